@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"expvar"
 	"os"
 
 	"github.com/labstack/echo/v5"
@@ -16,19 +17,42 @@ import (
 	"ichi-go/internal/applications/user"
 	"ichi-go/pkg/authenticator"
 	"ichi-go/pkg/logger"
+	"ichi-go/pkg/storage"
 )
 
 func SetupRestRoutes(injector do.Injector, e *echo.Echo, cfg *config.Config) {
-	openOpenAPIDocs(e, cfg)
 	if err := cfg.Auth().InitializeJWTKeys(); err != nil {
 		logger.Errorf("Failed to initialize JWT keys: %v", err)
 	}
+	if jwtCfg := cfg.Auth().JWT; jwtCfg != nil {
+		if store, err := do.Invoke[authenticator.RevocationStore](injector); err == nil && store != nil {
+			jwtCfg.RevocationStore = store
+		}
+	}
+	if basicCfg := cfg.Auth().BasicAuth; basicCfg != nil && basicCfg.UsersTable && basicCfg.Validator == nil {
+		basicCfg.Validator = auth.BasicAuthValidator(injector)
+	}
+	if apiKeyCfg := cfg.Auth().APIKey; apiKeyCfg != nil && apiKeyCfg.Database && apiKeyCfg.Resolver == nil {
+		apiKeyCfg.Resolver = auth.APIKeyResolver(injector)
+	}
 	appAuth := authenticator.New(cfg.Auth())
+	openOpenAPIDocs(e, cfg, appAuth)
+	openMetrics(e, cfg, appAuth)
+	if jwtCfg := cfg.Auth().JWT; jwtCfg != nil && jwtCfg.Enabled {
+		// Public keys for services verifying our tokens
+		e.GET("/.well-known/jwks.json", authenticator.JWKSHandler(jwtCfg))
+	}
+
+	if disk, err := do.Invoke[storage.Disk](injector); err == nil && disk != nil {
+		// Downloads behind temporary URLs of the local and memory disks
+		signer := do.MustInvoke[*storage.URLSigner](injector)
+		e.GET("/files/*", storage.DownloadHandler(disk, signer))
+	}
 
 	// Register application domains
+	rbacapp.Register(injector, cfg.App().Name, e, appAuth) // RBAC domain, first: user administration checks permissions through it
 	user.Register(injector, cfg.App().Name, e, appAuth)
 	auth.Register(injector, cfg.App().Name, e, appAuth)
-	rbacapp.Register(injector, cfg.App().Name, e, appAuth)         // RBAC domain
 	notificationapp.Register(injector, cfg.App().Name, e, appAuth) // Notification domain
 	healthapp.Register(injector, cfg.App().Name, e, cfg)
 }
@@ -48,7 +72,32 @@ func generateRouteList(e *echo.Echo) {
 	}
 }
 
-func openOpenAPIDocs(e *echo.Echo, cfg *config.Config) {
-	e.GET("/docs/*", echoSwagger.WrapHandler)
+// internalToolsAuth restricts internal tooling to internal users (Basic credentials or
+// a JWT). It returns nil when basic auth is disabled.
+func internalToolsAuth(cfg *config.Config, appAuth *authenticator.Authenticator) echo.MiddlewareFunc {
+	if basicCfg := cfg.Auth().BasicAuth; basicCfg == nil || !basicCfg.Enabled {
+		return nil
+	}
+	return appAuth.AuthenticateMiddleware(authenticator.WithMethods(authenticator.MethodBasic, authenticator.MethodJWT))
+}
+
+// openOpenAPIDocs serves Swagger UI. With basic auth enabled it is restricted to
+// internal users; otherwise it stays public.
+func openOpenAPIDocs(e *echo.Echo, cfg *config.Config, appAuth *authenticator.Authenticator) {
+	if toolsAuth := internalToolsAuth(cfg, appAuth); toolsAuth != nil {
+		e.GET("/docs/*", echoSwagger.WrapHandler, toolsAuth)
+	} else {
+		e.GET("/docs/*", echoSwagger.WrapHandler)
+	}
 	logger.Infof("Swagger UI available at http://localhost:%d/docs/index.html", cfg.Http().Port)
 }
+
+// openMetrics serves runtime metrics (expvar) to internal users. Without basic auth
+// there is no way to restrict it, so it is not served.
+func openMetrics(e *echo.Echo, cfg *config.Config, appAuth *authenticator.Authenticator) {
+	toolsAuth := internalToolsAuth(cfg, appAuth)
+	if toolsAuth == nil {
+		return
+	}
+	e.GET("/metrics", echo.WrapHandler(expvar.Handler()), toolsAuth)
+}
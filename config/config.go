@@ -7,7 +7,16 @@ import (
 	"ichi-go/internal/infra/queue"
 	"ichi-go/pkg/authenticator"
 	httpConfig "ichi-go/pkg/http"
+	"ichi-go/pkg/notification/audience"
+	"ichi-go/pkg/notification/digest"
+	"ichi-go/pkg/notification/email"
+	"ichi-go/pkg/notification/preference"
+	"ichi-go/pkg/notification/realtime"
+	"ichi-go/pkg/notification/sms"
+	notiftemplate "ichi-go/pkg/notification/template"
+	"ichi-go/pkg/notification/webhook"
 	"ichi-go/pkg/rbac"
+	"ichi-go/pkg/storage"
 	"ichi-go/pkg/validator"
 	"ichi-go/pkg/versioning"
 	"os"
@@ -39,6 +48,7 @@ type Schema struct {
 	Validator       validator.Config
 	Versioning      versioning.Config
 	RBAC            rbac.Config
+	Storage         storage.Config
 }
 
 type Config struct {
@@ -193,6 +203,11 @@ func (c *Config) RBAC() *rbac.Config {
 	return &c.schema.RBAC
 }
 
+func (c *Config) Storage() *storage.Config {
+	c.ensureLoaded()
+	return &c.schema.Storage
+}
+
 func (c *Config) ensureLoaded() {
 	if c == nil {
 		panic("config receiver is nil")
@@ -243,6 +258,15 @@ func setDefault() {
 	queue.SetDefault()
 	authenticator.SetDefault()
 	rbac.SetDefault()
+	storage.SetDefault()
+	email.SetDefault()
+	sms.SetDefault()
+	webhook.SetDefault()
+	realtime.SetDefault()
+	preference.SetDefault()
+	audience.SetDefault()
+	notiftemplate.SetDefault()
+	digest.SetDefault()
 }
 
 func SetDebugMode(_ *echo.Echo, debug bool) {
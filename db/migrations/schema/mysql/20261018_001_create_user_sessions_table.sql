@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- user_sessions
+-- One row per login session (refresh-token family).
+-- refresh_jti is rotated on every refresh; a refresh token with a stale jti is a replay
+-- and revokes the whole session.
+CREATE TABLE IF NOT EXISTS `user_sessions` (
+    `id`             BIGINT          NOT NULL AUTO_INCREMENT,
+    `created_at`     DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`     DATETIME                 DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    `user_id`        BIGINT          NOT NULL,
+    `family_id`      VARCHAR(36)     NOT NULL COMMENT 'Carried in the sid claim of every token in the family',
+    `refresh_jti`    VARCHAR(36)     NOT NULL COMMENT 'jti of the only refresh token currently accepted',
+    `access_jti`     VARCHAR(36)     NOT NULL COMMENT 'jti of the latest access token, denylisted when the session is revoked',
+    `device`         VARCHAR(100)             DEFAULT NULL,
+    `ip_address`     VARCHAR(45)              DEFAULT NULL,
+    `user_agent`     VARCHAR(500)             DEFAULT NULL,
+    `last_used_at`   DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `expires_at`     DATETIME        NOT NULL,
+    `revoked_at`     DATETIME                 DEFAULT NULL,
+    `revoked_reason` VARCHAR(50)              DEFAULT NULL COMMENT 'logout | logout_all | user_revoked | reuse_detected',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_session_family` (`family_id`),
+    INDEX `idx_session_user` (`user_id`, `revoked_at`, `expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Login sessions / refresh-token families';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `user_sessions`;
+-- +goose StatementEnd
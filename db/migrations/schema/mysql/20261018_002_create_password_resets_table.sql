@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- password_resets
+-- Single-use password reset tokens. Only the SHA-256 hash of the token is stored.
+CREATE TABLE IF NOT EXISTS `password_resets` (
+    `id`           BIGINT      NOT NULL AUTO_INCREMENT,
+    `created_at`   DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    `user_id`      BIGINT      NOT NULL,
+    `token_hash`   CHAR(64)    NOT NULL COMMENT 'hex SHA-256 of the emailed token',
+    `expires_at`   DATETIME    NOT NULL,
+    `used_at`      DATETIME             DEFAULT NULL COMMENT 'Set once the token is redeemed or superseded',
+    `requested_ip` VARCHAR(45)          DEFAULT NULL,
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_password_reset_token` (`token_hash`),
+    INDEX `idx_password_reset_user` (`user_id`, `used_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Single-use password reset tokens';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `password_resets`;
+-- +goose StatementEnd
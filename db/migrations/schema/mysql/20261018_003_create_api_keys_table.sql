@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- api_keys
+-- Per-client API keys. Only the SHA-256 hash of the key is stored.
+CREATE TABLE IF NOT EXISTS `api_keys` (
+    `id`            BIGINT       NOT NULL AUTO_INCREMENT,
+    `created_at`    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`    DATETIME              DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    `name`          VARCHAR(100) NOT NULL,
+    `prefix`        VARCHAR(32)  NOT NULL COMMENT 'Public lookup part of the key, e.g. ichi_0f3a9c2b7d1e',
+    `secret_hash`   CHAR(64)     NOT NULL COMMENT 'hex SHA-256 of the full key',
+    `owner_user_id` BIGINT       NOT NULL COMMENT 'User the key acts on behalf of',
+    `tenant_id`     VARCHAR(100)          DEFAULT NULL COMMENT 'Tenant the key is bound to; NULL = any',
+    `scopes`        JSON         NOT NULL COMMENT 'e.g. ["orders:view", "products:*"]',
+    `expires_at`    DATETIME              DEFAULT NULL,
+    `last_used_at`  DATETIME              DEFAULT NULL,
+    `revoked_at`    DATETIME              DEFAULT NULL,
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_api_key_prefix` (`prefix`),
+    INDEX `idx_api_key_owner` (`owner_user_id`, `revoked_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Per-client API keys';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `api_keys`;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- user_identities
+-- External OpenID Connect identities linked to local users.
+CREATE TABLE IF NOT EXISTS `user_identities` (
+    `id`            BIGINT       NOT NULL AUTO_INCREMENT,
+    `created_at`    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`    DATETIME              DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    `user_id`       BIGINT       NOT NULL,
+    `provider`      VARCHAR(50)  NOT NULL COMMENT 'Configured provider name, e.g. google',
+    `subject`       VARCHAR(255) NOT NULL COMMENT 'Stable "sub" claim of the provider',
+    `email`         VARCHAR(255)          DEFAULT NULL COMMENT 'Email reported at the last login',
+    `last_login_at` DATETIME              DEFAULT NULL,
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_user_identity_provider_subject` (`provider`, `subject`),
+    INDEX `idx_user_identity_user` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='External login identities';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `user_identities`;
+-- +goose StatementEnd
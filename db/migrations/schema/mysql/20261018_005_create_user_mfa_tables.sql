@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- user_mfa
+-- TOTP authenticators. The secret is encrypted with auth.mfa.encryption_key.
+CREATE TABLE IF NOT EXISTS `user_mfa` (
+    `id`               BIGINT       NOT NULL AUTO_INCREMENT,
+    `created_at`       DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`       DATETIME              DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    `user_id`          BIGINT       NOT NULL,
+    `secret_encrypted` VARCHAR(255) NOT NULL COMMENT 'base64 AES-GCM sealed TOTP secret',
+    `confirmed_at`     DATETIME              DEFAULT NULL COMMENT 'NULL = enrollment pending',
+    `last_used_step`   BIGINT       NOT NULL DEFAULT 0 COMMENT 'TOTP step of the last accepted code',
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_user_mfa_user` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='TOTP multi-factor authentication';
+
+-- user_mfa_recovery_codes
+-- Single-use MFA recovery codes. Only the SHA-256 hash is stored.
+CREATE TABLE IF NOT EXISTS `user_mfa_recovery_codes` (
+    `id`         BIGINT   NOT NULL AUTO_INCREMENT,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    `user_id`    BIGINT   NOT NULL,
+    `code_hash`  CHAR(64) NOT NULL,
+    `used_at`    DATETIME          DEFAULT NULL,
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_mfa_recovery_code` (`user_id`, `code_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='MFA recovery codes';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `user_mfa_recovery_codes`;
+DROP TABLE IF EXISTS `user_mfa`;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- user_data_requests
+-- GDPR data-subject requests (export / erasure), processed by queue jobs.
+CREATE TABLE IF NOT EXISTS `user_data_requests` (
+    `id`           BIGINT       NOT NULL AUTO_INCREMENT,
+    `created_at`   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`   DATETIME              DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    `user_id`      BIGINT       NOT NULL COMMENT 'data subject',
+    `requested_by` BIGINT       NOT NULL COMMENT 'user who filed the request',
+    `type`         VARCHAR(20)  NOT NULL COMMENT 'export | erasure',
+    `status`       VARCHAR(20)  NOT NULL DEFAULT 'pending' COMMENT 'pending | processing | completed | failed',
+    `file_path`    VARCHAR(255)          DEFAULT NULL COMMENT 'storage path of the export archive',
+    `error`        TEXT                  DEFAULT NULL,
+    `started_at`   DATETIME              DEFAULT NULL,
+    `completed_at` DATETIME              DEFAULT NULL,
+
+    PRIMARY KEY (`id`),
+    KEY `idx_user_data_requests_user` (`user_id`, `type`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='GDPR data export and erasure requests';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `user_data_requests`;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- user_devices
+-- Push notification targets: one row per FCM registration token.
+-- A token belongs to the user who registered it last; tokens FCM reports as
+-- unregistered are deleted by the push channel.
+CREATE TABLE IF NOT EXISTS `user_devices` (
+    `id`           BIGINT          NOT NULL AUTO_INCREMENT,
+    `created_at`   DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`   DATETIME                 DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    `user_id`      BIGINT          NOT NULL,
+    `platform`     VARCHAR(20)     NOT NULL COMMENT 'android | ios | web',
+    `app_version`  VARCHAR(50)              DEFAULT NULL,
+    `token`        VARCHAR(255)    NOT NULL COMMENT 'FCM registration token',
+    `topics`       JSON                     DEFAULT NULL COMMENT 'FCM topics the token is subscribed to: ["news"]',
+    `last_seen_at` DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_user_device_token` (`token`),
+    INDEX `idx_user_device_user` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Push notification device tokens';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `user_devices`;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification_logs
+    ADD COLUMN provider            VARCHAR(50)  DEFAULT NULL COMMENT 'Gateway that accepted the message, e.g. twilio' AFTER channel,
+    ADD COLUMN provider_message_id VARCHAR(100) DEFAULT NULL COMMENT 'Gateway message ID, matched by delivery receipts' AFTER provider,
+    ADD COLUMN delivered_at        DATETIME     DEFAULT NULL COMMENT 'Set by a delivered receipt' AFTER sent_at,
+    MODIFY COLUMN status           VARCHAR(20)  NOT NULL DEFAULT 'pending' COMMENT 'pending | sent | delivered | failed | skipped',
+    ADD INDEX idx_log_provider_message (provider, provider_message_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notification_logs
+    DROP INDEX idx_log_provider_message,
+    MODIFY COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending | sent | failed | skipped',
+    DROP COLUMN delivered_at,
+    DROP COLUMN provider_message_id,
+    DROP COLUMN provider;
+-- +goose StatementEnd
@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- webhook_endpoints
+-- URLs tenants registered to receive notification events sent on the webhook channel.
+-- Endpoints failing too many attempts in a row are disabled automatically.
+CREATE TABLE IF NOT EXISTS `webhook_endpoints` (
+    `id`                   BIGINT          NOT NULL AUTO_INCREMENT,
+    `created_at`           DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`           DATETIME                 DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    `tenant_id`            VARCHAR(100)    NOT NULL,
+    `created_by`           BIGINT                   DEFAULT NULL COMMENT 'User who registered the endpoint',
+    `url`                  VARCHAR(2048)   NOT NULL,
+    `description`          VARCHAR(255)             DEFAULT NULL,
+    `secret`               VARCHAR(100)    NOT NULL COMMENT 'HMAC-SHA256 signing secret',
+    `events`               JSON            NOT NULL COMMENT 'Event slug filters: ["order.shipped", "order.*", "*"]',
+    `is_active`            BOOLEAN         NOT NULL DEFAULT TRUE,
+    `consecutive_failures` INT             NOT NULL DEFAULT 0 COMMENT 'Failed attempts since the last success',
+    `disabled_at`          DATETIME                 DEFAULT NULL,
+    `disabled_reason`      VARCHAR(255)             DEFAULT NULL,
+    `last_success_at`      DATETIME                 DEFAULT NULL,
+    `last_failure_at`      DATETIME                 DEFAULT NULL,
+
+    PRIMARY KEY (`id`),
+    INDEX `idx_webhook_endpoint_tenant` (`tenant_id`, `is_active`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Outbound webhook subscriptions of tenants';
+
+
+-- webhook_deliveries
+-- One row per delivery attempt, with snapshots of the request sent and the response received.
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+    `id`               BIGINT          NOT NULL AUTO_INCREMENT,
+    `created_at`       DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    `endpoint_id`      BIGINT          NOT NULL COMMENT 'FK → webhook_endpoints.id',
+    `event_id`         VARCHAR(100)    NOT NULL,
+    `event_type`       VARCHAR(100)    NOT NULL,
+    `attempt`          INT             NOT NULL DEFAULT 1,
+    `redelivery_of`    BIGINT                   DEFAULT NULL COMMENT 'Delivery this one was manually redelivered from',
+    `status`           VARCHAR(20)     NOT NULL COMMENT 'succeeded | retrying | failed',
+    `request_url`      VARCHAR(2048)   NOT NULL,
+    `request_headers`  JSON                     DEFAULT NULL,
+    `request_body`     MEDIUMTEXT      NOT NULL,
+    `response_status`  INT                      DEFAULT NULL COMMENT 'NULL when no response was received',
+    `response_headers` JSON                     DEFAULT NULL,
+    `response_body`    TEXT                     DEFAULT NULL COMMENT 'Truncated',
+    `duration_ms`      BIGINT          NOT NULL DEFAULT 0,
+    `error`            TEXT                     DEFAULT NULL,
+    `next_retry_at`    DATETIME                 DEFAULT NULL,
+
+    PRIMARY KEY (`id`),
+    INDEX `idx_webhook_delivery_endpoint` (`endpoint_id`, `created_at`),
+    INDEX `idx_webhook_delivery_event` (`event_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Outbound webhook delivery attempts';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `webhook_deliveries`;
+DROP TABLE IF EXISTS `webhook_endpoints`;
+-- +goose StatementEnd
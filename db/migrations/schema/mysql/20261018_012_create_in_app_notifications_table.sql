@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- in_app_notifications
+-- The notification inbox of each user, written by the in_app channel.
+-- One row per user and event; archived rows leave the inbox and the unread count.
+CREATE TABLE IF NOT EXISTS `in_app_notifications` (
+    `id`          BIGINT          NOT NULL AUTO_INCREMENT,
+    `created_at`  DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    `user_id`     BIGINT          NOT NULL,
+    `event_id`    VARCHAR(100)    NOT NULL COMMENT 'NotificationEvent.EventID; unique per user',
+    `event_type`  VARCHAR(100)    NOT NULL,
+    `title`       VARCHAR(255)    NOT NULL,
+    `body`        TEXT            NOT NULL,
+    `action_url`  VARCHAR(2048)            DEFAULT NULL COMMENT 'Opened when the notification is clicked',
+    `data`        JSON                     DEFAULT NULL COMMENT 'Event data without internal keys',
+    `read_at`     DATETIME                 DEFAULT NULL,
+    `archived_at` DATETIME                 DEFAULT NULL,
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_in_app_user_event` (`user_id`, `event_id`),
+    INDEX `idx_in_app_user_inbox` (`user_id`, `archived_at`, `id`),
+    INDEX `idx_in_app_user_unread` (`user_id`, `read_at`, `archived_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='In-app notification inbox';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `in_app_notifications`;
+-- +goose StatementEnd
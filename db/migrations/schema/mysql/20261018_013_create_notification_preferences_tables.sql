@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- notification_preferences
+-- Delivery settings of a user: quiet hours and the global marketing unsubscribe.
+-- Users without a row get every notification.
+CREATE TABLE IF NOT EXISTS `notification_preferences` (
+    `user_id`           BIGINT          NOT NULL,
+    `created_at`        DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`        DATETIME                 DEFAULT NULL,
+
+    `timezone`          VARCHAR(64)              DEFAULT NULL COMMENT 'IANA time zone of quiet hours',
+    `quiet_hours_start` VARCHAR(5)               DEFAULT NULL COMMENT 'HH:MM; after quiet_hours_end wraps midnight',
+    `quiet_hours_end`   VARCHAR(5)               DEFAULT NULL COMMENT 'HH:MM',
+    `unsubscribed_at`   DATETIME                 DEFAULT NULL COMMENT 'Set while unsubscribed from all marketing',
+
+    PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Notification delivery settings per user';
+
+-- notification_opt_outs
+-- One row per (user, event category, channel) the user turned off.
+CREATE TABLE IF NOT EXISTS `notification_opt_outs` (
+    `id`         BIGINT          NOT NULL AUTO_INCREMENT,
+    `created_at` DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    `user_id`    BIGINT          NOT NULL,
+    `category`   VARCHAR(50)     NOT NULL COMMENT 'Classification.Category of the event template',
+    `channel`    VARCHAR(20)     NOT NULL,
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_notification_opt_outs` (`user_id`, `category`, `channel`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Notification channels turned off per user and event category';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `notification_opt_outs`;
+DROP TABLE IF EXISTS `notification_preferences`;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `notification_template_overrides`
+    ADD COLUMN `revision` INT NOT NULL DEFAULT 1 COMMENT 'Latest revision in notification_template_override_versions' AFTER `is_active`;
+
+-- notification_template_override_versions
+-- Snapshot of an override after every change made through the template API; rollbacks copy one back.
+CREATE TABLE IF NOT EXISTS `notification_template_override_versions` (
+    `id`              BIGINT          NOT NULL AUTO_INCREMENT,
+    `created_at`      DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_by`      BIGINT          NOT NULL DEFAULT 0 COMMENT 'User who made the change',
+
+    `override_id`     BIGINT          NOT NULL COMMENT 'FK → notification_template_overrides.id',
+    `revision`        INT             NOT NULL COMMENT 'Sequential per override, from 1',
+    `action`          VARCHAR(20)     NOT NULL COMMENT 'created | updated | rolled_back',
+    `source_revision` INT                      DEFAULT NULL COMMENT 'Revision restored by a rollback',
+    `title_template`  VARCHAR(500)             DEFAULT NULL,
+    `body_template`   TEXT                     DEFAULT NULL,
+    `is_active`       TINYINT(1)      NOT NULL,
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_template_override_version` (`override_id`, `revision`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Version history of notification template overrides';
+
+-- Overrides written before the history existed start it as revision 1.
+INSERT INTO `notification_template_override_versions`
+    (`created_at`, `created_by`, `override_id`, `revision`, `action`, `title_template`, `body_template`, `is_active`)
+SELECT `created_at`, `created_by`, `id`, 1, 'created', `title_template`, `body_template`, `is_active`
+FROM `notification_template_overrides`
+WHERE `deleted_at` IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `notification_template_override_versions`;
+ALTER TABLE `notification_template_overrides`
+    DROP COLUMN `revision`;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `notification_campaigns`
+    ADD COLUMN `cancelled_at` DATETIME DEFAULT NULL COMMENT 'Set when a scheduled campaign is cancelled before delivery' AFTER `published_at`,
+    MODIFY COLUMN `status`    VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending | processing | published | failed | cancelled';
+
+ALTER TABLE `notification_logs`
+    ADD INDEX `idx_log_campaign_status` (`campaign_id`, `status`, `channel`),
+    DROP INDEX `idx_log_campaign`,
+    MODIFY COLUMN `status` VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending | sent | delivered | failed | skipped | retried';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `notification_logs`
+    ADD INDEX `idx_log_campaign` (`campaign_id`),
+    DROP INDEX `idx_log_campaign_status`,
+    MODIFY COLUMN `status` VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending | sent | delivered | failed | skipped';
+
+ALTER TABLE `notification_campaigns`
+    DROP COLUMN `cancelled_at`,
+    MODIFY COLUMN `status` VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending | processing | published | failed';
+-- +goose StatementEnd
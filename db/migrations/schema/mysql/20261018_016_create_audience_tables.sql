@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `notification_campaigns`
+    ADD COLUMN `segment` JSON DEFAULT NULL COMMENT 'Audience of a blast: roles, tenant_id, created range, attributes; NULL reaches every user' AFTER `user_exclude_ids`;
+
+-- user_attributes
+-- Custom key/value attributes of a user that blast segments can match on.
+CREATE TABLE IF NOT EXISTS `user_attributes` (
+    `id`         BIGINT          NOT NULL AUTO_INCREMENT,
+    `created_at` DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` DATETIME                 DEFAULT NULL,
+
+    `user_id`    BIGINT          NOT NULL,
+    `name`       VARCHAR(64)     NOT NULL,
+    `value`      VARCHAR(255)    NOT NULL,
+
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_user_attributes` (`user_id`, `name`),
+    KEY `idx_user_attributes_value` (`name`, `value`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Custom attributes of users, matched by blast segments';
+
+-- notification_blast_checkpoints
+-- Progress of the fan-out of one blast message; a redelivered blast resumes after last_user_id.
+CREATE TABLE IF NOT EXISTS `notification_blast_checkpoints` (
+    `event_id`     VARCHAR(191)    NOT NULL COMMENT 'EventID of the blast message',
+    `created_at`   DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`   DATETIME                 DEFAULT NULL,
+
+    `campaign_id`  BIGINT          NOT NULL DEFAULT 0 COMMENT '0 for blasts sent outside a campaign',
+    `last_user_id` BIGINT          NOT NULL DEFAULT 0 COMMENT 'Highest user ID read so far',
+    `published`    BIGINT          NOT NULL DEFAULT 0 COMMENT 'Per-user messages published so far',
+    `completed_at` DATETIME                 DEFAULT NULL COMMENT 'Set once every user of the audience was published',
+
+    PRIMARY KEY (`event_id`),
+    KEY `idx_blast_checkpoints_campaign` (`campaign_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+  COMMENT='Fan-out progress of blast notifications';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `notification_blast_checkpoints`;
+DROP TABLE IF EXISTS `user_attributes`;
+
+ALTER TABLE `notification_campaigns`
+    DROP COLUMN `segment`;
+-- +goose StatementEnd
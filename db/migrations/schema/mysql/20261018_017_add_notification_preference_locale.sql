@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `notification_preferences`
+    ADD COLUMN `locale` VARCHAR(35) DEFAULT NULL COMMENT 'Canonical BCP-47 tag notifications are rendered in; NULL uses the event locale' AFTER `quiet_hours_end`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `notification_preferences`
+    DROP COLUMN `locale`;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id             BIGSERIAL       NOT NULL PRIMARY KEY,
+    created_at     TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ              DEFAULT NOW(),
+
+    user_id        BIGINT          NOT NULL,
+    family_id      VARCHAR(36)     NOT NULL,
+    refresh_jti    VARCHAR(36)     NOT NULL,
+    access_jti     VARCHAR(36)     NOT NULL,
+    device         VARCHAR(100)             DEFAULT NULL,
+    ip_address     VARCHAR(45)              DEFAULT NULL,
+    user_agent     VARCHAR(500)             DEFAULT NULL,
+    last_used_at   TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    expires_at     TIMESTAMPTZ     NOT NULL,
+    revoked_at     TIMESTAMPTZ              DEFAULT NULL,
+    revoked_reason VARCHAR(50)              DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uq_session_family ON user_sessions (family_id);
+CREATE INDEX idx_session_user ON user_sessions (user_id, revoked_at, expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS password_resets (
+    id           BIGSERIAL   NOT NULL PRIMARY KEY,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    user_id      BIGINT      NOT NULL,
+    token_hash   CHAR(64)    NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    used_at      TIMESTAMPTZ          DEFAULT NULL,
+    requested_ip VARCHAR(45)          DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uq_password_reset_token ON password_resets (token_hash);
+CREATE INDEX idx_password_reset_user ON password_resets (user_id, used_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS password_resets;
+-- +goose StatementEnd
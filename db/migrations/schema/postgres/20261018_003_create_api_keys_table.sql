@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id            BIGSERIAL    NOT NULL PRIMARY KEY,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ           DEFAULT NOW(),
+
+    name          VARCHAR(100) NOT NULL,
+    prefix        VARCHAR(32)  NOT NULL,
+    secret_hash   CHAR(64)     NOT NULL,
+    owner_user_id BIGINT       NOT NULL,
+    tenant_id     VARCHAR(100)          DEFAULT NULL,
+    scopes        JSONB        NOT NULL,
+    expires_at    TIMESTAMPTZ           DEFAULT NULL,
+    last_used_at  TIMESTAMPTZ           DEFAULT NULL,
+    revoked_at    TIMESTAMPTZ           DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uq_api_key_prefix ON api_keys (prefix);
+CREATE INDEX idx_api_key_owner ON api_keys (owner_user_id, revoked_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id            BIGSERIAL    NOT NULL PRIMARY KEY,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ           DEFAULT NOW(),
+
+    user_id       BIGINT       NOT NULL,
+    provider      VARCHAR(50)  NOT NULL,
+    subject       VARCHAR(255) NOT NULL,
+    email         VARCHAR(255)          DEFAULT NULL,
+    last_login_at TIMESTAMPTZ           DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uq_user_identity_provider_subject ON user_identities (provider, subject);
+CREATE INDEX idx_user_identity_user ON user_identities (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
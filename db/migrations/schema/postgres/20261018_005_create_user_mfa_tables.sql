@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS user_mfa (
+    id               BIGSERIAL    NOT NULL PRIMARY KEY,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ           DEFAULT NOW(),
+
+    user_id          BIGINT       NOT NULL,
+    secret_encrypted VARCHAR(255) NOT NULL,
+    confirmed_at     TIMESTAMPTZ           DEFAULT NULL,
+    last_used_step   BIGINT       NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX uq_user_mfa_user ON user_mfa (user_id);
+
+CREATE TABLE IF NOT EXISTS user_mfa_recovery_codes (
+    id         BIGSERIAL   NOT NULL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    user_id    BIGINT      NOT NULL,
+    code_hash  CHAR(64)    NOT NULL,
+    used_at    TIMESTAMPTZ          DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uq_mfa_recovery_code ON user_mfa_recovery_codes (user_id, code_hash);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
+-- +goose StatementEnd
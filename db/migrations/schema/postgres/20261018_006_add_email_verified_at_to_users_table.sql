@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Accounts created before email verification existed are treated as verified
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN email_verified_at;
+-- +goose StatementEnd
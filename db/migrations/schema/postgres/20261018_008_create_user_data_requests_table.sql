@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS user_data_requests (
+    id           BIGSERIAL    NOT NULL PRIMARY KEY,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ           DEFAULT NOW(),
+
+    user_id      BIGINT       NOT NULL,
+    requested_by BIGINT       NOT NULL,
+    type         VARCHAR(20)  NOT NULL,
+    status       VARCHAR(20)  NOT NULL DEFAULT 'pending',
+    file_path    VARCHAR(255)          DEFAULT NULL,
+    error        TEXT                  DEFAULT NULL,
+    started_at   TIMESTAMPTZ           DEFAULT NULL,
+    completed_at TIMESTAMPTZ           DEFAULT NULL
+);
+
+CREATE INDEX idx_user_data_requests_user ON user_data_requests (user_id, type, status);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_data_requests;
+-- +goose StatementEnd
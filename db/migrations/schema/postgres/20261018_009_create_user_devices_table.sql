@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS user_devices (
+    id           BIGSERIAL       NOT NULL PRIMARY KEY,
+    created_at   TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ              DEFAULT NOW(),
+
+    user_id      BIGINT          NOT NULL,
+    platform     VARCHAR(20)     NOT NULL,
+    app_version  VARCHAR(50)              DEFAULT NULL,
+    token        VARCHAR(255)    NOT NULL,
+    topics       JSONB                    DEFAULT NULL,
+    last_seen_at TIMESTAMPTZ     NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX uq_user_device_token ON user_devices (token);
+CREATE INDEX idx_user_device_user ON user_devices (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_devices;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification_logs
+    ADD COLUMN provider            VARCHAR(50)  DEFAULT NULL,
+    ADD COLUMN provider_message_id VARCHAR(100) DEFAULT NULL,
+    ADD COLUMN delivered_at        TIMESTAMPTZ  DEFAULT NULL;
+
+CREATE INDEX idx_log_provider_message ON notification_logs (provider, provider_message_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_log_provider_message;
+ALTER TABLE notification_logs
+    DROP COLUMN delivered_at,
+    DROP COLUMN provider_message_id,
+    DROP COLUMN provider;
+-- +goose StatementEnd
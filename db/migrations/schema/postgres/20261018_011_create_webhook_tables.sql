@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id                   BIGSERIAL       NOT NULL PRIMARY KEY,
+    created_at           TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ              DEFAULT NOW(),
+
+    tenant_id            VARCHAR(100)    NOT NULL,
+    created_by           BIGINT                   DEFAULT NULL,
+    url                  VARCHAR(2048)   NOT NULL,
+    description          VARCHAR(255)             DEFAULT NULL,
+    secret               VARCHAR(100)    NOT NULL,
+    events               JSONB           NOT NULL,
+    is_active            BOOLEAN         NOT NULL DEFAULT TRUE,
+    consecutive_failures INT             NOT NULL DEFAULT 0,
+    disabled_at          TIMESTAMPTZ              DEFAULT NULL,
+    disabled_reason      VARCHAR(255)             DEFAULT NULL,
+    last_success_at      TIMESTAMPTZ              DEFAULT NULL,
+    last_failure_at      TIMESTAMPTZ              DEFAULT NULL
+);
+
+CREATE INDEX idx_webhook_endpoint_tenant ON webhook_endpoints (tenant_id, is_active);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id               BIGSERIAL       NOT NULL PRIMARY KEY,
+    created_at       TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+
+    endpoint_id      BIGINT          NOT NULL,
+    event_id         VARCHAR(100)    NOT NULL,
+    event_type       VARCHAR(100)    NOT NULL,
+    attempt          INT             NOT NULL DEFAULT 1,
+    redelivery_of    BIGINT                   DEFAULT NULL,
+    status           VARCHAR(20)     NOT NULL,
+    request_url      VARCHAR(2048)   NOT NULL,
+    request_headers  JSONB                    DEFAULT NULL,
+    request_body     TEXT            NOT NULL,
+    response_status  INT                      DEFAULT NULL,
+    response_headers JSONB                    DEFAULT NULL,
+    response_body    TEXT                     DEFAULT NULL,
+    duration_ms      BIGINT          NOT NULL DEFAULT 0,
+    error            TEXT                     DEFAULT NULL,
+    next_retry_at    TIMESTAMPTZ              DEFAULT NULL
+);
+
+CREATE INDEX idx_webhook_delivery_endpoint ON webhook_deliveries (endpoint_id, created_at);
+CREATE INDEX idx_webhook_delivery_event ON webhook_deliveries (event_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
+-- +goose StatementEnd
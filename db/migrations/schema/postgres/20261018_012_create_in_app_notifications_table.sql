@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS in_app_notifications (
+    id          BIGSERIAL       NOT NULL PRIMARY KEY,
+    created_at  TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+
+    user_id     BIGINT          NOT NULL,
+    event_id    VARCHAR(100)    NOT NULL,
+    event_type  VARCHAR(100)    NOT NULL,
+    title       VARCHAR(255)    NOT NULL,
+    body        TEXT            NOT NULL,
+    action_url  VARCHAR(2048)            DEFAULT NULL,
+    data        JSONB                    DEFAULT NULL,
+    read_at     TIMESTAMPTZ              DEFAULT NULL,
+    archived_at TIMESTAMPTZ              DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uq_in_app_user_event ON in_app_notifications (user_id, event_id);
+CREATE INDEX idx_in_app_user_inbox ON in_app_notifications (user_id, archived_at, id);
+CREATE INDEX idx_in_app_user_unread ON in_app_notifications (user_id, read_at, archived_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS in_app_notifications;
+-- +goose StatementEnd
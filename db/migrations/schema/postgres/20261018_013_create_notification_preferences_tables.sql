@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id           BIGINT          NOT NULL PRIMARY KEY,
+    created_at        TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ              DEFAULT NULL,
+
+    timezone          VARCHAR(64)              DEFAULT NULL,
+    quiet_hours_start VARCHAR(5)               DEFAULT NULL,
+    quiet_hours_end   VARCHAR(5)               DEFAULT NULL,
+    unsubscribed_at   TIMESTAMPTZ              DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS notification_opt_outs (
+    id          BIGSERIAL       NOT NULL PRIMARY KEY,
+    created_at  TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+
+    user_id     BIGINT          NOT NULL,
+    category    VARCHAR(50)     NOT NULL,
+    channel     VARCHAR(20)     NOT NULL
+);
+
+CREATE UNIQUE INDEX uq_notification_opt_outs ON notification_opt_outs (user_id, category, channel);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_opt_outs;
+DROP TABLE IF EXISTS notification_preferences;
+-- +goose StatementEnd
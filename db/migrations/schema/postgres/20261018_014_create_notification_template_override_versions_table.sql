@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification_template_overrides
+    ADD COLUMN revision INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS notification_template_override_versions (
+    id              BIGSERIAL       NOT NULL PRIMARY KEY,
+    created_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    created_by      BIGINT          NOT NULL DEFAULT 0,
+
+    override_id     BIGINT          NOT NULL,
+    revision        INT             NOT NULL,
+    action          VARCHAR(20)     NOT NULL,
+    source_revision INT                      DEFAULT NULL,
+    title_template  VARCHAR(500)             DEFAULT NULL,
+    body_template   TEXT                     DEFAULT NULL,
+    is_active       BOOLEAN         NOT NULL
+);
+
+CREATE UNIQUE INDEX uq_template_override_version ON notification_template_override_versions (override_id, revision);
+
+-- Overrides written before the history existed start it as revision 1.
+INSERT INTO notification_template_override_versions
+    (created_at, created_by, override_id, revision, action, title_template, body_template, is_active)
+SELECT created_at, created_by, id, 1, 'created', title_template, body_template, is_active
+FROM notification_template_overrides
+WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_template_override_versions;
+ALTER TABLE notification_template_overrides
+    DROP COLUMN revision;
+-- +goose StatementEnd
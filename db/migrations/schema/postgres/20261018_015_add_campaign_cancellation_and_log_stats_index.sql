@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification_campaigns
+    ADD COLUMN cancelled_at TIMESTAMPTZ DEFAULT NULL;
+
+CREATE INDEX idx_log_campaign_status ON notification_logs (campaign_id, status, channel);
+DROP INDEX IF EXISTS idx_log_campaign;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE INDEX idx_log_campaign ON notification_logs (campaign_id);
+DROP INDEX IF EXISTS idx_log_campaign_status;
+
+ALTER TABLE notification_campaigns
+    DROP COLUMN cancelled_at;
+-- +goose StatementEnd
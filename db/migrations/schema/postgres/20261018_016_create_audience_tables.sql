@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification_campaigns
+    ADD COLUMN segment JSONB DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS user_attributes (
+    id          BIGSERIAL       NOT NULL PRIMARY KEY,
+    created_at  TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ              DEFAULT NULL,
+
+    user_id     BIGINT          NOT NULL,
+    name        VARCHAR(64)     NOT NULL,
+    value       VARCHAR(255)    NOT NULL
+);
+
+CREATE UNIQUE INDEX uq_user_attributes ON user_attributes (user_id, name);
+CREATE INDEX idx_user_attributes_value ON user_attributes (name, value);
+
+CREATE TABLE IF NOT EXISTS notification_blast_checkpoints (
+    event_id      VARCHAR(191)    NOT NULL PRIMARY KEY,
+    created_at    TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ              DEFAULT NULL,
+
+    campaign_id   BIGINT          NOT NULL DEFAULT 0,
+    last_user_id  BIGINT          NOT NULL DEFAULT 0,
+    published     BIGINT          NOT NULL DEFAULT 0,
+    completed_at  TIMESTAMPTZ              DEFAULT NULL
+);
+
+CREATE INDEX idx_blast_checkpoints_campaign ON notification_blast_checkpoints (campaign_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_blast_checkpoints;
+DROP TABLE IF EXISTS user_attributes;
+
+ALTER TABLE notification_campaigns
+    DROP COLUMN segment;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notification_preferences
+    ADD COLUMN locale VARCHAR(35) DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notification_preferences
+    DROP COLUMN locale;
+-- +goose StatementEnd
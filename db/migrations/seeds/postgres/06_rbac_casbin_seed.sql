@@ -61,7 +61,12 @@ INSERT INTO rbac_permissions (id, name, slug, description, module, resource, act
 (143, 'View Policies', 'rbac.policies.view', 'Can view Casbin policies', 'rbac', 'policies', 'view', NOW()),
 (144, 'Manage Policies', 'rbac.policies.manage', 'Can create/edit/delete policies', 'rbac', 'policies', 'manage', NOW()),
 (145, 'View Audit Log', 'rbac.audit.view', 'Can view RBAC audit log', 'rbac', 'audit', 'view', NOW()),
-(146, 'Export Audit Log', 'rbac.audit.export', 'Can export audit logs', 'rbac', 'audit', 'export', NOW())
+(146, 'Export Audit Log', 'rbac.audit.export', 'Can export audit logs', 'rbac', 'audit', 'export', NOW()),
+
+-- Notification Permissions (161-180)
+(161, 'Manage Webhooks', 'notifications.webhooks.manage', 'Can register and manage outbound webhook endpoints', 'notifications', 'webhooks', 'manage', NOW()),
+(162, 'Manage Templates', 'notifications.templates.manage', 'Can override the copy of notification templates for all tenants', 'notifications', 'templates', 'manage', NOW()),
+(163, 'Manage Campaigns', 'notifications.campaigns.manage', 'Can list, cancel and retry notification campaigns of all tenants', 'notifications', 'campaigns', 'manage', NOW())
 ON CONFLICT (id) DO NOTHING;
 
 SELECT setval(pg_get_serial_sequence('rbac_permissions', 'id'), COALESCE(MAX(id), 0), true) FROM rbac_permissions;
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"context"
+
+	authService "ichi-go/internal/applications/auth/service"
+	"ichi-go/pkg/authenticator"
+
+	"github.com/samber/do/v2"
+)
+
+// APIKeyResolver validates "ichi_" API keys against the api_keys table. Like
+// BasicAuthValidator, the service is resolved on first use.
+func APIKeyResolver(injector do.Injector) authenticator.APIKeyResolver {
+	return func(ctx context.Context, apiKey string) (*authenticator.APIKeyIdentity, error) {
+		svc, err := do.Invoke[*authService.APIKeyServiceImpl](injector)
+		if err != nil {
+			return nil, err
+		}
+		return svc.ResolveAPIKey(ctx, apiKey)
+	}
+}
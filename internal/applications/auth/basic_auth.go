@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+
+	authService "ichi-go/internal/applications/auth/service"
+	"ichi-go/pkg/authenticator"
+
+	"github.com/samber/do/v2"
+)
+
+// BasicAuthValidator validates HTTP Basic credentials (email + password) against the
+// users table, with the same lockout and email verification rules as /auth/login.
+// Users who must pass MFA are refused. The auth service is
+// resolved on first use because the authenticator is built before domains register.
+func BasicAuthValidator(injector do.Injector) authenticator.BasicAuthValidator {
+	return func(ctx context.Context, username, password string) (string, error) {
+		svc, err := do.Invoke[*authService.ServiceImpl](injector)
+		if err != nil {
+			return "", err
+		}
+		user, err := svc.AuthenticateBasic(ctx, username, password)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(user.ID, 10), nil
+	}
+}
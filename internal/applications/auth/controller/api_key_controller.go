@@ -0,0 +1,234 @@
+package auth
+
+import (
+	authDto "ichi-go/internal/applications/auth/dto"
+	authService "ichi-go/internal/applications/auth/service"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+)
+
+type APIKeyController struct {
+	service *authService.APIKeyServiceImpl
+}
+
+func NewAPIKeyController(service *authService.APIKeyServiceImpl) *APIKeyController {
+	return &APIKeyController{service: service}
+}
+
+// Create godoc
+//
+//	@Summary		Create an API key
+//	@Description	Issue an API key for a client integration. The full key is returned only once.
+//	@Tags			API Keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		authDto.CreateAPIKeyRequest									true	"API key details"
+//	@Success		201		{object}	response.SuccessResponse{data=authDto.APIKeySecretResponse}	"API key created"
+//	@Failure		400		{object}	response.ErrorResponse										"Invalid request or validation error"
+//	@Failure		401		{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse										"Platform admin permission required for owner_user_id"
+//	@Failure		500		{object}	response.ErrorResponse										"Internal server error"
+//	@Router			/202601/auth/api-keys [post]
+func (c *APIKeyController) Create(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req authDto.CreateAPIKeyRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Create API key request validation failed: %v", err)
+		return err
+	}
+
+	key, err := c.service.CreateAPIKey(eCtx.Request().Context(), *authCtx, req)
+	if err != nil {
+		logger.Errorf("Failed to create API key: %v", err)
+		return err
+	}
+
+	return response.Created(eCtx, key)
+}
+
+// List godoc
+//
+//	@Summary		List API keys
+//	@Description	List the current user's API keys that are not revoked
+//	@Tags			API Keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]authDto.APIKeyResponse}	"API keys"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		500	{object}	response.ErrorResponse									"Internal server error"
+//	@Router			/202601/auth/api-keys [get]
+func (c *APIKeyController) List(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	keys, err := c.service.ListAPIKeys(eCtx.Request().Context(), *authCtx)
+	if err != nil {
+		logger.Errorf("Failed to list API keys: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, keys)
+}
+
+// Get godoc
+//
+//	@Summary		Get an API key
+//	@Description	Get one API key of the current user (any key for platform admins)
+//	@Tags			API Keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int													true	"API key ID"
+//	@Success		200	{object}	response.SuccessResponse{data=authDto.APIKeyResponse}	"API key"
+//	@Failure		400	{object}	response.ErrorResponse									"Invalid API key ID"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		404	{object}	response.ErrorResponse									"API key not found"
+//	@Router			/202601/auth/api-keys/{id} [get]
+func (c *APIKeyController) Get(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid API key ID"))
+	}
+
+	key, err := c.service.GetAPIKey(eCtx.Request().Context(), *authCtx, id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(eCtx, key)
+}
+
+// Update godoc
+//
+//	@Summary		Update an API key
+//	@Description	Change the name, scopes or expiry of an API key; omitted fields are kept
+//	@Tags			API Keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int														true	"API key ID"
+//	@Param			request	body		authDto.UpdateAPIKeyRequest								true	"Changes"
+//	@Success		200		{object}	response.SuccessResponse{data=authDto.APIKeyResponse}	"API key updated"
+//	@Failure		400		{object}	response.ErrorResponse									"Invalid request or validation error"
+//	@Failure		401		{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		404		{object}	response.ErrorResponse									"API key not found"
+//	@Router			/202601/auth/api-keys/{id} [put]
+func (c *APIKeyController) Update(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid API key ID"))
+	}
+
+	var req authDto.UpdateAPIKeyRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Update API key request validation failed: %v", err)
+		return err
+	}
+
+	key, err := c.service.UpdateAPIKey(eCtx.Request().Context(), *authCtx, id, req)
+	if err != nil {
+		logger.Errorf("Failed to update API key %d: %v", id, err)
+		return err
+	}
+
+	return response.Success(eCtx, key)
+}
+
+// Rotate godoc
+//
+//	@Summary		Rotate an API key
+//	@Description	Replace the secret of an API key, keeping its name and scopes. The old key stops working immediately; the new one is returned only once.
+//	@Tags			API Keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int															true	"API key ID"
+//	@Success		200	{object}	response.SuccessResponse{data=authDto.APIKeySecretResponse}	"API key rotated"
+//	@Failure		400	{object}	response.ErrorResponse										"Invalid API key ID"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		404	{object}	response.ErrorResponse										"API key not found"
+//	@Router			/202601/auth/api-keys/{id}/rotate [post]
+func (c *APIKeyController) Rotate(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid API key ID"))
+	}
+
+	key, err := c.service.RotateAPIKey(eCtx.Request().Context(), *authCtx, id)
+	if err != nil {
+		logger.Errorf("Failed to rotate API key %d: %v", id, err)
+		return err
+	}
+
+	return response.Success(eCtx, key)
+}
+
+// Revoke godoc
+//
+//	@Summary		Revoke an API key
+//	@Description	Permanently disable an API key; requests using it are rejected immediately
+//	@Tags			API Keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int													true	"API key ID"
+//	@Success		200	{object}	response.SuccessResponse{data=map[string]string}	"API key revoked"
+//	@Failure		400	{object}	response.ErrorResponse								"Invalid API key ID"
+//	@Failure		401	{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		404	{object}	response.ErrorResponse								"API key not found"
+//	@Router			/202601/auth/api-keys/{id} [delete]
+func (c *APIKeyController) Revoke(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid API key ID"))
+	}
+
+	if err := c.service.RevokeAPIKey(eCtx.Request().Context(), *authCtx, id); err != nil {
+		logger.Errorf("Failed to revoke API key %d: %v", id, err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "API key revoked",
+	})
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/versioning"
+
+	"github.com/labstack/echo/v5"
+)
+
+// RegisterRoutes registers the API key management routes. Keys are managed with a
+// user's JWT only; an API key cannot manage keys itself.
+func (c *APIKeyController) RegisterRoutes(serviceName string, e *echo.Echo, auth *authenticator.Authenticator) {
+	vr := versioning.NewVersionedRoute(serviceName, versioning.TwentySixJan, Domain)
+
+	group := vr.Group(e)
+	group.Use(auth.AuthenticateMiddleware())
+	group.GET("/api-keys", c.List)
+	group.POST("/api-keys", c.Create)
+	group.GET("/api-keys/:id", c.Get)
+	group.PUT("/api-keys/:id", c.Update)
+	group.DELETE("/api-keys/:id", c.Revoke)
+	group.POST("/api-keys/:id/rotate", c.Rotate)
+}
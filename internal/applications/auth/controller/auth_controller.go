@@ -8,6 +8,7 @@ import (
 	"ichi-go/pkg/utils/response"
 	appValidator "ichi-go/pkg/validator"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v5"
 )
@@ -120,21 +121,319 @@ func (c *AuthController) RefreshToken(eCtx *echo.Context) error {
 // Logout godoc
 //
 //	@Summary		User logout
-//	@Description	Invalidate current user session (client should discard tokens)
+//	@Description	Revoke the current access token and, if provided, its refresh token
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Success		200	{object}	response.SuccessResponse{data=map[string]string}	"Logged out successfully"
-//	@Failure		401	{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Param			request	body		authDto.LogoutRequest								false	"Refresh token to revoke"
+//	@Success		200		{object}	response.SuccessResponse{data=map[string]string}	"Logged out successfully"
+//	@Failure		401		{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		500		{object}	response.ErrorResponse								"Internal server error"
 //	@Router			/202601/auth/logout [post]
 func (c *AuthController) Logout(eCtx *echo.Context) error {
-	logger.Infof("User logged out")
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req authDto.LogoutRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Logout request validation failed: %v", err)
+		return err
+	}
+
+	if err := c.service.Logout(eCtx.Request().Context(), *authCtx, req); err != nil {
+		logger.Errorf("Logout failed: %v", err)
+		return err
+	}
+
 	return response.Success(eCtx, map[string]string{
 		"message": "Logged out successfully",
 	})
 }
 
+// LogoutAll godoc
+//
+//	@Summary		Logout from all sessions
+//	@Description	Revoke every access and refresh token issued to the current user
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=map[string]string}	"Logged out of all sessions"
+//	@Failure		401	{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		500	{object}	response.ErrorResponse								"Internal server error"
+//	@Router			/202601/auth/logout-all [post]
+func (c *AuthController) LogoutAll(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	if err := c.service.LogoutAll(eCtx.Request().Context(), *authCtx); err != nil {
+		logger.Errorf("Logout-all failed: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "Logged out of all sessions",
+	})
+}
+
+// ListSessions godoc
+//
+//	@Summary		List active sessions
+//	@Description	List the current user's active login sessions
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]authDto.SessionResponse}	"Active sessions"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		500	{object}	response.ErrorResponse										"Internal server error"
+//	@Router			/202601/auth/sessions [get]
+func (c *AuthController) ListSessions(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	sessions, err := c.service.ListSessions(eCtx.Request().Context(), *authCtx)
+	if err != nil {
+		logger.Errorf("Failed to list sessions: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, sessions)
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Revoke a session
+//	@Description	End one of the current user's sessions; its tokens stop working immediately
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int													true	"Session ID"
+//	@Success		200	{object}	response.SuccessResponse{data=map[string]string}	"Session revoked"
+//	@Failure		400	{object}	response.ErrorResponse								"Invalid session ID"
+//	@Failure		401	{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		404	{object}	response.ErrorResponse								"Session not found"
+//	@Router			/202601/auth/sessions/{id} [delete]
+func (c *AuthController) RevokeSession(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid session ID"))
+	}
+
+	if err := c.service.RevokeSession(eCtx.Request().Context(), *authCtx, id); err != nil {
+		logger.Errorf("Failed to revoke session %d: %v", id, err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "Session revoked",
+	})
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Change password
+//	@Description	Change the current user's password. All other sessions are signed out.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		authDto.ChangePasswordRequest						true	"Current and new password"
+//	@Success		200		{object}	response.SuccessResponse{data=map[string]string}	"Password changed"
+//	@Failure		400		{object}	response.ErrorResponse								"Invalid request or validation error"
+//	@Failure		401		{object}	response.ErrorResponse								"Unauthorized or current password incorrect"
+//	@Failure		500		{object}	response.ErrorResponse								"Internal server error"
+//	@Router			/202601/auth/change-password [post]
+func (c *AuthController) ChangePassword(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req authDto.ChangePasswordRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Change password request validation failed: %v", err)
+		return err
+	}
+
+	if err := c.service.ChangePassword(eCtx.Request().Context(), *authCtx, req); err != nil {
+		logger.Errorf("Change password failed: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "Password changed",
+	})
+}
+
+// ForgotPassword godoc
+//
+//	@Summary		Request password reset
+//	@Description	Email a single-use password reset link. The response is the same whether or not the email is registered.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		authDto.ForgotPasswordRequest						true	"Account email"
+//	@Success		200		{object}	response.SuccessResponse{data=map[string]string}	"Reset link sent if the account exists"
+//	@Failure		400		{object}	response.ErrorResponse								"Invalid request or validation error"
+//	@Router			/202601/auth/forgot-password [post]
+func (c *AuthController) ForgotPassword(eCtx *echo.Context) error {
+	var req authDto.ForgotPasswordRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Forgot password request validation failed: %v", err)
+		return err
+	}
+
+	if err := c.service.ForgotPassword(eCtx.Request().Context(), req); err != nil {
+		logger.Errorf("Forgot password failed: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Reset password
+//	@Description	Set a new password using an emailed reset token. Signs the user out of every session.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		authDto.ResetPasswordRequest						true	"Reset token and new password"
+//	@Success		200		{object}	response.SuccessResponse{data=map[string]string}	"Password reset"
+//	@Failure		400		{object}	response.ErrorResponse								"Invalid or expired token, or validation error"
+//	@Failure		500		{object}	response.ErrorResponse								"Internal server error"
+//	@Router			/202601/auth/reset-password [post]
+func (c *AuthController) ResetPassword(eCtx *echo.Context) error {
+	var req authDto.ResetPasswordRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Reset password request validation failed: %v", err)
+		return err
+	}
+
+	if err := c.service.ResetPassword(eCtx.Request().Context(), req); err != nil {
+		logger.Errorf("Reset password failed: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "Password has been reset",
+	})
+}
+
+// UnlockLogin godoc
+//
+//	@Summary		Lift a login lockout
+//	@Description	Reset failed-login counters and lift the lockout of an email and/or client IP. Requires platform admin.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		authDto.UnlockLoginRequest							true	"Email and/or IP to unlock"
+//	@Success		200		{object}	response.SuccessResponse{data=map[string]string}	"Lockout lifted"
+//	@Failure		400		{object}	response.ErrorResponse								"Invalid request or validation error"
+//	@Failure		401		{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse								"Platform admin permission required"
+//	@Failure		500		{object}	response.ErrorResponse								"Internal server error"
+//	@Router			/202601/auth/admin/unlock [post]
+func (c *AuthController) UnlockLogin(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req authDto.UnlockLoginRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Unlock request validation failed: %v", err)
+		return err
+	}
+
+	if err := c.service.UnlockLogin(eCtx.Request().Context(), *authCtx, req); err != nil {
+		logger.Errorf("Unlock login failed: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "Login lockout lifted",
+	})
+}
+
+// OAuthAuthorize godoc
+//
+//	@Summary		Start an OpenID Connect login
+//	@Description	Redirect to the configured identity provider (authorization code flow with PKCE)
+//	@Tags			Auth
+//	@Param			provider	path	string					true	"Provider name, e.g. google"
+//	@Success		302			"Redirect to the identity provider"
+//	@Failure		404			{object}	response.ErrorResponse	"Unknown provider or OpenID Connect login disabled"
+//	@Failure		500			{object}	response.ErrorResponse	"Identity provider unavailable"
+//	@Router			/202601/auth/oauth/{provider} [get]
+func (c *AuthController) OAuthAuthorize(eCtx *echo.Context) error {
+	provider := eCtx.Param("provider")
+
+	authURL, err := c.service.OAuthAuthorize(eCtx.Request().Context(), provider)
+	if err != nil {
+		logger.Errorf("OAuth authorize with %s failed: %v", provider, err)
+		return err
+	}
+
+	return eCtx.Redirect(http.StatusFound, authURL)
+}
+
+// OAuthCallback godoc
+//
+//	@Summary		Finish an OpenID Connect login
+//	@Description	Redirect target of the identity provider. Links the external identity to a user, creating one on first login, and returns our tokens.
+//	@Tags			Auth
+//	@Produce		json
+//	@Param			provider			path		string													true	"Provider name, e.g. google"
+//	@Param			code				query		string													false	"Authorization code"
+//	@Param			state				query		string													false	"State issued by /oauth/{provider}"
+//	@Param			error				query		string													false	"Error reported by the provider"
+//	@Success		200					{object}	response.SuccessResponse{data=authDto.LoginResponse}	"Login successful"
+//	@Failure		401					{object}	response.ErrorResponse									"Login denied, expired or not verifiable"
+//	@Failure		404					{object}	response.ErrorResponse									"Unknown provider or OpenID Connect login disabled"
+//	@Failure		409					{object}	response.ErrorResponse									"Email belongs to an existing account and is not verified by the provider or the account"
+//	@Failure		500					{object}	response.ErrorResponse									"Internal server error"
+//	@Router			/202601/auth/oauth/{provider}/callback [get]
+func (c *AuthController) OAuthCallback(eCtx *echo.Context) error {
+	provider := eCtx.Param("provider")
+
+	var req authDto.OAuthCallbackRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("OAuth callback validation failed: %v", err)
+		return err
+	}
+
+	loginResponse, err := c.service.OAuthCallback(eCtx.Request().Context(), provider, req)
+	if err != nil {
+		logger.Errorf("OAuth login with %s failed: %v", provider, err)
+		return err
+	}
+	return response.Success(eCtx, loginResponse)
+}
+
 // Me godoc
 //
 //	@Summary		Get current user profile
@@ -25,12 +25,33 @@ func (c *AuthController) RegisterRoutesV1(serviceName string, e *echo.Echo, auth
 	publicGroup.POST("/login", c.Login)
 	publicGroup.POST("/register", c.Register)
 	publicGroup.POST("/refresh", c.RefreshToken)
+	publicGroup.POST("/forgot-password", c.ForgotPassword)
+	publicGroup.POST("/reset-password", c.ResetPassword)
+	publicGroup.POST("/verify-email", c.VerifyEmail)
+	publicGroup.POST("/verify-email/resend", c.ResendVerification)
+	publicGroup.GET("/oauth/:provider", c.OAuthAuthorize)
+	publicGroup.GET("/oauth/:provider/callback", c.OAuthCallback)
+	publicGroup.POST("/mfa/verify", c.VerifyMFA)
+	publicGroup.POST("/mfa/setup", c.SetupMFA)
+	publicGroup.POST("/mfa/setup/confirm", c.ConfirmMFASetup)
 
 	// Protected routes (authentication required)
 	protectedGroup := vr.Group(e)
 	protectedGroup.Use(auth.AuthenticateMiddleware())
 	protectedGroup.POST("/logout", c.Logout)
+	protectedGroup.POST("/logout-all", c.LogoutAll)
 	protectedGroup.GET("/me", c.Me)
+	protectedGroup.POST("/change-password", c.ChangePassword)
+	protectedGroup.GET("/mfa", c.MFAStatus)
+	protectedGroup.POST("/mfa/enroll", c.EnrollMFA)
+	protectedGroup.POST("/mfa/confirm", c.ConfirmMFA)
+	protectedGroup.POST("/mfa/disable", c.DisableMFA)
+	protectedGroup.POST("/mfa/recovery-codes", c.RegenerateRecoveryCodes)
+
+	// Admin routes (platform admin checked by the service)
+	protectedGroup.POST("/admin/unlock", c.UnlockLogin)
+	protectedGroup.GET("/sessions", c.ListSessions)
+	protectedGroup.DELETE("/sessions/:id", c.RevokeSession)
 }
 
 // RegisterRoutesV2 registers V2 auth routes
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	authDto "ichi-go/internal/applications/auth/dto"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+
+	"github.com/labstack/echo/v5"
+)
+
+// VerifyEmail godoc
+//
+//	@Summary		Verify email address
+//	@Description	Confirm the email address of an account using the token from the verification email. Each token works once.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		authDto.VerifyEmailRequest							true	"Verification token"
+//	@Success		200		{object}	response.SuccessResponse{data=map[string]string}	"Email verified"
+//	@Failure		400		{object}	response.ErrorResponse								"Invalid, used or expired token"
+//	@Failure		404		{object}	response.ErrorResponse								"Email verification not enabled"
+//	@Failure		500		{object}	response.ErrorResponse								"Internal server error"
+//	@Router			/202601/auth/verify-email [post]
+func (c *AuthController) VerifyEmail(eCtx *echo.Context) error {
+	var req authDto.VerifyEmailRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Verify email request validation failed: %v", err)
+		return err
+	}
+
+	if err := c.service.VerifyEmail(eCtx.Request().Context(), req); err != nil {
+		logger.Errorf("Verify email failed: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "Email verified",
+	})
+}
+
+// ResendVerification godoc
+//
+//	@Summary		Resend verification email
+//	@Description	Email a new verification link. The response is the same whether or not the email is registered or already verified.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		authDto.ResendVerificationRequest					true	"Account email"
+//	@Success		200		{object}	response.SuccessResponse{data=map[string]string}	"Verification link sent if the account needs one"
+//	@Failure		400		{object}	response.ErrorResponse								"Invalid request or validation error"
+//	@Failure		404		{object}	response.ErrorResponse								"Email verification not enabled"
+//	@Failure		429		{object}	response.ErrorResponse								"Too many verification emails requested"
+//	@Router			/202601/auth/verify-email/resend [post]
+func (c *AuthController) ResendVerification(eCtx *echo.Context) error {
+	var req authDto.ResendVerificationRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Resend verification request validation failed: %v", err)
+		return err
+	}
+
+	if err := c.service.ResendVerification(eCtx.Request().Context(), req); err != nil {
+		logger.Errorf("Resend verification failed: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "If the email is registered and not yet verified, a verification link has been sent",
+	})
+}
@@ -0,0 +1,254 @@
+package auth
+
+import (
+	authDto "ichi-go/internal/applications/auth/dto"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+)
+
+// VerifyMFA godoc
+//
+//	@Summary		Finish a login with a second factor
+//	@Description	Exchange the challenge_token returned by /login for a token pair, using a TOTP code or a single-use recovery code.
+//	@Tags			Auth MFA
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		authDto.MFAVerifyRequest								true	"Challenge token and code"
+//	@Success		200		{object}	response.SuccessResponse{data=authDto.LoginResponse}	"Login successful"
+//	@Failure		400		{object}	response.ErrorResponse									"Invalid request or MFA enrollment required"
+//	@Failure		401		{object}	response.ErrorResponse									"Invalid code or expired challenge"
+//	@Failure		500		{object}	response.ErrorResponse									"Internal server error"
+//	@Router			/202601/auth/mfa/verify [post]
+func (c *AuthController) VerifyMFA(eCtx *echo.Context) error {
+	var req authDto.MFAVerifyRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("MFA verify request validation failed: %v", err)
+		return err
+	}
+
+	loginResponse, err := c.service.VerifyMFA(eCtx.Request().Context(), req)
+	if err != nil {
+		logger.Errorf("MFA verification failed: %v", err)
+		return err
+	}
+	return response.Success(eCtx, loginResponse)
+}
+
+// SetupMFA godoc
+//
+//	@Summary		Set up MFA during login
+//	@Description	For logins answered with mfa_enrollment_required: create a TOTP secret for the authenticator app.
+//	@Tags			Auth MFA
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		authDto.MFASetupRequest										true	"Challenge token"
+//	@Success		200		{object}	response.SuccessResponse{data=authDto.MFAEnrollResponse}	"TOTP secret created"
+//	@Failure		400		{object}	response.ErrorResponse										"Invalid request or MFA already set up"
+//	@Failure		401		{object}	response.ErrorResponse										"Expired challenge"
+//	@Failure		500		{object}	response.ErrorResponse										"Internal server error"
+//	@Router			/202601/auth/mfa/setup [post]
+func (c *AuthController) SetupMFA(eCtx *echo.Context) error {
+	var req authDto.MFASetupRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("MFA setup request validation failed: %v", err)
+		return err
+	}
+
+	enrollResponse, err := c.service.SetupMFA(eCtx.Request().Context(), req)
+	if err != nil {
+		logger.Errorf("MFA setup failed: %v", err)
+		return err
+	}
+	return response.Success(eCtx, enrollResponse)
+}
+
+// ConfirmMFASetup godoc
+//
+//	@Summary		Confirm MFA set up during login
+//	@Description	Confirm the secret from /mfa/setup with a code. Returns the token pair and the recovery codes, which are shown only once.
+//	@Tags			Auth MFA
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		authDto.MFASetupConfirmRequest							true	"Challenge token and code"
+//	@Success		200		{object}	response.SuccessResponse{data=authDto.LoginResponse}	"MFA enabled and login successful"
+//	@Failure		400		{object}	response.ErrorResponse									"Invalid request or no pending setup"
+//	@Failure		401		{object}	response.ErrorResponse									"Invalid code or expired challenge"
+//	@Failure		500		{object}	response.ErrorResponse									"Internal server error"
+//	@Router			/202601/auth/mfa/setup/confirm [post]
+func (c *AuthController) ConfirmMFASetup(eCtx *echo.Context) error {
+	var req authDto.MFASetupConfirmRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("MFA setup confirm request validation failed: %v", err)
+		return err
+	}
+
+	loginResponse, err := c.service.ConfirmMFASetup(eCtx.Request().Context(), req)
+	if err != nil {
+		logger.Errorf("MFA setup confirmation failed: %v", err)
+		return err
+	}
+	return response.Success(eCtx, loginResponse)
+}
+
+// MFAStatus godoc
+//
+//	@Summary		Get MFA status
+//	@Description	Whether the current user has MFA enabled or is required to by a role
+//	@Tags			Auth MFA
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=authDto.MFAStatusResponse}	"MFA status"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized"
+//	@Failure		404	{object}	response.ErrorResponse										"MFA disabled on this server"
+//	@Router			/202601/auth/mfa [get]
+func (c *AuthController) MFAStatus(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	status, err := c.service.MFAStatus(eCtx.Request().Context(), *authCtx)
+	if err != nil {
+		logger.Errorf("Get MFA status failed: %v", err)
+		return err
+	}
+	return response.Success(eCtx, status)
+}
+
+// EnrollMFA godoc
+//
+//	@Summary		Start MFA enrollment
+//	@Description	Create a TOTP secret for the authenticator app. MFA is enabled once confirmed at /mfa/confirm.
+//	@Tags			Auth MFA
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=authDto.MFAEnrollResponse}	"TOTP secret created"
+//	@Failure		400	{object}	response.ErrorResponse										"MFA already enabled"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized"
+//	@Failure		404	{object}	response.ErrorResponse										"MFA disabled on this server"
+//	@Router			/202601/auth/mfa/enroll [post]
+func (c *AuthController) EnrollMFA(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	enrollResponse, err := c.service.EnrollMFA(eCtx.Request().Context(), *authCtx)
+	if err != nil {
+		logger.Errorf("MFA enrollment failed: %v", err)
+		return err
+	}
+	return response.Success(eCtx, enrollResponse)
+}
+
+// ConfirmMFA godoc
+//
+//	@Summary		Confirm MFA enrollment
+//	@Description	Enable MFA with a code from the authenticator app. Returns recovery codes, which are shown only once.
+//	@Tags			Auth MFA
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		authDto.MFACodeRequest											true	"Authenticator code"
+//	@Success		200		{object}	response.SuccessResponse{data=authDto.MFARecoveryCodesResponse}	"MFA enabled"
+//	@Failure		400		{object}	response.ErrorResponse											"Invalid request or no pending enrollment"
+//	@Failure		401		{object}	response.ErrorResponse											"Unauthorized or invalid code"
+//	@Router			/202601/auth/mfa/confirm [post]
+func (c *AuthController) ConfirmMFA(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req authDto.MFACodeRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("MFA confirm request validation failed: %v", err)
+		return err
+	}
+
+	codes, err := c.service.ConfirmMFA(eCtx.Request().Context(), *authCtx, req)
+	if err != nil {
+		logger.Errorf("MFA confirmation failed: %v", err)
+		return err
+	}
+	return response.Success(eCtx, codes)
+}
+
+// DisableMFA godoc
+//
+//	@Summary		Disable MFA
+//	@Description	Turn MFA off with the password and a TOTP or recovery code. Not allowed when a role of the user requires MFA.
+//	@Tags			Auth MFA
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		authDto.MFADisableRequest							true	"Password and code"
+//	@Success		200		{object}	response.SuccessResponse{data=map[string]string}	"MFA disabled"
+//	@Failure		400		{object}	response.ErrorResponse								"Invalid request or MFA not enabled"
+//	@Failure		401		{object}	response.ErrorResponse								"Unauthorized, wrong password or invalid code"
+//	@Failure		403		{object}	response.ErrorResponse								"MFA required by role"
+//	@Router			/202601/auth/mfa/disable [post]
+func (c *AuthController) DisableMFA(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req authDto.MFADisableRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("MFA disable request validation failed: %v", err)
+		return err
+	}
+
+	if err := c.service.DisableMFA(eCtx.Request().Context(), *authCtx, req); err != nil {
+		logger.Errorf("Disable MFA failed: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": "Multi-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+//
+//	@Summary		Regenerate MFA recovery codes
+//	@Description	Replace all recovery codes; the old ones stop working. The new codes are shown only once.
+//	@Tags			Auth MFA
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		authDto.MFACodeRequest											true	"Authenticator code"
+//	@Success		200		{object}	response.SuccessResponse{data=authDto.MFARecoveryCodesResponse}	"New recovery codes"
+//	@Failure		400		{object}	response.ErrorResponse											"Invalid request or MFA not enabled"
+//	@Failure		401		{object}	response.ErrorResponse											"Unauthorized or invalid code"
+//	@Router			/202601/auth/mfa/recovery-codes [post]
+func (c *AuthController) RegenerateRecoveryCodes(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req authDto.MFACodeRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Recovery codes request validation failed: %v", err)
+		return err
+	}
+
+	codes, err := c.service.RegenerateRecoveryCodes(eCtx.Request().Context(), *authCtx, req)
+	if err != nil {
+		logger.Errorf("Regenerate recovery codes failed: %v", err)
+		return err
+	}
+	return response.Success(eCtx, codes)
+}
@@ -1,5 +1,7 @@
 package auth
 
+import "time"
+
 // LoginRequest represents login credentials
 //
 //	@Description	Login credentials for user authentication
@@ -24,6 +26,13 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 }
 
+// LogoutRequest represents logout data
+//
+//	@Description	Optional refresh token to revoke together with the current access token
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
 // ChangePasswordRequest represents password change data
 //
 //	@Description	Password change information
@@ -31,3 +40,110 @@ type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" validate:"required,min=6" example:"OldPass123!"`
 	NewPassword string `json:"new_password" validate:"required,strong_password" example:"NewSecurePass123!@#"`
 }
+
+// ForgotPasswordRequest represents a password reset request
+//
+//	@Description	Email address of the account to reset
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email" example:"user@example.com"`
+}
+
+// ResetPasswordRequest represents password reset data
+//
+//	@Description	Emailed reset token and the new password
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required" example:"q3Jf0v1mX8b2..."`
+	NewPassword string `json:"new_password" validate:"required,strong_password" example:"NewSecurePass123!@#"`
+}
+
+// UnlockLoginRequest represents an admin request to lift a login lockout
+//
+//	@Description	Email and/or client IP whose login lockout should be lifted
+type UnlockLoginRequest struct {
+	Email     string `json:"email" validate:"omitempty,email" example:"user@example.com"`
+	IPAddress string `json:"ip_address" validate:"omitempty,ip" example:"203.0.113.7"`
+}
+
+// CreateAPIKeyRequest represents a new API key for a client integration
+//
+//	@Description	API key name, scopes and optional tenant binding and expiry
+type CreateAPIKeyRequest struct {
+	Name        string     `json:"name" validate:"required,max=100" example:"Acme partner integration"`
+	Scopes      []string   `json:"scopes" validate:"required,min=1,dive,required,max=100" example:"orders:view,products:*"`
+	TenantID    string     `json:"tenant_id" validate:"omitempty,max=100" example:"acme"`
+	ExpiresAt   *time.Time `json:"expires_at" example:"2027-01-01T00:00:00Z"`
+	OwnerUserID uint64     `json:"owner_user_id" example:"42" description:"Platform admins only: issue the key on behalf of another user"`
+}
+
+// UpdateAPIKeyRequest represents changes to an API key; omitted fields are kept
+//
+//	@Description	New API key name, scopes and/or expiry
+type UpdateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"omitempty,max=100" example:"Acme partner integration"`
+	Scopes    []string   `json:"scopes" validate:"omitempty,min=1,dive,required,max=100" example:"orders:view"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2027-06-01T00:00:00Z"`
+}
+
+// OAuthCallbackRequest represents the redirect back from an OpenID Connect provider
+//
+//	@Description	Authorization code and state, or the error reported by the provider
+type OAuthCallbackRequest struct {
+	Code             string `query:"code" example:"4/0AbCD..."`
+	State            string `query:"state" example:"q5W0h1..."`
+	Error            string `query:"error" example:"access_denied"`
+	ErrorDescription string `query:"error_description" example:"The user denied access"`
+}
+
+// MFAVerifyRequest represents the second login step
+//
+//	@Description	Login challenge token and either a TOTP code or a recovery code
+type MFAVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required" example:"Xq1v9c..."`
+	Code           string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric" example:"123456"`
+	RecoveryCode   string `json:"recovery_code" validate:"required_without=Code,omitempty,max=20" example:"k3j9a-x2mq7"`
+}
+
+// MFASetupRequest starts the MFA enrollment required to finish a login
+//
+//	@Description	Login challenge token of an account that must enroll in MFA
+type MFASetupRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required" example:"Xq1v9c..."`
+}
+
+// MFASetupConfirmRequest confirms the MFA enrollment required to finish a login
+//
+//	@Description	Login challenge token and the first code of the authenticator app
+type MFASetupConfirmRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required" example:"Xq1v9c..."`
+	Code           string `json:"code" validate:"required,len=6,numeric" example:"123456"`
+}
+
+// MFACodeRequest represents a TOTP code confirming an MFA change
+//
+//	@Description	Current code of the authenticator app
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric" example:"123456"`
+}
+
+// MFADisableRequest represents a request to turn MFA off
+//
+//	@Description	Password and a TOTP code or recovery code
+type MFADisableRequest struct {
+	Password     string `json:"password" validate:"required" example:"SecurePass123!"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric" example:"123456"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code,omitempty,max=20" example:"k3j9a-x2mq7"`
+}
+
+// VerifyEmailRequest represents the token from an email verification link
+//
+//	@Description	Signed token from the verification email
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,max=200" example:"42.1760000000.x9Qw..."`
+}
+
+// ResendVerificationRequest represents a request for a new verification email
+//
+//	@Description	Email address of the account to verify
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email" example:"user@example.com"`
+}
@@ -1,26 +1,71 @@
 package auth
 
 import (
+	"fmt"
+
 	"ichi-go/config"
 	authController "ichi-go/internal/applications/auth/controller"
+	authRepo "ichi-go/internal/applications/auth/repository"
 	authService "ichi-go/internal/applications/auth/service"
+	rbacRepo "ichi-go/internal/applications/rbac/repositories"
+	rbacServices "ichi-go/internal/applications/rbac/services"
 	userRepo "ichi-go/internal/applications/user/repository"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	"ichi-go/pkg/authenticator"
 	"ichi-go/pkg/logger"
 
 	"github.com/samber/do/v2"
+	"github.com/uptrace/bun"
 )
 
 // RegisterProviders registers all auth domain dependencies
 func RegisterProviders(injector do.Injector) {
+	do.Provide(injector, ProvideSessionRepository)
+	do.Provide(injector, ProvidePasswordResetRepository)
+	do.Provide(injector, ProvideAPIKeyRepository)
+	do.Provide(injector, ProvideUserIdentityRepository)
+	do.Provide(injector, ProvideMFARepository)
 	do.Provide(injector, ProvideAuthService)
+	do.Provide(injector, ProvideAPIKeyService)
 	do.Provide(injector, ProvideAuthController)
+	do.Provide(injector, ProvideAPIKeyController)
+}
+
+// ProvideSessionRepository provides the login session repository
+func ProvideSessionRepository(i do.Injector) (*authRepo.SessionRepositoryImpl, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return authRepo.NewSessionRepository(db), nil
+}
+
+// ProvidePasswordResetRepository provides the password reset token repository
+func ProvidePasswordResetRepository(i do.Injector) (*authRepo.PasswordResetRepositoryImpl, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return authRepo.NewPasswordResetRepository(db), nil
+}
+
+// ProvideAPIKeyRepository provides the API key repository
+func ProvideAPIKeyRepository(i do.Injector) (*authRepo.APIKeyRepositoryImpl, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return authRepo.NewAPIKeyRepository(db), nil
+}
+
+// ProvideUserIdentityRepository provides the linked external identity repository
+func ProvideUserIdentityRepository(i do.Injector) (*authRepo.UserIdentityRepositoryImpl, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return authRepo.NewUserIdentityRepository(db), nil
+}
+
+// ProvideMFARepository provides the TOTP authenticator and recovery code repository
+func ProvideMFARepository(i do.Injector) (*authRepo.MFARepositoryImpl, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return authRepo.NewMFARepository(db), nil
 }
 
 // ProvideAuthService provides auth service instance
 func ProvideAuthService(i do.Injector) (*authService.ServiceImpl, error) {
 	userRepository := do.MustInvoke[*userRepo.RepositoryImpl](i)
+	sessionRepository := do.MustInvoke[*authRepo.SessionRepositoryImpl](i)
+	resetRepository := do.MustInvoke[*authRepo.PasswordResetRepositoryImpl](i)
 	cfg := do.MustInvoke[*config.Config](i)
 
 	// Create JWT authenticator
@@ -38,7 +83,112 @@ func ProvideAuthService(i do.Injector) (*authService.ServiceImpl, error) {
 		logger.Warnf("⚠️  Queue not available: %v", err)
 	}
 
-	return authService.NewAuthService(userRepository, jwtAuth, producer), nil
+	mfa, err := provideMFA(i, cfg.Auth().MFA)
+	if err != nil {
+		return nil, err
+	}
+	emailVerifier, err := provideEmailVerifier(i, cfg.Auth().EmailVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	db := do.MustInvoke[*bun.DB](i)
+	return authService.NewAuthService(userRepository, sessionRepository, resetRepository, jwtAuth, producer, authService.Options{
+		PasswordReset: cfg.Auth().PasswordReset,
+		ResetLimiter:  provideResetLimiter(i, cfg.Auth().PasswordReset),
+		LoginThrottle: provideLoginThrottle(i, cfg.Auth().LoginThrottle),
+		// The RBAC domain registers after auth, so its repositories are built directly.
+		AuditLog:   rbacRepo.NewAuditRepository(db),
+		Admins:     rbacRepo.NewPlatformRepository(db),
+		OIDC:       provideOIDC(i, cfg.Auth().OIDC),
+		Identities: do.MustInvoke[*authRepo.UserIdentityRepositoryImpl](i),
+		MFA:        mfa,
+		MFARepo:    do.MustInvoke[*authRepo.MFARepositoryImpl](i),
+		MFAPolicy: rbacServices.NewMFAPolicyService(
+			rbacRepo.NewPlatformRepository(db), rbacRepo.NewUserRoleRepository(db), cfg.RBAC()),
+		EmailVerifier: emailVerifier,
+	}), nil
+}
+
+// provideOIDC builds OpenID Connect login. It is disabled when not configured.
+// Without Redis, login state lives in process memory and only works on a single instance.
+func provideOIDC(i do.Injector, oidcCfg *authenticator.OIDCConfig) *authenticator.OIDC {
+	if oidcCfg == nil || !oidcCfg.Enabled {
+		return nil
+	}
+	store, err := do.Invoke[authenticator.OIDCStateStore](i)
+	if err != nil || store == nil {
+		logger.Warnf("⚠️  OIDC login state kept in memory: Redis not available")
+		store = authenticator.NewMemoryOIDCStateStore()
+	}
+	return authenticator.NewOIDC(oidcCfg, store, nil)
+}
+
+// provideMFA builds TOTP multi-factor authentication. It is disabled when not
+// configured; an invalid encryption key fails startup, since running without MFA
+// would let enrolled users log in with their password alone. Without Redis, pending
+// login steps live in process memory and only work on a single instance.
+func provideMFA(i do.Injector, mfaCfg *authenticator.MFAConfig) (*authenticator.MFA, error) {
+	if mfaCfg == nil || !mfaCfg.Enabled {
+		return nil, nil
+	}
+	store, err := do.Invoke[authenticator.MFAChallengeStore](i)
+	if err != nil || store == nil {
+		logger.Warnf("⚠️  MFA challenges kept in memory: Redis not available")
+		store = authenticator.NewMemoryMFAChallengeStore()
+	}
+	mfa, err := authenticator.NewMFA(*mfaCfg, store)
+	if err != nil {
+		return nil, fmt.Errorf("mfa is enabled but cannot start: %w", err)
+	}
+	return mfa, nil
+}
+
+// provideEmailVerifier builds email verification. It is disabled when not configured;
+// an invalid signing key fails startup, since running without verification would
+// silently drop require_for_login. Without Redis, resend limits are counted per instance.
+func provideEmailVerifier(i do.Injector, verifyCfg *authenticator.EmailVerificationConfig) (*authenticator.EmailVerifier, error) {
+	if verifyCfg == nil || !verifyCfg.Enabled {
+		return nil, nil
+	}
+	store, err := do.Invoke[authenticator.RateLimitStore](i)
+	if err != nil || store == nil {
+		logger.Warnf("⚠️  Verification resend limits kept in memory: Redis not available")
+		store = authenticator.NewMemoryRateLimitStore()
+	}
+	verifier, err := authenticator.NewEmailVerifier(*verifyCfg, store)
+	if err != nil {
+		return nil, fmt.Errorf("email verification is enabled but cannot start: %w", err)
+	}
+	return verifier, nil
+}
+
+// provideResetLimiter builds the limit of forgot-password requests. Without Redis,
+// requests are counted per instance.
+func provideResetLimiter(i do.Injector, resetCfg *authenticator.PasswordResetConfig) *authenticator.ResendLimiter {
+	if resetCfg == nil {
+		resetCfg = &authenticator.PasswordResetConfig{}
+	}
+	store, err := do.Invoke[authenticator.RateLimitStore](i)
+	if err != nil || store == nil {
+		logger.Warnf("⚠️  Password reset limits kept in memory: Redis not available")
+		store = authenticator.NewMemoryRateLimitStore()
+	}
+	return resetCfg.NewRequestLimiter(store)
+}
+
+// provideLoginThrottle builds the login brute-force protection. It is disabled
+// when not configured or when Redis is unavailable.
+func provideLoginThrottle(i do.Injector, throttleCfg *authenticator.LoginThrottleConfig) *authenticator.LoginThrottle {
+	if throttleCfg == nil || !throttleCfg.Enabled {
+		return nil
+	}
+	store, err := do.Invoke[authenticator.LoginAttemptStore](i)
+	if err != nil || store == nil {
+		logger.Warnf("⚠️  Login throttling disabled: Redis not available")
+		return nil
+	}
+	return authenticator.NewLoginThrottle(store, *throttleCfg)
 }
 
 // ProvideAuthController provides auth controller instance
@@ -46,3 +196,16 @@ func ProvideAuthController(i do.Injector) (*authController.AuthController, error
 	svc := do.MustInvoke[*authService.ServiceImpl](i)
 	return authController.NewAuthController(svc), nil
 }
+
+// ProvideAPIKeyService provides the API key management service
+func ProvideAPIKeyService(i do.Injector) (*authService.APIKeyServiceImpl, error) {
+	repo := do.MustInvoke[*authRepo.APIKeyRepositoryImpl](i)
+	db := do.MustInvoke[*bun.DB](i)
+	return authService.NewAPIKeyService(repo, rbacRepo.NewPlatformRepository(db)), nil
+}
+
+// ProvideAPIKeyController provides the API key controller
+func ProvideAPIKeyController(i do.Injector) (*authController.APIKeyController, error) {
+	svc := do.MustInvoke[*authService.APIKeyServiceImpl](i)
+	return authController.NewAPIKeyController(svc), nil
+}
@@ -19,4 +19,5 @@ func Register(injector do.Injector, serviceName string, e *echo.Echo, auth *auth
 
 	// Register routes
 	authDI.RegisterRoutes(serviceName, e, auth)
+	do.MustInvoke[*authController.APIKeyController](injector).RegisterRoutes(serviceName, e, auth)
 }
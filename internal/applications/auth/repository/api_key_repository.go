@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"context"
+	"ichi-go/pkg/db/model"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	// FindByID returns nil, nil when the key does not exist.
+	FindByID(ctx context.Context, id int64) (*model.APIKey, error)
+	// FindByPrefix returns nil, nil when no key has prefix.
+	FindByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+	// FindByOwner lists the keys of a user that are not revoked, newest first.
+	FindByOwner(ctx context.Context, ownerUserID uint64) ([]*model.APIKey, error)
+	// Update saves name, scopes and expires_at.
+	Update(ctx context.Context, key *model.APIKey) error
+	// Rotate replaces the key material; the previous key stops working immediately.
+	Rotate(ctx context.Context, id int64, prefix, secretHash string) error
+	Revoke(ctx context.Context, id int64) error
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
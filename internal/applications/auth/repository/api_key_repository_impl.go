@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/db/repository"
+	pkgErrors "ichi-go/pkg/errors"
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+type APIKeyRepositoryImpl struct {
+	*repository.BaseRepository[model.APIKey]
+}
+
+func NewAPIKeyRepository(dbConnection *upbun.DB) *APIKeyRepositoryImpl {
+	return &APIKeyRepositoryImpl{BaseRepository: repository.NewRepository[model.APIKey](dbConnection, &model.APIKey{})}
+}
+
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, key *model.APIKey) error {
+	if _, err := r.DB().NewInsert().Model(key).Returning("id").Exec(ctx); err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "create_api_key").
+			With("owner_user_id", key.OwnerUserID).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) FindByID(ctx context.Context, id int64) (*model.APIKey, error) {
+	key := new(model.APIKey)
+	err := r.DB().NewSelect().Model(key).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_api_key").
+			With("api_key_id", id).
+			Wrap(err)
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepositoryImpl) FindByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	key := new(model.APIKey)
+	err := r.DB().NewSelect().Model(key).Where("prefix = ?", prefix).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_api_key").
+			With("prefix", prefix).
+			Wrap(err)
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepositoryImpl) FindByOwner(ctx context.Context, ownerUserID uint64) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	err := r.DB().NewSelect().Model(&keys).
+		Where("owner_user_id = ?", ownerUserID).
+		Where("revoked_at IS NULL").
+		Order("id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "list_api_keys").
+			With("owner_user_id", ownerUserID).
+			Wrap(err)
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepositoryImpl) Update(ctx context.Context, key *model.APIKey) error {
+	_, err := r.DB().NewUpdate().Model(key).
+		Column("name", "scopes", "expires_at").
+		Set("updated_at = ?", time.Now()).
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "update_api_key").
+			With("api_key_id", key.ID).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) Rotate(ctx context.Context, id int64, prefix, secretHash string) error {
+	_, err := r.DB().NewUpdate().
+		TableExpr("api_keys").
+		Set("prefix = ?", prefix).
+		Set("secret_hash = ?", secretHash).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "rotate_api_key").
+			With("api_key_id", id).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) Revoke(ctx context.Context, id int64) error {
+	now := time.Now()
+	_, err := r.DB().NewUpdate().
+		TableExpr("api_keys").
+		Set("revoked_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "revoke_api_key").
+			With("api_key_id", id).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.DB().NewUpdate().
+		TableExpr("api_keys").
+		Set("last_used_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "touch_api_key").
+			With("api_key_id", id).
+			Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+	"ichi-go/pkg/db/model"
+)
+
+type MFARepository interface {
+	// FindByUser returns nil, nil when the user has no authenticator.
+	FindByUser(ctx context.Context, userID uint64) (*model.UserMFA, error)
+	// SavePending stores a new unconfirmed authenticator, replacing a pending one.
+	SavePending(ctx context.Context, mfa *model.UserMFA) error
+	// Confirm enables a pending authenticator. It reports false when it was already confirmed.
+	Confirm(ctx context.Context, id int64, step int64) (bool, error)
+	// UseStep records an accepted TOTP step. It reports false when the step (or a
+	// later one) was already used, so a code cannot be replayed concurrently.
+	UseStep(ctx context.Context, id int64, step int64) (bool, error)
+	// Delete removes the authenticator and the recovery codes of the user.
+	Delete(ctx context.Context, userID uint64) error
+
+	// ReplaceRecoveryCodes invalidates all recovery codes of the user and stores new hashes.
+	ReplaceRecoveryCodes(ctx context.Context, userID uint64, codeHashes []string) error
+	// UseRecoveryCode marks an unused code as used. It reports false when no unused code matches.
+	UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) (bool, error)
+	// CountRecoveryCodes returns the number of unused recovery codes.
+	CountRecoveryCodes(ctx context.Context, userID uint64) (int, error)
+}
@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/db/repository"
+	pkgErrors "ichi-go/pkg/errors"
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+type MFARepositoryImpl struct {
+	*repository.BaseRepository[model.UserMFA]
+}
+
+func NewMFARepository(dbConnection *upbun.DB) *MFARepositoryImpl {
+	return &MFARepositoryImpl{BaseRepository: repository.NewRepository[model.UserMFA](dbConnection, &model.UserMFA{})}
+}
+
+func (r *MFARepositoryImpl) FindByUser(ctx context.Context, userID uint64) (*model.UserMFA, error) {
+	mfa := new(model.UserMFA)
+	err := r.DB().NewSelect().Model(mfa).Where("user_id = ?", userID).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_user_mfa").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return mfa, nil
+}
+
+func (r *MFARepositoryImpl) SavePending(ctx context.Context, mfa *model.UserMFA) error {
+	err := r.DB().RunInTx(ctx, nil, func(ctx context.Context, tx upbun.Tx) error {
+		if _, err := tx.NewDelete().
+			TableExpr("user_mfa").
+			Where("user_id = ?", mfa.UserID).
+			Where("confirmed_at IS NULL").
+			Exec(ctx); err != nil {
+			return err
+		}
+		_, err := tx.NewInsert().Model(mfa).Returning("id").Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "create_user_mfa").
+			With("user_id", mfa.UserID).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *MFARepositoryImpl) Confirm(ctx context.Context, id int64, step int64) (bool, error) {
+	now := time.Now()
+	res, err := r.DB().NewUpdate().
+		TableExpr("user_mfa").
+		Set("confirmed_at = ?", now).
+		Set("last_used_step = ?", step).
+		Set("updated_at = ?", now).
+		Where("id = ?", id).
+		Where("confirmed_at IS NULL").
+		Exec(ctx)
+	return r.affectedOne(res, err, "confirm_user_mfa", id)
+}
+
+func (r *MFARepositoryImpl) UseStep(ctx context.Context, id int64, step int64) (bool, error) {
+	res, err := r.DB().NewUpdate().
+		TableExpr("user_mfa").
+		Set("last_used_step = ?", step).
+		Where("id = ?", id).
+		Where("last_used_step < ?", step).
+		Exec(ctx)
+	return r.affectedOne(res, err, "use_user_mfa_step", id)
+}
+
+func (r *MFARepositoryImpl) Delete(ctx context.Context, userID uint64) error {
+	err := r.DB().RunInTx(ctx, nil, func(ctx context.Context, tx upbun.Tx) error {
+		if _, err := tx.NewDelete().TableExpr("user_mfa_recovery_codes").Where("user_id = ?", userID).Exec(ctx); err != nil {
+			return err
+		}
+		_, err := tx.NewDelete().TableExpr("user_mfa").Where("user_id = ?", userID).Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "delete_user_mfa").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *MFARepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, userID uint64, codeHashes []string) error {
+	codes := make([]model.MFARecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = model.MFARecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	err := r.DB().RunInTx(ctx, nil, func(ctx context.Context, tx upbun.Tx) error {
+		if _, err := tx.NewDelete().TableExpr("user_mfa_recovery_codes").Where("user_id = ?", userID).Exec(ctx); err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		_, err := tx.NewInsert().Model(&codes).Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "replace_mfa_recovery_codes").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *MFARepositoryImpl) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) (bool, error) {
+	res, err := r.DB().NewUpdate().
+		TableExpr("user_mfa_recovery_codes").
+		Set("used_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("code_hash = ?", codeHash).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	return r.affectedOne(res, err, "use_mfa_recovery_code", int64(userID))
+}
+
+func (r *MFARepositoryImpl) CountRecoveryCodes(ctx context.Context, userID uint64) (int, error) {
+	n, err := r.DB().NewSelect().
+		Model((*model.MFARecoveryCode)(nil)).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Count(ctx)
+	if err != nil {
+		return 0, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "count_mfa_recovery_codes").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return n, nil
+}
+
+func (r *MFARepositoryImpl) affectedOne(res sql.Result, err error, operation string, id int64) (bool, error) {
+	if err == nil {
+		var n int64
+		if n, err = res.RowsAffected(); err == nil {
+			return n == 1, nil
+		}
+	}
+	return false, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+		With("operation", operation).
+		With("id", id).
+		Wrap(err)
+}
@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"context"
+	"ichi-go/pkg/db/model"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, reset *model.PasswordReset) error
+	// FindValidByTokenHash returns nil, nil when the token is unknown, used or expired.
+	FindValidByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordReset, error)
+	// MarkUsed redeems the token; it reports false when it was already used.
+	MarkUsed(ctx context.Context, id int64) (bool, error)
+	// InvalidateForUser marks every outstanding token of the user as used.
+	InvalidateForUser(ctx context.Context, userID uint64) error
+}
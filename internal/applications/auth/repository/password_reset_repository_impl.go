@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/db/repository"
+	pkgErrors "ichi-go/pkg/errors"
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+type PasswordResetRepositoryImpl struct {
+	*repository.BaseRepository[model.PasswordReset]
+}
+
+func NewPasswordResetRepository(dbConnection *upbun.DB) *PasswordResetRepositoryImpl {
+	return &PasswordResetRepositoryImpl{BaseRepository: repository.NewRepository[model.PasswordReset](dbConnection, &model.PasswordReset{})}
+}
+
+func (r *PasswordResetRepositoryImpl) Create(ctx context.Context, reset *model.PasswordReset) error {
+	if _, err := r.DB().NewInsert().Model(reset).Returning("id").Exec(ctx); err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "create_password_reset").
+			With("user_id", reset.UserID).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *PasswordResetRepositoryImpl) FindValidByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+	reset := new(model.PasswordReset)
+	err := r.DB().NewSelect().Model(reset).
+		Where("token_hash = ?", tokenHash).
+		Where("used_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_password_reset").
+			Wrap(err)
+	}
+	return reset, nil
+}
+
+func (r *PasswordResetRepositoryImpl) MarkUsed(ctx context.Context, id int64) (bool, error) {
+	res, err := r.DB().NewUpdate().
+		TableExpr("password_resets").
+		Set("used_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "use_password_reset").
+			With("password_reset_id", id).
+			Wrap(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "use_password_reset").
+			With("password_reset_id", id).
+			Wrap(err)
+	}
+	return n == 1, nil
+}
+
+func (r *PasswordResetRepositoryImpl) InvalidateForUser(ctx context.Context, userID uint64) error {
+	_, err := r.DB().NewUpdate().
+		TableExpr("password_resets").
+		Set("used_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "invalidate_password_resets").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+	"ichi-go/pkg/db/model"
+)
+
+// Session revocation reasons stored in user_sessions.revoked_reason.
+const (
+	RevokedReasonLogout         = "logout"
+	RevokedReasonLogoutAll      = "logout_all"
+	RevokedReasonUserRevoked    = "user_revoked"
+	RevokedReasonReuseDetected  = "reuse_detected"
+	RevokedReasonPasswordChange = "password_change"
+	RevokedReasonPasswordReset  = "password_reset"
+	RevokedReasonUserDeleted    = "user_deleted"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *model.UserSession) error
+	// FindByFamilyID returns nil, nil when no session exists for familyID.
+	FindByFamilyID(ctx context.Context, familyID string) (*model.UserSession, error)
+	// FindByIDForUser returns nil, nil when the session does not exist or belongs to another user.
+	FindByIDForUser(ctx context.Context, id int64, userID uint64) (*model.UserSession, error)
+	FindActiveByUser(ctx context.Context, userID uint64) ([]*model.UserSession, error)
+	// Rotate moves the session to next's refresh/access jti only if its refresh jti
+	// still equals oldRefreshJTI. It reports false when the token was already rotated.
+	Rotate(ctx context.Context, id int64, oldRefreshJTI string, next model.UserSession) (bool, error)
+	Revoke(ctx context.Context, id int64, reason string) error
+	RevokeAllForUser(ctx context.Context, userID uint64, reason string) error
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/db/repository"
+	pkgErrors "ichi-go/pkg/errors"
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+type SessionRepositoryImpl struct {
+	*repository.BaseRepository[model.UserSession]
+}
+
+func NewSessionRepository(dbConnection *upbun.DB) *SessionRepositoryImpl {
+	return &SessionRepositoryImpl{BaseRepository: repository.NewRepository[model.UserSession](dbConnection, &model.UserSession{})}
+}
+
+func (r *SessionRepositoryImpl) Create(ctx context.Context, session *model.UserSession) error {
+	if _, err := r.DB().NewInsert().Model(session).Returning("id").Exec(ctx); err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "create_session").
+			With("user_id", session.UserID).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *SessionRepositoryImpl) FindByFamilyID(ctx context.Context, familyID string) (*model.UserSession, error) {
+	session := new(model.UserSession)
+	err := r.DB().NewSelect().Model(session).Where("family_id = ?", familyID).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_session").
+			With("family_id", familyID).
+			Wrap(err)
+	}
+	return session, nil
+}
+
+func (r *SessionRepositoryImpl) FindByIDForUser(ctx context.Context, id int64, userID uint64) (*model.UserSession, error) {
+	session := new(model.UserSession)
+	err := r.DB().NewSelect().Model(session).
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_session").
+			With("session_id", id).
+			Wrap(err)
+	}
+	return session, nil
+}
+
+func (r *SessionRepositoryImpl) FindActiveByUser(ctx context.Context, userID uint64) ([]*model.UserSession, error) {
+	var sessions []*model.UserSession
+	err := r.DB().NewSelect().Model(&sessions).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Order("last_used_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "list_sessions").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepositoryImpl) Rotate(ctx context.Context, id int64, oldRefreshJTI string, next model.UserSession) (bool, error) {
+	res, err := r.DB().NewUpdate().
+		TableExpr("user_sessions").
+		Set("refresh_jti = ?", next.RefreshJTI).
+		Set("access_jti = ?", next.AccessJTI).
+		Set("ip_address = ?", next.IPAddress).
+		Set("user_agent = ?", next.UserAgent).
+		Set("last_used_at = ?", next.LastUsedAt).
+		Set("expires_at = ?", next.ExpiresAt).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("refresh_jti = ?", oldRefreshJTI).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "rotate_session").
+			With("session_id", id).
+			Wrap(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "rotate_session").
+			With("session_id", id).
+			Wrap(err)
+	}
+	return n == 1, nil
+}
+
+func (r *SessionRepositoryImpl) Revoke(ctx context.Context, id int64, reason string) error {
+	now := time.Now()
+	_, err := r.DB().NewUpdate().
+		TableExpr("user_sessions").
+		Set("revoked_at = ?", now).
+		Set("revoked_reason = ?", reason).
+		Set("updated_at = ?", now).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "revoke_session").
+			With("session_id", id).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *SessionRepositoryImpl) RevokeAllForUser(ctx context.Context, userID uint64, reason string) error {
+	now := time.Now()
+	_, err := r.DB().NewUpdate().
+		TableExpr("user_sessions").
+		Set("revoked_at = ?", now).
+		Set("revoked_reason = ?", reason).
+		Set("updated_at = ?", now).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "revoke_sessions").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package auth_test
+
+import (
+	authRepo "ichi-go/internal/applications/auth/repository"
+)
+
+// Compile-time assertion: *SessionRepositoryImpl must satisfy SessionRepository.
+var _ authRepo.SessionRepository = (*authRepo.SessionRepositoryImpl)(nil)
+
+// Compile-time assertion: *PasswordResetRepositoryImpl must satisfy PasswordResetRepository.
+var _ authRepo.PasswordResetRepository = (*authRepo.PasswordResetRepositoryImpl)(nil)
+
+// Compile-time assertion: *APIKeyRepositoryImpl must satisfy APIKeyRepository.
+var _ authRepo.APIKeyRepository = (*authRepo.APIKeyRepositoryImpl)(nil)
+
+// Compile-time assertion: *UserIdentityRepositoryImpl must satisfy UserIdentityRepository.
+var _ authRepo.UserIdentityRepository = (*authRepo.UserIdentityRepositoryImpl)(nil)
+
+// Compile-time assertion: *MFARepositoryImpl must satisfy MFARepository.
+var _ authRepo.MFARepository = (*authRepo.MFARepositoryImpl)(nil)
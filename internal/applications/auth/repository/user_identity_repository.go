@@ -0,0 +1,15 @@
+package auth
+
+import (
+	"context"
+	"ichi-go/pkg/db/model"
+	"time"
+)
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *model.UserIdentity) error
+	// FindByProviderSubject returns nil, nil when the external account is not linked.
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error)
+	// TouchLogin records a login and the email the provider reported for it.
+	TouchLogin(ctx context.Context, id int64, email string, at time.Time) error
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/db/repository"
+	pkgErrors "ichi-go/pkg/errors"
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+type UserIdentityRepositoryImpl struct {
+	*repository.BaseRepository[model.UserIdentity]
+}
+
+func NewUserIdentityRepository(dbConnection *upbun.DB) *UserIdentityRepositoryImpl {
+	return &UserIdentityRepositoryImpl{BaseRepository: repository.NewRepository[model.UserIdentity](dbConnection, &model.UserIdentity{})}
+}
+
+func (r *UserIdentityRepositoryImpl) Create(ctx context.Context, identity *model.UserIdentity) error {
+	if _, err := r.DB().NewInsert().Model(identity).Returning("id").Exec(ctx); err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "create_user_identity").
+			With("user_id", identity.UserID).
+			With("provider", identity.Provider).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *UserIdentityRepositoryImpl) FindByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	identity := new(model.UserIdentity)
+	err := r.DB().NewSelect().Model(identity).
+		Where("provider = ?", provider).
+		Where("subject = ?", subject).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_user_identity").
+			With("provider", provider).
+			Wrap(err)
+	}
+	return identity, nil
+}
+
+func (r *UserIdentityRepositoryImpl) TouchLogin(ctx context.Context, id int64, email string, at time.Time) error {
+	_, err := r.DB().NewUpdate().
+		TableExpr("user_identities").
+		Set("email = ?", email).
+		Set("last_login_at = ?", at).
+		Set("updated_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "touch_user_identity").
+			With("user_identity_id", id).
+			Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+	authDto "ichi-go/internal/applications/auth/dto"
+	authRepo "ichi-go/internal/applications/auth/repository"
+	"ichi-go/pkg/authenticator"
+)
+
+// APIKeyService manages per-client API keys. A key acts on behalf of its owner,
+// so RBAC still evaluates the owner; scopes can only narrow what the key may do.
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, authCtx authenticator.AuthContext, req authDto.CreateAPIKeyRequest) (*authDto.APIKeySecretResponse, error)
+	ListAPIKeys(ctx context.Context, authCtx authenticator.AuthContext) ([]authDto.APIKeyResponse, error)
+	GetAPIKey(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*authDto.APIKeyResponse, error)
+	UpdateAPIKey(ctx context.Context, authCtx authenticator.AuthContext, id int64, req authDto.UpdateAPIKeyRequest) (*authDto.APIKeyResponse, error)
+	RotateAPIKey(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*authDto.APIKeySecretResponse, error)
+	RevokeAPIKey(ctx context.Context, authCtx authenticator.AuthContext, id int64) error
+	ResolveAPIKey(ctx context.Context, rawKey string) (*authenticator.APIKeyIdentity, error)
+}
+
+type APIKeyServiceImpl struct {
+	repo   authRepo.APIKeyRepository
+	admins AdminChecker
+}
+
+// NewAPIKeyService creates the API key service. admins may be nil, in which case
+// users can only manage their own keys.
+func NewAPIKeyService(repo authRepo.APIKeyRepository, admins AdminChecker) *APIKeyServiceImpl {
+	return &APIKeyServiceImpl{repo: repo, admins: admins}
+}
@@ -0,0 +1,305 @@
+package auth
+
+import (
+	"context"
+	authDto "ichi-go/internal/applications/auth/dto"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+// apiKeyTouchInterval limits last_used_at writes to one per key per interval.
+const apiKeyTouchInterval = time.Minute
+
+// CreateAPIKey issues a key owned by the caller, or by req.OwnerUserID for platform admins.
+// The full key is only returned here and by RotateAPIKey.
+func (s *APIKeyServiceImpl) CreateAPIKey(ctx context.Context, authCtx authenticator.AuthContext, req authDto.CreateAPIKeyRequest) (*authDto.APIKeySecretResponse, error) {
+	ownerID := authCtx.UserID.ID
+	if req.OwnerUserID != 0 && req.OwnerUserID != ownerID {
+		if err := s.requireAdmin(ctx, authCtx); err != nil {
+			return nil, err
+		}
+		ownerID = req.OwnerUserID
+	}
+	if err := validateScopes(req.Scopes); err != nil {
+		return nil, err
+	}
+	if err := validateExpiry(req.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	rawKey, prefix, err := authenticator.GenerateAPIKey()
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).
+			Hint("Failed to generate API key").
+			Wrap(err)
+	}
+
+	key := &model.APIKey{
+		Name:        req.Name,
+		Prefix:      prefix,
+		SecretHash:  authenticator.HashAPIKey(rawKey),
+		OwnerUserID: ownerID,
+		TenantID:    req.TenantID,
+		Scopes:      req.Scopes,
+		ExpiresAt:   nullTime(req.ExpiresAt),
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	logger.Infof("API key %s created for user %d by user %d", key.Prefix, ownerID, authCtx.UserID.ID)
+
+	return &authDto.APIKeySecretResponse{APIKeyResponse: toAPIKeyResponse(key), Key: rawKey}, nil
+}
+
+// ListAPIKeys returns the caller's keys that are not revoked
+func (s *APIKeyServiceImpl) ListAPIKeys(ctx context.Context, authCtx authenticator.AuthContext) ([]authDto.APIKeyResponse, error) {
+	keys, err := s.repo.FindByOwner(ctx, authCtx.UserID.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]authDto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, toAPIKeyResponse(key))
+	}
+	return result, nil
+}
+
+func (s *APIKeyServiceImpl) GetAPIKey(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*authDto.APIKeyResponse, error) {
+	key, err := s.findForCaller(ctx, authCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := toAPIKeyResponse(key)
+	return &resp, nil
+}
+
+// UpdateAPIKey renames the key or changes its scopes or expiry
+func (s *APIKeyServiceImpl) UpdateAPIKey(ctx context.Context, authCtx authenticator.AuthContext, id int64, req authDto.UpdateAPIKeyRequest) (*authDto.APIKeyResponse, error) {
+	key, err := s.findActiveForCaller(ctx, authCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != "" {
+		key.Name = req.Name
+	}
+	if len(req.Scopes) > 0 {
+		if err := validateScopes(req.Scopes); err != nil {
+			return nil, err
+		}
+		key.Scopes = req.Scopes
+	}
+	if req.ExpiresAt != nil {
+		if err := validateExpiry(req.ExpiresAt); err != nil {
+			return nil, err
+		}
+		key.ExpiresAt = nullTime(req.ExpiresAt)
+	}
+	if err := s.repo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+	resp := toAPIKeyResponse(key)
+	return &resp, nil
+}
+
+// RotateAPIKey replaces the key material while keeping name, scopes and owner.
+// The previous key stops working immediately.
+func (s *APIKeyServiceImpl) RotateAPIKey(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*authDto.APIKeySecretResponse, error) {
+	key, err := s.findActiveForCaller(ctx, authCtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, prefix, err := authenticator.GenerateAPIKey()
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).
+			Hint("Failed to generate API key").
+			Wrap(err)
+	}
+	oldPrefix := key.Prefix
+	key.Prefix = prefix
+	key.SecretHash = authenticator.HashAPIKey(rawKey)
+	if err := s.repo.Rotate(ctx, key.ID, key.Prefix, key.SecretHash); err != nil {
+		return nil, err
+	}
+	logger.Infof("API key %s rotated to %s by user %d", oldPrefix, prefix, authCtx.UserID.ID)
+
+	return &authDto.APIKeySecretResponse{APIKeyResponse: toAPIKeyResponse(key), Key: rawKey}, nil
+}
+
+// RevokeAPIKey permanently disables the key. Revoking twice is a no-op.
+func (s *APIKeyServiceImpl) RevokeAPIKey(ctx context.Context, authCtx authenticator.AuthContext, id int64) error {
+	key, err := s.findForCaller(ctx, authCtx, id)
+	if err != nil {
+		return err
+	}
+	if !key.RevokedAt.IsZero() {
+		return nil
+	}
+	if err := s.repo.Revoke(ctx, key.ID); err != nil {
+		return err
+	}
+	logger.Infof("API key %s revoked by user %d", key.Prefix, authCtx.UserID.ID)
+	return nil
+}
+
+// ResolveAPIKey authenticates a raw "ichi_" key; it backs authenticator.APIKeyConfig.Resolver
+func (s *APIKeyServiceImpl) ResolveAPIKey(ctx context.Context, rawKey string) (*authenticator.APIKeyIdentity, error) {
+	prefix, ok := authenticator.ParseAPIKeyPrefix(rawKey)
+	if !ok {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			Hint("Malformed API key").
+			Errorf("malformed API key")
+	}
+
+	key, err := s.repo.FindByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if key == nil || !authenticator.APIKeyHashMatches(rawKey, key.SecretHash) {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			With("prefix", prefix).
+			Hint("Invalid API key").
+			Errorf("API key not recognised")
+	}
+	if !key.IsActive(now) {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenRevoked).
+			With("prefix", prefix).
+			Hint("API key has been revoked or has expired").
+			Errorf("API key %s not active", prefix)
+	}
+
+	s.touchLastUsed(key, now)
+
+	return &authenticator.APIKeyIdentity{
+		ID:       key.ID,
+		Prefix:   key.Prefix,
+		Name:     key.Name,
+		OwnerID:  key.OwnerUserID,
+		TenantID: key.TenantID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+// touchLastUsed records key usage in the background so authentication does not wait on a write
+func (s *APIKeyServiceImpl) touchLastUsed(key *model.APIKey, now time.Time) {
+	if !key.LastUsedAt.IsZero() && now.Sub(key.LastUsedAt.Time) < apiKeyTouchInterval {
+		return
+	}
+	go func() {
+		if err := s.repo.TouchLastUsed(context.Background(), key.ID, now); err != nil {
+			logger.Warnf("Failed to record last use of API key %s: %v", key.Prefix, err)
+		}
+	}()
+}
+
+// findForCaller loads a key owned by the caller; platform admins may load any key.
+// Keys of other users are reported as not found.
+func (s *APIKeyServiceImpl) findForCaller(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*model.APIKey, error) {
+	key, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil && key.OwnerUserID != authCtx.UserID.ID && !s.isAdmin(ctx, authCtx) {
+		key = nil
+	}
+	if key == nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeNotFound).
+			With("user_id", authCtx.UserID.ID).
+			With("api_key_id", id).
+			Hint("API key not found").
+			Errorf("API key not found")
+	}
+	return key, nil
+}
+
+func (s *APIKeyServiceImpl) findActiveForCaller(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*model.APIKey, error) {
+	key, err := s.findForCaller(ctx, authCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !key.RevokedAt.IsZero() {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeNotFound).
+			With("api_key_id", id).
+			Hint("API key has been revoked").
+			Errorf("API key %d revoked", id)
+	}
+	return key, nil
+}
+
+func (s *APIKeyServiceImpl) requireAdmin(ctx context.Context, authCtx authenticator.AuthContext) error {
+	if s.isAdmin(ctx, authCtx) {
+		return nil
+	}
+	return pkgErrors.AuthService(pkgErrors.ErrCodeForbidden).
+		With("user_id", authCtx.UserID.ID).
+		Hint("Only platform admins can issue API keys for other users").
+		Errorf("user %d is not a platform admin", authCtx.UserID.ID)
+}
+
+func (s *APIKeyServiceImpl) isAdmin(ctx context.Context, authCtx authenticator.AuthContext) bool {
+	if s.admins == nil {
+		return false
+	}
+	ok, err := s.admins.IsPlatformAdmin(ctx, int64(authCtx.UserID.ID))
+	if err != nil {
+		logger.Warnf("Platform admin check failed for user %d: %v", authCtx.UserID.ID, err)
+		return false
+	}
+	return ok
+}
+
+func validateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !authenticator.ValidScope(scope) {
+			return pkgErrors.AuthService(pkgErrors.ErrCodeValidation).
+				With("scope", scope).
+				Hint(`Scopes must be "*" or "resource:action", e.g. "orders:view" or "orders:*"`).
+				Errorf("invalid scope %q", scope)
+		}
+	}
+	return nil
+}
+
+func validateExpiry(expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeValidation).
+			Hint("expires_at must be in the future").
+			Errorf("API key expiry %s is in the past", expiresAt)
+	}
+	return nil
+}
+
+func nullTime(t *time.Time) upbun.NullTime {
+	if t == nil {
+		return upbun.NullTime{}
+	}
+	return upbun.NullTime{Time: *t}
+}
+
+func timePtr(t upbun.NullTime) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t.Time
+}
+
+func toAPIKeyResponse(key *model.APIKey) authDto.APIKeyResponse {
+	return authDto.APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		OwnerUserID: key.OwnerUserID,
+		TenantID:    key.TenantID,
+		Scopes:      key.Scopes,
+		ExpiresAt:   timePtr(key.ExpiresAt),
+		LastUsedAt:  timePtr(key.LastUsedAt),
+		RevokedAt:   timePtr(key.RevokedAt),
+		CreatedAt:   key.CreatedAt,
+	}
+}
@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	authDto "ichi-go/internal/applications/auth/dto"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	upbun "github.com/uptrace/bun"
+)
+
+type fakeAPIKeyRepository struct {
+	mu     sync.Mutex
+	keys   map[int64]*model.APIKey
+	nextID int64
+}
+
+func newFakeAPIKeyRepository() *fakeAPIKeyRepository {
+	return &fakeAPIKeyRepository{keys: make(map[int64]*model.APIKey)}
+}
+
+func (f *fakeAPIKeyRepository) Create(_ context.Context, key *model.APIKey) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	key.ID = f.nextID
+	key.CreatedAt = time.Now()
+	stored := *key
+	f.keys[key.ID] = &stored
+	return nil
+}
+
+func (f *fakeAPIKeyRepository) FindByID(_ context.Context, id int64) (*model.APIKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if key, ok := f.keys[id]; ok {
+		found := *key
+		return &found, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeAPIKeyRepository) FindByPrefix(_ context.Context, prefix string) (*model.APIKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range f.keys {
+		if key.Prefix == prefix {
+			found := *key
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeAPIKeyRepository) FindByOwner(_ context.Context, ownerUserID uint64) ([]*model.APIKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []*model.APIKey
+	for _, key := range f.keys {
+		if key.OwnerUserID == ownerUserID && key.RevokedAt.IsZero() {
+			found := *key
+			keys = append(keys, &found)
+		}
+	}
+	return keys, nil
+}
+
+func (f *fakeAPIKeyRepository) Update(_ context.Context, key *model.APIKey) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := f.keys[key.ID]
+	stored.Name, stored.Scopes, stored.ExpiresAt = key.Name, key.Scopes, key.ExpiresAt
+	return nil
+}
+
+func (f *fakeAPIKeyRepository) Rotate(_ context.Context, id int64, prefix, secretHash string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[id].Prefix, f.keys[id].SecretHash = prefix, secretHash
+	return nil
+}
+
+func (f *fakeAPIKeyRepository) Revoke(_ context.Context, id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[id].RevokedAt = upbun.NullTime{Time: time.Now()}
+	return nil
+}
+
+func (f *fakeAPIKeyRepository) TouchLastUsed(_ context.Context, id int64, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[id].LastUsedAt = upbun.NullTime{Time: at}
+	return nil
+}
+
+func (f *fakeAPIKeyRepository) lastUsed(id int64) time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.keys[id].LastUsedAt.Time
+}
+
+const (
+	apiKeyOwnerID = uint64(10)
+	apiKeyAdminID = uint64(99)
+)
+
+func ownerCtx(id uint64) authenticator.AuthContext {
+	return authenticator.AuthContext{UserID: authenticator.UserSubject{ID: id}}
+}
+
+func newAPIKeyTestService() (*APIKeyServiceImpl, *fakeAPIKeyRepository) {
+	repo := newFakeAPIKeyRepository()
+	return NewAPIKeyService(repo, fakeAdmins{int64(apiKeyAdminID): true}), repo
+}
+
+func createTestAPIKey(t *testing.T, svc *APIKeyServiceImpl) *authDto.APIKeySecretResponse {
+	t.Helper()
+	created, err := svc.CreateAPIKey(context.Background(), ownerCtx(apiKeyOwnerID), authDto.CreateAPIKeyRequest{
+		Name:     "Acme",
+		Scopes:   []string{"orders:view", "products:*"},
+		TenantID: "acme",
+	})
+	require.NoError(t, err)
+	return created
+}
+
+func assertErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+	require.Error(t, err)
+	oopsErr, ok := oops.AsOops(err)
+	require.True(t, ok, "expected an oops error, got %v", err)
+	assert.Equal(t, code, oopsErr.Code())
+}
+
+func TestCreateAPIKey_ResolvesToOwnerIdentity(t *testing.T) {
+	svc, repo := newAPIKeyTestService()
+	created := createTestAPIKey(t, svc)
+
+	assert.True(t, len(created.Key) > len(created.Prefix))
+	assert.Equal(t, apiKeyOwnerID, created.OwnerUserID)
+	stored, _ := repo.FindByID(context.Background(), created.ID)
+	assert.NotContains(t, stored.SecretHash, created.Key, "raw key must not be stored")
+
+	identity, err := svc.ResolveAPIKey(context.Background(), created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, identity.ID)
+	assert.Equal(t, apiKeyOwnerID, identity.OwnerID)
+	assert.Equal(t, "acme", identity.TenantID)
+	assert.Equal(t, []string{"orders:view", "products:*"}, identity.Scopes)
+
+	assert.Eventually(t, func() bool { return !repo.lastUsed(created.ID).IsZero() },
+		time.Second, 10*time.Millisecond, "last_used_at should be recorded")
+}
+
+func TestCreateAPIKey_RejectsInvalidScopeAndPastExpiry(t *testing.T) {
+	svc, _ := newAPIKeyTestService()
+	ctx := context.Background()
+
+	_, err := svc.CreateAPIKey(ctx, ownerCtx(apiKeyOwnerID), authDto.CreateAPIKeyRequest{
+		Name: "bad", Scopes: []string{"orders"},
+	})
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+
+	past := time.Now().Add(-time.Hour)
+	_, err = svc.CreateAPIKey(ctx, ownerCtx(apiKeyOwnerID), authDto.CreateAPIKeyRequest{
+		Name: "bad", Scopes: []string{"*"}, ExpiresAt: &past,
+	})
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+}
+
+func TestCreateAPIKey_OnBehalfOfOtherUserRequiresAdmin(t *testing.T) {
+	svc, _ := newAPIKeyTestService()
+	req := authDto.CreateAPIKeyRequest{Name: "partner", Scopes: []string{"*"}, OwnerUserID: 55}
+
+	_, err := svc.CreateAPIKey(context.Background(), ownerCtx(apiKeyOwnerID), req)
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+
+	created, err := svc.CreateAPIKey(context.Background(), ownerCtx(apiKeyAdminID), req)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(55), created.OwnerUserID)
+}
+
+func TestResolveAPIKey_RejectsUnknownTamperedAndExpiredKeys(t *testing.T) {
+	svc, repo := newAPIKeyTestService()
+	ctx := context.Background()
+	created := createTestAPIKey(t, svc)
+
+	_, err := svc.ResolveAPIKey(ctx, "not-a-key")
+	assertErrorCode(t, err, pkgErrors.ErrCodeInvalidToken)
+
+	_, err = svc.ResolveAPIKey(ctx, created.Key+"x")
+	assertErrorCode(t, err, pkgErrors.ErrCodeInvalidToken)
+
+	repo.keys[created.ID].ExpiresAt = upbun.NullTime{Time: time.Now().Add(-time.Minute)}
+	_, err = svc.ResolveAPIKey(ctx, created.Key)
+	assertErrorCode(t, err, pkgErrors.ErrCodeTokenRevoked)
+}
+
+func TestRotateAPIKey_InvalidatesPreviousKey(t *testing.T) {
+	svc, _ := newAPIKeyTestService()
+	ctx := context.Background()
+	created := createTestAPIKey(t, svc)
+
+	rotated, err := svc.RotateAPIKey(ctx, ownerCtx(apiKeyOwnerID), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, rotated.ID)
+	assert.NotEqual(t, created.Key, rotated.Key)
+	assert.Equal(t, created.Scopes, rotated.Scopes)
+
+	_, err = svc.ResolveAPIKey(ctx, created.Key)
+	assert.Error(t, err)
+	_, err = svc.ResolveAPIKey(ctx, rotated.Key)
+	assert.NoError(t, err)
+}
+
+func TestRevokeAPIKey_StopsAuthentication(t *testing.T) {
+	svc, _ := newAPIKeyTestService()
+	ctx := context.Background()
+	created := createTestAPIKey(t, svc)
+
+	require.NoError(t, svc.RevokeAPIKey(ctx, ownerCtx(apiKeyOwnerID), created.ID))
+	require.NoError(t, svc.RevokeAPIKey(ctx, ownerCtx(apiKeyOwnerID), created.ID), "revoking twice is a no-op")
+
+	_, err := svc.ResolveAPIKey(ctx, created.Key)
+	assertErrorCode(t, err, pkgErrors.ErrCodeTokenRevoked)
+
+	keys, err := svc.ListAPIKeys(ctx, ownerCtx(apiKeyOwnerID))
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	_, err = svc.RotateAPIKey(ctx, ownerCtx(apiKeyOwnerID), created.ID)
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+}
+
+func TestAPIKey_OtherUsersKeysAreHiddenExceptFromAdmins(t *testing.T) {
+	svc, _ := newAPIKeyTestService()
+	ctx := context.Background()
+	created := createTestAPIKey(t, svc)
+
+	_, err := svc.GetAPIKey(ctx, ownerCtx(11), created.ID)
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+	err = svc.RevokeAPIKey(ctx, ownerCtx(11), created.ID)
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+
+	// Platform admins can revoke a partner's key.
+	require.NoError(t, svc.RevokeAPIKey(ctx, ownerCtx(apiKeyAdminID), created.ID))
+}
+
+func TestUpdateAPIKey_KeepsOmittedFields(t *testing.T) {
+	svc, _ := newAPIKeyTestService()
+	ctx := context.Background()
+	created := createTestAPIKey(t, svc)
+
+	updated, err := svc.UpdateAPIKey(ctx, ownerCtx(apiKeyOwnerID), created.ID, authDto.UpdateAPIKeyRequest{
+		Scopes: []string{"orders:view"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", updated.Name)
+	assert.Equal(t, []string{"orders:view"}, updated.Scopes)
+
+	identity, err := svc.ResolveAPIKey(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders:view"}, identity.Scopes)
+}
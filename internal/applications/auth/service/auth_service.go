@@ -3,31 +3,119 @@ package auth
 import (
 	"context"
 	authDto "ichi-go/internal/applications/auth/dto"
+	authRepo "ichi-go/internal/applications/auth/repository"
+	rbacModels "ichi-go/internal/applications/rbac/models"
 	userRepo "ichi-go/internal/applications/user/repository"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	"ichi-go/pkg/authenticator"
 	"ichi-go/pkg/db/model"
+	"sync"
 )
 
 type Service interface {
 	Login(ctx context.Context, req authDto.LoginRequest) (*authDto.LoginResponse, error)
 	Register(ctx context.Context, req authDto.RegisterRequest) (*authDto.RegisterResponse, error)
 	RefreshToken(ctx context.Context, req authDto.RefreshTokenRequest) (*authDto.RefreshTokenResponse, error)
+	Logout(ctx context.Context, authCtx authenticator.AuthContext, req authDto.LogoutRequest) error
+	LogoutAll(ctx context.Context, authCtx authenticator.AuthContext) error
+	ListSessions(ctx context.Context, authCtx authenticator.AuthContext) ([]authDto.SessionResponse, error)
+	RevokeSession(ctx context.Context, authCtx authenticator.AuthContext, sessionID int64) error
+	ChangePassword(ctx context.Context, authCtx authenticator.AuthContext, req authDto.ChangePasswordRequest) error
+	ForgotPassword(ctx context.Context, req authDto.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req authDto.ResetPasswordRequest) error
+	UnlockLogin(ctx context.Context, authCtx authenticator.AuthContext, req authDto.UnlockLoginRequest) error
+	OAuthAuthorize(ctx context.Context, provider string) (string, error)
+	OAuthCallback(ctx context.Context, provider string, req authDto.OAuthCallbackRequest) (*authDto.LoginResponse, error)
+	VerifyMFA(ctx context.Context, req authDto.MFAVerifyRequest) (*authDto.LoginResponse, error)
+	SetupMFA(ctx context.Context, req authDto.MFASetupRequest) (*authDto.MFAEnrollResponse, error)
+	ConfirmMFASetup(ctx context.Context, req authDto.MFASetupConfirmRequest) (*authDto.LoginResponse, error)
+	MFAStatus(ctx context.Context, authCtx authenticator.AuthContext) (*authDto.MFAStatusResponse, error)
+	EnrollMFA(ctx context.Context, authCtx authenticator.AuthContext) (*authDto.MFAEnrollResponse, error)
+	ConfirmMFA(ctx context.Context, authCtx authenticator.AuthContext, req authDto.MFACodeRequest) (*authDto.MFARecoveryCodesResponse, error)
+	DisableMFA(ctx context.Context, authCtx authenticator.AuthContext, req authDto.MFADisableRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, authCtx authenticator.AuthContext, req authDto.MFACodeRequest) (*authDto.MFARecoveryCodesResponse, error)
+	VerifyEmail(ctx context.Context, req authDto.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, req authDto.ResendVerificationRequest) error
 	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
 	VerifyPassword(hashedPassword, password string) bool
 	Me(ctx context.Context, userId uint64) (*authDto.UserInfo, error)
 }
 
+// AuditLogWriter persists security events; satisfied by the RBAC AuditRepository
+type AuditLogWriter interface {
+	Create(ctx context.Context, log *rbacModels.AuditLog) error
+}
+
+// AdminChecker decides who may use admin endpoints; satisfied by the RBAC PlatformRepository
+type AdminChecker interface {
+	IsPlatformAdmin(ctx context.Context, userID int64) (bool, error)
+}
+
+// MFAPolicy decides who must use multi-factor authentication; satisfied by the RBAC MFAPolicyService
+type MFAPolicy interface {
+	RequiresMFA(ctx context.Context, userID int64) (bool, error)
+}
+
+// Options holds the optional collaborators of the auth service
+type Options struct {
+	PasswordReset *authenticator.PasswordResetConfig
+	ResetLimiter  *authenticator.ResendLimiter // nil leaves forgot-password requests unlimited
+	LoginThrottle *authenticator.LoginThrottle // nil disables brute-force protection
+	AuditLog      AuditLogWriter               // nil skips security audit events
+	Admins        AdminChecker                 // nil rejects every admin request
+	OIDC          *authenticator.OIDC          // nil disables OpenID Connect login
+	Identities    authRepo.UserIdentityRepository
+	MFA           *authenticator.MFA // nil disables multi-factor authentication
+	MFARepo       authRepo.MFARepository
+	MFAPolicy     MFAPolicy                    // nil forces MFA on nobody
+	EmailVerifier *authenticator.EmailVerifier // nil disables email verification
+}
+
 type ServiceImpl struct {
-	userRepo userRepo.Repository
-	jwtAuth  *authenticator.JWTAuthenticator
-	producer rabbitmq.MessageProducer
+	userRepo      userRepo.Repository
+	sessionRepo   authRepo.SessionRepository
+	resetRepo     authRepo.PasswordResetRepository
+	jwtAuth       *authenticator.JWTAuthenticator
+	producer      rabbitmq.MessageProducer
+	resetConfig   authenticator.PasswordResetConfig
+	resetLimiter  *authenticator.ResendLimiter
+	loginThrottle *authenticator.LoginThrottle
+	auditLog      AuditLogWriter
+	admins        AdminChecker
+	oidc          *authenticator.OIDC
+	identities    authRepo.UserIdentityRepository
+	mfa           *authenticator.MFA
+	mfaRepo       authRepo.MFARepository
+	mfaPolicy     MFAPolicy
+	emailVerifier *authenticator.EmailVerifier
+
+	// background tracks work finishing after the request returned, e.g. reset emails.
+	background sync.WaitGroup
 }
 
-func NewAuthService(userRepo userRepo.Repository, jwtAuth *authenticator.JWTAuthenticator, producer rabbitmq.MessageProducer) *ServiceImpl {
-	return &ServiceImpl{
-		userRepo: userRepo,
-		jwtAuth:  jwtAuth,
-		producer: producer,
+func NewAuthService(userRepo userRepo.Repository, sessionRepo authRepo.SessionRepository, resetRepo authRepo.PasswordResetRepository, jwtAuth *authenticator.JWTAuthenticator, producer rabbitmq.MessageProducer, opts Options) *ServiceImpl {
+	svc := &ServiceImpl{
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		resetRepo:     resetRepo,
+		jwtAuth:       jwtAuth,
+		producer:      producer,
+		resetLimiter:  opts.ResetLimiter,
+		loginThrottle: opts.LoginThrottle,
+		auditLog:      opts.AuditLog,
+		admins:        opts.Admins,
+		oidc:          opts.OIDC,
+		identities:    opts.Identities,
+		mfa:           opts.MFA,
+		mfaRepo:       opts.MFARepo,
+		mfaPolicy:     opts.MFAPolicy,
+		emailVerifier: opts.EmailVerifier,
+	}
+	if opts.PasswordReset != nil {
+		svc.resetConfig = *opts.PasswordReset
+	}
+	if svc.resetConfig.TokenTTL <= 0 {
+		svc.resetConfig.TokenTTL = defaultPasswordResetTTL
 	}
+	return svc
 }
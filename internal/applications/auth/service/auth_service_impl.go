@@ -2,62 +2,34 @@ package auth
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	authDto "ichi-go/internal/applications/auth/dto"
+	authRepo "ichi-go/internal/applications/auth/repository"
 	userDto "ichi-go/internal/applications/user/dto"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	"ichi-go/pkg/authenticator"
 	"ichi-go/pkg/db/model"
 	pkgErrors "ichi-go/pkg/errors"
 	"ichi-go/pkg/logger"
+	"ichi-go/pkg/requestctx"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Login authenticates user and returns tokens
+// Login authenticates user and returns tokens, or an MFA challenge when a second factor is needed
 func (s *ServiceImpl) Login(ctx context.Context, req authDto.LoginRequest) (*authDto.LoginResponse, error) {
-	user, err := s.GetUserByEmail(ctx, req.Email)
-	if err != nil || user == nil {
-		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeUserNotFound).
-			With("email", req.Email).
-			Hint("Invalid email or password").
-			Errorf("user not found")
-	}
-
-	if !s.VerifyPassword(user.Password, req.Password) {
-		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidCredentials).
-			With("email", req.Email).
-			With("user_id", user.ID).
-			Hint("Invalid email or password").
-			Errorf("password verification failed")
-	}
-
-	tokenPair, err := s.jwtAuth.GenerateTokens(uint64(user.ID))
+	user, err := s.AuthenticatePassword(ctx, req.Email, req.Password)
 	if err != nil {
-		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).
-			With("user_id", user.ID).
-			Hint("Failed to generate authentication tokens").
-			Wrap(err)
+		return nil, err
 	}
 
-	response := &authDto.LoginResponse{
-		User: authDto.UserInfo{
-			ID:        uint64(user.ID),
-			Name:      user.Name,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt,
-		},
-		AccessToken:  tokenPair.AccessToken,
-		RefreshToken: tokenPair.RefreshToken,
-		TokenType:    tokenPair.TokenType,
-		ExpiresIn:    tokenPair.ExpiresIn,
-	}
-
-	return response, nil
+	return s.completeLogin(ctx, user)
 }
 
-// Register creates new user and returns tokens
+// Register creates new user and returns tokens, unless the email must be verified first
 func (s *ServiceImpl) Register(ctx context.Context, req authDto.RegisterRequest) (*authDto.RegisterResponse, error) {
 	existingUser, _ := s.GetUserByEmail(ctx, req.Email)
 	if existingUser != nil {
@@ -97,25 +69,32 @@ func (s *ServiceImpl) Register(ctx context.Context, req authDto.RegisterRequest)
 			Wrap(err)
 	}
 
-	tokenPair, err := s.jwtAuth.GenerateTokens(uint64(user.ID))
-	if err != nil {
-		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).
-			With("user_id", user.ID).
-			Hint("Failed to generate authentication tokens").
-			Wrap(err)
+	response := &authDto.RegisterResponse{User: userInfo(user)}
+
+	if s.emailVerifier != nil {
+		if err := s.issueEmailVerification(ctx, user); err != nil {
+			logger.Errorf("%v", pkgErrors.AuthService(pkgErrors.ErrCodeNotificationFailed).
+				With("user_id", userID).
+				Hint("Verification email could not be sent").
+				Wrap(err))
+		}
 	}
 
-	response := &authDto.RegisterResponse{
-		User: authDto.UserInfo{
-			ID:        uint64(user.ID),
-			Name:      user.Name,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt,
-		},
-		AccessToken:  tokenPair.AccessToken,
-		RefreshToken: tokenPair.RefreshToken,
-		TokenType:    tokenPair.TokenType,
-		ExpiresIn:    tokenPair.ExpiresIn,
+	// Users who must verify their email before logging in get no tokens yet
+	if s.emailVerifier != nil && s.emailVerifier.RequireForLogin() {
+		response.EmailVerificationRequired = true
+	} else {
+		tokenPair, err := s.startSession(ctx, uint64(user.ID))
+		if err != nil {
+			return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).
+				With("user_id", user.ID).
+				Hint("Failed to generate authentication tokens").
+				Wrap(err)
+		}
+		response.AccessToken = tokenPair.AccessToken
+		response.RefreshToken = tokenPair.RefreshToken
+		response.TokenType = tokenPair.TokenType
+		response.ExpiresIn = tokenPair.ExpiresIn
 	}
 
 	if err := s.EnqueueWelcomeNotification(ctx, uint32(userID)); err != nil {
@@ -128,15 +107,24 @@ func (s *ServiceImpl) Register(ctx context.Context, req authDto.RegisterRequest)
 	return response, nil
 }
 
-// RefreshToken validates refresh token and generates new token pair
+// RefreshToken rotates the refresh token of a session and issues a new token pair.
+// Presenting a refresh token that was already rotated revokes the whole session.
 func (s *ServiceImpl) RefreshToken(ctx context.Context, req authDto.RefreshTokenRequest) (*authDto.RefreshTokenResponse, error) {
-	userID, err := s.jwtAuth.ValidateRefreshToken(req.RefreshToken)
+	claims, err := s.jwtAuth.ParseRefreshToken(ctx, req.RefreshToken)
 	if err != nil {
 		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
 			Hint("Invalid or expired refresh token").
 			Wrap(err)
 	}
 
+	subject, err := authenticator.GetUserIdFromMapClaims(claims)
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			Hint("Invalid or expired refresh token").
+			Wrap(err)
+	}
+	userID := subject.ID
+
 	user, err := s.userRepo.GetById(ctx, userID)
 	if err != nil || user == nil {
 		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeUserNotFound).
@@ -145,13 +133,20 @@ func (s *ServiceImpl) RefreshToken(ctx context.Context, req authDto.RefreshToken
 			Errorf("user not found")
 	}
 
-	tokenPair, err := s.jwtAuth.GenerateTokens(userID)
+	// Refresh tokens issued before session tracking carry no sid: they cannot be
+	// rotated, so their users log in again.
+	sessionID, err := authenticator.GetClaimString(claims, authenticator.SessionIDClaim)
 	if err != nil {
-		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
 			With("user_id", userID).
-			Hint("Failed to generate new tokens").
+			Hint("Session has ended, please log in again").
 			Wrap(err)
 	}
+	jti, _ := authenticator.GetClaimString(claims, "jti")
+	tokenPair, err := s.rotateSession(ctx, userID, sessionID, jti)
+	if err != nil {
+		return nil, err
+	}
 
 	return &authDto.RefreshTokenResponse{
 		AccessToken:  tokenPair.AccessToken,
@@ -161,6 +156,126 @@ func (s *ServiceImpl) RefreshToken(ctx context.Context, req authDto.RefreshToken
 	}, nil
 }
 
+// Logout ends the session of the current request, revokes its access token and,
+// when given, the refresh token issued alongside it
+func (s *ServiceImpl) Logout(ctx context.Context, authCtx authenticator.AuthContext, req authDto.LogoutRequest) error {
+	userID := authCtx.UserID.ID
+
+	if sessionID, err := authenticator.GetClaimString(authCtx.Claims, authenticator.SessionIDClaim); err == nil {
+		if err := s.endSession(ctx, userID, sessionID); err != nil {
+			return err
+		}
+	}
+
+	if !s.jwtAuth.RevocationEnabled() {
+		logger.Warnf("Token revocation not configured - access token of user %d stays valid until it expires", userID)
+		return nil
+	}
+
+	if req.RefreshToken != "" {
+		if err := s.jwtAuth.RevokeTokenString(ctx, req.RefreshToken, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.jwtAuth.RevokeToken(ctx, authCtx.Claims); err != nil {
+		return err
+	}
+
+	logger.Infof("User %d logged out", userID)
+	return nil
+}
+
+// LogoutAll ends every session of the current user and revokes all tokens issued to them
+func (s *ServiceImpl) LogoutAll(ctx context.Context, authCtx authenticator.AuthContext) error {
+	userID := authCtx.UserID.ID
+
+	if err := s.sessionRepo.RevokeAllForUser(ctx, userID, authRepo.RevokedReasonLogoutAll); err != nil {
+		return err
+	}
+
+	if !s.jwtAuth.RevocationEnabled() {
+		logger.Warnf("Token revocation not configured - access tokens of user %d stay valid until they expire", userID)
+		return nil
+	}
+
+	if err := s.jwtAuth.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	logger.Infof("User %d logged out of all sessions", userID)
+	return nil
+}
+
+// SignOutDeletedUser ends every session of a user removed by an administrator and
+// invalidates their outstanding access tokens.
+func (s *ServiceImpl) SignOutDeletedUser(ctx context.Context, userID uint64) error {
+	if err := s.sessionRepo.RevokeAllForUser(ctx, userID, authRepo.RevokedReasonUserDeleted); err != nil {
+		return err
+	}
+	if !s.jwtAuth.RevocationEnabled() {
+		logger.Warnf("Token revocation not configured - access tokens of deleted user %d stay valid until they expire", userID)
+		return nil
+	}
+	return s.jwtAuth.RevokeAllForUser(ctx, userID)
+}
+
+// AuthenticatePassword checks an email/password pair with brute-force protection.
+// Used by Login and by HTTP Basic auth backed by the users table.
+func (s *ServiceImpl) AuthenticatePassword(ctx context.Context, email, password string) (*model.User, error) {
+	clientIP := requestctx.FromContext(ctx).ClientIP
+	if err := s.checkLoginAllowed(ctx, email, clientIP); err != nil {
+		return nil, err
+	}
+
+	user, err := s.GetUserByEmail(ctx, email)
+	if err != nil || user == nil {
+		// Unknown emails count too, so lockouts do not reveal which accounts exist.
+		if err == nil || errors.Is(err, sql.ErrNoRows) {
+			s.recordLoginFailure(ctx, email, clientIP, nil)
+		}
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeUserNotFound).
+			With("email", email).
+			Hint("Invalid email or password").
+			Errorf("user not found")
+	}
+
+	if !s.VerifyPassword(user.Password, password) {
+		s.recordLoginFailure(ctx, email, clientIP, user)
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidCredentials).
+			With("email", email).
+			With("user_id", user.ID).
+			Hint("Invalid email or password").
+			Errorf("password verification failed")
+	}
+
+	s.recordLoginSuccess(ctx, email)
+	return user, nil
+}
+
+// AuthenticateBasic checks HTTP Basic credentials. Basic auth has no second step, so
+// users who must pass MFA are refused, as are logins refused for an unverified email.
+func (s *ServiceImpl) AuthenticateBasic(ctx context.Context, email, password string) (*model.User, error) {
+	user, err := s.AuthenticatePassword(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEmailVerifiedForLogin(user); err != nil {
+		return nil, err
+	}
+	needed, _, err := s.secondFactor(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if needed {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeForbidden).
+			With("user_id", user.ID).
+			Hint("Accounts with multi-factor authentication cannot use Basic auth").
+			Errorf("basic auth refused: mfa required")
+	}
+	return user, nil
+}
+
 // GetUserByEmail retrieves user by email address
 func (s *ServiceImpl) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	return s.userRepo.FindByEmail(ctx, email)
@@ -199,12 +314,18 @@ func (s *ServiceImpl) Me(ctx context.Context, authCtx authenticator.AuthContext)
 			Errorf("user not found")
 	}
 
-	return &authDto.UserInfo{
-		ID:        uint64(user.ID),
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	info := userInfo(user)
+	return &info, nil
+}
+
+func userInfo(user *model.User) authDto.UserInfo {
+	return authDto.UserInfo{
+		ID:            uint64(user.ID),
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.IsEmailVerified(),
+		CreatedAt:     user.CreatedAt,
+	}
 }
 
 // EnqueueWelcomeNotification PublishWelcomeNotification Producer publishes message to queue
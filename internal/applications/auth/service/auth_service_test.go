@@ -57,6 +57,47 @@ func (m *MockUserRepository) Update(ctx context.Context, user dbModel.User) (int
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id uint64, email string) (bool, error) {
+	args := m.Called(ctx, id, email)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) SetAvatar(ctx context.Context, id uint64, path string) error {
+	args := m.Called(ctx, id, path)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, filter userRepo.ListFilter) ([]*dbModel.User, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*dbModel.User), args.Int(1), args.Error(2)
+}
+
+func (m *MockUserRepository) GetByIdWithDeleted(ctx context.Context, id uint64) (*dbModel.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dbModel.User), args.Error(1)
+}
+
+func (m *MockUserRepository) SoftDelete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Restore(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) HardDelete(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Delete(ctx context.Context, id uint64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	authDto "ichi-go/internal/applications/auth/dto"
+	notifDto "ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/template/builtin"
+	"ichi-go/pkg/requestctx"
+	"math"
+	"time"
+)
+
+// VerifyEmail redeems a verification token. A token only works while the address it was
+// issued for is still the user's unverified email, which also makes it single-use.
+func (s *ServiceImpl) VerifyEmail(ctx context.Context, req authDto.VerifyEmailRequest) error {
+	if s.emailVerifier == nil {
+		return emailVerificationDisabledError()
+	}
+
+	userID, err := s.emailVerifier.TokenUserID(req.Token)
+	if err != nil {
+		return invalidVerificationTokenError(err)
+	}
+	user, err := s.userRepo.GetById(ctx, userID)
+	if err != nil || user == nil {
+		return invalidVerificationTokenError(authenticator.ErrInvalidVerificationToken)
+	}
+	if err := s.emailVerifier.Verify(req.Token, user.Email); err != nil {
+		return invalidVerificationTokenError(err)
+	}
+
+	// The conditional update redeems the token; concurrent requests cannot both succeed.
+	verified, err := s.userRepo.MarkEmailVerified(ctx, userID, user.Email)
+	if err != nil {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeDatabase).
+			With("user_id", userID).
+			Hint("Failed to verify email").
+			Wrap(err)
+	}
+	if !verified {
+		return invalidVerificationTokenError(authenticator.ErrInvalidVerificationToken)
+	}
+
+	logger.Infof("User %d verified email", userID)
+	return nil
+}
+
+// ResendVerification emails a new verification link. Like ForgotPassword it succeeds for
+// unknown and already verified addresses so callers cannot probe for accounts; only
+// exceeding the resend limit is reported.
+func (s *ServiceImpl) ResendVerification(ctx context.Context, req authDto.ResendVerificationRequest) error {
+	if s.emailVerifier == nil {
+		return emailVerificationDisabledError()
+	}
+
+	if err := s.checkResendLimit(ctx, req.Email); err != nil {
+		return err
+	}
+
+	user, err := s.GetUserByEmail(ctx, req.Email)
+	if err != nil || user == nil {
+		logger.Debugf("Verification email requested for unknown email")
+		return nil
+	}
+	if user.IsEmailVerified() {
+		logger.Debugf("Verification email requested for verified user %d", user.ID)
+		return nil
+	}
+
+	if err := s.issueEmailVerification(ctx, user); err != nil {
+		logger.Errorf("%v", pkgErrors.AuthService(pkgErrors.ErrCodeNotificationFailed).
+			With("user_id", user.ID).
+			Hint("Verification email could not be sent").
+			Wrap(err))
+	}
+	return nil
+}
+
+// checkEmailVerifiedForLogin rejects the login of an unverified user when verification
+// is required before login.
+func (s *ServiceImpl) checkEmailVerifiedForLogin(user *model.User) error {
+	if s.emailVerifier == nil || !s.emailVerifier.RequireForLogin() || user.IsEmailVerified() {
+		return nil
+	}
+	return pkgErrors.AuthService(pkgErrors.ErrCodeEmailNotVerified).
+		With("user_id", user.ID).
+		Hint("Please verify your email address first, or request a new link at /auth/verify-email/resend").
+		Errorf("email not verified")
+}
+
+// checkResendLimit applies the resend limits per address and per client IP.
+func (s *ServiceImpl) checkResendLimit(ctx context.Context, email string) error {
+	return checkEmailLimit(ctx, s.emailVerifier.AllowResend, email, "verification emails")
+}
+
+// checkEmailLimit applies an email send limit per address and per client IP. An
+// unavailable limiter lets the request through, like the login throttle.
+func checkEmailLimit(ctx context.Context, allow func(context.Context, string) (time.Duration, error), email, what string) error {
+	keys := []string{"email:" + hashEmail(email)}
+	if ip := requestctx.FromContext(ctx).ClientIP; ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+
+	for _, key := range keys {
+		retryAfter, err := allow(ctx, key)
+		if err != nil {
+			logger.Warnf("Limit of %s unavailable, allowing request: %v", what, err)
+			return nil
+		}
+		if retryAfter > 0 {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			return pkgErrors.AuthService(pkgErrors.ErrCodeRateLimited).
+				With("retry_after_seconds", seconds).
+				Hint(fmt.Sprintf("Too many %s requested, try again in %d seconds", what, seconds)).
+				Errorf("%s rate limited", what)
+		}
+	}
+	return nil
+}
+
+// issueEmailVerification publishes the user.verify_email notification carrying a
+// fresh verification token
+func (s *ServiceImpl) issueEmailVerification(ctx context.Context, user *model.User) error {
+	if s.producer == nil {
+		return fmt.Errorf("queue not configured")
+	}
+
+	token := s.emailVerifier.IssueToken(uint64(user.ID), user.Email)
+	event := notifDto.NotificationEvent{
+		EventID:      fmt.Sprintf("verify-email-%d-%d", user.ID, time.Now().UnixNano()),
+		EventType:    builtin.VerifyEmailTemplate{}.Slug(),
+		DeliveryMode: notifDto.DeliveryModeUser,
+		Channels:     []notifDto.Channel{notifDto.ChannelEmail},
+		UserID:       fmt.Sprintf("%d", user.ID),
+		Data: map[string]any{
+			"name":       user.Name,
+			"email":      user.Email,
+			"verify_url": tokenURL(s.emailVerifier.VerifyURL(), token),
+			"expires_in": formatTokenTTL(s.emailVerifier.TokenTTL()),
+		},
+		Meta: map[string]string{"source": "auth"},
+	}
+	return s.producer.Publish(ctx, notificationRoutingKey, event, rabbitmq.PublishOptions{})
+}
+
+func emailVerificationDisabledError() error {
+	return pkgErrors.AuthService(pkgErrors.ErrCodeNotFound).
+		Hint("Email verification is not enabled on this server").
+		Errorf("email verification disabled")
+}
+
+func invalidVerificationTokenError(err error) error {
+	hint := "Verification link is invalid or was already used"
+	if errors.Is(err, authenticator.ErrVerificationTokenExpired) {
+		hint = "Verification link has expired, please request a new one"
+	}
+	return pkgErrors.AuthService(pkgErrors.ErrCodeVerifyTokenInvalid).
+		Hint(hint).
+		Wrap(err)
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	authDto "ichi-go/internal/applications/auth/dto"
+	notifDto "ichi-go/internal/applications/notification/dto"
+	"ichi-go/pkg/authenticator"
+	pkgErrors "ichi-go/pkg/errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type verificationTestEnv struct {
+	svc   *ServiceImpl
+	users *fakeUserRepository
+
+	mu     sync.Mutex
+	tokens []string
+}
+
+func newVerificationTestService(t *testing.T) *verificationTestEnv {
+	t.Helper()
+
+	verifier, err := authenticator.NewEmailVerifier(authenticator.EmailVerificationConfig{
+		Enabled:         true,
+		SigningKey:      "0123456789abcdef0123456789abcdef",
+		VerifyURL:       "https://app.example.com/verify-email",
+		RequireForLogin: true,
+		ResendLimit:     2,
+	}, authenticator.NewMemoryRateLimitStore())
+	require.NoError(t, err)
+
+	jwtAuth := authenticator.NewJWTAuthenticator(&authenticator.JWTConfig{
+		SigningMethod:   jwt.SigningMethodHS256,
+		SecretKey:       []byte("test-secret-key-minimum-32-chars-long"),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+		AuthScheme:      "Bearer",
+	})
+
+	env := &verificationTestEnv{users: newFakeUserRepository()}
+	producer := new(MockMessageProducer)
+	producer.On("Publish", mock.Anything, "user.welcome", mock.Anything, mock.Anything).Return(nil)
+	producer.On("Publish", mock.Anything, notificationRoutingKey, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			event, ok := args.Get(2).(notifDto.NotificationEvent)
+			if !ok || event.EventType != "user.verify_email" {
+				return
+			}
+			verifyURL, _ := event.Data["verify_url"].(string)
+			_, token, found := strings.Cut(verifyURL, "token=")
+			require.True(t, found, "verify_url should carry the token: %q", verifyURL)
+			env.mu.Lock()
+			env.tokens = append(env.tokens, token)
+			env.mu.Unlock()
+		}).
+		Return(nil)
+
+	env.svc = NewAuthService(env.users, newFakeSessionRepository(), newFakePasswordResetRepository(), jwtAuth, producer, Options{
+		EmailVerifier: verifier,
+	})
+	return env
+}
+
+func (env *verificationTestEnv) lastToken(t *testing.T) string {
+	t.Helper()
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	require.NotEmpty(t, env.tokens, "no verification email was sent")
+	return env.tokens[len(env.tokens)-1]
+}
+
+func (env *verificationTestEnv) register(t *testing.T) *authDto.RegisterResponse {
+	t.Helper()
+	resp, err := env.svc.Register(context.Background(), authDto.RegisterRequest{
+		Name:     "jane",
+		Email:    "jane@example.com",
+		Password: mfaTestPassword,
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestRegister_RequiresEmailVerification(t *testing.T) {
+	env := newVerificationTestService(t)
+
+	resp := env.register(t)
+	assert.True(t, resp.EmailVerificationRequired)
+	assert.Empty(t, resp.AccessToken, "no tokens before the email is verified")
+	assert.False(t, resp.User.EmailVerified)
+	assert.NotEmpty(t, env.lastToken(t))
+}
+
+func TestLogin_BlockedUntilEmailVerified(t *testing.T) {
+	env := newVerificationTestService(t)
+	ctx := context.Background()
+	env.register(t)
+
+	login := authDto.LoginRequest{Email: "jane@example.com", Password: mfaTestPassword}
+	_, err := env.svc.Login(ctx, login)
+	assertErrorCode(t, err, pkgErrors.ErrCodeEmailNotVerified)
+
+	require.NoError(t, env.svc.VerifyEmail(ctx, authDto.VerifyEmailRequest{Token: env.lastToken(t)}))
+
+	resp, err := env.svc.Login(ctx, login)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.True(t, resp.User.EmailVerified)
+}
+
+func TestVerifyEmail_TokenIsSingleUse(t *testing.T) {
+	env := newVerificationTestService(t)
+	ctx := context.Background()
+	env.register(t)
+	token := env.lastToken(t)
+
+	require.NoError(t, env.svc.VerifyEmail(ctx, authDto.VerifyEmailRequest{Token: token}))
+	err := env.svc.VerifyEmail(ctx, authDto.VerifyEmailRequest{Token: token})
+	assertErrorCode(t, err, pkgErrors.ErrCodeVerifyTokenInvalid)
+}
+
+func TestVerifyEmail_RejectsTamperedToken(t *testing.T) {
+	env := newVerificationTestService(t)
+	env.register(t)
+
+	err := env.svc.VerifyEmail(context.Background(), authDto.VerifyEmailRequest{Token: env.lastToken(t) + "x"})
+	assertErrorCode(t, err, pkgErrors.ErrCodeVerifyTokenInvalid)
+}
+
+func TestResendVerification_IsRateLimited(t *testing.T) {
+	env := newVerificationTestService(t)
+	ctx := context.Background()
+	env.register(t)
+
+	req := authDto.ResendVerificationRequest{Email: "jane@example.com"}
+	require.NoError(t, env.svc.ResendVerification(ctx, req))
+	assert.Len(t, env.tokens, 2)
+
+	err := env.svc.ResendVerification(ctx, req)
+	assertErrorCode(t, err, pkgErrors.ErrCodeRateLimited)
+	assert.Len(t, env.tokens, 2, "no email sent while rate limited")
+}
+
+func TestResendVerification_DoesNotRevealAccounts(t *testing.T) {
+	env := newVerificationTestService(t)
+
+	err := env.svc.ResendVerification(context.Background(), authDto.ResendVerificationRequest{Email: "nobody@example.com"})
+	assert.NoError(t, err)
+	assert.Empty(t, env.tokens)
+}
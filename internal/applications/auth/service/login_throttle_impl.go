@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	authDto "ichi-go/internal/applications/auth/dto"
+	rbacModels "ichi-go/internal/applications/rbac/models"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/rbac"
+	"ichi-go/pkg/requestctx"
+	"math"
+	"time"
+)
+
+// auditTenantSystem is the tenant of audit events that belong to no tenant, matching rbac.default_tenant
+const auditTenantSystem = "system"
+
+// UnlockLogin lifts a login lockout of an email and/or client IP. Platform admins only.
+func (s *ServiceImpl) UnlockLogin(ctx context.Context, authCtx authenticator.AuthContext, req authDto.UnlockLoginRequest) error {
+	adminID := authCtx.UserID.ID
+
+	if err := s.requirePlatformAdmin(ctx, adminID); err != nil {
+		return err
+	}
+
+	if req.Email == "" && req.IPAddress == "" {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeValidation).
+			Hint("Provide an email or an IP address to unlock").
+			Errorf("unlock request without email or ip")
+	}
+
+	if s.loginThrottle == nil {
+		return nil
+	}
+
+	if req.Email != "" {
+		if err := s.loginThrottle.UnlockEmail(ctx, req.Email); err != nil {
+			return pkgErrors.Cache(pkgErrors.ErrCodeCache).
+				With("operation", "unlock_login").
+				Wrap(err)
+		}
+	}
+	if req.IPAddress != "" {
+		if err := s.loginThrottle.UnlockIP(ctx, req.IPAddress); err != nil {
+			return pkgErrors.Cache(pkgErrors.ErrCodeCache).
+				With("operation", "unlock_login").
+				Wrap(err)
+		}
+	}
+
+	logger.Infof("Admin %d lifted login lockout (email=%t ip=%q)", adminID, req.Email != "", req.IPAddress)
+	s.auditLoginEvent(ctx, loginAuditEvent{
+		action:    rbacModels.ActionLoginUnlocked,
+		actorID:   fmt.Sprintf("%d", adminID),
+		actorType: rbacModels.ActorTypeUser,
+		email:     req.Email,
+		ipAddress: req.IPAddress,
+		reason:    "unlocked by admin",
+	})
+	return nil
+}
+
+// checkLoginAllowed rejects locked emails/IPs and applies the progressive delay.
+// Throttle store errors fail open: an unavailable Redis must not block every login.
+func (s *ServiceImpl) checkLoginAllowed(ctx context.Context, email, clientIP string) error {
+	if s.loginThrottle == nil {
+		return nil
+	}
+
+	remaining, err := s.loginThrottle.Locked(ctx, email, clientIP)
+	if err != nil {
+		logger.Warnf("Login throttle unavailable, allowing attempt: %v", err)
+		return nil
+	}
+	if remaining > 0 {
+		minutes := int(math.Ceil(remaining.Minutes()))
+		return pkgErrors.AuthService(pkgErrors.ErrCodeAccountLocked).
+			With("email", email).
+			With("retry_after_seconds", int(math.Ceil(remaining.Seconds()))).
+			Hint(fmt.Sprintf("Too many failed login attempts, try again in %d minute(s)", minutes)).
+			Errorf("login locked")
+	}
+
+	delay, err := s.loginThrottle.Delay(ctx, email)
+	if err != nil {
+		logger.Warnf("Login throttle unavailable, skipping delay: %v", err)
+		return nil
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt and audits any lockout it triggers
+func (s *ServiceImpl) recordLoginFailure(ctx context.Context, email, clientIP string, user *model.User) {
+	if s.loginThrottle == nil {
+		return
+	}
+
+	result, err := s.loginThrottle.RecordFailure(ctx, email, clientIP)
+	if err != nil {
+		logger.Warnf("Failed to record login failure: %v", err)
+		return
+	}
+
+	event := loginAuditEvent{
+		action:    rbacModels.ActionLoginLocked,
+		actorID:   "system",
+		actorType: rbacModels.ActorTypeSystem,
+		ipAddress: clientIP,
+	}
+	if user != nil {
+		event.subjectID = fmt.Sprintf("%d", user.ID)
+	}
+	lockout := s.loginThrottle.LockoutDuration()
+
+	if result.EmailLocked {
+		logger.Warnf("Login locked for email after %d failed attempts (user_id=%s ip=%s)", result.Failures, event.subjectID, clientIP)
+		event.email = email
+		event.reason = fmt.Sprintf("%d failed login attempts, email locked for %s", result.Failures, lockout)
+		s.auditLoginEvent(ctx, event)
+	}
+	if result.IPLocked {
+		logger.Warnf("Login locked for client IP %s after too many failed attempts", clientIP)
+		event.email = ""
+		event.subjectID = ""
+		event.reason = fmt.Sprintf("too many failed login attempts from IP, locked for %s", lockout)
+		s.auditLoginEvent(ctx, event)
+	}
+}
+
+func (s *ServiceImpl) recordLoginSuccess(ctx context.Context, email string) {
+	if s.loginThrottle == nil {
+		return
+	}
+	if err := s.loginThrottle.RecordSuccess(ctx, email); err != nil {
+		logger.Warnf("Failed to reset login failures: %v", err)
+	}
+}
+
+func (s *ServiceImpl) requirePlatformAdmin(ctx context.Context, userID uint64) error {
+	if s.admins != nil {
+		isAdmin, err := s.admins.IsPlatformAdmin(ctx, int64(userID))
+		if err != nil {
+			return pkgErrors.AuthService(pkgErrors.ErrCodeDatabase).
+				With("user_id", userID).
+				Hint("Failed to check permissions").
+				Wrap(err)
+		}
+		if isAdmin {
+			return nil
+		}
+	}
+	return pkgErrors.AuthService(pkgErrors.ErrCodeForbidden).
+		With("user_id", userID).
+		Hint("Platform admin permission required").
+		Errorf("user is not a platform admin")
+}
+
+type loginAuditEvent struct {
+	action    string
+	actorID   string
+	actorType string
+	subjectID string
+	email     string
+	ipAddress string
+	reason    string
+}
+
+// auditLoginEvent writes a login security event to the audit log (async, like the RBAC services).
+// Only a SHA-256 hash of the email is stored (subject_email_hash).
+func (s *ServiceImpl) auditLoginEvent(ctx context.Context, event loginAuditEvent) {
+	if s.auditLog == nil {
+		return
+	}
+
+	rc := requestctx.FromContext(ctx)
+	tenantID := rc.TenantID
+	if tenantID == "" {
+		tenantID = auditTenantSystem
+	}
+
+	log := &rbacModels.AuditLog{
+		EventID:      fmt.Sprintf("auth_%s_%d", event.action, time.Now().UnixNano()),
+		Timestamp:    time.Now(),
+		ActorID:      event.actorID,
+		ActorType:    event.actorType,
+		Action:       event.action,
+		ResourceType: strPtr("login"),
+		TenantID:     tenantID,
+		Reason:       strPtr(event.reason),
+		IPAddress:    strPtr(event.ipAddress),
+		UserAgent:    strPtr(rc.UserAgent),
+		RequestID:    strPtr(rc.RequestID),
+	}
+	if event.subjectID != "" {
+		log.SubjectID = strPtr(event.subjectID)
+	}
+	if event.email != "" {
+		log.SubjectEmailHash = strPtr(hashEmail(event.email))
+	}
+
+	go func() {
+		if err := s.auditLog.Create(context.Background(), log); err != nil {
+			logger.Errorf("Failed to save audit log: %v", err)
+		}
+	}()
+}
+
+func hashEmail(email string) string {
+	return rbac.HashEmail(email)
+}
+
+func strPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
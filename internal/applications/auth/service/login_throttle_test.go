@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	authDto "ichi-go/internal/applications/auth/dto"
+	rbacModels "ichi-go/internal/applications/rbac/models"
+	"ichi-go/pkg/authenticator"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuditLog struct {
+	mu   sync.Mutex
+	logs []*rbacModels.AuditLog
+}
+
+func (f *fakeAuditLog) Create(_ context.Context, log *rbacModels.AuditLog) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logs = append(f.logs, log)
+	return nil
+}
+
+func (f *fakeAuditLog) actions() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var actions []string
+	for _, l := range f.logs {
+		actions = append(actions, l.Action)
+	}
+	return actions
+}
+
+type fakeAdmins map[int64]bool
+
+func (f fakeAdmins) IsPlatformAdmin(_ context.Context, userID int64) (bool, error) {
+	return f[userID], nil
+}
+
+func newThrottleTestService(t *testing.T) (*ServiceImpl, *TestUser, *fakeAuditLog) {
+	t.Helper()
+
+	testUser := createTestUser()
+	testUser.Model.ID = int64(testUser.ID)
+
+	users := new(MockUserRepository)
+	users.On("FindByEmail", mock.Anything, testUser.Model.Email).Return(testUser.Model, nil)
+	users.On("FindByEmail", mock.Anything, mock.Anything).Return(nil, nil)
+
+	jwtAuth := authenticator.NewJWTAuthenticator(&authenticator.JWTConfig{
+		SigningMethod:   jwt.SigningMethodHS256,
+		SecretKey:       []byte("test-secret-key-minimum-32-chars-long"),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+		AuthScheme:      "Bearer",
+	})
+
+	throttle := authenticator.NewLoginThrottle(authenticator.NewMemoryLoginAttemptStore(), authenticator.LoginThrottleConfig{
+		Enabled:     true,
+		MaxAttempts: 3,
+	})
+	audit := &fakeAuditLog{}
+
+	svc := NewAuthService(users, newFakeSessionRepository(), newFakePasswordResetRepository(), jwtAuth, nil, Options{
+		LoginThrottle: throttle,
+		AuditLog:      audit,
+		Admins:        fakeAdmins{99: true},
+	})
+	return svc, testUser, audit
+}
+
+func TestLogin_LocksAfterRepeatedFailures(t *testing.T) {
+	svc, user, audit := newThrottleTestService(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := svc.Login(ctx, authDto.LoginRequest{Email: user.Model.Email, Password: "WrongPassword1!"})
+		require.Error(t, err)
+	}
+
+	// Even the correct password is rejected while locked
+	_, err := svc.Login(ctx, authDto.LoginRequest{Email: user.Model.Email, Password: user.Password})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "login locked")
+
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{rbacModels.ActionLoginLocked}, audit.actions())
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLogin_UnknownEmailIsLockedLikeKnownEmail(t *testing.T) {
+	svc, _, _ := newThrottleTestService(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := svc.Login(ctx, authDto.LoginRequest{Email: "nobody@example.com", Password: "WrongPassword1!"})
+		require.Error(t, err)
+	}
+
+	_, err := svc.Login(ctx, authDto.LoginRequest{Email: "nobody@example.com", Password: "WrongPassword1!"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "login locked")
+}
+
+func TestUnlockLogin(t *testing.T) {
+	svc, user, audit := newThrottleTestService(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, _ = svc.Login(ctx, authDto.LoginRequest{Email: user.Model.Email, Password: "WrongPassword1!"})
+	}
+
+	req := authDto.UnlockLoginRequest{Email: user.Model.Email}
+
+	// Regular users cannot unlock accounts
+	err := svc.UnlockLogin(ctx, authenticator.AuthContext{UserID: authenticator.UserSubject{ID: user.ID}}, req)
+	require.Error(t, err)
+
+	require.NoError(t, svc.UnlockLogin(ctx, authenticator.AuthContext{UserID: authenticator.UserSubject{ID: 99}}, req))
+
+	resp, err := svc.Login(ctx, authDto.LoginRequest{Email: user.Model.Email, Password: user.Password})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+
+	assert.Eventually(t, func() bool {
+		return len(audit.actions()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{rbacModels.ActionLoginLocked, rbacModels.ActionLoginUnlocked}, audit.actions())
+}
@@ -0,0 +1,512 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	authDto "ichi-go/internal/applications/auth/dto"
+	rbacModels "ichi-go/internal/applications/rbac/models"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/requestctx"
+	"time"
+)
+
+// completeLogin finishes a login that passed the first factor. Users with MFA enabled,
+// or forced to use it by the MFA policy, get a challenge instead of tokens.
+func (s *ServiceImpl) completeLogin(ctx context.Context, user *model.User) (*authDto.LoginResponse, error) {
+	if err := s.checkEmailVerifiedForLogin(user); err != nil {
+		return nil, err
+	}
+
+	needed, enroll, err := s.secondFactor(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if needed {
+		return s.mfaChallengeResponse(ctx, user, enroll)
+	}
+
+	return s.loginTokens(ctx, user)
+}
+
+// secondFactor reports whether user must pass MFA to log in, and whether they
+// must enroll first because the policy requires MFA they have not set up.
+func (s *ServiceImpl) secondFactor(ctx context.Context, user *model.User) (needed, enroll bool, err error) {
+	if s.mfa == nil {
+		return false, false, nil
+	}
+	record, err := s.mfaRepo.FindByUser(ctx, uint64(user.ID))
+	if err != nil {
+		return false, false, err
+	}
+	if record != nil && record.IsEnabled() {
+		return true, false, nil
+	}
+	required, err := s.requiresMFA(ctx, uint64(user.ID))
+	if err != nil {
+		return false, false, err
+	}
+	return required, required, nil
+}
+
+func (s *ServiceImpl) mfaChallengeResponse(ctx context.Context, user *model.User, enroll bool) (*authDto.LoginResponse, error) {
+	token, err := s.mfa.NewChallenge(ctx, uint64(user.ID), enroll)
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).
+			With("user_id", user.ID).
+			Hint("Failed to start multi-factor authentication").
+			Wrap(err)
+	}
+	return &authDto.LoginResponse{
+		User:                  userInfo(user),
+		MFARequired:           true,
+		MFAEnrollmentRequired: enroll,
+		ChallengeToken:        token,
+		ChallengeExpiresIn:    int64(s.mfa.ChallengeTTL().Seconds()),
+	}, nil
+}
+
+// loginTokens starts a session for user and returns its token pair
+func (s *ServiceImpl) loginTokens(ctx context.Context, user *model.User) (*authDto.LoginResponse, error) {
+	tokenPair, err := s.startSession(ctx, uint64(user.ID))
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).
+			With("user_id", user.ID).
+			Hint("Failed to generate authentication tokens").
+			Wrap(err)
+	}
+
+	return &authDto.LoginResponse{
+		User:         userInfo(user),
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		TokenType:    tokenPair.TokenType,
+		ExpiresIn:    tokenPair.ExpiresIn,
+	}, nil
+}
+
+// VerifyMFA exchanges a login challenge and a TOTP or recovery code for a token pair
+func (s *ServiceImpl) VerifyMFA(ctx context.Context, req authDto.MFAVerifyRequest) (*authDto.LoginResponse, error) {
+	challenge, err := s.attemptMFAChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.Enroll {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeValidation).
+			With("user_id", challenge.UserID).
+			Hint("Multi-factor authentication must be set up first, use /auth/mfa/setup").
+			Errorf("mfa enrollment required")
+	}
+
+	record, err := s.mfaRepo.FindByUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || !record.IsEnabled() {
+		return nil, invalidMFAChallengeError(authenticator.ErrInvalidMFAChallenge)
+	}
+	if err := s.verifySecondFactor(ctx, record, req.Code, req.RecoveryCode); err != nil {
+		return nil, err
+	}
+
+	return s.finishMFALogin(ctx, req.ChallengeToken, challenge.UserID)
+}
+
+// SetupMFA starts the enrollment of a user who must use MFA before the login completes
+func (s *ServiceImpl) SetupMFA(ctx context.Context, req authDto.MFASetupRequest) (*authDto.MFAEnrollResponse, error) {
+	challenge, err := s.attemptEnrollmentChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.mfaUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.beginEnrollment(ctx, user)
+}
+
+// ConfirmMFASetup finishes the enrollment started by SetupMFA and completes the login.
+// The recovery codes are returned with the token pair.
+func (s *ServiceImpl) ConfirmMFASetup(ctx context.Context, req authDto.MFASetupConfirmRequest) (*authDto.LoginResponse, error) {
+	challenge, err := s.attemptEnrollmentChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := s.confirmEnrollment(ctx, challenge.UserID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.finishMFALogin(ctx, req.ChallengeToken, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	response.RecoveryCodes = codes
+	return response, nil
+}
+
+// MFAStatus reports whether the current user has MFA enabled or is required to
+func (s *ServiceImpl) MFAStatus(ctx context.Context, authCtx authenticator.AuthContext) (*authDto.MFAStatusResponse, error) {
+	if s.mfa == nil {
+		return nil, mfaDisabledError()
+	}
+	userID := authCtx.UserID.ID
+
+	required, err := s.requiresMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := &authDto.MFAStatusResponse{Required: required}
+
+	record, err := s.mfaRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && record.IsEnabled() {
+		enabledAt := record.ConfirmedAt.Time
+		response.Enabled = true
+		response.EnabledAt = &enabledAt
+		if response.RecoveryCodesRemaining, err = s.mfaRepo.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// EnrollMFA creates a pending TOTP secret for the current user. It takes effect once
+// confirmed with a code from the authenticator app.
+func (s *ServiceImpl) EnrollMFA(ctx context.Context, authCtx authenticator.AuthContext) (*authDto.MFAEnrollResponse, error) {
+	if s.mfa == nil {
+		return nil, mfaDisabledError()
+	}
+	user, err := s.mfaUser(ctx, authCtx.UserID.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.beginEnrollment(ctx, user)
+}
+
+// ConfirmMFA enables the pending TOTP secret of the current user and returns recovery codes
+func (s *ServiceImpl) ConfirmMFA(ctx context.Context, authCtx authenticator.AuthContext, req authDto.MFACodeRequest) (*authDto.MFARecoveryCodesResponse, error) {
+	if s.mfa == nil {
+		return nil, mfaDisabledError()
+	}
+	codes, err := s.confirmEnrollment(ctx, authCtx.UserID.ID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &authDto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableMFA turns MFA off after checking the password and a second factor.
+// Users the MFA policy applies to cannot disable it.
+func (s *ServiceImpl) DisableMFA(ctx context.Context, authCtx authenticator.AuthContext, req authDto.MFADisableRequest) error {
+	if s.mfa == nil {
+		return mfaDisabledError()
+	}
+	userID := authCtx.UserID.ID
+
+	user, err := s.mfaUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !s.VerifyPassword(user.Password, req.Password) {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeInvalidCredentials).
+			With("user_id", userID).
+			Hint("Password is incorrect").
+			Errorf("password verification failed")
+	}
+
+	required, err := s.requiresMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeForbidden).
+			With("user_id", userID).
+			Hint("Multi-factor authentication is required for your role and cannot be disabled").
+			Errorf("mfa required by policy")
+	}
+
+	record, err := s.enabledMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifySecondFactor(ctx, record, req.Code, req.RecoveryCode); err != nil {
+		return err
+	}
+	if err := s.mfaRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	s.auditMFAEvent(ctx, rbacModels.ActionMFADisabled, userID, "disabled by user")
+	logger.Infof("User %d disabled MFA", userID)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of the current user
+func (s *ServiceImpl) RegenerateRecoveryCodes(ctx context.Context, authCtx authenticator.AuthContext, req authDto.MFACodeRequest) (*authDto.MFARecoveryCodesResponse, error) {
+	if s.mfa == nil {
+		return nil, mfaDisabledError()
+	}
+	userID := authCtx.UserID.ID
+
+	record, err := s.enabledMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifySecondFactor(ctx, record, req.Code, ""); err != nil {
+		return nil, err
+	}
+	codes, err := s.issueRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &authDto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// beginEnrollment stores a new pending secret, replacing an unconfirmed one
+func (s *ServiceImpl) beginEnrollment(ctx context.Context, user *model.User) (*authDto.MFAEnrollResponse, error) {
+	userID := uint64(user.ID)
+
+	record, err := s.mfaRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && record.IsEnabled() {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeValidation).
+			With("user_id", userID).
+			Hint("Multi-factor authentication is already enabled").
+			Errorf("mfa already enabled")
+	}
+
+	secret, err := authenticator.GenerateTOTPSecret()
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).Wrap(err)
+	}
+	encrypted, err := s.mfa.EncryptSecret(secret)
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInternal).
+			With("user_id", userID).
+			Wrap(err)
+	}
+	if err := s.mfaRepo.SavePending(ctx, &model.UserMFA{UserID: userID, SecretEncrypted: encrypted}); err != nil {
+		return nil, err
+	}
+
+	return &authDto.MFAEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: authenticator.TOTPURI(s.mfa.Issuer(), user.Email, secret),
+		Digits:     authenticator.TOTPDigits,
+		Period:     int(authenticator.TOTPPeriod.Seconds()),
+	}, nil
+}
+
+// confirmEnrollment enables the pending secret of userID when code matches it
+func (s *ServiceImpl) confirmEnrollment(ctx context.Context, userID uint64, code string) ([]string, error) {
+	record, err := s.mfaRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.IsEnabled() {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeValidation).
+			With("user_id", userID).
+			Hint("No multi-factor enrollment is pending, start a new one").
+			Errorf("no pending mfa enrollment")
+	}
+
+	step, err := s.validateTOTP(record, code)
+	if err != nil {
+		return nil, err
+	}
+	confirmed, err := s.mfaRepo.Confirm(ctx, record.ID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, invalidMFACodeError(userID)
+	}
+
+	codes, err := s.issueRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditMFAEvent(ctx, rbacModels.ActionMFAEnabled, userID, "TOTP authenticator confirmed")
+	logger.Infof("User %d enabled MFA", userID)
+	return codes, nil
+}
+
+// verifySecondFactor accepts either a TOTP code, usable once, or an unused recovery code
+func (s *ServiceImpl) verifySecondFactor(ctx context.Context, record *model.UserMFA, code, recoveryCode string) error {
+	if code == "" && recoveryCode != "" {
+		used, err := s.mfaRepo.UseRecoveryCode(ctx, record.UserID, authenticator.HashRecoveryCode(recoveryCode))
+		if err != nil {
+			return err
+		}
+		if !used {
+			return invalidMFACodeError(record.UserID)
+		}
+		logger.Infof("User %d used an MFA recovery code", record.UserID)
+		return nil
+	}
+
+	step, err := s.validateTOTP(record, code)
+	if err != nil {
+		return err
+	}
+	// Compare-and-set, so two concurrent requests cannot both use the same code
+	accepted, err := s.mfaRepo.UseStep(ctx, record.ID, step)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return invalidMFACodeError(record.UserID)
+	}
+	return nil
+}
+
+func (s *ServiceImpl) validateTOTP(record *model.UserMFA, code string) (int64, error) {
+	secret, err := s.mfa.DecryptSecret(record.SecretEncrypted)
+	if err != nil {
+		return 0, pkgErrors.AuthService(pkgErrors.ErrCodeInternal).
+			With("user_id", record.UserID).
+			Hint("Multi-factor authentication is unavailable").
+			Wrap(err)
+	}
+	step, ok := authenticator.ValidateTOTP(secret, code, time.Now(), record.LastUsedStep)
+	if !ok {
+		return 0, invalidMFACodeError(record.UserID)
+	}
+	return step, nil
+}
+
+func (s *ServiceImpl) issueRecoveryCodes(ctx context.Context, userID uint64) ([]string, error) {
+	codes, err := authenticator.GenerateRecoveryCodes(s.mfa.RecoveryCodeCount())
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).Wrap(err)
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = authenticator.HashRecoveryCode(code)
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// finishMFALogin ends the challenge and starts the session of userID
+func (s *ServiceImpl) finishMFALogin(ctx context.Context, challengeToken string, userID uint64) (*authDto.LoginResponse, error) {
+	if err := s.mfa.EndChallenge(ctx, challengeToken); err != nil {
+		logger.Warnf("Failed to end MFA challenge of user %d: %v", userID, err)
+	}
+	user, err := s.mfaUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.loginTokens(ctx, user)
+}
+
+func (s *ServiceImpl) attemptMFAChallenge(ctx context.Context, token string) (*authenticator.MFAChallenge, error) {
+	if s.mfa == nil {
+		return nil, mfaDisabledError()
+	}
+	challenge, err := s.mfa.AttemptChallenge(ctx, token)
+	if errors.Is(err, authenticator.ErrInvalidMFAChallenge) {
+		return nil, invalidMFAChallengeError(err)
+	}
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInternal).
+			Hint("Multi-factor authentication is unavailable, please try again later").
+			Wrap(err)
+	}
+	return challenge, nil
+}
+
+func (s *ServiceImpl) attemptEnrollmentChallenge(ctx context.Context, token string) (*authenticator.MFAChallenge, error) {
+	challenge, err := s.attemptMFAChallenge(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.Enroll {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeValidation).
+			With("user_id", challenge.UserID).
+			Hint("Multi-factor authentication is already set up, use /auth/mfa/verify").
+			Errorf("mfa enrollment not required")
+	}
+	return challenge, nil
+}
+
+func (s *ServiceImpl) enabledMFA(ctx context.Context, userID uint64) (*model.UserMFA, error) {
+	record, err := s.mfaRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || !record.IsEnabled() {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeValidation).
+			With("user_id", userID).
+			Hint("Multi-factor authentication is not enabled").
+			Errorf("mfa not enabled")
+	}
+	return record, nil
+}
+
+func (s *ServiceImpl) mfaUser(ctx context.Context, userID uint64) (*model.User, error) {
+	user, err := s.userRepo.GetById(ctx, userID)
+	if err != nil || user == nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeUserNotFound).
+			With("user_id", userID).
+			Hint("User not found").
+			Errorf("user not found")
+	}
+	return user, nil
+}
+
+// requiresMFA fails closed: when the policy cannot be evaluated the login is refused
+func (s *ServiceImpl) requiresMFA(ctx context.Context, userID uint64) (bool, error) {
+	if s.mfaPolicy == nil {
+		return false, nil
+	}
+	required, err := s.mfaPolicy.RequiresMFA(ctx, int64(userID))
+	if err != nil {
+		return false, pkgErrors.AuthService(pkgErrors.ErrCodeDatabase).
+			With("user_id", userID).
+			Hint("Failed to check the multi-factor authentication policy").
+			Wrap(err)
+	}
+	return required, nil
+}
+
+func (s *ServiceImpl) auditMFAEvent(ctx context.Context, action string, userID uint64, reason string) {
+	s.auditLoginEvent(ctx, loginAuditEvent{
+		action:    action,
+		actorID:   fmt.Sprintf("%d", userID),
+		actorType: rbacModels.ActorTypeUser,
+		subjectID: fmt.Sprintf("%d", userID),
+		ipAddress: requestctx.FromContext(ctx).ClientIP,
+		reason:    reason,
+	})
+}
+
+func mfaDisabledError() error {
+	return pkgErrors.AuthService(pkgErrors.ErrCodeNotFound).
+		Hint("Multi-factor authentication is not enabled on this server").
+		Errorf("mfa disabled")
+}
+
+func invalidMFAChallengeError(err error) error {
+	return pkgErrors.AuthService(pkgErrors.ErrCodeMFAInvalid).
+		Hint("Login challenge expired or was used too often, please log in again").
+		Wrap(err)
+}
+
+func invalidMFACodeError(userID uint64) error {
+	return pkgErrors.AuthService(pkgErrors.ErrCodeMFAInvalid).
+		With("user_id", userID).
+		Hint("Invalid authentication code").
+		Errorf("invalid mfa code")
+}
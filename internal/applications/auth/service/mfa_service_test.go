@@ -0,0 +1,384 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"testing"
+	"time"
+
+	authDto "ichi-go/internal/applications/auth/dto"
+	rbacModels "ichi-go/internal/applications/rbac/models"
+	"ichi-go/pkg/authenticator"
+	dbModel "ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const mfaTestPassword = "SecurePass123!"
+
+type fakeMFARepository struct {
+	mu       sync.Mutex
+	nextID   int64
+	records  map[uint64]*dbModel.UserMFA
+	recovery map[uint64]map[string]bool // user -> code hash -> used
+}
+
+func newFakeMFARepository() *fakeMFARepository {
+	return &fakeMFARepository{
+		records:  make(map[uint64]*dbModel.UserMFA),
+		recovery: make(map[uint64]map[string]bool),
+	}
+}
+
+func (r *fakeMFARepository) FindByUser(_ context.Context, userID uint64) (*dbModel.UserMFA, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.records[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (r *fakeMFARepository) SavePending(_ context.Context, mfa *dbModel.UserMFA) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	mfa.ID = r.nextID
+	copied := *mfa
+	r.records[mfa.UserID] = &copied
+	return nil
+}
+
+func (r *fakeMFARepository) byID(id int64) *dbModel.UserMFA {
+	for _, record := range r.records {
+		if record.ID == id {
+			return record
+		}
+	}
+	return nil
+}
+
+func (r *fakeMFARepository) Confirm(_ context.Context, id int64, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record := r.byID(id)
+	if record == nil || record.IsEnabled() {
+		return false, nil
+	}
+	record.ConfirmedAt = bun.NullTime{Time: time.Now()}
+	record.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeMFARepository) UseStep(_ context.Context, id int64, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record := r.byID(id)
+	if record == nil || record.LastUsedStep >= step {
+		return false, nil
+	}
+	record.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeMFARepository) Delete(_ context.Context, userID uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, userID)
+	delete(r.recovery, userID)
+	return nil
+}
+
+func (r *fakeMFARepository) ReplaceRecoveryCodes(_ context.Context, userID uint64, codeHashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recovery[userID] = make(map[string]bool)
+	for _, hash := range codeHashes {
+		r.recovery[userID][hash] = false
+	}
+	return nil
+}
+
+func (r *fakeMFARepository) UseRecoveryCode(_ context.Context, userID uint64, codeHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	used, ok := r.recovery[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recovery[userID][codeHash] = true
+	return true, nil
+}
+
+func (r *fakeMFARepository) CountRecoveryCodes(_ context.Context, userID uint64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, used := range r.recovery[userID] {
+		if !used {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type fakeMFAPolicy map[int64]bool
+
+func (f fakeMFAPolicy) RequiresMFA(_ context.Context, userID int64) (bool, error) {
+	return f[userID], nil
+}
+
+type mfaTestEnv struct {
+	svc   *ServiceImpl
+	repo  *fakeMFARepository
+	audit *fakeAuditLog
+	user  *dbModel.User
+	admin *dbModel.User
+}
+
+// newMFATestService has a regular user and an admin the MFA policy applies to
+func newMFATestService(t *testing.T) *mfaTestEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(mfaTestPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+	users := newFakeUserRepository()
+	userID, err := users.Create(ctx, dbModel.User{Name: "jane", Email: "jane@example.com", Password: string(hash)})
+	require.NoError(t, err)
+	adminID, err := users.Create(ctx, dbModel.User{Name: "root", Email: "root@example.com", Password: string(hash)})
+	require.NoError(t, err)
+
+	key := make([]byte, 32)
+	_, err = rand.Read(key)
+	require.NoError(t, err)
+	mfa, err := authenticator.NewMFA(authenticator.MFAConfig{
+		EncryptionKey: base64.StdEncoding.EncodeToString(key),
+		RecoveryCodes: 3,
+	}, authenticator.NewMemoryMFAChallengeStore())
+	require.NoError(t, err)
+
+	jwtAuth := authenticator.NewJWTAuthenticator(&authenticator.JWTConfig{
+		SigningMethod:   jwt.SigningMethodHS256,
+		SecretKey:       []byte("test-secret-key-minimum-32-chars-long"),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+		AuthScheme:      "Bearer",
+	})
+
+	env := &mfaTestEnv{repo: newFakeMFARepository(), audit: &fakeAuditLog{}}
+	env.svc = NewAuthService(users, newFakeSessionRepository(), newFakePasswordResetRepository(), jwtAuth, nil, Options{
+		AuditLog:  env.audit,
+		MFA:       mfa,
+		MFARepo:   env.repo,
+		MFAPolicy: fakeMFAPolicy{adminID: true},
+	})
+	env.user, err = users.GetById(ctx, uint64(userID))
+	require.NoError(t, err)
+	env.admin, err = users.GetById(ctx, uint64(adminID))
+	require.NoError(t, err)
+	return env
+}
+
+func (env *mfaTestEnv) login(t *testing.T, user *dbModel.User) *authDto.LoginResponse {
+	t.Helper()
+	resp, err := env.svc.Login(context.Background(), authDto.LoginRequest{Email: user.Email, Password: mfaTestPassword})
+	require.NoError(t, err)
+	return resp
+}
+
+// enroll enables MFA for user and returns the TOTP secret and recovery codes
+func (env *mfaTestEnv) enroll(t *testing.T, user *dbModel.User) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	authCtx := ownerCtx(uint64(user.ID))
+
+	enrollment, err := env.svc.EnrollMFA(ctx, authCtx)
+	require.NoError(t, err)
+	codes, err := env.svc.ConfirmMFA(ctx, authCtx, authDto.MFACodeRequest{Code: totpCode(t, enrollment.Secret, 0)})
+	require.NoError(t, err)
+	return enrollment.Secret, codes.RecoveryCodes
+}
+
+// totpCode returns the code of the period offset steps from now. Each accepted
+// code burns its period, so follow-up logins in a test use the next one.
+func totpCode(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	code, err := authenticator.TOTPCode(secret, authenticator.TOTPStep(time.Now())+offset)
+	require.NoError(t, err)
+	return code
+}
+
+func TestLogin_WithoutMFAReturnsTokens(t *testing.T) {
+	env := newMFATestService(t)
+
+	resp := env.login(t, env.user)
+	assert.False(t, resp.MFARequired)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.Empty(t, resp.ChallengeToken)
+}
+
+func TestLogin_MFARequiresSecondFactor(t *testing.T) {
+	env := newMFATestService(t)
+	ctx := context.Background()
+	secret, _ := env.enroll(t, env.user)
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{rbacModels.ActionMFAEnabled}, env.audit.actions())
+	}, time.Second, 10*time.Millisecond)
+
+	resp := env.login(t, env.user)
+	require.True(t, resp.MFARequired)
+	assert.False(t, resp.MFAEnrollmentRequired)
+	assert.Empty(t, resp.AccessToken)
+	assert.Empty(t, resp.RefreshToken)
+	assert.NotEmpty(t, resp.ChallengeToken)
+	assert.Equal(t, int64(300), resp.ChallengeExpiresIn)
+
+	_, err := env.svc.VerifyMFA(ctx, authDto.MFAVerifyRequest{ChallengeToken: resp.ChallengeToken, Code: "000000"})
+	assertErrorCode(t, err, pkgErrors.ErrCodeMFAInvalid)
+	_, err = env.svc.VerifyMFA(ctx, authDto.MFAVerifyRequest{ChallengeToken: resp.ChallengeToken, Code: totpCode(t, secret, 0)})
+	assertErrorCode(t, err, pkgErrors.ErrCodeMFAInvalid) // already used to confirm the enrollment
+
+	verified, err := env.svc.VerifyMFA(ctx, authDto.MFAVerifyRequest{ChallengeToken: resp.ChallengeToken, Code: totpCode(t, secret, 1)})
+	require.NoError(t, err)
+	assert.NotEmpty(t, verified.AccessToken)
+	assert.Equal(t, uint64(env.user.ID), verified.User.ID)
+
+	_, err = env.svc.VerifyMFA(ctx, authDto.MFAVerifyRequest{ChallengeToken: resp.ChallengeToken, Code: totpCode(t, secret, 1)})
+	assertErrorCode(t, err, pkgErrors.ErrCodeMFAInvalid) // challenge is single-use
+}
+
+func TestVerifyMFA_RecoveryCodesAreSingleUse(t *testing.T) {
+	env := newMFATestService(t)
+	ctx := context.Background()
+	_, recoveryCodes := env.enroll(t, env.user)
+	require.Len(t, recoveryCodes, 3)
+
+	resp := env.login(t, env.user)
+	_, err := env.svc.VerifyMFA(ctx, authDto.MFAVerifyRequest{ChallengeToken: resp.ChallengeToken, RecoveryCode: recoveryCodes[0]})
+	require.NoError(t, err)
+
+	resp = env.login(t, env.user)
+	_, err = env.svc.VerifyMFA(ctx, authDto.MFAVerifyRequest{ChallengeToken: resp.ChallengeToken, RecoveryCode: recoveryCodes[0]})
+	assertErrorCode(t, err, pkgErrors.ErrCodeMFAInvalid)
+
+	status, err := env.svc.MFAStatus(ctx, ownerCtx(uint64(env.user.ID)))
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.False(t, status.Required)
+	assert.Equal(t, 2, status.RecoveryCodesRemaining)
+}
+
+func TestVerifyMFA_InvalidChallenge(t *testing.T) {
+	env := newMFATestService(t)
+
+	_, err := env.svc.VerifyMFA(context.Background(), authDto.MFAVerifyRequest{ChallengeToken: "forged", Code: "123456"})
+	assertErrorCode(t, err, pkgErrors.ErrCodeMFAInvalid)
+}
+
+func TestLogin_PolicyForcesMFAEnrollment(t *testing.T) {
+	env := newMFATestService(t)
+	ctx := context.Background()
+
+	resp := env.login(t, env.admin)
+	require.True(t, resp.MFARequired)
+	require.True(t, resp.MFAEnrollmentRequired)
+	assert.Empty(t, resp.AccessToken)
+
+	_, err := env.svc.VerifyMFA(ctx, authDto.MFAVerifyRequest{ChallengeToken: resp.ChallengeToken, Code: "123456"})
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+
+	enrollment, err := env.svc.SetupMFA(ctx, authDto.MFASetupRequest{ChallengeToken: resp.ChallengeToken})
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.OTPAuthURI, "ichi-go:root@example.com")
+
+	verified, err := env.svc.ConfirmMFASetup(ctx, authDto.MFASetupConfirmRequest{
+		ChallengeToken: resp.ChallengeToken,
+		Code:           totpCode(t, enrollment.Secret, 0),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, verified.AccessToken)
+	assert.Len(t, verified.RecoveryCodes, 3)
+
+	// From now on the admin logs in with the second factor
+	resp = env.login(t, env.admin)
+	assert.True(t, resp.MFARequired)
+	assert.False(t, resp.MFAEnrollmentRequired)
+
+	err = env.svc.DisableMFA(ctx, ownerCtx(uint64(env.admin.ID)), authDto.MFADisableRequest{
+		Password: mfaTestPassword,
+		Code:     totpCode(t, enrollment.Secret, 1),
+	})
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+}
+
+func TestDisableMFA(t *testing.T) {
+	env := newMFATestService(t)
+	ctx := context.Background()
+	secret, _ := env.enroll(t, env.user)
+	authCtx := ownerCtx(uint64(env.user.ID))
+
+	err := env.svc.DisableMFA(ctx, authCtx, authDto.MFADisableRequest{Password: "wrong", Code: totpCode(t, secret, 1)})
+	assertErrorCode(t, err, pkgErrors.ErrCodeInvalidCredentials)
+	err = env.svc.DisableMFA(ctx, authCtx, authDto.MFADisableRequest{Password: mfaTestPassword, Code: "000000"})
+	assertErrorCode(t, err, pkgErrors.ErrCodeMFAInvalid)
+
+	require.NoError(t, env.svc.DisableMFA(ctx, authCtx, authDto.MFADisableRequest{Password: mfaTestPassword, Code: totpCode(t, secret, 1)}))
+	assert.Eventually(t, func() bool {
+		return len(env.audit.actions()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{rbacModels.ActionMFAEnabled, rbacModels.ActionMFADisabled}, env.audit.actions())
+
+	resp := env.login(t, env.user)
+	assert.False(t, resp.MFARequired)
+	assert.NotEmpty(t, resp.AccessToken)
+}
+
+func TestRegenerateRecoveryCodes_InvalidatesOldCodes(t *testing.T) {
+	env := newMFATestService(t)
+	ctx := context.Background()
+	secret, oldCodes := env.enroll(t, env.user)
+
+	regenerated, err := env.svc.RegenerateRecoveryCodes(ctx, ownerCtx(uint64(env.user.ID)), authDto.MFACodeRequest{Code: totpCode(t, secret, 1)})
+	require.NoError(t, err)
+	require.Len(t, regenerated.RecoveryCodes, 3)
+
+	resp := env.login(t, env.user)
+	_, err = env.svc.VerifyMFA(ctx, authDto.MFAVerifyRequest{ChallengeToken: resp.ChallengeToken, RecoveryCode: oldCodes[0]})
+	assertErrorCode(t, err, pkgErrors.ErrCodeMFAInvalid)
+	_, err = env.svc.VerifyMFA(ctx, authDto.MFAVerifyRequest{ChallengeToken: resp.ChallengeToken, RecoveryCode: regenerated.RecoveryCodes[0]})
+	require.NoError(t, err)
+}
+
+func TestEnrollMFA_RejectsSecondEnrollment(t *testing.T) {
+	env := newMFATestService(t)
+	env.enroll(t, env.user)
+
+	_, err := env.svc.EnrollMFA(context.Background(), ownerCtx(uint64(env.user.ID)))
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+}
+
+func TestAuthenticateBasic_RefusesUsersWithMFA(t *testing.T) {
+	env := newMFATestService(t)
+	ctx := context.Background()
+
+	user, err := env.svc.AuthenticateBasic(ctx, env.user.Email, mfaTestPassword)
+	require.NoError(t, err)
+	assert.Equal(t, env.user.ID, user.ID)
+
+	_, err = env.svc.AuthenticateBasic(ctx, env.admin.Email, mfaTestPassword)
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden) // required by policy
+
+	env.enroll(t, env.user)
+	_, err = env.svc.AuthenticateBasic(ctx, env.user.Email, mfaTestPassword)
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+}
@@ -7,6 +7,7 @@ package auth
 import (
 	"context"
 	auth "ichi-go/internal/applications/auth/dto"
+	"ichi-go/pkg/authenticator"
 	dbModel "ichi-go/pkg/db/model"
 
 	mock "github.com/stretchr/testify/mock"
@@ -39,95 +40,164 @@ func (_m *MockService) EXPECT() *MockService_Expecter {
 	return &MockService_Expecter{mock: &_m.Mock}
 }
 
-// GetUserByEmail provides a mock function for the type MockService
-func (_mock *MockService) GetUserByEmail(ctx context.Context, email string) (*dbModel.User, error) {
-	ret := _mock.Called(ctx, email)
+// ChangePassword provides a mock function for the type MockService
+func (_mock *MockService) ChangePassword(ctx context.Context, authCtx authenticator.AuthContext, req auth.ChangePasswordRequest) error {
+	ret := _mock.Called(ctx, authCtx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByEmail")
+		panic("no return value specified for ChangePassword")
 	}
 
-	var r0 *dbModel.User
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, auth.ChangePasswordRequest) error); ok {
+		r0 = returnFunc(ctx, authCtx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type MockService_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - req auth.ChangePasswordRequest
+func (_e *MockService_Expecter) ChangePassword(ctx interface{}, authCtx interface{}, req interface{}) *MockService_ChangePassword_Call {
+	return &MockService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, authCtx, req)}
+}
+
+func (_c *MockService_ChangePassword_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.ChangePasswordRequest)) *MockService_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 auth.ChangePasswordRequest
+		if args[2] != nil {
+			arg2 = args[2].(auth.ChangePasswordRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ChangePassword_Call) Return(err error) *MockService_ChangePassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.ChangePasswordRequest) error) *MockService_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfirmMFA provides a mock function for the type MockService
+func (_mock *MockService) ConfirmMFA(ctx context.Context, authCtx authenticator.AuthContext, req auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error) {
+	ret := _mock.Called(ctx, authCtx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmMFA")
+	}
+
+	var r0 *auth.MFARecoveryCodesResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*dbModel.User, error)); ok {
-		return returnFunc(ctx, email)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error)); ok {
+		return returnFunc(ctx, authCtx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *dbModel.User); ok {
-		r0 = returnFunc(ctx, email)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, auth.MFACodeRequest) *auth.MFARecoveryCodesResponse); ok {
+		r0 = returnFunc(ctx, authCtx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*dbModel.User)
+			r0 = ret.Get(0).(*auth.MFARecoveryCodesResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, email)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, auth.MFACodeRequest) error); ok {
+		r1 = returnFunc(ctx, authCtx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockService_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
-type MockService_GetUserByEmail_Call struct {
+// MockService_ConfirmMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmMFA'
+type MockService_ConfirmMFA_Call struct {
 	*mock.Call
 }
 
-// GetUserByEmail is a helper method to define mock.On call
+// ConfirmMFA is a helper method to define mock.On call
 //   - ctx context.Context
-//   - email string
-func (_e *MockService_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *MockService_GetUserByEmail_Call {
-	return &MockService_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
+//   - authCtx authenticator.AuthContext
+//   - req auth.MFACodeRequest
+func (_e *MockService_Expecter) ConfirmMFA(ctx interface{}, authCtx interface{}, req interface{}) *MockService_ConfirmMFA_Call {
+	return &MockService_ConfirmMFA_Call{Call: _e.mock.On("ConfirmMFA", ctx, authCtx, req)}
 }
 
-func (_c *MockService_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *MockService_GetUserByEmail_Call {
+func (_c *MockService_ConfirmMFA_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.MFACodeRequest)) *MockService_ConfirmMFA_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 authenticator.AuthContext
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 auth.MFACodeRequest
+		if args[2] != nil {
+			arg2 = args[2].(auth.MFACodeRequest)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_GetUserByEmail_Call) Return(userModel *dbModel.User, err error) *MockService_GetUserByEmail_Call {
-	_c.Call.Return(userModel, err)
+func (_c *MockService_ConfirmMFA_Call) Return(mfaRecoveryCodesResponse *auth.MFARecoveryCodesResponse, err error) *MockService_ConfirmMFA_Call {
+	_c.Call.Return(mfaRecoveryCodesResponse, err)
 	return _c
 }
 
-func (_c *MockService_GetUserByEmail_Call) RunAndReturn(run func(ctx context.Context, email string) (*dbModel.User, error)) *MockService_GetUserByEmail_Call {
+func (_c *MockService_ConfirmMFA_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error)) *MockService_ConfirmMFA_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Login provides a mock function for the type MockService
-func (_mock *MockService) Login(ctx context.Context, req auth.LoginRequest) (*auth.LoginResponse, error) {
+// ConfirmMFASetup provides a mock function for the type MockService
+func (_mock *MockService) ConfirmMFASetup(ctx context.Context, req auth.MFASetupConfirmRequest) (*auth.LoginResponse, error) {
 	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for ConfirmMFASetup")
 	}
 
 	var r0 *auth.LoginResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.LoginRequest) (*auth.LoginResponse, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.MFASetupConfirmRequest) (*auth.LoginResponse, error)); ok {
 		return returnFunc(ctx, req)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.LoginRequest) *auth.LoginResponse); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.MFASetupConfirmRequest) *auth.LoginResponse); ok {
 		r0 = returnFunc(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*auth.LoginResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, auth.LoginRequest) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, auth.MFASetupConfirmRequest) error); ok {
 		r1 = returnFunc(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -135,27 +205,27 @@ func (_mock *MockService) Login(ctx context.Context, req auth.LoginRequest) (*au
 	return r0, r1
 }
 
-// MockService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
-type MockService_Login_Call struct {
+// MockService_ConfirmMFASetup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmMFASetup'
+type MockService_ConfirmMFASetup_Call struct {
 	*mock.Call
 }
 
-// Login is a helper method to define mock.On call
+// ConfirmMFASetup is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req auth.LoginRequest
-func (_e *MockService_Expecter) Login(ctx interface{}, req interface{}) *MockService_Login_Call {
-	return &MockService_Login_Call{Call: _e.mock.On("Login", ctx, req)}
+//   - req auth.MFASetupConfirmRequest
+func (_e *MockService_Expecter) ConfirmMFASetup(ctx interface{}, req interface{}) *MockService_ConfirmMFASetup_Call {
+	return &MockService_ConfirmMFASetup_Call{Call: _e.mock.On("ConfirmMFASetup", ctx, req)}
 }
 
-func (_c *MockService_Login_Call) Run(run func(ctx context.Context, req auth.LoginRequest)) *MockService_Login_Call {
+func (_c *MockService_ConfirmMFASetup_Call) Run(run func(ctx context.Context, req auth.MFASetupConfirmRequest)) *MockService_ConfirmMFASetup_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 auth.LoginRequest
+		var arg1 auth.MFASetupConfirmRequest
 		if args[1] != nil {
-			arg1 = args[1].(auth.LoginRequest)
+			arg1 = args[1].(auth.MFASetupConfirmRequest)
 		}
 		run(
 			arg0,
@@ -165,65 +235,128 @@ func (_c *MockService_Login_Call) Run(run func(ctx context.Context, req auth.Log
 	return _c
 }
 
-func (_c *MockService_Login_Call) Return(loginResponse *auth.LoginResponse, err error) *MockService_Login_Call {
+func (_c *MockService_ConfirmMFASetup_Call) Return(loginResponse *auth.LoginResponse, err error) *MockService_ConfirmMFASetup_Call {
 	_c.Call.Return(loginResponse, err)
 	return _c
 }
 
-func (_c *MockService_Login_Call) RunAndReturn(run func(ctx context.Context, req auth.LoginRequest) (*auth.LoginResponse, error)) *MockService_Login_Call {
+func (_c *MockService_ConfirmMFASetup_Call) RunAndReturn(run func(ctx context.Context, req auth.MFASetupConfirmRequest) (*auth.LoginResponse, error)) *MockService_ConfirmMFASetup_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Me provides a mock function for the type MockService
-func (_mock *MockService) Me(ctx context.Context, userId uint64) (*auth.UserInfo, error) {
-	ret := _mock.Called(ctx, userId)
+// DisableMFA provides a mock function for the type MockService
+func (_mock *MockService) DisableMFA(ctx context.Context, authCtx authenticator.AuthContext, req auth.MFADisableRequest) error {
+	ret := _mock.Called(ctx, authCtx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Me")
+		panic("no return value specified for DisableMFA")
 	}
 
-	var r0 *auth.UserInfo
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, auth.MFADisableRequest) error); ok {
+		r0 = returnFunc(ctx, authCtx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DisableMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableMFA'
+type MockService_DisableMFA_Call struct {
+	*mock.Call
+}
+
+// DisableMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - req auth.MFADisableRequest
+func (_e *MockService_Expecter) DisableMFA(ctx interface{}, authCtx interface{}, req interface{}) *MockService_DisableMFA_Call {
+	return &MockService_DisableMFA_Call{Call: _e.mock.On("DisableMFA", ctx, authCtx, req)}
+}
+
+func (_c *MockService_DisableMFA_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.MFADisableRequest)) *MockService_DisableMFA_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 auth.MFADisableRequest
+		if args[2] != nil {
+			arg2 = args[2].(auth.MFADisableRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DisableMFA_Call) Return(err error) *MockService_DisableMFA_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DisableMFA_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.MFADisableRequest) error) *MockService_DisableMFA_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnrollMFA provides a mock function for the type MockService
+func (_mock *MockService) EnrollMFA(ctx context.Context, authCtx authenticator.AuthContext) (*auth.MFAEnrollResponse, error) {
+	ret := _mock.Called(ctx, authCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollMFA")
+	}
+
+	var r0 *auth.MFAEnrollResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) (*auth.UserInfo, error)); ok {
-		return returnFunc(ctx, userId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext) (*auth.MFAEnrollResponse, error)); ok {
+		return returnFunc(ctx, authCtx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) *auth.UserInfo); ok {
-		r0 = returnFunc(ctx, userId)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext) *auth.MFAEnrollResponse); ok {
+		r0 = returnFunc(ctx, authCtx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*auth.UserInfo)
+			r0 = ret.Get(0).(*auth.MFAEnrollResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
-		r1 = returnFunc(ctx, userId)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext) error); ok {
+		r1 = returnFunc(ctx, authCtx)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockService_Me_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Me'
-type MockService_Me_Call struct {
+// MockService_EnrollMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnrollMFA'
+type MockService_EnrollMFA_Call struct {
 	*mock.Call
 }
 
-// Me is a helper method to define mock.On call
+// EnrollMFA is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userId uint64
-func (_e *MockService_Expecter) Me(ctx interface{}, userId interface{}) *MockService_Me_Call {
-	return &MockService_Me_Call{Call: _e.mock.On("Me", ctx, userId)}
+//   - authCtx authenticator.AuthContext
+func (_e *MockService_Expecter) EnrollMFA(ctx interface{}, authCtx interface{}) *MockService_EnrollMFA_Call {
+	return &MockService_EnrollMFA_Call{Call: _e.mock.On("EnrollMFA", ctx, authCtx)}
 }
 
-func (_c *MockService_Me_Call) Run(run func(ctx context.Context, userId uint64)) *MockService_Me_Call {
+func (_c *MockService_EnrollMFA_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext)) *MockService_EnrollMFA_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uint64
+		var arg1 authenticator.AuthContext
 		if args[1] != nil {
-			arg1 = args[1].(uint64)
+			arg1 = args[1].(authenticator.AuthContext)
 		}
 		run(
 			arg0,
@@ -233,65 +366,122 @@ func (_c *MockService_Me_Call) Run(run func(ctx context.Context, userId uint64))
 	return _c
 }
 
-func (_c *MockService_Me_Call) Return(userInfo *auth.UserInfo, err error) *MockService_Me_Call {
-	_c.Call.Return(userInfo, err)
+func (_c *MockService_EnrollMFA_Call) Return(mfaEnrollResponse *auth.MFAEnrollResponse, err error) *MockService_EnrollMFA_Call {
+	_c.Call.Return(mfaEnrollResponse, err)
 	return _c
 }
 
-func (_c *MockService_Me_Call) RunAndReturn(run func(ctx context.Context, userId uint64) (*auth.UserInfo, error)) *MockService_Me_Call {
+func (_c *MockService_EnrollMFA_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext) (*auth.MFAEnrollResponse, error)) *MockService_EnrollMFA_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RefreshToken provides a mock function for the type MockService
-func (_mock *MockService) RefreshToken(ctx context.Context, req auth.RefreshTokenRequest) (*auth.RefreshTokenResponse, error) {
+// ForgotPassword provides a mock function for the type MockService
+func (_mock *MockService) ForgotPassword(ctx context.Context, req auth.ForgotPasswordRequest) error {
 	ret := _mock.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RefreshToken")
+		panic("no return value specified for ForgotPassword")
 	}
 
-	var r0 *auth.RefreshTokenResponse
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.ForgotPasswordRequest) error); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ForgotPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForgotPassword'
+type MockService_ForgotPassword_Call struct {
+	*mock.Call
+}
+
+// ForgotPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.ForgotPasswordRequest
+func (_e *MockService_Expecter) ForgotPassword(ctx interface{}, req interface{}) *MockService_ForgotPassword_Call {
+	return &MockService_ForgotPassword_Call{Call: _e.mock.On("ForgotPassword", ctx, req)}
+}
+
+func (_c *MockService_ForgotPassword_Call) Run(run func(ctx context.Context, req auth.ForgotPasswordRequest)) *MockService_ForgotPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.ForgotPasswordRequest
+		if args[1] != nil {
+			arg1 = args[1].(auth.ForgotPasswordRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ForgotPassword_Call) Return(err error) *MockService_ForgotPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ForgotPassword_Call) RunAndReturn(run func(ctx context.Context, req auth.ForgotPasswordRequest) error) *MockService_ForgotPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByEmail provides a mock function for the type MockService
+func (_mock *MockService) GetUserByEmail(ctx context.Context, email string) (*dbModel.User, error) {
+	ret := _mock.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByEmail")
+	}
+
+	var r0 *dbModel.User
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.RefreshTokenRequest) (*auth.RefreshTokenResponse, error)); ok {
-		return returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*dbModel.User, error)); ok {
+		return returnFunc(ctx, email)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.RefreshTokenRequest) *auth.RefreshTokenResponse); ok {
-		r0 = returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *dbModel.User); ok {
+		r0 = returnFunc(ctx, email)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*auth.RefreshTokenResponse)
+			r0 = ret.Get(0).(*dbModel.User)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, auth.RefreshTokenRequest) error); ok {
-		r1 = returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, email)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockService_RefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshToken'
-type MockService_RefreshToken_Call struct {
+// MockService_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
+type MockService_GetUserByEmail_Call struct {
 	*mock.Call
 }
 
-// RefreshToken is a helper method to define mock.On call
+// GetUserByEmail is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req auth.RefreshTokenRequest
-func (_e *MockService_Expecter) RefreshToken(ctx interface{}, req interface{}) *MockService_RefreshToken_Call {
-	return &MockService_RefreshToken_Call{Call: _e.mock.On("RefreshToken", ctx, req)}
+//   - email string
+func (_e *MockService_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *MockService_GetUserByEmail_Call {
+	return &MockService_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
 }
 
-func (_c *MockService_RefreshToken_Call) Run(run func(ctx context.Context, req auth.RefreshTokenRequest)) *MockService_RefreshToken_Call {
+func (_c *MockService_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *MockService_GetUserByEmail_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 auth.RefreshTokenRequest
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(auth.RefreshTokenRequest)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -301,65 +491,65 @@ func (_c *MockService_RefreshToken_Call) Run(run func(ctx context.Context, req a
 	return _c
 }
 
-func (_c *MockService_RefreshToken_Call) Return(refreshTokenResponse *auth.RefreshTokenResponse, err error) *MockService_RefreshToken_Call {
-	_c.Call.Return(refreshTokenResponse, err)
+func (_c *MockService_GetUserByEmail_Call) Return(userModel *dbModel.User, err error) *MockService_GetUserByEmail_Call {
+	_c.Call.Return(userModel, err)
 	return _c
 }
 
-func (_c *MockService_RefreshToken_Call) RunAndReturn(run func(ctx context.Context, req auth.RefreshTokenRequest) (*auth.RefreshTokenResponse, error)) *MockService_RefreshToken_Call {
+func (_c *MockService_GetUserByEmail_Call) RunAndReturn(run func(ctx context.Context, email string) (*dbModel.User, error)) *MockService_GetUserByEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Register provides a mock function for the type MockService
-func (_mock *MockService) Register(ctx context.Context, req auth.RegisterRequest) (*auth.RegisterResponse, error) {
-	ret := _mock.Called(ctx, req)
+// ListSessions provides a mock function for the type MockService
+func (_mock *MockService) ListSessions(ctx context.Context, authCtx authenticator.AuthContext) ([]auth.SessionResponse, error) {
+	ret := _mock.Called(ctx, authCtx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Register")
+		panic("no return value specified for ListSessions")
 	}
 
-	var r0 *auth.RegisterResponse
+	var r0 []auth.SessionResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.RegisterRequest) (*auth.RegisterResponse, error)); ok {
-		return returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext) ([]auth.SessionResponse, error)); ok {
+		return returnFunc(ctx, authCtx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.RegisterRequest) *auth.RegisterResponse); ok {
-		r0 = returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext) []auth.SessionResponse); ok {
+		r0 = returnFunc(ctx, authCtx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*auth.RegisterResponse)
+			r0 = ret.Get(0).([]auth.SessionResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, auth.RegisterRequest) error); ok {
-		r1 = returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext) error); ok {
+		r1 = returnFunc(ctx, authCtx)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockService_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
-type MockService_Register_Call struct {
+// MockService_ListSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSessions'
+type MockService_ListSessions_Call struct {
 	*mock.Call
 }
 
-// Register is a helper method to define mock.On call
+// ListSessions is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req auth.RegisterRequest
-func (_e *MockService_Expecter) Register(ctx interface{}, req interface{}) *MockService_Register_Call {
-	return &MockService_Register_Call{Call: _e.mock.On("Register", ctx, req)}
+//   - authCtx authenticator.AuthContext
+func (_e *MockService_Expecter) ListSessions(ctx interface{}, authCtx interface{}) *MockService_ListSessions_Call {
+	return &MockService_ListSessions_Call{Call: _e.mock.On("ListSessions", ctx, authCtx)}
 }
 
-func (_c *MockService_Register_Call) Run(run func(ctx context.Context, req auth.RegisterRequest)) *MockService_Register_Call {
+func (_c *MockService_ListSessions_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext)) *MockService_ListSessions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 auth.RegisterRequest
+		var arg1 authenticator.AuthContext
 		if args[1] != nil {
-			arg1 = args[1].(auth.RegisterRequest)
+			arg1 = args[1].(authenticator.AuthContext)
 		}
 		run(
 			arg0,
@@ -369,12 +559,1119 @@ func (_c *MockService_Register_Call) Run(run func(ctx context.Context, req auth.
 	return _c
 }
 
-func (_c *MockService_Register_Call) Return(registerResponse *auth.RegisterResponse, err error) *MockService_Register_Call {
-	_c.Call.Return(registerResponse, err)
+func (_c *MockService_ListSessions_Call) Return(sessionResponses []auth.SessionResponse, err error) *MockService_ListSessions_Call {
+	_c.Call.Return(sessionResponses, err)
 	return _c
 }
 
-func (_c *MockService_Register_Call) RunAndReturn(run func(ctx context.Context, req auth.RegisterRequest) (*auth.RegisterResponse, error)) *MockService_Register_Call {
+func (_c *MockService_ListSessions_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext) ([]auth.SessionResponse, error)) *MockService_ListSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Login provides a mock function for the type MockService
+func (_mock *MockService) Login(ctx context.Context, req auth.LoginRequest) (*auth.LoginResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *auth.LoginResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.LoginRequest) (*auth.LoginResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.LoginRequest) *auth.LoginResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.LoginResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, auth.LoginRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
+type MockService_Login_Call struct {
+	*mock.Call
+}
+
+// Login is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.LoginRequest
+func (_e *MockService_Expecter) Login(ctx interface{}, req interface{}) *MockService_Login_Call {
+	return &MockService_Login_Call{Call: _e.mock.On("Login", ctx, req)}
+}
+
+func (_c *MockService_Login_Call) Run(run func(ctx context.Context, req auth.LoginRequest)) *MockService_Login_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.LoginRequest
+		if args[1] != nil {
+			arg1 = args[1].(auth.LoginRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Login_Call) Return(loginResponse *auth.LoginResponse, err error) *MockService_Login_Call {
+	_c.Call.Return(loginResponse, err)
+	return _c
+}
+
+func (_c *MockService_Login_Call) RunAndReturn(run func(ctx context.Context, req auth.LoginRequest) (*auth.LoginResponse, error)) *MockService_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function for the type MockService
+func (_mock *MockService) Logout(ctx context.Context, authCtx authenticator.AuthContext, req auth.LogoutRequest) error {
+	ret := _mock.Called(ctx, authCtx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, auth.LogoutRequest) error); ok {
+		r0 = returnFunc(ctx, authCtx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type MockService_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - req auth.LogoutRequest
+func (_e *MockService_Expecter) Logout(ctx interface{}, authCtx interface{}, req interface{}) *MockService_Logout_Call {
+	return &MockService_Logout_Call{Call: _e.mock.On("Logout", ctx, authCtx, req)}
+}
+
+func (_c *MockService_Logout_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.LogoutRequest)) *MockService_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 auth.LogoutRequest
+		if args[2] != nil {
+			arg2 = args[2].(auth.LogoutRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Logout_Call) Return(err error) *MockService_Logout_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_Logout_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.LogoutRequest) error) *MockService_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LogoutAll provides a mock function for the type MockService
+func (_mock *MockService) LogoutAll(ctx context.Context, authCtx authenticator.AuthContext) error {
+	ret := _mock.Called(ctx, authCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogoutAll")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext) error); ok {
+		r0 = returnFunc(ctx, authCtx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_LogoutAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LogoutAll'
+type MockService_LogoutAll_Call struct {
+	*mock.Call
+}
+
+// LogoutAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+func (_e *MockService_Expecter) LogoutAll(ctx interface{}, authCtx interface{}) *MockService_LogoutAll_Call {
+	return &MockService_LogoutAll_Call{Call: _e.mock.On("LogoutAll", ctx, authCtx)}
+}
+
+func (_c *MockService_LogoutAll_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext)) *MockService_LogoutAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_LogoutAll_Call) Return(err error) *MockService_LogoutAll_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_LogoutAll_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext) error) *MockService_LogoutAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MFAStatus provides a mock function for the type MockService
+func (_mock *MockService) MFAStatus(ctx context.Context, authCtx authenticator.AuthContext) (*auth.MFAStatusResponse, error) {
+	ret := _mock.Called(ctx, authCtx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MFAStatus")
+	}
+
+	var r0 *auth.MFAStatusResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext) (*auth.MFAStatusResponse, error)); ok {
+		return returnFunc(ctx, authCtx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext) *auth.MFAStatusResponse); ok {
+		r0 = returnFunc(ctx, authCtx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.MFAStatusResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext) error); ok {
+		r1 = returnFunc(ctx, authCtx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_MFAStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MFAStatus'
+type MockService_MFAStatus_Call struct {
+	*mock.Call
+}
+
+// MFAStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+func (_e *MockService_Expecter) MFAStatus(ctx interface{}, authCtx interface{}) *MockService_MFAStatus_Call {
+	return &MockService_MFAStatus_Call{Call: _e.mock.On("MFAStatus", ctx, authCtx)}
+}
+
+func (_c *MockService_MFAStatus_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext)) *MockService_MFAStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_MFAStatus_Call) Return(mfaStatusResponse *auth.MFAStatusResponse, err error) *MockService_MFAStatus_Call {
+	_c.Call.Return(mfaStatusResponse, err)
+	return _c
+}
+
+func (_c *MockService_MFAStatus_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext) (*auth.MFAStatusResponse, error)) *MockService_MFAStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Me provides a mock function for the type MockService
+func (_mock *MockService) Me(ctx context.Context, userId uint64) (*auth.UserInfo, error) {
+	ret := _mock.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Me")
+	}
+
+	var r0 *auth.UserInfo
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) (*auth.UserInfo, error)); ok {
+		return returnFunc(ctx, userId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) *auth.UserInfo); ok {
+		r0 = returnFunc(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.UserInfo)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = returnFunc(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Me_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Me'
+type MockService_Me_Call struct {
+	*mock.Call
+}
+
+// Me is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId uint64
+func (_e *MockService_Expecter) Me(ctx interface{}, userId interface{}) *MockService_Me_Call {
+	return &MockService_Me_Call{Call: _e.mock.On("Me", ctx, userId)}
+}
+
+func (_c *MockService_Me_Call) Run(run func(ctx context.Context, userId uint64)) *MockService_Me_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Me_Call) Return(userInfo *auth.UserInfo, err error) *MockService_Me_Call {
+	_c.Call.Return(userInfo, err)
+	return _c
+}
+
+func (_c *MockService_Me_Call) RunAndReturn(run func(ctx context.Context, userId uint64) (*auth.UserInfo, error)) *MockService_Me_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OAuthAuthorize provides a mock function for the type MockService
+func (_mock *MockService) OAuthAuthorize(ctx context.Context, provider string) (string, error) {
+	ret := _mock.Called(ctx, provider)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OAuthAuthorize")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, provider)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, provider)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, provider)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_OAuthAuthorize_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OAuthAuthorize'
+type MockService_OAuthAuthorize_Call struct {
+	*mock.Call
+}
+
+// OAuthAuthorize is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+func (_e *MockService_Expecter) OAuthAuthorize(ctx interface{}, provider interface{}) *MockService_OAuthAuthorize_Call {
+	return &MockService_OAuthAuthorize_Call{Call: _e.mock.On("OAuthAuthorize", ctx, provider)}
+}
+
+func (_c *MockService_OAuthAuthorize_Call) Run(run func(ctx context.Context, provider string)) *MockService_OAuthAuthorize_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_OAuthAuthorize_Call) Return(s string, err error) *MockService_OAuthAuthorize_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockService_OAuthAuthorize_Call) RunAndReturn(run func(ctx context.Context, provider string) (string, error)) *MockService_OAuthAuthorize_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OAuthCallback provides a mock function for the type MockService
+func (_mock *MockService) OAuthCallback(ctx context.Context, provider string, req auth.OAuthCallbackRequest) (*auth.LoginResponse, error) {
+	ret := _mock.Called(ctx, provider, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OAuthCallback")
+	}
+
+	var r0 *auth.LoginResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, auth.OAuthCallbackRequest) (*auth.LoginResponse, error)); ok {
+		return returnFunc(ctx, provider, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, auth.OAuthCallbackRequest) *auth.LoginResponse); ok {
+		r0 = returnFunc(ctx, provider, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.LoginResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, auth.OAuthCallbackRequest) error); ok {
+		r1 = returnFunc(ctx, provider, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_OAuthCallback_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OAuthCallback'
+type MockService_OAuthCallback_Call struct {
+	*mock.Call
+}
+
+// OAuthCallback is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - req auth.OAuthCallbackRequest
+func (_e *MockService_Expecter) OAuthCallback(ctx interface{}, provider interface{}, req interface{}) *MockService_OAuthCallback_Call {
+	return &MockService_OAuthCallback_Call{Call: _e.mock.On("OAuthCallback", ctx, provider, req)}
+}
+
+func (_c *MockService_OAuthCallback_Call) Run(run func(ctx context.Context, provider string, req auth.OAuthCallbackRequest)) *MockService_OAuthCallback_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 auth.OAuthCallbackRequest
+		if args[2] != nil {
+			arg2 = args[2].(auth.OAuthCallbackRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_OAuthCallback_Call) Return(loginResponse *auth.LoginResponse, err error) *MockService_OAuthCallback_Call {
+	_c.Call.Return(loginResponse, err)
+	return _c
+}
+
+func (_c *MockService_OAuthCallback_Call) RunAndReturn(run func(ctx context.Context, provider string, req auth.OAuthCallbackRequest) (*auth.LoginResponse, error)) *MockService_OAuthCallback_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RefreshToken provides a mock function for the type MockService
+func (_mock *MockService) RefreshToken(ctx context.Context, req auth.RefreshTokenRequest) (*auth.RefreshTokenResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 *auth.RefreshTokenResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.RefreshTokenRequest) (*auth.RefreshTokenResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.RefreshTokenRequest) *auth.RefreshTokenResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.RefreshTokenResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, auth.RefreshTokenRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_RefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshToken'
+type MockService_RefreshToken_Call struct {
+	*mock.Call
+}
+
+// RefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.RefreshTokenRequest
+func (_e *MockService_Expecter) RefreshToken(ctx interface{}, req interface{}) *MockService_RefreshToken_Call {
+	return &MockService_RefreshToken_Call{Call: _e.mock.On("RefreshToken", ctx, req)}
+}
+
+func (_c *MockService_RefreshToken_Call) Run(run func(ctx context.Context, req auth.RefreshTokenRequest)) *MockService_RefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.RefreshTokenRequest
+		if args[1] != nil {
+			arg1 = args[1].(auth.RefreshTokenRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_RefreshToken_Call) Return(refreshTokenResponse *auth.RefreshTokenResponse, err error) *MockService_RefreshToken_Call {
+	_c.Call.Return(refreshTokenResponse, err)
+	return _c
+}
+
+func (_c *MockService_RefreshToken_Call) RunAndReturn(run func(ctx context.Context, req auth.RefreshTokenRequest) (*auth.RefreshTokenResponse, error)) *MockService_RefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegenerateRecoveryCodes provides a mock function for the type MockService
+func (_mock *MockService) RegenerateRecoveryCodes(ctx context.Context, authCtx authenticator.AuthContext, req auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error) {
+	ret := _mock.Called(ctx, authCtx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 *auth.MFARecoveryCodesResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error)); ok {
+		return returnFunc(ctx, authCtx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, auth.MFACodeRequest) *auth.MFARecoveryCodesResponse); ok {
+		r0 = returnFunc(ctx, authCtx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.MFARecoveryCodesResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, auth.MFACodeRequest) error); ok {
+		r1 = returnFunc(ctx, authCtx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_RegenerateRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegenerateRecoveryCodes'
+type MockService_RegenerateRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// RegenerateRecoveryCodes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - req auth.MFACodeRequest
+func (_e *MockService_Expecter) RegenerateRecoveryCodes(ctx interface{}, authCtx interface{}, req interface{}) *MockService_RegenerateRecoveryCodes_Call {
+	return &MockService_RegenerateRecoveryCodes_Call{Call: _e.mock.On("RegenerateRecoveryCodes", ctx, authCtx, req)}
+}
+
+func (_c *MockService_RegenerateRecoveryCodes_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.MFACodeRequest)) *MockService_RegenerateRecoveryCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 auth.MFACodeRequest
+		if args[2] != nil {
+			arg2 = args[2].(auth.MFACodeRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_RegenerateRecoveryCodes_Call) Return(mfaRecoveryCodesResponse *auth.MFARecoveryCodesResponse, err error) *MockService_RegenerateRecoveryCodes_Call {
+	_c.Call.Return(mfaRecoveryCodesResponse, err)
+	return _c
+}
+
+func (_c *MockService_RegenerateRecoveryCodes_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error)) *MockService_RegenerateRecoveryCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Register provides a mock function for the type MockService
+func (_mock *MockService) Register(ctx context.Context, req auth.RegisterRequest) (*auth.RegisterResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Register")
+	}
+
+	var r0 *auth.RegisterResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.RegisterRequest) (*auth.RegisterResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.RegisterRequest) *auth.RegisterResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.RegisterResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, auth.RegisterRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
+type MockService_Register_Call struct {
+	*mock.Call
+}
+
+// Register is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.RegisterRequest
+func (_e *MockService_Expecter) Register(ctx interface{}, req interface{}) *MockService_Register_Call {
+	return &MockService_Register_Call{Call: _e.mock.On("Register", ctx, req)}
+}
+
+func (_c *MockService_Register_Call) Run(run func(ctx context.Context, req auth.RegisterRequest)) *MockService_Register_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.RegisterRequest
+		if args[1] != nil {
+			arg1 = args[1].(auth.RegisterRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Register_Call) Return(registerResponse *auth.RegisterResponse, err error) *MockService_Register_Call {
+	_c.Call.Return(registerResponse, err)
+	return _c
+}
+
+func (_c *MockService_Register_Call) RunAndReturn(run func(ctx context.Context, req auth.RegisterRequest) (*auth.RegisterResponse, error)) *MockService_Register_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResendVerification provides a mock function for the type MockService
+func (_mock *MockService) ResendVerification(ctx context.Context, req auth.ResendVerificationRequest) error {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendVerification")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.ResendVerificationRequest) error); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ResendVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResendVerification'
+type MockService_ResendVerification_Call struct {
+	*mock.Call
+}
+
+// ResendVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.ResendVerificationRequest
+func (_e *MockService_Expecter) ResendVerification(ctx interface{}, req interface{}) *MockService_ResendVerification_Call {
+	return &MockService_ResendVerification_Call{Call: _e.mock.On("ResendVerification", ctx, req)}
+}
+
+func (_c *MockService_ResendVerification_Call) Run(run func(ctx context.Context, req auth.ResendVerificationRequest)) *MockService_ResendVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.ResendVerificationRequest
+		if args[1] != nil {
+			arg1 = args[1].(auth.ResendVerificationRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ResendVerification_Call) Return(err error) *MockService_ResendVerification_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ResendVerification_Call) RunAndReturn(run func(ctx context.Context, req auth.ResendVerificationRequest) error) *MockService_ResendVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function for the type MockService
+func (_mock *MockService) ResetPassword(ctx context.Context, req auth.ResetPasswordRequest) error {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.ResetPasswordRequest) error); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockService_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.ResetPasswordRequest
+func (_e *MockService_Expecter) ResetPassword(ctx interface{}, req interface{}) *MockService_ResetPassword_Call {
+	return &MockService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, req)}
+}
+
+func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, req auth.ResetPasswordRequest)) *MockService_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.ResetPasswordRequest
+		if args[1] != nil {
+			arg1 = args[1].(auth.ResetPasswordRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ResetPassword_Call) Return(err error) *MockService_ResetPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, req auth.ResetPasswordRequest) error) *MockService_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function for the type MockService
+func (_mock *MockService) RevokeSession(ctx context.Context, authCtx authenticator.AuthContext, sessionID int64) error {
+	ret := _mock.Called(ctx, authCtx, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, int64) error); ok {
+		r0 = returnFunc(ctx, authCtx, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type MockService_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - sessionID int64
+func (_e *MockService_Expecter) RevokeSession(ctx interface{}, authCtx interface{}, sessionID interface{}) *MockService_RevokeSession_Call {
+	return &MockService_RevokeSession_Call{Call: _e.mock.On("RevokeSession", ctx, authCtx, sessionID)}
+}
+
+func (_c *MockService_RevokeSession_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, sessionID int64)) *MockService_RevokeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_RevokeSession_Call) Return(err error) *MockService_RevokeSession_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_RevokeSession_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, sessionID int64) error) *MockService_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetupMFA provides a mock function for the type MockService
+func (_mock *MockService) SetupMFA(ctx context.Context, req auth.MFASetupRequest) (*auth.MFAEnrollResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetupMFA")
+	}
+
+	var r0 *auth.MFAEnrollResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.MFASetupRequest) (*auth.MFAEnrollResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.MFASetupRequest) *auth.MFAEnrollResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.MFAEnrollResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, auth.MFASetupRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_SetupMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetupMFA'
+type MockService_SetupMFA_Call struct {
+	*mock.Call
+}
+
+// SetupMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.MFASetupRequest
+func (_e *MockService_Expecter) SetupMFA(ctx interface{}, req interface{}) *MockService_SetupMFA_Call {
+	return &MockService_SetupMFA_Call{Call: _e.mock.On("SetupMFA", ctx, req)}
+}
+
+func (_c *MockService_SetupMFA_Call) Run(run func(ctx context.Context, req auth.MFASetupRequest)) *MockService_SetupMFA_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.MFASetupRequest
+		if args[1] != nil {
+			arg1 = args[1].(auth.MFASetupRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_SetupMFA_Call) Return(mfaEnrollResponse *auth.MFAEnrollResponse, err error) *MockService_SetupMFA_Call {
+	_c.Call.Return(mfaEnrollResponse, err)
+	return _c
+}
+
+func (_c *MockService_SetupMFA_Call) RunAndReturn(run func(ctx context.Context, req auth.MFASetupRequest) (*auth.MFAEnrollResponse, error)) *MockService_SetupMFA_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnlockLogin provides a mock function for the type MockService
+func (_mock *MockService) UnlockLogin(ctx context.Context, authCtx authenticator.AuthContext, req auth.UnlockLoginRequest) error {
+	ret := _mock.Called(ctx, authCtx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockLogin")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, auth.UnlockLoginRequest) error); ok {
+		r0 = returnFunc(ctx, authCtx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_UnlockLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlockLogin'
+type MockService_UnlockLogin_Call struct {
+	*mock.Call
+}
+
+// UnlockLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - req auth.UnlockLoginRequest
+func (_e *MockService_Expecter) UnlockLogin(ctx interface{}, authCtx interface{}, req interface{}) *MockService_UnlockLogin_Call {
+	return &MockService_UnlockLogin_Call{Call: _e.mock.On("UnlockLogin", ctx, authCtx, req)}
+}
+
+func (_c *MockService_UnlockLogin_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.UnlockLoginRequest)) *MockService_UnlockLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 auth.UnlockLoginRequest
+		if args[2] != nil {
+			arg2 = args[2].(auth.UnlockLoginRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_UnlockLogin_Call) Return(err error) *MockService_UnlockLogin_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_UnlockLogin_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, req auth.UnlockLoginRequest) error) *MockService_UnlockLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyEmail provides a mock function for the type MockService
+func (_mock *MockService) VerifyEmail(ctx context.Context, req auth.VerifyEmailRequest) error {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.VerifyEmailRequest) error); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_VerifyEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyEmail'
+type MockService_VerifyEmail_Call struct {
+	*mock.Call
+}
+
+// VerifyEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.VerifyEmailRequest
+func (_e *MockService_Expecter) VerifyEmail(ctx interface{}, req interface{}) *MockService_VerifyEmail_Call {
+	return &MockService_VerifyEmail_Call{Call: _e.mock.On("VerifyEmail", ctx, req)}
+}
+
+func (_c *MockService_VerifyEmail_Call) Run(run func(ctx context.Context, req auth.VerifyEmailRequest)) *MockService_VerifyEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.VerifyEmailRequest
+		if args[1] != nil {
+			arg1 = args[1].(auth.VerifyEmailRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_VerifyEmail_Call) Return(err error) *MockService_VerifyEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_VerifyEmail_Call) RunAndReturn(run func(ctx context.Context, req auth.VerifyEmailRequest) error) *MockService_VerifyEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyMFA provides a mock function for the type MockService
+func (_mock *MockService) VerifyMFA(ctx context.Context, req auth.MFAVerifyRequest) (*auth.LoginResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyMFA")
+	}
+
+	var r0 *auth.LoginResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.MFAVerifyRequest) (*auth.LoginResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, auth.MFAVerifyRequest) *auth.LoginResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.LoginResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, auth.MFAVerifyRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_VerifyMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyMFA'
+type MockService_VerifyMFA_Call struct {
+	*mock.Call
+}
+
+// VerifyMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req auth.MFAVerifyRequest
+func (_e *MockService_Expecter) VerifyMFA(ctx interface{}, req interface{}) *MockService_VerifyMFA_Call {
+	return &MockService_VerifyMFA_Call{Call: _e.mock.On("VerifyMFA", ctx, req)}
+}
+
+func (_c *MockService_VerifyMFA_Call) Run(run func(ctx context.Context, req auth.MFAVerifyRequest)) *MockService_VerifyMFA_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 auth.MFAVerifyRequest
+		if args[1] != nil {
+			arg1 = args[1].(auth.MFAVerifyRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_VerifyMFA_Call) Return(loginResponse *auth.LoginResponse, err error) *MockService_VerifyMFA_Call {
+	_c.Call.Return(loginResponse, err)
+	return _c
+}
+
+func (_c *MockService_VerifyMFA_Call) RunAndReturn(run func(ctx context.Context, req auth.MFAVerifyRequest) (*auth.LoginResponse, error)) *MockService_VerifyMFA_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	authDto "ichi-go/internal/applications/auth/dto"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"strings"
+	"time"
+)
+
+// OAuthAuthorize starts an OpenID Connect login and returns the provider URL to redirect to.
+func (s *ServiceImpl) OAuthAuthorize(ctx context.Context, provider string) (string, error) {
+	if s.oidc == nil {
+		return "", oauthDisabledError(provider)
+	}
+
+	authURL, err := s.oidc.Begin(ctx, provider)
+	if errors.Is(err, authenticator.ErrUnknownOIDCProvider) {
+		return "", unknownOAuthProviderError(provider)
+	}
+	if err != nil {
+		return "", pkgErrors.AuthService(pkgErrors.ErrCodeInternal).
+			With("provider", provider).
+			Hint("Identity provider is unavailable, please try again later").
+			Wrap(err)
+	}
+	return authURL, nil
+}
+
+// OAuthCallback finishes an OpenID Connect login. The external identity is linked to
+// a local user, who is created on first login, and the login continues as a password login would.
+func (s *ServiceImpl) OAuthCallback(ctx context.Context, provider string, req authDto.OAuthCallbackRequest) (*authDto.LoginResponse, error) {
+	if s.oidc == nil {
+		return nil, oauthDisabledError(provider)
+	}
+	if req.Error != "" {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeOAuthFailed).
+			With("provider", provider).
+			With("provider_error", req.Error).
+			Hint("Login was cancelled or denied at the identity provider").
+			Errorf("provider returned %s: %s", req.Error, req.ErrorDescription)
+	}
+
+	identity, err := s.oidc.Complete(ctx, provider, req.State, req.Code)
+	switch {
+	case errors.Is(err, authenticator.ErrUnknownOIDCProvider):
+		return nil, unknownOAuthProviderError(provider)
+	case errors.Is(err, authenticator.ErrInvalidOIDCState):
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeOAuthFailed).
+			With("provider", provider).
+			Hint("Login session expired or was already used, please try again").
+			Wrap(err)
+	case err != nil:
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeOAuthFailed).
+			With("provider", provider).
+			Hint("Could not verify the login with the identity provider").
+			Wrap(err)
+	}
+
+	user, err := s.userForOIDCIdentity(ctx, provider, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user)
+}
+
+// userForOIDCIdentity returns the user linked to identity. An unlinked identity is
+// linked to the user with the same email once both the provider and the account
+// verified it, or to a newly provisioned user.
+func (s *ServiceImpl) userForOIDCIdentity(ctx context.Context, provider string, identity *authenticator.OIDCIdentity) (*model.User, error) {
+	now := time.Now()
+
+	linked, err := s.identities.FindByProviderSubject(ctx, provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if linked != nil {
+		user, err := s.userRepo.GetById(ctx, linked.UserID)
+		if err != nil || user == nil {
+			return nil, pkgErrors.AuthService(pkgErrors.ErrCodeUserNotFound).
+				With("user_id", linked.UserID).
+				With("provider", provider).
+				Hint("The linked account no longer exists").
+				Errorf("user not found")
+		}
+		if err := s.identities.TouchLogin(ctx, linked.ID, identity.Email, now); err != nil {
+			logger.Warnf("Failed to record login of identity %d: %v", linked.ID, err)
+		}
+		return user, nil
+	}
+
+	user, err := s.userForNewOIDCIdentity(ctx, provider, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	link := &model.UserIdentity{
+		UserID:      uint64(user.ID),
+		Provider:    provider,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		LastLoginAt: nullTime(&now),
+	}
+	if err := s.identities.Create(ctx, link); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *ServiceImpl) userForNewOIDCIdentity(ctx context.Context, provider string, identity *authenticator.OIDCIdentity) (*model.User, error) {
+	if identity.Email == "" {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeOAuthFailed).
+			With("provider", provider).
+			Hint("The identity provider did not share an email address").
+			Errorf("id_token has no email")
+	}
+
+	existing, err := s.GetUserByEmail(ctx, identity.Email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if existing != nil {
+		// Only a provider-verified email proves the caller owns the local account.
+		if !identity.EmailVerified {
+			return nil, pkgErrors.AuthService(pkgErrors.ErrCodeUserExists).
+				With("provider", provider).
+				With("user_id", existing.ID).
+				Hint("An account with this email already exists, please sign in with your password").
+				Errorf("email not verified by provider")
+		}
+		// An unverified account may have been registered by someone else with this
+		// address; linking it would leave them its password and sessions.
+		if !existing.IsEmailVerified() {
+			return nil, pkgErrors.AuthService(pkgErrors.ErrCodeUserExists).
+				With("provider", provider).
+				With("user_id", existing.ID).
+				Hint("An account with this email already exists, please sign in with your password and verify your email first").
+				Errorf("local account email not verified")
+		}
+		return existing, nil
+	}
+
+	return s.provisionOIDCUser(ctx, provider, identity)
+}
+
+// provisionOIDCUser creates the local user of a first external login. The user gets
+// a random password, so it can only sign in through the provider or after a reset.
+func (s *ServiceImpl) provisionOIDCUser(ctx context.Context, provider string, identity *authenticator.OIDCIdentity) (*model.User, error) {
+	password, err := randomPassword()
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodePasswordHashFailed).Wrap(err)
+	}
+	hashedPassword, err := s.HashPassword(password)
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodePasswordHashFailed).
+			With("provider", provider).
+			Hint("Failed to process password").
+			Wrap(err)
+	}
+
+	newUser := model.User{
+		Name:     oidcUserName(identity),
+		Email:    identity.Email,
+		Password: hashedPassword,
+	}
+	if identity.EmailVerified {
+		now := time.Now()
+		newUser.EmailVerifiedAt = nullTime(&now)
+	}
+	userID, err := s.userRepo.Create(ctx, newUser)
+	if err != nil {
+		// User names are unique; retry once with a random suffix.
+		suffix, suffixErr := randomHex(3)
+		if suffixErr != nil {
+			return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInternal).Wrap(suffixErr)
+		}
+		newUser.Name = truncate(newUser.Name, 90) + "-" + suffix
+		userID, err = s.userRepo.Create(ctx, newUser)
+	}
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeDatabase).
+			With("email", identity.Email).
+			With("provider", provider).
+			Hint("Failed to create user account").
+			Wrap(err)
+	}
+
+	user, err := s.userRepo.GetById(ctx, uint64(userID))
+	if err != nil || user == nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeDatabase).
+			With("user_id", userID).
+			Hint("Failed to retrieve created user").
+			Wrap(err)
+	}
+
+	if err := s.EnqueueWelcomeNotification(ctx, uint32(userID)); err != nil {
+		logger.Errorf("%v", pkgErrors.Queue(pkgErrors.ErrCodeNotificationFailed).
+			With("user_id", userID).
+			Hint("Welcome notification could not be queued").
+			Wrap(err))
+	}
+	return user, nil
+}
+
+// oidcUserName prefers the provider's display name, falling back to the email's local part.
+func oidcUserName(identity *authenticator.OIDCIdentity) string {
+	name := strings.TrimSpace(identity.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+	return truncate(name, 100)
+}
+
+func oauthDisabledError(provider string) error {
+	return pkgErrors.AuthService(pkgErrors.ErrCodeNotFound).
+		With("provider", provider).
+		Hint("OpenID Connect login is not enabled").
+		Errorf("oidc disabled")
+}
+
+func unknownOAuthProviderError(provider string) error {
+	return pkgErrors.AuthService(pkgErrors.ErrCodeNotFound).
+		With("provider", provider).
+		Hint("Unknown login provider").
+		Errorf("unknown oidc provider %q", provider)
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
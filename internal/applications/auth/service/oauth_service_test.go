@@ -0,0 +1,462 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	authDto "ichi-go/internal/applications/auth/dto"
+	userRepo "ichi-go/internal/applications/user/repository"
+	"ichi-go/pkg/authenticator"
+	dbModel "ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+const oidcTestRedirectURL = "http://localhost:8080/ichi-go/api/202601/auth/oauth/fake/callback"
+
+// fakeOIDCProvider is a local OpenID Connect provider. Its authorize endpoint
+// signs in the configured account without a login page and redirects back with a code.
+type fakeOIDCProvider struct {
+	t   *testing.T
+	srv *httptest.Server
+	key *ecdsa.PrivateKey
+
+	mu      sync.Mutex
+	account jwt.MapClaims // claims of the next user to sign in
+	codes   map[string]fakeAuthorization
+}
+
+type fakeAuthorization struct {
+	clientID    string
+	redirectURI string
+	challenge   string
+	claims      jwt.MapClaims
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p := &fakeOIDCProvider{t: t, key: key, codes: make(map[string]fakeAuthorization)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.srv.URL,
+			"authorization_endpoint": p.srv.URL + "/authorize",
+			"token_endpoint":         p.srv.URL + "/token",
+			"jwks_uri":               p.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		jwk, err := authenticator.NewJWK("fake-1", "ES256", &p.key.PublicKey)
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(authenticator.JWKS{Keys: []authenticator.JWK{jwk}})
+	})
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	p.srv = httptest.NewServer(mux)
+	t.Cleanup(p.srv.Close)
+	return p
+}
+
+func (p *fakeOIDCProvider) signInAs(claims jwt.MapClaims) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.account = claims
+}
+
+func (p *fakeOIDCProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "unsupported request", http.StatusBadRequest)
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   p.srv.URL,
+		"aud":   query.Get("client_id"),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	p.mu.Lock()
+	for k, v := range p.account {
+		claims[k] = v
+	}
+	code := base64.RawURLEncoding.EncodeToString([]byte(query.Get("state")))[:16]
+	p.codes[code] = fakeAuthorization{
+		clientID:    query.Get("client_id"),
+		redirectURI: query.Get("redirect_uri"),
+		challenge:   query.Get("code_challenge"),
+		claims:      claims,
+	}
+	p.mu.Unlock()
+
+	callback, _ := url.Parse(query.Get("redirect_uri"))
+	callback.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, callback.String(), http.StatusFound)
+}
+
+func (p *fakeOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	require.NoError(p.t, r.ParseForm())
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	auth, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	verifierSum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok ||
+		r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("client_id") != auth.clientID ||
+		r.PostForm.Get("redirect_uri") != auth.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(verifierSum[:]) != auth.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodES256, auth.claims)
+	idToken.Header["kid"] = "fake-1"
+	signed, err := idToken.SignedString(p.key)
+	require.NoError(p.t, err)
+	_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "provider-token", "id_token": signed, "token_type": "Bearer"})
+}
+
+// login runs the browser side of a login: follow our redirect to the provider and
+// return the parameters it sends back to the callback.
+func (p *fakeOIDCProvider) login(t *testing.T, svc *ServiceImpl) authDto.OAuthCallbackRequest {
+	t.Helper()
+	authURL, err := svc.OAuthAuthorize(context.Background(), "fake")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(authURL, p.srv.URL+"/authorize?"))
+
+	browser := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := browser.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(callback.String(), oidcTestRedirectURL))
+	return authDto.OAuthCallbackRequest{Code: callback.Query().Get("code"), State: callback.Query().Get("state")}
+}
+
+// fakeUserRepository stores users in memory with the unique name and email of the users table.
+type fakeUserRepository struct {
+	mu     sync.Mutex
+	nextID int64
+	users  map[int64]*dbModel.User
+}
+
+func newFakeUserRepository() *fakeUserRepository {
+	return &fakeUserRepository{users: make(map[int64]*dbModel.User)}
+}
+
+func (r *fakeUserRepository) GetById(_ context.Context, id uint64) (*dbModel.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[int64(id)]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepository) FindByEmail(_ context.Context, email string) (*dbModel.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeUserRepository) Create(_ context.Context, newUser dbModel.User) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Name == newUser.Name || user.Email == newUser.Email {
+			return 0, errors.New("duplicate entry")
+		}
+	}
+	r.nextID++
+	newUser.ID = r.nextID
+	newUser.CreatedAt = time.Now()
+	r.users[newUser.ID] = &newUser
+	return newUser.ID, nil
+}
+
+func (r *fakeUserRepository) Update(_ context.Context, updateUser dbModel.User) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[updateUser.ID] = &updateUser
+	return updateUser.ID, nil
+}
+
+func (r *fakeUserRepository) MarkEmailVerified(_ context.Context, id uint64, email string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[int64(id)]
+	if !ok || user.Email != email || user.IsEmailVerified() {
+		return false, nil
+	}
+	user.EmailVerifiedAt = bun.NullTime{Time: time.Now()}
+	return true, nil
+}
+
+func (r *fakeUserRepository) SetAvatar(_ context.Context, id uint64, path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, ok := r.users[int64(id)]; ok {
+		user.AvatarPath = path
+	}
+	return nil
+}
+
+func (r *fakeUserRepository) List(_ context.Context, _ userRepo.ListFilter) ([]*dbModel.User, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := make([]*dbModel.User, 0, len(r.users))
+	for _, user := range r.users {
+		copied := *user
+		users = append(users, &copied)
+	}
+	return users, len(users), nil
+}
+
+func (r *fakeUserRepository) GetByIdWithDeleted(ctx context.Context, id uint64) (*dbModel.User, error) {
+	return r.GetById(ctx, id)
+}
+
+func (r *fakeUserRepository) SoftDelete(ctx context.Context, id int64) error {
+	return r.HardDelete(ctx, uint64(id))
+}
+
+func (r *fakeUserRepository) Restore(_ context.Context, _ int64) error {
+	return nil
+}
+
+func (r *fakeUserRepository) HardDelete(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, int64(id))
+	return nil
+}
+
+type fakeUserIdentityRepository struct {
+	mu         sync.Mutex
+	nextID     int64
+	identities map[int64]*dbModel.UserIdentity
+}
+
+func newFakeUserIdentityRepository() *fakeUserIdentityRepository {
+	return &fakeUserIdentityRepository{identities: make(map[int64]*dbModel.UserIdentity)}
+}
+
+func (r *fakeUserIdentityRepository) Create(_ context.Context, identity *dbModel.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	identity.ID = r.nextID
+	copied := *identity
+	r.identities[identity.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserIdentityRepository) FindByProviderSubject(_ context.Context, provider, subject string) (*dbModel.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserIdentityRepository) TouchLogin(_ context.Context, id int64, email string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if identity, ok := r.identities[id]; ok {
+		identity.Email = email
+		identity.LastLoginAt.Time = at
+	}
+	return nil
+}
+
+func newOAuthTestService(t *testing.T) (*ServiceImpl, *fakeOIDCProvider, *fakeUserRepository, *fakeUserIdentityRepository) {
+	t.Helper()
+	provider := newFakeOIDCProvider(t)
+
+	jwtAuth := authenticator.NewJWTAuthenticator(&authenticator.JWTConfig{
+		SigningMethod:   jwt.SigningMethodHS256,
+		SecretKey:       []byte("test-secret-key-minimum-32-chars-long"),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+		AuthScheme:      "Bearer",
+	})
+	oidc := authenticator.NewOIDC(&authenticator.OIDCConfig{
+		Providers: map[string]authenticator.OIDCProviderConfig{
+			"fake": {Issuer: provider.srv.URL, ClientID: "ichi-client", ClientSecret: "s3cret", RedirectURL: oidcTestRedirectURL},
+		},
+	}, authenticator.NewMemoryOIDCStateStore(), provider.srv.Client())
+
+	users := newFakeUserRepository()
+	identities := newFakeUserIdentityRepository()
+	svc := NewAuthService(users, newFakeSessionRepository(), newFakePasswordResetRepository(), jwtAuth, nil, Options{
+		OIDC:       oidc,
+		Identities: identities,
+	})
+	return svc, provider, users, identities
+}
+
+func TestOAuthCallback_ProvisionsUserOnFirstLoginAndReusesLink(t *testing.T) {
+	svc, provider, users, identities := newOAuthTestService(t)
+	ctx := context.Background()
+	provider.signInAs(jwt.MapClaims{"sub": "ext-123", "email": "jane@example.com", "email_verified": true, "name": "Jane Doe"})
+
+	first, err := svc.OAuthCallback(ctx, "fake", provider.login(t, svc))
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", first.User.Email)
+	assert.Equal(t, "Jane Doe", first.User.Name)
+	assert.NotEmpty(t, first.AccessToken)
+	assert.NotEmpty(t, first.RefreshToken)
+	assert.Equal(t, first.User.ID, authContextFor(t, first.AccessToken).UserID.ID)
+
+	link, err := identities.FindByProviderSubject(ctx, "fake", "ext-123")
+	require.NoError(t, err)
+	require.NotNil(t, link)
+	assert.Equal(t, first.User.ID, link.UserID)
+
+	// The provider may change the email; the subject keeps identifying the same user.
+	provider.signInAs(jwt.MapClaims{"sub": "ext-123", "email": "jane@new.example.com", "email_verified": true})
+	second, err := svc.OAuthCallback(ctx, "fake", provider.login(t, svc))
+	require.NoError(t, err)
+	assert.Equal(t, first.User.ID, second.User.ID)
+	assert.Len(t, users.users, 1)
+
+	// The refresh token is one of ours and rotates like any other session.
+	_, err = svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: second.RefreshToken})
+	assert.NoError(t, err)
+}
+
+func TestOAuthCallback_LinksExistingUserOnlyWithVerifiedEmail(t *testing.T) {
+	svc, provider, users, _ := newOAuthTestService(t)
+	ctx := context.Background()
+	existingID, err := users.Create(ctx, dbModel.User{
+		Name: "jane", Email: "jane@example.com", Password: "hash",
+		EmailVerifiedAt: bun.NullTime{Time: time.Now()},
+	})
+	require.NoError(t, err)
+
+	provider.signInAs(jwt.MapClaims{"sub": "ext-1", "email": "jane@example.com", "email_verified": false})
+	_, err = svc.OAuthCallback(ctx, "fake", provider.login(t, svc))
+	assertErrorCode(t, err, pkgErrors.ErrCodeUserExists)
+
+	provider.signInAs(jwt.MapClaims{"sub": "ext-1", "email": "jane@example.com", "email_verified": true})
+	resp, err := svc.OAuthCallback(ctx, "fake", provider.login(t, svc))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(existingID), resp.User.ID)
+}
+
+func TestOAuthCallback_RefusesToLinkUnverifiedLocalAccount(t *testing.T) {
+	svc, provider, users, identities := newOAuthTestService(t)
+	ctx := context.Background()
+	// Someone registered the address first and never verified it.
+	squatterID, err := users.Create(ctx, dbModel.User{Name: "squatter", Email: "jane@example.com", Password: "hash"})
+	require.NoError(t, err)
+
+	provider.signInAs(jwt.MapClaims{"sub": "ext-1", "email": "jane@example.com", "email_verified": true})
+	_, err = svc.OAuthCallback(ctx, "fake", provider.login(t, svc))
+	assertErrorCode(t, err, pkgErrors.ErrCodeUserExists)
+
+	link, err := identities.FindByProviderSubject(ctx, "fake", "ext-1")
+	require.NoError(t, err)
+	assert.Nil(t, link, "the identity is not linked to the unverified account")
+	assert.False(t, users.users[squatterID].IsEmailVerified(), "the provider's verification is not taken over")
+}
+
+func TestOAuthCallback_ProvisioningAvoidsTakenNames(t *testing.T) {
+	svc, provider, users, _ := newOAuthTestService(t)
+	ctx := context.Background()
+	_, err := users.Create(ctx, dbModel.User{Name: "jane", Email: "other@example.com", Password: "hash"})
+	require.NoError(t, err)
+
+	// Without a display name the email's local part is used.
+	provider.signInAs(jwt.MapClaims{"sub": "ext-2", "email": "jane@example.com", "email_verified": true})
+	resp, err := svc.OAuthCallback(ctx, "fake", provider.login(t, svc))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.User.Name, "jane-"), resp.User.Name)
+}
+
+func TestOAuthCallback_RejectsFailedLogins(t *testing.T) {
+	svc, provider, _, _ := newOAuthTestService(t)
+	ctx := context.Background()
+
+	t.Run("provider error", func(t *testing.T) {
+		_, err := svc.OAuthCallback(ctx, "fake", authDto.OAuthCallbackRequest{Error: "access_denied"})
+		assertErrorCode(t, err, pkgErrors.ErrCodeOAuthFailed)
+	})
+
+	t.Run("replayed callback", func(t *testing.T) {
+		provider.signInAs(jwt.MapClaims{"sub": "ext-3", "email": "sam@example.com", "email_verified": true})
+		req := provider.login(t, svc)
+		_, err := svc.OAuthCallback(ctx, "fake", req)
+		require.NoError(t, err)
+
+		_, err = svc.OAuthCallback(ctx, "fake", req)
+		assertErrorCode(t, err, pkgErrors.ErrCodeOAuthFailed)
+	})
+
+	t.Run("tampered code", func(t *testing.T) {
+		provider.signInAs(jwt.MapClaims{"sub": "ext-4", "email": "kim@example.com", "email_verified": true})
+		req := provider.login(t, svc)
+		req.Code = "stolen-code"
+		_, err := svc.OAuthCallback(ctx, "fake", req)
+		assertErrorCode(t, err, pkgErrors.ErrCodeOAuthFailed)
+	})
+
+	t.Run("no email", func(t *testing.T) {
+		provider.signInAs(jwt.MapClaims{"sub": "ext-5"})
+		_, err := svc.OAuthCallback(ctx, "fake", provider.login(t, svc))
+		assertErrorCode(t, err, pkgErrors.ErrCodeOAuthFailed)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := svc.OAuthAuthorize(ctx, "nope")
+		assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+	})
+}
+
+func TestOAuth_DisabledWithoutOIDC(t *testing.T) {
+	svc, _, _ := newSessionTestService(t)
+
+	_, err := svc.OAuthAuthorize(context.Background(), "google")
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+}
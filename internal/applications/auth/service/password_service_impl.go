@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	authDto "ichi-go/internal/applications/auth/dto"
+	authRepo "ichi-go/internal/applications/auth/repository"
+	notifDto "ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/template/builtin"
+	"ichi-go/pkg/requestctx"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPasswordResetTTL = 30 * time.Minute
+	resetTokenBytes         = 32
+	notificationRoutingKey  = "notification.dispatch"
+)
+
+// ChangePassword replaces the password of the current user after verifying the old one.
+// Every other session of the user is revoked; the session making the request stays signed in.
+func (s *ServiceImpl) ChangePassword(ctx context.Context, authCtx authenticator.AuthContext, req authDto.ChangePasswordRequest) error {
+	userID := authCtx.UserID.ID
+
+	user, err := s.userRepo.GetById(ctx, userID)
+	if err != nil || user == nil {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeUserNotFound).
+			With("user_id", userID).
+			Hint("User not found").
+			Errorf("user not found")
+	}
+
+	if !s.VerifyPassword(user.Password, req.OldPassword) {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeInvalidCredentials).
+			With("user_id", userID).
+			Hint("Current password is incorrect").
+			Errorf("password verification failed")
+	}
+
+	if req.OldPassword == req.NewPassword {
+		return pkgErrors.AuthService(pkgErrors.ErrCodePasswordWeak).
+			With("user_id", userID).
+			Hint("New password must be different from the current password").
+			Errorf("new password equals old password")
+	}
+
+	if err := s.updatePassword(ctx, userID, req.NewPassword); err != nil {
+		return err
+	}
+
+	currentSessionID, _ := authenticator.GetClaimString(authCtx.Claims, authenticator.SessionIDClaim)
+	if err := s.revokeOtherSessions(ctx, userID, currentSessionID); err != nil {
+		return err
+	}
+
+	logger.Infof("User %d changed password", userID)
+	return nil
+}
+
+// ForgotPassword emails a single-use reset link to the account owner.
+// It succeeds whether or not the email is registered so callers cannot probe for accounts:
+// the lookup and the email happen after the response, and their failures are only logged.
+// Requests are limited per address and per client IP.
+func (s *ServiceImpl) ForgotPassword(ctx context.Context, req authDto.ForgotPasswordRequest) error {
+	if err := checkEmailLimit(ctx, s.resetLimiter.Allow, req.Email, "password reset emails"); err != nil {
+		return err
+	}
+
+	// Detached from the request, but keeping its values (client IP) for the reset record.
+	ctx = context.WithoutCancel(ctx)
+	s.background.Go(func() {
+		s.sendPasswordReset(ctx, req.Email)
+	})
+	return nil
+}
+
+// sendPasswordReset issues a reset link for the account of email, if there is one
+func (s *ServiceImpl) sendPasswordReset(ctx context.Context, email string) {
+	user, err := s.GetUserByEmail(ctx, email)
+	if err != nil || user == nil {
+		logger.Debugf("Password reset requested for unknown email")
+		return
+	}
+
+	if err := s.issuePasswordReset(ctx, user); err != nil {
+		logger.Errorf("%v", pkgErrors.AuthService(pkgErrors.ErrCodeNotificationFailed).
+			With("user_id", user.ID).
+			Hint("Password reset email could not be sent").
+			Wrap(err))
+	}
+}
+
+// ResetPassword redeems a reset token, sets the new password and signs the user out everywhere
+func (s *ServiceImpl) ResetPassword(ctx context.Context, req authDto.ResetPasswordRequest) error {
+	reset, err := s.resetRepo.FindValidByTokenHash(ctx, hashResetToken(req.Token))
+	if err != nil {
+		return err
+	}
+	if reset == nil {
+		return invalidResetTokenError()
+	}
+
+	// Redeem before changing anything so concurrent requests cannot both succeed.
+	redeemed, err := s.resetRepo.MarkUsed(ctx, reset.ID)
+	if err != nil {
+		return err
+	}
+	if !redeemed {
+		return invalidResetTokenError()
+	}
+
+	if err := s.updatePassword(ctx, reset.UserID, req.NewPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllForUser(ctx, reset.UserID, authRepo.RevokedReasonPasswordReset); err != nil {
+		return err
+	}
+	if s.jwtAuth.RevocationEnabled() {
+		if err := s.jwtAuth.RevokeAllForUser(ctx, reset.UserID); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("User %d reset password", reset.UserID)
+	return nil
+}
+
+// issuePasswordReset replaces any outstanding reset token of the user with a new one
+// and publishes the auth.password_reset notification carrying it
+func (s *ServiceImpl) issuePasswordReset(ctx context.Context, user *model.User) error {
+	if s.producer == nil {
+		return fmt.Errorf("queue not configured")
+	}
+
+	token, err := newResetToken()
+	if err != nil {
+		return err
+	}
+
+	userID := uint64(user.ID)
+	if err := s.resetRepo.InvalidateForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	reset := &model.PasswordReset{
+		UserID:      userID,
+		TokenHash:   hashResetToken(token),
+		ExpiresAt:   time.Now().Add(s.resetConfig.TokenTTL),
+		RequestedIP: requestctx.FromContext(ctx).ClientIP,
+	}
+	if err := s.resetRepo.Create(ctx, reset); err != nil {
+		return err
+	}
+
+	event := notifDto.NotificationEvent{
+		EventID:      fmt.Sprintf("password-reset-%d", reset.ID),
+		EventType:    builtin.PasswordResetTemplate{}.Slug(),
+		DeliveryMode: notifDto.DeliveryModeUser,
+		Channels:     []notifDto.Channel{notifDto.ChannelEmail},
+		UserID:       fmt.Sprintf("%d", user.ID),
+		Data: map[string]any{
+			"name":       user.Name,
+			"email":      user.Email,
+			"reset_url":  tokenURL(s.resetConfig.ResetURL, token),
+			"expires_in": formatTokenTTL(s.resetConfig.TokenTTL),
+		},
+		Meta: map[string]string{"source": "auth"},
+	}
+	return s.producer.Publish(ctx, notificationRoutingKey, event, rabbitmq.PublishOptions{})
+}
+
+// updatePassword hashes and stores a new password for the user
+func (s *ServiceImpl) updatePassword(ctx context.Context, userID uint64, password string) error {
+	hashedPassword, err := s.HashPassword(password)
+	if err != nil {
+		return pkgErrors.AuthService(pkgErrors.ErrCodePasswordHashFailed).
+			With("user_id", userID).
+			Hint("Failed to process password").
+			Wrap(err)
+	}
+
+	user := model.User{Password: hashedPassword}
+	user.ID = int64(userID)
+	if _, err := s.userRepo.Update(ctx, user); err != nil {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeDatabase).
+			With("user_id", userID).
+			Hint("Failed to update password").
+			Wrap(err)
+	}
+	return nil
+}
+
+// revokeOtherSessions ends every active session of the user except keepFamilyID
+func (s *ServiceImpl) revokeOtherSessions(ctx context.Context, userID uint64, keepFamilyID string) error {
+	sessions, err := s.sessionRepo.FindActiveByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if keepFamilyID != "" && session.FamilyID == keepFamilyID {
+			continue
+		}
+		if err := s.revokeSession(ctx, session, authRepo.RevokedReasonPasswordChange); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tokenURL appends token to the frontend page base as the "token" query parameter.
+// Without a page configured the bare token is sent.
+func tokenURL(base, token string) string {
+	if base == "" {
+		return token
+	}
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "token=" + url.QueryEscape(token)
+}
+
+func invalidResetTokenError() error {
+	return pkgErrors.AuthService(pkgErrors.ErrCodeResetTokenInvalid).
+		Hint("Reset link is invalid or has expired").
+		Errorf("password reset token invalid")
+}
+
+// newResetToken returns a URL-safe random token; only its hash is persisted
+func newResetToken() (string, error) {
+	buf := make([]byte, resetTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate reset token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func formatTokenTTL(ttl time.Duration) string {
+	switch {
+	case ttl == time.Hour:
+		return "1 hour"
+	case ttl > time.Hour && ttl%time.Hour == 0:
+		return fmt.Sprintf("%d hours", int(ttl/time.Hour))
+	}
+	return fmt.Sprintf("%d minutes", int(ttl.Minutes()))
+}
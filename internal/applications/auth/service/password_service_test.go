@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	authDto "ichi-go/internal/applications/auth/dto"
+	notifDto "ichi-go/internal/applications/notification/dto"
+	"ichi-go/pkg/authenticator"
+	dbModel "ichi-go/pkg/db/model"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+// fakePasswordResetRepository keeps reset tokens in memory with the same
+// single-use semantics as PasswordResetRepositoryImpl.MarkUsed.
+type fakePasswordResetRepository struct {
+	mu     sync.Mutex
+	nextID int64
+	resets map[int64]*dbModel.PasswordReset
+}
+
+func newFakePasswordResetRepository() *fakePasswordResetRepository {
+	return &fakePasswordResetRepository{resets: make(map[int64]*dbModel.PasswordReset)}
+}
+
+func (r *fakePasswordResetRepository) Create(_ context.Context, reset *dbModel.PasswordReset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	reset.ID = r.nextID
+	copied := *reset
+	r.resets[reset.ID] = &copied
+	return nil
+}
+
+func (r *fakePasswordResetRepository) FindValidByTokenHash(_ context.Context, tokenHash string) (*dbModel.PasswordReset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, reset := range r.resets {
+		if reset.TokenHash == tokenHash && reset.UsedAt.IsZero() && reset.ExpiresAt.After(time.Now()) {
+			copied := *reset
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakePasswordResetRepository) MarkUsed(_ context.Context, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reset, ok := r.resets[id]
+	if !ok || !reset.UsedAt.IsZero() {
+		return false, nil
+	}
+	reset.UsedAt = bun.NullTime{Time: time.Now()}
+	return true, nil
+}
+
+func (r *fakePasswordResetRepository) InvalidateForUser(_ context.Context, userID uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, reset := range r.resets {
+		if reset.UserID == userID && reset.UsedAt.IsZero() {
+			reset.UsedAt = bun.NullTime{Time: time.Now()}
+		}
+	}
+	return nil
+}
+
+type passwordTestFixture struct {
+	svc      *ServiceImpl
+	users    *MockUserRepository
+	producer *MockMessageProducer
+	sessions *fakeSessionRepository
+	resets   *fakePasswordResetRepository
+	user     *TestUser
+}
+
+func newPasswordTestService(t *testing.T) *passwordTestFixture {
+	t.Helper()
+
+	testUser := createTestUser()
+	testUser.Model.ID = int64(testUser.ID)
+
+	users := new(MockUserRepository)
+	users.On("GetById", mock.Anything, testUser.ID).Return(testUser.Model, nil)
+	users.On("FindByEmail", mock.Anything, testUser.Model.Email).Return(testUser.Model, nil)
+	users.On("FindByEmail", mock.Anything, mock.Anything).Return(nil, nil)
+	users.On("Update", mock.Anything, mock.Anything).Return(testUser.Model.ID, nil)
+
+	jwtAuth := authenticator.NewJWTAuthenticator(&authenticator.JWTConfig{
+		SigningMethod:   jwt.SigningMethodHS256,
+		SecretKey:       []byte("test-secret-key-minimum-32-chars-long"),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+		AuthScheme:      "Bearer",
+		RevocationStore: authenticator.NewMemoryRevocationStore(),
+	})
+
+	producer := new(MockMessageProducer)
+	sessions := newFakeSessionRepository()
+	resets := newFakePasswordResetRepository()
+	resetConfig := authenticator.PasswordResetConfig{ResetURL: "https://app.example.com/reset-password", RequestCooldown: time.Nanosecond, RequestLimit: 3}
+	svc := NewAuthService(users, sessions, resets, jwtAuth, producer, Options{
+		PasswordReset: &resetConfig,
+		ResetLimiter:  resetConfig.NewRequestLimiter(authenticator.NewMemoryRateLimitStore()),
+	})
+
+	return &passwordTestFixture{svc: svc, users: users, producer: producer, sessions: sessions, resets: resets, user: testUser}
+}
+
+// forgotPassword requests a reset link and waits for it to be sent
+func (f *passwordTestFixture) forgotPassword(t *testing.T, email string) error {
+	t.Helper()
+	err := f.svc.ForgotPassword(context.Background(), authDto.ForgotPasswordRequest{Email: email})
+	f.svc.background.Wait()
+	return err
+}
+
+// captureResetToken records the published reset event and returns a func extracting its token
+func (f *passwordTestFixture) captureResetToken(t *testing.T) func() string {
+	t.Helper()
+	var event notifDto.NotificationEvent
+	f.producer.On("Publish", mock.Anything, notificationRoutingKey, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { event = args.Get(2).(notifDto.NotificationEvent) }).
+		Return(nil)
+
+	return func() string {
+		require.Equal(t, "auth.password_reset", event.EventType)
+		resetURL, _ := event.Data["reset_url"].(string)
+		_, token, found := strings.Cut(resetURL, "token=")
+		require.True(t, found, "reset_url should carry the token: %q", resetURL)
+		return token
+	}
+}
+
+func TestChangePassword_RevokesOtherSessions(t *testing.T) {
+	f := newPasswordTestService(t)
+	ctx := context.Background()
+
+	current, err := f.svc.startSession(ctx, f.user.ID)
+	require.NoError(t, err)
+	other, err := f.svc.startSession(ctx, f.user.ID)
+	require.NoError(t, err)
+
+	err = f.svc.ChangePassword(ctx, authContextFor(t, current.AccessToken), authDto.ChangePasswordRequest{
+		OldPassword: f.user.Password,
+		NewPassword: "NewSecurePass123!@#",
+	})
+	require.NoError(t, err)
+	f.users.AssertCalled(t, "Update", mock.Anything, mock.Anything)
+
+	// The requesting session survives, the other one is gone
+	_, err = f.svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: current.RefreshToken})
+	assert.NoError(t, err)
+	_, err = f.svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: other.RefreshToken})
+	assert.Error(t, err)
+}
+
+func TestChangePassword_WrongOldPassword(t *testing.T) {
+	f := newPasswordTestService(t)
+	ctx := context.Background()
+
+	pair, err := f.svc.startSession(ctx, f.user.ID)
+	require.NoError(t, err)
+
+	err = f.svc.ChangePassword(ctx, authContextFor(t, pair.AccessToken), authDto.ChangePasswordRequest{
+		OldPassword: "WrongPassword1!",
+		NewPassword: "NewSecurePass123!@#",
+	})
+	require.Error(t, err)
+	f.users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestForgotPassword_UnknownEmailDoesNotLeak(t *testing.T) {
+	f := newPasswordTestService(t)
+
+	err := f.forgotPassword(t, "nobody@example.com")
+	assert.NoError(t, err)
+	f.producer.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, f.resets.resets)
+}
+
+func TestForgotPassword_StoresOnlyTokenHash(t *testing.T) {
+	f := newPasswordTestService(t)
+	token := f.captureResetToken(t)
+
+	require.NoError(t, f.forgotPassword(t, f.user.Model.Email))
+
+	raw := token()
+	require.Len(t, f.resets.resets, 1)
+	for _, reset := range f.resets.resets {
+		assert.NotEqual(t, raw, reset.TokenHash)
+		assert.Equal(t, hashResetToken(raw), reset.TokenHash)
+		assert.WithinDuration(t, time.Now().Add(defaultPasswordResetTTL), reset.ExpiresAt, time.Minute)
+	}
+}
+
+func TestForgotPassword_IsRateLimited(t *testing.T) {
+	f := newPasswordTestService(t)
+	f.captureResetToken(t)
+
+	for range 3 {
+		require.NoError(t, f.forgotPassword(t, f.user.Model.Email))
+	}
+	err := f.forgotPassword(t, f.user.Model.Email)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limited")
+
+	// Unknown addresses are limited the same way
+	for range 3 {
+		require.NoError(t, f.forgotPassword(t, "nobody@example.com"))
+	}
+	assert.Error(t, f.forgotPassword(t, "nobody@example.com"))
+}
+
+func TestResetPassword_SingleUse(t *testing.T) {
+	f := newPasswordTestService(t)
+	ctx := context.Background()
+	token := f.captureResetToken(t)
+
+	session, err := f.svc.startSession(ctx, f.user.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, f.forgotPassword(t, f.user.Model.Email))
+	raw := token()
+
+	req := authDto.ResetPasswordRequest{Token: raw, NewPassword: "NewSecurePass123!@#"}
+	require.NoError(t, f.svc.ResetPassword(ctx, req))
+
+	// Existing sessions are signed out
+	_, err = f.svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: session.RefreshToken})
+	assert.Error(t, err)
+
+	// The token cannot be redeemed twice
+	assert.Error(t, f.svc.ResetPassword(ctx, req))
+}
+
+func TestResetPassword_NewRequestInvalidatesPreviousToken(t *testing.T) {
+	f := newPasswordTestService(t)
+	ctx := context.Background()
+	token := f.captureResetToken(t)
+
+	require.NoError(t, f.forgotPassword(t, f.user.Model.Email))
+	first := token()
+	require.NoError(t, f.forgotPassword(t, f.user.Model.Email))
+	second := token()
+
+	assert.Error(t, f.svc.ResetPassword(ctx, authDto.ResetPasswordRequest{Token: first, NewPassword: "NewSecurePass123!@#"}))
+	assert.NoError(t, f.svc.ResetPassword(ctx, authDto.ResetPasswordRequest{Token: second, NewPassword: "NewSecurePass123!@#"}))
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	authDto "ichi-go/internal/applications/auth/dto"
+	authRepo "ichi-go/internal/applications/auth/repository"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/requestctx"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxUserAgentLength = 500
+
+// ListSessions returns the active sessions of the current user, newest activity first
+func (s *ServiceImpl) ListSessions(ctx context.Context, authCtx authenticator.AuthContext) ([]authDto.SessionResponse, error) {
+	sessions, err := s.sessionRepo.FindActiveByUser(ctx, authCtx.UserID.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentID, _ := authenticator.GetClaimString(authCtx.Claims, authenticator.SessionIDClaim)
+
+	result := make([]authDto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, authDto.SessionResponse{
+			ID:         session.ID,
+			Device:     session.Device,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			Current:    currentID != "" && session.FamilyID == currentID,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+	return result, nil
+}
+
+// RevokeSession ends one of the current user's sessions, e.g. a lost device
+func (s *ServiceImpl) RevokeSession(ctx context.Context, authCtx authenticator.AuthContext, sessionID int64) error {
+	session, err := s.sessionRepo.FindByIDForUser(ctx, sessionID, authCtx.UserID.ID)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeNotFound).
+			With("user_id", authCtx.UserID.ID).
+			With("session_id", sessionID).
+			Hint("Session not found").
+			Errorf("session not found")
+	}
+	if !session.RevokedAt.IsZero() {
+		return nil
+	}
+
+	return s.revokeSession(ctx, session, authRepo.RevokedReasonUserRevoked)
+}
+
+// startSession issues a token pair bound to a new session
+func (s *ServiceImpl) startSession(ctx context.Context, userID uint64) (*authenticator.TokenPair, error) {
+	familyID := uuid.NewString()
+
+	tokenPair, err := s.jwtAuth.GenerateSessionTokens(ctx, userID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := requestctx.FromContext(ctx)
+	now := time.Now()
+	session := &model.UserSession{
+		UserID:     userID,
+		FamilyID:   familyID,
+		RefreshJTI: tokenPair.RefreshTokenID,
+		AccessJTI:  tokenPair.AccessTokenID,
+		Device:     deviceFromRequest(rc),
+		IPAddress:  rc.ClientIP,
+		UserAgent:  truncate(rc.UserAgent, maxUserAgentLength),
+		LastUsedAt: now,
+		ExpiresAt:  tokenPair.RefreshExpiresAt,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return tokenPair, nil
+}
+
+// rotateSession exchanges the session's current refresh token (jti) for a new pair.
+// Any other jti is a replayed token: the session is revoked so neither the thief
+// nor the legitimate client can keep using it.
+func (s *ServiceImpl) rotateSession(ctx context.Context, userID uint64, familyID, jti string) (*authenticator.TokenPair, error) {
+	session, err := s.sessionRepo.FindByFamilyID(ctx, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID || !session.IsActive(time.Now()) {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenRevoked).
+			With("user_id", userID).
+			Hint("Session has ended, please log in again").
+			Errorf("session %s not active", familyID)
+	}
+
+	if session.RefreshJTI != jti {
+		return nil, s.handleRefreshReuse(ctx, session)
+	}
+
+	tokenPair, err := s.jwtAuth.GenerateSessionTokens(ctx, userID, familyID)
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenGenFailed).
+			With("user_id", userID).
+			Hint("Failed to generate new tokens").
+			Wrap(err)
+	}
+
+	rc := requestctx.FromContext(ctx)
+	rotated, err := s.sessionRepo.Rotate(ctx, session.ID, jti, model.UserSession{
+		RefreshJTI: tokenPair.RefreshTokenID,
+		AccessJTI:  tokenPair.AccessTokenID,
+		IPAddress:  rc.ClientIP,
+		UserAgent:  truncate(rc.UserAgent, maxUserAgentLength),
+		LastUsedAt: time.Now(),
+		ExpiresAt:  tokenPair.RefreshExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another request rotated this token between our read and write.
+		return nil, s.handleRefreshReuse(ctx, session)
+	}
+
+	return tokenPair, nil
+}
+
+func (s *ServiceImpl) handleRefreshReuse(ctx context.Context, session *model.UserSession) error {
+	logger.Warnf("Refresh token reuse detected for user %d session %d - revoking session", session.UserID, session.ID)
+
+	if err := s.revokeSession(ctx, session, authRepo.RevokedReasonReuseDetected); err != nil {
+		logger.Errorf("Failed to revoke session %d after refresh token reuse: %v", session.ID, err)
+	}
+
+	return pkgErrors.AuthService(pkgErrors.ErrCodeTokenRevoked).
+		With("user_id", session.UserID).
+		With("session_id", session.ID).
+		Hint("Refresh token reuse detected, please log in again").
+		Errorf("refresh token reuse detected")
+}
+
+// endSession revokes the session identified by the sid claim of the caller's token
+func (s *ServiceImpl) endSession(ctx context.Context, userID uint64, familyID string) error {
+	session, err := s.sessionRepo.FindByFamilyID(ctx, familyID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID || !session.RevokedAt.IsZero() {
+		return nil
+	}
+	return s.sessionRepo.Revoke(ctx, session.ID, authRepo.RevokedReasonLogout)
+}
+
+// revokeSession marks the session revoked and denylists its latest access token
+func (s *ServiceImpl) revokeSession(ctx context.Context, session *model.UserSession, reason string) error {
+	if err := s.sessionRepo.Revoke(ctx, session.ID, reason); err != nil {
+		return err
+	}
+	return s.jwtAuth.RevokeAccessTokenID(ctx, session.AccessJTI)
+}
+
+// deviceFromRequest drops requestctx's placeholder when the client sent no X-Device header
+func deviceFromRequest(rc *requestctx.RequestContext) string {
+	if strings.HasPrefix(rc.Platform, "default_") {
+		return ""
+	}
+	return rc.Platform
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,244 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	authDto "ichi-go/internal/applications/auth/dto"
+	"ichi-go/pkg/authenticator"
+	dbModel "ichi-go/pkg/db/model"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+// fakeSessionRepository keeps sessions in memory with the same compare-and-swap
+// semantics as SessionRepositoryImpl.Rotate.
+type fakeSessionRepository struct {
+	mu       sync.Mutex
+	nextID   int64
+	sessions map[int64]*dbModel.UserSession
+}
+
+func newFakeSessionRepository() *fakeSessionRepository {
+	return &fakeSessionRepository{sessions: make(map[int64]*dbModel.UserSession)}
+}
+
+func (r *fakeSessionRepository) Create(_ context.Context, session *dbModel.UserSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	session.ID = r.nextID
+	session.CreatedAt = time.Now()
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *fakeSessionRepository) FindByFamilyID(_ context.Context, familyID string) (*dbModel.UserSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.FamilyID == familyID {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeSessionRepository) FindByIDForUser(_ context.Context, id int64, userID uint64) (*dbModel.UserSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok || s.UserID != userID {
+		return nil, nil
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (r *fakeSessionRepository) FindActiveByUser(_ context.Context, userID uint64) ([]*dbModel.UserSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*dbModel.UserSession
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.IsActive(time.Now()) {
+			copied := *s
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeSessionRepository) Rotate(_ context.Context, id int64, oldRefreshJTI string, next dbModel.UserSession) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok || s.RefreshJTI != oldRefreshJTI || !s.RevokedAt.IsZero() {
+		return false, nil
+	}
+	s.RefreshJTI = next.RefreshJTI
+	s.AccessJTI = next.AccessJTI
+	s.LastUsedAt = next.LastUsedAt
+	s.ExpiresAt = next.ExpiresAt
+	return true, nil
+}
+
+func (r *fakeSessionRepository) Revoke(_ context.Context, id int64, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.sessions[id]; ok && s.RevokedAt.IsZero() {
+		s.RevokedAt = bun.NullTime{Time: time.Now()}
+		s.RevokedReason = reason
+	}
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeAllForUser(_ context.Context, userID uint64, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt.IsZero() {
+			s.RevokedAt = bun.NullTime{Time: time.Now()}
+			s.RevokedReason = reason
+		}
+	}
+	return nil
+}
+
+func newSessionTestService(t *testing.T) (*ServiceImpl, *fakeSessionRepository, *authenticator.MemoryRevocationStore) {
+	t.Helper()
+
+	testUser := createTestUser()
+	testUser.Model.ID = int64(testUser.ID)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetById", mock.Anything, testUser.ID).Return(testUser.Model, nil)
+
+	store := authenticator.NewMemoryRevocationStore()
+	jwtAuth := authenticator.NewJWTAuthenticator(&authenticator.JWTConfig{
+		SigningMethod:   jwt.SigningMethodHS256,
+		SecretKey:       []byte("test-secret-key-minimum-32-chars-long"),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+		AuthScheme:      "Bearer",
+		RevocationStore: store,
+	})
+
+	sessions := newFakeSessionRepository()
+	return NewAuthService(userRepo, sessions, newFakePasswordResetRepository(), jwtAuth, nil, Options{}), sessions, store
+}
+
+func TestRefreshToken_RotatesRefreshToken(t *testing.T) {
+	svc, sessions, _ := newSessionTestService(t)
+	ctx := context.Background()
+
+	initial, err := svc.startSession(ctx, 1)
+	require.NoError(t, err)
+
+	refreshed, err := svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: initial.RefreshToken})
+	require.NoError(t, err)
+	assert.NotEqual(t, initial.RefreshToken, refreshed.RefreshToken)
+
+	// Rotation keeps the same session
+	active, _ := sessions.FindActiveByUser(ctx, 1)
+	assert.Len(t, active, 1)
+
+	// The new refresh token works
+	_, err = svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	assert.NoError(t, err)
+}
+
+func TestRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	svc, sessions, store := newSessionTestService(t)
+	ctx := context.Background()
+
+	initial, err := svc.startSession(ctx, 1)
+	require.NoError(t, err)
+
+	rotated, err := svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: initial.RefreshToken})
+	require.NoError(t, err)
+
+	// Replaying the already-used refresh token is detected...
+	_, err = svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: initial.RefreshToken})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reuse")
+
+	// ...and the whole family is dead, including the legitimately rotated token
+	_, err = svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: rotated.RefreshToken})
+	assert.Error(t, err)
+
+	active, _ := sessions.FindActiveByUser(ctx, 1)
+	assert.Empty(t, active)
+
+	// The session's latest access token was denylisted as well
+	jti, _ := authenticator.GetClaimString(authContextFor(t, rotated.AccessToken).Claims, "jti")
+	revoked, err := store.IsRevoked(ctx, jti)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestListAndRevokeSessions(t *testing.T) {
+	svc, _, _ := newSessionTestService(t)
+	ctx := context.Background()
+
+	phone, err := svc.startSession(ctx, 1)
+	require.NoError(t, err)
+	_, err = svc.startSession(ctx, 1)
+	require.NoError(t, err)
+
+	authCtx := authContextFor(t, phone.AccessToken)
+
+	list, err := svc.ListSessions(ctx, authCtx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	var current, other int64
+	for _, s := range list {
+		if s.Current {
+			current = s.ID
+		} else {
+			other = s.ID
+		}
+	}
+	require.NotZero(t, current)
+	require.NotZero(t, other)
+
+	require.NoError(t, svc.RevokeSession(ctx, authCtx, other))
+
+	list, err = svc.ListSessions(ctx, authCtx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, current, list[0].ID)
+
+	// Sessions of other users are invisible
+	assert.Error(t, svc.RevokeSession(ctx, authenticator.AuthContext{UserID: authenticator.UserSubject{ID: 2}}, current))
+}
+
+func TestLogout_EndsSession(t *testing.T) {
+	svc, _, _ := newSessionTestService(t)
+	ctx := context.Background()
+
+	pair, err := svc.startSession(ctx, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Logout(ctx, authContextFor(t, pair.AccessToken), authDto.LogoutRequest{}))
+
+	_, err = svc.RefreshToken(ctx, authDto.RefreshTokenRequest{RefreshToken: pair.RefreshToken})
+	assert.Error(t, err)
+}
+
+func authContextFor(t *testing.T, accessToken string) authenticator.AuthContext {
+	t.Helper()
+	token, _, err := jwt.NewParser().ParseUnverified(accessToken, jwt.MapClaims{})
+	require.NoError(t, err)
+	claims := token.Claims.(jwt.MapClaims)
+	subject, err := authenticator.GetUserIdFromMapClaims(claims)
+	require.NoError(t, err)
+	return authenticator.AuthContext{UserID: subject, Claims: claims}
+}
@@ -2,28 +2,272 @@ package channels
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+
 	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/pkg/db/model"
 	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/email"
+	"ichi-go/pkg/notification/preference"
 )
 
-// EmailChannel delivers notifications via email.
-// Replace the Send body with your real email client (SES, SendGrid, SMTP, etc).
+// Mailer is the part of the SMTP client EmailChannel uses; satisfied by *email.Client.
+type Mailer interface {
+	Send(ctx context.Context, msg *email.Message) error
+}
+
+// UserLookup resolves the recipient of a user-targeted email.
+// The user repository satisfies this interface.
+type UserLookup interface {
+	GetById(ctx context.Context, id uint64) (*model.User, error)
+}
+
+// EmailOptions configures the HTML layout and List-Unsubscribe header of EmailChannel.
+type EmailOptions struct {
+	Layout            *email.Layout // nil sends text-only emails
+	AppName           string
+	UnsubscribeURL    string // "{user_id}", "{event_type}" and "{token}" are substituted
+	UnsubscribeMailto string // same placeholders; address only, without "mailto:"
+	// UnsubscribeSigner signs the one-click unsubscribe {token}; nil omits
+	// targets that use it.
+	UnsubscribeSigner *preference.Signer
+}
+
+// NewEmailOptions builds the EmailOptions of cfg, parsing its layout file.
+func NewEmailOptions(cfg email.Config) (EmailOptions, error) {
+	layout, err := email.NewLayout(cfg.LayoutFile)
+	if err != nil {
+		return EmailOptions{}, err
+	}
+	return EmailOptions{
+		Layout:            layout,
+		AppName:           cfg.AppName,
+		UnsubscribeURL:    cfg.UnsubscribeURL,
+		UnsubscribeMailto: cfg.UnsubscribeMailto,
+	}, nil
+}
+
+// EmailChannel delivers notifications via SMTP.
+//
+// Recipient, in order of precedence:
+//   - event.Data["email"] (and event.Data["name"] for the display name)
+//   - the email address of event.UserID, read from the user repository
+//
+// Attachments are read from event.Data["attachments"]: a JSON array of
+// {"filename", "content_type", "content_base64"} objects.
+//
+// SMTP pitfalls handled here:
+//   - mailer is nil (email disabled in config): log and return nil (permanent skip)
+//   - Unknown user or user without email: log warning and return nil (no retry)
+//   - 5xx reply (mailbox unknown, message refused): log and return nil (no retry)
+//   - 4xx reply, network error or user lookup failure: return error (requeue)
 type EmailChannel struct {
-	// emailClient email.Client  — inject your real client here
+	mailer Mailer     // nil when email is disabled
+	users  UserLookup // nil disables recipient lookup by user
+	opts   EmailOptions
 }
 
-func NewEmailChannel() *EmailChannel {
-	return &EmailChannel{}
+// NewEmailChannel creates an EmailChannel. client may be nil when email is not configured,
+// users may be nil when the database is unavailable.
+func NewEmailChannel(client *email.Client, users UserLookup, opts EmailOptions) *EmailChannel {
+	c := &EmailChannel{users: users, opts: opts}
+	if client != nil {
+		c.mailer = client
+	}
+	return c
 }
 
 func (c *EmailChannel) Name() dto.Channel {
 	return dto.ChannelEmail
 }
 
+// Send delivers an email over SMTP.
+//
+// Reads the subject and plain-text body from event.Data["__title__"] and
+// event.Data["__body__"] (injected by TemplateRenderer in dispatch.go before Send
+// is called); the HTML alternative is the body wrapped in the configured layout.
 func (c *EmailChannel) Send(ctx context.Context, event dto.NotificationEvent) error {
-	// TODO: look up user email from event.UserID or event.Data["email"]
-	// TODO: resolve template by event.EventType + event.Locale
-	// TODO: call c.emailClient.Send(ctx, to, subject, body)
+	// Email disabled — skip silently.
+	if c.mailer == nil {
+		logger.Debugf("[email] SMTP client not configured, skipping event_type=%s", event.EventType)
+		return nil
+	}
+
+	to, err := c.recipient(ctx, event)
+	if err != nil {
+		// User store unavailable — transient, requeue.
+		return fmt.Errorf("[email] recipient lookup failed: %w", err)
+	}
+	if to == nil {
+		logger.Warnf("[email] no recipient event_type=%s user_id=%s, skipping",
+			event.EventType, event.UserID)
+		return nil // permanent — no address = no retry
+	}
+
+	attachments, err := extractAttachments(event.Data)
+	if err != nil {
+		logger.Warnf("[email] invalid attachments event_type=%s user_id=%s: %v",
+			event.EventType, event.UserID, err)
+		return nil // permanent — bad data, do not retry
+	}
+
+	subject, _ := event.Data["__title__"].(string)
+	text, _ := event.Data["__body__"].(string)
+	unsubscribeURL := c.expand(c.opts.UnsubscribeURL, event)
+
+	msg := &email.Message{
+		To:          []mail.Address{*to},
+		Subject:     subject,
+		Text:        text,
+		Headers:     c.listUnsubscribe(unsubscribeURL, c.expand(c.opts.UnsubscribeMailto, event)),
+		Attachments: attachments,
+	}
+	if c.opts.Layout != nil {
+		msg.HTML, err = c.opts.Layout.Render(email.LayoutData{
+			Title:          subject,
+			Body:           text,
+			AppName:        c.opts.AppName,
+			UnsubscribeURL: unsubscribeURL,
+			Locale:         event.Locale,
+		})
+		if err != nil {
+			// A broken layout fails every email the same way — send text only.
+			logger.Errorf("[email] layout render failed event_type=%s: %v", event.EventType, err)
+			msg.HTML = ""
+		}
+	}
+
 	logger.Infof("[email] sending event_type=%s user_id=%s", event.EventType, event.UserID)
+	if err := c.mailer.Send(ctx, msg); err != nil {
+		if email.IsPermanent(err) {
+			logger.Warnf("[email] rejected permanently event_type=%s user_id=%s: %v",
+				event.EventType, event.UserID, err)
+			return nil // permanent — 5xx, do not retry
+		}
+		return fmt.Errorf("[email] SMTP send failed: %w", err)
+	}
+
+	logger.Infof("[email] sent event_type=%s user_id=%s", event.EventType, event.UserID)
 	return nil
 }
+
+// recipient returns the address of the event, or nil when it has none.
+func (c *EmailChannel) recipient(ctx context.Context, event dto.NotificationEvent) (*mail.Address, error) {
+	if addr, _ := event.Data["email"].(string); addr != "" {
+		name, _ := event.Data["name"].(string)
+		return &mail.Address{Name: name, Address: addr}, nil
+	}
+
+	if c.users == nil || event.UserID == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(event.UserID, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	user, err := c.users.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if user == nil || user.Email == "" {
+		return nil, nil
+	}
+	return &mail.Address{Name: user.Name, Address: user.Email}, nil
+}
+
+// listUnsubscribe builds the List-Unsubscribe headers (RFC 2369), plus
+// List-Unsubscribe-Post for one-click unsubscribe (RFC 8058) when a URL is set.
+func (c *EmailChannel) listUnsubscribe(url, mailto string) map[string]string {
+	var targets []string
+	if mailto != "" {
+		targets = append(targets, "<mailto:"+mailto+">")
+	}
+	if url != "" {
+		targets = append(targets, "<"+url+">")
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	headers := map[string]string{"List-Unsubscribe": strings.Join(targets, ", ")}
+	if url != "" {
+		headers["List-Unsubscribe-Post"] = "List-Unsubscribe=One-Click"
+	}
+	return headers
+}
+
+// expand substitutes the {user_id}, {event_type} and {token} placeholders of pattern.
+// A pattern using {token} expands to "" when no token can be signed for the event.
+func (c *EmailChannel) expand(pattern string, event dto.NotificationEvent) string {
+	if pattern == "" {
+		return ""
+	}
+	token := ""
+	if strings.Contains(pattern, "{token}") {
+		userID, err := strconv.ParseInt(event.UserID, 10, 64)
+		if c.opts.UnsubscribeSigner == nil || err != nil {
+			return ""
+		}
+		token = c.opts.UnsubscribeSigner.Sign(preference.UnsubscribeToken{
+			UserID:    userID,
+			EventType: event.EventType,
+			Channel:   string(dto.ChannelEmail),
+		})
+	}
+	return strings.NewReplacer(
+		"{user_id}", event.UserID,
+		"{event_type}", event.EventType,
+		"{token}", token,
+	).Replace(pattern)
+}
+
+type attachmentPayload struct {
+	Filename      string `json:"filename"`
+	ContentType   string `json:"content_type"`
+	ContentBase64 string `json:"content_base64"`
+}
+
+// extractAttachments decodes event.Data["attachments"], which arrives as the
+// []any produced by JSON decoding of the queue message.
+func extractAttachments(data map[string]any) ([]email.Attachment, error) {
+	raw, ok := data["attachments"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var payloads []attachmentPayload
+	if err := json.Unmarshal(encoded, &payloads); err != nil {
+		return nil, fmt.Errorf("attachments must be an array of objects: %w", err)
+	}
+
+	attachments := make([]email.Attachment, 0, len(payloads))
+	for i, p := range payloads {
+		if p.Filename == "" {
+			return nil, fmt.Errorf("attachment %d has no filename", i)
+		}
+		content, err := base64.StdEncoding.DecodeString(p.ContentBase64)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %q: invalid base64: %w", p.Filename, err)
+		}
+		attachments = append(attachments, email.Attachment{
+			Filename:    p.Filename,
+			ContentType: p.ContentType,
+			Data:        content,
+		})
+	}
+	return attachments, nil
+}
@@ -0,0 +1,173 @@
+package channels
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/notification/email"
+	"ichi-go/pkg/notification/email/smtptest"
+	"ichi-go/pkg/notification/preference"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockUserLookup struct {
+	mock.Mock
+}
+
+func (m *mockUserLookup) GetById(ctx context.Context, id uint64) (*model.User, error) {
+	args := m.Called(ctx, id)
+	user, _ := args.Get(0).(*model.User)
+	return user, args.Error(1)
+}
+
+func emailEvent(userID string, data map[string]any) dto.NotificationEvent {
+	if data == nil {
+		data = map[string]any{}
+	}
+	data["__title__"] = "Shipped"
+	data["__body__"] = "Your order is on its way"
+	return dto.NotificationEvent{
+		EventID:      "evt-1",
+		EventType:    "order.shipped",
+		DeliveryMode: dto.DeliveryModeUser,
+		UserID:       userID,
+		Channels:     []dto.Channel{dto.ChannelEmail},
+		Data:         data,
+	}
+}
+
+// newSinkChannel returns an EmailChannel sending to an in-process SMTP sink.
+func newSinkChannel(t *testing.T, users UserLookup) (*EmailChannel, *smtptest.Server) {
+	t.Helper()
+	srv := smtptest.NewServer(t)
+	client, err := email.NewClient(email.Config{
+		Host:        srv.Host(),
+		Port:        srv.Port(),
+		Encryption:  email.EncryptionNone,
+		FromAddress: "no-reply@example.com",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	opts, err := NewEmailOptions(email.Config{
+		AppName:        "Ichi",
+		UnsubscribeURL: "https://example.com/unsubscribe?user={user_id}&event={event_type}",
+	})
+	require.NoError(t, err)
+	return NewEmailChannel(client, users, opts), srv
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestEmailSend_LooksUpRecipientOfUser(t *testing.T) {
+	users := new(mockUserLookup)
+	users.On("GetById", mock.Anything, uint64(42)).Return(&model.User{Name: "Budi", Email: "budi@example.com"}, nil)
+	ch, srv := newSinkChannel(t, users)
+
+	require.NoError(t, ch.Send(context.Background(), emailEvent("42", nil)))
+
+	msgs := srv.Messages()
+	require.Len(t, msgs, 1)
+	assert.Equal(t, []string{"budi@example.com"}, msgs[0].To)
+
+	parsed, err := msgs[0].Parse()
+	require.NoError(t, err)
+	assert.Equal(t, "<https://example.com/unsubscribe?user=42&event=order.shipped>", parsed.Header.Get("List-Unsubscribe"))
+	assert.Equal(t, "List-Unsubscribe=One-Click", parsed.Header.Get("List-Unsubscribe-Post"))
+	assert.Contains(t, parsed.Header.Get("Content-Type"), "multipart/alternative")
+}
+
+func TestEmailSend_SuppliedAddressSkipsLookup(t *testing.T) {
+	users := new(mockUserLookup)
+	ch, srv := newSinkChannel(t, users)
+
+	require.NoError(t, ch.Send(context.Background(), emailEvent("42", map[string]any{"email": "reset@example.com"})))
+
+	require.Len(t, srv.Messages(), 1)
+	assert.Equal(t, []string{"reset@example.com"}, srv.Messages()[0].To)
+	users.AssertNotCalled(t, "GetById", mock.Anything, mock.Anything)
+}
+
+func TestEmailSend_Attachments(t *testing.T) {
+	ch, srv := newSinkChannel(t, nil)
+
+	data := map[string]any{
+		"email": "budi@example.com",
+		"attachments": []any{
+			map[string]any{"filename": "invoice.pdf", "content_type": "application/pdf", "content_base64": "JVBERi0xLjQ="},
+		},
+	}
+	require.NoError(t, ch.Send(context.Background(), emailEvent("42", data)))
+
+	require.Len(t, srv.Messages(), 1)
+	assert.Contains(t, string(srv.Messages()[0].Raw), `filename=invoice.pdf`)
+}
+
+func TestEmailSend_PermanentRejectionIsSkipped(t *testing.T) {
+	ch, srv := newSinkChannel(t, nil)
+	srv.RejectRecipient("gone@example.com", 550, "5.1.1 No such user")
+
+	assert.NoError(t, ch.Send(context.Background(), emailEvent("", map[string]any{"email": "gone@example.com"})))
+}
+
+func TestEmailSend_TransientFailureIsRetried(t *testing.T) {
+	ch, srv := newSinkChannel(t, nil)
+	srv.FailNextData(451, "4.7.1 Greylisted")
+
+	assert.Error(t, ch.Send(context.Background(), emailEvent("", map[string]any{"email": "budi@example.com"})))
+}
+
+func TestEmailSend_UnknownUserIsSkipped(t *testing.T) {
+	users := new(mockUserLookup)
+	users.On("GetById", mock.Anything, uint64(42)).Return(nil, fmt.Errorf("get user: %w", sql.ErrNoRows))
+	ch, srv := newSinkChannel(t, users)
+
+	assert.NoError(t, ch.Send(context.Background(), emailEvent("42", nil)))
+	assert.Empty(t, srv.Messages())
+}
+
+func TestEmailSend_LookupFailureIsRetried(t *testing.T) {
+	users := new(mockUserLookup)
+	users.On("GetById", mock.Anything, uint64(42)).Return(nil, fmt.Errorf("db down"))
+	ch, _ := newSinkChannel(t, users)
+
+	assert.Error(t, ch.Send(context.Background(), emailEvent("42", nil)))
+}
+
+func TestNewEmailChannel_NilClientDisablesEmail(t *testing.T) {
+	ch := NewEmailChannel(nil, nil, EmailOptions{})
+
+	assert.Nil(t, ch.mailer)
+	assert.NoError(t, ch.Send(context.Background(), emailEvent("42", nil)))
+}
+
+func TestEmailExpand_SignsUnsubscribeToken(t *testing.T) {
+	signer := preference.NewSigner("secret")
+	ch := NewEmailChannel(nil, nil, EmailOptions{UnsubscribeSigner: signer})
+	pattern := "https://example.com/unsubscribe?token={token}"
+
+	url := ch.expand(pattern, emailEvent("42", nil))
+
+	token := strings.TrimPrefix(url, "https://example.com/unsubscribe?token=")
+	got, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, preference.UnsubscribeToken{UserID: 42, EventType: "order.shipped", Channel: "email"}, got)
+
+	// No token without a user or a signer: the target is left out rather than broken.
+	assert.Empty(t, ch.expand(pattern, emailEvent("", nil)))
+	assert.Empty(t, NewEmailChannel(nil, nil, EmailOptions{}).expand(pattern, emailEvent("42", nil)))
+}
@@ -0,0 +1,110 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/realtime"
+)
+
+// InboxStore stores in-app notifications. The concrete
+// *repositories.InAppNotificationRepository satisfies this interface.
+type InboxStore interface {
+	Create(ctx context.Context, notification *models.InAppNotification) (bool, error)
+}
+
+// RealtimePublisher pushes messages to the open streams of a user; satisfied by *realtime.Hub.
+type RealtimePublisher interface {
+	Publish(ctx context.Context, msg realtime.Message) error
+}
+
+// InAppChannel stores notifications in the inbox of event.UserID and pushes them
+// to the user's open inbox streams, on whichever pod they are connected to.
+//
+// Reads title and body from event.Data["__title__"] and event.Data["__body__"],
+// and the click target from event.Data["action_url"]. The other data keys are
+// stored with the notification for the client.
+//
+// In-app pitfalls handled here:
+//   - Channel disabled or no database: log and return nil (permanent skip)
+//   - Event without a numeric user ID (blast): log and return nil — an inbox belongs to one user
+//   - Event already stored for the user (requeue): not stored or pushed again
+//   - Storing fails: return error (requeue)
+//   - Pushing to the streams fails: log only; the client sees the notification on its next fetch
+type InAppChannel struct {
+	store     InboxStore        // nil when the database is unavailable
+	publisher RealtimePublisher // nil disables realtime delivery
+	enabled   bool
+}
+
+// NewInAppChannel creates an InAppChannel. store may be nil when the database is
+// unavailable, hub may be nil to store notifications without pushing them.
+func NewInAppChannel(store InboxStore, hub *realtime.Hub, cfg realtime.Config) *InAppChannel {
+	c := &InAppChannel{store: store, enabled: cfg.Enabled}
+	if hub != nil {
+		c.publisher = hub
+	}
+	return c
+}
+
+func (c *InAppChannel) Name() dto.Channel {
+	return dto.ChannelInApp
+}
+
+// Send stores the notification in the user's inbox and pushes it to their open streams.
+func (c *InAppChannel) Send(ctx context.Context, event dto.NotificationEvent) error {
+	if !c.enabled || c.store == nil {
+		logger.Debugf("[in_app] inbox not configured, skipping event_type=%s", event.EventType)
+		return nil
+	}
+	userID, err := strconv.ParseInt(event.UserID, 10, 64)
+	if err != nil || userID <= 0 {
+		logger.Warnf("[in_app] event_id=%s has no user, skipping", event.EventID)
+		return nil // permanent — blast events have no inbox to go to
+	}
+
+	title, _ := event.Data["__title__"].(string)
+	body, _ := event.Data["__body__"].(string)
+	actionURL, _ := event.Data["action_url"].(string)
+
+	notification := &models.InAppNotification{
+		UserID:    userID,
+		EventID:   event.EventID,
+		EventType: event.EventType,
+		Title:     title,
+		Body:      body,
+		ActionURL: actionURL,
+		Data:      payloadData(event.Data),
+	}
+	created, err := c.store.Create(ctx, notification)
+	if err != nil {
+		return fmt.Errorf("[in_app] store notification: %w", err)
+	}
+	if !created {
+		logger.Infof("[in_app] event_id=%s already in the inbox of user %d", event.EventID, userID)
+		return nil
+	}
+
+	c.push(ctx, notification)
+	logger.Infof("[in_app] stored event_type=%s user_id=%d notification_id=%d",
+		event.EventType, userID, notification.ID)
+	return nil
+}
+
+func (c *InAppChannel) push(ctx context.Context, notification *models.InAppNotification) {
+	if c.publisher == nil {
+		return
+	}
+	msg, err := dto.NewNotificationMessage(notification)
+	if err != nil {
+		logger.Errorf("[in_app] cannot encode notification %d: %v", notification.ID, err)
+		return
+	}
+	if err := c.publisher.Publish(ctx, msg); err != nil {
+		logger.Warnf("[in_app] failed to push notification %d to user %d: %v", notification.ID, notification.UserID, err)
+	}
+}
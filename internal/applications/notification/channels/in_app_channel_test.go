@@ -0,0 +1,131 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/pkg/notification/realtime"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockInboxStore struct {
+	mock.Mock
+}
+
+func (m *mockInboxStore) Create(ctx context.Context, notification *models.InAppNotification) (bool, error) {
+	args := m.Called(ctx, notification)
+	return args.Bool(0), args.Error(1)
+}
+
+func inAppEvent(userID string) dto.NotificationEvent {
+	return dto.NotificationEvent{
+		EventID:      "evt-1",
+		EventType:    "order.shipped",
+		DeliveryMode: dto.DeliveryModeUser,
+		UserID:       userID,
+		Channels:     []dto.Channel{dto.ChannelInApp},
+		Data: map[string]any{
+			"order_id":   "ORD-1",
+			"action_url": "/orders/ORD-1",
+			"__title__":  "Your order has shipped",
+			"__body__":   "ORD-1 is on its way",
+			"__log_id__": int64(7),
+		},
+	}
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestInAppSend_StoresAndPushesToOpenStreams(t *testing.T) {
+	store := new(mockInboxStore)
+	hub := realtime.NewHub(realtime.Config{}, nil)
+	ch := NewInAppChannel(store, hub, realtime.Config{Enabled: true})
+
+	sub, err := hub.Subscribe(42)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	var stored *models.InAppNotification
+	store.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.InAppNotification)
+			stored.ID = 1001
+		}).
+		Return(true, nil)
+
+	require.NoError(t, ch.Send(context.Background(), inAppEvent("42")))
+
+	require.NotNil(t, stored)
+	assert.Equal(t, int64(42), stored.UserID)
+	assert.Equal(t, "evt-1", stored.EventID)
+	assert.Equal(t, "Your order has shipped", stored.Title)
+	assert.Equal(t, "ORD-1 is on its way", stored.Body)
+	assert.Equal(t, "/orders/ORD-1", stored.ActionURL)
+	assert.Equal(t, map[string]any{"order_id": "ORD-1", "action_url": "/orders/ORD-1"}, stored.Data)
+
+	select {
+	case msg := <-sub.C:
+		assert.Equal(t, dto.InboxEventNotification, msg.Event)
+		assert.Equal(t, "1001", msg.ID)
+		var pushed models.InAppNotification
+		require.NoError(t, json.Unmarshal(msg.Data, &pushed))
+		assert.Equal(t, int64(1001), pushed.ID)
+		assert.Equal(t, "Your order has shipped", pushed.Title)
+	default:
+		t.Fatal("notification was not pushed to the open stream")
+	}
+}
+
+func TestInAppSend_AlreadyStoredIsNotPushedAgain(t *testing.T) {
+	store := new(mockInboxStore)
+	hub := realtime.NewHub(realtime.Config{}, nil)
+	ch := NewInAppChannel(store, hub, realtime.Config{Enabled: true})
+
+	sub, err := hub.Subscribe(42)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	store.On("Create", mock.Anything, mock.Anything).Return(false, nil)
+
+	require.NoError(t, ch.Send(context.Background(), inAppEvent("42")))
+	assert.Empty(t, sub.C)
+}
+
+func TestInAppSend_StoreErrorIsRetried(t *testing.T) {
+	store := new(mockInboxStore)
+	ch := NewInAppChannel(store, nil, realtime.Config{Enabled: true})
+
+	store.On("Create", mock.Anything, mock.Anything).Return(false, errors.New("connection reset"))
+
+	assert.Error(t, ch.Send(context.Background(), inAppEvent("42")))
+}
+
+func TestInAppSend_SkipsEventsWithoutUser(t *testing.T) {
+	store := new(mockInboxStore)
+	ch := NewInAppChannel(store, nil, realtime.Config{Enabled: true})
+
+	require.NoError(t, ch.Send(context.Background(), inAppEvent("")))
+	require.NoError(t, ch.Send(context.Background(), inAppEvent("not-a-user")))
+	store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestInAppSend_DisabledOrWithoutStoreSkips(t *testing.T) {
+	store := new(mockInboxStore)
+
+	require.NoError(t, NewInAppChannel(store, nil, realtime.Config{}).Send(context.Background(), inAppEvent("42")))
+	require.NoError(t, NewInAppChannel(nil, nil, realtime.Config{Enabled: true}).Send(context.Background(), inAppEvent("42")))
+	store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -4,30 +4,52 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"ichi-go/internal/applications/notification/dto"
 	"ichi-go/pkg/logger"
 	"ichi-go/pkg/notification/fcm"
 )
 
+// PushSender is the part of the FCM client PushChannel uses; satisfied by *fcm.Client.
+type PushSender interface {
+	SendToTokensBatch(ctx context.Context, tokens []string, title, body string, data map[string]string) (fcm.BatchResult, error)
+	SendToTopic(ctx context.Context, topic, title, body string, data map[string]string) error
+}
+
+// DeviceTokenStore looks up and prunes the push tokens of users.
+// The concrete *repositories.UserDeviceRepository satisfies this interface.
+type DeviceTokenStore interface {
+	TokensForUser(ctx context.Context, userID int64) ([]string, error)
+	DeleteTokens(ctx context.Context, tokens []string) (int64, error)
+}
+
 // PushChannel delivers notifications via Firebase Cloud Messaging (FCM).
 //
-// Device tokens are expected in event.Data["device_tokens"] as a JSON array of strings
-// or a []string value. Future enhancement: inject a device token repository to look up
-// tokens by event.UserID automatically.
+// Targets, in order of precedence:
+//   - event.Data["push_topic"]: the FCM topic, sent once via SendToTopic
+//   - event.Data["device_tokens"]: a JSON array of strings or a []string value
+//   - the registered devices of event.UserID, read from the device token store
 //
 // FCM pitfalls handled here:
 //   - fcmClient is nil (FCM disabled in config): log and return nil (permanent skip)
-//   - No device tokens in event data: log warning and return nil (no retry)
-//   - Unregistered token: FCM returns ErrCodeUnregistered — log and return nil (no retry)
+//   - No device tokens found: log warning and return nil (no retry)
+//   - Unregistered token: FCM returns ErrCodeUnregistered — the token is deleted from
+//     the device store and the send is not retried
 //   - Batch > 500 tokens: handled via SendToTokensBatch chunking
 type PushChannel struct {
-	fcmClient *fcm.Client // nil when FCM is disabled
+	sender  PushSender       // nil when FCM is disabled
+	devices DeviceTokenStore // nil disables token lookup by user and pruning
 }
 
-// NewPushChannel creates a PushChannel. fcmClient may be nil when FCM is not configured.
-func NewPushChannel(fcmClient *fcm.Client) *PushChannel {
-	return &PushChannel{fcmClient: fcmClient}
+// NewPushChannel creates a PushChannel. fcmClient may be nil when FCM is not configured,
+// devices may be nil when the database is unavailable.
+func NewPushChannel(fcmClient *fcm.Client, devices DeviceTokenStore) *PushChannel {
+	c := &PushChannel{devices: devices}
+	if fcmClient != nil {
+		c.sender = fcmClient
+	}
+	return c
 }
 
 func (c *PushChannel) Name() dto.Channel {
@@ -38,12 +60,9 @@ func (c *PushChannel) Name() dto.Channel {
 //
 // Reads title and body from event.Data["__title__"] and event.Data["__body__"]
 // (injected by TemplateRenderer in dispatch.go before Send is called).
-//
-// Reads device tokens from event.Data["device_tokens"] — a JSON array of strings
-// or a []interface{} slice.
 func (c *PushChannel) Send(ctx context.Context, event dto.NotificationEvent) error {
 	// FCM disabled — skip silently.
-	if c.fcmClient == nil {
+	if c.sender == nil {
 		logger.Debugf("[push] FCM client not configured, skipping event_type=%s", event.EventType)
 		return nil
 	}
@@ -51,7 +70,18 @@ func (c *PushChannel) Send(ctx context.Context, event dto.NotificationEvent) err
 	title, _ := event.Data["__title__"].(string)
 	body, _ := event.Data["__body__"].(string)
 
-	// Extract device tokens from event data.
+	// Build FCM data payload from non-reserved event.Data keys.
+	fcmData := toFCMData(event.Data)
+
+	if topic, _ := event.Data["push_topic"].(string); topic != "" {
+		logger.Infof("[push] sending to topic=%s event_type=%s", topic, event.EventType)
+		if err := c.sender.SendToTopic(ctx, topic, title, body, fcmData); err != nil {
+			return fmt.Errorf("[push] FCM topic send failed: %w", err)
+		}
+		return nil
+	}
+
+	// Extract device tokens from event data, falling back to the user's registered devices.
 	tokens, err := extractDeviceTokens(event.Data)
 	if err != nil {
 		logger.Warnf("[push] failed to extract device_tokens event_type=%s user_id=%s: %v",
@@ -59,37 +89,68 @@ func (c *PushChannel) Send(ctx context.Context, event dto.NotificationEvent) err
 		return nil // permanent — bad data, do not retry
 	}
 	if len(tokens) == 0 {
-		logger.Warnf("[push] no device_tokens in event data event_type=%s user_id=%s, skipping",
+		tokens, err = c.userTokens(ctx, event.UserID)
+		if err != nil {
+			// Device store unavailable — transient, requeue.
+			return fmt.Errorf("[push] device token lookup failed: %w", err)
+		}
+	}
+	if len(tokens) == 0 {
+		logger.Warnf("[push] no device tokens event_type=%s user_id=%s, skipping",
 			event.EventType, event.UserID)
 		return nil // permanent — no token = no retry
 	}
 
-	// Build FCM data payload from non-reserved event.Data keys.
-	fcmData := toFCMData(event.Data)
-
 	// Send via FCM — use batch for multiple tokens.
 	logger.Infof("[push] sending to %d token(s) event_type=%s user_id=%s",
 		len(tokens), event.EventType, event.UserID)
 
-	failedTokens, err := c.fcmClient.SendToTokensBatch(ctx, tokens, title, body, fcmData)
+	result, err := c.sender.SendToTokensBatch(ctx, tokens, title, body, fcmData)
 	if err != nil {
 		// Entire batch request failed (network/auth error) — transient, requeue.
 		return fmt.Errorf("[push] FCM batch send failed: %w", err)
 	}
 
-	if len(failedTokens) > 0 {
-		// Partial failure — some tokens failed. Log for token cleanup; don't requeue
-		// (other tokens succeeded, and failed tokens may be stale/unregistered).
+	if len(result.Failed) > 0 {
+		// Partial failure — some tokens failed. Don't requeue (other tokens succeeded,
+		// and failed tokens may be stale/unregistered).
 		logger.Warnf("[push] %d/%d tokens failed event_type=%s user_id=%s failed_tokens=%v",
-			len(failedTokens), len(tokens), event.EventType, event.UserID, failedTokens)
+			len(result.Failed), len(tokens), event.EventType, event.UserID, result.Failed)
 	}
+	c.pruneTokens(ctx, result.Unregistered)
 
 	logger.Infof("[push] sent event_type=%s user_id=%s success=%d failed=%d",
-		event.EventType, event.UserID, len(tokens)-len(failedTokens), len(failedTokens))
+		event.EventType, event.UserID, len(tokens)-len(result.Failed), len(result.Failed))
 
 	return nil // partial failure is not a requeue trigger — at least some tokens succeeded
 }
 
+// userTokens returns the registered tokens of a user; none when the event has no
+// numeric user ID or no device store is configured.
+func (c *PushChannel) userTokens(ctx context.Context, userID string) ([]string, error) {
+	if c.devices == nil || userID == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	return c.devices.TokensForUser(ctx, id)
+}
+
+// pruneTokens deletes tokens FCM reported as unregistered; they will never work again.
+func (c *PushChannel) pruneTokens(ctx context.Context, tokens []string) {
+	if c.devices == nil || len(tokens) == 0 {
+		return
+	}
+	removed, err := c.devices.DeleteTokens(ctx, tokens)
+	if err != nil {
+		logger.Errorf("[push] failed to prune %d unregistered token(s): %v", len(tokens), err)
+		return
+	}
+	logger.Infof("[push] pruned %d unregistered device(s)", removed)
+}
+
 // extractDeviceTokens reads device tokens from event.Data["device_tokens"].
 // Accepts: []string, []interface{} (from JSON unmarshal), or JSON string array.
 func extractDeviceTokens(data map[string]any) ([]string, error) {
@@ -130,8 +191,8 @@ func toFCMData(data map[string]any) map[string]string {
 		if len(k) >= 2 && k[0] == '_' && k[1] == '_' {
 			continue
 		}
-		// Skip device_tokens — not a user-facing payload field.
-		if k == "device_tokens" {
+		// Skip push targets — not user-facing payload fields.
+		if k == "device_tokens" || k == "push_topic" {
 			continue
 		}
 		switch s := v.(type) {
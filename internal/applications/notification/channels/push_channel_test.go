@@ -0,0 +1,151 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/pkg/notification/fcm"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockSender struct {
+	mock.Mock
+}
+
+func (m *mockSender) SendToTokensBatch(ctx context.Context, tokens []string, title, body string, data map[string]string) (fcm.BatchResult, error) {
+	args := m.Called(ctx, tokens, title, body, data)
+	result, _ := args.Get(0).(fcm.BatchResult)
+	return result, args.Error(1)
+}
+
+func (m *mockSender) SendToTopic(ctx context.Context, topic, title, body string, data map[string]string) error {
+	return m.Called(ctx, topic, title, body, data).Error(0)
+}
+
+type mockTokenStore struct {
+	mock.Mock
+}
+
+func (m *mockTokenStore) TokensForUser(ctx context.Context, userID int64) ([]string, error) {
+	args := m.Called(ctx, userID)
+	tokens, _ := args.Get(0).([]string)
+	return tokens, args.Error(1)
+}
+
+func (m *mockTokenStore) DeleteTokens(ctx context.Context, tokens []string) (int64, error) {
+	args := m.Called(ctx, tokens)
+	return int64(args.Int(0)), args.Error(1)
+}
+
+func pushEvent(userID string, data map[string]any) dto.NotificationEvent {
+	if data == nil {
+		data = map[string]any{}
+	}
+	data["__title__"] = "Shipped"
+	data["__body__"] = "Your order is on its way"
+	return dto.NotificationEvent{
+		EventID:      "evt-1",
+		EventType:    "order.shipped",
+		DeliveryMode: dto.DeliveryModeUser,
+		UserID:       userID,
+		Channels:     []dto.Channel{dto.ChannelPush},
+		Data:         data,
+	}
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestPushSend_ResolvesTokensOfUser(t *testing.T) {
+	sender := new(mockSender)
+	store := new(mockTokenStore)
+	ch := &PushChannel{sender: sender, devices: store}
+
+	store.On("TokensForUser", mock.Anything, int64(42)).Return([]string{"a", "b"}, nil)
+	sender.On("SendToTokensBatch", mock.Anything, []string{"a", "b"}, "Shipped", "Your order is on its way", mock.Anything).
+		Return(fcm.BatchResult{}, nil)
+
+	require.NoError(t, ch.Send(context.Background(), pushEvent("42", nil)))
+	sender.AssertExpectations(t)
+	store.AssertNotCalled(t, "DeleteTokens", mock.Anything, mock.Anything)
+}
+
+func TestPushSend_SuppliedTokensSkipLookup(t *testing.T) {
+	sender := new(mockSender)
+	store := new(mockTokenStore)
+	ch := &PushChannel{sender: sender, devices: store}
+
+	sender.On("SendToTokensBatch", mock.Anything, []string{"x"}, mock.Anything, mock.Anything, mock.Anything).
+		Return(fcm.BatchResult{}, nil)
+
+	require.NoError(t, ch.Send(context.Background(), pushEvent("42", map[string]any{"device_tokens": []string{"x"}})))
+	store.AssertNotCalled(t, "TokensForUser", mock.Anything, mock.Anything)
+}
+
+func TestPushSend_PrunesUnregisteredTokens(t *testing.T) {
+	sender := new(mockSender)
+	store := new(mockTokenStore)
+	ch := &PushChannel{sender: sender, devices: store}
+
+	store.On("TokensForUser", mock.Anything, int64(42)).Return([]string{"a", "b", "c"}, nil)
+	sender.On("SendToTokensBatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(fcm.BatchResult{Failed: []string{"b", "c"}, Unregistered: []string{"b"}}, nil)
+	store.On("DeleteTokens", mock.Anything, []string{"b"}).Return(1, nil)
+
+	require.NoError(t, ch.Send(context.Background(), pushEvent("42", nil)))
+	store.AssertExpectations(t)
+}
+
+func TestPushSend_TopicTakesPrecedence(t *testing.T) {
+	sender := new(mockSender)
+	store := new(mockTokenStore)
+	ch := &PushChannel{sender: sender, devices: store}
+
+	sender.On("SendToTopic", mock.Anything, "deals", "Shipped", "Your order is on its way",
+		mock.MatchedBy(func(data map[string]string) bool {
+			_, leaked := data["push_topic"]
+			return !leaked
+		})).Return(nil)
+
+	require.NoError(t, ch.Send(context.Background(), pushEvent("42", map[string]any{"push_topic": "deals"})))
+	sender.AssertExpectations(t)
+	store.AssertNotCalled(t, "TokensForUser", mock.Anything, mock.Anything)
+}
+
+func TestPushSend_LookupFailureIsRetried(t *testing.T) {
+	sender := new(mockSender)
+	store := new(mockTokenStore)
+	ch := &PushChannel{sender: sender, devices: store}
+
+	store.On("TokensForUser", mock.Anything, int64(42)).Return(nil, errors.New("db down"))
+
+	assert.Error(t, ch.Send(context.Background(), pushEvent("42", nil)))
+}
+
+func TestPushSend_NoDevicesIsSkipped(t *testing.T) {
+	sender := new(mockSender)
+	store := new(mockTokenStore)
+	ch := &PushChannel{sender: sender, devices: store}
+
+	store.On("TokensForUser", mock.Anything, int64(42)).Return([]string{}, nil)
+
+	assert.NoError(t, ch.Send(context.Background(), pushEvent("42", nil)))
+	sender.AssertNotCalled(t, "SendToTokensBatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNewPushChannel_NilClientDisablesPush(t *testing.T) {
+	ch := NewPushChannel(nil, nil)
+
+	assert.Nil(t, ch.sender)
+	assert.NoError(t, ch.Send(context.Background(), pushEvent("42", nil)))
+}
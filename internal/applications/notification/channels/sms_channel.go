@@ -0,0 +1,131 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/sms"
+)
+
+// SMSSender is the part of the SMS client SMSChannel uses; satisfied by *sms.Client.
+type SMSSender interface {
+	Send(ctx context.Context, provider string, msg sms.Message) (sms.Result, error)
+	DefaultProvider() string
+}
+
+// SMSMessageRecorder attaches the provider message ID to the notification log entry,
+// so delivery receipts can find it. The concrete *repositories.NotificationLogRepository
+// satisfies this interface.
+type SMSMessageRecorder interface {
+	SetProviderMessage(ctx context.Context, id int64, provider, messageID string) error
+}
+
+// SMSChannel delivers notifications as SMS through the configured providers.
+//
+// Reads from event.Data:
+//   - "phone": recipient number, normalized to E.164 with the default country code
+//   - "sms_provider": provider name; the configured default when absent
+//   - "__body__": the message text (the title is not used for SMS)
+//
+// SMS pitfalls handled here:
+//   - sender is nil (SMS disabled in config): log and return nil (permanent skip)
+//   - Missing or invalid phone number: log warning and return nil (no retry)
+//   - Body over max_segments: log warning and return nil (no retry, it would be billed per segment)
+//   - Provider 4xx (invalid number, opted out): log and return nil (no retry)
+//   - Provider 5xx, 429 or network error: return error (requeue)
+type SMSChannel struct {
+	sender             SMSSender          // nil when SMS is disabled
+	recorder           SMSMessageRecorder // nil disables delivery receipt tracking
+	defaultCountryCode string
+	maxSegments        int
+}
+
+// NewSMSChannel creates an SMSChannel. client may be nil when SMS is not configured,
+// recorder may be nil when the database is unavailable.
+func NewSMSChannel(client *sms.Client, recorder SMSMessageRecorder, cfg sms.Config) *SMSChannel {
+	c := &SMSChannel{
+		recorder:           recorder,
+		defaultCountryCode: cfg.DefaultCountryCode,
+		maxSegments:        cfg.MaxSegments,
+	}
+	if client != nil {
+		c.sender = client
+	}
+	return c
+}
+
+func (c *SMSChannel) Name() dto.Channel {
+	return dto.ChannelSMS
+}
+
+// Send delivers an SMS.
+//
+// Reads the text from event.Data["__body__"] (injected by TemplateRenderer in
+// dispatch.go before Send is called).
+func (c *SMSChannel) Send(ctx context.Context, event dto.NotificationEvent) error {
+	// SMS disabled — skip silently.
+	if c.sender == nil {
+		logger.Debugf("[sms] SMS client not configured, skipping event_type=%s", event.EventType)
+		return nil
+	}
+
+	raw, _ := event.Data["phone"].(string)
+	if raw == "" {
+		logger.Warnf("[sms] no phone number event_type=%s user_id=%s, skipping",
+			event.EventType, event.UserID)
+		return nil // permanent — no number = no retry
+	}
+	to, err := sms.NormalizeE164(raw, c.defaultCountryCode)
+	if err != nil {
+		logger.Warnf("[sms] invalid phone number event_type=%s user_id=%s: %v",
+			event.EventType, event.UserID, err)
+		return nil // permanent — bad data, do not retry
+	}
+
+	body, _ := event.Data["__body__"].(string)
+	info := sms.CountSegments(body)
+	if c.maxSegments > 0 && info.Segments > c.maxSegments {
+		logger.Warnf("[sms] body too long event_type=%s encoding=%s segments=%d max=%d, skipping",
+			event.EventType, info.Encoding, info.Segments, c.maxSegments)
+		return nil // permanent — the same body will always be too long
+	}
+
+	provider, _ := event.Data["sms_provider"].(string)
+	if provider == "" {
+		provider = c.sender.DefaultProvider()
+	}
+
+	logger.Infof("[sms] sending via provider=%s event_type=%s user_id=%s encoding=%s segments=%d",
+		provider, event.EventType, event.UserID, info.Encoding, info.Segments)
+
+	result, err := c.sender.Send(ctx, provider, sms.Message{To: to, Body: body})
+	if err != nil {
+		if sms.IsPermanent(err) {
+			logger.Warnf("[sms] rejected permanently provider=%s event_type=%s user_id=%s: %v",
+				provider, event.EventType, event.UserID, err)
+			return nil // permanent — do not retry
+		}
+		return fmt.Errorf("[sms] send via %s failed: %w", provider, err)
+	}
+
+	c.recordMessage(ctx, event, provider, result.MessageID)
+
+	logger.Infof("[sms] sent provider=%s message_id=%s event_type=%s user_id=%s",
+		provider, result.MessageID, event.EventType, event.UserID)
+	return nil
+}
+
+// recordMessage stores the provider message ID on the log entry of this send.
+// Failures only cost the delivery receipt, so they are logged and not retried:
+// requeueing would send the SMS twice.
+func (c *SMSChannel) recordMessage(ctx context.Context, event dto.NotificationEvent, provider, messageID string) {
+	logID, _ := event.Data["__log_id__"].(int64)
+	if c.recorder == nil || logID == 0 || messageID == "" {
+		return
+	}
+	if err := c.recorder.SetProviderMessage(ctx, logID, provider, messageID); err != nil {
+		logger.Warnf("[sms] failed to record message_id=%s on log_id=%d: %v", messageID, logID, err)
+	}
+}
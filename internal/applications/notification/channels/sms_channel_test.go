@@ -0,0 +1,142 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/pkg/notification/sms"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockSMSSender struct {
+	mock.Mock
+}
+
+func (m *mockSMSSender) Send(ctx context.Context, provider string, msg sms.Message) (sms.Result, error) {
+	args := m.Called(ctx, provider, msg)
+	result, _ := args.Get(0).(sms.Result)
+	return result, args.Error(1)
+}
+
+func (m *mockSMSSender) DefaultProvider() string { return "twilio" }
+
+type mockSMSRecorder struct {
+	mock.Mock
+}
+
+func (m *mockSMSRecorder) SetProviderMessage(ctx context.Context, id int64, provider, messageID string) error {
+	return m.Called(ctx, id, provider, messageID).Error(0)
+}
+
+func smsEvent(data map[string]any) dto.NotificationEvent {
+	if data == nil {
+		data = map[string]any{}
+	}
+	data["__body__"] = "Your code is 123456"
+	return dto.NotificationEvent{
+		EventID:      "evt-1",
+		EventType:    "auth.otp",
+		DeliveryMode: dto.DeliveryModeUser,
+		UserID:       "42",
+		Channels:     []dto.Channel{dto.ChannelSMS},
+		Data:         data,
+	}
+}
+
+func newTestSMSChannel(sender SMSSender, recorder SMSMessageRecorder) *SMSChannel {
+	return &SMSChannel{sender: sender, recorder: recorder, defaultCountryCode: "62", maxSegments: 2}
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestSMSSend_NormalizesPhoneAndRecordsMessageID(t *testing.T) {
+	sender := new(mockSMSSender)
+	recorder := new(mockSMSRecorder)
+	ch := newTestSMSChannel(sender, recorder)
+
+	sender.On("Send", mock.Anything, "twilio", sms.Message{To: "+6281234567890", Body: "Your code is 123456"}).
+		Return(sms.Result{MessageID: "SM1"}, nil)
+	recorder.On("SetProviderMessage", mock.Anything, int64(7), "twilio", "SM1").Return(nil)
+
+	require.NoError(t, ch.Send(context.Background(), smsEvent(map[string]any{"phone": "0812-3456-7890", "__log_id__": int64(7)})))
+	sender.AssertExpectations(t)
+	recorder.AssertExpectations(t)
+}
+
+func TestSMSSend_UsesRequestedProvider(t *testing.T) {
+	sender := new(mockSMSSender)
+	ch := newTestSMSChannel(sender, nil)
+
+	sender.On("Send", mock.Anything, "gateway", mock.Anything).Return(sms.Result{MessageID: "m-1"}, nil)
+
+	require.NoError(t, ch.Send(context.Background(), smsEvent(map[string]any{"phone": "+6281234567890", "sms_provider": "gateway"})))
+	sender.AssertExpectations(t)
+}
+
+func TestSMSSend_InvalidPhoneIsSkipped(t *testing.T) {
+	sender := new(mockSMSSender)
+	ch := newTestSMSChannel(sender, nil)
+
+	assert.NoError(t, ch.Send(context.Background(), smsEvent(map[string]any{"phone": "call me"})))
+	assert.NoError(t, ch.Send(context.Background(), smsEvent(nil)))
+	sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSMSSend_TooManySegmentsIsSkipped(t *testing.T) {
+	sender := new(mockSMSSender)
+	ch := newTestSMSChannel(sender, nil)
+
+	event := smsEvent(map[string]any{"phone": "+6281234567890"})
+	event.Data["__body__"] = strings.Repeat("a", 307) // 3 GSM-7 segments
+	assert.NoError(t, ch.Send(context.Background(), event))
+	sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSMSSend_PermanentRejectionIsSkipped(t *testing.T) {
+	sender := new(mockSMSSender)
+	ch := newTestSMSChannel(sender, nil)
+
+	sender.On("Send", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, &sms.ProviderError{Provider: "twilio", StatusCode: 400, Code: "21610", Permanent: true})
+
+	assert.NoError(t, ch.Send(context.Background(), smsEvent(map[string]any{"phone": "+6281234567890"})))
+}
+
+func TestSMSSend_TransientFailureIsRetried(t *testing.T) {
+	sender := new(mockSMSSender)
+	ch := newTestSMSChannel(sender, nil)
+
+	sender.On("Send", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("connection reset"))
+
+	assert.Error(t, ch.Send(context.Background(), smsEvent(map[string]any{"phone": "+6281234567890"})))
+}
+
+func TestSMSSend_RecordFailureDoesNotRetry(t *testing.T) {
+	sender := new(mockSMSSender)
+	recorder := new(mockSMSRecorder)
+	ch := newTestSMSChannel(sender, recorder)
+
+	sender.On("Send", mock.Anything, mock.Anything, mock.Anything).Return(sms.Result{MessageID: "SM1"}, nil)
+	recorder.On("SetProviderMessage", mock.Anything, int64(7), "twilio", "SM1").Return(errors.New("db down"))
+
+	assert.NoError(t, ch.Send(context.Background(), smsEvent(map[string]any{"phone": "+6281234567890", "__log_id__": int64(7)})))
+}
+
+func TestNewSMSChannel_NilClientDisablesSMS(t *testing.T) {
+	ch := NewSMSChannel(nil, nil, sms.Config{})
+
+	assert.Nil(t, ch.sender)
+	assert.NoError(t, ch.Send(context.Background(), smsEvent(map[string]any{"phone": "+6281234567890"})))
+}
@@ -0,0 +1,129 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/webhook"
+)
+
+// WebhookEndpointLister finds the endpoints an event goes to. The concrete
+// *repositories.WebhookEndpointRepository satisfies this interface.
+type WebhookEndpointLister interface {
+	ListActiveByTenant(ctx context.Context, tenantID string) ([]*models.WebhookEndpoint, error)
+}
+
+// WebhookChannel pushes notification events to the webhook endpoints of a tenant.
+//
+// Send does not call the endpoints itself: it publishes one dto.WebhookDeliveryJob
+// per subscribed endpoint, and the notification_webhook consumer signs, delivers,
+// retries and logs each one, so a slow or failing endpoint never holds up the
+// other channels or the other endpoints.
+//
+// The tenant is event.Meta["tenant_id"], or the default tenant when absent.
+// The payload (dto.WebhookPayload) carries event.Data without the "__"-prefixed
+// keys added by dispatch: receivers get the raw event, not rendered text.
+//
+// Webhook pitfalls handled here:
+//   - Webhooks disabled, or no database or queue: log and return nil (permanent skip)
+//   - No endpoint subscribed to the event: return nil
+//   - Listing endpoints or publishing a job fails: return error (requeue). Endpoints
+//     already published to are published to again; receivers deduplicate on X-Webhook-Id.
+type WebhookChannel struct {
+	endpoints     WebhookEndpointLister    // nil when the database is unavailable
+	producer      rabbitmq.MessageProducer // app.events exchange; nil when the queue is unavailable
+	enabled       bool
+	defaultTenant string
+	now           func() time.Time
+}
+
+// NewWebhookChannel creates a WebhookChannel. endpoints and producer may be nil,
+// which disables the channel like cfg.Enabled = false does.
+func NewWebhookChannel(endpoints WebhookEndpointLister, producer rabbitmq.MessageProducer, cfg webhook.Config, defaultTenant string) *WebhookChannel {
+	return &WebhookChannel{
+		endpoints:     endpoints,
+		producer:      producer,
+		enabled:       cfg.Enabled,
+		defaultTenant: defaultTenant,
+		now:           time.Now,
+	}
+}
+
+func (c *WebhookChannel) Name() dto.Channel {
+	return dto.ChannelWebhook
+}
+
+// Send queues the delivery of event to every active endpoint of its tenant subscribed to event.EventType.
+func (c *WebhookChannel) Send(ctx context.Context, event dto.NotificationEvent) error {
+	if !c.enabled || c.endpoints == nil || c.producer == nil {
+		logger.Debugf("[webhook] webhooks not configured, skipping event_type=%s", event.EventType)
+		return nil
+	}
+
+	tenantID := event.Meta["tenant_id"]
+	if tenantID == "" {
+		tenantID = c.defaultTenant
+	}
+
+	endpoints, err := c.endpoints.ListActiveByTenant(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("webhook: list endpoints of tenant %s: %w", tenantID, err)
+	}
+	var targets []*models.WebhookEndpoint
+	for _, endpoint := range endpoints {
+		if endpoint.Subscribes(event.EventType) {
+			targets = append(targets, endpoint)
+		}
+	}
+	if len(targets) == 0 {
+		logger.Debugf("[webhook] no endpoint of tenant %s subscribes to %s", tenantID, event.EventType)
+		return nil
+	}
+
+	payload, err := json.Marshal(dto.WebhookPayload{
+		ID:        event.EventID,
+		Type:      event.EventType,
+		CreatedAt: c.now().UTC(),
+		TenantID:  tenantID,
+		UserID:    event.UserID,
+		Data:      payloadData(event.Data),
+	})
+	if err != nil {
+		logger.Errorf("[webhook] cannot encode event_id=%s: %v, skipping", event.EventID, err)
+		return nil // permanent — the data will not encode on retry either
+	}
+
+	for _, endpoint := range targets {
+		job := dto.WebhookDeliveryJob{
+			EndpointID: endpoint.ID,
+			EventID:    event.EventID,
+			EventType:  event.EventType,
+			Attempt:    1,
+			Payload:    payload,
+		}
+		if err := c.producer.Publish(ctx, dto.WebhookDeliveryRoutingKey, job, rabbitmq.PublishOptions{}); err != nil {
+			return fmt.Errorf("webhook: queue delivery to endpoint %d: %w", endpoint.ID, err)
+		}
+	}
+
+	logger.Debugf("[webhook] queued event_id=%s to %d endpoint(s)", event.EventID, len(targets))
+	return nil
+}
+
+// payloadData returns data without the internal keys dispatch adds (__title__, __body__, __log_id__).
+func payloadData(data map[string]any) map[string]any {
+	out := make(map[string]any, len(data))
+	for k, v := range data {
+		if !strings.HasPrefix(k, "__") {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,135 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/notification/webhook"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockWebhookLister struct {
+	mock.Mock
+}
+
+func (m *mockWebhookLister) ListActiveByTenant(ctx context.Context, tenantID string) ([]*models.WebhookEndpoint, error) {
+	args := m.Called(ctx, tenantID)
+	endpoints, _ := args.Get(0).([]*models.WebhookEndpoint)
+	return endpoints, args.Error(1)
+}
+
+type mockWebhookProducer struct {
+	mock.Mock
+}
+
+func (m *mockWebhookProducer) Publish(ctx context.Context, routingKey string, message interface{}, opts rabbitmq.PublishOptions) error {
+	return m.Called(ctx, routingKey, message, opts).Error(0)
+}
+
+func (m *mockWebhookProducer) Close() error { return nil }
+
+func webhookEvent(meta map[string]string) dto.NotificationEvent {
+	return dto.NotificationEvent{
+		EventID:      "evt-1",
+		EventType:    "order.shipped",
+		DeliveryMode: dto.DeliveryModeUser,
+		UserID:       "42",
+		Channels:     []dto.Channel{dto.ChannelWebhook},
+		Data: map[string]any{
+			"order_id":   "ORD-1",
+			"__title__":  "Your order has shipped",
+			"__log_id__": int64(7),
+		},
+		Meta: meta,
+	}
+}
+
+func newTestWebhookChannel(lister WebhookEndpointLister, producer rabbitmq.MessageProducer) *WebhookChannel {
+	return NewWebhookChannel(lister, producer, webhook.Config{Enabled: true}, "system")
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestWebhookSend_QueuesOneJobPerSubscribedEndpoint(t *testing.T) {
+	lister := new(mockWebhookLister)
+	producer := new(mockWebhookProducer)
+	ch := newTestWebhookChannel(lister, producer)
+
+	lister.On("ListActiveByTenant", mock.Anything, "acme").Return([]*models.WebhookEndpoint{
+		{ID: 1, Events: []string{"order.*"}},
+		{ID: 2, Events: []string{"user.created"}},
+		{ID: 3, Events: []string{"*"}},
+	}, nil)
+
+	var jobs []dto.WebhookDeliveryJob
+	producer.On("Publish", mock.Anything, dto.WebhookDeliveryRoutingKey, mock.Anything, rabbitmq.PublishOptions{}).
+		Run(func(args mock.Arguments) { jobs = append(jobs, args.Get(2).(dto.WebhookDeliveryJob)) }).
+		Return(nil)
+
+	require.NoError(t, ch.Send(context.Background(), webhookEvent(map[string]string{"tenant_id": "acme"})))
+
+	require.Len(t, jobs, 2)
+	assert.Equal(t, int64(1), jobs[0].EndpointID)
+	assert.Equal(t, int64(3), jobs[1].EndpointID)
+	assert.Equal(t, 1, jobs[0].Attempt)
+
+	var payload dto.WebhookPayload
+	require.NoError(t, json.Unmarshal(jobs[0].Payload, &payload))
+	assert.Equal(t, "evt-1", payload.ID)
+	assert.Equal(t, "order.shipped", payload.Type)
+	assert.Equal(t, "acme", payload.TenantID)
+	assert.Equal(t, map[string]any{"order_id": "ORD-1"}, payload.Data, "dispatch keys must not leak to receivers")
+}
+
+func TestWebhookSend_FallsBackToDefaultTenant(t *testing.T) {
+	lister := new(mockWebhookLister)
+	ch := newTestWebhookChannel(lister, new(mockWebhookProducer))
+
+	lister.On("ListActiveByTenant", mock.Anything, "system").Return([]*models.WebhookEndpoint{}, nil)
+
+	require.NoError(t, ch.Send(context.Background(), webhookEvent(nil)))
+	lister.AssertExpectations(t)
+}
+
+func TestWebhookSend_SkipsWhenDisabled(t *testing.T) {
+	lister := new(mockWebhookLister)
+	ch := NewWebhookChannel(lister, new(mockWebhookProducer), webhook.Config{Enabled: false}, "system")
+
+	require.NoError(t, ch.Send(context.Background(), webhookEvent(nil)))
+	lister.AssertNotCalled(t, "ListActiveByTenant", mock.Anything, mock.Anything)
+}
+
+func TestWebhookSend_ListErrorRequeues(t *testing.T) {
+	lister := new(mockWebhookLister)
+	ch := newTestWebhookChannel(lister, new(mockWebhookProducer))
+
+	lister.On("ListActiveByTenant", mock.Anything, "system").Return(nil, errors.New("db down"))
+
+	assert.Error(t, ch.Send(context.Background(), webhookEvent(nil)))
+}
+
+func TestWebhookSend_PublishErrorRequeues(t *testing.T) {
+	lister := new(mockWebhookLister)
+	producer := new(mockWebhookProducer)
+	ch := newTestWebhookChannel(lister, producer)
+
+	lister.On("ListActiveByTenant", mock.Anything, "system").
+		Return([]*models.WebhookEndpoint{{ID: 1, Events: []string{"*"}}}, nil)
+	producer.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("channel closed"))
+
+	assert.Error(t, ch.Send(context.Background(), webhookEvent(nil)))
+}
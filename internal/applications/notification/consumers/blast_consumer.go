@@ -2,45 +2,107 @@ package consumers
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
 
 	"ichi-go/internal/applications/notification/channels"
 	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
 	"ichi-go/internal/applications/notification/repositories"
 	"ichi-go/internal/applications/notification/services"
+	"ichi-go/internal/infra/queue/codec"
+	"ichi-go/internal/infra/queue/rabbitmq"
 	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/audience"
 )
 
-// BlastConsumer processes broadcast notifications sent to ALL users.
+// broadcastChannels address tenants rather than users: a blast sends them once
+// instead of once per user.
+var broadcastChannels = map[dto.Channel]bool{
+	dto.ChannelWebhook: true,
+}
+
+// AudienceResolver pages through the users a blast segment matches.
+// The concrete *repositories.AudienceRepository satisfies this interface.
+type AudienceResolver interface {
+	ListUserIDs(ctx context.Context, segment *models.AudienceSegment, afterID int64, limit int) ([]int64, error)
+}
+
+// BlastCheckpointStore persists how far the fan-out of each blast message got.
+// The concrete *repositories.NotificationBlastCheckpointRepository satisfies this interface.
+type BlastCheckpointStore interface {
+	Find(ctx context.Context, eventID string) (*models.NotificationBlastCheckpoint, error)
+	Create(ctx context.Context, checkpoint *models.NotificationBlastCheckpoint) error
+	Update(ctx context.Context, checkpoint *models.NotificationBlastCheckpoint) error
+}
+
+// CampaignReader loads the campaign of a blast for its segment and exclusions.
+// The concrete *repositories.NotificationCampaignRepository satisfies this interface.
+type CampaignReader interface {
+	FindByID(ctx context.Context, id int64) (*models.NotificationCampaign, error)
+}
+
+// BlastConsumer processes broadcast notifications sent to every user of a segment.
 //
 // Bound to: notification.blast exchange (fanout)
 // Routing key: ignored by fanout — every bound queue receives the message.
 //
 // Use for: system announcements, maintenance windows, feature releases,
-// promotional campaigns targeting every user.
+// promotional campaigns targeting every user or a segment of them.
+//
+// A blast fans out to one user event per user of its campaign's segment, published
+// on the notification.user exchange, so the user consumer renders, applies the
+// user's preferences and delivers it like any targeted notification. Users are read
+// in id order, in batches; after each batch the blast's checkpoint records the last
+// user read, and a redelivered blast resumes after it. After cfg.MaxBatches()
+// batches the blast re-queues itself for the rest of its audience. Channels that
+// address tenants (webhook) are sent once when the fan-out starts.
+//
+// A crash between a publish and the next checkpoint re-publishes the users of that
+// batch; the user consumer's idempotency guard drops the duplicates.
 type BlastConsumer struct {
-	channels []channels.NotificationChannel
-	renderer *services.TemplateRenderer
-	logRepo  *repositories.NotificationLogRepository
+	channels    []channels.NotificationChannel
+	renderer    *services.TemplateRenderer
+	logRepo     *repositories.NotificationLogRepository
+	audience    AudienceResolver         // nil when the database is unavailable; the blast is dispatched once
+	checkpoints BlastCheckpointStore     // nil when the database is unavailable
+	campaigns   CampaignReader           // nil reaches every user, without exclusions
+	blast       rabbitmq.MessageProducer // notification.blast; nil fans out a whole blast in one delivery
+	users       rabbitmq.MessageProducer // notification.user
+	cfg         audience.Config
 }
 
 func NewBlastConsumer(
 	renderer *services.TemplateRenderer,
 	logRepo *repositories.NotificationLogRepository,
+	resolver AudienceResolver,
+	checkpoints BlastCheckpointStore,
+	campaigns CampaignReader,
+	blastProducer rabbitmq.MessageProducer,
+	userProducer rabbitmq.MessageProducer,
+	cfg audience.Config,
 	chs ...channels.NotificationChannel,
 ) *BlastConsumer {
 	return &BlastConsumer{
-		channels: chs,
-		renderer: renderer,
-		logRepo:  logRepo,
+		channels:    chs,
+		renderer:    renderer,
+		logRepo:     logRepo,
+		audience:    resolver,
+		checkpoints: checkpoints,
+		campaigns:   campaigns,
+		blast:       blastProducer,
+		users:       userProducer,
+		cfg:         cfg,
 	}
 }
 
 // Consume is the ConsumeFunc registered in registry.go.
 func (c *BlastConsumer) Consume(ctx context.Context, body []byte) error {
 	var event dto.NotificationEvent
-	if err := json.Unmarshal(body, &event); err != nil {
+	if err := codec.Unmarshal(ctx, body, &event); err != nil {
 		// Bad JSON is a permanent failure — ack and discard, never retry.
 		logger.Errorf("[blast] invalid JSON, discarding: %v", err)
 		return nil
@@ -52,13 +114,176 @@ func (c *BlastConsumer) Consume(ctx context.Context, body []byte) error {
 		return nil
 	}
 
-	logger.Infof("[blast] dispatching event_id=%s event_type=%s channels=%v",
-		event.EventID, event.EventType, event.Channels)
-
 	// Extract campaign_id from meta for log correlation.
 	campaignID := extractCampaignID(event.Meta)
 
-	return dispatch(ctx, event, c.channels, c.renderer, c.logRepo, campaignID)
+	if c.audience == nil || c.checkpoints == nil || c.users == nil {
+		// Without the users table or the user exchange there is no one to fan out to:
+		// the blast is dispatched once and only reaches channels that need no recipient.
+		logger.Warnf("[blast] fan-out unavailable, dispatching event_id=%s event_type=%s channels=%v once",
+			event.EventID, event.EventType, event.Channels)
+		return dispatch(ctx, event, c.channels, c.renderer, c.logRepo, nil, nil, campaignID)
+	}
+
+	if event.EventID == "" {
+		logger.Errorf("[blast] missing event_id event_type=%s, discarding: the fan-out cannot be checkpointed",
+			event.EventType)
+		return nil
+	}
+
+	return c.fanOut(ctx, event, campaignID)
+}
+
+// fanOut publishes one user event per user of the blast's audience, starting after
+// the checkpoint of an earlier delivery of the same message.
+func (c *BlastConsumer) fanOut(ctx context.Context, event dto.NotificationEvent, campaignID int64) error {
+	var segment *models.AudienceSegment
+	excluded := make(map[int64]bool)
+	if campaignID != 0 && c.campaigns != nil {
+		campaign, err := c.campaigns.FindByID(ctx, campaignID)
+		if err != nil {
+			logger.Errorf("[blast] loading campaign_id=%d failed event_id=%s: %v", campaignID, event.EventID, err)
+			return err // transient — requeue
+		}
+		if campaign == nil {
+			logger.Errorf("[blast] campaign_id=%d not found event_id=%s, discarding", campaignID, event.EventID)
+			return nil
+		}
+		segment = campaign.Segment
+		for _, id := range campaign.UserExcludeIDs {
+			excluded[id] = true
+		}
+	}
+
+	checkpoint, err := c.checkpoints.Find(ctx, event.EventID)
+	if err != nil {
+		logger.Errorf("[blast] loading checkpoint failed event_id=%s: %v", event.EventID, err)
+		return err
+	}
+	if checkpoint == nil {
+		if err := c.dispatchBroadcast(ctx, event, campaignID); err != nil {
+			return err
+		}
+		checkpoint = &models.NotificationBlastCheckpoint{EventID: event.EventID, CampaignID: campaignID}
+		if err := c.checkpoints.Create(ctx, checkpoint); err != nil {
+			logger.Errorf("[blast] creating checkpoint failed event_id=%s: %v", event.EventID, err)
+			return err
+		}
+		logger.Infof("[blast] fanning out event_id=%s event_type=%s channels=%v",
+			event.EventID, event.EventType, event.Channels)
+	} else if checkpoint.CompletedAt != nil {
+		logger.Infof("[blast] event_id=%s already fanned out to %d users, skipping",
+			event.EventID, checkpoint.Published)
+		return nil
+	} else {
+		logger.Infof("[blast] resuming event_id=%s after user_id=%d (%d published)",
+			event.EventID, checkpoint.LastUserID, checkpoint.Published)
+	}
+
+	_, perUser := splitBlastChannels(event.Channels)
+	if len(perUser) == 0 {
+		return c.complete(ctx, checkpoint)
+	}
+	userEvent := event
+	userEvent.DeliveryMode = dto.DeliveryModeUser
+	userEvent.Channels = perUser
+
+	batch := c.cfg.Batch()
+	for i := 0; c.blast == nil || i < c.cfg.MaxBatches(); i++ {
+		ids, err := c.audience.ListUserIDs(ctx, segment, checkpoint.LastUserID, batch)
+		if err != nil {
+			logger.Errorf("[blast] resolving audience failed event_id=%s after user_id=%d: %v",
+				event.EventID, checkpoint.LastUserID, err)
+			return err // transient — the redelivery resumes from the checkpoint
+		}
+		for _, id := range ids {
+			if excluded[id] {
+				continue
+			}
+			if err := c.publishUser(ctx, userEvent, event.EventID, id); err != nil {
+				logger.Errorf("[blast] publishing user event failed event_id=%s user_id=%d: %v",
+					event.EventID, id, err)
+				return err
+			}
+			checkpoint.Published++
+		}
+		if len(ids) > 0 {
+			checkpoint.LastUserID = ids[len(ids)-1]
+		}
+		if len(ids) < batch {
+			return c.complete(ctx, checkpoint)
+		}
+		if err := c.checkpoints.Update(ctx, checkpoint); err != nil {
+			logger.Errorf("[blast] saving checkpoint failed event_id=%s: %v", event.EventID, err)
+			return err
+		}
+	}
+
+	// Users remain: re-queue the blast so its next batches take turns with other messages.
+	if err := c.blast.Publish(ctx, blastRoutingKey, event, rabbitmq.PublishOptions{
+		Headers: amqp.Table{
+			"x-event-type":    event.EventType,
+			"x-event-id":      event.EventID,
+			"x-delivery-mode": string(dto.DeliveryModeBlast),
+		},
+		Codec: codec.FromContext(ctx),
+	}); err != nil {
+		logger.Errorf("[blast] re-queueing failed event_id=%s: %v", event.EventID, err)
+		return err // the redelivery resumes from the checkpoint
+	}
+	logger.Infof("[blast] event_id=%s re-queued after user_id=%d (%d published)",
+		event.EventID, checkpoint.LastUserID, checkpoint.Published)
+	return nil
+}
+
+// dispatchBroadcast sends the blast once on the channels that address tenants rather than users.
+func (c *BlastConsumer) dispatchBroadcast(ctx context.Context, event dto.NotificationEvent, campaignID int64) error {
+	broadcast, _ := splitBlastChannels(event.Channels)
+	if len(broadcast) == 0 {
+		return nil
+	}
+	event.Channels = broadcast
+	// Preferences and digests are per user: a tenant-level channel is always used.
+	return dispatch(ctx, event, c.channels, c.renderer, c.logRepo, nil, nil, campaignID)
+}
+
+// publishUser publishes the user event of one user of the blast.
+func (c *BlastConsumer) publishUser(ctx context.Context, event dto.NotificationEvent, blastEventID string, userID int64) error {
+	event.UserID = strconv.FormatInt(userID, 10)
+	event.EventID = fmt.Sprintf("%s-user-%d", blastEventID, userID)
+	return c.users.Publish(ctx, userRoutingKeyPrefix+event.UserID, event, rabbitmq.PublishOptions{
+		Headers: amqp.Table{
+			"x-event-type":    event.EventType,
+			"x-event-id":      event.EventID,
+			"x-delivery-mode": string(dto.DeliveryModeUser),
+			"x-user-id":       event.UserID,
+		},
+		Codec: codec.FromContext(ctx),
+	})
+}
+
+// complete marks the fan-out of the blast done, so a redelivery publishes nothing.
+func (c *BlastConsumer) complete(ctx context.Context, checkpoint *models.NotificationBlastCheckpoint) error {
+	now := time.Now()
+	checkpoint.CompletedAt = &now
+	if err := c.checkpoints.Update(ctx, checkpoint); err != nil {
+		logger.Errorf("[blast] completing checkpoint failed event_id=%s: %v", checkpoint.EventID, err)
+		return err
+	}
+	logger.Infof("[blast] event_id=%s fanned out to %d users", checkpoint.EventID, checkpoint.Published)
+	return nil
+}
+
+// splitBlastChannels separates the channels sent once per blast from those sent per user.
+func splitBlastChannels(chs []dto.Channel) (broadcast, perUser []dto.Channel) {
+	for _, ch := range chs {
+		if broadcastChannels[ch] {
+			broadcast = append(broadcast, ch)
+		} else {
+			perUser = append(perUser, ch)
+		}
+	}
+	return broadcast, perUser
 }
 
 // extractCampaignID reads the campaign_id from the event's meta map.
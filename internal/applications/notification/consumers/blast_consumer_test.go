@@ -1,14 +1,18 @@
 package consumers
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/pkg/notification/audience"
 )
 
 // ============================================================================
@@ -161,3 +165,233 @@ func TestBlastConsume_EventWithMeta_ExtractsCampaignID(t *testing.T) {
 	// campaignID=99 is passed to dispatch — no logRepo means it's silently skipped
 	ch.AssertCalled(t, "Send", mock.Anything, mock.Anything)
 }
+
+// ============================================================================
+// BlastConsumer.Consume() — fan-out to the users of the segment
+// ============================================================================
+
+type mockAudienceResolver struct {
+	mock.Mock
+}
+
+func (m *mockAudienceResolver) ListUserIDs(ctx context.Context, segment *models.AudienceSegment, afterID int64, limit int) ([]int64, error) {
+	args := m.Called(ctx, segment, afterID, limit)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+type mockCheckpointStore struct {
+	mock.Mock
+}
+
+func (m *mockCheckpointStore) Find(ctx context.Context, eventID string) (*models.NotificationBlastCheckpoint, error) {
+	args := m.Called(ctx, eventID)
+	if cp := args.Get(0); cp != nil {
+		return cp.(*models.NotificationBlastCheckpoint), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockCheckpointStore) Create(ctx context.Context, checkpoint *models.NotificationBlastCheckpoint) error {
+	return m.Called(ctx, checkpoint).Error(0)
+}
+
+func (m *mockCheckpointStore) Update(ctx context.Context, checkpoint *models.NotificationBlastCheckpoint) error {
+	return m.Called(ctx, checkpoint).Error(0)
+}
+
+type mockCampaignReader struct {
+	mock.Mock
+}
+
+func (m *mockCampaignReader) FindByID(ctx context.Context, id int64) (*models.NotificationCampaign, error) {
+	args := m.Called(ctx, id)
+	if c := args.Get(0); c != nil {
+		return c.(*models.NotificationCampaign), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type fanOutMocks struct {
+	audience    *mockAudienceResolver
+	checkpoints *mockCheckpointStore
+	campaigns   *mockCampaignReader
+	blast       *mockJobProducer
+	users       *mockJobProducer
+}
+
+func newFanOutConsumer(batch, maxBatches int, chs ...*mockChannel) (*BlastConsumer, *fanOutMocks) {
+	m := &fanOutMocks{
+		audience:    new(mockAudienceResolver),
+		checkpoints: new(mockCheckpointStore),
+		campaigns:   new(mockCampaignReader),
+		blast:       new(mockJobProducer),
+		users:       new(mockJobProducer),
+	}
+	c := NewBlastConsumer(nil, nil, m.audience, m.checkpoints, m.campaigns, m.blast, m.users,
+		audience.Config{BatchSize: batch, BatchesPerMessage: maxBatches}, asChannels(chs...)...)
+	return c, m
+}
+
+// segmentCampaign is campaign 7, a blast to the customers of acme that excludes user 2.
+func segmentCampaign() *models.NotificationCampaign {
+	c := &models.NotificationCampaign{
+		DeliveryMode:   string(dto.DeliveryModeBlast),
+		EventSlug:      "promo.sale",
+		Channels:       []string{"email", "webhook"},
+		UserExcludeIDs: []int64{2},
+		Segment:        &models.AudienceSegment{Roles: []string{"customer"}, TenantID: "acme"},
+	}
+	c.ID = 7
+	return c
+}
+
+func campaignBlastEvent(chs ...dto.Channel) dto.NotificationEvent {
+	event := makeTestBlastEvent("campaign-7-blast", chs...)
+	event.Meta = map[string]string{"campaign_id": "7"}
+	return event
+}
+
+// publishedUsers returns the user IDs of the user events published, in order.
+func publishedUsers(p *mockJobProducer) []string {
+	var ids []string
+	for _, call := range p.Calls {
+		ids = append(ids, call.Arguments.Get(2).(dto.NotificationEvent).UserID)
+	}
+	return ids
+}
+
+func TestBlastFanOut_PublishesSegmentInBatchesWithoutExcludedUsers(t *testing.T) {
+	webhookCh := newMockChannel(dto.ChannelWebhook)
+	c, m := newFanOutConsumer(2, 10, webhookCh)
+	campaign := segmentCampaign()
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(campaign, nil)
+	m.checkpoints.On("Find", mock.Anything, "campaign-7-blast").Return(nil, nil)
+	m.checkpoints.On("Create", mock.Anything, mock.Anything).Return(nil)
+	m.checkpoints.On("Update", mock.Anything, mock.Anything).Return(nil)
+	m.audience.On("ListUserIDs", mock.Anything, campaign.Segment, int64(0), 2).Return([]int64{1, 2}, nil)
+	m.audience.On("ListUserIDs", mock.Anything, campaign.Segment, int64(2), 2).Return([]int64{3}, nil)
+	m.users.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	webhookCh.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	err := c.Consume(newCtx(), marshalEvent(t, campaignBlastEvent(dto.ChannelEmail, dto.ChannelWebhook)))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, publishedUsers(m.users))
+	m.users.AssertCalled(t, "Publish", mock.Anything, "user.3", mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.EventID == "campaign-7-blast-user-3" &&
+			e.DeliveryMode == dto.DeliveryModeUser &&
+			e.Meta["campaign_id"] == "7" &&
+			assert.ObjectsAreEqual([]dto.Channel{dto.ChannelEmail}, e.Channels)
+	}), mock.Anything)
+	// The tenant-level channel goes out once, not per user.
+	webhookCh.AssertNumberOfCalls(t, "Send", 1)
+
+	checkpoint := m.checkpoints.Calls[len(m.checkpoints.Calls)-1].Arguments.Get(1).(*models.NotificationBlastCheckpoint)
+	assert.Equal(t, int64(3), checkpoint.LastUserID)
+	assert.Equal(t, int64(2), checkpoint.Published)
+	assert.NotNil(t, checkpoint.CompletedAt)
+	m.blast.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBlastFanOut_ResumesAfterCheckpoint(t *testing.T) {
+	webhookCh := newMockChannel(dto.ChannelWebhook)
+	c, m := newFanOutConsumer(2, 10, webhookCh)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(segmentCampaign(), nil)
+	m.checkpoints.On("Find", mock.Anything, "campaign-7-blast").
+		Return(&models.NotificationBlastCheckpoint{EventID: "campaign-7-blast", CampaignID: 7, LastUserID: 40, Published: 39}, nil)
+	m.checkpoints.On("Update", mock.Anything, mock.Anything).Return(nil)
+	m.audience.On("ListUserIDs", mock.Anything, mock.Anything, int64(40), 2).Return([]int64{41}, nil)
+	m.users.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := c.Consume(newCtx(), marshalEvent(t, campaignBlastEvent(dto.ChannelEmail, dto.ChannelWebhook)))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"41"}, publishedUsers(m.users))
+	m.checkpoints.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	webhookCh.AssertNotCalled(t, "Send", mock.Anything, mock.Anything) // sent by the first delivery
+}
+
+func TestBlastFanOut_CompletedCheckpointPublishesNothing(t *testing.T) {
+	c, m := newFanOutConsumer(2, 10)
+	done := time.Now()
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(segmentCampaign(), nil)
+	m.checkpoints.On("Find", mock.Anything, "campaign-7-blast").
+		Return(&models.NotificationBlastCheckpoint{EventID: "campaign-7-blast", CompletedAt: &done}, nil)
+
+	err := c.Consume(newCtx(), marshalEvent(t, campaignBlastEvent(dto.ChannelEmail)))
+
+	require.NoError(t, err)
+	m.audience.AssertNotCalled(t, "ListUserIDs", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	m.users.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBlastFanOut_RequeuesAfterMaxBatches(t *testing.T) {
+	c, m := newFanOutConsumer(2, 1)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(segmentCampaign(), nil)
+	m.checkpoints.On("Find", mock.Anything, "campaign-7-blast").Return(nil, nil)
+	m.checkpoints.On("Create", mock.Anything, mock.Anything).Return(nil)
+	m.checkpoints.On("Update", mock.Anything, mock.Anything).Return(nil)
+	m.audience.On("ListUserIDs", mock.Anything, mock.Anything, int64(0), 2).Return([]int64{3, 4}, nil)
+	m.users.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	m.blast.On("Publish", mock.Anything, blastRoutingKey, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.EventID == "campaign-7-blast" && e.DeliveryMode == dto.DeliveryModeBlast
+	}), mock.Anything).Return(nil)
+
+	err := c.Consume(newCtx(), marshalEvent(t, campaignBlastEvent(dto.ChannelEmail)))
+
+	require.NoError(t, err)
+	m.blast.AssertExpectations(t)
+	checkpoint := m.checkpoints.Calls[len(m.checkpoints.Calls)-1].Arguments.Get(1).(*models.NotificationBlastCheckpoint)
+	assert.Equal(t, int64(4), checkpoint.LastUserID)
+	assert.Nil(t, checkpoint.CompletedAt)
+}
+
+func TestBlastFanOut_PublishFailureKeepsCheckpoint(t *testing.T) {
+	c, m := newFanOutConsumer(2, 10)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(segmentCampaign(), nil)
+	m.checkpoints.On("Find", mock.Anything, "campaign-7-blast").Return(nil, nil)
+	m.checkpoints.On("Create", mock.Anything, mock.Anything).Return(nil)
+	m.audience.On("ListUserIDs", mock.Anything, mock.Anything, int64(0), 2).Return([]int64{3, 4}, nil)
+	m.users.On("Publish", mock.Anything, "user.3", mock.Anything, mock.Anything).Return(nil)
+	m.users.On("Publish", mock.Anything, "user.4", mock.Anything, mock.Anything).Return(errors.New("channel closed"))
+
+	err := c.Consume(newCtx(), marshalEvent(t, campaignBlastEvent(dto.ChannelEmail)))
+
+	require.Error(t, err) // requeue — the redelivery resumes from the saved checkpoint
+	m.checkpoints.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestBlastFanOut_MissingCampaignDiscards(t *testing.T) {
+	c, m := newFanOutConsumer(2, 10)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(nil, nil)
+
+	err := c.Consume(newCtx(), marshalEvent(t, campaignBlastEvent(dto.ChannelEmail)))
+
+	require.NoError(t, err)
+	m.checkpoints.AssertNotCalled(t, "Find", mock.Anything, mock.Anything)
+	m.users.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBlastFanOut_WithoutCampaignReachesEveryUser(t *testing.T) {
+	c, m := newFanOutConsumer(2, 10)
+	m.checkpoints.On("Find", mock.Anything, "evt-009").Return(nil, nil)
+	m.checkpoints.On("Create", mock.Anything, mock.MatchedBy(func(cp *models.NotificationBlastCheckpoint) bool {
+		return cp.CampaignID == 0
+	})).Return(nil)
+	m.checkpoints.On("Update", mock.Anything, mock.Anything).Return(nil)
+	m.audience.On("ListUserIDs", mock.Anything, (*models.AudienceSegment)(nil), int64(0), 2).Return([]int64{2}, nil)
+	m.users.On("Publish", mock.Anything, "user.2", mock.Anything, mock.Anything).Return(nil)
+
+	err := c.Consume(newCtx(), marshalEvent(t, makeTestBlastEvent("evt-009", dto.ChannelPush)))
+
+	require.NoError(t, err)
+	m.campaigns.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	m.users.AssertExpectations(t)
+}
+
+func TestSplitBlastChannels(t *testing.T) {
+	broadcast, perUser := splitBlastChannels([]dto.Channel{dto.ChannelEmail, dto.ChannelWebhook, dto.ChannelInApp})
+
+	assert.Equal(t, []dto.Channel{dto.ChannelWebhook}, broadcast)
+	assert.Equal(t, []dto.Channel{dto.ChannelEmail, dto.ChannelInApp}, perUser)
+}
@@ -17,7 +17,20 @@ import (
 //
 // Before calling each channel's Send(), TemplateRenderer.Render() is invoked to inject
 // __title__ and __body__ into event.Data. If rendering fails (bad DB override syntax),
-// the channel is skipped (permanent — do not requeue).
+// the channel is skipped (permanent — do not requeue). The ID of the channel's
+// log entry, when one was written, is passed as __log_id__ so channels with
+// delivery receipts (SMS) can attach the provider message ID to it.
+//
+// The recipient's preferences (PreferenceService.Plan) are applied first: opted-out
+// channels are logged "skipped" with the reason, and channels held back by quiet
+// hours are re-queued together until the hours end and logged "skipped" meanwhile.
+// Templates are rendered in the recipient's preferred locale when they chose one.
+//
+// Channels that the event's digest policy batches (DigestService.Channels) and that
+// survive the preferences are buffered into the user's digest window instead of
+// sent, and logged "skipped" until the window's flush. The flush, dispatched like
+// any event, sends the buffered events as one digest notification: preferences
+// apply again, and the buffer is deleted once it is sent.
 //
 // Channel failures are logged but never block other channels — a broken push
 // provider must not prevent email from being sent.
@@ -31,6 +44,8 @@ func dispatch(
 	chs []channels.NotificationChannel,
 	renderer *services.TemplateRenderer,
 	logRepo *repositories.NotificationLogRepository,
+	preferences *services.PreferenceService,
+	digests *services.DigestService,
 	campaignID int64,
 ) error {
 	var lastTransientErr error
@@ -39,6 +54,40 @@ func dispatch(
 
 	userID, _ := strconv.ParseInt(event.UserID, 10, 64)
 
+	templateSlug := event.EventType
+	var batch *services.DigestBatch
+	if services.IsDigestFlush(event) {
+		var err error
+		batch, err = digests.Collect(ctx, event)
+		if err != nil {
+			logger.Errorf("[dispatch] loading digest failed event_id=%s: %v", event.EventID, err)
+			return err // transient — requeue
+		}
+		if batch == nil {
+			logger.Infof("[dispatch] digest event_id=%s has nothing left to send, skipping", event.EventID)
+			return nil
+		}
+		event.Channels = batch.Channels
+		event.Data = batch.Data
+		templateSlug = batch.EventType
+		logger.Infof("[dispatch] digest event_id=%s sends %d events as %s", event.EventID, batch.Count, templateSlug)
+	}
+
+	plan, err := preferences.Plan(ctx, event)
+	if err != nil {
+		logger.Errorf("[dispatch] loading preferences failed event_id=%s: %v", event.EventID, err)
+		return err // transient — requeue rather than ignore an opt-out
+	}
+	var deferred []dto.Channel
+	var deferredLogIDs []int64
+
+	digestible := make(map[dto.Channel]bool)
+	for _, ch := range digests.Channels(event) {
+		digestible[ch] = true
+	}
+	var buffered []dto.Channel
+	var bufferedLogIDs []int64
+
 	for _, ch := range chs {
 		if !event.HasChannel(ch.Name()) {
 			continue
@@ -58,17 +107,32 @@ func dispatch(
 			}
 		}
 
+		skipReason, deferUntilQuietEnd := plan.Decide(ch.Name())
+		if skipReason != "" {
+			logger.Infof("[dispatch] channel=%s event_id=%s skipped: %s", ch.Name(), event.EventID, skipReason)
+			if logRepo != nil && log.ID > 0 {
+				_ = logRepo.UpdateStatus(ctx, log.ID, models.LogStatusSkipped, skipReason, nil)
+			}
+			continue // permanent — the user does not want it
+		}
+		if deferUntilQuietEnd {
+			deferred = append(deferred, ch.Name())
+			deferredLogIDs = append(deferredLogIDs, log.ID)
+			continue
+		}
+		if digestible[ch.Name()] {
+			buffered = append(buffered, ch.Name())
+			bufferedLogIDs = append(bufferedLogIDs, log.ID)
+			continue
+		}
+
 		// Render template — inject __title__ and __body__ into event.Data.
 		// Make a per-channel copy of Data so different channels get independent rendering.
 		eventCopy := event
 		eventCopy.Data = copyData(event.Data)
 
 		if renderer != nil {
-			locale := event.Locale
-			if locale == "" {
-				locale = "en"
-			}
-			rendered, err := renderer.Render(ctx, event.EventType, string(ch.Name()), locale, eventCopy.Data)
+			rendered, err := renderer.Render(ctx, templateSlug, string(ch.Name()), plan.Locale(event), eventCopy.Data)
 			if err != nil {
 				logger.Warnf("[dispatch] template render failed channel=%s event_id=%s: %v — skipping channel",
 					ch.Name(), event.EventID, err)
@@ -84,6 +148,13 @@ func dispatch(
 			eventCopy.Data["__body__"] = rendered.Body
 		}
 
+		if log.ID > 0 {
+			if eventCopy.Data == nil {
+				eventCopy.Data = make(map[string]any)
+			}
+			eventCopy.Data["__log_id__"] = log.ID
+		}
+
 		// Send via channel.
 		if err := ch.Send(ctx, eventCopy); err != nil {
 			logger.Errorf("[dispatch] channel=%s event_id=%s failed: %v",
@@ -103,6 +174,51 @@ func dispatch(
 		logger.Debugf("[dispatch] channel=%s event_id=%s ok", ch.Name(), event.EventID)
 	}
 
+	if len(deferred) > 0 {
+		status, reason := models.LogStatusSkipped, "quiet hours: deferred until "+plan.DeferUntil().UTC().Format(time.RFC3339)
+		if err := preferences.Defer(ctx, event, deferred, plan.DeferUntil()); err != nil {
+			logger.Errorf("[dispatch] deferring channels=%v event_id=%s failed: %v", deferred, event.EventID, err)
+			status, reason = models.LogStatusFailed, err.Error()
+			lastTransientErr = err
+		} else {
+			logger.Infof("[dispatch] channels=%v event_id=%s %s", deferred, event.EventID, reason)
+			successCount++
+		}
+		for _, id := range deferredLogIDs {
+			if logRepo != nil && id > 0 {
+				_ = logRepo.UpdateStatus(ctx, id, status, reason, nil)
+			}
+		}
+	}
+
+	if len(buffered) > 0 {
+		status, reason := models.LogStatusSkipped, "digest: already buffered"
+		if flushAt, err := digests.Buffer(ctx, event, buffered); err != nil {
+			logger.Errorf("[dispatch] buffering channels=%v event_id=%s failed: %v", buffered, event.EventID, err)
+			status, reason = models.LogStatusFailed, err.Error()
+			lastTransientErr = err
+		} else {
+			if !flushAt.IsZero() {
+				reason = "digest: buffered until " + flushAt.UTC().Format(time.RFC3339)
+			}
+			logger.Infof("[dispatch] channels=%v event_id=%s %s", buffered, event.EventID, reason)
+			successCount++
+		}
+		for _, id := range bufferedLogIDs {
+			if logRepo != nil && id > 0 {
+				_ = logRepo.UpdateStatus(ctx, id, status, reason, nil)
+			}
+		}
+	}
+
+	// A digest is deleted once sent. Its channels deferred to the end of quiet hours
+	// are sent by the deferred flush, which reads the same buffer.
+	if batch != nil && len(deferred) == 0 && (successCount > 0 || lastTransientErr == nil) {
+		if err := digests.Done(ctx, event); err != nil {
+			logger.Warnf("[dispatch] deleting digest event_id=%s failed: %v", event.EventID, err)
+		}
+	}
+
 	if targetCount == 0 {
 		logger.Warnf("[dispatch] event_id=%s has no matching registered channels for %v",
 			event.EventID, event.Channels)
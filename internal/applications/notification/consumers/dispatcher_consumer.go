@@ -2,12 +2,13 @@ package consumers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 
 	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/internal/infra/queue/codec"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	"ichi-go/pkg/logger"
 )
@@ -21,6 +22,12 @@ const (
 	userRoutingKeyPrefix = "user."
 )
 
+// CampaignStatusReader reads the status of a campaign, so cancelled campaigns are
+// not delivered. The concrete *repositories.NotificationCampaignRepository satisfies this interface.
+type CampaignStatusReader interface {
+	FindStatus(ctx context.Context, id int64) (models.CampaignStatus, error)
+}
+
 // DispatcherConsumer is bound to the app.events (x-delayed-message) exchange
 // with routing key "notification.dispatch".
 //
@@ -31,6 +38,8 @@ const (
 // The blast and user exchanges are NOT x-delayed-message type. All delay logic
 // lives in one place (the app.events exchange), keeping blast/user exchanges clean.
 //
+// Messages of campaigns cancelled while they waited in the delay queue are dropped.
+//
 // Flow:
 //
 //	CampaignService → app.events (x-delay=N) → [delay expires] → DispatcherConsumer
@@ -39,30 +48,41 @@ const (
 type DispatcherConsumer struct {
 	blastProducer rabbitmq.MessageProducer // bound to notification.blast (fanout) exchange
 	userProducer  rabbitmq.MessageProducer // bound to notification.user (direct) exchange
+	campaigns     CampaignStatusReader     // nil when the database is unavailable; cancellation is not checked
 }
 
 func NewDispatcherConsumer(
 	blastProducer rabbitmq.MessageProducer,
 	userProducer rabbitmq.MessageProducer,
+	campaigns CampaignStatusReader,
 ) *DispatcherConsumer {
 	return &DispatcherConsumer{
 		blastProducer: blastProducer,
 		userProducer:  userProducer,
+		campaigns:     campaigns,
 	}
 }
 
 // Consume processes a delayed notification message and re-routes it.
 func (c *DispatcherConsumer) Consume(ctx context.Context, body []byte) error {
 	var event dto.NotificationEvent
-	if err := json.Unmarshal(body, &event); err != nil {
-		// Bad JSON is a permanent failure — ack and discard, never retry.
-		logger.Errorf("[dispatcher] invalid JSON, discarding: %v", err)
+	if err := codec.Unmarshal(ctx, body, &event); err != nil {
+		// Undecodable payload is a permanent failure — ack and discard, never retry.
+		logger.Errorf("[dispatcher] invalid payload, discarding: %v", err)
 		return nil
 	}
 
 	logger.Infof("[dispatcher] routing event_id=%s event_type=%s delivery_mode=%s",
 		event.EventID, event.EventType, event.DeliveryMode)
 
+	if cancelled, err := c.cancelled(ctx, event); err != nil {
+		logger.Errorf("[dispatcher] campaign status lookup failed event_id=%s: %v", event.EventID, err)
+		return err // transient — requeue rather than deliver a cancelled campaign
+	} else if cancelled {
+		logger.Infof("[dispatcher] campaign cancelled, dropping event_id=%s", event.EventID)
+		return nil
+	}
+
 	opts := rabbitmq.PublishOptions{
 		Headers: amqp.Table{
 			"x-event-type":    event.EventType,
@@ -70,6 +90,8 @@ func (c *DispatcherConsumer) Consume(ctx context.Context, body []byte) error {
 			"x-delivery-mode": string(event.DeliveryMode),
 		},
 		// No delay on re-publish — deliver immediately.
+		// Keep the inbound codec so downstream consumers see the same ContentType.
+		Codec: codec.FromContext(ctx),
 	}
 
 	switch event.DeliveryMode {
@@ -101,3 +123,17 @@ func (c *DispatcherConsumer) Consume(ctx context.Context, body []byte) error {
 		return fmt.Errorf("unknown delivery_mode: %s", event.DeliveryMode) // won't retry if consumer is configured correctly
 	}
 }
+
+// cancelled reports whether the campaign of event was cancelled. Events published
+// outside a campaign carry no campaign_id and are never cancelled.
+func (c *DispatcherConsumer) cancelled(ctx context.Context, event dto.NotificationEvent) (bool, error) {
+	campaignID := extractCampaignID(event.Meta)
+	if c.campaigns == nil || campaignID == 0 {
+		return false, nil
+	}
+	status, err := c.campaigns.FindStatus(ctx, campaignID)
+	if err != nil {
+		return false, err
+	}
+	return status == models.CampaignStatusCancelled, nil
+}
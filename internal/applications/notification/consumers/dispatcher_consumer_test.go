@@ -0,0 +1,79 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+)
+
+type mockCampaignStatusReader struct {
+	mock.Mock
+}
+
+func (m *mockCampaignStatusReader) FindStatus(ctx context.Context, id int64) (models.CampaignStatus, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.CampaignStatus), args.Error(1)
+}
+
+func campaignUserEvent(campaignID string) dto.NotificationEvent {
+	event := makeTestUserEvent("42", "campaign-7-user-42", dto.ChannelEmail)
+	event.Meta = map[string]string{"campaign_id": campaignID}
+	return event
+}
+
+func TestDispatcher_DropsCancelledCampaign(t *testing.T) {
+	blast, user := new(mockJobProducer), new(mockJobProducer)
+	campaigns := new(mockCampaignStatusReader)
+	campaigns.On("FindStatus", mock.Anything, int64(7)).Return(models.CampaignStatusCancelled, nil)
+	c := NewDispatcherConsumer(blast, user, campaigns)
+
+	err := c.Consume(context.Background(), marshalEvent(t, campaignUserEvent("7")))
+
+	require.NoError(t, err)
+	user.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDispatcher_RoutesPublishedCampaign(t *testing.T) {
+	blast, user := new(mockJobProducer), new(mockJobProducer)
+	campaigns := new(mockCampaignStatusReader)
+	campaigns.On("FindStatus", mock.Anything, int64(7)).Return(models.CampaignStatusPublished, nil)
+	user.On("Publish", mock.Anything, "user.42", mock.Anything, mock.Anything).Return(nil)
+	c := NewDispatcherConsumer(blast, user, campaigns)
+
+	err := c.Consume(context.Background(), marshalEvent(t, campaignUserEvent("7")))
+
+	require.NoError(t, err)
+	user.AssertExpectations(t)
+}
+
+func TestDispatcher_RequeuesWhenStatusLookupFails(t *testing.T) {
+	blast, user := new(mockJobProducer), new(mockJobProducer)
+	campaigns := new(mockCampaignStatusReader)
+	campaigns.On("FindStatus", mock.Anything, int64(7)).Return(models.CampaignStatus(""), errors.New("db down"))
+	c := NewDispatcherConsumer(blast, user, campaigns)
+
+	err := c.Consume(context.Background(), marshalEvent(t, campaignUserEvent("7")))
+
+	assert.Error(t, err)
+	user.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDispatcher_EventWithoutCampaignSkipsStatusCheck(t *testing.T) {
+	blast, user := new(mockJobProducer), new(mockJobProducer)
+	campaigns := new(mockCampaignStatusReader)
+	blast.On("Publish", mock.Anything, blastRoutingKey, mock.Anything, mock.Anything).Return(nil)
+	c := NewDispatcherConsumer(blast, user, campaigns)
+
+	err := c.Consume(context.Background(), marshalEvent(t, makeTestBlastEvent("evt-1", dto.ChannelPush)))
+
+	require.NoError(t, err)
+	campaigns.AssertNotCalled(t, "FindStatus", mock.Anything, mock.Anything)
+	blast.AssertExpectations(t)
+}
@@ -2,7 +2,6 @@ package consumers
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,6 +10,7 @@ import (
 	"ichi-go/internal/applications/notification/dto"
 	"ichi-go/internal/applications/notification/repositories"
 	"ichi-go/internal/applications/notification/services"
+	"ichi-go/internal/infra/queue/codec"
 	"ichi-go/pkg/logger"
 )
 
@@ -22,23 +22,29 @@ import (
 // Use for: OTPs, order updates, account alerts, password resets,
 // personal recommendations — anything that must reach exactly one person.
 type UserNotificationConsumer struct {
-	channels []channels.NotificationChannel
-	renderer *services.TemplateRenderer
-	logRepo  *repositories.NotificationLogRepository
-	redis    *redis.Client // nil when Redis is unavailable; idempotency guard is skipped
+	channels    []channels.NotificationChannel
+	renderer    *services.TemplateRenderer
+	logRepo     *repositories.NotificationLogRepository
+	preferences *services.PreferenceService // nil delivers every channel the event lists
+	digests     *services.DigestService     // nil sends every event on its own
+	redis       *redis.Client               // nil when Redis is unavailable; idempotency guard is skipped
 }
 
 func NewUserNotificationConsumer(
 	renderer *services.TemplateRenderer,
 	logRepo *repositories.NotificationLogRepository,
+	preferences *services.PreferenceService,
+	digests *services.DigestService,
 	redisClient *redis.Client,
 	chs ...channels.NotificationChannel,
 ) *UserNotificationConsumer {
 	return &UserNotificationConsumer{
-		channels: chs,
-		renderer: renderer,
-		logRepo:  logRepo,
-		redis:    redisClient,
+		channels:    chs,
+		renderer:    renderer,
+		logRepo:     logRepo,
+		preferences: preferences,
+		digests:     digests,
+		redis:       redisClient,
 	}
 }
 
@@ -58,7 +64,7 @@ func maskUserID(uid string) string {
 // Consume is the ConsumeFunc registered in registry.go.
 func (c *UserNotificationConsumer) Consume(ctx context.Context, body []byte) error {
 	var event dto.NotificationEvent
-	if err := json.Unmarshal(body, &event); err != nil {
+	if err := codec.Unmarshal(ctx, body, &event); err != nil {
 		logger.Errorf("[user-notif] invalid JSON, discarding: %v", err)
 		return nil
 	}
@@ -99,5 +105,5 @@ func (c *UserNotificationConsumer) Consume(ctx context.Context, body []byte) err
 		}
 	}
 
-	return dispatch(ctx, event, c.channels, c.renderer, c.logRepo, campaignID)
+	return dispatch(ctx, event, c.channels, c.renderer, c.logRepo, c.preferences, c.digests, campaignID)
 }
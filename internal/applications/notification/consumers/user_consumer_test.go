@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -11,6 +12,11 @@ import (
 
 	"ichi-go/internal/applications/notification/channels"
 	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/internal/applications/notification/services"
+	"ichi-go/pkg/notification/digest"
+	"ichi-go/pkg/notification/preference"
+	notiftemplate "ichi-go/pkg/notification/template"
 )
 
 // ============================================================================
@@ -142,3 +148,132 @@ func TestUserConsume_EmptyEventID_NilRedis(t *testing.T) {
 	require.NoError(t, err)
 	ch.AssertCalled(t, "Send", mock.Anything, mock.Anything)
 }
+
+// ============================================================================
+// Preferences
+// ============================================================================
+
+type stubPreferenceRepo struct {
+	pref *models.NotificationPreference
+}
+
+func (s *stubPreferenceRepo) Get(ctx context.Context, userID int64) (*models.NotificationPreference, error) {
+	return s.pref, nil
+}
+
+func (s *stubPreferenceRepo) Save(ctx context.Context, pref *models.NotificationPreference) error {
+	return nil
+}
+
+func (s *stubPreferenceRepo) SetOptOut(ctx context.Context, userID int64, category, channel string, optedOut bool) error {
+	return nil
+}
+
+type loginTemplate struct{}
+
+func (loginTemplate) Slug() string                { return "otp.login" }
+func (loginTemplate) SupportedChannels() []string { return []string{"email", "push"} }
+func (loginTemplate) DefaultContent(channel, locale string) notiftemplate.ChannelContent {
+	return notiftemplate.ChannelContent{}
+}
+func (loginTemplate) Classification() notiftemplate.Classification {
+	return notiftemplate.Classification{Category: "account", Kind: notiftemplate.KindTransactional}
+}
+
+func TestUserConsume_SkipsOptedOutChannels(t *testing.T) {
+	reg := notiftemplate.NewRegistry()
+	reg.Register(loginTemplate{})
+	prefs := services.NewPreferenceService(&stubPreferenceRepo{pref: &models.NotificationPreference{
+		UserID:  42,
+		OptOuts: []*models.NotificationOptOut{{UserID: 42, Category: "account", Channel: "push"}},
+	}}, reg, nil, nil, preference.Config{Enabled: true})
+
+	emailCh := newMockChannel(dto.ChannelEmail)
+	pushCh := newMockChannel(dto.ChannelPush)
+	c := &UserNotificationConsumer{channels: asChannels(emailCh, pushCh), preferences: prefs}
+	emailCh.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	event := makeTestUserEvent("42", "evt-007", dto.ChannelEmail, dto.ChannelPush)
+	err := c.Consume(newCtx(), marshalEvent(t, event))
+
+	require.NoError(t, err)
+	emailCh.AssertCalled(t, "Send", mock.Anything, mock.Anything)
+	pushCh.AssertNotCalled(t, "Send")
+}
+
+// ============================================================================
+// Digests
+// ============================================================================
+
+type shippedTemplate struct{ loginTemplate }
+
+func (shippedTemplate) Slug() string { return "order.shipped" }
+func (shippedTemplate) Digest() notiftemplate.DigestPolicy {
+	return notiftemplate.DigestPolicy{Window: time.Minute, Template: "order.shipped.digest", Channels: []string{"push"}}
+}
+
+type shippedDigestTemplate struct{ loginTemplate }
+
+func (shippedDigestTemplate) Slug() string { return "order.shipped.digest" }
+
+func newDigestConsumer(pref *models.NotificationPreference, chs ...*mockChannel) (*UserNotificationConsumer, *mockJobProducer) {
+	reg := notiftemplate.NewRegistry()
+	reg.Register(shippedTemplate{})
+	reg.Register(shippedDigestTemplate{})
+	producer := new(mockJobProducer)
+	c := &UserNotificationConsumer{
+		channels:    asChannels(chs...),
+		preferences: services.NewPreferenceService(&stubPreferenceRepo{pref: pref}, reg, nil, nil, preference.Config{Enabled: true}),
+		digests:     services.NewDigestService(reg, digest.NewMemoryStore(), producer, digest.Config{Enabled: true}),
+	}
+	return c, producer
+}
+
+func shippedUserEvent(eventID, orderID string) dto.NotificationEvent {
+	event := makeTestUserEvent("42", eventID, dto.ChannelEmail, dto.ChannelPush)
+	event.EventType = "order.shipped"
+	event.Data = map[string]any{"order_id": orderID}
+	return event
+}
+
+func TestUserConsume_DigestsBufferedEventsIntoOneNotification(t *testing.T) {
+	emailCh := newMockChannel(dto.ChannelEmail)
+	pushCh := newMockChannel(dto.ChannelPush)
+	c, producer := newDigestConsumer(nil, emailCh, pushCh)
+	emailCh.On("Send", mock.Anything, mock.Anything).Return(nil)
+	producer.On("Publish", mock.Anything, "notification.dispatch", mock.Anything, mock.Anything).Return(nil).Once()
+
+	for _, event := range []dto.NotificationEvent{
+		shippedUserEvent("evt-1", "ORD-1"),
+		shippedUserEvent("evt-2", "ORD-2"),
+		shippedUserEvent("evt-2", "ORD-2"), // redelivered
+	} {
+		require.NoError(t, c.Consume(newCtx(), marshalEvent(t, event)))
+	}
+	emailCh.AssertNumberOfCalls(t, "Send", 3)
+	pushCh.AssertNotCalled(t, "Send")
+	producer.AssertExpectations(t)
+
+	flush := producer.Calls[0].Arguments.Get(2).(dto.NotificationEvent)
+	pushCh.On("Send", mock.Anything, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.Data["count"] == 2 && e.Data["more"] == 0
+	})).Return(nil).Once()
+	require.NoError(t, c.Consume(newCtx(), marshalEvent(t, flush)))
+	require.NoError(t, c.Consume(newCtx(), marshalEvent(t, flush)), "a redelivered flush sends nothing")
+
+	pushCh.AssertExpectations(t)
+	emailCh.AssertNumberOfCalls(t, "Send", 3)
+}
+
+func TestUserConsume_OptedOutChannelsAreNotDigested(t *testing.T) {
+	pushCh := newMockChannel(dto.ChannelPush)
+	c, producer := newDigestConsumer(&models.NotificationPreference{
+		UserID:  42,
+		OptOuts: []*models.NotificationOptOut{{UserID: 42, Category: "account", Channel: "push"}},
+	}, pushCh)
+
+	require.NoError(t, c.Consume(newCtx(), marshalEvent(t, shippedUserEvent("evt-1", "ORD-1"))))
+
+	pushCh.AssertNotCalled(t, "Send")
+	producer.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,202 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/internal/infra/queue/codec"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/webhook"
+)
+
+// WebhookEndpointStore is the endpoint persistence WebhookDeliveryConsumer uses.
+// The concrete *repositories.WebhookEndpointRepository satisfies this interface.
+type WebhookEndpointStore interface {
+	FindByID(ctx context.Context, id int64) (*models.WebhookEndpoint, error)
+	RecordSuccess(ctx context.Context, id int64, at time.Time) error
+	RecordFailure(ctx context.Context, id int64, at time.Time, disableAfter int, reason string) (bool, error)
+	Disable(ctx context.Context, id int64, at time.Time, reason string) error
+}
+
+// WebhookDeliveryLog records delivery attempts. The concrete
+// *repositories.WebhookDeliveryRepository satisfies this interface.
+type WebhookDeliveryLog interface {
+	Create(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+// WebhookDeliverer sends one signed delivery; satisfied by *webhook.Client.
+type WebhookDeliverer interface {
+	Deliver(ctx context.Context, req webhook.Request) (*webhook.Result, error)
+}
+
+// WebhookDeliveryConsumer delivers webhook jobs queued by the webhook channel.
+//
+// Routing key: "notification.webhook" on app.events. Every attempt is written to
+// the delivery log. A failed attempt is retried by publishing the job again with
+// an x-delay of Config.Backoff, up to Config.MaxAttempts; the message itself is
+// always acked, so one failing endpoint never blocks the queue.
+//
+// The endpoint is disabled after Config.DisableAfter failed attempts in a row, or
+// at once when it answers 410 Gone. Jobs of disabled or deleted endpoints are dropped.
+type WebhookDeliveryConsumer struct {
+	endpoints  WebhookEndpointStore
+	deliveries WebhookDeliveryLog
+	client     WebhookDeliverer         // nil when webhooks are disabled
+	producer   rabbitmq.MessageProducer // app.events exchange; nil disables retries
+	cfg        webhook.Config
+	now        func() time.Time
+}
+
+// NewWebhookDeliveryConsumer creates a WebhookDeliveryConsumer. client may be nil
+// when webhooks are not configured; endpoints and deliveries are nil when the
+// database is unavailable.
+func NewWebhookDeliveryConsumer(
+	endpoints WebhookEndpointStore,
+	deliveries WebhookDeliveryLog,
+	client *webhook.Client,
+	producer rabbitmq.MessageProducer,
+	cfg webhook.Config,
+) *WebhookDeliveryConsumer {
+	c := &WebhookDeliveryConsumer{
+		endpoints:  endpoints,
+		deliveries: deliveries,
+		producer:   producer,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+	if client != nil {
+		c.client = client
+	}
+	return c
+}
+
+// Consume is the ConsumeFunc registered in registry.go.
+func (c *WebhookDeliveryConsumer) Consume(ctx context.Context, body []byte) error {
+	var job dto.WebhookDeliveryJob
+	if err := codec.Unmarshal(ctx, body, &job); err != nil {
+		logger.Errorf("[webhook] invalid job, discarding: %v", err)
+		return nil
+	}
+	if c.client == nil || c.endpoints == nil || c.deliveries == nil {
+		logger.Warnf("[webhook] webhooks not configured, dropping delivery of event_id=%s to endpoint %d",
+			job.EventID, job.EndpointID)
+		return nil
+	}
+	if job.Attempt < 1 {
+		job.Attempt = 1
+	}
+
+	endpoint, err := c.endpoints.FindByID(ctx, job.EndpointID)
+	if err != nil {
+		return err // database down — requeue
+	}
+	if endpoint == nil || !endpoint.IsActive {
+		logger.Infof("[webhook] endpoint %d deleted or disabled, dropping event_id=%s", job.EndpointID, job.EventID)
+		return nil
+	}
+
+	result, deliverErr := c.client.Deliver(ctx, webhook.Request{
+		URL:       endpoint.URL,
+		Secret:    endpoint.Secret,
+		EventID:   job.EventID,
+		EventType: job.EventType,
+		Attempt:   job.Attempt,
+		Body:      job.Payload,
+	})
+	now := c.now()
+
+	delivery := &models.WebhookDelivery{
+		EndpointID:      endpoint.ID,
+		EventID:         job.EventID,
+		EventType:       job.EventType,
+		Attempt:         job.Attempt,
+		RedeliveryOf:    job.RedeliveryOf,
+		RequestURL:      endpoint.URL,
+		RequestHeaders:  redactSignature(result.RequestHeaders),
+		RequestBody:     string(job.Payload),
+		ResponseStatus:  result.StatusCode,
+		ResponseHeaders: result.ResponseHeaders,
+		ResponseBody:    result.ResponseBody,
+		DurationMS:      result.Duration.Milliseconds(),
+	}
+
+	switch {
+	case deliverErr == nil && result.Succeeded():
+		delivery.Status = models.WebhookDeliverySucceeded
+		if err := c.endpoints.RecordSuccess(ctx, endpoint.ID, now); err != nil {
+			logger.Warnf("[webhook] failed to record success of endpoint %d: %v", endpoint.ID, err)
+		}
+
+	case deliverErr == nil && result.StatusCode == http.StatusGone:
+		// The receiver asked us to stop: no retries, no waiting for the failure threshold.
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.Error = "endpoint answered 410 Gone; endpoint disabled"
+		if err := c.endpoints.Disable(ctx, endpoint.ID, now, "endpoint answered 410 Gone"); err != nil {
+			logger.Warnf("[webhook] failed to disable endpoint %d: %v", endpoint.ID, err)
+		}
+
+	default:
+		if deliverErr != nil {
+			delivery.Error = deliverErr.Error()
+		} else {
+			delivery.Error = fmt.Sprintf("endpoint answered HTTP %d", result.StatusCode)
+		}
+		delivery.Status = models.WebhookDeliveryFailed
+
+		reason := fmt.Sprintf("%d failed attempts in a row", c.cfg.DisableAfter)
+		disabled, err := c.endpoints.RecordFailure(ctx, endpoint.ID, now, c.cfg.DisableAfter, reason)
+		if err != nil {
+			logger.Warnf("[webhook] failed to record failure of endpoint %d: %v", endpoint.ID, err)
+		}
+		if disabled {
+			logger.Warnf("[webhook] endpoint %d of tenant %s disabled after %s", endpoint.ID, endpoint.TenantID, reason)
+			delivery.Error += "; endpoint disabled"
+		} else if job.Attempt < c.cfg.MaxAttempts {
+			if next, ok := c.retry(ctx, job); ok {
+				delivery.Status = models.WebhookDeliveryRetrying
+				delivery.NextRetryAt = &next
+			}
+		}
+	}
+
+	if err := c.deliveries.Create(ctx, delivery); err != nil {
+		// Not retried: the endpoint already received (or refused) the request.
+		logger.Errorf("[webhook] failed to log delivery of event_id=%s to endpoint %d: %v", job.EventID, endpoint.ID, err)
+	}
+	logger.Infof("[webhook] event_id=%s endpoint=%d attempt=%d status=%s",
+		job.EventID, endpoint.ID, job.Attempt, delivery.Status)
+	return nil
+}
+
+// retry publishes the next attempt of job with a backoff delay and returns when it is due.
+func (c *WebhookDeliveryConsumer) retry(ctx context.Context, job dto.WebhookDeliveryJob) (time.Time, bool) {
+	if c.producer == nil {
+		return time.Time{}, false
+	}
+	delay := c.cfg.Backoff(job.Attempt)
+	next := job
+	next.Attempt++
+	if err := c.producer.Publish(ctx, dto.WebhookDeliveryRoutingKey, next, rabbitmq.PublishOptions{Delay: delay}); err != nil {
+		logger.Errorf("[webhook] failed to schedule retry of event_id=%s to endpoint %d: %v", job.EventID, job.EndpointID, err)
+		return time.Time{}, false
+	}
+	return c.now().Add(delay), true
+}
+
+// redactSignature drops the signature from the logged request headers: together
+// with the logged body and timestamp it would let anyone reading the log replay it.
+func redactSignature(headers map[string]string) map[string]string {
+	out := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if k == webhook.HeaderSignature {
+			v = "[redacted]"
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,239 @@
+package consumers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/notification/webhook"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockEndpointStore struct {
+	mock.Mock
+}
+
+func (m *mockEndpointStore) FindByID(ctx context.Context, id int64) (*models.WebhookEndpoint, error) {
+	args := m.Called(ctx, id)
+	endpoint, _ := args.Get(0).(*models.WebhookEndpoint)
+	return endpoint, args.Error(1)
+}
+
+func (m *mockEndpointStore) RecordSuccess(ctx context.Context, id int64, at time.Time) error {
+	return m.Called(ctx, id, at).Error(0)
+}
+
+func (m *mockEndpointStore) RecordFailure(ctx context.Context, id int64, at time.Time, disableAfter int, reason string) (bool, error) {
+	args := m.Called(ctx, id, at, disableAfter, reason)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockEndpointStore) Disable(ctx context.Context, id int64, at time.Time, reason string) error {
+	return m.Called(ctx, id, at, reason).Error(0)
+}
+
+type mockDeliveryLog struct {
+	mock.Mock
+}
+
+func (m *mockDeliveryLog) Create(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return m.Called(ctx, delivery).Error(0)
+}
+
+type mockDeliverer struct {
+	mock.Mock
+}
+
+func (m *mockDeliverer) Deliver(ctx context.Context, req webhook.Request) (*webhook.Result, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*webhook.Result), args.Error(1)
+}
+
+type mockJobProducer struct {
+	mock.Mock
+}
+
+func (m *mockJobProducer) Publish(ctx context.Context, routingKey string, message interface{}, opts rabbitmq.PublishOptions) error {
+	return m.Called(ctx, routingKey, message, opts).Error(0)
+}
+
+func (m *mockJobProducer) Close() error { return nil }
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+var webhookNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+type webhookConsumerMocks struct {
+	endpoints  *mockEndpointStore
+	deliveries *mockDeliveryLog
+	client     *mockDeliverer
+	producer   *mockJobProducer
+	logged     *models.WebhookDelivery
+}
+
+func newTestWebhookConsumer() (*WebhookDeliveryConsumer, *webhookConsumerMocks) {
+	m := &webhookConsumerMocks{
+		endpoints:  new(mockEndpointStore),
+		deliveries: new(mockDeliveryLog),
+		client:     new(mockDeliverer),
+		producer:   new(mockJobProducer),
+	}
+	m.deliveries.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { m.logged = args.Get(1).(*models.WebhookDelivery) }).
+		Return(nil)
+
+	c := &WebhookDeliveryConsumer{
+		endpoints:  m.endpoints,
+		deliveries: m.deliveries,
+		client:     m.client,
+		producer:   m.producer,
+		cfg: webhook.Config{
+			MaxAttempts:  3,
+			BackoffBase:  30 * time.Second,
+			BackoffMax:   time.Hour,
+			DisableAfter: 15,
+		},
+		now: func() time.Time { return webhookNow },
+	}
+	return c, m
+}
+
+func webhookJob(t *testing.T, attempt int) []byte {
+	t.Helper()
+	b, err := json.Marshal(dto.WebhookDeliveryJob{
+		EndpointID: 9,
+		EventID:    "evt-1",
+		EventType:  "order.shipped",
+		Attempt:    attempt,
+		Payload:    json.RawMessage(`{"id":"evt-1"}`),
+	})
+	require.NoError(t, err)
+	return b
+}
+
+func activeEndpoint() *models.WebhookEndpoint {
+	return &models.WebhookEndpoint{ID: 9, TenantID: "acme", URL: "https://hooks.example.com/in", Secret: "whsec_test", IsActive: true}
+}
+
+// ============================================================================
+// WebhookDeliveryConsumer.Consume()
+// ============================================================================
+
+func TestWebhookConsume_Success(t *testing.T) {
+	c, m := newTestWebhookConsumer()
+	m.endpoints.On("FindByID", mock.Anything, int64(9)).Return(activeEndpoint(), nil)
+	m.client.On("Deliver", mock.Anything, mock.MatchedBy(func(req webhook.Request) bool {
+		return req.URL == "https://hooks.example.com/in" && req.Secret == "whsec_test" && req.Attempt == 1
+	})).Return(&webhook.Result{
+		StatusCode:     200,
+		RequestHeaders: map[string]string{webhook.HeaderSignature: "v1=abc", webhook.HeaderID: "evt-1"},
+	}, nil)
+	m.endpoints.On("RecordSuccess", mock.Anything, int64(9), webhookNow).Return(nil)
+
+	require.NoError(t, c.Consume(newCtx(), webhookJob(t, 1)))
+
+	m.endpoints.AssertExpectations(t)
+	require.NotNil(t, m.logged)
+	assert.Equal(t, models.WebhookDeliverySucceeded, m.logged.Status)
+	assert.Equal(t, "[redacted]", m.logged.RequestHeaders[webhook.HeaderSignature])
+	assert.Equal(t, "evt-1", m.logged.RequestHeaders[webhook.HeaderID])
+	m.producer.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhookConsume_FailureSchedulesRetryWithBackoff(t *testing.T) {
+	c, m := newTestWebhookConsumer()
+	m.endpoints.On("FindByID", mock.Anything, int64(9)).Return(activeEndpoint(), nil)
+	m.client.On("Deliver", mock.Anything, mock.Anything).Return(&webhook.Result{StatusCode: 500}, nil)
+	m.endpoints.On("RecordFailure", mock.Anything, int64(9), webhookNow, 15, mock.Anything).Return(false, nil)
+	m.producer.On("Publish", mock.Anything, dto.WebhookDeliveryRoutingKey,
+		mock.MatchedBy(func(job dto.WebhookDeliveryJob) bool { return job.Attempt == 3 }),
+		rabbitmq.PublishOptions{Delay: time.Minute}).Return(nil)
+
+	require.NoError(t, c.Consume(newCtx(), webhookJob(t, 2)))
+
+	m.producer.AssertExpectations(t)
+	assert.Equal(t, models.WebhookDeliveryRetrying, m.logged.Status)
+	require.NotNil(t, m.logged.NextRetryAt)
+	assert.Equal(t, webhookNow.Add(time.Minute), *m.logged.NextRetryAt)
+	assert.Equal(t, "endpoint answered HTTP 500", m.logged.Error)
+}
+
+func TestWebhookConsume_LastAttemptFails(t *testing.T) {
+	c, m := newTestWebhookConsumer()
+	m.endpoints.On("FindByID", mock.Anything, int64(9)).Return(activeEndpoint(), nil)
+	m.client.On("Deliver", mock.Anything, mock.Anything).Return(&webhook.Result{}, errors.New("connection refused"))
+	m.endpoints.On("RecordFailure", mock.Anything, int64(9), webhookNow, 15, mock.Anything).Return(false, nil)
+
+	require.NoError(t, c.Consume(newCtx(), webhookJob(t, 3)))
+
+	m.producer.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, models.WebhookDeliveryFailed, m.logged.Status)
+	assert.Equal(t, "connection refused", m.logged.Error)
+}
+
+func TestWebhookConsume_DisabledAfterThresholdIsNotRetried(t *testing.T) {
+	c, m := newTestWebhookConsumer()
+	m.endpoints.On("FindByID", mock.Anything, int64(9)).Return(activeEndpoint(), nil)
+	m.client.On("Deliver", mock.Anything, mock.Anything).Return(&webhook.Result{StatusCode: 503}, nil)
+	m.endpoints.On("RecordFailure", mock.Anything, int64(9), webhookNow, 15, mock.Anything).Return(true, nil)
+
+	require.NoError(t, c.Consume(newCtx(), webhookJob(t, 1)))
+
+	m.producer.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, models.WebhookDeliveryFailed, m.logged.Status)
+	assert.Contains(t, m.logged.Error, "endpoint disabled")
+}
+
+func TestWebhookConsume_GoneDisablesEndpoint(t *testing.T) {
+	c, m := newTestWebhookConsumer()
+	m.endpoints.On("FindByID", mock.Anything, int64(9)).Return(activeEndpoint(), nil)
+	m.client.On("Deliver", mock.Anything, mock.Anything).Return(&webhook.Result{StatusCode: 410}, nil)
+	m.endpoints.On("Disable", mock.Anything, int64(9), webhookNow, mock.Anything).Return(nil)
+
+	require.NoError(t, c.Consume(newCtx(), webhookJob(t, 1)))
+
+	m.endpoints.AssertExpectations(t)
+	m.endpoints.AssertNotCalled(t, "RecordFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, models.WebhookDeliveryFailed, m.logged.Status)
+}
+
+func TestWebhookConsume_InactiveEndpointDropsJob(t *testing.T) {
+	c, m := newTestWebhookConsumer()
+	endpoint := activeEndpoint()
+	endpoint.IsActive = false
+	m.endpoints.On("FindByID", mock.Anything, int64(9)).Return(endpoint, nil)
+
+	require.NoError(t, c.Consume(newCtx(), webhookJob(t, 1)))
+
+	m.client.AssertNotCalled(t, "Deliver", mock.Anything, mock.Anything)
+	assert.Nil(t, m.logged)
+}
+
+func TestWebhookConsume_FindErrorRequeues(t *testing.T) {
+	c, m := newTestWebhookConsumer()
+	m.endpoints.On("FindByID", mock.Anything, int64(9)).Return(nil, errors.New("db down"))
+
+	assert.Error(t, c.Consume(newCtx(), webhookJob(t, 1)))
+	m.client.AssertNotCalled(t, "Deliver", mock.Anything, mock.Anything)
+}
+
+func TestWebhookConsume_InvalidJSON(t *testing.T) {
+	c, m := newTestWebhookConsumer()
+
+	require.NoError(t, c.Consume(newCtx(), []byte("not-json"))) // permanent discard
+	m.endpoints.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/services"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+)
+
+// AudienceController manages the custom user attributes blast segments match on
+// and previews the reach of segments.
+type AudienceController struct {
+	audienceService *services.AudienceService
+}
+
+func NewAudienceController(audienceService *services.AudienceService) *AudienceController {
+	return &AudienceController{audienceService: audienceService}
+}
+
+// PreviewAudience godoc
+//
+//	@Summary		Preview the audience of a segment
+//	@Description	Count the users a blast with this segment would reach, before its user_exclude_ids. Requires campaigns:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.AudiencePreviewRequest									true	"Segment"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.AudiencePreviewResponse}	"Users matched"
+//	@Failure		400		{object}	response.ErrorResponse										"Invalid segment"
+//	@Failure		401		{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse										"Missing campaigns:manage permission"
+//	@Router			/api/notifications/audience/preview [post]
+func (c *AudienceController) PreviewAudience(eCtx *echo.Context) error {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.AudiencePreviewRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Audience preview request validation failed: %v", err)
+		return err
+	}
+
+	preview, err := c.audienceService.Preview(eCtx.Request().Context(), *authCtx, req.Segment)
+	if err != nil {
+		logger.Errorf("Failed to preview audience: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, preview)
+}
+
+// GetUserAttributes godoc
+//
+//	@Summary		Get the custom attributes of a user
+//	@Description	List the custom attributes blast segments match on. Requires campaigns:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int															true	"User ID"
+//	@Success		200	{object}	response.SuccessResponse{data=dto.UserAttributesResponse}	"Attributes"
+//	@Failure		400	{object}	response.ErrorResponse										"Invalid user ID"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse										"Missing campaigns:manage permission"
+//	@Router			/api/notifications/audience/users/{id}/attributes [get]
+func (c *AudienceController) GetUserAttributes(eCtx *echo.Context) error {
+	authCtx, userID, httpErr := audienceUserParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	attrs, err := c.audienceService.GetAttributes(eCtx.Request().Context(), *authCtx, userID)
+	if err != nil {
+		logger.Errorf("Failed to get attributes of user %d: %v", userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, attrs)
+}
+
+// SetUserAttributes godoc
+//
+//	@Summary		Replace the custom attributes of a user
+//	@Description	Replace all custom attributes of a user, e.g. {"plan":"pro"}; an empty map removes them. Requires campaigns:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int															true	"User ID"
+//	@Param			request	body		dto.SetUserAttributesRequest								true	"Attributes"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.UserAttributesResponse}	"Attributes saved"
+//	@Failure		400		{object}	response.ErrorResponse										"Invalid user ID or attributes"
+//	@Failure		401		{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse										"Missing campaigns:manage permission"
+//	@Router			/api/notifications/audience/users/{id}/attributes [put]
+func (c *AudienceController) SetUserAttributes(eCtx *echo.Context) error {
+	authCtx, userID, httpErr := audienceUserParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.SetUserAttributesRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Set user attributes request validation failed: %v", err)
+		return err
+	}
+
+	attrs, err := c.audienceService.SetAttributes(eCtx.Request().Context(), *authCtx, userID, req)
+	if err != nil {
+		logger.Errorf("Failed to set attributes of user %d: %v", userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, attrs)
+}
+
+// audienceUserParams returns the authenticated caller and the :id path parameter.
+func audienceUserParams(eCtx *echo.Context) (*authenticator.AuthContext, int64, *echo.HTTPError) {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return nil, 0, httpErr
+	}
+	userID, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+	return authCtx, userID, nil
+}
@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/services"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+)
+
+// CampaignController reads back, cancels and retries notification campaigns.
+type CampaignController struct {
+	campaignAdminService *services.CampaignAdminService
+}
+
+func NewCampaignController(campaignAdminService *services.CampaignAdminService) *CampaignController {
+	return &CampaignController{campaignAdminService: campaignAdminService}
+}
+
+// ListCampaigns godoc
+//
+//	@Summary		List notification campaigns
+//	@Description	List the campaigns created by POST /api/notifications/send, newest first, with cursor pagination. Requires campaigns:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			status			query		string											false	"Status: pending, processing, published, failed or cancelled"
+//	@Param			delivery_mode	query		string											false	"Delivery mode: blast or user"
+//	@Param			event_slug		query		string											false	"Event slug"
+//	@Param			created_from	query		string											false	"Created at or after (RFC3339)"
+//	@Param			created_to		query		string											false	"Created before (RFC3339)"
+//	@Param			cursor			query		string											false	"next_cursor of the previous page"
+//	@Param			limit			query		int												false	"Page size (default 20, max 100)"
+//	@Success		200				{object}	response.SuccessResponse{data=dto.CampaignPage}	"Campaigns"
+//	@Failure		400				{object}	response.ErrorResponse							"Validation error or invalid cursor"
+//	@Failure		401				{object}	response.ErrorResponse							"Unauthorized - invalid or missing token"
+//	@Failure		403				{object}	response.ErrorResponse							"Missing campaigns:manage permission"
+//	@Router			/api/notifications/campaigns [get]
+func (c *CampaignController) ListCampaigns(eCtx *echo.Context) error {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.ListCampaignsRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("List campaigns request validation failed: %v", err)
+		return err
+	}
+
+	page, err := c.campaignAdminService.List(eCtx.Request().Context(), *authCtx, req)
+	if err != nil {
+		logger.Errorf("Failed to list campaigns: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, page)
+}
+
+// GetCampaign godoc
+//
+//	@Summary		Get a notification campaign
+//	@Description	Get one campaign with its status. Requires campaigns:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int																true	"Campaign ID"
+//	@Success		200	{object}	response.SuccessResponse{data=models.NotificationCampaign}	"Campaign"
+//	@Failure		400	{object}	response.ErrorResponse										"Invalid campaign ID"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse										"Missing campaigns:manage permission"
+//	@Failure		404	{object}	response.ErrorResponse										"Campaign not found"
+//	@Router			/api/notifications/campaigns/{id} [get]
+func (c *CampaignController) GetCampaign(eCtx *echo.Context) error {
+	authCtx, campaignID, httpErr := campaignParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	campaign, err := c.campaignAdminService.Get(eCtx.Request().Context(), *authCtx, campaignID)
+	if err != nil {
+		logger.Errorf("Failed to get campaign %d: %v", campaignID, err)
+		return err
+	}
+
+	return response.Success(eCtx, campaign)
+}
+
+// CancelCampaign godoc
+//
+//	@Summary		Cancel a scheduled campaign
+//	@Description	Cancel a scheduled or delayed campaign before its delivery time; its queued messages are dropped when they come due. Requires campaigns:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int																true	"Campaign ID"
+//	@Success		200	{object}	response.SuccessResponse{data=models.NotificationCampaign}	"Campaign cancelled"
+//	@Failure		400	{object}	response.ErrorResponse										"Invalid campaign ID"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse										"Missing campaigns:manage permission"
+//	@Failure		404	{object}	response.ErrorResponse										"Campaign not found"
+//	@Failure		409	{object}	response.ErrorResponse										"Campaign already delivered, failed or cancelled"
+//	@Router			/api/notifications/campaigns/{id}/cancel [post]
+func (c *CampaignController) CancelCampaign(eCtx *echo.Context) error {
+	authCtx, campaignID, httpErr := campaignParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	campaign, err := c.campaignAdminService.Cancel(eCtx.Request().Context(), *authCtx, campaignID)
+	if err != nil {
+		logger.Errorf("Failed to cancel campaign %d: %v", campaignID, err)
+		return err
+	}
+
+	return response.Success(eCtx, campaign)
+}
+
+// CampaignStats godoc
+//
+//	@Summary		Get campaign delivery statistics
+//	@Description	Count the delivery attempts of a campaign per channel and status, with send and delivery latency percentiles. Requires campaigns:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int																true	"Campaign ID"
+//	@Success		200	{object}	response.SuccessResponse{data=dto.CampaignStatsResponse}	"Delivery statistics"
+//	@Failure		400	{object}	response.ErrorResponse										"Invalid campaign ID"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse										"Missing campaigns:manage permission"
+//	@Failure		404	{object}	response.ErrorResponse										"Campaign not found"
+//	@Router			/api/notifications/campaigns/{id}/stats [get]
+func (c *CampaignController) CampaignStats(eCtx *echo.Context) error {
+	authCtx, campaignID, httpErr := campaignParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	stats, err := c.campaignAdminService.Stats(eCtx.Request().Context(), *authCtx, campaignID)
+	if err != nil {
+		logger.Errorf("Failed to get statistics of campaign %d: %v", campaignID, err)
+		return err
+	}
+
+	return response.Success(eCtx, stats)
+}
+
+// RetryFailed godoc
+//
+//	@Summary		Retry failed deliveries
+//	@Description	Queue the failed delivery attempts of a campaign again, only for the users and channels that failed. Requires campaigns:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int															true	"Campaign ID"
+//	@Success		200	{object}	response.SuccessResponse{data=dto.RetryFailedResponse}	"Failed deliveries queued"
+//	@Failure		400	{object}	response.ErrorResponse									"Invalid campaign ID"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse									"Missing campaigns:manage permission"
+//	@Failure		404	{object}	response.ErrorResponse									"Campaign not found"
+//	@Failure		409	{object}	response.ErrorResponse									"Campaign was not published"
+//	@Router			/api/notifications/campaigns/{id}/retry-failed [post]
+func (c *CampaignController) RetryFailed(eCtx *echo.Context) error {
+	authCtx, campaignID, httpErr := campaignParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	resp, err := c.campaignAdminService.RetryFailed(eCtx.Request().Context(), *authCtx, campaignID)
+	if err != nil {
+		logger.Errorf("Failed to retry campaign %d: %v", campaignID, err)
+		return err
+	}
+
+	return response.Success(eCtx, resp)
+}
+
+// campaignParams returns the authenticated caller and the :id path parameter.
+func campaignParams(eCtx *echo.Context) (*authenticator.AuthContext, int64, *echo.HTTPError) {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return nil, 0, httpErr
+	}
+	campaignID, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid campaign ID")
+	}
+	return authCtx, campaignID, nil
+}
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/services"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+)
+
+// DeviceController handles push device registration of the authenticated user.
+type DeviceController struct {
+	deviceService *services.DeviceService
+}
+
+func NewDeviceController(deviceService *services.DeviceService) *DeviceController {
+	return &DeviceController{deviceService: deviceService}
+}
+
+// RegisterDevice godoc
+//
+//	@Summary		Register a push device
+//	@Description	Register the FCM token of one of the caller's devices, or refresh its platform, app version and last seen time. A token registered by another user moves to the caller.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.RegisterDeviceRequest								true	"Device"
+//	@Success		200		{object}	response.SuccessResponse{data=models.UserDevice}	"Device registered"
+//	@Failure		400		{object}	response.ErrorResponse									"Validation error"
+//	@Failure		401		{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Router			/api/notifications/devices [post]
+func (c *DeviceController) RegisterDevice(eCtx *echo.Context) error {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.RegisterDeviceRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Register device request validation failed: %v", err)
+		return err
+	}
+
+	device, err := c.deviceService.Register(eCtx.Request().Context(), userID, req)
+	if err != nil {
+		logger.Errorf("Failed to register device of user %d: %v", userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, device)
+}
+
+// ListDevices godoc
+//
+//	@Summary		List push devices
+//	@Description	List the caller's registered devices, most recently seen first.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]models.UserDevice}	"Devices"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Router			/api/notifications/devices [get]
+func (c *DeviceController) ListDevices(eCtx *echo.Context) error {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	devices, err := c.deviceService.List(eCtx.Request().Context(), userID)
+	if err != nil {
+		logger.Errorf("Failed to list devices of user %d: %v", userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, devices)
+}
+
+// UnregisterDevice godoc
+//
+//	@Summary		Unregister a push device
+//	@Description	Delete one of the caller's devices and its topic subscriptions. Call this on sign-out.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int						true	"Device ID"
+//	@Success		200	{object}	response.SuccessResponse	"Device unregistered"
+//	@Failure		400	{object}	response.ErrorResponse	"Invalid device ID"
+//	@Failure		401	{object}	response.ErrorResponse	"Unauthorized - invalid or missing token"
+//	@Failure		404	{object}	response.ErrorResponse	"Device not found"
+//	@Router			/api/notifications/devices/{id} [delete]
+func (c *DeviceController) UnregisterDevice(eCtx *echo.Context) error {
+	userID, deviceID, httpErr := deviceParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	if err := c.deviceService.Unregister(eCtx.Request().Context(), userID, deviceID); err != nil {
+		logger.Errorf("Failed to unregister device %d of user %d: %v", deviceID, userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{"message": "Device unregistered"})
+}
+
+// SubscribeTopic godoc
+//
+//	@Summary		Subscribe a device to a topic
+//	@Description	Subscribe one of the caller's devices to an FCM topic, so notifications sent with a push_topic reach it.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int													true	"Device ID"
+//	@Param			request	body		dto.DeviceTopicRequest								true	"Topic"
+//	@Success		200		{object}	response.SuccessResponse{data=models.UserDevice}	"Device subscribed"
+//	@Failure		400		{object}	response.ErrorResponse								"Invalid device ID or topic"
+//	@Failure		401		{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		404		{object}	response.ErrorResponse								"Device not found"
+//	@Failure		502		{object}	response.ErrorResponse								"FCM refused the subscription"
+//	@Failure		503		{object}	response.ErrorResponse								"Push notifications are not configured"
+//	@Router			/api/notifications/devices/{id}/topics [post]
+func (c *DeviceController) SubscribeTopic(eCtx *echo.Context) error {
+	userID, deviceID, httpErr := deviceParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.DeviceTopicRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Subscribe topic request validation failed: %v", err)
+		return err
+	}
+
+	device, err := c.deviceService.Subscribe(eCtx.Request().Context(), userID, deviceID, req.Topic)
+	if err != nil {
+		logger.Errorf("Failed to subscribe device %d to topic %s: %v", deviceID, req.Topic, err)
+		return err
+	}
+
+	return response.Success(eCtx, device)
+}
+
+// UnsubscribeTopic godoc
+//
+//	@Summary		Unsubscribe a device from a topic
+//	@Description	Remove one of the caller's devices from an FCM topic.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int													true	"Device ID"
+//	@Param			topic	path		string												true	"Topic"
+//	@Success		200		{object}	response.SuccessResponse{data=models.UserDevice}	"Device unsubscribed"
+//	@Failure		400		{object}	response.ErrorResponse								"Invalid device ID or topic"
+//	@Failure		401		{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		404		{object}	response.ErrorResponse								"Device not found"
+//	@Failure		502		{object}	response.ErrorResponse								"FCM refused the request"
+//	@Failure		503		{object}	response.ErrorResponse								"Push notifications are not configured"
+//	@Router			/api/notifications/devices/{id}/topics/{topic} [delete]
+func (c *DeviceController) UnsubscribeTopic(eCtx *echo.Context) error {
+	userID, deviceID, httpErr := deviceParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+	topic := eCtx.Param("topic")
+
+	device, err := c.deviceService.Unsubscribe(eCtx.Request().Context(), userID, deviceID, topic)
+	if err != nil {
+		logger.Errorf("Failed to unsubscribe device %d from topic %s: %v", deviceID, topic, err)
+		return err
+	}
+
+	return response.Success(eCtx, device)
+}
+
+// authUserID returns the authenticated user, or the 401 to answer when there is none.
+func authUserID(eCtx *echo.Context) (int64, *echo.HTTPError) {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return 0, echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+	return int64(authCtx.UserID.ID), nil
+}
+
+// deviceParams returns the authenticated user and the :id path parameter.
+func deviceParams(eCtx *echo.Context) (int64, int64, *echo.HTTPError) {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return 0, 0, httpErr
+	}
+	deviceID, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return 0, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid device ID")
+	}
+	return userID, deviceID, nil
+}
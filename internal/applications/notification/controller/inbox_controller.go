@@ -0,0 +1,291 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v5"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/internal/applications/notification/services"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/realtime"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+)
+
+// InboxController handles the in-app notification inbox of the authenticated user.
+type InboxController struct {
+	inboxService      *services.InboxService
+	heartbeat         time.Duration
+	maxStreamDuration time.Duration
+}
+
+func NewInboxController(inboxService *services.InboxService, cfg realtime.Config) *InboxController {
+	c := &InboxController{
+		inboxService:      inboxService,
+		heartbeat:         cfg.Heartbeat,
+		maxStreamDuration: cfg.MaxStreamDuration,
+	}
+	if c.heartbeat <= 0 {
+		c.heartbeat = 25 * time.Second
+	}
+	if c.maxStreamDuration <= 0 {
+		c.maxStreamDuration = time.Hour
+	}
+	return c
+}
+
+// ListInbox godoc
+//
+//	@Summary		List in-app notifications
+//	@Description	List the caller's notifications, newest first. Without a filter the inbox is listed: everything not archived. Pass the returned next_cursor as cursor to fetch the next page.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			filter	query		string											false	"unread, archived or all"
+//	@Param			cursor	query		string											false	"next_cursor of the previous page"
+//	@Param			limit	query		int												false	"Page size (default 20, max 100)"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.InboxPage}	"Notifications"
+//	@Failure		400		{object}	response.ErrorResponse							"Invalid filter, cursor or limit"
+//	@Failure		401		{object}	response.ErrorResponse							"Unauthorized - invalid or missing token"
+//	@Router			/api/notifications/inbox [get]
+func (c *InboxController) ListInbox(eCtx *echo.Context) error {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.InboxListRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("List inbox request validation failed: %v", err)
+		return err
+	}
+
+	page, err := c.inboxService.List(eCtx.Request().Context(), userID, req)
+	if err != nil {
+		logger.Errorf("Failed to list inbox of user %d: %v", userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, page)
+}
+
+// UnreadCount godoc
+//
+//	@Summary		Count unread notifications
+//	@Description	Count the unread notifications in the caller's inbox. Archived notifications are not counted.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=dto.UnreadCountResponse}	"Unread count"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Router			/api/notifications/inbox/unread-count [get]
+func (c *InboxController) UnreadCount(eCtx *echo.Context) error {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	count, err := c.inboxService.UnreadCount(eCtx.Request().Context(), userID)
+	if err != nil {
+		logger.Errorf("Failed to count unread notifications of user %d: %v", userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, dto.UnreadCountResponse{Unread: count})
+}
+
+// MarkRead godoc
+//
+//	@Summary		Mark a notification read
+//	@Description	Mark one of the caller's notifications as read.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int														true	"Notification ID"
+//	@Success		200	{object}	response.SuccessResponse{data=models.InAppNotification}	"Notification"
+//	@Failure		400	{object}	response.ErrorResponse									"Invalid notification ID"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		404	{object}	response.ErrorResponse									"Notification not found"
+//	@Router			/api/notifications/inbox/{id}/read [post]
+func (c *InboxController) MarkRead(eCtx *echo.Context) error {
+	return c.update(eCtx, "mark read", c.inboxService.MarkRead)
+}
+
+// MarkUnread godoc
+//
+//	@Summary		Mark a notification unread
+//	@Description	Mark one of the caller's notifications as unread again.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int														true	"Notification ID"
+//	@Success		200	{object}	response.SuccessResponse{data=models.InAppNotification}	"Notification"
+//	@Failure		400	{object}	response.ErrorResponse									"Invalid notification ID"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		404	{object}	response.ErrorResponse									"Notification not found"
+//	@Router			/api/notifications/inbox/{id}/unread [post]
+func (c *InboxController) MarkUnread(eCtx *echo.Context) error {
+	return c.update(eCtx, "mark unread", c.inboxService.MarkUnread)
+}
+
+// Archive godoc
+//
+//	@Summary		Archive a notification
+//	@Description	Move one of the caller's notifications out of the inbox. Archived notifications are listed with filter=archived.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int														true	"Notification ID"
+//	@Success		200	{object}	response.SuccessResponse{data=models.InAppNotification}	"Notification"
+//	@Failure		400	{object}	response.ErrorResponse									"Invalid notification ID"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		404	{object}	response.ErrorResponse									"Notification not found"
+//	@Router			/api/notifications/inbox/{id}/archive [post]
+func (c *InboxController) Archive(eCtx *echo.Context) error {
+	return c.update(eCtx, "archive", c.inboxService.Archive)
+}
+
+// MarkAllRead godoc
+//
+//	@Summary		Mark all notifications read
+//	@Description	Mark every unread notification in the caller's inbox as read.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=dto.MarkAllReadResponse}	"Notifications marked read"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Router			/api/notifications/inbox/read-all [post]
+func (c *InboxController) MarkAllRead(eCtx *echo.Context) error {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	updated, err := c.inboxService.MarkAllRead(eCtx.Request().Context(), userID)
+	if err != nil {
+		logger.Errorf("Failed to mark all notifications of user %d read: %v", userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, dto.MarkAllReadResponse{Updated: updated})
+}
+
+// Stream godoc
+//
+//	@Summary		Stream in-app notifications
+//	@Description	Open a Server-Sent Events stream of the caller's inbox. The stream starts with an unread_count event, then sends a notification event (id = notification ID) for every new notification and an unread_count event when the count changes elsewhere. Browsers' EventSource cannot set headers: pass the token in the query string or a cookie, as allowed by auth.jwt.token_lookup. On reconnect, the Last-Event-ID header resends the notifications missed meanwhile. Streams are closed after notification.in_app.max_stream_duration; clients reconnect.
+//	@Tags			Notifications
+//	@Produce		text/event-stream
+//	@Security		BearerAuth
+//	@Param			Last-Event-ID	header		string					false	"ID of the last notification received"
+//	@Success		200				{string}	string					"Event stream"
+//	@Failure		401				{object}	response.ErrorResponse	"Unauthorized - invalid or missing token"
+//	@Failure		429				{object}	response.ErrorResponse	"Too many open streams"
+//	@Failure		503				{object}	response.ErrorResponse	"Realtime notifications are not available"
+//	@Router			/api/notifications/inbox/stream [get]
+func (c *InboxController) Stream(eCtx *echo.Context) error {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	ctx := eCtx.Request().Context()
+	stream, err := c.inboxService.OpenStream(ctx, userID, eCtx.Request().Header.Get("Last-Event-ID"))
+	if err != nil {
+		logger.Errorf("Failed to open inbox stream of user %d: %v", userID, err)
+		return err
+	}
+	defer stream.Close()
+
+	w := eCtx.Response()
+	header := w.Header()
+	header.Set(echo.HeaderContentType, realtime.ContentType)
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // stop nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+
+	flusher := http.NewResponseController(w)
+	send := func(msg realtime.Message) error {
+		if err := realtime.WriteEvent(w, msg); err != nil {
+			return err
+		}
+		return flusher.Flush()
+	}
+
+	if err := send(dto.NewUnreadCountMessage(userID, stream.Unread)); err != nil {
+		return nil
+	}
+	for _, notification := range stream.Missed {
+		msg, err := dto.NewNotificationMessage(notification)
+		if err != nil {
+			continue
+		}
+		if err := send(msg); err != nil {
+			return nil
+		}
+	}
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+	expire := time.NewTimer(c.maxStreamDuration)
+	defer expire.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-expire.C:
+			return nil
+		case msg, ok := <-stream.C:
+			if !ok {
+				return nil // hub shutting down
+			}
+			if err := send(msg); err != nil {
+				return nil // client went away
+			}
+		case <-heartbeat.C:
+			if err := realtime.WriteComment(w, "ping"); err != nil {
+				return nil
+			}
+			if err := flusher.Flush(); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// update runs one of the per-notification inbox changes for the :id path parameter.
+func (c *InboxController) update(
+	eCtx *echo.Context,
+	action string,
+	apply func(ctx context.Context, userID, id int64) (*models.InAppNotification, error),
+) error {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+	id, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid notification ID"))
+	}
+
+	notification, err := apply(eCtx.Request().Context(), userID, id)
+	if err != nil {
+		logger.Errorf("Failed to %s notification %d of user %d: %v", action, id, userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, notification)
+}
@@ -65,6 +65,11 @@ func validateSendRequest(req *dto.SendNotificationRequest) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "user_target_ids is required when delivery_mode=user")
 	}
 
+	// segment narrows blasts only; user deliveries name their users.
+	if req.Segment != nil && req.DeliveryMode != dto.DeliveryModeBlast {
+		return echo.NewHTTPError(http.StatusBadRequest, "segment is only supported when delivery_mode=blast")
+	}
+
 	return nil
 }
 
@@ -80,6 +85,7 @@ func isValidationError(err error) bool {
 	return strings.HasPrefix(s, "channels_not_supported:") ||
 		strings.HasPrefix(s, "scheduled_at") ||
 		strings.HasPrefix(s, "delay_seconds") ||
+		strings.HasPrefix(s, "segment") ||
 		strings.Contains(s, "mutually exclusive") ||
 		strings.Contains(s, "must be in the future")
 }
@@ -2,16 +2,189 @@ package controller
 
 import (
 	"github.com/labstack/echo/v5"
+	rbacConstants "ichi-go/internal/applications/rbac/constants"
 	"ichi-go/pkg/authenticator"
 )
 
 // RegisterRoutes adds notification API routes to the Echo instance.
+// Partners may call them with their API key alone; the key needs the notifications:send scope.
 //
 // Routes:
 //   POST /{serviceName}/api/notifications/send  — create and queue a notification campaign
 func (c *NotificationController) RegisterRoutes(e *echo.Echo, serviceName string, auth *authenticator.Authenticator) {
 	g := e.Group("/" + serviceName + "/api/notifications")
-	g.Use(auth.AuthenticateMiddleware())
+	g.Use(auth.AuthenticateMiddleware(
+		authenticator.WithMethods(authenticator.MethodJWT, authenticator.MethodAPIKey),
+		authenticator.WithScope(rbacConstants.NotificationsSend),
+	))
 
 	g.POST("/send", c.Send)
 }
+
+// RegisterRoutes adds the campaign management routes to the Echo instance.
+// Every route needs campaigns:manage in the platform tenant.
+//
+// Routes:
+//   GET  /{serviceName}/api/notifications/campaigns                    — list campaigns
+//   GET  /{serviceName}/api/notifications/campaigns/:id                — get a campaign
+//   POST /{serviceName}/api/notifications/campaigns/:id/cancel         — cancel a scheduled campaign
+//   GET  /{serviceName}/api/notifications/campaigns/:id/stats          — delivery statistics
+//   POST /{serviceName}/api/notifications/campaigns/:id/retry-failed   — queue failed deliveries again
+func (c *CampaignController) RegisterRoutes(e *echo.Echo, serviceName string, auth *authenticator.Authenticator) {
+	g := e.Group("/" + serviceName + "/api/notifications/campaigns")
+	g.Use(auth.AuthenticateMiddleware())
+
+	g.GET("", c.ListCampaigns)
+	g.GET("/:id", c.GetCampaign)
+	g.POST("/:id/cancel", c.CancelCampaign)
+	g.GET("/:id/stats", c.CampaignStats)
+	g.POST("/:id/retry-failed", c.RetryFailed)
+}
+
+// RegisterRoutes adds the blast audience routes to the Echo instance.
+//
+// Routes:
+//   POST /{serviceName}/api/notifications/audience/preview                 — count the users of a segment
+//   GET  /{serviceName}/api/notifications/audience/users/:id/attributes    — get custom user attributes
+//   PUT  /{serviceName}/api/notifications/audience/users/:id/attributes    — replace custom user attributes
+func (c *AudienceController) RegisterRoutes(e *echo.Echo, serviceName string, auth *authenticator.Authenticator) {
+	g := e.Group("/" + serviceName + "/api/notifications/audience")
+	g.Use(auth.AuthenticateMiddleware())
+
+	g.POST("/preview", c.PreviewAudience)
+	g.GET("/users/:id/attributes", c.GetUserAttributes)
+	g.PUT("/users/:id/attributes", c.SetUserAttributes)
+}
+
+// RegisterRoutes adds the push device API routes to the Echo instance.
+//
+// Routes:
+//   POST   /{serviceName}/api/notifications/devices                     — register or refresh a device
+//   GET    /{serviceName}/api/notifications/devices                     — list the caller's devices
+//   DELETE /{serviceName}/api/notifications/devices/:id                 — unregister a device
+//   POST   /{serviceName}/api/notifications/devices/:id/topics          — subscribe a device to a topic
+//   DELETE /{serviceName}/api/notifications/devices/:id/topics/:topic   — unsubscribe a device from a topic
+func (c *DeviceController) RegisterRoutes(e *echo.Echo, serviceName string, auth *authenticator.Authenticator) {
+	g := e.Group("/" + serviceName + "/api/notifications/devices")
+	g.Use(auth.AuthenticateMiddleware())
+
+	g.POST("", c.RegisterDevice)
+	g.GET("", c.ListDevices)
+	g.DELETE("/:id", c.UnregisterDevice)
+	g.POST("/:id/topics", c.SubscribeTopic)
+	g.DELETE("/:id/topics/:topic", c.UnsubscribeTopic)
+}
+
+// RegisterRoutes adds the SMS delivery-receipt callback to the Echo instance.
+// The route is public: providers authenticate with their request signature.
+//
+// Routes:
+//   POST /{serviceName}/api/notifications/sms/receipts/:provider  — delivery receipt of a provider
+func (c *SMSReceiptController) RegisterRoutes(e *echo.Echo, serviceName string) {
+	g := e.Group("/" + serviceName + "/api/notifications/sms")
+
+	g.POST("/receipts/:provider", c.HandleReceipt)
+}
+
+// RegisterRoutes adds the webhook endpoint API routes to the Echo instance.
+// Partners may manage their endpoints with their API key alone; the key needs the
+// webhooks:manage scope, which WebhookService checks.
+//
+// Routes:
+//   POST   /{serviceName}/api/notifications/webhooks                                          — register an endpoint
+//   GET    /{serviceName}/api/notifications/webhooks                                          — list the tenant's endpoints
+//   GET    /{serviceName}/api/notifications/webhooks/:id                                      — get an endpoint
+//   PATCH  /{serviceName}/api/notifications/webhooks/:id                                      — update, pause or re-enable an endpoint
+//   DELETE /{serviceName}/api/notifications/webhooks/:id                                      — delete an endpoint
+//   POST   /{serviceName}/api/notifications/webhooks/:id/secret                               — rotate the signing secret
+//   GET    /{serviceName}/api/notifications/webhooks/:id/deliveries                           — list delivery attempts
+//   GET    /{serviceName}/api/notifications/webhooks/:id/deliveries/:deliveryId               — get a delivery attempt
+//   POST   /{serviceName}/api/notifications/webhooks/:id/deliveries/:deliveryId/redeliver     — redeliver a payload
+func (c *WebhookController) RegisterRoutes(e *echo.Echo, serviceName string, auth *authenticator.Authenticator) {
+	g := e.Group("/" + serviceName + "/api/notifications/webhooks")
+	g.Use(auth.AuthenticateMiddleware(authenticator.WithMethods(authenticator.MethodJWT, authenticator.MethodAPIKey)))
+
+	g.POST("", c.CreateEndpoint)
+	g.GET("", c.ListEndpoints)
+	g.GET("/:id", c.GetEndpoint)
+	g.PATCH("/:id", c.UpdateEndpoint)
+	g.DELETE("/:id", c.DeleteEndpoint)
+	g.POST("/:id/secret", c.RotateSecret)
+	g.GET("/:id/deliveries", c.ListDeliveries)
+	g.GET("/:id/deliveries/:deliveryId", c.GetDelivery)
+	g.POST("/:id/deliveries/:deliveryId/redeliver", c.Redeliver)
+}
+
+// RegisterRoutes adds the in-app notification inbox routes to the Echo instance.
+// The stream is Server-Sent Events; EventSource clients pass the token as allowed
+// by auth.jwt.token_lookup.
+//
+// Routes:
+//   GET  /{serviceName}/api/notifications/inbox                — list the caller's notifications
+//   GET  /{serviceName}/api/notifications/inbox/unread-count   — count unread notifications
+//   GET  /{serviceName}/api/notifications/inbox/stream         — stream new notifications (SSE)
+//   POST /{serviceName}/api/notifications/inbox/read-all       — mark all notifications read
+//   POST /{serviceName}/api/notifications/inbox/:id/read       — mark a notification read
+//   POST /{serviceName}/api/notifications/inbox/:id/unread     — mark a notification unread
+//   POST /{serviceName}/api/notifications/inbox/:id/archive    — archive a notification
+func (c *InboxController) RegisterRoutes(e *echo.Echo, serviceName string, auth *authenticator.Authenticator) {
+	g := e.Group("/" + serviceName + "/api/notifications/inbox")
+	g.Use(auth.AuthenticateMiddleware())
+
+	g.GET("", c.ListInbox)
+	g.GET("/unread-count", c.UnreadCount)
+	g.GET("/stream", c.Stream)
+	g.POST("/read-all", c.MarkAllRead)
+	g.POST("/:id/read", c.MarkRead)
+	g.POST("/:id/unread", c.MarkUnread)
+	g.POST("/:id/archive", c.Archive)
+}
+
+// RegisterRoutes adds the notification preference and unsubscribe routes to the Echo instance.
+// The unsubscribe routes are public: the signed token of the link identifies the user.
+//
+// Routes:
+//   GET  /{serviceName}/api/notifications/preferences    — get the caller's preferences
+//   PUT  /{serviceName}/api/notifications/preferences    — update the caller's preferences
+//   GET  /{serviceName}/api/notifications/unsubscribe    — describe an unsubscribe link
+//   POST /{serviceName}/api/notifications/unsubscribe    — one-click unsubscribe (RFC 8058)
+func (c *PreferenceController) RegisterRoutes(e *echo.Echo, serviceName string, auth *authenticator.Authenticator) {
+	g := e.Group("/" + serviceName + "/api/notifications/preferences")
+	g.Use(auth.AuthenticateMiddleware())
+
+	g.GET("", c.GetPreferences)
+	g.PUT("", c.UpdatePreferences)
+
+	public := e.Group("/" + serviceName + "/api/notifications/unsubscribe")
+	public.GET("", c.DescribeUnsubscribe)
+	public.POST("", c.Unsubscribe)
+}
+
+// RegisterRoutes adds the notification template override routes to the Echo instance.
+// Listing events only needs a login; the override routes need templates:manage in the platform tenant.
+//
+// Routes:
+//   GET    /{serviceName}/api/notifications/events                                    — list registry events and channels
+//   GET    /{serviceName}/api/notifications/templates                                 — list overrides
+//   POST   /{serviceName}/api/notifications/templates                                 — create an override
+//   POST   /{serviceName}/api/notifications/templates/preview                         — render a preview
+//   GET    /{serviceName}/api/notifications/templates/:id                             — get an override
+//   PUT    /{serviceName}/api/notifications/templates/:id                             — update an override
+//   DELETE /{serviceName}/api/notifications/templates/:id                             — delete an override
+//   GET    /{serviceName}/api/notifications/templates/:id/versions                    — list revisions
+//   POST   /{serviceName}/api/notifications/templates/:id/versions/:revision/rollback — restore a revision
+func (c *TemplateController) RegisterRoutes(e *echo.Echo, serviceName string, auth *authenticator.Authenticator) {
+	e.GET("/"+serviceName+"/api/notifications/events", c.ListEvents, auth.AuthenticateMiddleware())
+
+	g := e.Group("/" + serviceName + "/api/notifications/templates")
+	g.Use(auth.AuthenticateMiddleware())
+
+	g.GET("", c.ListOverrides)
+	g.POST("", c.CreateOverride)
+	g.POST("/preview", c.Preview)
+	g.GET("/:id", c.GetOverride)
+	g.PUT("/:id", c.UpdateOverride)
+	g.DELETE("/:id", c.DeleteOverride)
+	g.GET("/:id/versions", c.ListVersions)
+	g.POST("/:id/versions/:revision/rollback", c.Rollback)
+}
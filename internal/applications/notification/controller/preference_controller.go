@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/services"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+)
+
+// PreferenceController handles notification preferences and one-click unsubscribe links.
+type PreferenceController struct {
+	preferenceService *services.PreferenceService
+}
+
+func NewPreferenceController(preferenceService *services.PreferenceService) *PreferenceController {
+	return &PreferenceController{preferenceService: preferenceService}
+}
+
+// GetPreferences godoc
+//
+//	@Summary		Get notification preferences
+//	@Description	Get the caller's time zone, quiet hours, global marketing unsubscribe and, per event category, which channels are on.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=dto.PreferencesResponse}	"Preferences"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Router			/api/notifications/preferences [get]
+func (c *PreferenceController) GetPreferences(eCtx *echo.Context) error {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	prefs, err := c.preferenceService.Get(eCtx.Request().Context(), userID)
+	if err != nil {
+		logger.Errorf("Failed to get notification preferences of user %d: %v", userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, prefs)
+}
+
+// UpdatePreferences godoc
+//
+//	@Summary		Update notification preferences
+//	@Description	Change the caller's time zone, quiet hours, global marketing unsubscribe or channels per event category. Omitted fields keep their value. Urgent events, such as password resets, are sent whatever the preferences.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.UpdatePreferencesRequest							true	"Changes"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.PreferencesResponse}	"Preferences"
+//	@Failure		400		{object}	response.ErrorResponse									"Validation error, unknown time zone, category or channel"
+//	@Failure		401		{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Router			/api/notifications/preferences [put]
+func (c *PreferenceController) UpdatePreferences(eCtx *echo.Context) error {
+	userID, httpErr := authUserID(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.UpdatePreferencesRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Update notification preferences request validation failed: %v", err)
+		return err
+	}
+
+	prefs, err := c.preferenceService.Update(eCtx.Request().Context(), userID, req)
+	if err != nil {
+		logger.Errorf("Failed to update notification preferences of user %d: %v", userID, err)
+		return err
+	}
+
+	return response.Success(eCtx, prefs)
+}
+
+// DescribeUnsubscribe godoc
+//
+//	@Summary		Describe an unsubscribe link
+//	@Description	Show what a one-click unsubscribe link opts out of, without changing anything, e.g. for a confirmation page. Link scanners open links with GET: only POST unsubscribes.
+//	@Tags			Notifications
+//	@Produce		json
+//	@Param			token	query		string													true	"Token of the unsubscribe link"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.UnsubscribeResponse}	"Opt-out of the link"
+//	@Failure		400		{object}	response.ErrorResponse									"Invalid token"
+//	@Router			/api/notifications/unsubscribe [get]
+func (c *PreferenceController) DescribeUnsubscribe(eCtx *echo.Context) error {
+	resp, err := c.preferenceService.DescribeUnsubscribe(eCtx.Request().Context(), unsubscribeToken(eCtx))
+	if err != nil {
+		logger.Warnf("Invalid unsubscribe link: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, resp)
+}
+
+// Unsubscribe godoc
+//
+//	@Summary		One-click unsubscribe
+//	@Description	Apply the opt-out of an unsubscribe link, without a login: the signed token identifies the user. Mail clients call it for the List-Unsubscribe header with the body List-Unsubscribe=One-Click (RFC 8058). Repeating it is a no-op.
+//	@Tags			Notifications
+//	@Accept			x-www-form-urlencoded
+//	@Produce		json
+//	@Param			token	query		string													true	"Token of the unsubscribe link"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.UnsubscribeResponse}	"Unsubscribed"
+//	@Failure		400		{object}	response.ErrorResponse									"Invalid token"
+//	@Router			/api/notifications/unsubscribe [post]
+func (c *PreferenceController) Unsubscribe(eCtx *echo.Context) error {
+	resp, err := c.preferenceService.Unsubscribe(eCtx.Request().Context(), unsubscribeToken(eCtx))
+	if err != nil {
+		logger.Errorf("Failed to unsubscribe: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, resp)
+}
+
+// unsubscribeToken reads the token of an unsubscribe link from the query string,
+// or from the form of a confirmation page.
+func unsubscribeToken(eCtx *echo.Context) string {
+	if token := eCtx.QueryParam("token"); token != "" {
+		return token
+	}
+	if eCtx.Request().Method == http.MethodPost {
+		return eCtx.FormValue("token")
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+
+	"ichi-go/internal/applications/notification/services"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+)
+
+// maxReceiptBody bounds the size of a delivery-receipt callback.
+const maxReceiptBody = 64 << 10
+
+// SMSReceiptController receives delivery receipts pushed by SMS providers.
+type SMSReceiptController struct {
+	receiptService *services.SMSReceiptService
+}
+
+func NewSMSReceiptController(receiptService *services.SMSReceiptService) *SMSReceiptController {
+	return &SMSReceiptController{receiptService: receiptService}
+}
+
+// HandleReceipt godoc
+//
+//	@Summary		SMS delivery receipt callback
+//	@Description	Called by SMS providers to report the delivery of a message; updates the matching notification log to delivered or failed. Authenticated by the provider signature (X-Twilio-Signature for twilio providers, X-Signature for http providers), not by a bearer token.
+//	@Tags			Notifications
+//	@Accept			json,x-www-form-urlencoded
+//	@Produce		json
+//	@Param			provider	path		string						true	"Provider name from notification.sms.providers"
+//	@Success		200			{object}	response.SuccessResponse	"Receipt recorded"
+//	@Failure		400			{object}	response.ErrorResponse		"Malformed receipt"
+//	@Failure		401			{object}	response.ErrorResponse		"Invalid signature"
+//	@Failure		404			{object}	response.ErrorResponse		"Unknown provider"
+//	@Failure		503			{object}	response.ErrorResponse		"SMS notifications are not configured"
+//	@Router			/api/notifications/sms/receipts/{provider} [post]
+func (c *SMSReceiptController) HandleReceipt(eCtx *echo.Context) error {
+	provider := eCtx.Param("provider")
+
+	body, err := io.ReadAll(io.LimitReader(eCtx.Request().Body, maxReceiptBody))
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest, err)
+	}
+
+	applied, err := c.receiptService.HandleReceipt(eCtx.Request().Context(), provider, eCtx.Request(), body)
+	if err != nil {
+		logger.Errorf("Failed to handle SMS receipt of provider %s: %v", provider, err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]int{"applied": applied})
+}
@@ -0,0 +1,327 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/services"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+)
+
+// TemplateController manages the copy overrides of notification templates.
+type TemplateController struct {
+	templateService *services.TemplateOverrideService
+}
+
+func NewTemplateController(templateService *services.TemplateOverrideService) *TemplateController {
+	return &TemplateController{templateService: templateService}
+}
+
+// ListEvents godoc
+//
+//	@Summary		List notification events
+//	@Description	List the events of the template registry with their channels, preference category and sample data variables.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	response.SuccessResponse{data=[]dto.EventTemplateInfo}	"Events"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Router			/api/notifications/events [get]
+func (c *TemplateController) ListEvents(eCtx *echo.Context) error {
+	if _, httpErr := authContext(eCtx); httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	return response.Success(eCtx, c.templateService.ListEvents())
+}
+
+// ListOverrides godoc
+//
+//	@Summary		List template overrides
+//	@Description	List the copy overrides of notification templates, optionally for one event, channel or locale. Requires templates:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			event_slug	query		string														false	"Event slug"
+//	@Param			channel		query		string														false	"Channel"
+//	@Param			locale		query		string														false	"Locale"
+//	@Success		200			{object}	response.SuccessResponse{data=[]dto.TemplateOverrideResponse}	"Overrides"
+//	@Failure		400			{object}	response.ErrorResponse										"Validation error"
+//	@Failure		401			{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse										"Missing templates:manage permission"
+//	@Router			/api/notifications/templates [get]
+func (c *TemplateController) ListOverrides(eCtx *echo.Context) error {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var query dto.ListTemplateOverridesQuery
+	if err := appValidator.BindAndValidate(eCtx, &query); err != nil {
+		logger.Errorf("List template overrides request validation failed: %v", err)
+		return err
+	}
+
+	overrides, err := c.templateService.ListOverrides(eCtx.Request().Context(), *authCtx, query)
+	if err != nil {
+		logger.Errorf("Failed to list template overrides: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, overrides)
+}
+
+// CreateOverride godoc
+//
+//	@Summary		Create a template override
+//	@Description	Override the copy of an event for one channel and locale without a redeploy. Templates use Go text/template syntax and must render with the sample data of the event. Requires templates:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateTemplateOverrideRequest							true	"Override"
+//	@Success		201		{object}	response.SuccessResponse{data=dto.TemplateOverrideResponse}	"Override created"
+//	@Failure		400		{object}	response.ErrorResponse										"Validation error, unknown event or invalid template"
+//	@Failure		401		{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse										"Missing templates:manage permission"
+//	@Failure		409		{object}	response.ErrorResponse										"An override of the event, channel and locale exists"
+//	@Router			/api/notifications/templates [post]
+func (c *TemplateController) CreateOverride(eCtx *echo.Context) error {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.CreateTemplateOverrideRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Create template override request validation failed: %v", err)
+		return err
+	}
+
+	override, err := c.templateService.CreateOverride(eCtx.Request().Context(), *authCtx, req)
+	if err != nil {
+		logger.Errorf("Failed to create template override: %v", err)
+		return err
+	}
+
+	return response.Created(eCtx, override)
+}
+
+// GetOverride godoc
+//
+//	@Summary		Get a template override
+//	@Description	Get one copy override. Requires templates:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int															true	"Override ID"
+//	@Success		200	{object}	response.SuccessResponse{data=dto.TemplateOverrideResponse}	"Override"
+//	@Failure		400	{object}	response.ErrorResponse										"Invalid override ID"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse										"Missing templates:manage permission"
+//	@Failure		404	{object}	response.ErrorResponse										"Override not found"
+//	@Router			/api/notifications/templates/{id} [get]
+func (c *TemplateController) GetOverride(eCtx *echo.Context) error {
+	authCtx, overrideID, httpErr := overrideParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	override, err := c.templateService.GetOverride(eCtx.Request().Context(), *authCtx, overrideID)
+	if err != nil {
+		logger.Errorf("Failed to get template override %d: %v", overrideID, err)
+		return err
+	}
+
+	return response.Success(eCtx, override)
+}
+
+// UpdateOverride godoc
+//
+//	@Summary		Update a template override
+//	@Description	Change the copy of an override, or turn it on or off, as a new revision. Pass the revision it was read at to fail instead of overwriting a concurrent change. Requires templates:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int															true	"Override ID"
+//	@Param			request	body		dto.UpdateTemplateOverrideRequest							true	"Changes"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.TemplateOverrideResponse}	"Override updated"
+//	@Failure		400		{object}	response.ErrorResponse										"Validation error or invalid template"
+//	@Failure		401		{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse										"Missing templates:manage permission"
+//	@Failure		404		{object}	response.ErrorResponse										"Override not found"
+//	@Failure		409		{object}	response.ErrorResponse										"The override changed since the given revision"
+//	@Router			/api/notifications/templates/{id} [put]
+func (c *TemplateController) UpdateOverride(eCtx *echo.Context) error {
+	authCtx, overrideID, httpErr := overrideParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.UpdateTemplateOverrideRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Update template override request validation failed: %v", err)
+		return err
+	}
+
+	override, err := c.templateService.UpdateOverride(eCtx.Request().Context(), *authCtx, overrideID, req)
+	if err != nil {
+		logger.Errorf("Failed to update template override %d: %v", overrideID, err)
+		return err
+	}
+
+	return response.Success(eCtx, override)
+}
+
+// DeleteOverride godoc
+//
+//	@Summary		Delete a template override
+//	@Description	Delete an override; the event is sent with its default copy again. Requires templates:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int							true	"Override ID"
+//	@Success		200	{object}	response.SuccessResponse	"Override deleted"
+//	@Failure		400	{object}	response.ErrorResponse		"Invalid override ID"
+//	@Failure		401	{object}	response.ErrorResponse		"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse		"Missing templates:manage permission"
+//	@Failure		404	{object}	response.ErrorResponse		"Override not found"
+//	@Router			/api/notifications/templates/{id} [delete]
+func (c *TemplateController) DeleteOverride(eCtx *echo.Context) error {
+	authCtx, overrideID, httpErr := overrideParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	if err := c.templateService.DeleteOverride(eCtx.Request().Context(), *authCtx, overrideID); err != nil {
+		logger.Errorf("Failed to delete template override %d: %v", overrideID, err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{"message": "Template override deleted"})
+}
+
+// ListVersions godoc
+//
+//	@Summary		List template override revisions
+//	@Description	List the revisions of an override, newest first, with the copy and state of each. Requires templates:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int																			true	"Override ID"
+//	@Success		200	{object}	response.SuccessResponse{data=[]models.NotificationTemplateOverrideVersion}	"Revisions"
+//	@Failure		400	{object}	response.ErrorResponse														"Invalid override ID"
+//	@Failure		401	{object}	response.ErrorResponse														"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse														"Missing templates:manage permission"
+//	@Failure		404	{object}	response.ErrorResponse														"Override not found"
+//	@Router			/api/notifications/templates/{id}/versions [get]
+func (c *TemplateController) ListVersions(eCtx *echo.Context) error {
+	authCtx, overrideID, httpErr := overrideParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	versions, err := c.templateService.ListVersions(eCtx.Request().Context(), *authCtx, overrideID)
+	if err != nil {
+		logger.Errorf("Failed to list revisions of template override %d: %v", overrideID, err)
+		return err
+	}
+
+	return response.Success(eCtx, versions)
+}
+
+// Rollback godoc
+//
+//	@Summary		Roll back a template override
+//	@Description	Restore the copy and state of an earlier revision of an override, as a new revision. Requires templates:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int															true	"Override ID"
+//	@Param			revision	path		int															true	"Revision to restore"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.TemplateOverrideResponse}	"Override rolled back"
+//	@Failure		400			{object}	response.ErrorResponse										"Invalid ID, or the revision no longer renders"
+//	@Failure		401			{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse										"Missing templates:manage permission"
+//	@Failure		404			{object}	response.ErrorResponse										"Override or revision not found"
+//	@Failure		409			{object}	response.ErrorResponse										"The override changed meanwhile"
+//	@Router			/api/notifications/templates/{id}/versions/{revision}/rollback [post]
+func (c *TemplateController) Rollback(eCtx *echo.Context) error {
+	authCtx, overrideID, httpErr := overrideParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+	revision, err := strconv.Atoi(eCtx.Param("revision"))
+	if err != nil || revision < 1 {
+		return response.Error(eCtx, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid revision"))
+	}
+
+	override, err := c.templateService.Rollback(eCtx.Request().Context(), *authCtx, overrideID, revision)
+	if err != nil {
+		logger.Errorf("Failed to roll back template override %d to revision %d: %v", overrideID, revision, err)
+		return err
+	}
+
+	return response.Success(eCtx, override)
+}
+
+// Preview godoc
+//
+//	@Summary		Preview a notification template
+//	@Description	Render the default copy, the active override or a draft of an event for one channel and locale, with its sample data merged with the given data. Nothing is saved. Requires templates:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		dto.PreviewTemplateRequest								true	"Preview"
+//	@Success		200		{object}	response.SuccessResponse{data=dto.PreviewTemplateResponse}	"Rendered title and body"
+//	@Failure		400		{object}	response.ErrorResponse									"Validation error, unknown event or invalid template"
+//	@Failure		401		{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse									"Missing templates:manage permission"
+//	@Router			/api/notifications/templates/preview [post]
+func (c *TemplateController) Preview(eCtx *echo.Context) error {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.PreviewTemplateRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Preview template request validation failed: %v", err)
+		return err
+	}
+
+	preview, err := c.templateService.Preview(eCtx.Request().Context(), *authCtx, req)
+	if err != nil {
+		logger.Errorf("Failed to preview template of %s/%s: %v", req.EventSlug, req.Channel, err)
+		return err
+	}
+
+	return response.Success(eCtx, preview)
+}
+
+// overrideParams returns the authenticated caller and the :id path parameter.
+func overrideParams(eCtx *echo.Context) (*authenticator.AuthContext, int64, *echo.HTTPError) {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return nil, 0, httpErr
+	}
+	overrideID, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid override ID")
+	}
+	return authCtx, overrideID, nil
+}
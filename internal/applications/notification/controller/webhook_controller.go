@@ -0,0 +1,356 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/services"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
+)
+
+// WebhookController manages the webhook endpoints of the caller's tenant.
+type WebhookController struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookController(webhookService *services.WebhookService) *WebhookController {
+	return &WebhookController{webhookService: webhookService}
+}
+
+// CreateEndpoint godoc
+//
+//	@Summary		Register a webhook endpoint
+//	@Description	Register a URL that receives a signed POST for every event of the tenant matching one of its event filters. The signing secret is returned only in this response. Requires webhooks:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-Tenant-Id	header		string																	false	"Tenant; defaults to the API key's tenant or the default tenant"
+//	@Param			request		body		dto.CreateWebhookEndpointRequest										true	"Endpoint"
+//	@Success		201			{object}	response.SuccessResponse{data=dto.WebhookEndpointSecretResponse}	"Endpoint registered"
+//	@Failure		400			{object}	response.ErrorResponse													"Validation error"
+//	@Failure		401			{object}	response.ErrorResponse													"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse													"Missing webhooks:manage permission"
+//	@Router			/api/notifications/webhooks [post]
+func (c *WebhookController) CreateEndpoint(eCtx *echo.Context) error {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.CreateWebhookEndpointRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Create webhook endpoint request validation failed: %v", err)
+		return err
+	}
+
+	endpoint, err := c.webhookService.CreateEndpoint(eCtx.Request().Context(), *authCtx, req)
+	if err != nil {
+		logger.Errorf("Failed to create webhook endpoint: %v", err)
+		return err
+	}
+
+	return response.Created(eCtx, endpoint)
+}
+
+// ListEndpoints godoc
+//
+//	@Summary		List webhook endpoints
+//	@Description	List the webhook endpoints of the tenant, including disabled ones. Requires webhooks:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-Tenant-Id	header		string													false	"Tenant"
+//	@Success		200			{object}	response.SuccessResponse{data=[]models.WebhookEndpoint}	"Endpoints"
+//	@Failure		401			{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse									"Missing webhooks:manage permission"
+//	@Router			/api/notifications/webhooks [get]
+func (c *WebhookController) ListEndpoints(eCtx *echo.Context) error {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	endpoints, err := c.webhookService.ListEndpoints(eCtx.Request().Context(), *authCtx)
+	if err != nil {
+		logger.Errorf("Failed to list webhook endpoints: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, endpoints)
+}
+
+// GetEndpoint godoc
+//
+//	@Summary		Get a webhook endpoint
+//	@Description	Get one webhook endpoint of the tenant, with its failure count and why it was disabled. Requires webhooks:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-Tenant-Id	header		string												false	"Tenant"
+//	@Param			id			path		int													true	"Endpoint ID"
+//	@Success		200			{object}	response.SuccessResponse{data=models.WebhookEndpoint}	"Endpoint"
+//	@Failure		400			{object}	response.ErrorResponse								"Invalid endpoint ID"
+//	@Failure		401			{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse								"Missing webhooks:manage permission"
+//	@Failure		404			{object}	response.ErrorResponse								"Endpoint not found"
+//	@Router			/api/notifications/webhooks/{id} [get]
+func (c *WebhookController) GetEndpoint(eCtx *echo.Context) error {
+	authCtx, endpointID, httpErr := endpointParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	endpoint, err := c.webhookService.GetEndpoint(eCtx.Request().Context(), *authCtx, endpointID)
+	if err != nil {
+		logger.Errorf("Failed to get webhook endpoint %d: %v", endpointID, err)
+		return err
+	}
+
+	return response.Success(eCtx, endpoint)
+}
+
+// UpdateEndpoint godoc
+//
+//	@Summary		Update a webhook endpoint
+//	@Description	Change the URL, description or event filters of an endpoint, pause it, or re-enable it after it was disabled for failing. Requires webhooks:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-Tenant-Id	header		string												false	"Tenant"
+//	@Param			id			path		int													true	"Endpoint ID"
+//	@Param			request		body		dto.UpdateWebhookEndpointRequest					true	"Changes"
+//	@Success		200			{object}	response.SuccessResponse{data=models.WebhookEndpoint}	"Endpoint updated"
+//	@Failure		400			{object}	response.ErrorResponse								"Validation error"
+//	@Failure		401			{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse								"Missing webhooks:manage permission"
+//	@Failure		404			{object}	response.ErrorResponse								"Endpoint not found"
+//	@Router			/api/notifications/webhooks/{id} [patch]
+func (c *WebhookController) UpdateEndpoint(eCtx *echo.Context) error {
+	authCtx, endpointID, httpErr := endpointParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	var req dto.UpdateWebhookEndpointRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Update webhook endpoint request validation failed: %v", err)
+		return err
+	}
+
+	endpoint, err := c.webhookService.UpdateEndpoint(eCtx.Request().Context(), *authCtx, endpointID, req)
+	if err != nil {
+		logger.Errorf("Failed to update webhook endpoint %d: %v", endpointID, err)
+		return err
+	}
+
+	return response.Success(eCtx, endpoint)
+}
+
+// DeleteEndpoint godoc
+//
+//	@Summary		Delete a webhook endpoint
+//	@Description	Delete an endpoint and its delivery log. Pending retries are dropped. Requires webhooks:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-Tenant-Id	header		string						false	"Tenant"
+//	@Param			id			path		int							true	"Endpoint ID"
+//	@Success		200			{object}	response.SuccessResponse	"Endpoint deleted"
+//	@Failure		400			{object}	response.ErrorResponse		"Invalid endpoint ID"
+//	@Failure		401			{object}	response.ErrorResponse		"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse		"Missing webhooks:manage permission"
+//	@Failure		404			{object}	response.ErrorResponse		"Endpoint not found"
+//	@Router			/api/notifications/webhooks/{id} [delete]
+func (c *WebhookController) DeleteEndpoint(eCtx *echo.Context) error {
+	authCtx, endpointID, httpErr := endpointParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	if err := c.webhookService.DeleteEndpoint(eCtx.Request().Context(), *authCtx, endpointID); err != nil {
+		logger.Errorf("Failed to delete webhook endpoint %d: %v", endpointID, err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{"message": "Webhook endpoint deleted"})
+}
+
+// RotateSecret godoc
+//
+//	@Summary		Rotate a webhook signing secret
+//	@Description	Replace the signing secret of an endpoint. The new secret is returned only in this response and signs every delivery from now on, including pending retries. Requires webhooks:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-Tenant-Id	header		string																	false	"Tenant"
+//	@Param			id			path		int																		true	"Endpoint ID"
+//	@Success		200			{object}	response.SuccessResponse{data=dto.WebhookEndpointSecretResponse}	"Secret rotated"
+//	@Failure		400			{object}	response.ErrorResponse													"Invalid endpoint ID"
+//	@Failure		401			{object}	response.ErrorResponse													"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse													"Missing webhooks:manage permission"
+//	@Failure		404			{object}	response.ErrorResponse													"Endpoint not found"
+//	@Router			/api/notifications/webhooks/{id}/secret [post]
+func (c *WebhookController) RotateSecret(eCtx *echo.Context) error {
+	authCtx, endpointID, httpErr := endpointParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	endpoint, err := c.webhookService.RotateSecret(eCtx.Request().Context(), *authCtx, endpointID)
+	if err != nil {
+		logger.Errorf("Failed to rotate secret of webhook endpoint %d: %v", endpointID, err)
+		return err
+	}
+
+	return response.Success(eCtx, endpoint)
+}
+
+// ListDeliveries godoc
+//
+//	@Summary		List webhook deliveries
+//	@Description	List the latest delivery attempts of an endpoint, newest first, with request and response snapshots. Requires webhooks:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-Tenant-Id	header		string													false	"Tenant"
+//	@Param			id			path		int														true	"Endpoint ID"
+//	@Param			limit		query		int														false	"Maximum number of deliveries (default 50, max 100)"
+//	@Success		200			{object}	response.SuccessResponse{data=[]models.WebhookDelivery}	"Deliveries"
+//	@Failure		400			{object}	response.ErrorResponse									"Invalid endpoint ID or limit"
+//	@Failure		401			{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse									"Missing webhooks:manage permission"
+//	@Failure		404			{object}	response.ErrorResponse									"Endpoint not found"
+//	@Router			/api/notifications/webhooks/{id}/deliveries [get]
+func (c *WebhookController) ListDeliveries(eCtx *echo.Context) error {
+	authCtx, endpointID, httpErr := endpointParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+	limit := 0
+	if raw := eCtx.QueryParam("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 {
+			return response.Error(eCtx, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid limit"))
+		}
+	}
+
+	deliveries, err := c.webhookService.ListDeliveries(eCtx.Request().Context(), *authCtx, endpointID, limit)
+	if err != nil {
+		logger.Errorf("Failed to list deliveries of webhook endpoint %d: %v", endpointID, err)
+		return err
+	}
+
+	return response.Success(eCtx, deliveries)
+}
+
+// GetDelivery godoc
+//
+//	@Summary		Get a webhook delivery
+//	@Description	Get one delivery attempt of an endpoint with its request and response snapshots. Requires webhooks:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-Tenant-Id	header		string												false	"Tenant"
+//	@Param			id			path		int													true	"Endpoint ID"
+//	@Param			deliveryId	path		int													true	"Delivery ID"
+//	@Success		200			{object}	response.SuccessResponse{data=models.WebhookDelivery}	"Delivery"
+//	@Failure		400			{object}	response.ErrorResponse								"Invalid endpoint or delivery ID"
+//	@Failure		401			{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse								"Missing webhooks:manage permission"
+//	@Failure		404			{object}	response.ErrorResponse								"Endpoint or delivery not found"
+//	@Router			/api/notifications/webhooks/{id}/deliveries/{deliveryId} [get]
+func (c *WebhookController) GetDelivery(eCtx *echo.Context) error {
+	authCtx, endpointID, deliveryID, httpErr := deliveryParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	delivery, err := c.webhookService.GetDelivery(eCtx.Request().Context(), *authCtx, endpointID, deliveryID)
+	if err != nil {
+		logger.Errorf("Failed to get webhook delivery %d: %v", deliveryID, err)
+		return err
+	}
+
+	return response.Success(eCtx, delivery)
+}
+
+// Redeliver godoc
+//
+//	@Summary		Redeliver a webhook
+//	@Description	Queue the payload of a past delivery to its endpoint again, e.g. after the receiver fixed an outage. The redelivery is logged as a new delivery with a fresh retry budget. Requires webhooks:manage.
+//	@Tags			Notifications
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-Tenant-Id	header		string						false	"Tenant"
+//	@Param			id			path		int							true	"Endpoint ID"
+//	@Param			deliveryId	path		int							true	"Delivery ID"
+//	@Success		202			{object}	response.SuccessResponse	"Redelivery queued"
+//	@Failure		400			{object}	response.ErrorResponse		"Invalid IDs, or the endpoint is disabled"
+//	@Failure		401			{object}	response.ErrorResponse		"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse		"Missing webhooks:manage permission"
+//	@Failure		404			{object}	response.ErrorResponse		"Endpoint or delivery not found"
+//	@Failure		503			{object}	response.ErrorResponse		"Webhook deliveries are not available"
+//	@Router			/api/notifications/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (c *WebhookController) Redeliver(eCtx *echo.Context) error {
+	authCtx, endpointID, deliveryID, httpErr := deliveryParams(eCtx)
+	if httpErr != nil {
+		return response.Error(eCtx, httpErr.Code, httpErr)
+	}
+
+	if err := c.webhookService.Redeliver(eCtx.Request().Context(), *authCtx, endpointID, deliveryID); err != nil {
+		logger.Errorf("Failed to redeliver webhook delivery %d: %v", deliveryID, err)
+		return err
+	}
+
+	return response.Accepted(eCtx, map[string]string{"message": "Redelivery queued"})
+}
+
+// authContext returns the authenticated caller, or the 401 to answer when there is none.
+func authContext(eCtx *echo.Context) (*authenticator.AuthContext, *echo.HTTPError) {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+	return authCtx, nil
+}
+
+// endpointParams returns the authenticated caller and the :id path parameter.
+func endpointParams(eCtx *echo.Context) (*authenticator.AuthContext, int64, *echo.HTTPError) {
+	authCtx, httpErr := authContext(eCtx)
+	if httpErr != nil {
+		return nil, 0, httpErr
+	}
+	endpointID, err := strconv.ParseInt(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid endpoint ID")
+	}
+	return authCtx, endpointID, nil
+}
+
+// deliveryParams returns the authenticated caller and the :id and :deliveryId path parameters.
+func deliveryParams(eCtx *echo.Context) (*authenticator.AuthContext, int64, int64, *echo.HTTPError) {
+	authCtx, endpointID, httpErr := endpointParams(eCtx)
+	if httpErr != nil {
+		return nil, 0, 0, httpErr
+	}
+	deliveryID, err := strconv.ParseInt(eCtx.Param("deliveryId"), 10, 64)
+	if err != nil {
+		return nil, 0, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid delivery ID")
+	}
+	return authCtx, endpointID, deliveryID, nil
+}
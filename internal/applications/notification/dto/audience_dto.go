@@ -0,0 +1,27 @@
+package dto
+
+import "ichi-go/internal/applications/notification/models"
+
+// AudiencePreviewRequest is the body of POST /api/notifications/audience/preview.
+type AudiencePreviewRequest struct {
+	// Segment is the segment of a blast; empty matches every user.
+	Segment models.AudienceSegment `json:"segment"`
+}
+
+// AudiencePreviewResponse reports how many users a blast with the segment would reach,
+// before its user_exclude_ids are applied.
+type AudiencePreviewResponse struct {
+	Users int `json:"users" example:"1280"`
+}
+
+// SetUserAttributesRequest is the body of PUT /api/notifications/audience/users/{id}/attributes.
+type SetUserAttributesRequest struct {
+	// Attributes replace all custom attributes of the user; an empty map removes them.
+	Attributes map[string]string `json:"attributes" example:"plan:pro,country:ID"`
+}
+
+// UserAttributesResponse lists the custom attributes of a user.
+type UserAttributesResponse struct {
+	UserID     int64             `json:"user_id" example:"42"`
+	Attributes map[string]string `json:"attributes"`
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// ListCampaignsRequest filters and pages GET /api/notifications/campaigns.
+type ListCampaignsRequest struct {
+	Status       string `query:"status"        validate:"omitempty,oneof=pending processing published failed cancelled"`
+	DeliveryMode string `query:"delivery_mode" validate:"omitempty,oneof=blast user"`
+	EventSlug    string `query:"event_slug"    validate:"omitempty,max=100"`
+	// CreatedFrom and CreatedTo bound the creation time (RFC3339); CreatedTo is exclusive.
+	CreatedFrom *time.Time `query:"created_from"`
+	CreatedTo   *time.Time `query:"created_to"`
+	// Cursor is the next_cursor of the previous page; empty for the first page.
+	Cursor string `query:"cursor" validate:"omitempty,max=20"`
+	// Limit is the page size (default: 20, max: 100).
+	Limit int `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// CampaignPage is one page of campaigns, newest first.
+type CampaignPage struct {
+	Items []*models.NotificationCampaign `json:"items"`
+	// NextCursor fetches the next (older) page; empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty" example:"1042"`
+}
+
+// LogStatusCounts counts the delivery attempts of a campaign by status.
+// Sent includes the attempts later confirmed Delivered by a provider receipt.
+type LogStatusCounts struct {
+	Pending   int64 `json:"pending"`
+	Sent      int64 `json:"sent"`
+	Delivered int64 `json:"delivered"`
+	Failed    int64 `json:"failed"`
+	Skipped   int64 `json:"skipped"`
+	// Retried counts failed attempts queued again; each retry has its own attempt.
+	Retried int64 `json:"retried"`
+}
+
+// LatencyStats are percentiles of a delivery latency, in milliseconds.
+type LatencyStats struct {
+	Count int   `json:"count"`
+	P50Ms int64 `json:"p50_ms"`
+	P90Ms int64 `json:"p90_ms"`
+	P99Ms int64 `json:"p99_ms"`
+	MaxMs int64 `json:"max_ms"`
+}
+
+// ChannelStats are the delivery statistics of a campaign on one channel.
+type ChannelStats struct {
+	Channel string          `json:"channel" example:"email"`
+	Counts  LogStatusCounts `json:"counts"`
+	// SendLatency is the time from the campaign's delivery time to each send.
+	SendLatency *LatencyStats `json:"send_latency,omitempty"`
+	// DeliveryLatency is the time from each send to its provider delivery receipt.
+	DeliveryLatency *LatencyStats `json:"delivery_latency,omitempty"`
+}
+
+// CampaignStatsResponse is the delivery report of GET /api/notifications/campaigns/{id}/stats.
+type CampaignStatsResponse struct {
+	CampaignID int64                 `json:"campaign_id"`
+	Status     models.CampaignStatus `json:"status"`
+	DeliverAt  time.Time             `json:"deliver_at"`
+	Totals     LogStatusCounts       `json:"totals"`
+	Channels   []ChannelStats        `json:"channels"`
+}
+
+// RetryFailedResponse reports what POST /api/notifications/campaigns/{id}/retry-failed queued.
+type RetryFailedResponse struct {
+	CampaignID int64 `json:"campaign_id"`
+	// Retried is the number of failed attempts queued again.
+	Retried int `json:"retried" example:"12"`
+	// Messages is the number of messages published: one per user, or one for a blast.
+	Messages int `json:"messages" example:"7"`
+}
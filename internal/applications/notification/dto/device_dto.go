@@ -0,0 +1,19 @@
+package dto
+
+// RegisterDeviceRequest is the API request body for POST /api/notifications/devices.
+type RegisterDeviceRequest struct {
+	// Platform is the operating system the token was issued for: android, ios or web.
+	Platform string `json:"platform" validate:"required,oneof=android ios web"`
+
+	// AppVersion is the version of the client app, for support and targeting.
+	AppVersion string `json:"app_version,omitempty" validate:"omitempty,max=50"`
+
+	// Token is the FCM registration token of the device.
+	Token string `json:"token" validate:"required,max=255"`
+}
+
+// DeviceTopicRequest is the API request body for POST /api/notifications/devices/{id}/topics.
+type DeviceTopicRequest struct {
+	// Topic is the FCM topic name without the "/topics/" prefix, e.g. "announcements".
+	Topic string `json:"topic" validate:"required,max=900"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/pkg/notification/realtime"
+)
+
+// Events sent on the inbox stream (GET /api/notifications/inbox/stream).
+const (
+	// InboxEventNotification carries a new models.InAppNotification; its SSE id is the
+	// notification ID, so a reconnecting client receives what it missed.
+	InboxEventNotification = "notification"
+	// InboxEventUnreadCount carries an UnreadCountResponse whenever the count changes
+	// other than by a new notification, e.g. when another device marks one read.
+	InboxEventUnreadCount = "unread_count"
+)
+
+// InboxListRequest filters and pages GET /api/notifications/inbox.
+type InboxListRequest struct {
+	// Filter is empty for the inbox (not archived), or unread, archived or all.
+	Filter string `query:"filter" validate:"omitempty,oneof=unread archived all"`
+	// Cursor is the next_cursor of the previous page; empty for the first page.
+	Cursor string `query:"cursor" validate:"omitempty,max=20"`
+	// Limit is the page size (default: 20, max: 100).
+	Limit int `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// InboxPage is one page of the inbox, newest first.
+type InboxPage struct {
+	Items []*models.InAppNotification `json:"items"`
+	// NextCursor fetches the next (older) page; empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty" example:"1042"`
+}
+
+// UnreadCountResponse is the number of unread notifications in the inbox.
+type UnreadCountResponse struct {
+	Unread int `json:"unread" example:"3"`
+}
+
+// MarkAllReadResponse reports how many notifications POST /api/notifications/inbox/read-all marked read.
+type MarkAllReadResponse struct {
+	Updated int64 `json:"updated" example:"3"`
+}
+
+// NewNotificationMessage builds the InboxEventNotification stream event of notification.
+func NewNotificationMessage(notification *models.InAppNotification) (realtime.Message, error) {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return realtime.Message{}, err
+	}
+	return realtime.Message{
+		UserID: notification.UserID,
+		Event:  InboxEventNotification,
+		ID:     strconv.FormatInt(notification.ID, 10),
+		Data:   data,
+	}, nil
+}
+
+// NewUnreadCountMessage builds the InboxEventUnreadCount stream event of a user.
+func NewUnreadCountMessage(userID int64, unread int) realtime.Message {
+	data, _ := json.Marshal(UnreadCountResponse{Unread: unread})
+	return realtime.Message{UserID: userID, Event: InboxEventUnreadCount, Data: data}
+}
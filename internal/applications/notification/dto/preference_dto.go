@@ -0,0 +1,65 @@
+package dto
+
+// QuietHours is a daily window, in the user's time zone, during which non-urgent
+// push and SMS notifications wait. A start after end wraps midnight.
+type QuietHours struct {
+	Start string `json:"start" validate:"omitempty,datetime=15:04" example:"22:00"`
+	End   string `json:"end"   validate:"omitempty,datetime=15:04" example:"07:00"`
+}
+
+// CategoryPreference lists the channels of one event category and whether each is on.
+type CategoryPreference struct {
+	Category string `json:"category" example:"orders"`
+	// Kind is transactional or marketing; unsubscribed_all only stops marketing.
+	Kind string `json:"kind" example:"transactional"`
+	// Required categories only hold urgent events, such as password resets,
+	// which are sent whatever the preferences.
+	Required bool             `json:"required"`
+	Channels map[Channel]bool `json:"channels"`
+}
+
+// PreferencesResponse is the notification preferences of the authenticated user.
+type PreferencesResponse struct {
+	Timezone string `json:"timezone" example:"Asia/Jakarta"`
+	// Locale is the language notifications are written in; empty uses the
+	// language each notification was sent with.
+	Locale string `json:"locale" example:"id-ID"`
+	// QuietHours is null when quiet hours are off.
+	QuietHours *QuietHours `json:"quiet_hours"`
+	// UnsubscribedAll stops every marketing notification.
+	UnsubscribedAll bool                 `json:"unsubscribed_all"`
+	Categories      []CategoryPreference `json:"categories"`
+}
+
+// ChannelPreference turns one channel of an event category on or off.
+type ChannelPreference struct {
+	Category string  `json:"category" validate:"required,max=50"                     example:"orders"`
+	Channel  Channel `json:"channel"  validate:"required,oneof=email push sms in_app" example:"push"`
+	Enabled  bool    `json:"enabled"`
+}
+
+// UpdatePreferencesRequest is the body of PUT /api/notifications/preferences.
+// Omitted fields keep their current value.
+type UpdatePreferencesRequest struct {
+	// Timezone is an IANA time zone name; quiet hours are read on its wall clock.
+	Timezone *string `json:"timezone,omitempty" validate:"omitempty,max=64" example:"Asia/Jakarta"`
+	// Locale is a BCP-47 language tag; copy missing in it falls back to its
+	// language, then English ("id-ID" → "id" → "en"). Empty clears it.
+	Locale *string `json:"locale,omitempty" validate:"omitempty,max=35" example:"id-ID"`
+	// QuietHours sets quiet hours; empty start and end turn them off.
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+	// UnsubscribedAll stops (true) or resumes (false) every marketing notification.
+	UnsubscribedAll *bool               `json:"unsubscribed_all,omitempty"`
+	Channels        []ChannelPreference `json:"channels,omitempty" validate:"omitempty,max=100,dive"`
+}
+
+// UnsubscribeResponse describes what a one-click unsubscribe link opts out of.
+type UnsubscribeResponse struct {
+	// All is true when the link stops every marketing notification.
+	All      bool   `json:"all"`
+	Category string `json:"category,omitempty" example:"promotions"`
+	// Channel is empty when the link turns off every channel of Category.
+	Channel string `json:"channel,omitempty" example:"email"`
+	// Unsubscribed is true once the opt-out is saved (POST); false when only describing it (GET).
+	Unsubscribed bool `json:"unsubscribed"`
+}
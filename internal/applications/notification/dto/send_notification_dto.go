@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"ichi-go/internal/applications/notification/models"
+)
 
 // SendNotificationRequest is the API request body for POST /api/notifications/send.
 type SendNotificationRequest struct {
@@ -8,7 +12,7 @@ type SendNotificationRequest struct {
 	// Must be registered in the Go TemplateRegistry (pkg/notification/template).
 	EventSlug string `json:"event_slug" validate:"required"`
 
-	// DeliveryMode controls routing: "blast" (all users) or "user" (specific users).
+	// DeliveryMode controls routing: "blast" (all users, or those of Segment) or "user" (specific users).
 	DeliveryMode DeliveryMode `json:"delivery_mode" validate:"required,oneof=blast user"`
 
 	// Channels lists the delivery channels. Each must be supported by the event's Go template.
@@ -19,9 +23,13 @@ type SendNotificationRequest struct {
 	UserTargetIDs []int64 `json:"user_target_ids,omitempty"`
 
 	// UserExcludeIDs is the list of user IDs to always exclude from delivery.
-	// Applied after UserTargetIDs — excluded IDs are removed before publishing.
+	// Applied after UserTargetIDs (or the blast's segment) — excluded users are never published.
 	UserExcludeIDs []int64 `json:"user_exclude_ids,omitempty"`
 
+	// Segment narrows the users a blast reaches by role, tenant, sign-up time and
+	// custom attributes. Only valid when DeliveryMode is "blast"; omitted reaches every user.
+	Segment *models.AudienceSegment `json:"segment,omitempty"`
+
 	// Locale is the BCP-47 language tag for template selection.
 	// Defaults to "en" when empty.
 	Locale string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag"`
@@ -31,7 +39,7 @@ type SendNotificationRequest struct {
 	Data map[string]any `json:"data,omitempty"`
 
 	// Meta holds operational metadata: trace_id, source service, correlation IDs.
-	// Not shown to end users.
+	// Not shown to end users. tenant_id is always set from the caller's tenant.
 	Meta map[string]string `json:"meta,omitempty"`
 
 	// ScheduledAt is the absolute delivery time in UTC (RFC3339).
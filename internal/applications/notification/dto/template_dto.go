@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"time"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// Preview sources: which copy a template preview renders.
+const (
+	PreviewSourceDefault  = "default"  // the Go template's DefaultContent
+	PreviewSourceOverride = "override" // the active override, or the Go default when there is none
+	PreviewSourceDraft    = "draft"    // the title and body templates of the request
+)
+
+// EventTemplateInfo describes an event registered in the Go template registry.
+type EventTemplateInfo struct {
+	Slug     string   `json:"slug" example:"order.shipped"`
+	Channels []string `json:"channels" example:"email,push"`
+	Category string   `json:"category" example:"orders"`
+	Kind     string   `json:"kind" example:"transactional"`
+	Urgent   bool     `json:"urgent"`
+	// SampleData are example data variables, used by previews when none are given.
+	SampleData map[string]any `json:"sample_data"`
+}
+
+// ListTemplateOverridesQuery filters GET /api/notifications/templates; empty fields match all.
+type ListTemplateOverridesQuery struct {
+	EventSlug string `query:"event_slug" validate:"omitempty,max=100"`
+	Channel   string `query:"channel"    validate:"omitempty,max=50"`
+	Locale    string `query:"locale"     validate:"omitempty,max=10"`
+}
+
+// CreateTemplateOverrideRequest is the API request body for POST /api/notifications/templates.
+// Templates use Go text/template syntax; an empty title or body keeps the Go default.
+type CreateTemplateOverrideRequest struct {
+	EventSlug     string  `json:"event_slug"               validate:"required,max=100"                        example:"order.shipped"`
+	Channel       Channel `json:"channel"                  validate:"required,oneof=email push sms in_app"    example:"push"`
+	Locale        string  `json:"locale"                   validate:"required,max=10"                         example:"en"`
+	TitleTemplate string  `json:"title_template,omitempty" validate:"omitempty,max=500"                       example:"{{.name}}, order #{{.order_id}} shipped"`
+	BodyTemplate  string  `json:"body_template,omitempty"  validate:"omitempty,max=20000"                     example:"Arriving {{.eta}}"`
+	// IsActive defaults to true; an inactive override is kept but not used.
+	IsActive *bool `json:"is_active,omitempty"`
+}
+
+// UpdateTemplateOverrideRequest is the API request body for PUT /api/notifications/templates/{id}.
+// Omitted fields are left unchanged.
+type UpdateTemplateOverrideRequest struct {
+	TitleTemplate *string `json:"title_template,omitempty" validate:"omitempty,max=500"`
+	BodyTemplate  *string `json:"body_template,omitempty"  validate:"omitempty,max=20000"`
+	IsActive      *bool   `json:"is_active,omitempty"`
+	// Revision is the revision the change was made from. When set, the update fails
+	// with 409 if the override has changed since.
+	Revision int `json:"revision,omitempty" validate:"omitempty,min=1" example:"3"`
+}
+
+// PreviewTemplateRequest is the API request body for POST /api/notifications/templates/preview.
+type PreviewTemplateRequest struct {
+	EventSlug string  `json:"event_slug" validate:"required,max=100"                     example:"order.shipped"`
+	Channel   Channel `json:"channel"    validate:"required,oneof=email push sms in_app" example:"push"`
+	Locale    string  `json:"locale"     validate:"omitempty,max=10"                     example:"en"`
+
+	// Source is "default", "override" or "draft". It defaults to "draft" when a
+	// template is given, "override" otherwise.
+	Source        string `json:"source,omitempty"         validate:"omitempty,oneof=default override draft"`
+	TitleTemplate string `json:"title_template,omitempty" validate:"omitempty,max=500"`
+	BodyTemplate  string `json:"body_template,omitempty"  validate:"omitempty,max=20000"`
+
+	// Data is merged over the sample data of the event.
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// PreviewTemplateResponse is a rendered notification title and body.
+type PreviewTemplateResponse struct {
+	EventSlug string `json:"event_slug"`
+	Channel   string `json:"channel"`
+	Locale    string `json:"locale"`
+	// ContentLocale is the locale of the copy rendered, found along the fallback
+	// chain of Locale: "id-ID", then "id", then "en".
+	ContentLocale string `json:"content_locale"`
+	// Source is the copy rendered: "default", "override" or "draft".
+	Source string `json:"source"`
+	// OverrideID is the override rendered when Source is "override".
+	OverrideID int64          `json:"override_id,omitempty"`
+	Title      string         `json:"title"`
+	Body       string         `json:"body"`
+	Data       map[string]any `json:"data"`
+}
+
+// TemplateOverrideResponse is a copy override of a notification template.
+type TemplateOverrideResponse struct {
+	ID            int64      `json:"id"`
+	EventSlug     string     `json:"event_slug"`
+	Channel       string     `json:"channel"`
+	Locale        string     `json:"locale"`
+	TitleTemplate string     `json:"title_template"`
+	BodyTemplate  string     `json:"body_template"`
+	IsActive      bool       `json:"is_active"`
+	Revision      int        `json:"revision"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CreatedBy     int64      `json:"created_by"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	UpdatedBy     int64      `json:"updated_by,omitempty"`
+}
+
+// NewTemplateOverrideResponse converts an override model into its API representation.
+func NewTemplateOverrideResponse(o *models.NotificationTemplateOverride) *TemplateOverrideResponse {
+	resp := &TemplateOverrideResponse{
+		ID:            o.ID,
+		EventSlug:     o.EventSlug,
+		Channel:       o.Channel,
+		Locale:        o.Locale,
+		TitleTemplate: o.TitleTemplate,
+		BodyTemplate:  o.BodyTemplate,
+		IsActive:      o.IsActive,
+		Revision:      o.Revision,
+		CreatedAt:     o.CreatedAt,
+		CreatedBy:     o.CreatedBy,
+		UpdatedBy:     o.UpdatedBy,
+	}
+	if !o.UpdatedAt.IsZero() {
+		updatedAt := o.UpdatedAt.Time
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
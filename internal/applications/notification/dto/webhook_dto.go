@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// WebhookDeliveryRoutingKey routes webhook delivery jobs on the app.events exchange.
+// Retries are published to the same key with an x-delay.
+const WebhookDeliveryRoutingKey = "notification.webhook"
+
+// CreateWebhookEndpointRequest is the API request body for POST /api/notifications/webhooks.
+type CreateWebhookEndpointRequest struct {
+	// URL receives a signed POST for every subscribed event.
+	URL string `json:"url" validate:"required,url,max=2048" example:"https://erp.example.com/hooks/ichi"`
+
+	// Description helps tell endpoints apart, e.g. "ERP order sync".
+	Description string `json:"description,omitempty" validate:"omitempty,max=255"`
+
+	// Events are event slugs ("order.shipped"), prefixes ("order.*") or "*" for all events.
+	Events []string `json:"events" validate:"required,min=1,max=50,dive,required,max=100" example:"order.shipped"`
+}
+
+// UpdateWebhookEndpointRequest is the API request body for PATCH /api/notifications/webhooks/{id}.
+// Omitted fields are left unchanged.
+type UpdateWebhookEndpointRequest struct {
+	URL         *string  `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=255"`
+	Events      []string `json:"events,omitempty" validate:"omitempty,min=1,max=50,dive,required,max=100"`
+
+	// IsActive pauses the endpoint, or re-enables it after it was disabled for failing;
+	// re-enabling resets the failure count.
+	IsActive *bool `json:"is_active,omitempty"`
+}
+
+// WebhookEndpointSecretResponse is an endpoint together with its signing secret.
+// The secret is only returned on creation and rotation.
+type WebhookEndpointSecretResponse struct {
+	*models.WebhookEndpoint
+	Secret string `json:"secret" example:"whsec_3f1c..."`
+}
+
+// WebhookPayload is the JSON body POSTed to webhook endpoints.
+type WebhookPayload struct {
+	ID        string         `json:"id"`   // event ID; identical across retries and redeliveries
+	Type      string         `json:"type"` // event slug, e.g. "order.shipped"
+	CreatedAt time.Time      `json:"created_at"`
+	TenantID  string         `json:"tenant_id"`
+	UserID    string         `json:"user_id,omitempty"`
+	Data      map[string]any `json:"data"`
+}
+
+// WebhookDeliveryJob is the queue message delivering one event to one endpoint.
+type WebhookDeliveryJob struct {
+	EndpointID   int64           `json:"endpoint_id"`
+	EventID      string          `json:"event_id"`
+	EventType    string          `json:"event_type"`
+	Attempt      int             `json:"attempt"`                 // 1 for the first try
+	RedeliveryOf int64           `json:"redelivery_of,omitempty"` // delivery a manual redelivery was made from
+	Payload      json.RawMessage `json:"payload"`                 // WebhookPayload, signed and sent as is
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AudienceSegment narrows the users a blast reaches. Every set field must match;
+// a nil or empty segment reaches every user.
+//
+// Roles and TenantID match the user's active RBAC role assignments: with both set,
+// a user needs one of Roles in TenantID; with only TenantID, any role in it.
+type AudienceSegment struct {
+	// Roles are role slugs; a user needs at least one of them.
+	Roles []string `json:"roles,omitempty" example:"customer"`
+
+	// TenantID limits the audience to users with a role in this tenant.
+	TenantID string `json:"tenant_id,omitempty" example:"acme"`
+
+	// CreatedFrom and CreatedTo bound when the user signed up; CreatedTo is exclusive.
+	CreatedFrom *time.Time `json:"created_from,omitempty"`
+	CreatedTo   *time.Time `json:"created_to,omitempty"`
+
+	// Attributes are custom user attributes (user_attributes); a user needs every
+	// name set to exactly its value.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// IsEmpty reports whether the segment matches every user.
+func (s *AudienceSegment) IsEmpty() bool {
+	return s == nil || (len(s.Roles) == 0 && s.TenantID == "" &&
+		s.CreatedFrom == nil && s.CreatedTo == nil && len(s.Attributes) == 0)
+}
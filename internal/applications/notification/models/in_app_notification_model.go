@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// InboxFilter selects which notifications of the inbox a listing returns.
+type InboxFilter string
+
+const (
+	InboxFilterInbox    InboxFilter = ""         // not archived, read or unread
+	InboxFilterUnread   InboxFilter = "unread"   // not archived and not read
+	InboxFilterArchived InboxFilter = "archived" // archived only
+	InboxFilterAll      InboxFilter = "all"      // everything
+)
+
+// InAppNotification is one notification in a user's inbox, stored by the in_app channel.
+//
+// EventID is unique per user, so an event requeued after a partial failure is not
+// stored twice. An archived notification leaves the inbox and no longer counts as unread.
+type InAppNotification struct {
+	ID        int64     `bun:"id,pk,autoincrement"                         json:"id"`
+	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UserID    int64     `bun:"user_id,notnull"                             json:"-"`
+	EventID   string    `bun:"event_id,notnull"                            json:"event_id"`
+	EventType string    `bun:"event_type,notnull"                          json:"event_type"`
+	Title     string    `bun:"title,notnull"                               json:"title"`
+	Body      string    `bun:"body,notnull"                                json:"body"`
+	// ActionURL is opened when the notification is clicked (event.Data["action_url"]).
+	ActionURL  string         `bun:"action_url,nullzero"  json:"action_url,omitempty"`
+	Data       map[string]any `bun:"data,type:json"       json:"data,omitempty"`
+	ReadAt     *time.Time     `bun:"read_at"              json:"read_at,omitempty"`
+	ArchivedAt *time.Time     `bun:"archived_at"          json:"archived_at,omitempty"`
+
+	_ struct{} `bun:"table:in_app_notifications,alias:ian"`
+}
+
+// IsRead reports whether the user has read the notification.
+func (n *InAppNotification) IsRead() bool {
+	return n.ReadAt != nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// NotificationBlastCheckpoint records how far the fan-out of one blast message got. Users are
+// read in id order, so a redelivered blast resumes after LastUserID instead of
+// starting over.
+type NotificationBlastCheckpoint struct {
+	// EventID is the EventID of the blast message.
+	EventID   string     `bun:"event_id,pk"                                  json:"event_id"`
+	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt *time.Time `bun:"updated_at"                                   json:"updated_at,omitempty"`
+	// CampaignID is 0 for blasts sent outside a campaign (NotificationService.Blast).
+	CampaignID int64 `bun:"campaign_id,notnull" json:"campaign_id"`
+	// LastUserID is the highest user ID read so far, excluded users included.
+	LastUserID int64 `bun:"last_user_id,notnull" json:"last_user_id"`
+	// Published counts the per-user messages published so far.
+	Published int64 `bun:"published,notnull" json:"published"`
+	// CompletedAt is set once every user of the audience was published.
+	CompletedAt *time.Time `bun:"completed_at" json:"completed_at,omitempty"`
+
+	_ struct{} `bun:"table:notification_blast_checkpoints,alias:nbc"`
+}
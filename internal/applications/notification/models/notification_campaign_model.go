@@ -15,10 +15,23 @@ const (
 	CampaignStatusProcessing CampaignStatus = "processing"
 	CampaignStatusPublished  CampaignStatus = "published"
 	CampaignStatusFailed     CampaignStatus = "failed"
+	// CampaignStatusCancelled is set when a scheduled campaign is cancelled before
+	// its delivery time; the dispatcher drops its messages.
+	CampaignStatusCancelled CampaignStatus = "cancelled"
 )
 
+// CampaignFilter narrows a campaign listing; zero fields match all campaigns.
+type CampaignFilter struct {
+	Status       CampaignStatus
+	DeliveryMode string
+	EventSlug    string
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+}
+
 // NotificationCampaign records one POST /api/notifications/send call.
-// Tracks the full lifecycle: pending → published | failed.
+// Tracks the full lifecycle: pending → published | failed, and published → cancelled
+// while a scheduled campaign has not reached its delivery time.
 type NotificationCampaign struct {
 	model.CoreModel `bun:"table:notification_campaigns,alias:nc"`
 
@@ -40,6 +53,10 @@ type NotificationCampaign struct {
 	// Applied after UserTargetIDs — excluded IDs are removed before publishing.
 	UserExcludeIDs []int64 `bun:"user_exclude_ids,type:json" json:"user_exclude_ids,omitempty"`
 
+	// Segment narrows the users a blast reaches (delivery_mode=blast only).
+	// Stored as JSON object; nil reaches every user.
+	Segment *AudienceSegment `bun:"segment,type:json" json:"segment,omitempty"`
+
 	// Locale is the BCP-47 language tag for template selection. Default: "en".
 	Locale string `bun:"locale,notnull,default:'en'" json:"locale"`
 
@@ -66,4 +83,23 @@ type NotificationCampaign struct {
 
 	// PublishedAt is set when the message was successfully queued in RabbitMQ.
 	PublishedAt *time.Time `bun:"published_at" json:"published_at,omitempty"`
+
+	// CancelledAt is set when Status is "cancelled".
+	CancelledAt *time.Time `bun:"cancelled_at" json:"cancelled_at,omitempty"`
+}
+
+// DeliverAt returns when the campaign's messages leave the delay queue: ScheduledAt,
+// or DelaySeconds after it was published (created, while still pending).
+func (c *NotificationCampaign) DeliverAt() time.Time {
+	if !c.ScheduledAt.IsZero() {
+		return c.ScheduledAt.Time
+	}
+	from := c.CreatedAt
+	if c.PublishedAt != nil {
+		from = *c.PublishedAt
+	}
+	if c.DelaySeconds != nil {
+		return from.Add(time.Duration(*c.DelaySeconds) * time.Second)
+	}
+	return from
 }
@@ -10,6 +10,11 @@ const (
 	LogStatusSent    LogStatus = "sent"
 	LogStatusFailed  LogStatus = "failed"
 	LogStatusSkipped LogStatus = "skipped"
+	// LogStatusDelivered is set by a provider delivery receipt after "sent".
+	LogStatusDelivered LogStatus = "delivered"
+	// LogStatusRetried marks a failed attempt that was queued again; the new
+	// attempt has its own log entry.
+	LogStatusRetried LogStatus = "retried"
 )
 
 // NotificationLog records a single per-user, per-channel delivery attempt.
@@ -17,16 +22,37 @@ const (
 //
 // Note: This model does NOT embed CoreModel — it uses a simpler schema
 // (no soft-delete, no audit fields) because logs are append-only and immutable.
+//
+// Provider and ProviderMessageID identify the message at the gateway (set by the
+// SMS channel) so delivery receipts can find the row and move it to "delivered"
+// or "failed".
 type NotificationLog struct {
-	ID         int64      `bun:"id,pk,autoincrement"                         json:"id"`
-	CreatedAt  time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
-	UpdatedAt  *time.Time `bun:"updated_at"                                  json:"updated_at,omitempty"`
-	CampaignID int64      `bun:"campaign_id,notnull"                         json:"campaign_id"`
-	UserID     int64      `bun:"user_id,notnull,default:0"                   json:"user_id"`
-	Channel    string     `bun:"channel,notnull"                             json:"channel"`
-	Status     LogStatus  `bun:"status,notnull,default:'pending'"            json:"status"`
-	Error      string     `bun:"error"                                       json:"error,omitempty"`
-	SentAt     *time.Time `bun:"sent_at"                                     json:"sent_at,omitempty"`
+	ID                int64      `bun:"id,pk,autoincrement"                          json:"id"`
+	CreatedAt         time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt         *time.Time `bun:"updated_at"                                   json:"updated_at,omitempty"`
+	CampaignID        int64      `bun:"campaign_id,notnull"                          json:"campaign_id"`
+	UserID            int64      `bun:"user_id,notnull,default:0"                    json:"user_id"`
+	Channel           string     `bun:"channel,notnull"                              json:"channel"`
+	Provider          string     `bun:"provider,nullzero"                            json:"provider,omitempty"`
+	ProviderMessageID string     `bun:"provider_message_id,nullzero"                 json:"provider_message_id,omitempty"`
+	Status            LogStatus  `bun:"status,notnull,default:'pending'"             json:"status"`
+	Error             string     `bun:"error"                                        json:"error,omitempty"`
+	SentAt            *time.Time `bun:"sent_at"                                      json:"sent_at,omitempty"`
+	DeliveredAt       *time.Time `bun:"delivered_at"                                 json:"delivered_at,omitempty"`
 
 	_ struct{} `bun:"table:notification_logs,alias:nl"`
 }
+
+// LogStatusCount is the number of log entries of a campaign with one channel and status.
+type LogStatusCount struct {
+	Channel string    `bun:"channel"`
+	Status  LogStatus `bun:"status"`
+	Count   int64     `bun:"count"`
+}
+
+// LogTiming holds the timestamps of a sent log entry, for delivery latency statistics.
+type LogTiming struct {
+	Channel     string     `bun:"channel"`
+	SentAt      time.Time  `bun:"sent_at"`
+	DeliveredAt *time.Time `bun:"delivered_at"`
+}
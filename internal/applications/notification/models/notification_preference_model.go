@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// NotificationPreference holds the delivery settings of a user. A user without a
+// row gets every notification, without quiet hours.
+//
+// Quiet hours are "HH:MM" wall-clock times in Timezone; a QuietHoursStart after
+// QuietHoursEnd wraps midnight. Both empty disables them.
+//
+// Locale is the canonical BCP-47 tag notifications are rendered in; empty uses the
+// locale of each event.
+type NotificationPreference struct {
+	UserID          int64      `bun:"user_id,pk"                                   json:"-"`
+	CreatedAt       time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"-"`
+	UpdatedAt       *time.Time `bun:"updated_at"                                   json:"-"`
+	Timezone        string     `bun:"timezone,nullzero"                            json:"timezone,omitempty"`
+	QuietHoursStart string     `bun:"quiet_hours_start,nullzero"                   json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   string     `bun:"quiet_hours_end,nullzero"                     json:"quiet_hours_end,omitempty"`
+	Locale          string     `bun:"locale,nullzero"                              json:"locale,omitempty"`
+	// UnsubscribedAt is set while the user is unsubscribed from all marketing notifications.
+	UnsubscribedAt *time.Time `bun:"unsubscribed_at" json:"unsubscribed_at,omitempty"`
+
+	// OptOuts are loaded separately from notification_opt_outs.
+	OptOuts []*NotificationOptOut `bun:"-" json:"-"`
+
+	_ struct{} `bun:"table:notification_preferences,alias:np"`
+}
+
+// IsOptedOut reports whether the user turned off channel for the event category.
+func (p *NotificationPreference) IsOptedOut(category, channel string) bool {
+	for _, o := range p.OptOuts {
+		if o.Category == category && o.Channel == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationOptOut turns off one channel of one event category for a user.
+type NotificationOptOut struct {
+	ID        int64     `bun:"id,pk,autoincrement"                          json:"-"`
+	CreatedAt time.Time `bun:"created_at,notnull,default:current_timestamp" json:"-"`
+	UserID    int64     `bun:"user_id,notnull"                              json:"-"`
+	Category  string    `bun:"category,notnull"                             json:"category"`
+	Channel   string    `bun:"channel,notnull"                              json:"channel"`
+
+	_ struct{} `bun:"table:notification_opt_outs,alias:noo"`
+}
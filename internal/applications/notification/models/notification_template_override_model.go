@@ -33,4 +33,8 @@ type NotificationTemplateOverride struct {
 
 	// IsActive controls whether this override is used. Inactive rows are ignored.
 	IsActive bool `bun:"is_active,notnull,default:true" json:"is_active"`
+
+	// Revision is the latest entry of the override's version history
+	// (NotificationTemplateOverrideVersion). Changes through the API increment it.
+	Revision int `bun:"revision,notnull,default:1" json:"revision"`
 }
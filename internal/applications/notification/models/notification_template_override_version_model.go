@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// TemplateOverrideAction is the change that produced a version of an override.
+type TemplateOverrideAction string
+
+const (
+	TemplateOverrideCreated    TemplateOverrideAction = "created"
+	TemplateOverrideUpdated    TemplateOverrideAction = "updated"
+	TemplateOverrideRolledBack TemplateOverrideAction = "rolled_back" // copied from SourceRevision
+)
+
+// NotificationTemplateOverrideVersion is a snapshot of a NotificationTemplateOverride
+// after one change. Revisions are sequential per override, from 1; a rollback
+// copies an older snapshot into the override and records it as a new revision.
+type NotificationTemplateOverrideVersion struct {
+	ID             int64                  `bun:"id,pk,autoincrement"                         json:"id"`
+	CreatedAt      time.Time              `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	CreatedBy      int64                  `bun:"created_by,notnull,default:0"                json:"created_by"`
+	OverrideID     int64                  `bun:"override_id,notnull"                         json:"override_id"`
+	Revision       int                    `bun:"revision,notnull"                            json:"revision"`
+	Action         TemplateOverrideAction `bun:"action,notnull"                              json:"action"`
+	SourceRevision int                    `bun:"source_revision,nullzero"                    json:"source_revision,omitempty"`
+	TitleTemplate  string                 `bun:"title_template,nullzero"                     json:"title_template"`
+	BodyTemplate   string                 `bun:"body_template,nullzero"                      json:"body_template"`
+	IsActive       bool                   `bun:"is_active,notnull"                           json:"is_active"`
+
+	_ struct{} `bun:"table:notification_template_override_versions,alias:ntov"`
+}
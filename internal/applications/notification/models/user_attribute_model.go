@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UserAttribute is one custom attribute of a user that blast segments can match on,
+// such as plan=pro or country=ID. A user has at most one value per name.
+type UserAttribute struct {
+	ID        int64      `bun:"id,pk,autoincrement"                          json:"-"`
+	CreatedAt time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"-"`
+	UpdatedAt *time.Time `bun:"updated_at"                                   json:"-"`
+	UserID    int64      `bun:"user_id,notnull"                              json:"-"`
+	Name      string     `bun:"name,notnull"                                 json:"name"`
+	Value     string     `bun:"value,notnull"                                json:"value"`
+
+	_ struct{} `bun:"table:user_attributes,alias:ua"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// DevicePlatform identifies the operating system a push token was issued for.
+type DevicePlatform string
+
+const (
+	DevicePlatformAndroid DevicePlatform = "android"
+	DevicePlatformIOS     DevicePlatform = "ios"
+	DevicePlatformWeb     DevicePlatform = "web"
+)
+
+// UserDevice is an FCM registration token of one of a user's devices.
+// The push channel reads these to reach a user, and deletes the ones FCM reports
+// as unregistered.
+//
+// Token is unique: registering a token another user holds moves it to the caller,
+// since a shared device only delivers to whoever signed in last.
+type UserDevice struct {
+	ID         int64          `bun:"id,pk,autoincrement"                           json:"id"`
+	CreatedAt  time.Time      `bun:"created_at,notnull,default:current_timestamp"   json:"created_at"`
+	UpdatedAt  *time.Time     `bun:"updated_at"                                    json:"updated_at,omitempty"`
+	UserID     int64          `bun:"user_id,notnull"                               json:"user_id"`
+	Platform   DevicePlatform `bun:"platform,notnull"                              json:"platform"`
+	AppVersion string         `bun:"app_version,nullzero"                          json:"app_version,omitempty"`
+	Token      string         `bun:"token,notnull"                                 json:"token"`
+	// Topics are the FCM topics the token is subscribed to.
+	Topics     []string  `bun:"topics,type:json"                              json:"topics"`
+	LastSeenAt time.Time `bun:"last_seen_at,notnull,default:current_timestamp" json:"last_seen_at"`
+
+	_ struct{} `bun:"table:user_devices,alias:ud"`
+}
+
+// HasTopic reports whether the device is subscribed to topic.
+func (d *UserDevice) HasTopic(topic string) bool {
+	for _, t := range d.Topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// WebhookDeliveryStatus is the outcome of one webhook delivery attempt.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // endpoint answered 2xx
+	WebhookDeliveryRetrying  WebhookDeliveryStatus = "retrying"  // failed; another attempt is scheduled
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // failed; no attempts left
+)
+
+// WebhookDelivery records one attempt to deliver an event to a webhook endpoint,
+// with snapshots of the signed request and of the response. Retries and manual
+// redeliveries are new rows; RequestBody is the payload they resend.
+type WebhookDelivery struct {
+	ID              int64                 `bun:"id,pk,autoincrement"                         json:"id"`
+	CreatedAt       time.Time             `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	EndpointID      int64                 `bun:"endpoint_id,notnull"                         json:"endpoint_id"`
+	EventID         string                `bun:"event_id,notnull"                            json:"event_id"`
+	EventType       string                `bun:"event_type,notnull"                          json:"event_type"`
+	Attempt         int                   `bun:"attempt,notnull"                             json:"attempt"`
+	RedeliveryOf    int64                 `bun:"redelivery_of,nullzero"                      json:"redelivery_of,omitempty"`
+	Status          WebhookDeliveryStatus `bun:"status,notnull"                              json:"status"`
+	RequestURL      string                `bun:"request_url,notnull"                         json:"request_url"`
+	RequestHeaders  map[string]string     `bun:"request_headers,type:json"                   json:"request_headers"`
+	RequestBody     string                `bun:"request_body,notnull"                        json:"request_body"`
+	ResponseStatus  int                   `bun:"response_status,nullzero"                    json:"response_status,omitempty"`
+	ResponseHeaders map[string]string     `bun:"response_headers,type:json"                  json:"response_headers,omitempty"`
+	ResponseBody    string                `bun:"response_body,nullzero"                      json:"response_body,omitempty"`
+	DurationMS      int64                 `bun:"duration_ms,notnull"                         json:"duration_ms"`
+	Error           string                `bun:"error,nullzero"                              json:"error,omitempty"`
+	NextRetryAt     *time.Time            `bun:"next_retry_at"                               json:"next_retry_at,omitempty"`
+
+	_ struct{} `bun:"table:webhook_deliveries,alias:wd"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// WebhookEndpoint is a URL a tenant registered to receive the notification events
+// sent on the webhook channel. Events lists the event slugs it subscribes to:
+// an exact slug ("order.shipped"), a prefix ("order.*") or "*" for everything.
+//
+// ConsecutiveFailures counts failed delivery attempts since the last success; the
+// endpoint is disabled once it reaches notification.webhook.disable_after.
+type WebhookEndpoint struct {
+	ID                  int64      `bun:"id,pk,autoincrement"                         json:"id"`
+	CreatedAt           time.Time  `bun:"created_at,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt           *time.Time `bun:"updated_at"                                  json:"updated_at,omitempty"`
+	TenantID            string     `bun:"tenant_id,notnull"                           json:"tenant_id"`
+	CreatedBy           int64      `bun:"created_by,nullzero"                         json:"created_by,omitempty"`
+	URL                 string     `bun:"url,notnull"                                 json:"url"`
+	Description         string     `bun:"description,nullzero"                        json:"description,omitempty"`
+	Secret              string     `bun:"secret,notnull"                              json:"-"`
+	Events              []string   `bun:"events,type:json,notnull"                    json:"events"`
+	IsActive            bool       `bun:"is_active,notnull"                           json:"is_active"`
+	ConsecutiveFailures int        `bun:"consecutive_failures,notnull"                json:"consecutive_failures"`
+	DisabledAt          *time.Time `bun:"disabled_at"                                 json:"disabled_at,omitempty"`
+	DisabledReason      string     `bun:"disabled_reason,nullzero"                    json:"disabled_reason,omitempty"`
+	LastSuccessAt       *time.Time `bun:"last_success_at"                             json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `bun:"last_failure_at"                             json:"last_failure_at,omitempty"`
+
+	_ struct{} `bun:"table:webhook_endpoints,alias:we"`
+}
+
+// Subscribes reports whether the endpoint wants events of eventType.
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	for _, filter := range e.Events {
+		switch {
+		case filter == "*", filter == eventType:
+			return true
+		case strings.HasSuffix(filter, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(filter, "*")):
+			return true
+		}
+	}
+	return false
+}
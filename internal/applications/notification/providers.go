@@ -3,7 +3,9 @@ package notification
 import (
 	"context"
 	"fmt"
+	"os"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/samber/do/v2"
 	"github.com/spf13/viper"
 	"github.com/uptrace/bun"
@@ -13,9 +15,17 @@ import (
 	notifController "ichi-go/internal/applications/notification/controller"
 	"ichi-go/internal/applications/notification/repositories"
 	"ichi-go/internal/applications/notification/services"
+	rbacServices "ichi-go/internal/applications/rbac/services"
+	userRepo "ichi-go/internal/applications/user/repository"
 	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/email"
 	"ichi-go/pkg/notification/fcm"
+	"ichi-go/pkg/notification/preference"
+	"ichi-go/pkg/notification/realtime"
+	"ichi-go/pkg/notification/sms"
 	notiftemplate "ichi-go/pkg/notification/template"
+	"ichi-go/pkg/notification/webhook"
 	// Import builtin templates so their init() functions run and register them.
 	_ "ichi-go/pkg/notification/template/builtin"
 )
@@ -26,19 +36,65 @@ func RegisterProviders(injector do.Injector) {
 	do.Provide(injector, ProvideTemplateOverrideRepository)
 	do.Provide(injector, ProvideCampaignRepository)
 	do.Provide(injector, ProvideLogRepository)
+	do.Provide(injector, ProvideUserDeviceRepository)
 	do.Provide(injector, ProvideTemplateRenderer)
 	do.ProvideNamed(injector, "notification.blast.producer", ProvideBlastProducer)
 	do.ProvideNamed(injector, "notification.user.producer", ProvideUserProducer)
 	do.Provide(injector, ProvideFCMClient)
 	do.Provide(injector, ProvidePushChannel)
+	do.Provide(injector, ProvideEmailClient)
+	do.Provide(injector, ProvideEmailChannel)
+	do.Provide(injector, ProvideSMSClient)
+	do.Provide(injector, ProvideSMSChannel)
+	do.Provide(injector, ProvideWebhookEndpointRepository)
+	do.Provide(injector, ProvideWebhookDeliveryRepository)
+	do.Provide(injector, ProvideWebhookClient)
+	do.Provide(injector, ProvideWebhookChannel)
 	do.Provide(injector, ProvideCampaignService)
 	do.Provide(injector, ProvideNotificationService)
 	do.Provide(injector, ProvideNotificationController)
+	do.Provide(injector, ProvideCampaignAdminService)
+	do.Provide(injector, ProvideCampaignController)
+	do.Provide(injector, ProvideAudienceRepository)
+	do.Provide(injector, ProvideAudienceService)
+	do.Provide(injector, ProvideAudienceController)
+	do.Provide(injector, ProvideDeviceService)
+	do.Provide(injector, ProvideDeviceController)
+	do.Provide(injector, ProvideSMSReceiptService)
+	do.Provide(injector, ProvideSMSReceiptController)
+	do.Provide(injector, ProvideWebhookService)
+	do.Provide(injector, ProvideWebhookController)
+	do.Provide(injector, ProvideInAppNotificationRepository)
+	do.Provide(injector, ProvideRealtimeHub)
+	do.Provide(injector, ProvideInAppChannel)
+	do.Provide(injector, ProvideInboxService)
+	do.Provide(injector, ProvideInboxController)
+	do.Provide(injector, ProvideNotificationPreferenceRepository)
+	do.Provide(injector, ProvidePreferenceService)
+	do.Provide(injector, ProvidePreferenceController)
+	do.Provide(injector, ProvideTemplateOverrideService)
+	do.Provide(injector, ProvideTemplateController)
 }
 
 // ProvideTemplateRegistry returns the global Go template registry.
 // Builtin templates are registered via init() when the builtin package is imported above.
+// The translation bundles of notification.templates.bundle_dir are merged over the
+// embedded ones, and startup fails when a channel of an event has no copy in the
+// fallback locale.
 func ProvideTemplateRegistry(_ do.Injector) (*notiftemplate.Registry, error) {
+	cfg, err := notiftemplate.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.BundleDir != "" {
+		if err := notiftemplate.GlobalBundle.Load(os.DirFS(cfg.BundleDir)); err != nil {
+			return nil, fmt.Errorf("notification: loading translation bundles from %s: %w", cfg.BundleDir, err)
+		}
+		logger.Infof("notification: translation bundles loaded from %s, locales %v", cfg.BundleDir, notiftemplate.GlobalBundle.Locales())
+	}
+	if err := notiftemplate.GlobalRegistry.Validate(); err != nil {
+		return nil, err
+	}
 	return notiftemplate.GlobalRegistry, nil
 }
 
@@ -57,6 +113,11 @@ func ProvideLogRepository(i do.Injector) (*repositories.NotificationLogRepositor
 	return repositories.NewNotificationLogRepository(db), nil
 }
 
+func ProvideUserDeviceRepository(i do.Injector) (*repositories.UserDeviceRepository, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return repositories.NewUserDeviceRepository(db), nil
+}
+
 func ProvideTemplateRenderer(i do.Injector) (*services.TemplateRenderer, error) {
 	registry := do.MustInvoke[*notiftemplate.Registry](i)
 	overrideRepo := do.MustInvoke[*repositories.NotificationTemplateOverrideRepository](i)
@@ -77,14 +138,283 @@ func ProvideFCMClient(_ do.Injector) (*fcm.Client, error) {
 	return client, nil
 }
 
-// ProvidePushChannel provides the FCM-backed push channel.
+// ProvidePushChannel provides the FCM-backed push channel, resolving tokens from the device registry.
 // Falls back to no-op when FCM client is nil (disabled).
 func ProvidePushChannel(i do.Injector) (*notifChannels.PushChannel, error) {
 	fcmClient, err := do.Invoke[*fcm.Client](i)
 	if err != nil {
 		return nil, fmt.Errorf("notification: failed to get FCM client: %w", err)
 	}
-	return notifChannels.NewPushChannel(fcmClient), nil
+	devices := do.MustInvoke[*repositories.UserDeviceRepository](i)
+	return notifChannels.NewPushChannel(fcmClient, devices), nil
+}
+
+// ProvideEmailClient initializes the pooled SMTP client.
+// Returns nil (not an error) when email is disabled in config so the app starts without a relay.
+func ProvideEmailClient(_ do.Injector) (*email.Client, error) {
+	if !viper.GetBool("notification.email.enabled") {
+		return nil, nil
+	}
+	cfg, err := email.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return email.NewClient(cfg)
+}
+
+// ProvideEmailChannel provides the SMTP-backed email channel, resolving recipients from the users table.
+// Falls back to no-op when the SMTP client is nil (disabled).
+func ProvideEmailChannel(i do.Injector) (*notifChannels.EmailChannel, error) {
+	client, err := do.Invoke[*email.Client](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get SMTP client: %w", err)
+	}
+	cfg, err := email.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := notifChannels.NewEmailOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db := do.MustInvoke[*bun.DB](i)
+	return notifChannels.NewEmailChannel(client, userRepo.NewUserRepository(db), opts), nil
+}
+
+// ProvideSMSClient initializes the SMS providers.
+// Returns nil (not an error) when SMS is disabled in config so the app starts without gateway credentials.
+func ProvideSMSClient(_ do.Injector) (*sms.Client, error) {
+	if !viper.GetBool("notification.sms.enabled") {
+		return nil, nil
+	}
+	cfg, err := sms.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return sms.NewClient(cfg)
+}
+
+// ProvideSMSChannel provides the SMS channel, recording provider message IDs on notification logs.
+// Falls back to no-op when the SMS client is nil (disabled).
+func ProvideSMSChannel(i do.Injector) (*notifChannels.SMSChannel, error) {
+	client, err := do.Invoke[*sms.Client](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get SMS client: %w", err)
+	}
+	cfg, err := sms.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	logRepo := do.MustInvoke[*repositories.NotificationLogRepository](i)
+	return notifChannels.NewSMSChannel(client, logRepo, cfg), nil
+}
+
+// ProvideSMSReceiptService wires SMSReceiptService with the SMS client, when SMS is enabled,
+// and the notification log repository.
+func ProvideSMSReceiptService(i do.Injector) (*services.SMSReceiptService, error) {
+	logRepo := do.MustInvoke[*repositories.NotificationLogRepository](i)
+	client, err := do.Invoke[*sms.Client](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get SMS client: %w", err)
+	}
+	// A nil *sms.Client must not become a non-nil ReceiptParser.
+	if client == nil {
+		return services.NewSMSReceiptService(nil, logRepo), nil
+	}
+	return services.NewSMSReceiptService(client, logRepo), nil
+}
+
+func ProvideSMSReceiptController(i do.Injector) (*notifController.SMSReceiptController, error) {
+	receiptSvc := do.MustInvoke[*services.SMSReceiptService](i)
+	return notifController.NewSMSReceiptController(receiptSvc), nil
+}
+
+func ProvideWebhookEndpointRepository(i do.Injector) (*repositories.WebhookEndpointRepository, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return repositories.NewWebhookEndpointRepository(db), nil
+}
+
+func ProvideWebhookDeliveryRepository(i do.Injector) (*repositories.WebhookDeliveryRepository, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return repositories.NewWebhookDeliveryRepository(db), nil
+}
+
+// ProvideWebhookClient initializes the signing HTTP client of webhook deliveries.
+// Returns nil (not an error) when webhooks are disabled in config.
+func ProvideWebhookClient(_ do.Injector) (*webhook.Client, error) {
+	if !viper.GetBool("notification.webhook.enabled") {
+		return nil, nil
+	}
+	cfg, err := webhook.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return webhook.NewClient(cfg), nil
+}
+
+// ProvideWebhookChannel provides the webhook channel, queueing deliveries on the main producer.
+// Falls back to no-op when webhooks are disabled or the queue is unavailable.
+func ProvideWebhookChannel(i do.Injector) (*notifChannels.WebhookChannel, error) {
+	cfg, err := webhook.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	producer, err := do.Invoke[rabbitmq.MessageProducer](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get main producer: %w", err)
+	}
+	endpoints := do.MustInvoke[*repositories.WebhookEndpointRepository](i)
+	appCfg := do.MustInvoke[*config.Config](i)
+	return notifChannels.NewWebhookChannel(endpoints, producer, cfg, appCfg.RBAC().DefaultTenant), nil
+}
+
+// ProvideWebhookService wires WebhookService with the webhook repositories, the main
+// producer for redeliveries and, when available, RBAC for the webhooks:manage check.
+func ProvideWebhookService(i do.Injector) (*services.WebhookService, error) {
+	cfg, err := webhook.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	appCfg := do.MustInvoke[*config.Config](i)
+	opts := services.WebhookOptions{
+		DefaultTenant:        appCfg.RBAC().DefaultTenant,
+		AllowPrivateNetworks: cfg.AllowPrivateNetworks,
+	}
+	// A nil *EnforcementService must not become a non-nil PermissionChecker.
+	if enforcement, err := do.Invoke[*rbacServices.EnforcementService](i); err == nil && enforcement != nil {
+		opts.Permissions = enforcement
+	} else {
+		logger.Warnf("⚠️  RBAC enforcement not available, webhook management disabled: %v", err)
+	}
+
+	producer, err := do.Invoke[rabbitmq.MessageProducer](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get main producer: %w", err)
+	}
+	endpoints := do.MustInvoke[*repositories.WebhookEndpointRepository](i)
+	deliveries := do.MustInvoke[*repositories.WebhookDeliveryRepository](i)
+	return services.NewWebhookService(endpoints, deliveries, producer, opts), nil
+}
+
+func ProvideWebhookController(i do.Injector) (*notifController.WebhookController, error) {
+	webhookSvc := do.MustInvoke[*services.WebhookService](i)
+	return notifController.NewWebhookController(webhookSvc), nil
+}
+
+func ProvideInAppNotificationRepository(i do.Injector) (*repositories.InAppNotificationRepository, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return repositories.NewInAppNotificationRepository(db), nil
+}
+
+// ProvideRealtimeHub provides the hub of open inbox streams, shared by the in-app
+// channel and the inbox API. Messages fan out across pods over Redis pub/sub; without
+// Redis they only reach streams on this pod.
+// Returns nil (not an error) when the in-app channel is disabled in config.
+func ProvideRealtimeHub(i do.Injector) (*realtime.Hub, error) {
+	cfg, err := realtime.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil || redisClient == nil {
+		logger.Warnf("⚠️  Redis not available, in-app notifications only reach streams on this instance: %v", err)
+		return realtime.NewHub(cfg, nil), nil
+	}
+	return realtime.NewHub(cfg, realtime.NewRedisBroker(redisClient, cfg.RedisChannel)), nil
+}
+
+// ProvideInAppChannel provides the in-app channel, storing notifications in the inbox
+// and pushing them to the user's open streams.
+func ProvideInAppChannel(i do.Injector) (*notifChannels.InAppChannel, error) {
+	cfg, err := realtime.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	hub, err := do.Invoke[*realtime.Hub](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get realtime hub: %w", err)
+	}
+	repo := do.MustInvoke[*repositories.InAppNotificationRepository](i)
+	return notifChannels.NewInAppChannel(repo, hub, cfg), nil
+}
+
+func ProvideInboxService(i do.Injector) (*services.InboxService, error) {
+	repo := do.MustInvoke[*repositories.InAppNotificationRepository](i)
+	hub, err := do.Invoke[*realtime.Hub](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get realtime hub: %w", err)
+	}
+	// A nil *realtime.Hub must not become a non-nil InboxHub.
+	if hub == nil {
+		return services.NewInboxService(repo, nil), nil
+	}
+	return services.NewInboxService(repo, hub), nil
+}
+
+func ProvideInboxController(i do.Injector) (*notifController.InboxController, error) {
+	cfg, err := realtime.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	inboxSvc := do.MustInvoke[*services.InboxService](i)
+	return notifController.NewInboxController(inboxSvc, cfg), nil
+}
+
+func ProvideNotificationPreferenceRepository(i do.Injector) (*repositories.NotificationPreferenceRepository, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return repositories.NewNotificationPreferenceRepository(db), nil
+}
+
+// ProvidePreferenceService wires PreferenceService with the preference repository, the
+// template registry for event categories and the main producer for quiet-hour deferrals.
+func ProvidePreferenceService(i do.Injector) (*services.PreferenceService, error) {
+	cfg, err := preference.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	signer := preference.NewSigner(cfg.UnsubscribeSecret)
+	if signer == nil {
+		logger.Warnf("⚠️  notification.preferences.unsubscribe_secret not set, one-click unsubscribe links disabled")
+	}
+	registry := do.MustInvoke[*notiftemplate.Registry](i)
+	repo := do.MustInvoke[*repositories.NotificationPreferenceRepository](i)
+	producer, err := do.Invoke[rabbitmq.MessageProducer](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get main producer: %w", err)
+	}
+	// ProvideMainProducer returns (nil, nil) when the queue is disabled.
+	if producer == nil {
+		return services.NewPreferenceService(repo, registry, nil, signer, cfg), nil
+	}
+	return services.NewPreferenceService(repo, registry, producer, signer, cfg), nil
+}
+
+func ProvidePreferenceController(i do.Injector) (*notifController.PreferenceController, error) {
+	preferenceSvc := do.MustInvoke[*services.PreferenceService](i)
+	return notifController.NewPreferenceController(preferenceSvc), nil
+}
+
+// ProvideDeviceService wires DeviceService with the device registry and, when FCM is enabled,
+// the FCM client for topic subscriptions.
+func ProvideDeviceService(i do.Injector) (*services.DeviceService, error) {
+	repo := do.MustInvoke[*repositories.UserDeviceRepository](i)
+	fcmClient, err := do.Invoke[*fcm.Client](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get FCM client: %w", err)
+	}
+	// A nil *fcm.Client must not become a non-nil TopicSubscriber.
+	if fcmClient == nil {
+		return services.NewDeviceService(repo, nil), nil
+	}
+	return services.NewDeviceService(repo, fcmClient), nil
+}
+
+func ProvideDeviceController(i do.Injector) (*notifController.DeviceController, error) {
+	deviceSvc := do.MustInvoke[*services.DeviceService](i)
+	return notifController.NewDeviceController(deviceSvc), nil
 }
 
 func ProvideCampaignService(i do.Injector) (*services.CampaignService, error) {
@@ -107,6 +437,63 @@ func ProvideNotificationController(i do.Injector) (*notifController.Notification
 	return notifController.NewNotificationController(campaignSvc), nil
 }
 
+// ProvideCampaignAdminService wires CampaignAdminService with the campaign and log
+// repositories, the main producer for retries and, when available, RBAC for the
+// campaigns:manage check.
+func ProvideCampaignAdminService(i do.Injector) (*services.CampaignAdminService, error) {
+	appCfg := do.MustInvoke[*config.Config](i)
+	opts := services.CampaignAdminOptions{DefaultTenant: appCfg.RBAC().DefaultTenant}
+	// A nil *EnforcementService must not become a non-nil PermissionChecker.
+	if enforcement, err := do.Invoke[*rbacServices.EnforcementService](i); err == nil && enforcement != nil {
+		opts.Permissions = enforcement
+	} else {
+		logger.Warnf("⚠️  RBAC enforcement not available, campaign management disabled: %v", err)
+	}
+
+	campaignRepo := do.MustInvoke[*repositories.NotificationCampaignRepository](i)
+	logRepo := do.MustInvoke[*repositories.NotificationLogRepository](i)
+	producer, err := do.Invoke[rabbitmq.MessageProducer](i)
+	if err != nil {
+		return nil, fmt.Errorf("notification: failed to get main producer: %w", err)
+	}
+	// ProvideMainProducer returns (nil, nil) when the queue is disabled.
+	if producer == nil {
+		return services.NewCampaignAdminService(campaignRepo, logRepo, nil, opts), nil
+	}
+	return services.NewCampaignAdminService(campaignRepo, logRepo, producer, opts), nil
+}
+
+func ProvideCampaignController(i do.Injector) (*notifController.CampaignController, error) {
+	campaignAdminSvc := do.MustInvoke[*services.CampaignAdminService](i)
+	return notifController.NewCampaignController(campaignAdminSvc), nil
+}
+
+func ProvideAudienceRepository(i do.Injector) (*repositories.AudienceRepository, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return repositories.NewAudienceRepository(db), nil
+}
+
+// ProvideAudienceService wires AudienceService with the audience repository and,
+// when available, RBAC for the campaigns:manage check.
+func ProvideAudienceService(i do.Injector) (*services.AudienceService, error) {
+	appCfg := do.MustInvoke[*config.Config](i)
+	opts := services.AudienceOptions{DefaultTenant: appCfg.RBAC().DefaultTenant}
+	// A nil *EnforcementService must not become a non-nil PermissionChecker.
+	if enforcement, err := do.Invoke[*rbacServices.EnforcementService](i); err == nil && enforcement != nil {
+		opts.Permissions = enforcement
+	} else {
+		logger.Warnf("⚠️  RBAC enforcement not available, audience management disabled: %v", err)
+	}
+
+	audienceRepo := do.MustInvoke[*repositories.AudienceRepository](i)
+	return services.NewAudienceService(audienceRepo, opts), nil
+}
+
+func ProvideAudienceController(i do.Injector) (*notifController.AudienceController, error) {
+	audienceSvc := do.MustInvoke[*services.AudienceService](i)
+	return notifController.NewAudienceController(audienceSvc), nil
+}
+
 // ProvideBlastProducer returns a producer bound to the fanout blast exchange.
 // Returns nil (not an error) when the queue connection is unavailable.
 // Callers must guard against nil before invoking Publish.
@@ -161,3 +548,25 @@ func ProvideNotificationService(i do.Injector) (*services.NotificationService, e
 	}
 	return services.NewNotificationService(blastProducer, userProducer), nil
 }
+
+// ProvideTemplateOverrideService wires TemplateOverrideService with the override repository,
+// the template registry and, when available, RBAC for the templates:manage check.
+func ProvideTemplateOverrideService(i do.Injector) (*services.TemplateOverrideService, error) {
+	appCfg := do.MustInvoke[*config.Config](i)
+	opts := services.TemplateOverrideOptions{DefaultTenant: appCfg.RBAC().DefaultTenant}
+	// A nil *EnforcementService must not become a non-nil PermissionChecker.
+	if enforcement, err := do.Invoke[*rbacServices.EnforcementService](i); err == nil && enforcement != nil {
+		opts.Permissions = enforcement
+	} else {
+		logger.Warnf("⚠️  RBAC enforcement not available, template override management disabled: %v", err)
+	}
+
+	registry := do.MustInvoke[*notiftemplate.Registry](i)
+	repo := do.MustInvoke[*repositories.NotificationTemplateOverrideRepository](i)
+	return services.NewTemplateOverrideService(repo, registry, opts), nil
+}
+
+func ProvideTemplateController(i do.Injector) (*notifController.TemplateController, error) {
+	templateSvc := do.MustInvoke[*services.TemplateOverrideService](i)
+	return notifController.NewTemplateController(templateSvc), nil
+}
@@ -15,4 +15,28 @@ func Register(injector do.Injector, serviceName string, e *echo.Echo, auth *auth
 
 	ctrl := do.MustInvoke[*notifController.NotificationController](injector)
 	ctrl.RegisterRoutes(e, serviceName, auth)
+
+	campaignCtrl := do.MustInvoke[*notifController.CampaignController](injector)
+	campaignCtrl.RegisterRoutes(e, serviceName, auth)
+
+	audienceCtrl := do.MustInvoke[*notifController.AudienceController](injector)
+	audienceCtrl.RegisterRoutes(e, serviceName, auth)
+
+	deviceCtrl := do.MustInvoke[*notifController.DeviceController](injector)
+	deviceCtrl.RegisterRoutes(e, serviceName, auth)
+
+	smsReceiptCtrl := do.MustInvoke[*notifController.SMSReceiptController](injector)
+	smsReceiptCtrl.RegisterRoutes(e, serviceName)
+
+	webhookCtrl := do.MustInvoke[*notifController.WebhookController](injector)
+	webhookCtrl.RegisterRoutes(e, serviceName, auth)
+
+	inboxCtrl := do.MustInvoke[*notifController.InboxController](injector)
+	inboxCtrl.RegisterRoutes(e, serviceName, auth)
+
+	preferenceCtrl := do.MustInvoke[*notifController.PreferenceController](injector)
+	preferenceCtrl.RegisterRoutes(e, serviceName, auth)
+
+	templateCtrl := do.MustInvoke[*notifController.TemplateController](injector)
+	templateCtrl.RegisterRoutes(e, serviceName, auth)
 }
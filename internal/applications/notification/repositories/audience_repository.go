@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// AudienceRepository resolves the users a blast segment matches, and stores the
+// custom user attributes segments match on.
+type AudienceRepository struct {
+	db *bun.DB
+}
+
+func NewAudienceRepository(db *bun.DB) *AudienceRepository {
+	return &AudienceRepository{db: db}
+}
+
+// ListUserIDs returns up to limit IDs of the users in segment after afterID, in
+// ascending order, so callers page through the audience with the last ID returned.
+func (r *AudienceRepository) ListUserIDs(ctx context.Context, segment *models.AudienceSegment, afterID int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := r.segmentQuery(segment).
+		Column("u.id").
+		Where("u.id > ?", afterID).
+		OrderExpr("u.id ASC").
+		Limit(limit).
+		Scan(ctx, &ids)
+	return ids, err
+}
+
+// Count returns the number of users in segment.
+func (r *AudienceRepository) Count(ctx context.Context, segment *models.AudienceSegment) (int, error) {
+	return r.segmentQuery(segment).Count(ctx)
+}
+
+// ListAttributes returns the custom attributes of a user, by name.
+func (r *AudienceRepository) ListAttributes(ctx context.Context, userID int64) ([]*models.UserAttribute, error) {
+	var attrs []*models.UserAttribute
+	err := r.db.NewSelect().Model(&attrs).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Scan(ctx)
+	return attrs, err
+}
+
+// ReplaceAttributes sets the custom attributes of a user to attrs, removing the others.
+func (r *AudienceRepository) ReplaceAttributes(ctx context.Context, userID int64, attrs map[string]string) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().Model((*models.UserAttribute)(nil)).
+			Where("user_id = ?", userID).
+			Exec(ctx); err != nil {
+			return err
+		}
+		if len(attrs) == 0 {
+			return nil
+		}
+
+		rows := make([]*models.UserAttribute, 0, len(attrs))
+		for _, name := range sortedKeys(attrs) {
+			rows = append(rows, &models.UserAttribute{UserID: userID, Name: name, Value: attrs[name]})
+		}
+		_, err := tx.NewInsert().Model(&rows).Exec(ctx)
+		return err
+	})
+}
+
+// segmentQuery selects the users matched by segment that are not deleted.
+func (r *AudienceRepository) segmentQuery(segment *models.AudienceSegment) *bun.SelectQuery {
+	q := r.db.NewSelect().
+		TableExpr("users AS u").
+		Where("u.deleted_at IS NULL")
+	if segment.IsEmpty() {
+		return q
+	}
+
+	// Roles and tenant match active assignments of roles that were not deleted.
+	if len(segment.Roles) > 0 || segment.TenantID != "" {
+		assignments := r.db.NewSelect().
+			TableExpr("rbac_user_roles AS ur").
+			Join("JOIN rbac_roles AS r ON r.id = ur.role_id").
+			ColumnExpr("1").
+			Where("ur.user_id = u.id").
+			Where("r.deleted_at IS NULL").
+			Where("(ur.expires_at IS NULL OR ur.expires_at > ?)", time.Now())
+		if segment.TenantID != "" {
+			assignments = assignments.Where("ur.tenant_id = ?", segment.TenantID)
+		}
+		if len(segment.Roles) > 0 {
+			assignments = assignments.Where("r.slug IN (?)", bun.In(segment.Roles))
+		}
+		q = q.Where("EXISTS (?)", assignments)
+	}
+
+	if segment.CreatedFrom != nil {
+		q = q.Where("u.created_at >= ?", *segment.CreatedFrom)
+	}
+	if segment.CreatedTo != nil {
+		q = q.Where("u.created_at < ?", *segment.CreatedTo)
+	}
+
+	// Sorted so the same segment always builds the same SQL.
+	for _, name := range sortedKeys(segment.Attributes) {
+		q = q.Where("EXISTS (?)", r.db.NewSelect().
+			TableExpr("user_attributes AS ua").
+			ColumnExpr("1").
+			Where("ua.user_id = u.id").
+			Where("ua.name = ?", name).
+			Where("ua.value = ?", segment.Attributes[name]))
+	}
+	return q
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
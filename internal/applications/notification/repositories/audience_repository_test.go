@@ -0,0 +1,13 @@
+package repositories_test
+
+import (
+	"ichi-go/internal/applications/notification/consumers"
+	"ichi-go/internal/applications/notification/repositories"
+	"ichi-go/internal/applications/notification/services"
+)
+
+// Compile-time assertions for blast fan-out and audience management.
+var (
+	_ consumers.AudienceResolver = (*repositories.AudienceRepository)(nil)
+	_ services.AudienceStore     = (*repositories.AudienceRepository)(nil)
+)
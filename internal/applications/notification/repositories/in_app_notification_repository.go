@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// InAppNotificationRepository stores the in-app notification inboxes of users.
+type InAppNotificationRepository struct {
+	db *bun.DB
+}
+
+func NewInAppNotificationRepository(db *bun.DB) *InAppNotificationRepository {
+	return &InAppNotificationRepository{db: db}
+}
+
+// Create inserts a notification unless the user already has one for the same event,
+// in which case notification is replaced by the stored row.
+// Reports whether a row was inserted.
+func (r *InAppNotificationRepository) Create(ctx context.Context, notification *models.InAppNotification) (bool, error) {
+	created := false
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		existing := new(models.InAppNotification)
+		err := tx.NewSelect().Model(existing).
+			Where("user_id = ?", notification.UserID).
+			Where("event_id = ?", notification.EventID).
+			Scan(ctx)
+		if err == nil {
+			*notification = *existing
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if _, err := tx.NewInsert().Model(notification).Returning("id").Exec(ctx); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+// FindByID returns nil, nil when the notification does not exist.
+func (r *InAppNotificationRepository) FindByID(ctx context.Context, id int64) (*models.InAppNotification, error) {
+	notification := new(models.InAppNotification)
+	err := r.db.NewSelect().Model(notification).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// ListByUser returns up to limit notifications of a user matching filter, newest
+// first. beforeID > 0 returns only notifications older than that one (the cursor).
+func (r *InAppNotificationRepository) ListByUser(ctx context.Context, userID int64, filter models.InboxFilter, beforeID int64, limit int) ([]*models.InAppNotification, error) {
+	var notifications []*models.InAppNotification
+	q := r.db.NewSelect().Model(&notifications).Where("user_id = ?", userID)
+	switch filter {
+	case models.InboxFilterUnread:
+		q = q.Where("archived_at IS NULL").Where("read_at IS NULL")
+	case models.InboxFilterArchived:
+		q = q.Where("archived_at IS NOT NULL")
+	case models.InboxFilterAll:
+	default:
+		q = q.Where("archived_at IS NULL")
+	}
+	if beforeID > 0 {
+		q = q.Where("id < ?", beforeID)
+	}
+	err := q.Order("id DESC").Limit(limit).Scan(ctx)
+	return notifications, err
+}
+
+// ListAfter returns up to limit inbox notifications of a user newer than afterID, oldest first.
+func (r *InAppNotificationRepository) ListAfter(ctx context.Context, userID, afterID int64, limit int) ([]*models.InAppNotification, error) {
+	var notifications []*models.InAppNotification
+	err := r.db.NewSelect().Model(&notifications).
+		Where("user_id = ?", userID).
+		Where("archived_at IS NULL").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Scan(ctx)
+	return notifications, err
+}
+
+// CountUnread counts the unread notifications in a user's inbox.
+func (r *InAppNotificationRepository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	return r.db.NewSelect().Model((*models.InAppNotification)(nil)).
+		Where("user_id = ?", userID).
+		Where("archived_at IS NULL").
+		Where("read_at IS NULL").
+		Count(ctx)
+}
+
+// SetRead sets read_at of a notification; nil marks it unread.
+func (r *InAppNotificationRepository) SetRead(ctx context.Context, id int64, at *time.Time) error {
+	_, err := r.db.NewUpdate().Model((*models.InAppNotification)(nil)).
+		Set("read_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// SetArchived sets archived_at of a notification; nil moves it back to the inbox.
+func (r *InAppNotificationRepository) SetArchived(ctx context.Context, id int64, at *time.Time) error {
+	_, err := r.db.NewUpdate().Model((*models.InAppNotification)(nil)).
+		Set("archived_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// MarkAllRead marks every unread notification in a user's inbox as read and returns how many changed.
+func (r *InAppNotificationRepository) MarkAllRead(ctx context.Context, userID int64, at time.Time) (int64, error) {
+	res, err := r.db.NewUpdate().Model((*models.InAppNotification)(nil)).
+		Set("read_at = ?", at).
+		Where("user_id = ?", userID).
+		Where("archived_at IS NULL").
+		Where("read_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,13 @@
+package repositories_test
+
+import (
+	"ichi-go/internal/applications/notification/channels"
+	"ichi-go/internal/applications/notification/repositories"
+	"ichi-go/internal/applications/notification/services"
+)
+
+// Compile-time assertions for the in-app inbox repository.
+var (
+	_ services.InboxRepository = (*repositories.InAppNotificationRepository)(nil)
+	_ channels.InboxStore      = (*repositories.InAppNotificationRepository)(nil)
+)
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// NotificationBlastCheckpointRepository stores the fan-out progress of blast messages.
+type NotificationBlastCheckpointRepository struct {
+	db *bun.DB
+}
+
+func NewNotificationBlastCheckpointRepository(db *bun.DB) *NotificationBlastCheckpointRepository {
+	return &NotificationBlastCheckpointRepository{db: db}
+}
+
+// Find returns the checkpoint of a blast message, or nil, nil when its fan-out has not started.
+func (r *NotificationBlastCheckpointRepository) Find(ctx context.Context, eventID string) (*models.NotificationBlastCheckpoint, error) {
+	checkpoint := new(models.NotificationBlastCheckpoint)
+	err := r.db.NewSelect().Model(checkpoint).Where("event_id = ?", eventID).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// Create inserts the checkpoint of a blast message whose fan-out starts.
+func (r *NotificationBlastCheckpointRepository) Create(ctx context.Context, checkpoint *models.NotificationBlastCheckpoint) error {
+	_, err := r.db.NewInsert().Model(checkpoint).Exec(ctx)
+	return err
+}
+
+// Update saves the progress of checkpoint.
+func (r *NotificationBlastCheckpointRepository) Update(ctx context.Context, checkpoint *models.NotificationBlastCheckpoint) error {
+	now := time.Now()
+	checkpoint.UpdatedAt = &now
+	_, err := r.db.NewUpdate().Model(checkpoint).
+		Column("last_user_id", "published", "completed_at", "updated_at").
+		WherePK().
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,9 @@
+package repositories_test
+
+import (
+	"ichi-go/internal/applications/notification/consumers"
+	"ichi-go/internal/applications/notification/repositories"
+)
+
+// Compile-time assertion for the progress of blast fan-out.
+var _ consumers.BlastCheckpointStore = (*repositories.NotificationBlastCheckpointRepository)(nil)
@@ -2,12 +2,15 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/uptrace/bun"
 
 	"ichi-go/internal/applications/notification/models"
 	baseRepository "ichi-go/pkg/db/repository"
+	"ichi-go/pkg/requestctx"
 )
 
 // NotificationCampaignRepository manages notification campaign lifecycle records.
@@ -26,9 +29,13 @@ func (r *NotificationCampaignRepository) CreateCampaign(ctx context.Context, cam
 	return r.Create(ctx, campaign)
 }
 
-// FindByID retrieves a campaign by primary key.
+// FindByID retrieves a campaign by primary key. Returns nil, nil when not found.
 func (r *NotificationCampaignRepository) FindByID(ctx context.Context, id int64) (*models.NotificationCampaign, error) {
-	return r.Find(ctx, id)
+	campaign, err := r.Find(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return campaign, err
 }
 
 // UpdateStatus sets status, optional error message, and optional published_at timestamp.
@@ -55,3 +62,64 @@ func (r *NotificationCampaignRepository) UpdateStatus(
 	_, err := q.Where("id = ?", id).Exec(ctx)
 	return err
 }
+
+// List returns up to limit campaigns matching filter, newest first. beforeID > 0
+// returns only campaigns older than that one (the cursor).
+func (r *NotificationCampaignRepository) List(ctx context.Context, filter models.CampaignFilter, beforeID int64, limit int) ([]*models.NotificationCampaign, error) {
+	var campaigns []*models.NotificationCampaign
+	q := r.DB().NewSelect().Model(&campaigns)
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.DeliveryMode != "" {
+		q = q.Where("delivery_mode = ?", filter.DeliveryMode)
+	}
+	if filter.EventSlug != "" {
+		q = q.Where("event_slug = ?", filter.EventSlug)
+	}
+	if filter.CreatedFrom != nil {
+		q = q.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		q = q.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if beforeID > 0 {
+		q = q.Where("id < ?", beforeID)
+	}
+	err := q.Order("id DESC").Limit(limit).Scan(ctx)
+	return campaigns, err
+}
+
+// FindStatus returns the status of a campaign, or "" when it does not exist.
+func (r *NotificationCampaignRepository) FindStatus(ctx context.Context, id int64) (models.CampaignStatus, error) {
+	var status models.CampaignStatus
+	err := r.DB().NewSelect().
+		Model((*models.NotificationCampaign)(nil)).
+		Column("status").
+		Where("id = ?", id).
+		Scan(ctx, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return status, err
+}
+
+// Cancel moves a pending or published campaign to "cancelled". It reports false
+// when the campaign has another status, e.g. because it was cancelled meanwhile.
+func (r *NotificationCampaignRepository) Cancel(ctx context.Context, id int64, at time.Time) (bool, error) {
+	res, err := r.DB().NewUpdate().
+		TableExpr("notification_campaigns").
+		Set("status = ?", models.CampaignStatusCancelled).
+		Set("cancelled_at = ?", at).
+		Set("updated_at = ?", at).
+		Set("updated_by = ?", requestctx.GetUserIDAsInt64(ctx)).
+		Where("id = ?", id).
+		Where("status IN (?)", bun.In([]models.CampaignStatus{models.CampaignStatusPending, models.CampaignStatusPublished})).
+		Where("deleted_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -1,6 +1,7 @@
 package repositories_test
 
 import (
+	"ichi-go/internal/applications/notification/consumers"
 	"ichi-go/internal/applications/notification/repositories"
 	"ichi-go/internal/applications/notification/services"
 )
@@ -8,3 +9,11 @@ import (
 // Compile-time assertion: *NotificationCampaignRepository must satisfy services.CampaignRepository.
 // If the interface or the repository diverge in signature, this file will fail to compile.
 var _ services.CampaignRepository = (*repositories.NotificationCampaignRepository)(nil)
+
+// Compile-time assertions for campaign management, the dispatcher's cancellation
+// check and blast fan-out.
+var (
+	_ services.CampaignStore         = (*repositories.NotificationCampaignRepository)(nil)
+	_ consumers.CampaignStatusReader = (*repositories.NotificationCampaignRepository)(nil)
+	_ consumers.CampaignReader       = (*repositories.NotificationCampaignRepository)(nil)
+)
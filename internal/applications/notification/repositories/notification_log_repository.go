@@ -40,6 +40,9 @@ func (r *NotificationLogRepository) CreateBatch(ctx context.Context, logs []*mod
 
 // UpdateStatus updates the status, error, and sent_at timestamp of a log entry.
 // Uses raw SQL to avoid OmitZero skipping the "sent" status string.
+//
+// Only pending rows are updated: a delivery receipt that raced ahead of dispatch
+// must not be overwritten with "sent".
 func (r *NotificationLogRepository) UpdateStatus(
 	ctx context.Context,
 	id int64,
@@ -59,6 +62,135 @@ func (r *NotificationLogRepository) UpdateStatus(
 		q = q.Set("sent_at = ?", *sentAt)
 	}
 
-	_, err := q.Where("id = ?", id).Exec(ctx)
+	_, err := q.Where("id = ?", id).
+		Where("status = ?", models.LogStatusPending).
+		Exec(ctx)
+	return err
+}
+
+// SetProviderMessage records the gateway and gateway message ID of a log entry,
+// so ApplyReceipt can find it.
+func (r *NotificationLogRepository) SetProviderMessage(ctx context.Context, id int64, provider, messageID string) error {
+	_, err := r.db.NewUpdate().
+		TableExpr("notification_logs").
+		Set("provider = ?", provider).
+		Set("provider_message_id = ?", messageID).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// ApplyReceipt moves the log entry of a gateway message to status, which must be
+// LogStatusDelivered or LogStatusFailed, and returns the number of rows updated.
+//
+// Receipts arrive out of order and more than once: "delivered" is final and is
+// never downgraded, and a "delivered" receipt also fills a missing sent_at.
+func (r *NotificationLogRepository) ApplyReceipt(
+	ctx context.Context,
+	provider, messageID string,
+	status models.LogStatus,
+	errMsg string,
+	at time.Time,
+) (int64, error) {
+	q := r.db.NewUpdate().
+		TableExpr("notification_logs").
+		Set("status = ?", status).
+		Set("updated_at = ?", time.Now())
+
+	if status == models.LogStatusDelivered {
+		q = q.Set("delivered_at = ?", at).
+			Set("sent_at = COALESCE(sent_at, ?)", at)
+	}
+	if errMsg != "" {
+		q = q.Set("error = ?", errMsg)
+	}
+
+	res, err := q.Where("provider = ?", provider).
+		Where("provider_message_id = ?", messageID).
+		Where("status <> ?", models.LogStatusDelivered).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// CountByCampaign counts the log entries of a campaign per channel and status.
+func (r *NotificationLogRepository) CountByCampaign(ctx context.Context, campaignID int64) ([]models.LogStatusCount, error) {
+	var counts []models.LogStatusCount
+	err := r.db.NewSelect().
+		TableExpr("notification_logs").
+		Column("channel", "status").
+		ColumnExpr("COUNT(*) AS count").
+		Where("campaign_id = ?", campaignID).
+		Group("channel", "status").
+		Scan(ctx, &counts)
+	return counts, err
+}
+
+// ListTimings returns the sent and delivered timestamps of the sent log entries of a campaign.
+func (r *NotificationLogRepository) ListTimings(ctx context.Context, campaignID int64) ([]models.LogTiming, error) {
+	var timings []models.LogTiming
+	err := r.db.NewSelect().
+		TableExpr("notification_logs").
+		Column("channel", "sent_at", "delivered_at").
+		Where("campaign_id = ?", campaignID).
+		Where("status IN (?)", bun.In([]models.LogStatus{models.LogStatusSent, models.LogStatusDelivered})).
+		Where("sent_at IS NOT NULL").
+		Scan(ctx, &timings)
+	return timings, err
+}
+
+// ClaimFailed marks up to limit failed log entries of a campaign "retried" and
+// returns them, oldest first. Rows are locked while claimed, so concurrent
+// retries never queue the same attempt twice.
+func (r *NotificationLogRepository) ClaimFailed(ctx context.Context, campaignID int64, limit int) ([]*models.NotificationLog, error) {
+	var logs []*models.NotificationLog
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := tx.NewSelect().Model(&logs).
+			Where("campaign_id = ?", campaignID).
+			Where("status = ?", models.LogStatusFailed).
+			Order("id ASC").
+			Limit(limit).
+			For("UPDATE").
+			Scan(ctx); err != nil {
+			return err
+		}
+		if len(logs) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, len(logs))
+		for i, log := range logs {
+			ids[i] = log.ID
+		}
+		_, err := tx.NewUpdate().
+			TableExpr("notification_logs").
+			Set("status = ?", models.LogStatusRetried).
+			Set("updated_at = ?", time.Now()).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// ReleaseRetried moves log entries claimed by ClaimFailed back to "failed", when
+// queueing them again did not succeed.
+func (r *NotificationLogRepository) ReleaseRetried(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.NewUpdate().
+		TableExpr("notification_logs").
+		Set("status = ?", models.LogStatusFailed).
+		Set("updated_at = ?", time.Now()).
+		Where("id IN (?)", bun.In(ids)).
+		Where("status = ?", models.LogStatusRetried).
+		Exec(ctx)
 	return err
 }
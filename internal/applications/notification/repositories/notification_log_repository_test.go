@@ -0,0 +1,9 @@
+package repositories_test
+
+import (
+	"ichi-go/internal/applications/notification/repositories"
+	"ichi-go/internal/applications/notification/services"
+)
+
+// Compile-time assertion for the delivery statistics of campaign management.
+var _ services.CampaignLogStore = (*repositories.NotificationLogRepository)(nil)
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// NotificationPreferenceRepository stores the notification settings and opt-outs of users.
+type NotificationPreferenceRepository struct {
+	db *bun.DB
+}
+
+func NewNotificationPreferenceRepository(db *bun.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// Get returns the preferences of a user with their opt-outs, or nil, nil when the
+// user has not changed any.
+func (r *NotificationPreferenceRepository) Get(ctx context.Context, userID int64) (*models.NotificationPreference, error) {
+	pref := new(models.NotificationPreference)
+	err := r.db.NewSelect().Model(pref).Where("user_id = ?", userID).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		pref = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var optOuts []*models.NotificationOptOut
+	if err := r.db.NewSelect().Model(&optOuts).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	if pref == nil {
+		if len(optOuts) == 0 {
+			return nil, nil
+		}
+		pref = &models.NotificationPreference{UserID: userID}
+	}
+	pref.OptOuts = optOuts
+	return pref, nil
+}
+
+// Save inserts or updates the settings of pref. Its OptOuts are not written; use SetOptOut.
+func (r *NotificationPreferenceRepository) Save(ctx context.Context, pref *models.NotificationPreference) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		exists, err := tx.NewSelect().Model((*models.NotificationPreference)(nil)).
+			Where("user_id = ?", pref.UserID).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			_, err = tx.NewInsert().Model(pref).Exec(ctx)
+			return err
+		}
+
+		now := time.Now()
+		pref.UpdatedAt = &now
+		_, err = tx.NewUpdate().Model(pref).
+			Column("timezone", "quiet_hours_start", "quiet_hours_end", "locale", "unsubscribed_at", "updated_at").
+			WherePK().
+			Exec(ctx)
+		return err
+	})
+}
+
+// SetOptOut turns channel off for the event category of a user, or back on when
+// optedOut is false. Repeating a call is a no-op.
+func (r *NotificationPreferenceRepository) SetOptOut(ctx context.Context, userID int64, category, channel string, optedOut bool) error {
+	if !optedOut {
+		_, err := r.db.NewDelete().Model((*models.NotificationOptOut)(nil)).
+			Where("user_id = ?", userID).
+			Where("category = ?", category).
+			Where("channel = ?", channel).
+			Exec(ctx)
+		return err
+	}
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		exists, err := tx.NewSelect().Model((*models.NotificationOptOut)(nil)).
+			Where("user_id = ?", userID).
+			Where("category = ?", category).
+			Where("channel = ?", channel).
+			Exists(ctx)
+		if err != nil || exists {
+			return err
+		}
+		_, err = tx.NewInsert().Model(&models.NotificationOptOut{
+			UserID:   userID,
+			Category: category,
+			Channel:  channel,
+		}).Exec(ctx)
+		return err
+	})
+}
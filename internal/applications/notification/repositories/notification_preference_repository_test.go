@@ -0,0 +1,9 @@
+package repositories_test
+
+import (
+	"ichi-go/internal/applications/notification/repositories"
+	"ichi-go/internal/applications/notification/services"
+)
+
+// Compile-time assertion for the notification preference repository.
+var _ services.PreferenceRepository = (*repositories.NotificationPreferenceRepository)(nil)
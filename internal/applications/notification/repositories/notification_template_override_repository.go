@@ -24,47 +24,165 @@ func NewNotificationTemplateOverrideRepository(db *bun.DB) *NotificationTemplate
 	}
 }
 
-// FindOverride returns the active override row for (eventSlug, channel, locale).
-// Locale fallback: tries exact locale first, then falls back to "en".
+// FindOverride returns the active override of (eventSlug, channel) in the first of
+// locales that has one; pass the locale's fallback chain (notiftemplate.LocaleChain).
 // Returns nil, nil when no active override exists — this is NOT an error.
-func (r *NotificationTemplateOverrideRepository) FindOverride(ctx context.Context, eventSlug, channel, locale string) (*models.NotificationTemplateOverride, error) {
-	// Try exact locale first.
-	override, err := r.findExact(ctx, eventSlug, channel, locale)
-	if err == nil {
-		return override, nil
+func (r *NotificationTemplateOverrideRepository) FindOverride(ctx context.Context, eventSlug, channel string, locales []string) (*models.NotificationTemplateOverride, error) {
+	if len(locales) == 0 {
+		return nil, nil
 	}
-	if !errors.Is(err, sql.ErrNoRows) {
+	var overrides []*models.NotificationTemplateOverride
+	err := r.DB().NewSelect().
+		Model(&overrides).
+		Where("event_slug = ?", eventSlug).
+		Where("channel = ?", channel).
+		Where("locale IN (?)", bun.In(locales)).
+		Where("is_active = ?", true).
+		Where("deleted_at IS NULL").
+		Scan(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	// Exact locale not found — fall back to "en" (unless we already tried en).
-	if locale == "en" {
-		return nil, nil // No override; caller uses Go default.
+	for _, locale := range locales {
+		for _, o := range overrides {
+			if o.Locale == locale {
+				return o, nil
+			}
+		}
 	}
+	return nil, nil // No override; caller uses Go default.
+}
 
-	override, err = r.findExact(ctx, eventSlug, channel, "en")
+// FindByID returns an override, active or not, or nil, nil when it does not exist.
+func (r *NotificationTemplateOverrideRepository) FindByID(ctx context.Context, id int64) (*models.NotificationTemplateOverride, error) {
+	m := new(models.NotificationTemplateOverride)
+	err := r.DB().NewSelect().Model(m).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil // Still no override; that's fine.
-		}
 		return nil, err
 	}
-	return override, nil
+	return m, nil
 }
 
-func (r *NotificationTemplateOverrideRepository) findExact(ctx context.Context, eventSlug, channel, locale string) (*models.NotificationTemplateOverride, error) {
+// FindByKey returns the override of (eventSlug, channel, locale), active or not,
+// or nil, nil when there is none. Unlike FindOverride it does not fall back to other locales.
+func (r *NotificationTemplateOverrideRepository) FindByKey(ctx context.Context, eventSlug, channel, locale string) (*models.NotificationTemplateOverride, error) {
 	m := new(models.NotificationTemplateOverride)
 	err := r.DB().NewSelect().
 		Model(m).
 		Where("event_slug = ?", eventSlug).
 		Where("channel = ?", channel).
 		Where("locale = ?", locale).
-		Where("is_active = 1").
-		Where("deleted_at IS NULL").
 		Limit(1).
 		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 	return m, nil
 }
+
+// List returns the overrides matching the non-empty filters, ordered by event, channel and locale.
+func (r *NotificationTemplateOverrideRepository) List(ctx context.Context, eventSlug, channel, locale string) ([]*models.NotificationTemplateOverride, error) {
+	var overrides []*models.NotificationTemplateOverride
+	q := r.DB().NewSelect().Model(&overrides)
+	if eventSlug != "" {
+		q = q.Where("event_slug = ?", eventSlug)
+	}
+	if channel != "" {
+		q = q.Where("channel = ?", channel)
+	}
+	if locale != "" {
+		q = q.Where("locale = ?", locale)
+	}
+	err := q.Order("event_slug ASC", "channel ASC", "locale ASC").Scan(ctx)
+	return overrides, err
+}
+
+// CreateOverride inserts an override and version, its first revision, in one transaction.
+func (r *NotificationTemplateOverrideRepository) CreateOverride(
+	ctx context.Context,
+	override *models.NotificationTemplateOverride,
+	version *models.NotificationTemplateOverrideVersion,
+) error {
+	return r.DB().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// is_active has a column default: write false explicitly instead of DEFAULT.
+		if _, err := tx.NewInsert().Model(override).
+			Value("is_active", "?", override.IsActive).
+			Returning("id").
+			Exec(ctx); err != nil {
+			return err
+		}
+		version.OverrideID = override.ID
+		_, err := tx.NewInsert().Model(version).Exec(ctx)
+		return err
+	})
+}
+
+// SaveRevision writes the copy, state and revision of override and records version,
+// in one transaction. override.Revision is the revision it was read at, and becomes
+// version.Revision; false is returned, and nothing written, when another change was
+// saved in between.
+func (r *NotificationTemplateOverrideRepository) SaveRevision(
+	ctx context.Context,
+	override *models.NotificationTemplateOverride,
+	version *models.NotificationTemplateOverrideVersion,
+) (bool, error) {
+	saved := false
+	err := r.DB().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		readAt := override.Revision
+		override.Revision = version.Revision
+		res, err := tx.NewUpdate().Model(override).
+			Column("title_template", "body_template", "is_active", "revision", "versions", "updated_at", "updated_by").
+			WherePK().
+			Where("revision = ?", readAt).
+			Exec(ctx)
+		if err != nil {
+			override.Revision = readAt
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			override.Revision = readAt
+			return err
+		}
+
+		version.OverrideID = override.ID
+		if _, err := tx.NewInsert().Model(version).Exec(ctx); err != nil {
+			return err
+		}
+		saved = true
+		return nil
+	})
+	return saved, err
+}
+
+// ListVersions returns the version history of an override, newest first.
+func (r *NotificationTemplateOverrideRepository) ListVersions(ctx context.Context, overrideID int64) ([]*models.NotificationTemplateOverrideVersion, error) {
+	var versions []*models.NotificationTemplateOverrideVersion
+	err := r.DB().NewSelect().Model(&versions).
+		Where("override_id = ?", overrideID).
+		Order("revision DESC").
+		Scan(ctx)
+	return versions, err
+}
+
+// FindVersion returns one revision of an override, or nil, nil when it does not exist.
+func (r *NotificationTemplateOverrideRepository) FindVersion(ctx context.Context, overrideID int64, revision int) (*models.NotificationTemplateOverrideVersion, error) {
+	version := new(models.NotificationTemplateOverrideVersion)
+	err := r.DB().NewSelect().Model(version).
+		Where("override_id = ?", overrideID).
+		Where("revision = ?", revision).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
@@ -0,0 +1,9 @@
+package repositories_test
+
+import (
+	"ichi-go/internal/applications/notification/repositories"
+	"ichi-go/internal/applications/notification/services"
+)
+
+// Compile-time assertion for the template override repository.
+var _ services.TemplateOverrideRepository = (*repositories.NotificationTemplateOverrideRepository)(nil)
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// UserDeviceRepository stores the push notification tokens of users.
+type UserDeviceRepository struct {
+	db *bun.DB
+}
+
+func NewUserDeviceRepository(db *bun.DB) *UserDeviceRepository {
+	return &UserDeviceRepository{db: db}
+}
+
+// Register inserts a device or, when its token is already known, moves the existing
+// row to device.UserID and refreshes its platform, app version and last_seen_at.
+// Topics of a token that changes hands are cleared on the row; the caller is
+// responsible for unsubscribing the token at FCM.
+//
+// Returns the stored row and the previous owner's topics (nil for a new token or the same user).
+func (r *UserDeviceRepository) Register(ctx context.Context, device *models.UserDevice) (*models.UserDevice, []string, error) {
+	var staleTopics []string
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		existing := new(models.UserDevice)
+		err := tx.NewSelect().Model(existing).Where("token = ?", device.Token).For("UPDATE").Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			device.LastSeenAt = time.Now()
+			_, err = tx.NewInsert().Model(device).Returning("id").Exec(ctx)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if existing.UserID != device.UserID {
+			staleTopics = existing.Topics
+			existing.Topics = nil
+		}
+		existing.UserID = device.UserID
+		existing.Platform = device.Platform
+		existing.AppVersion = device.AppVersion
+		existing.LastSeenAt = now
+		existing.UpdatedAt = &now
+		_, err = tx.NewUpdate().Model(existing).
+			Column("user_id", "platform", "app_version", "topics", "last_seen_at", "updated_at").
+			WherePK().
+			Exec(ctx)
+		*device = *existing
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return device, staleTopics, nil
+}
+
+// FindByID returns nil, nil when the device does not exist.
+func (r *UserDeviceRepository) FindByID(ctx context.Context, id int64) (*models.UserDevice, error) {
+	device := new(models.UserDevice)
+	err := r.db.NewSelect().Model(device).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// ListByUser returns the devices of a user, most recently seen first.
+func (r *UserDeviceRepository) ListByUser(ctx context.Context, userID int64) ([]*models.UserDevice, error) {
+	var devices []*models.UserDevice
+	err := r.db.NewSelect().Model(&devices).
+		Where("user_id = ?", userID).
+		Order("last_seen_at DESC").
+		Scan(ctx)
+	return devices, err
+}
+
+// TokensForUser returns the push tokens of all devices of a user.
+func (r *UserDeviceRepository) TokensForUser(ctx context.Context, userID int64) ([]string, error) {
+	var tokens []string
+	err := r.db.NewSelect().
+		Model((*models.UserDevice)(nil)).
+		Column("token").
+		Where("user_id = ?", userID).
+		Scan(ctx, &tokens)
+	return tokens, err
+}
+
+// SetTopics replaces the topic subscriptions recorded for a device.
+func (r *UserDeviceRepository) SetTopics(ctx context.Context, id int64, topics []string) error {
+	device := &models.UserDevice{ID: id, Topics: topics}
+	now := time.Now()
+	device.UpdatedAt = &now
+	_, err := r.db.NewUpdate().Model(device).
+		Column("topics", "updated_at").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// Delete removes one device.
+func (r *UserDeviceRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().
+		Model((*models.UserDevice)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// DeleteTokens removes the devices holding any of the tokens, e.g. tokens FCM reported
+// as unregistered. Returns the number of devices removed.
+func (r *UserDeviceRepository) DeleteTokens(ctx context.Context, tokens []string) (int64, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+	res, err := r.db.NewDelete().
+		Model((*models.UserDevice)(nil)).
+		Where("token IN (?)", bun.In(tokens)).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/uptrace/bun"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// WebhookDeliveryRepository stores the delivery log of webhook endpoints.
+type WebhookDeliveryRepository struct {
+	db *bun.DB
+}
+
+func NewWebhookDeliveryRepository(db *bun.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+// Create inserts a delivery attempt and sets its ID.
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := r.db.NewInsert().Model(delivery).Returning("id").Exec(ctx)
+	return err
+}
+
+// FindByID returns nil, nil when the delivery does not exist.
+func (r *WebhookDeliveryRepository) FindByID(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	delivery := new(models.WebhookDelivery)
+	err := r.db.NewSelect().Model(delivery).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ListByEndpoint returns the latest delivery attempts of an endpoint, newest first.
+func (r *WebhookDeliveryRepository) ListByEndpoint(ctx context.Context, endpointID int64, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	err := r.db.NewSelect().Model(&deliveries).
+		Where("endpoint_id = ?", endpointID).
+		Order("id DESC").
+		Limit(limit).
+		Scan(ctx)
+	return deliveries, err
+}
@@ -0,0 +1,13 @@
+package repositories_test
+
+import (
+	"ichi-go/internal/applications/notification/consumers"
+	"ichi-go/internal/applications/notification/repositories"
+	"ichi-go/internal/applications/notification/services"
+)
+
+// Compile-time assertions for the webhook delivery log.
+var (
+	_ services.WebhookDeliveryRepository = (*repositories.WebhookDeliveryRepository)(nil)
+	_ consumers.WebhookDeliveryLog       = (*repositories.WebhookDeliveryRepository)(nil)
+)
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"ichi-go/internal/applications/notification/models"
+)
+
+// WebhookEndpointRepository stores the webhook endpoints of tenants.
+type WebhookEndpointRepository struct {
+	db *bun.DB
+}
+
+func NewWebhookEndpointRepository(db *bun.DB) *WebhookEndpointRepository {
+	return &WebhookEndpointRepository{db: db}
+}
+
+// Create inserts an endpoint and sets its ID.
+func (r *WebhookEndpointRepository) Create(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	_, err := r.db.NewInsert().Model(endpoint).Returning("id").Exec(ctx)
+	return err
+}
+
+// FindByID returns nil, nil when the endpoint does not exist.
+func (r *WebhookEndpointRepository) FindByID(ctx context.Context, id int64) (*models.WebhookEndpoint, error) {
+	endpoint := new(models.WebhookEndpoint)
+	err := r.db.NewSelect().Model(endpoint).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// ListByTenant returns all endpoints of a tenant, oldest first.
+func (r *WebhookEndpointRepository) ListByTenant(ctx context.Context, tenantID string) ([]*models.WebhookEndpoint, error) {
+	var endpoints []*models.WebhookEndpoint
+	err := r.db.NewSelect().Model(&endpoints).
+		Where("tenant_id = ?", tenantID).
+		Order("id ASC").
+		Scan(ctx)
+	return endpoints, err
+}
+
+// ListActiveByTenant returns the endpoints of a tenant that receive deliveries.
+func (r *WebhookEndpointRepository) ListActiveByTenant(ctx context.Context, tenantID string) ([]*models.WebhookEndpoint, error) {
+	var endpoints []*models.WebhookEndpoint
+	err := r.db.NewSelect().Model(&endpoints).
+		Where("tenant_id = ?", tenantID).
+		Where("is_active = ?", true).
+		Order("id ASC").
+		Scan(ctx)
+	return endpoints, err
+}
+
+// Update writes the given columns of endpoint, plus updated_at.
+func (r *WebhookEndpointRepository) Update(ctx context.Context, endpoint *models.WebhookEndpoint, columns ...string) error {
+	now := time.Now()
+	endpoint.UpdatedAt = &now
+	_, err := r.db.NewUpdate().Model(endpoint).
+		Column(append(columns, "updated_at")...).
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// Delete removes an endpoint and its delivery log.
+func (r *WebhookEndpointRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*models.WebhookDelivery)(nil)).
+			Where("endpoint_id = ?", id).
+			Exec(ctx); err != nil {
+			return err
+		}
+		_, err := tx.NewDelete().
+			Model((*models.WebhookEndpoint)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
+
+// RecordSuccess resets the failure count of an endpoint after a delivered attempt.
+func (r *WebhookEndpointRepository) RecordSuccess(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.WebhookEndpoint)(nil)).
+		Set("consecutive_failures = 0").
+		Set("last_success_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// RecordFailure counts a failed attempt and disables the endpoint once
+// disableAfter attempts in a row have failed (0 never disables).
+// Reports whether this call disabled the endpoint.
+func (r *WebhookEndpointRepository) RecordFailure(ctx context.Context, id int64, at time.Time, disableAfter int, reason string) (bool, error) {
+	disabled := false
+	err := r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().
+			Model((*models.WebhookEndpoint)(nil)).
+			Set("consecutive_failures = consecutive_failures + 1").
+			Set("last_failure_at = ?", at).
+			Where("id = ?", id).
+			Exec(ctx); err != nil {
+			return err
+		}
+		if disableAfter <= 0 {
+			return nil
+		}
+
+		res, err := tx.NewUpdate().
+			Model((*models.WebhookEndpoint)(nil)).
+			Set("is_active = ?", false).
+			Set("disabled_at = ?", at).
+			Set("disabled_reason = ?", reason).
+			Where("id = ?", id).
+			Where("is_active = ?", true).
+			Where("consecutive_failures >= ?", disableAfter).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		disabled = n > 0
+		return err
+	})
+	return disabled, err
+}
+
+// Disable stops deliveries to an endpoint, e.g. after it answered 410 Gone.
+func (r *WebhookEndpointRepository) Disable(ctx context.Context, id int64, at time.Time, reason string) error {
+	_, err := r.db.NewUpdate().
+		Model((*models.WebhookEndpoint)(nil)).
+		Set("is_active = ?", false).
+		Set("disabled_at = ?", at).
+		Set("disabled_reason = ?", reason).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
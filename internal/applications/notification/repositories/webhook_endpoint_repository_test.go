@@ -0,0 +1,15 @@
+package repositories_test
+
+import (
+	"ichi-go/internal/applications/notification/channels"
+	"ichi-go/internal/applications/notification/consumers"
+	"ichi-go/internal/applications/notification/repositories"
+	"ichi-go/internal/applications/notification/services"
+)
+
+// Compile-time assertions for the webhook endpoint repository and the interfaces of its consumers.
+var (
+	_ services.WebhookEndpointRepository = (*repositories.WebhookEndpointRepository)(nil)
+	_ channels.WebhookEndpointLister     = (*repositories.WebhookEndpointRepository)(nil)
+	_ consumers.WebhookEndpointStore     = (*repositories.WebhookEndpointRepository)(nil)
+)
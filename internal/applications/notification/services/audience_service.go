@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	rbacConstants "ichi-go/internal/applications/rbac/constants"
+	"ichi-go/pkg/authenticator"
+	pkgErrors "ichi-go/pkg/errors"
+)
+
+const (
+	maxSegmentRoles         = 20
+	maxRoleSlugLength       = 100
+	maxAttributes           = 50
+	maxAttributeNameLength  = 64
+	maxAttributeValueLength = 255
+)
+
+// AudienceStore is the persistence AudienceService uses.
+// The concrete *repositories.AudienceRepository satisfies this interface.
+type AudienceStore interface {
+	Count(ctx context.Context, segment *models.AudienceSegment) (int, error)
+	ListAttributes(ctx context.Context, userID int64) ([]*models.UserAttribute, error)
+	ReplaceAttributes(ctx context.Context, userID int64, attrs map[string]string) error
+}
+
+// AudienceOptions holds the optional dependencies and settings of AudienceService.
+type AudienceOptions struct {
+	Permissions   PermissionChecker // nil when RBAC is unavailable; all operations are then denied
+	DefaultTenant string            // tenant of requests without tenant context
+}
+
+// AudienceService manages the custom user attributes that blast segments match on,
+// and previews how many users a segment reaches. Every operation requires campaigns:manage.
+type AudienceService struct {
+	store AudienceStore
+	opts  AudienceOptions
+}
+
+func NewAudienceService(store AudienceStore, opts AudienceOptions) *AudienceService {
+	return &AudienceService{store: store, opts: opts}
+}
+
+// Preview counts the users segment matches.
+func (s *AudienceService) Preview(ctx context.Context, authCtx authenticator.AuthContext, segment models.AudienceSegment) (*dto.AudiencePreviewResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	if err := validateSegment(&segment); err != nil {
+		return nil, pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+			Hint("Fix the segment: " + err.Error()).
+			Wrap(err)
+	}
+
+	users, err := s.store.Count(ctx, &segment)
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "count_audience").
+			Wrap(err)
+	}
+	return &dto.AudiencePreviewResponse{Users: users}, nil
+}
+
+// GetAttributes returns the custom attributes of a user.
+func (s *AudienceService) GetAttributes(ctx context.Context, authCtx authenticator.AuthContext, userID int64) (*dto.UserAttributesResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	return s.attributes(ctx, userID)
+}
+
+// SetAttributes replaces the custom attributes of a user.
+func (s *AudienceService) SetAttributes(ctx context.Context, authCtx authenticator.AuthContext, userID int64, req dto.SetUserAttributesRequest) (*dto.UserAttributesResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	if err := validateAttributes(req.Attributes); err != nil {
+		return nil, pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+			With("user_id", userID).
+			Hint("Fix the attributes: " + err.Error()).
+			Wrap(err)
+	}
+
+	if err := s.store.ReplaceAttributes(ctx, userID, req.Attributes); err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "replace_user_attributes").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return s.attributes(ctx, userID)
+}
+
+func (s *AudienceService) attributes(ctx context.Context, userID int64) (*dto.UserAttributesResponse, error) {
+	attrs, err := s.store.ListAttributes(ctx, userID)
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "list_user_attributes").
+			With("user_id", userID).
+			Wrap(err)
+	}
+
+	resp := &dto.UserAttributesResponse{UserID: userID, Attributes: make(map[string]string, len(attrs))}
+	for _, attr := range attrs {
+		resp.Attributes[attr.Name] = attr.Value
+	}
+	return resp, nil
+}
+
+func (s *AudienceService) authorize(ctx context.Context, authCtx authenticator.AuthContext) error {
+	_, err := authorizePermission(ctx, authCtx, s.opts.Permissions, s.opts.DefaultTenant,
+		rbacConstants.CampaignsManage, "notification audiences")
+	return err
+}
+
+// validateSegment checks the limits of a blast segment. Errors start with "segment"
+// so the send API reports them as validation errors.
+func validateSegment(segment *models.AudienceSegment) error {
+	if segment == nil {
+		return nil
+	}
+	if len(segment.Roles) > maxSegmentRoles {
+		return fmt.Errorf("segment.roles must not list more than %d roles", maxSegmentRoles)
+	}
+	for _, role := range segment.Roles {
+		if role == "" || len(role) > maxRoleSlugLength {
+			return fmt.Errorf("segment.roles must be role slugs of 1 to %d characters", maxRoleSlugLength)
+		}
+	}
+	if len(segment.TenantID) > maxRoleSlugLength {
+		return fmt.Errorf("segment.tenant_id must not exceed %d characters", maxRoleSlugLength)
+	}
+	if segment.CreatedFrom != nil && segment.CreatedTo != nil && !segment.CreatedFrom.Before(*segment.CreatedTo) {
+		return fmt.Errorf("segment.created_from must be before segment.created_to")
+	}
+	if err := validateAttributes(segment.Attributes); err != nil {
+		return fmt.Errorf("segment.%w", err)
+	}
+	return nil
+}
+
+// validateAttributes checks the names and values of custom user attributes.
+func validateAttributes(attrs map[string]string) error {
+	if len(attrs) > maxAttributes {
+		return fmt.Errorf("attributes must not have more than %d entries", maxAttributes)
+	}
+	for name, value := range attrs {
+		if name == "" || len(name) > maxAttributeNameLength {
+			return fmt.Errorf("attributes names must have 1 to %d characters", maxAttributeNameLength)
+		}
+		if len(value) > maxAttributeValueLength {
+			return fmt.Errorf("attributes.%s must not exceed %d characters", name, maxAttributeValueLength)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	pkgErrors "ichi-go/pkg/errors"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockAudienceStore struct {
+	mock.Mock
+}
+
+func (m *mockAudienceStore) Count(ctx context.Context, segment *models.AudienceSegment) (int, error) {
+	args := m.Called(ctx, segment)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockAudienceStore) ListAttributes(ctx context.Context, userID int64) ([]*models.UserAttribute, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*models.UserAttribute), args.Error(1)
+}
+
+func (m *mockAudienceStore) ReplaceAttributes(ctx context.Context, userID int64, attrs map[string]string) error {
+	return m.Called(ctx, userID, attrs).Error(0)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func setupAudienceSvc(allowed bool) (*AudienceService, *mockAudienceStore, *mockPermissionChecker) {
+	store := new(mockAudienceStore)
+	permissions := new(mockPermissionChecker)
+	permissions.On("CheckPermission", mock.Anything, int64(42), mock.Anything, "campaigns", "manage").Return(allowed, nil)
+	svc := NewAudienceService(store, AudienceOptions{Permissions: permissions, DefaultTenant: "system"})
+	return svc, store, permissions
+}
+
+// ============================================================================
+// Preview
+// ============================================================================
+
+func TestAudienceService_PreviewRequiresPermission(t *testing.T) {
+	svc, store, _ := setupAudienceSvc(false)
+
+	_, err := svc.Preview(context.Background(), campaignAuth(), models.AudienceSegment{})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+	store.AssertNotCalled(t, "Count", mock.Anything, mock.Anything)
+}
+
+func TestAudienceService_PreviewCountsSegment(t *testing.T) {
+	svc, store, _ := setupAudienceSvc(true)
+	segment := models.AudienceSegment{Roles: []string{"customer"}, TenantID: "acme", Attributes: map[string]string{"plan": "pro"}}
+	store.On("Count", mock.Anything, &segment).Return(1280, nil)
+
+	preview, err := svc.Preview(context.Background(), campaignAuth(), segment)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1280, preview.Users)
+}
+
+func TestAudienceService_PreviewRejectsInvalidSegment(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	tests := []struct {
+		name    string
+		segment models.AudienceSegment
+	}{
+		{"empty role", models.AudienceSegment{Roles: []string{""}}},
+		{"inverted created range", models.AudienceSegment{CreatedFrom: &from, CreatedTo: &to}},
+		{"empty attribute name", models.AudienceSegment{Attributes: map[string]string{"": "x"}}},
+		{"long attribute value", models.AudienceSegment{Attributes: map[string]string{"plan": strings.Repeat("x", 256)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, _ := setupAudienceSvc(true)
+
+			_, err := svc.Preview(context.Background(), campaignAuth(), tt.segment)
+
+			assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+			store.AssertNotCalled(t, "Count", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// ============================================================================
+// Attributes
+// ============================================================================
+
+func TestAudienceService_SetAttributesReplacesAndReadsBack(t *testing.T) {
+	svc, store, _ := setupAudienceSvc(true)
+	attrs := map[string]string{"plan": "pro", "country": "ID"}
+	store.On("ReplaceAttributes", mock.Anything, int64(9), attrs).Return(nil)
+	store.On("ListAttributes", mock.Anything, int64(9)).Return([]*models.UserAttribute{
+		{UserID: 9, Name: "country", Value: "ID"},
+		{UserID: 9, Name: "plan", Value: "pro"},
+	}, nil)
+
+	resp, err := svc.SetAttributes(context.Background(), campaignAuth(), 9, dto.SetUserAttributesRequest{Attributes: attrs})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), resp.UserID)
+	assert.Equal(t, attrs, resp.Attributes)
+}
+
+func TestAudienceService_SetAttributesRejectsTooMany(t *testing.T) {
+	svc, store, _ := setupAudienceSvc(true)
+	attrs := make(map[string]string, maxAttributes+1)
+	for i := 0; i <= maxAttributes; i++ {
+		attrs[strings.Repeat("a", i+1)] = "x"
+	}
+
+	_, err := svc.SetAttributes(context.Background(), campaignAuth(), 9, dto.SetUserAttributesRequest{Attributes: attrs})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+	store.AssertNotCalled(t, "ReplaceAttributes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAudienceService_GetAttributesDatabaseError(t *testing.T) {
+	svc, store, _ := setupAudienceSvc(true)
+	store.On("ListAttributes", mock.Anything, int64(9)).Return([]*models.UserAttribute(nil), errors.New("db down"))
+
+	_, err := svc.GetAttributes(context.Background(), campaignAuth(), 9)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeDatabase)
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	rbacConstants "ichi-go/internal/applications/rbac/constants"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/authenticator"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+)
+
+const (
+	defaultCampaignPageSize = 20
+
+	// retryBatchSize is the number of failed log entries claimed per round by RetryFailed.
+	retryBatchSize = 500
+)
+
+// CampaignStore is the campaign persistence CampaignAdminService uses.
+// The concrete *repositories.NotificationCampaignRepository satisfies this interface.
+type CampaignStore interface {
+	FindByID(ctx context.Context, id int64) (*models.NotificationCampaign, error)
+	List(ctx context.Context, filter models.CampaignFilter, beforeID int64, limit int) ([]*models.NotificationCampaign, error)
+	Cancel(ctx context.Context, id int64, at time.Time) (bool, error)
+}
+
+// CampaignLogStore is the delivery log persistence CampaignAdminService uses.
+// The concrete *repositories.NotificationLogRepository satisfies this interface.
+type CampaignLogStore interface {
+	CountByCampaign(ctx context.Context, campaignID int64) ([]models.LogStatusCount, error)
+	ListTimings(ctx context.Context, campaignID int64) ([]models.LogTiming, error)
+	ClaimFailed(ctx context.Context, campaignID int64, limit int) ([]*models.NotificationLog, error)
+	ReleaseRetried(ctx context.Context, ids []int64) error
+}
+
+// CampaignAdminOptions holds the optional dependencies and settings of CampaignAdminService.
+type CampaignAdminOptions struct {
+	Permissions   PermissionChecker // nil when RBAC is unavailable; all operations are then denied
+	DefaultTenant string            // platform tenant campaigns:manage is checked in
+}
+
+// CampaignAdminService reads back the campaigns created by CampaignService.Send
+// and their delivery logs: listing, cancellation, delivery statistics and
+// re-queueing of failed deliveries. Campaigns are not tenant-scoped, so every
+// operation requires campaigns:manage in the platform (default) tenant.
+type CampaignAdminService struct {
+	campaigns CampaignStore
+	logs      CampaignLogStore
+	producer  rabbitmq.MessageProducer // app.events exchange; nil disables retries
+	opts      CampaignAdminOptions
+	now       func() time.Time
+}
+
+func NewCampaignAdminService(
+	campaigns CampaignStore,
+	logs CampaignLogStore,
+	producer rabbitmq.MessageProducer,
+	opts CampaignAdminOptions,
+) *CampaignAdminService {
+	return &CampaignAdminService{
+		campaigns: campaigns,
+		logs:      logs,
+		producer:  producer,
+		opts:      opts,
+		now:       time.Now,
+	}
+}
+
+// List returns one page of campaigns matching req, newest first.
+func (s *CampaignAdminService) List(ctx context.Context, authCtx authenticator.AuthContext, req dto.ListCampaignsRequest) (*dto.CampaignPage, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+
+	var beforeID int64
+	if req.Cursor != "" {
+		id, err := strconv.ParseInt(req.Cursor, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+				With("cursor", req.Cursor).
+				Hint("Invalid cursor; use the next_cursor of the previous page").
+				Errorf("invalid campaign cursor")
+		}
+		beforeID = id
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultCampaignPageSize
+	}
+
+	filter := models.CampaignFilter{
+		Status:       models.CampaignStatus(req.Status),
+		DeliveryMode: req.DeliveryMode,
+		EventSlug:    req.EventSlug,
+		CreatedFrom:  req.CreatedFrom,
+		CreatedTo:    req.CreatedTo,
+	}
+	items, err := s.campaigns.List(ctx, filter, beforeID, limit)
+	if err != nil {
+		return nil, campaignDatabaseError("list_campaigns", 0, err)
+	}
+
+	page := &dto.CampaignPage{Items: items}
+	if page.Items == nil {
+		page.Items = []*models.NotificationCampaign{}
+	}
+	if len(items) == limit {
+		page.NextCursor = strconv.FormatInt(items[len(items)-1].ID, 10)
+	}
+	return page, nil
+}
+
+// Get returns one campaign.
+func (s *CampaignAdminService) Get(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*models.NotificationCampaign, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	return s.findCampaign(ctx, id)
+}
+
+// Cancel stops a scheduled campaign that has not reached its delivery time. Its
+// messages stay in the delay queue; the dispatcher drops them when they come due.
+func (s *CampaignAdminService) Cancel(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*models.NotificationCampaign, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	campaign, err := s.findCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	cancelable := campaign.Status == models.CampaignStatusPending || campaign.Status == models.CampaignStatusPublished
+	if !cancelable || !campaign.DeliverAt().After(now) {
+		return nil, campaignNotCancelableError(campaign)
+	}
+
+	cancelled, err := s.campaigns.Cancel(ctx, id, now)
+	if err != nil {
+		return nil, campaignDatabaseError("cancel_campaign", id, err)
+	}
+	if !cancelled {
+		return nil, campaignNotCancelableError(campaign)
+	}
+
+	campaign.Status = models.CampaignStatusCancelled
+	campaign.CancelledAt = &now
+	return campaign, nil
+}
+
+// Stats reports the delivery attempts of a campaign per channel: counts by status
+// and latency percentiles of sends and provider-confirmed deliveries.
+func (s *CampaignAdminService) Stats(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*dto.CampaignStatsResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	campaign, err := s.findCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.logs.CountByCampaign(ctx, id)
+	if err != nil {
+		return nil, campaignDatabaseError("count_campaign_logs", id, err)
+	}
+	timings, err := s.logs.ListTimings(ctx, id)
+	if err != nil {
+		return nil, campaignDatabaseError("list_campaign_log_timings", id, err)
+	}
+
+	deliverAt := campaign.DeliverAt()
+	byChannel := make(map[string]*dto.ChannelStats)
+	channelStats := func(channel string) *dto.ChannelStats {
+		if cs, ok := byChannel[channel]; ok {
+			return cs
+		}
+		cs := &dto.ChannelStats{Channel: channel}
+		byChannel[channel] = cs
+		return cs
+	}
+
+	resp := &dto.CampaignStatsResponse{CampaignID: id, Status: campaign.Status, DeliverAt: deliverAt}
+	for _, c := range counts {
+		addLogCount(&channelStats(c.Channel).Counts, c.Status, c.Count)
+		addLogCount(&resp.Totals, c.Status, c.Count)
+	}
+
+	sendLatency := make(map[string][]time.Duration)
+	deliveryLatency := make(map[string][]time.Duration)
+	for _, t := range timings {
+		channelStats(t.Channel)
+		sendLatency[t.Channel] = append(sendLatency[t.Channel], nonNegative(t.SentAt.Sub(deliverAt)))
+		if t.DeliveredAt != nil {
+			deliveryLatency[t.Channel] = append(deliveryLatency[t.Channel], nonNegative(t.DeliveredAt.Sub(t.SentAt)))
+		}
+	}
+
+	resp.Channels = make([]dto.ChannelStats, 0, len(byChannel))
+	for channel, cs := range byChannel {
+		cs.SendLatency = latencyStats(sendLatency[channel])
+		cs.DeliveryLatency = latencyStats(deliveryLatency[channel])
+		resp.Channels = append(resp.Channels, *cs)
+	}
+	sort.Slice(resp.Channels, func(i, j int) bool { return resp.Channels[i].Channel < resp.Channels[j].Channel })
+	return resp, nil
+}
+
+// RetryFailed queues the failed delivery attempts of a campaign again, and only
+// those: each user gets one message for the channels that failed for them. The
+// failed log entries become "retried" and the new attempts are logged as usual.
+func (s *CampaignAdminService) RetryFailed(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*dto.RetryFailedResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	campaign, err := s.findCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.CampaignStatusPublished && campaign.Status != models.CampaignStatusProcessing {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeCampaignNotRetryable).
+			With("campaign_id", id).
+			With("status", campaign.Status).
+			Hint("Only published campaigns have deliveries to retry").
+			Errorf("campaign is %s", campaign.Status)
+	}
+	if s.producer == nil {
+		return nil, pkgErrors.Queue(pkgErrors.ErrCodeQueue).
+			Hint("The message queue is unavailable").
+			Errorf("campaign retry: queue unavailable")
+	}
+
+	resp := &dto.RetryFailedResponse{CampaignID: id}
+	for {
+		logs, err := s.logs.ClaimFailed(ctx, id, retryBatchSize)
+		if err != nil {
+			return nil, campaignDatabaseError("claim_failed_logs", id, err)
+		}
+		if len(logs) == 0 {
+			break
+		}
+
+		messages, err := s.requeue(ctx, campaign, logs)
+		resp.Messages += messages
+		if err != nil {
+			return nil, err
+		}
+		resp.Retried += len(logs)
+		if len(logs) < retryBatchSize {
+			break
+		}
+	}
+
+	logger.Infof("[campaign] retried campaign_id=%d attempts=%d messages=%d", id, resp.Retried, resp.Messages)
+	return resp, nil
+}
+
+// requeue publishes one event per user of the claimed failed logs, for the channels
+// that failed. Logs whose event could not be published are released to "failed".
+func (s *CampaignAdminService) requeue(ctx context.Context, campaign *models.NotificationCampaign, logs []*models.NotificationLog) (int, error) {
+	type retry struct {
+		userID   int64
+		channels []dto.Channel
+		logIDs   []int64
+	}
+	var retries []*retry
+	byUser := make(map[int64]*retry)
+	for _, log := range logs {
+		r, ok := byUser[log.UserID]
+		if !ok {
+			r = &retry{userID: log.UserID}
+			byUser[log.UserID] = r
+			retries = append(retries, r)
+		}
+		if !containsChannel(r.channels, dto.Channel(log.Channel)) {
+			r.channels = append(r.channels, dto.Channel(log.Channel))
+		}
+		r.logIDs = append(r.logIDs, log.ID)
+	}
+
+	meta := campaignEventMeta(campaign.Meta, campaign.ID)
+	opts := rabbitmq.PublishOptions{Headers: campaignHeaders(campaign)}
+	for i, r := range retries {
+		// The first log ID keeps the event ID unique per retry, past the consumers' idempotency guard.
+		event := dto.NotificationEvent{
+			EventID:      fmt.Sprintf("campaign-%d-blast-retry-%d", campaign.ID, r.logIDs[0]),
+			EventType:    campaign.EventSlug,
+			DeliveryMode: dto.DeliveryModeBlast,
+			Channels:     r.channels,
+			Locale:       campaign.Locale,
+			Data:         campaign.Data,
+			Meta:         meta,
+		}
+		if r.userID != 0 {
+			event.EventID = fmt.Sprintf("campaign-%d-user-%d-retry-%d", campaign.ID, r.userID, r.logIDs[0])
+			event.DeliveryMode = dto.DeliveryModeUser
+			event.UserID = strconv.FormatInt(r.userID, 10)
+		}
+
+		if err := s.producer.Publish(ctx, dispatchRoutingKey, event, opts); err != nil {
+			var unpublished []int64
+			for _, rest := range retries[i:] {
+				unpublished = append(unpublished, rest.logIDs...)
+			}
+			if releaseErr := s.logs.ReleaseRetried(ctx, unpublished); releaseErr != nil {
+				logger.Errorf("[campaign] releasing retried logs failed campaign_id=%d: %v", campaign.ID, releaseErr)
+			}
+			return i, pkgErrors.Queue(pkgErrors.ErrCodeQueue).
+				With("campaign_id", campaign.ID).
+				Hint("Queueing the retry failed; retry again later").
+				Wrap(err)
+		}
+	}
+	return len(retries), nil
+}
+
+func (s *CampaignAdminService) authorize(ctx context.Context, authCtx authenticator.AuthContext) error {
+	return authorizePlatformPermission(ctx, authCtx, s.opts.Permissions, s.opts.DefaultTenant,
+		rbacConstants.CampaignsManage, "notification campaigns")
+}
+
+func (s *CampaignAdminService) findCampaign(ctx context.Context, id int64) (*models.NotificationCampaign, error) {
+	campaign, err := s.campaigns.FindByID(ctx, id)
+	if err != nil {
+		return nil, campaignDatabaseError("find_campaign", id, err)
+	}
+	if campaign == nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeNotFound).
+			With("campaign_id", id).
+			Hint("Campaign not found").
+			Errorf("campaign not found")
+	}
+	return campaign, nil
+}
+
+// addLogCount adds n log entries with status to counts. Delivered entries were sent too.
+func addLogCount(counts *dto.LogStatusCounts, status models.LogStatus, n int64) {
+	switch status {
+	case models.LogStatusPending:
+		counts.Pending += n
+	case models.LogStatusSent:
+		counts.Sent += n
+	case models.LogStatusDelivered:
+		counts.Sent += n
+		counts.Delivered += n
+	case models.LogStatusFailed:
+		counts.Failed += n
+	case models.LogStatusSkipped:
+		counts.Skipped += n
+	case models.LogStatusRetried:
+		counts.Retried += n
+	}
+}
+
+// latencyStats returns the nearest-rank percentiles of durations, or nil when there are none.
+func latencyStats(durations []time.Duration) *dto.LatencyStats {
+	if len(durations) == 0 {
+		return nil
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	percentile := func(p int) int64 {
+		rank := (p*len(durations) + 99) / 100 // ceil(p/100 * n)
+		return durations[rank-1].Milliseconds()
+	}
+	return &dto.LatencyStats{
+		Count: len(durations),
+		P50Ms: percentile(50),
+		P90Ms: percentile(90),
+		P99Ms: percentile(99),
+		MaxMs: durations[len(durations)-1].Milliseconds(),
+	}
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func containsChannel(channels []dto.Channel, channel dto.Channel) bool {
+	for _, ch := range channels {
+		if ch == channel {
+			return true
+		}
+	}
+	return false
+}
+
+func campaignNotCancelableError(campaign *models.NotificationCampaign) error {
+	return pkgErrors.NotificationService(pkgErrors.ErrCodeCampaignNotCancelable).
+		With("campaign_id", campaign.ID).
+		With("status", campaign.Status).
+		Hint("Only scheduled campaigns can be cancelled, before their delivery time").
+		Errorf("campaign cannot be cancelled")
+}
+
+func campaignDatabaseError(operation string, campaignID int64, err error) error {
+	return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+		With("operation", operation).
+		With("campaign_id", campaignID).
+		Wrap(err)
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/pkg/authenticator"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/requestctx"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockCampaignStore struct {
+	mock.Mock
+}
+
+func (m *mockCampaignStore) FindByID(ctx context.Context, id int64) (*models.NotificationCampaign, error) {
+	args := m.Called(ctx, id)
+	if c := args.Get(0); c != nil {
+		return c.(*models.NotificationCampaign), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockCampaignStore) List(ctx context.Context, filter models.CampaignFilter, beforeID int64, limit int) ([]*models.NotificationCampaign, error) {
+	args := m.Called(ctx, filter, beforeID, limit)
+	return args.Get(0).([]*models.NotificationCampaign), args.Error(1)
+}
+
+func (m *mockCampaignStore) Cancel(ctx context.Context, id int64, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, at)
+	return args.Bool(0), args.Error(1)
+}
+
+type mockCampaignLogStore struct {
+	mock.Mock
+}
+
+func (m *mockCampaignLogStore) CountByCampaign(ctx context.Context, campaignID int64) ([]models.LogStatusCount, error) {
+	args := m.Called(ctx, campaignID)
+	return args.Get(0).([]models.LogStatusCount), args.Error(1)
+}
+
+func (m *mockCampaignLogStore) ListTimings(ctx context.Context, campaignID int64) ([]models.LogTiming, error) {
+	args := m.Called(ctx, campaignID)
+	return args.Get(0).([]models.LogTiming), args.Error(1)
+}
+
+func (m *mockCampaignLogStore) ClaimFailed(ctx context.Context, campaignID int64, limit int) ([]*models.NotificationLog, error) {
+	args := m.Called(ctx, campaignID, limit)
+	return args.Get(0).([]*models.NotificationLog), args.Error(1)
+}
+
+func (m *mockCampaignLogStore) ReleaseRetried(ctx context.Context, ids []int64) error {
+	return m.Called(ctx, ids).Error(0)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+var campaignTestNow = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+type campaignAdminMocks struct {
+	campaigns   *mockCampaignStore
+	logs        *mockCampaignLogStore
+	producer    *mockProducer
+	permissions *mockPermissionChecker
+}
+
+func setupCampaignAdminSvc(allowed bool) (*CampaignAdminService, *campaignAdminMocks) {
+	m := &campaignAdminMocks{
+		campaigns:   new(mockCampaignStore),
+		logs:        new(mockCampaignLogStore),
+		producer:    new(mockProducer),
+		permissions: new(mockPermissionChecker),
+	}
+	m.permissions.On("CheckPermission", mock.Anything, int64(42), mock.Anything, "campaigns", "manage").Return(allowed, nil)
+	svc := NewCampaignAdminService(m.campaigns, m.logs, m.producer, CampaignAdminOptions{
+		Permissions:   m.permissions,
+		DefaultTenant: "system",
+	})
+	svc.now = func() time.Time { return campaignTestNow }
+	return svc, m
+}
+
+func campaignAuth() authenticator.AuthContext {
+	return authenticator.AuthContext{UserID: authenticator.UserSubject{ID: 42}}
+}
+
+func storedCampaign(status models.CampaignStatus, scheduledAt time.Time) *models.NotificationCampaign {
+	c := &models.NotificationCampaign{
+		DeliveryMode: string(dto.DeliveryModeUser),
+		EventSlug:    "order.shipped",
+		Channels:     []string{"email", "push"},
+		Locale:       "en",
+		Data:         map[string]any{"order_id": "A-1"},
+		Meta:         map[string]string{"tenant_id": "acme"},
+		Status:       status,
+	}
+	c.ID = 7
+	c.CreatedAt = scheduledAt.Add(-time.Hour)
+	if !scheduledAt.IsZero() {
+		c.ScheduledAt = bun.NullTime{Time: scheduledAt}
+	}
+	return c
+}
+
+func failedLog(id, userID int64, channel string) *models.NotificationLog {
+	return &models.NotificationLog{ID: id, CampaignID: 7, UserID: userID, Channel: channel, Status: models.LogStatusFailed}
+}
+
+// ============================================================================
+// List / authorization
+// ============================================================================
+
+func TestCampaignAdminService_RequiresPermission(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(false)
+
+	_, err := svc.List(context.Background(), campaignAuth(), dto.ListCampaignsRequest{})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+	m.permissions.AssertCalled(t, "CheckPermission", mock.Anything, int64(42), "system", "campaigns", "manage")
+	m.campaigns.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCampaignAdminService_ChecksPermissionInPlatformTenant(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(false)
+	ctx := requestctx.SetTenantID(context.Background(), "acme")
+
+	_, err := svc.Cancel(ctx, campaignAuth(), 7)
+
+	// Campaigns span tenants: a campaigns:manage grant in acme is not enough.
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+	m.permissions.AssertCalled(t, "CheckPermission", mock.Anything, int64(42), "system", "campaigns", "manage")
+	m.permissions.AssertNotCalled(t, "CheckPermission", mock.Anything, int64(42), "acme", "campaigns", "manage")
+	m.campaigns.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestCampaignAdminService_ListPagesWithCursor(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(true)
+	first, second := storedCampaign(models.CampaignStatusPublished, campaignTestNow), storedCampaign(models.CampaignStatusPublished, campaignTestNow)
+	first.ID, second.ID = 30, 29
+	filter := models.CampaignFilter{Status: models.CampaignStatusPublished, EventSlug: "order.shipped"}
+	m.campaigns.On("List", mock.Anything, filter, int64(31), 2).Return([]*models.NotificationCampaign{first, second}, nil)
+
+	page, err := svc.List(context.Background(), campaignAuth(), dto.ListCampaignsRequest{
+		Status:    "published",
+		EventSlug: "order.shipped",
+		Cursor:    "31",
+		Limit:     2,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "29", page.NextCursor)
+}
+
+func TestCampaignAdminService_ListRejectsInvalidCursor(t *testing.T) {
+	svc, _ := setupCampaignAdminSvc(true)
+
+	_, err := svc.List(context.Background(), campaignAuth(), dto.ListCampaignsRequest{Cursor: "abc"})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+}
+
+func TestCampaignAdminService_GetNotFound(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(true)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(nil, nil)
+
+	_, err := svc.Get(context.Background(), campaignAuth(), 7)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+}
+
+// ============================================================================
+// Cancel
+// ============================================================================
+
+func TestCampaignAdminService_CancelScheduledCampaign(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(true)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(storedCampaign(models.CampaignStatusPublished, campaignTestNow.Add(time.Hour)), nil)
+	m.campaigns.On("Cancel", mock.Anything, int64(7), campaignTestNow).Return(true, nil)
+
+	campaign, err := svc.Cancel(context.Background(), campaignAuth(), 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.CampaignStatusCancelled, campaign.Status)
+	require.NotNil(t, campaign.CancelledAt)
+	assert.Equal(t, campaignTestNow, *campaign.CancelledAt)
+}
+
+func TestCampaignAdminService_CancelRejectsDeliveredOrFinishedCampaigns(t *testing.T) {
+	delay := uint32(60)
+	delayed := storedCampaign(models.CampaignStatusPublished, time.Time{})
+	publishedAt := campaignTestNow.Add(-2 * time.Minute)
+	delayed.PublishedAt = &publishedAt
+	delayed.DelaySeconds = &delay
+
+	tests := []struct {
+		name     string
+		campaign *models.NotificationCampaign
+	}{
+		{"schedule passed", storedCampaign(models.CampaignStatusPublished, campaignTestNow.Add(-time.Minute))},
+		{"delay elapsed", delayed},
+		{"failed", storedCampaign(models.CampaignStatusFailed, campaignTestNow.Add(time.Hour))},
+		{"already cancelled", storedCampaign(models.CampaignStatusCancelled, campaignTestNow.Add(time.Hour))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupCampaignAdminSvc(true)
+			m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(tt.campaign, nil)
+
+			_, err := svc.Cancel(context.Background(), campaignAuth(), 7)
+
+			assertErrorCode(t, err, pkgErrors.ErrCodeCampaignNotCancelable)
+			m.campaigns.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCampaignAdminService_CancelLosesRace(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(true)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(storedCampaign(models.CampaignStatusPublished, campaignTestNow.Add(time.Hour)), nil)
+	m.campaigns.On("Cancel", mock.Anything, int64(7), campaignTestNow).Return(false, nil)
+
+	_, err := svc.Cancel(context.Background(), campaignAuth(), 7)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeCampaignNotCancelable)
+}
+
+// ============================================================================
+// Stats
+// ============================================================================
+
+func TestCampaignAdminService_StatsPerChannel(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(true)
+	deliverAt := campaignTestNow.Add(-time.Hour)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(storedCampaign(models.CampaignStatusPublished, deliverAt), nil)
+	m.logs.On("CountByCampaign", mock.Anything, int64(7)).Return([]models.LogStatusCount{
+		{Channel: "sms", Status: models.LogStatusSent, Count: 3},
+		{Channel: "sms", Status: models.LogStatusDelivered, Count: 1},
+		{Channel: "sms", Status: models.LogStatusFailed, Count: 2},
+		{Channel: "email", Status: models.LogStatusSkipped, Count: 5},
+		{Channel: "email", Status: models.LogStatusRetried, Count: 1},
+	}, nil)
+
+	var timings []models.LogTiming
+	for i := 1; i <= 4; i++ {
+		sentAt := deliverAt.Add(time.Duration(i) * time.Second)
+		timing := models.LogTiming{Channel: "sms", SentAt: sentAt}
+		if i == 4 {
+			deliveredAt := sentAt.Add(3 * time.Second)
+			timing.DeliveredAt = &deliveredAt
+		}
+		timings = append(timings, timing)
+	}
+	m.logs.On("ListTimings", mock.Anything, int64(7)).Return(timings, nil)
+
+	stats, err := svc.Stats(context.Background(), campaignAuth(), 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, dto.LogStatusCounts{Sent: 4, Delivered: 1, Failed: 2, Skipped: 5, Retried: 1}, stats.Totals)
+	require.Len(t, stats.Channels, 2)
+
+	email, sms := stats.Channels[0], stats.Channels[1]
+	assert.Equal(t, "email", email.Channel)
+	assert.Equal(t, int64(5), email.Counts.Skipped)
+	assert.Nil(t, email.SendLatency)
+
+	assert.Equal(t, "sms", sms.Channel)
+	assert.Equal(t, dto.LogStatusCounts{Sent: 4, Delivered: 1, Failed: 2}, sms.Counts)
+	require.NotNil(t, sms.SendLatency)
+	assert.Equal(t, dto.LatencyStats{Count: 4, P50Ms: 2000, P90Ms: 4000, P99Ms: 4000, MaxMs: 4000}, *sms.SendLatency)
+	require.NotNil(t, sms.DeliveryLatency)
+	assert.Equal(t, int64(3000), sms.DeliveryLatency.P50Ms)
+}
+
+// ============================================================================
+// Retry failed
+// ============================================================================
+
+func TestCampaignAdminService_RetryFailedGroupsChannelsPerUser(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(true)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(storedCampaign(models.CampaignStatusPublished, campaignTestNow.Add(-time.Hour)), nil)
+	m.logs.On("ClaimFailed", mock.Anything, int64(7), retryBatchSize).Return([]*models.NotificationLog{
+		failedLog(100, 1, "email"),
+		failedLog(101, 2, "push"),
+		failedLog(102, 1, "push"),
+	}, nil)
+
+	var events []dto.NotificationEvent
+	m.producer.On("Publish", mock.Anything, dispatchRoutingKey, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		events = append(events, e)
+		return true
+	}), mock.Anything).Return(nil)
+
+	resp, err := svc.RetryFailed(context.Background(), campaignAuth(), 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Retried)
+	assert.Equal(t, 2, resp.Messages)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "campaign-7-user-1-retry-100", events[0].EventID)
+	assert.Equal(t, dto.DeliveryModeUser, events[0].DeliveryMode)
+	assert.Equal(t, "1", events[0].UserID)
+	assert.Equal(t, []dto.Channel{dto.ChannelEmail, dto.ChannelPush}, events[0].Channels)
+	assert.Equal(t, "7", events[0].Meta["campaign_id"])
+	assert.Equal(t, "acme", events[0].Meta["tenant_id"])
+	assert.Equal(t, "A-1", events[0].Data["order_id"])
+
+	assert.Equal(t, "2", events[1].UserID)
+	assert.Equal(t, []dto.Channel{dto.ChannelPush}, events[1].Channels)
+}
+
+func TestCampaignAdminService_RetryFailedReleasesUnpublishedLogs(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(true)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(storedCampaign(models.CampaignStatusPublished, campaignTestNow.Add(-time.Hour)), nil)
+	m.logs.On("ClaimFailed", mock.Anything, int64(7), retryBatchSize).Return([]*models.NotificationLog{
+		failedLog(100, 1, "email"),
+		failedLog(101, 2, "push"),
+	}, nil)
+	m.producer.On("Publish", mock.Anything, dispatchRoutingKey, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.UserID == "1"
+	}), mock.Anything).Return(nil)
+	m.producer.On("Publish", mock.Anything, dispatchRoutingKey, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.UserID == "2"
+	}), mock.Anything).Return(errors.New("broker down"))
+	m.logs.On("ReleaseRetried", mock.Anything, []int64{101}).Return(nil)
+
+	_, err := svc.RetryFailed(context.Background(), campaignAuth(), 7)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeQueue)
+	m.logs.AssertExpectations(t)
+}
+
+func TestCampaignAdminService_RetryFailedRequiresPublishedCampaign(t *testing.T) {
+	svc, m := setupCampaignAdminSvc(true)
+	m.campaigns.On("FindByID", mock.Anything, int64(7)).Return(storedCampaign(models.CampaignStatusCancelled, campaignTestNow.Add(time.Hour)), nil)
+
+	_, err := svc.RetryFailed(context.Background(), campaignAuth(), 7)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeCampaignNotRetryable)
+	m.logs.AssertNotCalled(t, "ClaimFailed", mock.Anything, mock.Anything, mock.Anything)
+}
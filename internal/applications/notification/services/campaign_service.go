@@ -16,6 +16,7 @@ import (
 	"ichi-go/internal/applications/notification/models"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	"ichi-go/pkg/logger"
+	"ichi-go/pkg/requestctx"
 )
 
 const (
@@ -38,7 +39,7 @@ type CampaignRepository interface {
 // CampaignService orchestrates the full notification send flow:
 //  1. Validate event slug against Go TemplateRegistry
 //  2. Validate channels against event's SupportedChannels()
-//  3. Validate schedule/delay constraints
+//  3. Validate schedule/delay constraints and the blast segment
 //  4. Persist campaign record (status=pending)
 //  5. Compute effective delay and apply user exclusions
 //  6. Publish NotificationEvent(s) to app.events exchange with x-delay header
@@ -75,18 +76,35 @@ func (s *CampaignService) Send(ctx context.Context, req dto.SendNotificationRequ
 		return nil, err
 	}
 
-	// --- Step 3: Validate schedule/delay ---
+	// --- Step 3: Validate schedule/delay and segment ---
 	effectiveDelay, err := resolveDelay(req.ScheduledAt, req.DelaySeconds)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := validateSegment(req.Segment); err != nil {
+		return nil, err
+	}
+
 	// Default locale to "en".
 	locale := req.Locale
 	if locale == "" {
 		locale = "en"
 	}
 
+	// Stamp the caller's tenant so tenant-scoped channels (webhook) reach the right tenant.
+	// A tenant_id sent by the caller is never trusted: it would pick another tenant's endpoints.
+	meta := make(map[string]string, len(req.Meta)+1)
+	for k, v := range req.Meta {
+		if k != "tenant_id" {
+			meta[k] = v
+		}
+	}
+	if tenantID := requestctx.GetTenantID(ctx); tenantID != "" {
+		meta["tenant_id"] = tenantID
+	}
+	req.Meta = meta
+
 	// Convert dto.Channel slice to strings for the model.
 	channelStrings := make([]string, len(req.Channels))
 	for i, ch := range req.Channels {
@@ -109,6 +127,9 @@ func (s *CampaignService) Send(ctx context.Context, req dto.SendNotificationRequ
 	if req.ScheduledAt != nil {
 		campaign.ScheduledAt = bun.NullTime{Time: *req.ScheduledAt}
 	}
+	if !req.Segment.IsEmpty() {
+		campaign.Segment = req.Segment
+	}
 
 	campaign, err = s.campaignRepo.CreateCampaign(ctx, campaign)
 	if err != nil {
@@ -143,7 +164,8 @@ func (s *CampaignService) Send(ctx context.Context, req dto.SendNotificationRequ
 }
 
 // publish sends the NotificationEvent(s) to RabbitMQ.
-// Blast → ONE message. User → N messages (one per filtered user ID).
+// Blast → ONE message, which the BlastConsumer fans out to the users of the campaign's
+// segment minus UserExcludeIDs. User → N messages (one per filtered user ID).
 // Returns an error when the producer is nil (queue disabled).
 func (s *CampaignService) publish(
 	ctx context.Context,
@@ -162,16 +184,11 @@ func (s *CampaignService) publish(
 	for i, ch := range campaign.Channels {
 		channels[i] = dto.Channel(ch)
 	}
-
-	baseHeaders := amqp.Table{
-		"x-event-type":    campaign.EventSlug,
-		"x-campaign-id":   strconv.FormatInt(campaign.ID, 10),
-		"x-delivery-mode": campaign.DeliveryMode,
-	}
+	meta = campaignEventMeta(meta, campaign.ID)
 
 	opts := rabbitmq.PublishOptions{
 		Delay:   delay,
-		Headers: baseHeaders,
+		Headers: campaignHeaders(campaign),
 	}
 
 	switch dto.DeliveryMode(campaign.DeliveryMode) {
@@ -216,6 +233,26 @@ func (s *CampaignService) publish(
 
 // --- helpers ---
 
+// campaignEventMeta returns a copy of meta carrying the campaign ID, which the
+// consumers write to notification logs and the dispatcher checks for cancellation.
+func campaignEventMeta(meta map[string]string, campaignID int64) map[string]string {
+	out := make(map[string]string, len(meta)+1)
+	for k, v := range meta {
+		out[k] = v
+	}
+	out["campaign_id"] = strconv.FormatInt(campaignID, 10)
+	return out
+}
+
+// campaignHeaders returns the AMQP headers of the messages of a campaign.
+func campaignHeaders(campaign *models.NotificationCampaign) amqp.Table {
+	return amqp.Table{
+		"x-event-type":    campaign.EventSlug,
+		"x-campaign-id":   strconv.FormatInt(campaign.ID, 10),
+		"x-delivery-mode": campaign.DeliveryMode,
+	}
+}
+
 // validateChannels checks that all requested channels are supported by the event template.
 func validateChannels(requested []dto.Channel, supported []string) error {
 	supportedSet := make(map[string]bool, len(supported))
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"ichi-go/internal/applications/notification/models"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	notiftemplate "ichi-go/pkg/notification/template"
+	"ichi-go/pkg/requestctx"
 )
 
 // ============================================================================
@@ -42,6 +44,7 @@ func (m *mockCampaignRepo) UpdateStatus(ctx context.Context, id int64, status mo
 type mockEventTemplate struct {
 	slug     string
 	channels []string
+	class    notiftemplate.Classification
 }
 
 func (m *mockEventTemplate) Slug() string             { return m.slug }
@@ -49,6 +52,7 @@ func (m *mockEventTemplate) SupportedChannels() []string { return m.channels }
 func (m *mockEventTemplate) DefaultContent(channel, locale string) notiftemplate.ChannelContent {
 	return notiftemplate.ChannelContent{Title: "Test Title", Body: "Test Body"}
 }
+func (m *mockEventTemplate) Classification() notiftemplate.Classification { return m.class }
 
 // ============================================================================
 // Helpers
@@ -317,6 +321,18 @@ func TestSend_PastScheduledAt(t *testing.T) {
 	repo.AssertNotCalled(t, "CreateCampaign")
 }
 
+func TestSend_InvalidSegment(t *testing.T) {
+	svc, repo, _ := setupCampaignSvc(t, "order.shipped")
+	req := baseReq("order.shipped", dto.DeliveryModeBlast)
+	req.Segment = &models.AudienceSegment{Attributes: map[string]string{"": "pro"}}
+
+	_, err := svc.Send(context.Background(), req)
+
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "segment"), err.Error())
+	repo.AssertNotCalled(t, "CreateCampaign")
+}
+
 func TestSend_DBCreateFails(t *testing.T) {
 	svc, repo, producer := setupCampaignSvc(t, "order.shipped")
 	req := baseReq("order.shipped", dto.DeliveryModeBlast)
@@ -504,6 +520,84 @@ func TestSend_DefaultLocale(t *testing.T) {
 	assert.Equal(t, "en", capturedCampaign.Locale)
 }
 
+func TestSend_StampsTenantFromContext(t *testing.T) {
+	svc, repo, producer := setupCampaignSvc(t, "order.shipped")
+	req := baseReq("order.shipped", dto.DeliveryModeBlast)
+	req.Meta = map[string]string{"source": "api"}
+
+	repo.On("CreateCampaign", mock.Anything, mock.Anything).Return(createdCampaignWithID(7, req.DeliveryMode, []string{"email"}), nil)
+	repo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.Meta["tenant_id"] == "acme" && e.Meta["source"] == "api"
+	}), mock.Anything).Return(nil)
+
+	_, err := svc.Send(requestctx.SetTenantID(context.Background(), "acme"), req)
+
+	require.NoError(t, err)
+	producer.AssertExpectations(t)
+	assert.NotContains(t, req.Meta, "tenant_id", "caller's meta must not be modified")
+}
+
+func TestSend_ReplacesCallerTenant(t *testing.T) {
+	svc, repo, producer := setupCampaignSvc(t, "order.shipped")
+	req := baseReq("order.shipped", dto.DeliveryModeBlast)
+	req.Meta = map[string]string{"tenant_id": "victim"}
+
+	repo.On("CreateCampaign", mock.Anything, mock.Anything).Return(createdCampaignWithID(7, req.DeliveryMode, []string{"email"}), nil)
+	repo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.Meta["tenant_id"] == "acme"
+	}), mock.Anything).Return(nil).Once()
+
+	_, err := svc.Send(requestctx.SetTenantID(context.Background(), "acme"), req)
+	require.NoError(t, err)
+	producer.AssertExpectations(t)
+
+	// Without a tenant context the webhook channel falls back to the default tenant.
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		_, ok := e.Meta["tenant_id"]
+		return !ok
+	}), mock.Anything).Return(nil).Once()
+
+	_, err = svc.Send(context.Background(), req)
+	require.NoError(t, err)
+	producer.AssertExpectations(t)
+}
+
+func TestSend_StampsCampaignIDForLogs(t *testing.T) {
+	svc, repo, producer := setupCampaignSvc(t, "order.shipped")
+	req := baseReq("order.shipped", dto.DeliveryModeBlast)
+
+	repo.On("CreateCampaign", mock.Anything, mock.Anything).Return(createdCampaignWithID(7, req.DeliveryMode, []string{"email"}), nil)
+	repo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.Meta["campaign_id"] == "7"
+	}), mock.Anything).Return(nil)
+
+	_, err := svc.Send(context.Background(), req)
+
+	require.NoError(t, err)
+	producer.AssertExpectations(t)
+}
+
+func TestSend_PersistsBlastSegment(t *testing.T) {
+	svc, repo, producer := setupCampaignSvc(t, "order.shipped")
+	req := baseReq("order.shipped", dto.DeliveryModeBlast)
+	req.Segment = &models.AudienceSegment{Roles: []string{"customer"}, TenantID: "acme"}
+
+	repo.On("CreateCampaign", mock.Anything, mock.MatchedBy(func(c *models.NotificationCampaign) bool {
+		return c.Segment == req.Segment
+	})).Return(createdCampaignWithID(7, req.DeliveryMode, []string{"email"}), nil)
+	repo.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, mock.Anything, mock.Anything).Return(nil)
+
+	_, err := svc.Send(context.Background(), req)
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	producer.AssertNumberOfCalls(t, "Publish", 1) // one blast message; the consumer fans it out
+}
+
 func TestSend_UnknownDeliveryMode(t *testing.T) {
 	svc, repo, producer := setupCampaignSvc(t, "order.shipped")
 	req := baseReq("order.shipped", "webhook") // invalid mode
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+)
+
+// topicPattern is the character set FCM accepts in topic names.
+var topicPattern = regexp.MustCompile(`^[a-zA-Z0-9\-_.~%]+$`)
+
+// errTokenRefused is reported when FCM accepts a topic request but not the device token in it.
+var errTokenRefused = errors.New("token refused by FCM")
+
+// DeviceRepository is the minimal interface DeviceService uses for DB persistence.
+// The concrete *repositories.UserDeviceRepository satisfies this interface.
+type DeviceRepository interface {
+	Register(ctx context.Context, device *models.UserDevice) (*models.UserDevice, []string, error)
+	FindByID(ctx context.Context, id int64) (*models.UserDevice, error)
+	ListByUser(ctx context.Context, userID int64) ([]*models.UserDevice, error)
+	SetTopics(ctx context.Context, id int64, topics []string) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// TopicSubscriber manages FCM topic subscriptions; satisfied by *fcm.Client.
+type TopicSubscriber interface {
+	SubscribeToTopic(ctx context.Context, tokens []string, topic string) ([]string, error)
+	UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) ([]string, error)
+}
+
+// DeviceService registers the push notification devices of the calling user and
+// manages their FCM topic subscriptions. Every method is scoped to one user: a
+// device of someone else is reported as not found.
+type DeviceService struct {
+	repo       DeviceRepository
+	subscriber TopicSubscriber // nil when FCM is disabled; topic subscriptions are unavailable
+}
+
+func NewDeviceService(repo DeviceRepository, subscriber TopicSubscriber) *DeviceService {
+	return &DeviceService{repo: repo, subscriber: subscriber}
+}
+
+// Register stores the device of a user, or refreshes it when the token is already
+// known. A token taken over from another user loses that user's topic subscriptions.
+func (s *DeviceService) Register(ctx context.Context, userID int64, req dto.RegisterDeviceRequest) (*models.UserDevice, error) {
+	device, staleTopics, err := s.repo.Register(ctx, &models.UserDevice{
+		UserID:     userID,
+		Platform:   models.DevicePlatform(req.Platform),
+		AppVersion: req.AppVersion,
+		Token:      req.Token,
+	})
+	if err != nil {
+		return nil, deviceDatabaseError("register_device", userID, err)
+	}
+	for _, topic := range staleTopics {
+		s.unsubscribe(ctx, device, topic)
+	}
+	return device, nil
+}
+
+// List returns the devices of a user, most recently seen first.
+func (s *DeviceService) List(ctx context.Context, userID int64) ([]*models.UserDevice, error) {
+	devices, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, deviceDatabaseError("list_devices", userID, err)
+	}
+	return devices, nil
+}
+
+// Unregister deletes a device of the user and its topic subscriptions.
+func (s *DeviceService) Unregister(ctx context.Context, userID, deviceID int64) error {
+	device, err := s.findOwned(ctx, userID, deviceID)
+	if err != nil {
+		return err
+	}
+	for _, topic := range device.Topics {
+		s.unsubscribe(ctx, device, topic)
+	}
+	if err := s.repo.Delete(ctx, device.ID); err != nil {
+		return deviceDatabaseError("delete_device", userID, err)
+	}
+	return nil
+}
+
+// Subscribe subscribes a device of the user to an FCM topic. Subscribing twice is a no-op.
+func (s *DeviceService) Subscribe(ctx context.Context, userID, deviceID int64, topic string) (*models.UserDevice, error) {
+	device, err := s.topicDevice(ctx, userID, deviceID, topic)
+	if err != nil {
+		return nil, err
+	}
+	if device.HasTopic(topic) {
+		return device, nil
+	}
+
+	failed, err := s.subscriber.SubscribeToTopic(ctx, []string{device.Token}, topic)
+	if err == nil && len(failed) > 0 {
+		err = errTokenRefused
+	}
+	if err != nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeTopicSubscribeFailed).
+			With("device_id", device.ID).
+			With("topic", topic).
+			Hint("Failed to subscribe the device to the topic").
+			Wrap(err)
+	}
+
+	topics := append(append([]string{}, device.Topics...), topic)
+	if err := s.repo.SetTopics(ctx, device.ID, topics); err != nil {
+		return nil, deviceDatabaseError("subscribe_device", userID, err)
+	}
+	device.Topics = topics
+	return device, nil
+}
+
+// Unsubscribe removes a device of the user from an FCM topic. Unknown topics are a no-op.
+func (s *DeviceService) Unsubscribe(ctx context.Context, userID, deviceID int64, topic string) (*models.UserDevice, error) {
+	device, err := s.topicDevice(ctx, userID, deviceID, topic)
+	if err != nil {
+		return nil, err
+	}
+	if !device.HasTopic(topic) {
+		return device, nil
+	}
+
+	failed, err := s.subscriber.UnsubscribeFromTopic(ctx, []string{device.Token}, topic)
+	if err == nil && len(failed) > 0 {
+		err = errTokenRefused
+	}
+	if err != nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeTopicSubscribeFailed).
+			With("device_id", device.ID).
+			With("topic", topic).
+			Hint("Failed to unsubscribe the device from the topic").
+			Wrap(err)
+	}
+
+	topics := make([]string, 0, len(device.Topics))
+	for _, t := range device.Topics {
+		if t != topic {
+			topics = append(topics, t)
+		}
+	}
+	if err := s.repo.SetTopics(ctx, device.ID, topics); err != nil {
+		return nil, deviceDatabaseError("unsubscribe_device", userID, err)
+	}
+	device.Topics = topics
+	return device, nil
+}
+
+// topicDevice checks a topic request and returns the device it is about.
+func (s *DeviceService) topicDevice(ctx context.Context, userID, deviceID int64, topic string) (*models.UserDevice, error) {
+	if !topicPattern.MatchString(topic) {
+		return nil, pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+			With("topic", topic).
+			Hint("Topic may only contain letters, digits and -_.~%").
+			Errorf("invalid topic name")
+	}
+	if s.subscriber == nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodePushUnavailable).
+			Hint("Push notifications are not configured").
+			Errorf("fcm disabled")
+	}
+	return s.findOwned(ctx, userID, deviceID)
+}
+
+func (s *DeviceService) findOwned(ctx context.Context, userID, deviceID int64) (*models.UserDevice, error) {
+	device, err := s.repo.FindByID(ctx, deviceID)
+	if err != nil {
+		return nil, deviceDatabaseError("get_device", userID, err)
+	}
+	if device == nil || device.UserID != userID {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeNotFound).
+			With("user_id", userID).
+			With("device_id", deviceID).
+			Hint("Device not found").
+			Errorf("device not found")
+	}
+	return device, nil
+}
+
+// unsubscribe removes a device from a topic at FCM on a best-effort basis.
+func (s *DeviceService) unsubscribe(ctx context.Context, device *models.UserDevice, topic string) {
+	if s.subscriber == nil {
+		return
+	}
+	if _, err := s.subscriber.UnsubscribeFromTopic(ctx, []string{device.Token}, topic); err != nil {
+		logger.Warnf("[devices] failed to unsubscribe device %d from topic %s: %v", device.ID, topic, err)
+	}
+}
+
+func deviceDatabaseError(operation string, userID int64, err error) error {
+	return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+		With("operation", operation).
+		With("user_id", userID).
+		Wrap(err)
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	pkgErrors "ichi-go/pkg/errors"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockDeviceRepo struct {
+	mock.Mock
+}
+
+func (m *mockDeviceRepo) Register(ctx context.Context, device *models.UserDevice) (*models.UserDevice, []string, error) {
+	args := m.Called(ctx, device)
+	stale, _ := args.Get(1).([]string)
+	return device, stale, args.Error(2)
+}
+
+func (m *mockDeviceRepo) FindByID(ctx context.Context, id int64) (*models.UserDevice, error) {
+	args := m.Called(ctx, id)
+	device, _ := args.Get(0).(*models.UserDevice)
+	return device, args.Error(1)
+}
+
+func (m *mockDeviceRepo) ListByUser(ctx context.Context, userID int64) ([]*models.UserDevice, error) {
+	args := m.Called(ctx, userID)
+	devices, _ := args.Get(0).([]*models.UserDevice)
+	return devices, args.Error(1)
+}
+
+func (m *mockDeviceRepo) SetTopics(ctx context.Context, id int64, topics []string) error {
+	return m.Called(ctx, id, topics).Error(0)
+}
+
+func (m *mockDeviceRepo) Delete(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+type mockSubscriber struct {
+	mock.Mock
+}
+
+func (m *mockSubscriber) SubscribeToTopic(ctx context.Context, tokens []string, topic string) ([]string, error) {
+	args := m.Called(ctx, tokens, topic)
+	failed, _ := args.Get(0).([]string)
+	return failed, args.Error(1)
+}
+
+func (m *mockSubscriber) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) ([]string, error) {
+	args := m.Called(ctx, tokens, topic)
+	failed, _ := args.Get(0).([]string)
+	return failed, args.Error(1)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func testDevice(userID int64, topics ...string) *models.UserDevice {
+	return &models.UserDevice{ID: 5, UserID: userID, Platform: models.DevicePlatformAndroid, Token: "tok-5", Topics: topics}
+}
+
+func assertErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+	require.Error(t, err)
+	oopsErr, ok := oops.AsOops(err)
+	require.True(t, ok, "expected an oops error, got %v", err)
+	assert.Equal(t, code, oopsErr.Code())
+}
+
+// ============================================================================
+// Register / Unregister
+// ============================================================================
+
+func TestDeviceRegister_UnsubscribesTopicsOfPreviousOwner(t *testing.T) {
+	repo := new(mockDeviceRepo)
+	sub := new(mockSubscriber)
+	svc := NewDeviceService(repo, sub)
+
+	repo.On("Register", mock.Anything, mock.MatchedBy(func(d *models.UserDevice) bool {
+		return d.UserID == 7 && d.Token == "tok-5" && d.Platform == models.DevicePlatformIOS
+	})).Return(nil, []string{"news"}, nil)
+	sub.On("UnsubscribeFromTopic", mock.Anything, []string{"tok-5"}, "news").Return(nil, nil)
+
+	device, err := svc.Register(context.Background(), 7, dto.RegisterDeviceRequest{Platform: "ios", Token: "tok-5"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), device.UserID)
+	sub.AssertExpectations(t)
+}
+
+func TestDeviceUnregister_DeviceOfOtherUserIsNotFound(t *testing.T) {
+	repo := new(mockDeviceRepo)
+	svc := NewDeviceService(repo, nil)
+	repo.On("FindByID", mock.Anything, int64(5)).Return(testDevice(8), nil)
+
+	err := svc.Unregister(context.Background(), 7, 5)
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeviceUnregister_DropsTopicSubscriptions(t *testing.T) {
+	repo := new(mockDeviceRepo)
+	sub := new(mockSubscriber)
+	svc := NewDeviceService(repo, sub)
+	repo.On("FindByID", mock.Anything, int64(5)).Return(testDevice(7, "news", "deals"), nil)
+	sub.On("UnsubscribeFromTopic", mock.Anything, []string{"tok-5"}, mock.Anything).Return(nil, nil)
+	repo.On("Delete", mock.Anything, int64(5)).Return(nil)
+
+	require.NoError(t, svc.Unregister(context.Background(), 7, 5))
+	sub.AssertNumberOfCalls(t, "UnsubscribeFromTopic", 2)
+}
+
+// ============================================================================
+// Topics
+// ============================================================================
+
+func TestDeviceSubscribe_RecordsTopic(t *testing.T) {
+	repo := new(mockDeviceRepo)
+	sub := new(mockSubscriber)
+	svc := NewDeviceService(repo, sub)
+	repo.On("FindByID", mock.Anything, int64(5)).Return(testDevice(7, "news"), nil)
+	sub.On("SubscribeToTopic", mock.Anything, []string{"tok-5"}, "deals").Return(nil, nil)
+	repo.On("SetTopics", mock.Anything, int64(5), []string{"news", "deals"}).Return(nil)
+
+	device, err := svc.Subscribe(context.Background(), 7, 5, "deals")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"news", "deals"}, device.Topics)
+}
+
+func TestDeviceSubscribe_AlreadySubscribedIsNoop(t *testing.T) {
+	repo := new(mockDeviceRepo)
+	sub := new(mockSubscriber)
+	svc := NewDeviceService(repo, sub)
+	repo.On("FindByID", mock.Anything, int64(5)).Return(testDevice(7, "news"), nil)
+
+	_, err := svc.Subscribe(context.Background(), 7, 5, "news")
+	require.NoError(t, err)
+	sub.AssertNotCalled(t, "SubscribeToTopic", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeviceSubscribe_TokenRefusedByFCM(t *testing.T) {
+	repo := new(mockDeviceRepo)
+	sub := new(mockSubscriber)
+	svc := NewDeviceService(repo, sub)
+	repo.On("FindByID", mock.Anything, int64(5)).Return(testDevice(7), nil)
+	sub.On("SubscribeToTopic", mock.Anything, []string{"tok-5"}, "deals").Return([]string{"tok-5"}, nil)
+
+	_, err := svc.Subscribe(context.Background(), 7, 5, "deals")
+	assertErrorCode(t, err, pkgErrors.ErrCodeTopicSubscribeFailed)
+	repo.AssertNotCalled(t, "SetTopics", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeviceSubscribe_RejectsInvalidTopic(t *testing.T) {
+	svc := NewDeviceService(new(mockDeviceRepo), new(mockSubscriber))
+
+	_, err := svc.Subscribe(context.Background(), 7, 5, "/topics/news")
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+}
+
+func TestDeviceSubscribe_UnavailableWithoutFCM(t *testing.T) {
+	svc := NewDeviceService(new(mockDeviceRepo), nil)
+
+	_, err := svc.Subscribe(context.Background(), 7, 5, "news")
+	assertErrorCode(t, err, pkgErrors.ErrCodePushUnavailable)
+}
+
+func TestDeviceUnsubscribe_RemovesTopic(t *testing.T) {
+	repo := new(mockDeviceRepo)
+	sub := new(mockSubscriber)
+	svc := NewDeviceService(repo, sub)
+	repo.On("FindByID", mock.Anything, int64(5)).Return(testDevice(7, "news", "deals"), nil)
+	sub.On("UnsubscribeFromTopic", mock.Anything, []string{"tok-5"}, "news").Return(nil, nil)
+	repo.On("SetTopics", mock.Anything, int64(5), []string{"deals"}).Return(nil)
+
+	device, err := svc.Unsubscribe(context.Background(), 7, 5, "news")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deals"}, device.Topics)
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/digest"
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+// metaDigestWindow marks the flush of a digest: the ID of the window whose buffered
+// events it delivers. A flush is never buffered again.
+const metaDigestWindow = "digest_window"
+
+// DigestStore buffers the events of digest windows.
+// The concrete *digest.RedisStore satisfies this interface.
+type DigestStore interface {
+	Append(ctx context.Context, userID, slug string, entry digest.Entry, window, ttl time.Duration) (digest.Appended, error)
+	Release(ctx context.Context, userID, slug, windowID string) error
+	Close(ctx context.Context, userID, slug, windowID string) error
+	Load(ctx context.Context, userID, slug, windowID string) ([]digest.Entry, error)
+	Delete(ctx context.Context, userID, slug, windowID string) error
+}
+
+// DigestService batches the user events of templates with a digest policy (see
+// notiftemplate.DigestPolicy). The first event of a user in a window schedules the
+// flush of the window, a delayed event on the app.events exchange; the events that
+// follow join the window until the flush delivers them as one digest notification.
+//
+// Events are buffered by event ID, so a retried event joins its window once.
+type DigestService struct {
+	registry *notiftemplate.Registry
+	store    DigestStore
+	producer rabbitmq.MessageProducer // app.events
+	cfg      digest.Config
+	now      func() time.Time
+}
+
+// NewDigestService creates a DigestService. Events are only digested when both the
+// store and the producer are available.
+func NewDigestService(
+	registry *notiftemplate.Registry,
+	store DigestStore,
+	producer rabbitmq.MessageProducer,
+	cfg digest.Config,
+) *DigestService {
+	return &DigestService{
+		registry: registry,
+		store:    store,
+		producer: producer,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// DigestBatch is the notification a digest flush delivers.
+type DigestBatch struct {
+	// EventType is the template the batch is rendered with: the digest template, or
+	// the event's own template when the window holds a single event.
+	EventType string
+	Channels  []dto.Channel
+	Data      map[string]any
+	Count     int
+}
+
+// IsDigestFlush reports whether event is the flush of a digest window.
+func IsDigestFlush(event dto.NotificationEvent) bool {
+	return event.Meta[metaDigestWindow] != ""
+}
+
+// Channels returns the channels of event that its digest policy buffers, none when
+// the event is sent as it is. Safe to call on a nil *DigestService.
+func (s *DigestService) Channels(event dto.NotificationEvent) []dto.Channel {
+	if s == nil || !s.cfg.Enabled || s.store == nil || s.producer == nil {
+		return nil
+	}
+	if event.DeliveryMode != dto.DeliveryModeUser || event.UserID == "" || event.EventID == "" || IsDigestFlush(event) {
+		return nil
+	}
+	policy, window, ok := s.policy(event.EventType)
+	if !ok || window <= 0 {
+		return nil
+	}
+
+	var chs []dto.Channel
+	for _, ch := range event.Channels {
+		for _, digested := range policy.Channels {
+			if string(ch) == digested {
+				chs = append(chs, ch)
+				break
+			}
+		}
+	}
+	return chs
+}
+
+// Buffer adds event to the open digest window of its user for channels, and returns
+// when the window is flushed. A retried event that was already buffered returns a
+// zero time. The event opening a window schedules its flush; when that fails the
+// window is released, so the retry opens it again, with the events that joined it
+// meanwhile still buffered.
+func (s *DigestService) Buffer(ctx context.Context, event dto.NotificationEvent, channels []dto.Channel) (time.Time, error) {
+	if s == nil || s.store == nil || s.producer == nil {
+		return time.Time{}, fmt.Errorf("digest: buffer is unavailable")
+	}
+	policy, window, ok := s.policy(event.EventType)
+	if !ok {
+		return time.Time{}, fmt.Errorf("digest: event %q has no digest policy", event.EventType)
+	}
+
+	entry := digest.Entry{
+		EventID:  event.EventID,
+		At:       s.now(),
+		Channels: make([]string, len(channels)),
+		Data:     event.Data,
+	}
+	for i, ch := range channels {
+		entry.Channels[i] = string(ch)
+	}
+	appended, err := s.store.Append(ctx, event.UserID, event.EventType, entry, window, s.cfg.TTL())
+	if err != nil {
+		return time.Time{}, err
+	}
+	if appended.Duplicate {
+		return time.Time{}, nil
+	}
+	flushAt := s.now().Add(appended.FlushIn)
+	if !appended.Opened {
+		return flushAt, nil
+	}
+
+	if err := s.scheduleFlush(ctx, event, policy, appended); err != nil {
+		if releaseErr := s.store.Release(ctx, event.UserID, event.EventType, appended.WindowID); releaseErr != nil {
+			logger.Errorf("[digest] releasing window=%s failed: %v", appended.WindowID, releaseErr)
+		}
+		return time.Time{}, fmt.Errorf("digest: scheduling flush: %w", err)
+	}
+	return flushAt, nil
+}
+
+// scheduleFlush publishes the flush of the window event opened, delayed until the window ends.
+func (s *DigestService) scheduleFlush(ctx context.Context, event dto.NotificationEvent, policy notiftemplate.DigestPolicy, appended digest.Appended) error {
+	flush := dto.NotificationEvent{
+		EventID:      appended.WindowID + ":digest",
+		EventType:    event.EventType,
+		DeliveryMode: dto.DeliveryModeUser,
+		UserID:       event.UserID,
+		Locale:       event.Locale,
+		Meta:         make(map[string]string, len(event.Meta)+1),
+	}
+	for _, ch := range policy.Channels {
+		flush.Channels = append(flush.Channels, dto.Channel(ch))
+	}
+	// The digest spans events: it belongs to no single campaign, and the quiet hours
+	// of its user apply when it is sent.
+	for k, v := range event.Meta {
+		if k != "campaign_id" && k != metaDeferredUntil {
+			flush.Meta[k] = v
+		}
+	}
+	flush.Meta[metaDigestWindow] = appended.WindowID
+
+	return s.producer.Publish(ctx, dispatchRoutingKey, flush, rabbitmq.PublishOptions{
+		Delay: appended.FlushIn,
+		Headers: amqp.Table{
+			"x-event-type":    flush.EventType,
+			"x-event-id":      flush.EventID,
+			"x-delivery-mode": string(flush.DeliveryMode),
+		},
+	})
+}
+
+// Collect closes the window of the digest flush event and returns the notification
+// of its buffered events, sent on the channels of event they were buffered for.
+// It returns nil when nothing is left to send: the window was already delivered by
+// an earlier delivery of the flush, or its events expired.
+// Safe to call on a nil *DigestService.
+func (s *DigestService) Collect(ctx context.Context, event dto.NotificationEvent) (*DigestBatch, error) {
+	windowID := event.Meta[metaDigestWindow]
+	if s == nil || s.store == nil || windowID == "" {
+		return nil, nil
+	}
+	if err := s.store.Close(ctx, event.UserID, event.EventType, windowID); err != nil {
+		return nil, err
+	}
+	entries, err := s.store.Load(ctx, event.UserID, event.EventType, windowID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	batch := s.batch(event.EventType, entries)
+	batch.Channels = intersectChannels(batch.Channels, event.Channels)
+	return batch, nil
+}
+
+// batch builds the digest notification of the buffered entries of an event, oldest first.
+func (s *DigestService) batch(slug string, entries []digest.Entry) *DigestBatch {
+	batch := &DigestBatch{Count: len(entries)}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		for _, ch := range e.Channels {
+			if !seen[ch] {
+				seen[ch] = true
+				batch.Channels = append(batch.Channels, dto.Channel(ch))
+			}
+		}
+	}
+
+	policy, _, ok := s.policy(slug)
+	if len(entries) == 1 || !ok {
+		// A lone event reads better on its own; an event whose policy was removed
+		// since it was buffered has no digest template left and sends the latest event.
+		batch.EventType = slug
+		batch.Data = entries[len(entries)-1].Data
+		return batch
+	}
+
+	listed := entries
+	if len(listed) > s.cfg.Max() {
+		listed = listed[:s.cfg.Max()]
+	}
+	events := make([]map[string]any, len(listed))
+	for i, e := range listed {
+		events[i] = e.Data
+	}
+	batch.EventType = policy.Template
+	batch.Data = map[string]any{
+		"count":  len(entries),
+		"events": events,
+		"more":   len(entries) - len(listed),
+	}
+	return batch
+}
+
+// Done deletes the buffered events of the window of the digest flush event, once sent.
+func (s *DigestService) Done(ctx context.Context, event dto.NotificationEvent) error {
+	if s == nil || s.store == nil || !IsDigestFlush(event) {
+		return nil
+	}
+	return s.store.Delete(ctx, event.UserID, event.EventType, event.Meta[metaDigestWindow])
+}
+
+// policy returns the digest policy of the template of slug and its configured window.
+func (s *DigestService) policy(slug string) (notiftemplate.DigestPolicy, time.Duration, bool) {
+	tmpl, ok := s.registry.Get(slug)
+	if !ok {
+		return notiftemplate.DigestPolicy{}, 0, false
+	}
+	policy, ok := notiftemplate.DigestPolicyOf(tmpl)
+	if !ok {
+		return notiftemplate.DigestPolicy{}, 0, false
+	}
+	return policy, s.cfg.Window(slug, policy.Window), true
+}
+
+// intersectChannels returns the channels of chs that are also in allowed, in the order of chs.
+func intersectChannels(chs, allowed []dto.Channel) []dto.Channel {
+	var out []dto.Channel
+	for _, ch := range chs {
+		for _, a := range allowed {
+			if ch == a {
+				out = append(out, ch)
+				break
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/notification/digest"
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+// Compile-time assertions for the digest stores.
+var (
+	_ DigestStore = (*digest.RedisStore)(nil)
+	_ DigestStore = (*digest.MemoryStore)(nil)
+)
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// digestedEventTemplate implements notiftemplate.EventTemplate and notiftemplate.Digester.
+type digestedEventTemplate struct {
+	mockEventTemplate
+	policy notiftemplate.DigestPolicy
+}
+
+func (m *digestedEventTemplate) Digest() notiftemplate.DigestPolicy { return m.policy }
+
+func setupDigestSvc(cfg digest.Config) (*DigestService, *digest.MemoryStore, *mockProducer) {
+	reg := notiftemplate.NewRegistry()
+	reg.Register(&digestedEventTemplate{
+		mockEventTemplate: mockEventTemplate{slug: "order.shipped", channels: []string{"email", "push", "sms"}},
+		policy: notiftemplate.DigestPolicy{
+			Window:   2 * time.Minute,
+			Template: "order.shipped.digest",
+			Channels: []string{"push", "sms"},
+		},
+	})
+	reg.Register(&mockEventTemplate{slug: "order.shipped.digest", channels: []string{"push", "sms"}})
+	reg.Register(&mockEventTemplate{slug: "promo.weekly", channels: []string{"email", "push"}})
+
+	store := digest.NewMemoryStore()
+	producer := new(mockProducer)
+	return NewDigestService(reg, store, producer, cfg), store, producer
+}
+
+func shippedEvent(eventID, orderID string) dto.NotificationEvent {
+	return dto.NotificationEvent{
+		EventID:      eventID,
+		EventType:    "order.shipped",
+		DeliveryMode: dto.DeliveryModeUser,
+		UserID:       "42",
+		Locale:       "id-ID",
+		Channels:     []dto.Channel{dto.ChannelEmail, dto.ChannelPush},
+		Data:         map[string]any{"order_id": orderID},
+		Meta:         map[string]string{"campaign_id": "7", "trace_id": "t-1"},
+	}
+}
+
+func isFlush(windowID string) any {
+	return mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.EventID == windowID+":digest" && e.Meta[metaDigestWindow] == windowID &&
+			e.Meta["campaign_id"] == "" && e.Meta["trace_id"] == "t-1" && e.UserID == "42"
+	})
+}
+
+func delayedByWindow() any {
+	return mock.MatchedBy(func(opts rabbitmq.PublishOptions) bool {
+		return opts.Delay > time.Minute && opts.Delay <= 2*time.Minute
+	})
+}
+
+// ============================================================================
+// Channels
+// ============================================================================
+
+func TestDigestChannels_OnlyPolicyChannelsOfUserEvents(t *testing.T) {
+	svc, _, _ := setupDigestSvc(digest.Config{Enabled: true})
+
+	event := shippedEvent("evt-1", "ORD-1")
+	assert.Equal(t, []dto.Channel{dto.ChannelPush}, svc.Channels(event))
+
+	flush := event
+	flush.Meta = map[string]string{metaDigestWindow: "evt-0"}
+	assert.Empty(t, svc.Channels(flush), "a flush is never buffered again")
+
+	blast := event
+	blast.DeliveryMode = dto.DeliveryModeBlast
+	assert.Empty(t, svc.Channels(blast))
+
+	promo := event
+	promo.EventType = "promo.weekly"
+	assert.Empty(t, svc.Channels(promo), "templates without a policy are sent on their own")
+
+	var nilSvc *DigestService
+	assert.Empty(t, nilSvc.Channels(event))
+}
+
+func TestDigestChannels_ConfigDisablesDigests(t *testing.T) {
+	disabled, _, _ := setupDigestSvc(digest.Config{Enabled: false})
+	assert.Empty(t, disabled.Channels(shippedEvent("evt-1", "ORD-1")))
+
+	noWindow, _, _ := setupDigestSvc(digest.Config{
+		Enabled: true,
+		Windows: []digest.WindowConfig{{Event: "order.shipped", Window: 0}},
+	})
+	assert.Empty(t, noWindow.Channels(shippedEvent("evt-1", "ORD-1")))
+}
+
+// ============================================================================
+// Buffer
+// ============================================================================
+
+func TestDigestBuffer_FirstEventSchedulesFlush(t *testing.T) {
+	svc, _, producer := setupDigestSvc(digest.Config{Enabled: true})
+	ctx := context.Background()
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, isFlush("evt-1"), delayedByWindow()).Return(nil).Once()
+
+	flushAt, err := svc.Buffer(ctx, shippedEvent("evt-1", "ORD-1"), []dto.Channel{dto.ChannelPush})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), flushAt, 5*time.Second)
+
+	next, err := svc.Buffer(ctx, shippedEvent("evt-2", "ORD-2"), []dto.Channel{dto.ChannelPush})
+	require.NoError(t, err)
+	assert.Equal(t, flushAt.Truncate(time.Second), next.Truncate(time.Second), "later events join the open window")
+
+	retried, err := svc.Buffer(ctx, shippedEvent("evt-1", "ORD-1"), []dto.Channel{dto.ChannelPush})
+	require.NoError(t, err)
+	assert.True(t, retried.IsZero(), "a retried event is buffered once")
+
+	producer.AssertExpectations(t)
+}
+
+func TestDigestBuffer_FailedFlushReleasesWindow(t *testing.T) {
+	svc, store, producer := setupDigestSvc(digest.Config{Enabled: true})
+	ctx := context.Background()
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, isFlush("evt-1"), mock.Anything).
+		Return(errors.New("broker down")).Once()
+
+	_, err := svc.Buffer(ctx, shippedEvent("evt-1", "ORD-1"), []dto.Channel{dto.ChannelPush})
+	require.Error(t, err)
+	entries, err := store.Load(ctx, "42", "order.shipped", "evt-1")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, isFlush("evt-1"), mock.Anything).Return(nil).Once()
+	flushAt, err := svc.Buffer(ctx, shippedEvent("evt-1", "ORD-1"), []dto.Channel{dto.ChannelPush})
+	require.NoError(t, err)
+	assert.False(t, flushAt.IsZero(), "the retry opens the window again")
+	producer.AssertExpectations(t)
+}
+
+func TestDigestBuffer_FailedFlushKeepsJoinedEvents(t *testing.T) {
+	svc, store, producer := setupDigestSvc(digest.Config{Enabled: true})
+	ctx := context.Background()
+	// evt-2 joins the window while the flush of evt-1 is being published.
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, isFlush("evt-1"), mock.Anything).
+		Run(func(mock.Arguments) {
+			_, err := svc.Buffer(ctx, shippedEvent("evt-2", "ORD-2"), []dto.Channel{dto.ChannelPush})
+			require.NoError(t, err)
+		}).
+		Return(errors.New("broker down")).Once()
+
+	_, err := svc.Buffer(ctx, shippedEvent("evt-1", "ORD-1"), []dto.Channel{dto.ChannelPush})
+	require.Error(t, err)
+
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, isFlush("evt-1"), mock.Anything).Return(nil).Once()
+	_, err = svc.Buffer(ctx, shippedEvent("evt-1", "ORD-1"), []dto.Channel{dto.ChannelPush})
+	require.NoError(t, err)
+
+	entries, err := store.Load(ctx, "42", "order.shipped", "evt-1")
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "the retried window still delivers the event that joined it")
+	assert.ElementsMatch(t, []string{"evt-1", "evt-2"}, []string{entries[0].EventID, entries[1].EventID})
+	producer.AssertExpectations(t)
+}
+
+// ============================================================================
+// Collect / Done
+// ============================================================================
+
+func TestDigestCollect_BuildsDigestOfWindow(t *testing.T) {
+	svc, _, producer := setupDigestSvc(digest.Config{Enabled: true, MaxEvents: 2})
+	ctx := context.Background()
+	producer.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	for i, order := range []string{"ORD-1", "ORD-2", "ORD-3"} {
+		_, err := svc.Buffer(ctx, shippedEvent(fmt.Sprintf("evt-%d", i+1), order), []dto.Channel{dto.ChannelPush, dto.ChannelSMS})
+		require.NoError(t, err)
+	}
+	flush := producer.Calls[0].Arguments.Get(2).(dto.NotificationEvent)
+	flush.Channels = []dto.Channel{dto.ChannelPush} // e.g. SMS deferred to the end of quiet hours earlier
+
+	batch, err := svc.Collect(ctx, flush)
+	require.NoError(t, err)
+	require.NotNil(t, batch)
+	assert.Equal(t, "order.shipped.digest", batch.EventType)
+	assert.Equal(t, []dto.Channel{dto.ChannelPush}, batch.Channels)
+	assert.Equal(t, 3, batch.Count)
+	assert.Equal(t, 3, batch.Data["count"])
+	assert.Equal(t, 1, batch.Data["more"])
+	assert.Equal(t, []map[string]any{{"order_id": "ORD-1"}, {"order_id": "ORD-2"}}, batch.Data["events"])
+
+	// The window is closed: the next event opens a new one.
+	_, err = svc.Buffer(ctx, shippedEvent("evt-4", "ORD-4"), []dto.Channel{dto.ChannelPush})
+	require.NoError(t, err)
+	assert.Len(t, producer.Calls, 2)
+
+	require.NoError(t, svc.Done(ctx, flush))
+	batch, err = svc.Collect(ctx, flush)
+	require.NoError(t, err)
+	assert.Nil(t, batch, "a redelivered flush sends nothing")
+}
+
+func TestDigestCollect_SingleEventUsesItsOwnTemplate(t *testing.T) {
+	svc, _, producer := setupDigestSvc(digest.Config{Enabled: true})
+	ctx := context.Background()
+	producer.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	_, err := svc.Buffer(ctx, shippedEvent("evt-1", "ORD-1"), []dto.Channel{dto.ChannelPush})
+	require.NoError(t, err)
+	flush := producer.Calls[0].Arguments.Get(2).(dto.NotificationEvent)
+
+	batch, err := svc.Collect(ctx, flush)
+	require.NoError(t, err)
+	require.NotNil(t, batch)
+	assert.Equal(t, "order.shipped", batch.EventType)
+	assert.Equal(t, map[string]any{"order_id": "ORD-1"}, batch.Data)
+	assert.Equal(t, 1, batch.Count)
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/realtime"
+)
+
+const (
+	defaultInboxPageSize = 20
+	// maxInboxReplay bounds the notifications resent to a reconnecting stream.
+	maxInboxReplay = 100
+)
+
+// InboxRepository is the minimal interface InboxService uses for the inbox.
+// The concrete *repositories.InAppNotificationRepository satisfies this interface.
+type InboxRepository interface {
+	FindByID(ctx context.Context, id int64) (*models.InAppNotification, error)
+	ListByUser(ctx context.Context, userID int64, filter models.InboxFilter, beforeID int64, limit int) ([]*models.InAppNotification, error)
+	ListAfter(ctx context.Context, userID, afterID int64, limit int) ([]*models.InAppNotification, error)
+	CountUnread(ctx context.Context, userID int64) (int, error)
+	SetRead(ctx context.Context, id int64, at *time.Time) error
+	SetArchived(ctx context.Context, id int64, at *time.Time) error
+	MarkAllRead(ctx context.Context, userID int64, at time.Time) (int64, error)
+}
+
+// InboxHub opens inbox streams and pushes events to them; satisfied by *realtime.Hub.
+type InboxHub interface {
+	Subscribe(userID int64) (*realtime.Subscription, error)
+	Publish(ctx context.Context, msg realtime.Message) error
+}
+
+// InboxStream is an open inbox stream together with what the client needs to catch up.
+type InboxStream struct {
+	*realtime.Subscription
+	// Missed are the notifications after the client's Last-Event-ID, oldest first.
+	// They may also arrive on the stream; clients deduplicate on the notification ID.
+	Missed []*models.InAppNotification
+	Unread int
+}
+
+// InboxService manages the in-app notification inbox of the authenticated user.
+// Changes to the unread count are pushed to the user's other open streams.
+type InboxService struct {
+	repo InboxRepository
+	hub  InboxHub // nil when realtime delivery is unavailable
+	now  func() time.Time
+}
+
+func NewInboxService(repo InboxRepository, hub InboxHub) *InboxService {
+	return &InboxService{repo: repo, hub: hub, now: time.Now}
+}
+
+// List returns one page of the user's notifications, newest first.
+func (s *InboxService) List(ctx context.Context, userID int64, req dto.InboxListRequest) (*dto.InboxPage, error) {
+	var beforeID int64
+	if req.Cursor != "" {
+		id, err := strconv.ParseInt(req.Cursor, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+				With("cursor", req.Cursor).
+				Hint("Invalid cursor; use the next_cursor of the previous page").
+				Errorf("invalid inbox cursor")
+		}
+		beforeID = id
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultInboxPageSize
+	}
+
+	items, err := s.repo.ListByUser(ctx, userID, models.InboxFilter(req.Filter), beforeID, limit)
+	if err != nil {
+		return nil, inboxDatabaseError("list_inbox", userID, err)
+	}
+	page := &dto.InboxPage{Items: items}
+	if page.Items == nil {
+		page.Items = []*models.InAppNotification{}
+	}
+	if len(items) == limit {
+		page.NextCursor = strconv.FormatInt(items[len(items)-1].ID, 10)
+	}
+	return page, nil
+}
+
+// UnreadCount returns the number of unread notifications in the user's inbox.
+func (s *InboxService) UnreadCount(ctx context.Context, userID int64) (int, error) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, inboxDatabaseError("count_unread", userID, err)
+	}
+	return count, nil
+}
+
+// MarkRead marks one of the user's notifications as read.
+func (s *InboxService) MarkRead(ctx context.Context, userID, id int64) (*models.InAppNotification, error) {
+	notification, err := s.find(ctx, userID, id)
+	if err != nil || notification.ReadAt != nil {
+		return notification, err
+	}
+	now := s.now()
+	if err := s.repo.SetRead(ctx, id, &now); err != nil {
+		return nil, inboxDatabaseError("mark_read", userID, err)
+	}
+	notification.ReadAt = &now
+	s.pushUnreadCount(ctx, userID)
+	return notification, nil
+}
+
+// MarkUnread marks one of the user's notifications as unread again.
+func (s *InboxService) MarkUnread(ctx context.Context, userID, id int64) (*models.InAppNotification, error) {
+	notification, err := s.find(ctx, userID, id)
+	if err != nil || notification.ReadAt == nil {
+		return notification, err
+	}
+	if err := s.repo.SetRead(ctx, id, nil); err != nil {
+		return nil, inboxDatabaseError("mark_unread", userID, err)
+	}
+	notification.ReadAt = nil
+	s.pushUnreadCount(ctx, userID)
+	return notification, nil
+}
+
+// Archive moves one of the user's notifications out of the inbox.
+func (s *InboxService) Archive(ctx context.Context, userID, id int64) (*models.InAppNotification, error) {
+	notification, err := s.find(ctx, userID, id)
+	if err != nil || notification.ArchivedAt != nil {
+		return notification, err
+	}
+	now := s.now()
+	if err := s.repo.SetArchived(ctx, id, &now); err != nil {
+		return nil, inboxDatabaseError("archive", userID, err)
+	}
+	notification.ArchivedAt = &now
+	if notification.ReadAt == nil {
+		s.pushUnreadCount(ctx, userID)
+	}
+	return notification, nil
+}
+
+// MarkAllRead marks every unread notification in the user's inbox as read.
+func (s *InboxService) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	updated, err := s.repo.MarkAllRead(ctx, userID, s.now())
+	if err != nil {
+		return 0, inboxDatabaseError("mark_all_read", userID, err)
+	}
+	if updated > 0 {
+		s.pushUnreadCount(ctx, userID)
+	}
+	return updated, nil
+}
+
+// OpenStream subscribes to the user's inbox events. lastEventID is the client's
+// Last-Event-ID header: the ID of the last notification it received, if any.
+// The caller must Close the stream.
+func (s *InboxService) OpenStream(ctx context.Context, userID int64, lastEventID string) (*InboxStream, error) {
+	if s.hub == nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeInboxUnavailable).
+			Hint("Realtime notifications are not available").
+			Errorf("realtime hub unavailable")
+	}
+	// Subscribe before reading the inbox, so nothing stored in between is missed.
+	sub, err := s.hub.Subscribe(userID)
+	if errors.Is(err, realtime.ErrTooManyStreams) {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeRateLimited).
+			With("user_id", userID).
+			Hint("Too many open notification streams; close another tab or device").
+			Wrap(err)
+	}
+	if err != nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeInboxUnavailable).
+			Hint("Realtime notifications are not available").
+			Wrap(err)
+	}
+
+	stream := &InboxStream{Subscription: sub}
+	if afterID, err := strconv.ParseInt(lastEventID, 10, 64); err == nil && afterID > 0 {
+		if stream.Missed, err = s.repo.ListAfter(ctx, userID, afterID, maxInboxReplay); err != nil {
+			sub.Close()
+			return nil, inboxDatabaseError("replay_inbox", userID, err)
+		}
+	}
+	if stream.Unread, err = s.repo.CountUnread(ctx, userID); err != nil {
+		sub.Close()
+		return nil, inboxDatabaseError("count_unread", userID, err)
+	}
+	return stream, nil
+}
+
+func (s *InboxService) find(ctx context.Context, userID, id int64) (*models.InAppNotification, error) {
+	notification, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, inboxDatabaseError("get_notification", userID, err)
+	}
+	if notification == nil || notification.UserID != userID {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeNotFound).
+			With("user_id", userID).
+			With("notification_id", id).
+			Hint("Notification not found").
+			Errorf("notification not found")
+	}
+	return notification, nil
+}
+
+// pushUnreadCount sends the current unread count to the user's open streams, so
+// their other tabs and devices stay in step. Failures are only logged.
+func (s *InboxService) pushUnreadCount(ctx context.Context, userID int64) {
+	if s.hub == nil {
+		return
+	}
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		logger.Warnf("[inbox] failed to count unread notifications of user %d: %v", userID, err)
+		return
+	}
+	if err := s.hub.Publish(ctx, dto.NewUnreadCountMessage(userID, count)); err != nil {
+		logger.Warnf("[inbox] failed to push unread count to user %d: %v", userID, err)
+	}
+}
+
+func inboxDatabaseError(operation string, userID int64, err error) error {
+	return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+		With("operation", operation).
+		With("user_id", userID).
+		Wrap(err)
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/notification/realtime"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockInboxRepo struct {
+	mock.Mock
+}
+
+func (m *mockInboxRepo) FindByID(ctx context.Context, id int64) (*models.InAppNotification, error) {
+	args := m.Called(ctx, id)
+	notification, _ := args.Get(0).(*models.InAppNotification)
+	return notification, args.Error(1)
+}
+
+func (m *mockInboxRepo) ListByUser(ctx context.Context, userID int64, filter models.InboxFilter, beforeID int64, limit int) ([]*models.InAppNotification, error) {
+	args := m.Called(ctx, userID, filter, beforeID, limit)
+	notifications, _ := args.Get(0).([]*models.InAppNotification)
+	return notifications, args.Error(1)
+}
+
+func (m *mockInboxRepo) ListAfter(ctx context.Context, userID, afterID int64, limit int) ([]*models.InAppNotification, error) {
+	args := m.Called(ctx, userID, afterID, limit)
+	notifications, _ := args.Get(0).([]*models.InAppNotification)
+	return notifications, args.Error(1)
+}
+
+func (m *mockInboxRepo) CountUnread(ctx context.Context, userID int64) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockInboxRepo) SetRead(ctx context.Context, id int64, at *time.Time) error {
+	return m.Called(ctx, id, at).Error(0)
+}
+
+func (m *mockInboxRepo) SetArchived(ctx context.Context, id int64, at *time.Time) error {
+	return m.Called(ctx, id, at).Error(0)
+}
+
+func (m *mockInboxRepo) MarkAllRead(ctx context.Context, userID int64, at time.Time) (int64, error) {
+	args := m.Called(ctx, userID, at)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func setupInboxSvc() (*InboxService, *mockInboxRepo, *realtime.Hub) {
+	repo := new(mockInboxRepo)
+	hub := realtime.NewHub(realtime.Config{}, nil)
+	return NewInboxService(repo, hub), repo, hub
+}
+
+func inboxNotifications(ids ...int64) []*models.InAppNotification {
+	notifications := make([]*models.InAppNotification, 0, len(ids))
+	for _, id := range ids {
+		notifications = append(notifications, &models.InAppNotification{ID: id, UserID: 42})
+	}
+	return notifications
+}
+
+func requireUnreadCountPushed(t *testing.T, sub *realtime.Subscription, unread int) {
+	t.Helper()
+	select {
+	case msg := <-sub.C:
+		assert.Equal(t, dto.InboxEventUnreadCount, msg.Event)
+		var count dto.UnreadCountResponse
+		require.NoError(t, json.Unmarshal(msg.Data, &count))
+		assert.Equal(t, unread, count.Unread)
+	default:
+		t.Fatal("unread count was not pushed")
+	}
+}
+
+// ============================================================================
+// List
+// ============================================================================
+
+func TestInboxList_FullPageReturnsNextCursor(t *testing.T) {
+	svc, repo, _ := setupInboxSvc()
+	repo.On("ListByUser", mock.Anything, int64(42), models.InboxFilterUnread, int64(0), 2).
+		Return(inboxNotifications(9, 7), nil)
+
+	page, err := svc.List(context.Background(), 42, dto.InboxListRequest{Filter: "unread", Limit: 2})
+
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "7", page.NextCursor)
+}
+
+func TestInboxList_LastPageHasNoCursor(t *testing.T) {
+	svc, repo, _ := setupInboxSvc()
+	repo.On("ListByUser", mock.Anything, int64(42), models.InboxFilterInbox, int64(7), defaultInboxPageSize).
+		Return(nil, nil)
+
+	page, err := svc.List(context.Background(), 42, dto.InboxListRequest{Cursor: "7"})
+
+	require.NoError(t, err)
+	assert.NotNil(t, page.Items)
+	assert.Empty(t, page.Items)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestInboxList_InvalidCursor(t *testing.T) {
+	svc, repo, _ := setupInboxSvc()
+
+	_, err := svc.List(context.Background(), 42, dto.InboxListRequest{Cursor: "abc"})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+	repo.AssertNotCalled(t, "ListByUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ============================================================================
+// Read / Unread / Archive
+// ============================================================================
+
+func TestInboxMarkRead_PushesUnreadCount(t *testing.T) {
+	svc, repo, hub := setupInboxSvc()
+	sub, err := hub.Subscribe(42)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	repo.On("FindByID", mock.Anything, int64(5)).Return(&models.InAppNotification{ID: 5, UserID: 42}, nil)
+	repo.On("SetRead", mock.Anything, int64(5), mock.AnythingOfType("*time.Time")).Return(nil)
+	repo.On("CountUnread", mock.Anything, int64(42)).Return(2, nil)
+
+	notification, err := svc.MarkRead(context.Background(), 42, 5)
+
+	require.NoError(t, err)
+	assert.True(t, notification.IsRead())
+	requireUnreadCountPushed(t, sub, 2)
+}
+
+func TestInboxMarkRead_AlreadyReadIsNoOp(t *testing.T) {
+	svc, repo, _ := setupInboxSvc()
+	readAt := time.Now()
+	repo.On("FindByID", mock.Anything, int64(5)).Return(&models.InAppNotification{ID: 5, UserID: 42, ReadAt: &readAt}, nil)
+
+	_, err := svc.MarkRead(context.Background(), 42, 5)
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "SetRead", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInboxMarkUnread_ClearsReadAt(t *testing.T) {
+	svc, repo, _ := setupInboxSvc()
+	readAt := time.Now()
+	repo.On("FindByID", mock.Anything, int64(5)).Return(&models.InAppNotification{ID: 5, UserID: 42, ReadAt: &readAt}, nil)
+	repo.On("SetRead", mock.Anything, int64(5), (*time.Time)(nil)).Return(nil)
+	repo.On("CountUnread", mock.Anything, int64(42)).Return(1, nil)
+
+	notification, err := svc.MarkUnread(context.Background(), 42, 5)
+
+	require.NoError(t, err)
+	assert.False(t, notification.IsRead())
+}
+
+func TestInboxArchive_OtherUsersNotificationIsNotFound(t *testing.T) {
+	svc, repo, _ := setupInboxSvc()
+	repo.On("FindByID", mock.Anything, int64(5)).Return(&models.InAppNotification{ID: 5, UserID: 7}, nil)
+
+	_, err := svc.Archive(context.Background(), 42, 5)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+	repo.AssertNotCalled(t, "SetArchived", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInboxMarkAllRead_DatabaseError(t *testing.T) {
+	svc, repo, _ := setupInboxSvc()
+	repo.On("MarkAllRead", mock.Anything, int64(42), mock.Anything).Return(int64(0), errors.New("connection reset"))
+
+	_, err := svc.MarkAllRead(context.Background(), 42)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeDatabase)
+}
+
+// ============================================================================
+// OpenStream
+// ============================================================================
+
+func TestInboxOpenStream_ReplaysMissedNotifications(t *testing.T) {
+	svc, repo, _ := setupInboxSvc()
+	repo.On("ListAfter", mock.Anything, int64(42), int64(7), maxInboxReplay).Return(inboxNotifications(8, 9), nil)
+	repo.On("CountUnread", mock.Anything, int64(42)).Return(4, nil)
+
+	stream, err := svc.OpenStream(context.Background(), 42, "7")
+
+	require.NoError(t, err)
+	defer stream.Close()
+	assert.Len(t, stream.Missed, 2)
+	assert.Equal(t, 4, stream.Unread)
+}
+
+func TestInboxOpenStream_WithoutLastEventIDSkipsReplay(t *testing.T) {
+	svc, repo, _ := setupInboxSvc()
+	repo.On("CountUnread", mock.Anything, int64(42)).Return(0, nil)
+
+	stream, err := svc.OpenStream(context.Background(), 42, "")
+
+	require.NoError(t, err)
+	defer stream.Close()
+	assert.Empty(t, stream.Missed)
+	repo.AssertNotCalled(t, "ListAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInboxOpenStream_TooManyStreams(t *testing.T) {
+	repo := new(mockInboxRepo)
+	hub := realtime.NewHub(realtime.Config{MaxStreamsPerUser: 1}, nil)
+	svc := NewInboxService(repo, hub)
+	repo.On("CountUnread", mock.Anything, int64(42)).Return(0, nil)
+
+	first, err := svc.OpenStream(context.Background(), 42, "")
+	require.NoError(t, err)
+	defer first.Close()
+
+	_, err = svc.OpenStream(context.Background(), 42, "")
+	assertErrorCode(t, err, pkgErrors.ErrCodeRateLimited)
+}
+
+func TestInboxOpenStream_WithoutHubIsUnavailable(t *testing.T) {
+	svc := NewInboxService(new(mockInboxRepo), nil)
+
+	_, err := svc.OpenStream(context.Background(), 42, "")
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeInboxUnavailable)
+}
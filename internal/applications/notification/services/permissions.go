@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ichi-go/pkg/authenticator"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/requestctx"
+)
+
+// PermissionChecker checks RBAC permissions; satisfied by *rbacServices.EnforcementService.
+type PermissionChecker interface {
+	CheckPermission(ctx context.Context, userID int64, tenantID, resource, action string) (bool, error)
+}
+
+// authorizePermission returns the caller's tenant once they hold permission
+// ("resource:action") in it. The tenant is the one of the request context (the
+// API key's tenant, or the X-Tenant-Id header), falling back to defaultTenant.
+// API keys must also carry a scope allowing the permission. A nil checker denies
+// every request. what names the managed objects in the denial hints.
+func authorizePermission(
+	ctx context.Context,
+	authCtx authenticator.AuthContext,
+	permissions PermissionChecker,
+	defaultTenant, permission, what string,
+) (string, error) {
+	tenantID := requestctx.GetTenantID(ctx)
+	if tenantID == "" {
+		tenantID = defaultTenant
+	}
+	if err := checkPermission(ctx, authCtx, permissions, tenantID, permission, what); err != nil {
+		return "", err
+	}
+	return tenantID, nil
+}
+
+// authorizePlatformPermission checks permission in platformTenant whatever the
+// tenant of the request, for objects shared by all tenants: a role granted in a
+// tenant the caller administers must not reach them.
+func authorizePlatformPermission(
+	ctx context.Context,
+	authCtx authenticator.AuthContext,
+	permissions PermissionChecker,
+	platformTenant, permission, what string,
+) error {
+	return checkPermission(ctx, authCtx, permissions, platformTenant, permission, what)
+}
+
+func checkPermission(
+	ctx context.Context,
+	authCtx authenticator.AuthContext,
+	permissions PermissionChecker,
+	tenantID, permission, what string,
+) error {
+	resource, action, _ := strings.Cut(permission, ":")
+	if authCtx.APIKey != nil && !authenticator.ScopeAllows(authCtx.APIKey.Scopes, resource, action) {
+		return forbiddenError(permission, "API key scope does not allow managing "+what)
+	}
+	if permissions == nil {
+		return forbiddenError(permission, "Permission checks are unavailable")
+	}
+	allowed, err := permissions.CheckPermission(ctx, int64(authCtx.UserID.ID), tenantID, resource, action)
+	if err != nil {
+		return pkgErrors.NotificationService(pkgErrors.ErrCodeInternal).
+			With("user_id", authCtx.UserID.ID).
+			With("tenant_id", tenantID).
+			Hint("Permission check failed").
+			Wrap(err)
+	}
+	if !allowed {
+		return forbiddenError(permission, fmt.Sprintf("You may not manage %s", what))
+	}
+	return nil
+}
+
+func forbiddenError(permission, hint string) error {
+	return pkgErrors.NotificationService(pkgErrors.ErrCodeForbidden).
+		With("permission", permission).
+		Hint(hint).
+		Errorf("permission denied")
+}
@@ -0,0 +1,496 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/preference"
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+// metaDeferredUntil marks an event re-queued until the end of quiet hours (RFC 3339).
+// A deferred event is not deferred again.
+const metaDeferredUntil = "deferred_until"
+
+// preferenceChannels are the channels users choose; webhooks belong to the tenant.
+var preferenceChannels = []dto.Channel{dto.ChannelEmail, dto.ChannelPush, dto.ChannelSMS, dto.ChannelInApp}
+
+// PreferenceRepository is the minimal interface PreferenceService uses for preferences.
+// The concrete *repositories.NotificationPreferenceRepository satisfies this interface.
+type PreferenceRepository interface {
+	Get(ctx context.Context, userID int64) (*models.NotificationPreference, error)
+	Save(ctx context.Context, pref *models.NotificationPreference) error
+	SetOptOut(ctx context.Context, userID int64, category, channel string, optedOut bool) error
+}
+
+// PreferenceService manages the notification preferences of users and applies them
+// to the events dispatch delivers. The rules per event come from the Classification
+// of its template (see notiftemplate.Classification).
+type PreferenceService struct {
+	repo     PreferenceRepository
+	registry *notiftemplate.Registry
+	producer rabbitmq.MessageProducer // app.events; nil sends quiet-hour notifications at once
+	signer   *preference.Signer       // nil disables unsubscribe links
+	cfg      preference.Config
+	now      func() time.Time
+}
+
+// NewPreferenceService creates a PreferenceService. producer may be nil when the
+// queue is unavailable, signer may be nil when no unsubscribe secret is configured.
+func NewPreferenceService(
+	repo PreferenceRepository,
+	registry *notiftemplate.Registry,
+	producer rabbitmq.MessageProducer,
+	signer *preference.Signer,
+	cfg preference.Config,
+) *PreferenceService {
+	return &PreferenceService{
+		repo:     repo,
+		registry: registry,
+		producer: producer,
+		signer:   signer,
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// Get returns the preferences of a user, with every category they can choose from.
+func (s *PreferenceService) Get(ctx context.Context, userID int64) (*dto.PreferencesResponse, error) {
+	pref, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, preferenceDatabaseError("get_preferences", userID, err)
+	}
+	if pref == nil {
+		pref = &models.NotificationPreference{UserID: userID}
+	}
+
+	resp := &dto.PreferencesResponse{
+		Timezone:        pref.Timezone,
+		Locale:          pref.Locale,
+		UnsubscribedAll: pref.UnsubscribedAt != nil,
+		Categories:      []dto.CategoryPreference{},
+	}
+	if resp.Timezone == "" {
+		resp.Timezone = s.cfg.DefaultTimezone
+	}
+	if pref.QuietHoursStart != "" && pref.QuietHoursEnd != "" {
+		resp.QuietHours = &dto.QuietHours{Start: pref.QuietHoursStart, End: pref.QuietHoursEnd}
+	}
+
+	for _, cat := range s.categories() {
+		item := dto.CategoryPreference{
+			Category: cat.name,
+			Kind:     string(cat.kind),
+			Required: cat.required,
+			Channels: make(map[dto.Channel]bool, len(cat.channels)),
+		}
+		for _, ch := range cat.channels {
+			item.Channels[ch] = !pref.IsOptedOut(cat.name, string(ch))
+		}
+		resp.Categories = append(resp.Categories, item)
+	}
+	return resp, nil
+}
+
+// Update changes the preferences set in req and returns the result.
+func (s *PreferenceService) Update(ctx context.Context, userID int64, req dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error) {
+	categories := make(map[string]category)
+	for _, cat := range s.categories() {
+		categories[cat.name] = cat
+	}
+	for _, p := range req.Channels {
+		cat, ok := categories[p.Category]
+		if !ok || !cat.has(p.Channel) {
+			return nil, pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+				With("category", p.Category).
+				With("channel", p.Channel).
+				Hint("Unknown category or channel; see GET /api/notifications/preferences").
+				Errorf("unknown preference %s/%s", p.Category, p.Channel)
+		}
+	}
+
+	if req.Timezone != nil || req.Locale != nil || req.QuietHours != nil || req.UnsubscribedAll != nil {
+		pref, err := s.repo.Get(ctx, userID)
+		if err != nil {
+			return nil, preferenceDatabaseError("get_preferences", userID, err)
+		}
+		if pref == nil {
+			pref = &models.NotificationPreference{UserID: userID}
+		}
+		if err := s.apply(pref, req); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Save(ctx, pref); err != nil {
+			return nil, preferenceDatabaseError("save_preferences", userID, err)
+		}
+	}
+
+	for _, p := range req.Channels {
+		if err := s.repo.SetOptOut(ctx, userID, p.Category, string(p.Channel), !p.Enabled); err != nil {
+			return nil, preferenceDatabaseError("set_opt_out", userID, err)
+		}
+	}
+
+	return s.Get(ctx, userID)
+}
+
+// apply copies the settings of req onto pref, validating them.
+func (s *PreferenceService) apply(pref *models.NotificationPreference, req dto.UpdatePreferencesRequest) error {
+	if req.Timezone != nil {
+		if _, err := preference.LoadLocation(*req.Timezone); err != nil {
+			return pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+				With("timezone", *req.Timezone).
+				Hint("Use an IANA time zone name, e.g. Asia/Jakarta").
+				Wrap(err)
+		}
+		pref.Timezone = *req.Timezone
+	}
+
+	if req.Locale != nil {
+		pref.Locale = ""
+		if *req.Locale != "" {
+			locale, err := notiftemplate.ParseLocale(*req.Locale)
+			if err != nil {
+				return pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+					With("locale", *req.Locale).
+					Hint("Use a BCP-47 language tag, e.g. id-ID or en").
+					Wrap(err)
+			}
+			pref.Locale = locale
+		}
+	}
+
+	if q := req.QuietHours; q != nil {
+		if (q.Start == "") != (q.End == "") || (q.Start != "" && q.Start == q.End) {
+			return pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+				Hint("Quiet hours need a different start and end, or neither to turn them off").
+				Errorf("invalid quiet hours %q-%q", q.Start, q.End)
+		}
+		pref.QuietHoursStart, pref.QuietHoursEnd = q.Start, q.End
+	}
+
+	if req.UnsubscribedAll != nil {
+		switch {
+		case *req.UnsubscribedAll && pref.UnsubscribedAt == nil:
+			now := s.now()
+			pref.UnsubscribedAt = &now
+		case !*req.UnsubscribedAll:
+			pref.UnsubscribedAt = nil
+		}
+	}
+	return nil
+}
+
+// DescribeUnsubscribe returns what the one-click unsubscribe token opts out of, without changing anything.
+func (s *PreferenceService) DescribeUnsubscribe(ctx context.Context, token string) (*dto.UnsubscribeResponse, error) {
+	_, resp, err := s.resolveToken(token)
+	return resp, err
+}
+
+// Unsubscribe applies the opt-out of a one-click unsubscribe token. Repeating it is a no-op.
+func (s *PreferenceService) Unsubscribe(ctx context.Context, token string) (*dto.UnsubscribeResponse, error) {
+	t, resp, err := s.resolveToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.All {
+		pref, err := s.repo.Get(ctx, t.UserID)
+		if err != nil {
+			return nil, preferenceDatabaseError("get_preferences", t.UserID, err)
+		}
+		if pref == nil {
+			pref = &models.NotificationPreference{UserID: t.UserID}
+		}
+		if pref.UnsubscribedAt == nil {
+			now := s.now()
+			pref.UnsubscribedAt = &now
+			if err := s.repo.Save(ctx, pref); err != nil {
+				return nil, preferenceDatabaseError("unsubscribe_all", t.UserID, err)
+			}
+		}
+	} else {
+		channels := []string{resp.Channel}
+		if resp.Channel == "" {
+			channels = channels[:0]
+			for _, cat := range s.categories() {
+				if cat.name == resp.Category {
+					for _, ch := range cat.channels {
+						channels = append(channels, string(ch))
+					}
+				}
+			}
+		}
+		for _, ch := range channels {
+			if err := s.repo.SetOptOut(ctx, t.UserID, resp.Category, ch, true); err != nil {
+				return nil, preferenceDatabaseError("unsubscribe", t.UserID, err)
+			}
+		}
+	}
+
+	logger.Infof("[preferences] user %d unsubscribed all=%t category=%s channel=%s",
+		t.UserID, resp.All, resp.Category, resp.Channel)
+	resp.Unsubscribed = true
+	return resp, nil
+}
+
+func (s *PreferenceService) resolveToken(token string) (preference.UnsubscribeToken, *dto.UnsubscribeResponse, error) {
+	invalid := func(err error) error {
+		return pkgErrors.Validation(pkgErrors.ErrCodeUnsubscribeInvalid).
+			Hint("This unsubscribe link is invalid; manage your notifications in your account settings").
+			Wrap(err)
+	}
+	if s.signer == nil {
+		return preference.UnsubscribeToken{}, nil, invalid(fmt.Errorf("unsubscribe links are disabled"))
+	}
+	t, err := s.signer.Verify(token)
+	if err != nil {
+		return preference.UnsubscribeToken{}, nil, invalid(err)
+	}
+	if t.EventType == "" {
+		return t, &dto.UnsubscribeResponse{All: true}, nil
+	}
+	tmpl, ok := s.registry.Get(t.EventType)
+	if !ok {
+		return preference.UnsubscribeToken{}, nil, invalid(fmt.Errorf("event %q is no longer registered", t.EventType))
+	}
+	return t, &dto.UnsubscribeResponse{Category: tmpl.Classification().Category, Channel: t.Channel}, nil
+}
+
+// ============================================================================
+// Dispatch
+// ============================================================================
+
+// DeliveryPlan is what the preferences of the recipient decide for the channels of
+// one event, and the locale it is rendered in. A nil plan delivers every channel at
+// once, in the locale of the event.
+type DeliveryPlan struct {
+	skipped    map[dto.Channel]string
+	deferred   map[dto.Channel]bool
+	deferUntil time.Time
+	locale     string
+}
+
+// Decide returns why channel is skipped, or whether it waits for the end of quiet hours.
+func (p *DeliveryPlan) Decide(channel dto.Channel) (skipReason string, deferred bool) {
+	if p == nil {
+		return "", false
+	}
+	if reason, ok := p.skipped[channel]; ok {
+		return reason, false
+	}
+	return "", p.deferred[channel]
+}
+
+// Locale is the locale the event is rendered in: the recipient's preferred locale,
+// or else the locale of event, empty when neither is set.
+func (p *DeliveryPlan) Locale(event dto.NotificationEvent) string {
+	if p == nil || p.locale == "" {
+		return event.Locale
+	}
+	return p.locale
+}
+
+// DeferUntil is the end of the recipient's quiet hours, zero when nothing is deferred.
+func (p *DeliveryPlan) DeferUntil() time.Time {
+	if p == nil {
+		return time.Time{}
+	}
+	return p.deferUntil
+}
+
+// Plan applies the preferences of the recipient of a user event. Blast events,
+// unregistered events and users without preferences get a nil plan: they are
+// delivered as they are. Urgent transactional events, and every event when
+// preferences are disabled, only take the recipient's preferred locale.
+// Safe to call on a nil *PreferenceService.
+func (s *PreferenceService) Plan(ctx context.Context, event dto.NotificationEvent) (*DeliveryPlan, error) {
+	if s == nil || event.DeliveryMode != dto.DeliveryModeUser {
+		return nil, nil
+	}
+	userID, err := strconv.ParseInt(event.UserID, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	tmpl, ok := s.registry.Get(event.EventType)
+	if !ok {
+		return nil, nil
+	}
+
+	pref, err := s.repo.Get(ctx, userID)
+	if err != nil || pref == nil {
+		return nil, err
+	}
+
+	plan := &DeliveryPlan{
+		skipped:  make(map[dto.Channel]string),
+		deferred: make(map[dto.Channel]bool),
+		locale:   pref.Locale,
+	}
+	class := tmpl.Classification()
+	if !s.cfg.Enabled || (class.Kind == notiftemplate.KindTransactional && class.Urgent) {
+		return plan, nil
+	}
+	for _, ch := range event.Channels {
+		switch {
+		case class.Kind == notiftemplate.KindMarketing && pref.UnsubscribedAt != nil:
+			plan.skipped[ch] = "preferences: unsubscribed from marketing"
+		case pref.IsOptedOut(class.Category, string(ch)):
+			plan.skipped[ch] = fmt.Sprintf("preferences: opted out of %s via %s", class.Category, ch)
+		}
+	}
+
+	if until, quiet := s.quietUntil(pref, event, class); quiet {
+		for _, ch := range event.Channels {
+			if _, skipped := plan.skipped[ch]; !skipped && s.cfg.IsQuietChannel(string(ch)) {
+				plan.deferred[ch] = true
+				plan.deferUntil = until
+			}
+		}
+	}
+	return plan, nil
+}
+
+// quietUntil reports whether event falls in the quiet hours of pref, and when they end.
+func (s *PreferenceService) quietUntil(pref *models.NotificationPreference, event dto.NotificationEvent, class notiftemplate.Classification) (time.Time, bool) {
+	if class.Urgent || s.producer == nil || event.Meta[metaDeferredUntil] != "" {
+		return time.Time{}, false
+	}
+	if pref.QuietHoursStart == "" || pref.QuietHoursEnd == "" {
+		return time.Time{}, false
+	}
+	start, errStart := preference.ParseClock(pref.QuietHoursStart)
+	end, errEnd := preference.ParseClock(pref.QuietHoursEnd)
+	if errStart != nil || errEnd != nil {
+		logger.Warnf("[preferences] user %d has invalid quiet hours %q-%q, ignoring them",
+			pref.UserID, pref.QuietHoursStart, pref.QuietHoursEnd)
+		return time.Time{}, false
+	}
+
+	tz := pref.Timezone
+	if tz == "" {
+		tz = s.cfg.DefaultTimezone
+	}
+	loc, err := preference.LoadLocation(tz)
+	if err != nil {
+		logger.Warnf("[preferences] user %d: %v, using UTC", pref.UserID, err)
+		loc = time.UTC
+	}
+	return preference.QuietHours{Start: start, End: end}.Until(s.now(), loc)
+}
+
+// Defer re-queues event for channels until the end of quiet hours, through the
+// delayed app.events exchange and the dispatcher.
+func (s *PreferenceService) Defer(ctx context.Context, event dto.NotificationEvent, channels []dto.Channel, until time.Time) error {
+	if s == nil || s.producer == nil {
+		return fmt.Errorf("preferences: message queue is unavailable")
+	}
+
+	deferred := event
+	deferred.EventID = event.EventID + ":deferred"
+	deferred.Channels = channels
+	deferred.Meta = make(map[string]string, len(event.Meta)+1)
+	for k, v := range event.Meta {
+		deferred.Meta[k] = v
+	}
+	deferred.Meta[metaDeferredUntil] = until.UTC().Format(time.RFC3339)
+
+	delay := until.Sub(s.now())
+	if delay < 0 {
+		delay = 0
+	}
+	return s.producer.Publish(ctx, dispatchRoutingKey, deferred, rabbitmq.PublishOptions{
+		Delay: delay,
+		Headers: amqp.Table{
+			"x-event-type":    deferred.EventType,
+			"x-event-id":      deferred.EventID,
+			"x-delivery-mode": string(deferred.DeliveryMode),
+		},
+	})
+}
+
+// ============================================================================
+// Categories
+// ============================================================================
+
+// category is an event category of the registry and the channels its events use.
+type category struct {
+	name     string
+	kind     notiftemplate.Kind
+	required bool // only urgent transactional events: preferences do not apply
+	channels []dto.Channel
+}
+
+func (c category) has(ch dto.Channel) bool {
+	for _, own := range c.channels {
+		if own == ch {
+			return true
+		}
+	}
+	return false
+}
+
+// categories lists the categories of the registered events, sorted by name.
+// A category holding any marketing event is marketing.
+func (s *PreferenceService) categories() []category {
+	byName := make(map[string]*category)
+	for _, slug := range s.registry.Slugs() {
+		tmpl, ok := s.registry.Get(slug)
+		if !ok {
+			continue
+		}
+		class := tmpl.Classification()
+		if class.Category == "" {
+			continue
+		}
+		cat := byName[class.Category]
+		if cat == nil {
+			cat = &category{name: class.Category, kind: notiftemplate.KindTransactional, required: true}
+			byName[class.Category] = cat
+		}
+		if class.Kind == notiftemplate.KindMarketing {
+			cat.kind = notiftemplate.KindMarketing
+		}
+		if class.Kind != notiftemplate.KindTransactional || !class.Urgent {
+			cat.required = false
+		}
+		for _, ch := range preferenceChannels {
+			if !cat.has(ch) && supports(tmpl, ch) {
+				cat.channels = append(cat.channels, ch)
+			}
+		}
+	}
+
+	out := make([]category, 0, len(byName))
+	for _, cat := range byName {
+		out = append(out, *cat)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+func supports(tmpl notiftemplate.EventTemplate, ch dto.Channel) bool {
+	for _, c := range tmpl.SupportedChannels() {
+		if c == string(ch) {
+			return true
+		}
+	}
+	return false
+}
+
+func preferenceDatabaseError(operation string, userID int64, err error) error {
+	return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+		With("operation", operation).
+		With("user_id", userID).
+		Wrap(err)
+}
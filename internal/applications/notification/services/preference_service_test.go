@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/notification/preference"
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockPreferenceRepo struct {
+	mock.Mock
+}
+
+func (m *mockPreferenceRepo) Get(ctx context.Context, userID int64) (*models.NotificationPreference, error) {
+	args := m.Called(ctx, userID)
+	pref, _ := args.Get(0).(*models.NotificationPreference)
+	return pref, args.Error(1)
+}
+
+func (m *mockPreferenceRepo) Save(ctx context.Context, pref *models.NotificationPreference) error {
+	return m.Called(ctx, pref).Error(0)
+}
+
+func (m *mockPreferenceRepo) SetOptOut(ctx context.Context, userID int64, category, channel string, optedOut bool) error {
+	return m.Called(ctx, userID, category, channel, optedOut).Error(0)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// 23:30 in Asia/Jakarta (UTC+7).
+var preferenceNow = time.Date(2026, 3, 1, 16, 30, 0, 0, time.UTC)
+
+func setupPreferenceSvc() (*PreferenceService, *mockPreferenceRepo, *mockProducer) {
+	reg := notiftemplate.NewRegistry()
+	reg.Register(&mockEventTemplate{
+		slug:     "auth.password_reset",
+		channels: []string{"email", "sms"},
+		class:    notiftemplate.Classification{Category: "account", Kind: notiftemplate.KindTransactional, Urgent: true},
+	})
+	reg.Register(&mockEventTemplate{
+		slug:     "order.shipped",
+		channels: []string{"email", "push", "sms"},
+		class:    notiftemplate.Classification{Category: "orders", Kind: notiftemplate.KindTransactional},
+	})
+	reg.Register(&mockEventTemplate{
+		slug:     "promo.weekly",
+		channels: []string{"email", "push"},
+		class:    notiftemplate.Classification{Category: "promotions", Kind: notiftemplate.KindMarketing},
+	})
+
+	repo := new(mockPreferenceRepo)
+	producer := new(mockProducer)
+	svc := NewPreferenceService(repo, reg, producer, preference.NewSigner("secret"), preference.Config{
+		Enabled:         true,
+		QuietChannels:   []string{"push", "sms"},
+		DefaultTimezone: "UTC",
+	})
+	svc.now = func() time.Time { return preferenceNow }
+	return svc, repo, producer
+}
+
+func userEvent(eventType string, channels ...dto.Channel) dto.NotificationEvent {
+	return dto.NotificationEvent{
+		EventID:      "evt-1",
+		EventType:    eventType,
+		DeliveryMode: dto.DeliveryModeUser,
+		UserID:       "42",
+		Channels:     channels,
+	}
+}
+
+func quietPreference() *models.NotificationPreference {
+	return &models.NotificationPreference{
+		UserID:          42,
+		Timezone:        "Asia/Jakarta",
+		QuietHoursStart: "22:00",
+		QuietHoursEnd:   "07:00",
+	}
+}
+
+// ============================================================================
+// Plan
+// ============================================================================
+
+func TestPlan_SkipsOptedOutChannels(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("Get", mock.Anything, int64(42)).Return(&models.NotificationPreference{
+		UserID:  42,
+		OptOuts: []*models.NotificationOptOut{{UserID: 42, Category: "orders", Channel: "push"}},
+	}, nil)
+
+	plan, err := svc.Plan(context.Background(), userEvent("order.shipped", dto.ChannelEmail, dto.ChannelPush))
+
+	require.NoError(t, err)
+	reason, deferred := plan.Decide(dto.ChannelPush)
+	assert.Equal(t, "preferences: opted out of orders via push", reason)
+	assert.False(t, deferred)
+	reason, _ = plan.Decide(dto.ChannelEmail)
+	assert.Empty(t, reason)
+}
+
+func TestPlan_UnsubscribedAllOnlySkipsMarketing(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("Get", mock.Anything, int64(42)).Return(&models.NotificationPreference{
+		UserID:         42,
+		UnsubscribedAt: timePtr(preferenceNow),
+	}, nil)
+
+	promo, err := svc.Plan(context.Background(), userEvent("promo.weekly", dto.ChannelEmail))
+	require.NoError(t, err)
+	reason, _ := promo.Decide(dto.ChannelEmail)
+	assert.Equal(t, "preferences: unsubscribed from marketing", reason)
+
+	order, err := svc.Plan(context.Background(), userEvent("order.shipped", dto.ChannelEmail))
+	require.NoError(t, err)
+	reason, _ = order.Decide(dto.ChannelEmail)
+	assert.Empty(t, reason)
+}
+
+func TestPlan_UrgentTransactionalIgnoresPreferences(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	pref := quietPreference()
+	pref.Locale = "id-ID"
+	pref.OptOuts = []*models.NotificationOptOut{{UserID: 42, Category: "account", Channel: "sms"}}
+	repo.On("Get", mock.Anything, int64(42)).Return(pref, nil)
+	event := userEvent("auth.password_reset", dto.ChannelEmail, dto.ChannelSMS)
+
+	plan, err := svc.Plan(context.Background(), event)
+
+	require.NoError(t, err)
+	for _, ch := range []dto.Channel{dto.ChannelEmail, dto.ChannelSMS} {
+		reason, deferred := plan.Decide(ch)
+		assert.Empty(t, reason, ch)
+		assert.False(t, deferred, ch)
+	}
+	assert.Equal(t, "id-ID", plan.Locale(event), "the preferred locale still applies")
+}
+
+func TestPlan_LocalePrefersUserOverEvent(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("Get", mock.Anything, int64(42)).Return(&models.NotificationPreference{UserID: 42, Locale: "id"}, nil).Once()
+	repo.On("Get", mock.Anything, int64(7)).Return(&models.NotificationPreference{UserID: 7}, nil).Once()
+	event := userEvent("order.shipped", dto.ChannelEmail)
+	event.Locale = "en-GB"
+
+	plan, err := svc.Plan(context.Background(), event)
+	require.NoError(t, err)
+	assert.Equal(t, "id", plan.Locale(event))
+
+	event.UserID = "7"
+	plan, err = svc.Plan(context.Background(), event)
+	require.NoError(t, err)
+	assert.Equal(t, "en-GB", plan.Locale(event), "no preferred locale keeps the event's")
+
+	var none *DeliveryPlan
+	assert.Equal(t, "en-GB", none.Locale(event))
+}
+
+func TestPlan_QuietHoursDeferInterruptiveChannels(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("Get", mock.Anything, int64(42)).Return(quietPreference(), nil)
+
+	plan, err := svc.Plan(context.Background(), userEvent("order.shipped", dto.ChannelEmail, dto.ChannelPush, dto.ChannelSMS))
+
+	require.NoError(t, err)
+	_, deferred := plan.Decide(dto.ChannelEmail)
+	assert.False(t, deferred, "email is not a quiet channel")
+	_, deferred = plan.Decide(dto.ChannelPush)
+	assert.True(t, deferred)
+	_, deferred = plan.Decide(dto.ChannelSMS)
+	assert.True(t, deferred)
+	// 07:00 in Jakarta the next morning.
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), plan.DeferUntil().UTC())
+}
+
+func TestPlan_DeferredEventIsNotDeferredAgain(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("Get", mock.Anything, int64(42)).Return(quietPreference(), nil)
+	event := userEvent("order.shipped", dto.ChannelPush)
+	event.Meta = map[string]string{"deferred_until": "2026-03-02T00:00:00Z"}
+
+	plan, err := svc.Plan(context.Background(), event)
+
+	require.NoError(t, err)
+	_, deferred := plan.Decide(dto.ChannelPush)
+	assert.False(t, deferred)
+}
+
+func TestPlan_BlastAndNilServiceUseEveryChannel(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	blast := userEvent("promo.weekly", dto.ChannelEmail)
+	blast.DeliveryMode = dto.DeliveryModeBlast
+
+	plan, err := svc.Plan(context.Background(), blast)
+	require.NoError(t, err)
+	assert.Nil(t, plan)
+
+	var none *PreferenceService
+	plan, err = none.Plan(context.Background(), userEvent("promo.weekly", dto.ChannelEmail))
+	require.NoError(t, err)
+	reason, deferred := plan.Decide(dto.ChannelEmail)
+	assert.Empty(t, reason)
+	assert.False(t, deferred)
+	repo.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+// ============================================================================
+// Defer
+// ============================================================================
+
+func TestDefer_RepublishesRemainingChannelsWithDelay(t *testing.T) {
+	svc, _, producer := setupPreferenceSvc()
+	until := preferenceNow.Add(7*time.Hour + 30*time.Minute)
+	event := userEvent("order.shipped", dto.ChannelEmail, dto.ChannelPush)
+	event.Meta = map[string]string{"source": "orders"}
+
+	producer.On("Publish", mock.Anything, dispatchRoutingKey, mock.MatchedBy(func(e dto.NotificationEvent) bool {
+		return e.EventID == "evt-1:deferred" &&
+			len(e.Channels) == 1 && e.Channels[0] == dto.ChannelPush &&
+			e.Meta["source"] == "orders" &&
+			e.Meta["deferred_until"] == "2026-03-02T00:00:00Z"
+	}), mock.MatchedBy(func(opts rabbitmq.PublishOptions) bool {
+		return opts.Delay == 7*time.Hour+30*time.Minute
+	})).Return(nil)
+
+	err := svc.Defer(context.Background(), event, []dto.Channel{dto.ChannelPush}, until)
+
+	require.NoError(t, err)
+	producer.AssertExpectations(t)
+	assert.NotContains(t, event.Meta, "deferred_until", "the original event is not modified")
+}
+
+// ============================================================================
+// Get / Update
+// ============================================================================
+
+func TestGet_ListsCategoriesWithDefaults(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("Get", mock.Anything, int64(42)).Return(nil, nil)
+
+	prefs, err := svc.Get(context.Background(), 42)
+
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", prefs.Timezone)
+	assert.Nil(t, prefs.QuietHours)
+	require.Len(t, prefs.Categories, 3)
+	assert.Equal(t, "account", prefs.Categories[0].Category)
+	assert.True(t, prefs.Categories[0].Required)
+	assert.Equal(t, "promotions", prefs.Categories[2].Category)
+	assert.Equal(t, "marketing", prefs.Categories[2].Kind)
+	assert.Equal(t, map[dto.Channel]bool{dto.ChannelEmail: true, dto.ChannelPush: true}, prefs.Categories[2].Channels)
+}
+
+func TestUpdate_SavesSettingsAndOptOuts(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("Get", mock.Anything, int64(42)).Return(nil, nil)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(p *models.NotificationPreference) bool {
+		return p.UserID == 42 && p.Timezone == "Asia/Jakarta" &&
+			p.QuietHoursStart == "22:00" && p.QuietHoursEnd == "07:00"
+	})).Return(nil)
+	repo.On("SetOptOut", mock.Anything, int64(42), "orders", "push", true).Return(nil)
+
+	tz := "Asia/Jakarta"
+	_, err := svc.Update(context.Background(), 42, dto.UpdatePreferencesRequest{
+		Timezone:   &tz,
+		QuietHours: &dto.QuietHours{Start: "22:00", End: "07:00"},
+		Channels:   []dto.ChannelPreference{{Category: "orders", Channel: dto.ChannelPush, Enabled: false}},
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdate_RejectsUnknownPreferences(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("Get", mock.Anything, int64(42)).Return(nil, nil)
+	tz := "Mars/Olympus_Mons"
+
+	cases := map[string]dto.UpdatePreferencesRequest{
+		"unknown category":    {Channels: []dto.ChannelPreference{{Category: "weather", Channel: dto.ChannelEmail}}},
+		"unsupported channel": {Channels: []dto.ChannelPreference{{Category: "promotions", Channel: dto.ChannelSMS}}},
+		"unknown time zone":   {Timezone: &tz},
+		"half quiet hours":    {QuietHours: &dto.QuietHours{Start: "22:00"}},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Update(context.Background(), 42, req)
+			assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+		})
+	}
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "SetOptOut", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ============================================================================
+// Unsubscribe
+// ============================================================================
+
+func TestUnsubscribe_EventTokenOptsOutOfCategoryChannel(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("SetOptOut", mock.Anything, int64(42), "promotions", "email", true).Return(nil)
+	token := svc.signer.Sign(preference.UnsubscribeToken{UserID: 42, EventType: "promo.weekly", Channel: "email"})
+
+	resp, err := svc.Unsubscribe(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, &dto.UnsubscribeResponse{Category: "promotions", Channel: "email", Unsubscribed: true}, resp)
+	repo.AssertExpectations(t)
+}
+
+func TestUnsubscribe_GlobalTokenStopsMarketing(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	repo.On("Get", mock.Anything, int64(42)).Return(nil, nil)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(p *models.NotificationPreference) bool {
+		return p.UserID == 42 && p.UnsubscribedAt != nil && p.UnsubscribedAt.Equal(preferenceNow)
+	})).Return(nil)
+	token := svc.signer.Sign(preference.UnsubscribeToken{UserID: 42})
+
+	resp, err := svc.Unsubscribe(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.True(t, resp.All)
+	assert.True(t, resp.Unsubscribed)
+	repo.AssertExpectations(t)
+}
+
+func TestDescribeUnsubscribe_DoesNotChangeAnything(t *testing.T) {
+	svc, repo, _ := setupPreferenceSvc()
+	token := svc.signer.Sign(preference.UnsubscribeToken{UserID: 42, EventType: "order.shipped"})
+
+	resp, err := svc.DescribeUnsubscribe(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, &dto.UnsubscribeResponse{Category: "orders"}, resp)
+	repo.AssertNotCalled(t, "SetOptOut", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUnsubscribe_RejectsInvalidTokens(t *testing.T) {
+	svc, _, _ := setupPreferenceSvc()
+
+	_, err := svc.Unsubscribe(context.Background(), "forged.token")
+	assertErrorCode(t, err, pkgErrors.ErrCodeUnsubscribeInvalid)
+
+	unknown := svc.signer.Sign(preference.UnsubscribeToken{UserID: 42, EventType: "retired.event"})
+	_, err = svc.Unsubscribe(context.Background(), unknown)
+	assertErrorCode(t, err, pkgErrors.ErrCodeUnsubscribeInvalid)
+
+	svc.signer = nil
+	_, err = svc.Unsubscribe(context.Background(), unknown)
+	assertErrorCode(t, err, pkgErrors.ErrCodeUnsubscribeInvalid)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"ichi-go/internal/applications/notification/models"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/sms"
+)
+
+// ReceiptParser verifies and decodes provider delivery-receipt callbacks; satisfied by *sms.Client.
+type ReceiptParser interface {
+	Providers() []string
+	ParseReceipt(provider string, r *http.Request, body []byte) ([]sms.Receipt, error)
+}
+
+// ReceiptRepository applies delivery receipts to notification logs.
+// The concrete *repositories.NotificationLogRepository satisfies this interface.
+type ReceiptRepository interface {
+	ApplyReceipt(ctx context.Context, provider, messageID string, status models.LogStatus, errMsg string, at time.Time) (int64, error)
+}
+
+// SMSReceiptService records SMS delivery receipts pushed by providers on the
+// notification log entries of the messages they refer to.
+type SMSReceiptService struct {
+	parser ReceiptParser // nil when SMS is disabled
+	repo   ReceiptRepository
+}
+
+func NewSMSReceiptService(parser ReceiptParser, repo ReceiptRepository) *SMSReceiptService {
+	return &SMSReceiptService{parser: parser, repo: repo}
+}
+
+// HandleReceipt verifies a callback of provider and applies its final receipts
+// (delivered or failed) to the matching logs. Returns the number of logs updated;
+// receipts of unknown messages and intermediate states are ignored.
+func (s *SMSReceiptService) HandleReceipt(ctx context.Context, provider string, r *http.Request, body []byte) (int, error) {
+	if s.parser == nil {
+		return 0, pkgErrors.NotificationService(pkgErrors.ErrCodeSMSUnavailable).
+			Hint("SMS notifications are not configured").
+			Errorf("sms client is disabled")
+	}
+	if !s.hasProvider(provider) {
+		return 0, pkgErrors.NotificationService(pkgErrors.ErrCodeNotFound).
+			With("provider", provider).
+			Errorf("sms provider not configured")
+	}
+
+	receipts, err := s.parser.ParseReceipt(provider, r, body)
+	if errors.Is(err, sms.ErrInvalidSignature) {
+		return 0, pkgErrors.NotificationService(pkgErrors.ErrCodeUnauthorized).
+			With("provider", provider).
+			Wrap(err)
+	}
+	if err != nil {
+		return 0, pkgErrors.NotificationService(pkgErrors.ErrCodeValidation).
+			With("provider", provider).
+			Wrap(err)
+	}
+
+	applied := 0
+	now := time.Now()
+	for _, receipt := range receipts {
+		var status models.LogStatus
+		switch receipt.Status {
+		case sms.ReceiptDelivered:
+			status = models.LogStatusDelivered
+		case sms.ReceiptFailed:
+			status = models.LogStatusFailed
+		default:
+			continue // queued, sent, ... — not final
+		}
+
+		n, err := s.repo.ApplyReceipt(ctx, provider, receipt.MessageID, status, receipt.Error, now)
+		if err != nil {
+			return applied, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+				With("operation", "apply_sms_receipt").
+				With("provider", provider).
+				With("message_id", receipt.MessageID).
+				Wrap(err)
+		}
+		if n == 0 {
+			logger.Debugf("[sms] receipt for unknown or delivered message provider=%s message_id=%s",
+				provider, receipt.MessageID)
+		}
+		applied += int(n)
+	}
+	return applied, nil
+}
+
+func (s *SMSReceiptService) hasProvider(provider string) bool {
+	for _, name := range s.parser.Providers() {
+		if name == provider {
+			return true
+		}
+	}
+	return false
+}
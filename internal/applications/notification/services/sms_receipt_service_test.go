@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/models"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/notification/sms"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockReceiptParser struct {
+	mock.Mock
+}
+
+func (m *mockReceiptParser) Providers() []string { return []string{"twilio"} }
+
+func (m *mockReceiptParser) ParseReceipt(provider string, r *http.Request, body []byte) ([]sms.Receipt, error) {
+	args := m.Called(provider, r, body)
+	receipts, _ := args.Get(0).([]sms.Receipt)
+	return receipts, args.Error(1)
+}
+
+type mockReceiptRepo struct {
+	mock.Mock
+}
+
+func (m *mockReceiptRepo) ApplyReceipt(ctx context.Context, provider, messageID string, status models.LogStatus, errMsg string, at time.Time) (int64, error) {
+	args := m.Called(ctx, provider, messageID, status, errMsg, at)
+	return int64(args.Int(0)), args.Error(1)
+}
+
+func receiptRequest() *http.Request {
+	return httptest.NewRequest(http.MethodPost, "/receipts/twilio", nil)
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestHandleReceipt_AppliesFinalStatuses(t *testing.T) {
+	parser := new(mockReceiptParser)
+	repo := new(mockReceiptRepo)
+	svc := NewSMSReceiptService(parser, repo)
+
+	parser.On("ParseReceipt", "twilio", mock.Anything, mock.Anything).Return([]sms.Receipt{
+		{MessageID: "SM1", Status: sms.ReceiptDelivered},
+		{MessageID: "SM2", Status: sms.ReceiptFailed, Error: "twilio error 30003"},
+		{MessageID: "SM3", Status: sms.ReceiptPending},
+	}, nil)
+	repo.On("ApplyReceipt", mock.Anything, "twilio", "SM1", models.LogStatusDelivered, "", mock.Anything).Return(1, nil)
+	repo.On("ApplyReceipt", mock.Anything, "twilio", "SM2", models.LogStatusFailed, "twilio error 30003", mock.Anything).Return(1, nil)
+
+	applied, err := svc.HandleReceipt(context.Background(), "twilio", receiptRequest(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	repo.AssertNumberOfCalls(t, "ApplyReceipt", 2)
+}
+
+func TestHandleReceipt_InvalidSignatureIsUnauthorized(t *testing.T) {
+	parser := new(mockReceiptParser)
+	svc := NewSMSReceiptService(parser, new(mockReceiptRepo))
+	parser.On("ParseReceipt", "twilio", mock.Anything, mock.Anything).Return(nil, sms.ErrInvalidSignature)
+
+	_, err := svc.HandleReceipt(context.Background(), "twilio", receiptRequest(), nil)
+	assertErrorCode(t, err, pkgErrors.ErrCodeUnauthorized)
+}
+
+func TestHandleReceipt_UnknownProviderIsNotFound(t *testing.T) {
+	svc := NewSMSReceiptService(new(mockReceiptParser), new(mockReceiptRepo))
+
+	_, err := svc.HandleReceipt(context.Background(), "nexmo", receiptRequest(), nil)
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+}
+
+func TestHandleReceipt_UnavailableWithoutSMS(t *testing.T) {
+	svc := NewSMSReceiptService(nil, new(mockReceiptRepo))
+
+	_, err := svc.HandleReceipt(context.Background(), "twilio", receiptRequest(), nil)
+	assertErrorCode(t, err, pkgErrors.ErrCodeSMSUnavailable)
+}
+
+func TestHandleReceipt_DatabaseError(t *testing.T) {
+	parser := new(mockReceiptParser)
+	repo := new(mockReceiptRepo)
+	svc := NewSMSReceiptService(parser, repo)
+
+	parser.On("ParseReceipt", "twilio", mock.Anything, mock.Anything).
+		Return([]sms.Receipt{{MessageID: "SM1", Status: sms.ReceiptDelivered}}, nil)
+	repo.On("ApplyReceipt", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(0, errors.New("db down"))
+
+	_, err := svc.HandleReceipt(context.Background(), "twilio", receiptRequest(), nil)
+	assertErrorCode(t, err, pkgErrors.ErrCodeDatabase)
+}
@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	rbacConstants "ichi-go/internal/applications/rbac/constants"
+	"ichi-go/pkg/authenticator"
+	pkgErrors "ichi-go/pkg/errors"
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+// TemplateOverrideRepository is the minimal interface TemplateOverrideService uses for overrides.
+// The concrete *repositories.NotificationTemplateOverrideRepository satisfies this interface.
+type TemplateOverrideRepository interface {
+	FindByID(ctx context.Context, id int64) (*models.NotificationTemplateOverride, error)
+	FindByKey(ctx context.Context, eventSlug, channel, locale string) (*models.NotificationTemplateOverride, error)
+	FindOverride(ctx context.Context, eventSlug, channel string, locales []string) (*models.NotificationTemplateOverride, error)
+	List(ctx context.Context, eventSlug, channel, locale string) ([]*models.NotificationTemplateOverride, error)
+	CreateOverride(ctx context.Context, override *models.NotificationTemplateOverride, version *models.NotificationTemplateOverrideVersion) error
+	SaveRevision(ctx context.Context, override *models.NotificationTemplateOverride, version *models.NotificationTemplateOverrideVersion) (bool, error)
+	SoftDelete(ctx context.Context, id int64) error
+	ListVersions(ctx context.Context, overrideID int64) ([]*models.NotificationTemplateOverrideVersion, error)
+	FindVersion(ctx context.Context, overrideID int64, revision int) (*models.NotificationTemplateOverrideVersion, error)
+}
+
+// TemplateOverrideOptions holds the optional dependencies and settings of TemplateOverrideService.
+type TemplateOverrideOptions struct {
+	Permissions   PermissionChecker // nil when RBAC is unavailable; all changes are then denied
+	DefaultTenant string            // platform tenant templates:manage is checked in
+}
+
+// TemplateOverrideService manages the copy overrides of notification templates
+// (see TemplateRenderer) and renders previews of them.
+//
+// Overrides are validated with the rules TemplateRenderer applies at send time:
+// they must parse and execute against the sample data of the event. Every change
+// is recorded as a new revision that can be rolled back to. Overrides apply to
+// every tenant, so managing them requires the templates:manage permission in the
+// platform (default) tenant, not in the tenant of the request.
+type TemplateOverrideService struct {
+	repo     TemplateOverrideRepository
+	registry *notiftemplate.Registry
+	opts     TemplateOverrideOptions
+}
+
+func NewTemplateOverrideService(
+	repo TemplateOverrideRepository,
+	registry *notiftemplate.Registry,
+	opts TemplateOverrideOptions,
+) *TemplateOverrideService {
+	return &TemplateOverrideService{repo: repo, registry: registry, opts: opts}
+}
+
+// ListEvents returns the events of the template registry, sorted by slug.
+func (s *TemplateOverrideService) ListEvents() []dto.EventTemplateInfo {
+	slugs := s.registry.Slugs()
+	sort.Strings(slugs)
+
+	events := make([]dto.EventTemplateInfo, 0, len(slugs))
+	for _, slug := range slugs {
+		tmpl, ok := s.registry.Get(slug)
+		if !ok {
+			continue
+		}
+		class := tmpl.Classification()
+		events = append(events, dto.EventTemplateInfo{
+			Slug:       slug,
+			Channels:   tmpl.SupportedChannels(),
+			Category:   class.Category,
+			Kind:       string(class.Kind),
+			Urgent:     class.Urgent,
+			SampleData: notiftemplate.SampleData(tmpl),
+		})
+	}
+	return events
+}
+
+// ListOverrides returns the overrides matching query.
+func (s *TemplateOverrideService) ListOverrides(ctx context.Context, authCtx authenticator.AuthContext, query dto.ListTemplateOverridesQuery) ([]*dto.TemplateOverrideResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	overrides, err := s.repo.List(ctx, query.EventSlug, query.Channel, query.Locale)
+	if err != nil {
+		return nil, templateDatabaseError("list_template_overrides", 0, err)
+	}
+
+	resp := make([]*dto.TemplateOverrideResponse, 0, len(overrides))
+	for _, o := range overrides {
+		resp = append(resp, dto.NewTemplateOverrideResponse(o))
+	}
+	return resp, nil
+}
+
+// GetOverride returns one override.
+func (s *TemplateOverrideService) GetOverride(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*dto.TemplateOverrideResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	override, err := s.findOverride(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewTemplateOverrideResponse(override), nil
+}
+
+// CreateOverride adds the override of an (event, channel, locale), as revision 1.
+// The locale is stored in its canonical form ("pt-br" as "pt-BR"), the form the
+// fallback chains of TemplateRenderer look it up in.
+func (s *TemplateOverrideService) CreateOverride(ctx context.Context, authCtx authenticator.AuthContext, req dto.CreateTemplateOverrideRequest) (*dto.TemplateOverrideResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	tmpl, err := s.eventTemplate(req.EventSlug, string(req.Channel))
+	if err != nil {
+		return nil, err
+	}
+	locale, err := notiftemplate.ParseLocale(req.Locale)
+	if err != nil {
+		return nil, pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+			With("locale", req.Locale).
+			Hint("Use a BCP-47 language tag, e.g. en or pt-BR").
+			Errorf("invalid locale %q", req.Locale)
+	}
+	if err := validateOverrideTemplates(tmpl, req.TitleTemplate, req.BodyTemplate, locale); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByKey(ctx, req.EventSlug, string(req.Channel), locale)
+	if err != nil {
+		return nil, templateDatabaseError("find_template_override", 0, err)
+	}
+	if existing != nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeTemplateExists).
+			With("override_id", existing.ID).
+			Hint("An override of this event, channel and locale exists; update it instead").
+			Errorf("template override already exists")
+	}
+
+	override := &models.NotificationTemplateOverride{
+		EventSlug:     req.EventSlug,
+		Channel:       string(req.Channel),
+		Locale:        locale,
+		TitleTemplate: req.TitleTemplate,
+		BodyTemplate:  req.BodyTemplate,
+		IsActive:      req.IsActive == nil || *req.IsActive,
+		Revision:      1,
+	}
+	if err := s.repo.CreateOverride(ctx, override, newOverrideVersion(authCtx, override, 1, models.TemplateOverrideCreated, 0)); err != nil {
+		return nil, templateDatabaseError("create_template_override", 0, err)
+	}
+	return dto.NewTemplateOverrideResponse(override), nil
+}
+
+// UpdateOverride changes the copy or state of an override, as a new revision.
+func (s *TemplateOverrideService) UpdateOverride(ctx context.Context, authCtx authenticator.AuthContext, id int64, req dto.UpdateTemplateOverrideRequest) (*dto.TemplateOverrideResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	override, err := s.findOverride(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Revision != 0 && req.Revision != override.Revision {
+		return nil, templateModifiedError(override)
+	}
+
+	if req.TitleTemplate != nil {
+		override.TitleTemplate = *req.TitleTemplate
+	}
+	if req.BodyTemplate != nil {
+		override.BodyTemplate = *req.BodyTemplate
+	}
+	if req.IsActive != nil {
+		override.IsActive = *req.IsActive
+	}
+	return s.saveRevision(ctx, authCtx, override, models.TemplateOverrideUpdated, 0)
+}
+
+// DeleteOverride removes an override; the Go default copy is used again.
+// Its version history is kept.
+func (s *TemplateOverrideService) DeleteOverride(ctx context.Context, authCtx authenticator.AuthContext, id int64) error {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return err
+	}
+	if _, err := s.findOverride(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.SoftDelete(ctx, id); err != nil {
+		return templateDatabaseError("delete_template_override", id, err)
+	}
+	return nil
+}
+
+// ListVersions returns the revisions of an override, newest first.
+func (s *TemplateOverrideService) ListVersions(ctx context.Context, authCtx authenticator.AuthContext, id int64) ([]*models.NotificationTemplateOverrideVersion, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	if _, err := s.findOverride(ctx, id); err != nil {
+		return nil, err
+	}
+	versions, err := s.repo.ListVersions(ctx, id)
+	if err != nil {
+		return nil, templateDatabaseError("list_template_override_versions", id, err)
+	}
+	return versions, nil
+}
+
+// Rollback restores the copy and state of an earlier revision, as a new revision.
+// The restored copy is validated again: the template it belongs to may have changed.
+func (s *TemplateOverrideService) Rollback(ctx context.Context, authCtx authenticator.AuthContext, id int64, revision int) (*dto.TemplateOverrideResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	override, err := s.findOverride(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.repo.FindVersion(ctx, id, revision)
+	if err != nil {
+		return nil, templateDatabaseError("find_template_override_version", id, err)
+	}
+	if version == nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeNotFound).
+			With("override_id", id).
+			With("revision", revision).
+			Hint("Template override revision not found").
+			Errorf("template override revision not found")
+	}
+
+	override.TitleTemplate = version.TitleTemplate
+	override.BodyTemplate = version.BodyTemplate
+	override.IsActive = version.IsActive
+	return s.saveRevision(ctx, authCtx, override, models.TemplateOverrideRolledBack, revision)
+}
+
+// Preview renders the default copy, the active override or a draft of an event
+// with its sample data, merged with the data of the request.
+func (s *TemplateOverrideService) Preview(ctx context.Context, authCtx authenticator.AuthContext, req dto.PreviewTemplateRequest) (*dto.PreviewTemplateResponse, error) {
+	if err := s.authorize(ctx, authCtx); err != nil {
+		return nil, err
+	}
+	tmpl, err := s.eventTemplate(req.EventSlug, string(req.Channel))
+	if err != nil {
+		return nil, err
+	}
+
+	channel, locale := string(req.Channel), req.Locale
+	if locale == "" {
+		locale = notiftemplate.FallbackLocale
+	}
+	source := req.Source
+	if source == "" {
+		source = dto.PreviewSourceOverride
+		if req.TitleTemplate != "" || req.BodyTemplate != "" {
+			source = dto.PreviewSourceDraft
+		}
+	}
+
+	resp := &dto.PreviewTemplateResponse{EventSlug: req.EventSlug, Channel: channel, Locale: locale, Source: source}
+	var title, body string
+	switch source {
+	case dto.PreviewSourceDraft:
+		resp.ContentLocale = locale
+		title, body = overrideContent(tmpl, &models.NotificationTemplateOverride{
+			TitleTemplate: req.TitleTemplate,
+			BodyTemplate:  req.BodyTemplate,
+		}, channel, locale)
+	case dto.PreviewSourceOverride:
+		override, contentLocale, err := resolveCopy(ctx, s.repo, tmpl, channel, locale)
+		if err != nil {
+			return nil, templateDatabaseError("find_template_override", 0, err)
+		}
+		if override != nil {
+			resp.OverrideID = override.ID
+		} else {
+			resp.Source = dto.PreviewSourceDefault
+		}
+		resp.ContentLocale = contentLocale
+		title, body = overrideContent(tmpl, override, channel, contentLocale)
+	default:
+		resp.ContentLocale = defaultCopyLocale(tmpl, channel, locale)
+		title, body = overrideContent(tmpl, nil, channel, resp.ContentLocale)
+	}
+
+	resp.Data = notiftemplate.SampleData(tmpl)
+	for k, v := range req.Data {
+		resp.Data[k] = v
+	}
+	if resp.Title, err = executeTemplate("title", title, resp.ContentLocale, resp.Data); err != nil {
+		return nil, invalidTemplateError(err)
+	}
+	if resp.Body, err = executeTemplate("body", body, resp.ContentLocale, resp.Data); err != nil {
+		return nil, invalidTemplateError(err)
+	}
+	return resp, nil
+}
+
+// saveRevision validates the copy of override and saves it as the revision after the one it was read at.
+func (s *TemplateOverrideService) saveRevision(
+	ctx context.Context,
+	authCtx authenticator.AuthContext,
+	override *models.NotificationTemplateOverride,
+	action models.TemplateOverrideAction,
+	sourceRevision int,
+) (*dto.TemplateOverrideResponse, error) {
+	tmpl, err := s.eventTemplate(override.EventSlug, override.Channel)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateOverrideTemplates(tmpl, override.TitleTemplate, override.BodyTemplate, override.Locale); err != nil {
+		return nil, err
+	}
+
+	version := newOverrideVersion(authCtx, override, override.Revision+1, action, sourceRevision)
+	saved, err := s.repo.SaveRevision(ctx, override, version)
+	if err != nil {
+		return nil, templateDatabaseError("save_template_override", override.ID, err)
+	}
+	if !saved {
+		return nil, templateModifiedError(override)
+	}
+	return dto.NewTemplateOverrideResponse(override), nil
+}
+
+func (s *TemplateOverrideService) authorize(ctx context.Context, authCtx authenticator.AuthContext) error {
+	return authorizePlatformPermission(ctx, authCtx, s.opts.Permissions, s.opts.DefaultTenant,
+		rbacConstants.TemplatesManage, "notification templates")
+}
+
+// eventTemplate returns the registered template of eventSlug once it supports channel.
+// Webhooks send the event data as is: their copy cannot be overridden.
+func (s *TemplateOverrideService) eventTemplate(eventSlug, channel string) (notiftemplate.EventTemplate, error) {
+	tmpl, ok := s.registry.Get(eventSlug)
+	if !ok {
+		return nil, pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+			With("event_slug", eventSlug).
+			Hint("Unknown event; see GET /api/notifications/events").
+			Errorf("event %q is not registered", eventSlug)
+	}
+	if channel == string(dto.ChannelWebhook) || !supports(tmpl, dto.Channel(channel)) {
+		return nil, pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+			With("event_slug", eventSlug).
+			With("channel", channel).
+			Hint("The event has no copy for this channel; see GET /api/notifications/events").
+			Errorf("event %q does not support channel %q", eventSlug, channel)
+	}
+	return tmpl, nil
+}
+
+func (s *TemplateOverrideService) findOverride(ctx context.Context, id int64) (*models.NotificationTemplateOverride, error) {
+	override, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, templateDatabaseError("find_template_override", id, err)
+	}
+	if override == nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeNotFound).
+			With("override_id", id).
+			Hint("Template override not found").
+			Errorf("template override not found")
+	}
+	return override, nil
+}
+
+// validateOverrideTemplates executes title and body against the sample data of tmpl,
+// with the helper functions of locale, as TemplateRenderer will at send time, so
+// that broken copy is rejected up front.
+func validateOverrideTemplates(tmpl notiftemplate.EventTemplate, title, body, locale string) error {
+	if title == "" && body == "" {
+		return pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+			Hint("Set a title or body template; delete the override to use the default copy").
+			Errorf("empty template override")
+	}
+	data := notiftemplate.SampleData(tmpl)
+	if _, err := executeTemplate("title", title, locale, data); err != nil {
+		return invalidTemplateError(err)
+	}
+	if _, err := executeTemplate("body", body, locale, data); err != nil {
+		return invalidTemplateError(err)
+	}
+	return nil
+}
+
+// defaultCopyLocale returns the first locale of the fallback chain of locale in
+// which tmpl has default copy for channel.
+func defaultCopyLocale(tmpl notiftemplate.EventTemplate, channel, locale string) string {
+	for _, l := range notiftemplate.LocaleChain(locale) {
+		if notiftemplate.HasContent(tmpl, channel, l) {
+			return l
+		}
+	}
+	return notiftemplate.FallbackLocale
+}
+
+func newOverrideVersion(
+	authCtx authenticator.AuthContext,
+	override *models.NotificationTemplateOverride,
+	revision int,
+	action models.TemplateOverrideAction,
+	sourceRevision int,
+) *models.NotificationTemplateOverrideVersion {
+	return &models.NotificationTemplateOverrideVersion{
+		CreatedBy:      int64(authCtx.UserID.ID),
+		OverrideID:     override.ID,
+		Revision:       revision,
+		Action:         action,
+		SourceRevision: sourceRevision,
+		TitleTemplate:  override.TitleTemplate,
+		BodyTemplate:   override.BodyTemplate,
+		IsActive:       override.IsActive,
+	}
+}
+
+func invalidTemplateError(err error) error {
+	return pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+		Hint("Fix the Go text/template syntax, e.g. Hello {{.name}}").
+		Wrap(err)
+}
+
+func templateModifiedError(override *models.NotificationTemplateOverride) error {
+	return pkgErrors.NotificationService(pkgErrors.ErrCodeTemplateModified).
+		With("override_id", override.ID).
+		With("revision", override.Revision).
+		Hint("The override was changed by someone else; reload it and retry").
+		Errorf("template override was modified")
+}
+
+func templateDatabaseError(operation string, overrideID int64, err error) error {
+	return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+		With("operation", operation).
+		With("override_id", overrideID).
+		Wrap(err)
+}
@@ -0,0 +1,464 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/pkg/authenticator"
+	pkgErrors "ichi-go/pkg/errors"
+	notiftemplate "ichi-go/pkg/notification/template"
+	"ichi-go/pkg/requestctx"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockTemplateOverrideRepo struct {
+	mock.Mock
+}
+
+func (m *mockTemplateOverrideRepo) FindByID(ctx context.Context, id int64) (*models.NotificationTemplateOverride, error) {
+	args := m.Called(ctx, id)
+	if o := args.Get(0); o != nil {
+		return o.(*models.NotificationTemplateOverride), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTemplateOverrideRepo) FindByKey(ctx context.Context, eventSlug, channel, locale string) (*models.NotificationTemplateOverride, error) {
+	args := m.Called(ctx, eventSlug, channel, locale)
+	if o := args.Get(0); o != nil {
+		return o.(*models.NotificationTemplateOverride), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTemplateOverrideRepo) FindOverride(ctx context.Context, eventSlug, channel string, locales []string) (*models.NotificationTemplateOverride, error) {
+	args := m.Called(ctx, eventSlug, channel, locales)
+	if o := args.Get(0); o != nil {
+		return o.(*models.NotificationTemplateOverride), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTemplateOverrideRepo) List(ctx context.Context, eventSlug, channel, locale string) ([]*models.NotificationTemplateOverride, error) {
+	args := m.Called(ctx, eventSlug, channel, locale)
+	return args.Get(0).([]*models.NotificationTemplateOverride), args.Error(1)
+}
+
+func (m *mockTemplateOverrideRepo) CreateOverride(ctx context.Context, override *models.NotificationTemplateOverride, version *models.NotificationTemplateOverrideVersion) error {
+	override.ID = 9
+	version.OverrideID = 9
+	return m.Called(ctx, override, version).Error(0)
+}
+
+func (m *mockTemplateOverrideRepo) SaveRevision(ctx context.Context, override *models.NotificationTemplateOverride, version *models.NotificationTemplateOverrideVersion) (bool, error) {
+	args := m.Called(ctx, override, version)
+	if args.Bool(0) {
+		override.Revision = version.Revision
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockTemplateOverrideRepo) SoftDelete(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockTemplateOverrideRepo) ListVersions(ctx context.Context, overrideID int64) ([]*models.NotificationTemplateOverrideVersion, error) {
+	args := m.Called(ctx, overrideID)
+	return args.Get(0).([]*models.NotificationTemplateOverrideVersion), args.Error(1)
+}
+
+func (m *mockTemplateOverrideRepo) FindVersion(ctx context.Context, overrideID int64, revision int) (*models.NotificationTemplateOverrideVersion, error) {
+	args := m.Called(ctx, overrideID, revision)
+	if v := args.Get(0); v != nil {
+		return v.(*models.NotificationTemplateOverrideVersion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// sampleEventTemplate is a mockEventTemplate with sample data.
+type sampleEventTemplate struct {
+	mockEventTemplate
+	sample map[string]any
+}
+
+func (m *sampleEventTemplate) SampleData() map[string]any { return m.sample }
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+func setupTemplateOverrideSvc(allowed bool) (*TemplateOverrideService, *mockTemplateOverrideRepo, *mockPermissionChecker) {
+	repo := new(mockTemplateOverrideRepo)
+	permissions := new(mockPermissionChecker)
+	permissions.On("CheckPermission", mock.Anything, int64(42), mock.Anything, "templates", "manage").Return(allowed, nil)
+
+	registry := notiftemplate.NewRegistry()
+	registry.Register(&sampleEventTemplate{
+		mockEventTemplate: mockEventTemplate{slug: "order.shipped", channels: []string{"email", "push", "webhook"}},
+		sample:            map[string]any{"name": "Jane", "order_id": "A-1"},
+	})
+	svc := NewTemplateOverrideService(repo, registry, TemplateOverrideOptions{
+		Permissions:   permissions,
+		DefaultTenant: "system",
+	})
+	return svc, repo, permissions
+}
+
+func templateAuth() authenticator.AuthContext {
+	return authenticator.AuthContext{UserID: authenticator.UserSubject{ID: 42}}
+}
+
+func pushOverride(revision int) *models.NotificationTemplateOverride {
+	override := &models.NotificationTemplateOverride{
+		EventSlug:     "order.shipped",
+		Channel:       "push",
+		Locale:        "en",
+		TitleTemplate: "Hi {{.name}}",
+		IsActive:      true,
+		Revision:      revision,
+	}
+	override.ID = 9
+	return override
+}
+
+// ============================================================================
+// Events / authorization
+// ============================================================================
+
+func TestTemplateOverrideService_ListEventsIncludesSampleData(t *testing.T) {
+	svc, _, _ := setupTemplateOverrideSvc(false)
+
+	events := svc.ListEvents()
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "order.shipped", events[0].Slug)
+	assert.Equal(t, []string{"email", "push", "webhook"}, events[0].Channels)
+	assert.Equal(t, "Jane", events[0].SampleData["name"])
+}
+
+func TestTemplateOverrideService_RequiresPermission(t *testing.T) {
+	svc, repo, permissions := setupTemplateOverrideSvc(false)
+
+	_, err := svc.ListOverrides(context.Background(), templateAuth(), dto.ListTemplateOverridesQuery{})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+	permissions.AssertCalled(t, "CheckPermission", mock.Anything, int64(42), "system", "templates", "manage")
+	repo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTemplateOverrideService_ChecksPermissionInPlatformTenant(t *testing.T) {
+	svc, repo, permissions := setupTemplateOverrideSvc(false)
+	ctx := requestctx.SetTenantID(context.Background(), "acme")
+
+	_, err := svc.ListOverrides(ctx, templateAuth(), dto.ListTemplateOverridesQuery{})
+
+	// Overrides apply to every tenant: a templates:manage grant in acme is not enough.
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+	permissions.AssertCalled(t, "CheckPermission", mock.Anything, int64(42), "system", "templates", "manage")
+	permissions.AssertNotCalled(t, "CheckPermission", mock.Anything, int64(42), "acme", "templates", "manage")
+	repo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ============================================================================
+// Create
+// ============================================================================
+
+func TestTemplateOverrideService_CreateRecordsFirstRevision(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindByKey", mock.Anything, "order.shipped", "push", "pt-BR").Return(nil, nil)
+	repo.On("CreateOverride", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := svc.CreateOverride(context.Background(), templateAuth(), dto.CreateTemplateOverrideRequest{
+		EventSlug:     "order.shipped",
+		Channel:       dto.ChannelPush,
+		Locale:        "pt-BR",
+		TitleTemplate: "Olá {{.name}}",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), resp.ID)
+	assert.Equal(t, 1, resp.Revision)
+	assert.True(t, resp.IsActive)
+	version := repo.Calls[1].Arguments.Get(2).(*models.NotificationTemplateOverrideVersion)
+	assert.Equal(t, 1, version.Revision)
+	assert.Equal(t, models.TemplateOverrideCreated, version.Action)
+	assert.Equal(t, int64(42), version.CreatedBy)
+}
+
+func TestTemplateOverrideService_CreateStoresCanonicalLocale(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindByKey", mock.Anything, "order.shipped", "push", "id-ID").Return(nil, nil)
+	repo.On("CreateOverride", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := svc.CreateOverride(context.Background(), templateAuth(), dto.CreateTemplateOverrideRequest{
+		EventSlug:     "order.shipped",
+		Channel:       dto.ChannelPush,
+		Locale:        "id_id",
+		TitleTemplate: "Hai {{.name}}",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "id-ID", resp.Locale)
+}
+
+func TestTemplateOverrideService_CreateRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		req  dto.CreateTemplateOverrideRequest
+	}{
+		{"unknown event", dto.CreateTemplateOverrideRequest{EventSlug: "order.lost", Channel: dto.ChannelPush, Locale: "en", TitleTemplate: "Hi"}},
+		{"unsupported channel", dto.CreateTemplateOverrideRequest{EventSlug: "order.shipped", Channel: dto.ChannelSMS, Locale: "en", TitleTemplate: "Hi"}},
+		{"webhook channel", dto.CreateTemplateOverrideRequest{EventSlug: "order.shipped", Channel: dto.ChannelWebhook, Locale: "en", TitleTemplate: "Hi"}},
+		{"invalid locale", dto.CreateTemplateOverrideRequest{EventSlug: "order.shipped", Channel: dto.ChannelPush, Locale: "English", TitleTemplate: "Hi"}},
+		{"empty templates", dto.CreateTemplateOverrideRequest{EventSlug: "order.shipped", Channel: dto.ChannelPush, Locale: "en"}},
+		{"syntax error", dto.CreateTemplateOverrideRequest{EventSlug: "order.shipped", Channel: dto.ChannelPush, Locale: "en", TitleTemplate: "Hi {{.name"}},
+		{"fails on sample data", dto.CreateTemplateOverrideRequest{EventSlug: "order.shipped", Channel: dto.ChannelPush, Locale: "en", BodyTemplate: "{{.name.first}}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, _ := setupTemplateOverrideSvc(true)
+
+			_, err := svc.CreateOverride(context.Background(), templateAuth(), tt.req)
+
+			assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+			repo.AssertNotCalled(t, "CreateOverride", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTemplateOverrideService_CreateConflictsWithExistingKey(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindByKey", mock.Anything, "order.shipped", "push", "en").Return(pushOverride(3), nil)
+
+	_, err := svc.CreateOverride(context.Background(), templateAuth(), dto.CreateTemplateOverrideRequest{
+		EventSlug: "order.shipped", Channel: dto.ChannelPush, Locale: "en", TitleTemplate: "Hi",
+	})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeTemplateExists)
+	repo.AssertNotCalled(t, "CreateOverride", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ============================================================================
+// Update / rollback
+// ============================================================================
+
+func TestTemplateOverrideService_UpdateSavesNextRevision(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindByID", mock.Anything, int64(9)).Return(pushOverride(3), nil)
+	repo.On("SaveRevision", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+	body := "Order {{.order_id}} is on its way"
+	resp, err := svc.UpdateOverride(context.Background(), templateAuth(), 9, dto.UpdateTemplateOverrideRequest{
+		BodyTemplate: &body,
+		Revision:     3,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, resp.Revision)
+	assert.Equal(t, "Hi {{.name}}", resp.TitleTemplate)
+	assert.Equal(t, body, resp.BodyTemplate)
+	version := repo.Calls[1].Arguments.Get(2).(*models.NotificationTemplateOverrideVersion)
+	assert.Equal(t, models.TemplateOverrideUpdated, version.Action)
+	assert.Equal(t, body, version.BodyTemplate)
+}
+
+func TestTemplateOverrideService_UpdateRejectsStaleRevision(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindByID", mock.Anything, int64(9)).Return(pushOverride(4), nil)
+
+	title := "Hello {{.name}}"
+	_, err := svc.UpdateOverride(context.Background(), templateAuth(), 9, dto.UpdateTemplateOverrideRequest{
+		TitleTemplate: &title,
+		Revision:      3,
+	})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeTemplateModified)
+	repo.AssertNotCalled(t, "SaveRevision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTemplateOverrideService_UpdateLosesConcurrentRace(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindByID", mock.Anything, int64(9)).Return(pushOverride(3), nil)
+	repo.On("SaveRevision", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+	inactive := false
+	_, err := svc.UpdateOverride(context.Background(), templateAuth(), 9, dto.UpdateTemplateOverrideRequest{IsActive: &inactive})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeTemplateModified)
+}
+
+func TestTemplateOverrideService_RollbackRestoresRevisionAsNewRevision(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindByID", mock.Anything, int64(9)).Return(pushOverride(5), nil)
+	repo.On("FindVersion", mock.Anything, int64(9), 2).Return(&models.NotificationTemplateOverrideVersion{
+		OverrideID:    9,
+		Revision:      2,
+		TitleTemplate: "Shipped, {{.name}}!",
+		BodyTemplate:  "Order {{.order_id}}",
+		IsActive:      false,
+	}, nil)
+	repo.On("SaveRevision", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+	resp, err := svc.Rollback(context.Background(), templateAuth(), 9, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, resp.Revision)
+	assert.Equal(t, "Shipped, {{.name}}!", resp.TitleTemplate)
+	assert.Equal(t, "Order {{.order_id}}", resp.BodyTemplate)
+	assert.False(t, resp.IsActive)
+	version := repo.Calls[2].Arguments.Get(2).(*models.NotificationTemplateOverrideVersion)
+	assert.Equal(t, 6, version.Revision)
+	assert.Equal(t, models.TemplateOverrideRolledBack, version.Action)
+	assert.Equal(t, 2, version.SourceRevision)
+}
+
+func TestTemplateOverrideService_RollbackUnknownRevision(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindByID", mock.Anything, int64(9)).Return(pushOverride(5), nil)
+	repo.On("FindVersion", mock.Anything, int64(9), 7).Return(nil, nil)
+
+	_, err := svc.Rollback(context.Background(), templateAuth(), 9, 7)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+	repo.AssertNotCalled(t, "SaveRevision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ============================================================================
+// Preview
+// ============================================================================
+
+func TestTemplateOverrideService_PreviewDraftMergesSampleData(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+
+	resp, err := svc.Preview(context.Background(), templateAuth(), dto.PreviewTemplateRequest{
+		EventSlug:     "order.shipped",
+		Channel:       dto.ChannelPush,
+		TitleTemplate: "Hi {{.name}}",
+		BodyTemplate:  "Order {{.order_id}}",
+		Data:          map[string]any{"order_id": "B-2"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, dto.PreviewSourceDraft, resp.Source)
+	assert.Equal(t, "en", resp.Locale)
+	assert.Equal(t, "Hi Jane", resp.Title)
+	assert.Equal(t, "Order B-2", resp.Body)
+	repo.AssertNotCalled(t, "FindOverride", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTemplateOverrideService_PreviewOverrideFallsBackToDefaultBody(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindOverride", mock.Anything, "order.shipped", "push", []string{"en"}).Return(pushOverride(1), nil)
+
+	resp, err := svc.Preview(context.Background(), templateAuth(), dto.PreviewTemplateRequest{
+		EventSlug: "order.shipped",
+		Channel:   dto.ChannelPush,
+		Locale:    "en",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, dto.PreviewSourceOverride, resp.Source)
+	assert.Equal(t, int64(9), resp.OverrideID)
+	assert.Equal(t, "Hi Jane", resp.Title)
+	assert.Equal(t, "Test Body", resp.Body)
+}
+
+func TestTemplateOverrideService_PreviewWithoutOverrideRendersDefault(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	repo.On("FindOverride", mock.Anything, "order.shipped", "email", []string{"en"}).Return(nil, nil)
+
+	resp, err := svc.Preview(context.Background(), templateAuth(), dto.PreviewTemplateRequest{
+		EventSlug: "order.shipped",
+		Channel:   dto.ChannelEmail,
+		Locale:    "en",
+		Source:    dto.PreviewSourceOverride,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, dto.PreviewSourceDefault, resp.Source)
+	assert.Zero(t, resp.OverrideID)
+	assert.Equal(t, "Test Title", resp.Title)
+}
+
+// translatedEventTemplate has default copy in the locales of its own only.
+type translatedEventTemplate struct {
+	mockEventTemplate
+	locales []string
+}
+
+func (m *translatedEventTemplate) HasContent(channel, locale string) bool {
+	for _, l := range m.locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTemplateOverrideService_PreviewWalksLocaleFallbackChain(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	svc.registry = notiftemplate.NewRegistry()
+	svc.registry.Register(&translatedEventTemplate{
+		mockEventTemplate: mockEventTemplate{slug: "order.shipped", channels: []string{"push"}},
+		locales:           []string{"en"},
+	})
+	override := pushOverride(1)
+	override.Locale = "id"
+	override.TitleTemplate = "Total {{currency .total \"IDR\"}}"
+	repo.On("FindOverride", mock.Anything, "order.shipped", "push", []string{"id-ID", "id", "en"}).Return(override, nil)
+
+	resp, err := svc.Preview(context.Background(), templateAuth(), dto.PreviewTemplateRequest{
+		EventSlug: "order.shipped",
+		Channel:   dto.ChannelPush,
+		Locale:    "id-ID",
+		Data:      map[string]any{"total": 150000},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, dto.PreviewSourceOverride, resp.Source)
+	assert.Equal(t, "id-ID", resp.Locale)
+	assert.Equal(t, "id", resp.ContentLocale)
+	assert.Equal(t, "Total Rp 150.000", resp.Title, "helpers format in the locale of the copy")
+}
+
+func TestTemplateOverrideService_PreviewStopsChainAtDefaultCopy(t *testing.T) {
+	svc, repo, _ := setupTemplateOverrideSvc(true)
+	svc.registry = notiftemplate.NewRegistry()
+	svc.registry.Register(&translatedEventTemplate{
+		mockEventTemplate: mockEventTemplate{slug: "order.shipped", channels: []string{"push"}},
+		locales:           []string{"id", "en"},
+	})
+	// The "id" default copy wins over an "en" override: "en" is not looked up.
+	repo.On("FindOverride", mock.Anything, "order.shipped", "push", []string{"id-ID", "id"}).Return(nil, nil)
+
+	resp, err := svc.Preview(context.Background(), templateAuth(), dto.PreviewTemplateRequest{
+		EventSlug: "order.shipped",
+		Channel:   dto.ChannelPush,
+		Locale:    "id-ID",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, dto.PreviewSourceDefault, resp.Source)
+	assert.Equal(t, "id", resp.ContentLocale)
+}
+
+func TestTemplateOverrideService_PreviewReportsInvalidDraft(t *testing.T) {
+	svc, _, _ := setupTemplateOverrideSvc(true)
+
+	_, err := svc.Preview(context.Background(), templateAuth(), dto.PreviewTemplateRequest{
+		EventSlug:    "order.shipped",
+		Channel:      dto.ChannelPush,
+		BodyTemplate: "{{if .name}}unterminated",
+	})
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+}
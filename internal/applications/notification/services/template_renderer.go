@@ -8,6 +8,7 @@ import (
 
 	notiftemplate "ichi-go/pkg/notification/template"
 
+	"ichi-go/internal/applications/notification/models"
 	"ichi-go/internal/applications/notification/repositories"
 )
 
@@ -20,10 +21,12 @@ type RenderedContent struct {
 // TemplateRenderer resolves and renders notification templates using the hybrid approach:
 //
 //  1. Load Go EventTemplate from the registry (always exists if event passed API validation)
-//  2. Check the DB for an active override row for (event_slug, channel, locale)
-//     - Found → use DB title_template / body_template strings
+//  2. Walk the locale's fallback chain (notiftemplate.LocaleChain: "id-ID" → "id" → "en")
+//     to the first locale with an active DB override or Go default copy for the channel
+//     - Override found → use DB title_template / body_template strings
 //     - Not found → use Go struct's DefaultContent() strings
-//  3. Execute Go text/template with event data → RenderedContent{Title, Body}
+//  3. Execute Go text/template with event data and the helper functions of the
+//     locale of the copy (notiftemplate.FuncMap) → RenderedContent{Title, Body}
 //
 // Missing DB overrides are NOT errors. Template execution failures are errors (bad syntax
 // in a DB override string) and cause the channel to be skipped without requeue.
@@ -45,32 +48,29 @@ func NewTemplateRenderer(
 // Render resolves the best template for (eventSlug, channel, locale) and executes it
 // with the provided data map. Returns RenderedContent with the final title and body strings.
 //
-// Locale fallback is handled by the override repository (exact → "en").
+// Locale fallback follows notiftemplate.LocaleChain; an empty locale renders in "en".
 // Channel fallback is NOT performed — if "push" has no content, it returns an error.
 func (r *TemplateRenderer) Render(ctx context.Context, eventSlug, channel, locale string, data map[string]any) (*RenderedContent, error) {
-	if locale == "" {
-		locale = "en"
-	}
-
 	// Step 1: Load the Go template contract (always present after API validation).
 	goTmpl, err := r.registry.MustGet(eventSlug)
 	if err != nil {
 		return nil, err
 	}
 
-	// Step 2: Try to load a DB copy override.
-	titleTmplStr, bodyTmplStr, err := r.resolveTemplateStrings(ctx, goTmpl, channel, locale)
+	// Step 2: Try to load a DB copy override along the locale's fallback chain.
+	override, contentLocale, err := resolveCopy(ctx, r.overrideRepo, goTmpl, channel, locale)
 	if err != nil {
 		return nil, fmt.Errorf("template_renderer: resolve failed event=%s channel=%s: %w", eventSlug, channel, err)
 	}
+	titleTmplStr, bodyTmplStr := overrideContent(goTmpl, override, channel, contentLocale)
 
 	// Step 3: Execute text/template with event data.
-	title, err := executeTemplate("title", titleTmplStr, data)
+	title, err := executeTemplate("title", titleTmplStr, contentLocale, data)
 	if err != nil {
 		return nil, fmt.Errorf("template_renderer: title render failed event=%s channel=%s: %w", eventSlug, channel, err)
 	}
 
-	body, err := executeTemplate("body", bodyTmplStr, data)
+	body, err := executeTemplate("body", bodyTmplStr, contentLocale, data)
 	if err != nil {
 		return nil, fmt.Errorf("template_renderer: body render failed event=%s channel=%s: %w", eventSlug, channel, err)
 	}
@@ -78,50 +78,73 @@ func (r *TemplateRenderer) Render(ctx context.Context, eventSlug, channel, local
 	return &RenderedContent{Title: title, Body: body}, nil
 }
 
-// resolveTemplateStrings returns the title and body template strings to use,
-// preferring DB overrides over Go defaults.
-func (r *TemplateRenderer) resolveTemplateStrings(
+// overrideFinder looks up the active DB override of the first locale of a fallback
+// chain that has one. The concrete *repositories.NotificationTemplateOverrideRepository
+// satisfies this interface.
+type overrideFinder interface {
+	FindOverride(ctx context.Context, eventSlug, channel string, locales []string) (*models.NotificationTemplateOverride, error)
+}
+
+// resolveCopy walks the fallback chain of locale to the first locale with an active
+// DB override or Go default copy of goTmpl for channel, and returns that override
+// (nil for the Go default) and locale. An override is preferred to the default copy
+// of the same locale, and the default copy of a locale to the overrides of the
+// less specific locales after it.
+func resolveCopy(
 	ctx context.Context,
+	finder overrideFinder,
 	goTmpl notiftemplate.EventTemplate,
 	channel, locale string,
-) (titleStr, bodyStr string, err error) {
-	// Check DB override.
-	override, err := r.overrideRepo.FindOverride(ctx, goTmpl.Slug(), channel, locale)
-	if err != nil {
-		return "", "", err
+) (*models.NotificationTemplateOverride, string, error) {
+	chain := notiftemplate.LocaleChain(locale)
+	for i, l := range chain {
+		if notiftemplate.HasContent(goTmpl, channel, l) {
+			chain = chain[:i+1]
+			break
+		}
 	}
 
+	override, err := finder.FindOverride(ctx, goTmpl.Slug(), channel, chain)
+	if err != nil {
+		return nil, "", err
+	}
 	if override != nil && override.IsActive {
-		// DB override found — use its strings (may be partial, fall back to Go if empty).
-		titleStr = override.TitleTemplate
-		bodyStr = override.BodyTemplate
-
-		// If one field is empty in the DB override, fall back to the Go default for that field.
-		if titleStr == "" || bodyStr == "" {
-			goContent := goTmpl.DefaultContent(channel, locale)
-			if titleStr == "" {
-				titleStr = goContent.Title
-			}
-			if bodyStr == "" {
-				bodyStr = goContent.Body
-			}
-		}
-		return titleStr, bodyStr, nil
+		return override, override.Locale, nil
 	}
+	return nil, chain[len(chain)-1], nil
+}
 
-	// No DB override — use Go defaults.
+// overrideContent returns the title and body template strings of override, falling
+// back to the Go default for each empty field, or the Go defaults when override is nil.
+func overrideContent(goTmpl notiftemplate.EventTemplate, override *models.NotificationTemplateOverride, channel, locale string) (titleStr, bodyStr string) {
 	goContent := goTmpl.DefaultContent(channel, locale)
-	return goContent.Title, goContent.Body, nil
+	if override == nil {
+		return goContent.Title, goContent.Body
+	}
+
+	// The override may be partial: an empty field keeps the Go default.
+	titleStr, bodyStr = override.TitleTemplate, override.BodyTemplate
+	if titleStr == "" {
+		titleStr = goContent.Title
+	}
+	if bodyStr == "" {
+		bodyStr = goContent.Body
+	}
+	return titleStr, bodyStr
 }
 
-// executeTemplate parses and executes a single Go text/template string.
+// executeTemplate parses and executes a single Go text/template string with the
+// helper functions of locale (notiftemplate.FuncMap).
 // Uses missingkey=zero so missing variables render as empty string (not panic).
-func executeTemplate(name, tmplStr string, data map[string]any) (string, error) {
+func executeTemplate(name, tmplStr, locale string, data map[string]any) (string, error) {
 	if tmplStr == "" {
 		return "", nil
 	}
 
-	t, err := gotemplate.New(name).Option("missingkey=zero").Parse(tmplStr)
+	t, err := gotemplate.New(name).
+		Option("missingkey=zero").
+		Funcs(notiftemplate.FuncMap(locale)).
+		Parse(tmplStr)
 	if err != nil {
 		return "", fmt.Errorf("parse error in %s template: %w", name, err)
 	}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	rbacConstants "ichi-go/internal/applications/rbac/constants"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/authenticator"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/notification/webhook"
+)
+
+// eventFilterPattern accepts "*", an event slug ("order.shipped") or a slug prefix ("order.*").
+var eventFilterPattern = regexp.MustCompile(`^(\*|[a-z0-9_-]+(\.[a-z0-9_-]+)*(\.\*)?)$`)
+
+const (
+	defaultDeliveryListLimit = 50
+	maxDeliveryListLimit     = 100
+)
+
+// WebhookEndpointRepository is the minimal interface WebhookService uses for endpoints.
+// The concrete *repositories.WebhookEndpointRepository satisfies this interface.
+type WebhookEndpointRepository interface {
+	Create(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	FindByID(ctx context.Context, id int64) (*models.WebhookEndpoint, error)
+	ListByTenant(ctx context.Context, tenantID string) ([]*models.WebhookEndpoint, error)
+	Update(ctx context.Context, endpoint *models.WebhookEndpoint, columns ...string) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// WebhookDeliveryRepository is the minimal interface WebhookService uses for the delivery log.
+// The concrete *repositories.WebhookDeliveryRepository satisfies this interface.
+type WebhookDeliveryRepository interface {
+	FindByID(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+	ListByEndpoint(ctx context.Context, endpointID int64, limit int) ([]*models.WebhookDelivery, error)
+}
+
+// WebhookOptions holds the optional dependencies and settings of WebhookService.
+type WebhookOptions struct {
+	Permissions          PermissionChecker // nil when RBAC is unavailable; all requests are then denied
+	DefaultTenant        string            // tenant of requests without tenant context
+	AllowPrivateNetworks bool              // accept endpoint URLs on internal addresses
+}
+
+// WebhookService manages the webhook endpoints of a tenant and their delivery log.
+//
+// The tenant is the one of the request context (the API key's tenant, or the
+// X-Tenant-Id header), falling back to the default tenant. Every method requires
+// the webhooks:manage permission in that tenant; endpoints of other tenants are
+// reported as not found.
+type WebhookService struct {
+	endpoints  WebhookEndpointRepository
+	deliveries WebhookDeliveryRepository
+	producer   rabbitmq.MessageProducer // app.events exchange; nil when the queue is unavailable
+	opts       WebhookOptions
+}
+
+func NewWebhookService(
+	endpoints WebhookEndpointRepository,
+	deliveries WebhookDeliveryRepository,
+	producer rabbitmq.MessageProducer,
+	opts WebhookOptions,
+) *WebhookService {
+	return &WebhookService{endpoints: endpoints, deliveries: deliveries, producer: producer, opts: opts}
+}
+
+// CreateEndpoint registers an endpoint with a new signing secret, returned only this once.
+func (s *WebhookService) CreateEndpoint(ctx context.Context, authCtx authenticator.AuthContext, req dto.CreateWebhookEndpointRequest) (*dto.WebhookEndpointSecretResponse, error) {
+	tenantID, err := s.authorize(ctx, authCtx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateEndpoint(req.URL, req.Events); err != nil {
+		return nil, err
+	}
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeInternal).Wrap(err)
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		TenantID:    tenantID,
+		CreatedBy:   int64(authCtx.UserID.ID),
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      secret,
+		Events:      req.Events,
+		IsActive:    true,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.endpoints.Create(ctx, endpoint); err != nil {
+		return nil, webhookDatabaseError("create_webhook_endpoint", tenantID, err)
+	}
+	return &dto.WebhookEndpointSecretResponse{WebhookEndpoint: endpoint, Secret: secret}, nil
+}
+
+// ListEndpoints returns the endpoints of the caller's tenant.
+func (s *WebhookService) ListEndpoints(ctx context.Context, authCtx authenticator.AuthContext) ([]*models.WebhookEndpoint, error) {
+	tenantID, err := s.authorize(ctx, authCtx)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := s.endpoints.ListByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, webhookDatabaseError("list_webhook_endpoints", tenantID, err)
+	}
+	return endpoints, nil
+}
+
+// GetEndpoint returns one endpoint of the caller's tenant.
+func (s *WebhookService) GetEndpoint(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*models.WebhookEndpoint, error) {
+	tenantID, err := s.authorize(ctx, authCtx)
+	if err != nil {
+		return nil, err
+	}
+	return s.findEndpoint(ctx, tenantID, id)
+}
+
+// UpdateEndpoint changes the URL, description, event filters or state of an endpoint.
+// Re-activating an endpoint clears its failure count and disabled reason.
+func (s *WebhookService) UpdateEndpoint(ctx context.Context, authCtx authenticator.AuthContext, id int64, req dto.UpdateWebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	tenantID, err := s.authorize(ctx, authCtx)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := s.findEndpoint(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	url, events := endpoint.URL, endpoint.Events
+	if req.URL != nil {
+		url = *req.URL
+	}
+	if req.Events != nil {
+		events = req.Events
+	}
+	if err := s.validateEndpoint(url, events); err != nil {
+		return nil, err
+	}
+
+	columns := []string{"url", "events"}
+	endpoint.URL, endpoint.Events = url, events
+	if req.Description != nil {
+		endpoint.Description = *req.Description
+		columns = append(columns, "description")
+	}
+	if req.IsActive != nil && *req.IsActive != endpoint.IsActive {
+		endpoint.IsActive = *req.IsActive
+		if endpoint.IsActive {
+			endpoint.ConsecutiveFailures = 0
+			endpoint.DisabledAt = nil
+			endpoint.DisabledReason = ""
+		} else {
+			now := time.Now()
+			endpoint.DisabledAt = &now
+			endpoint.DisabledReason = "disabled by user"
+		}
+		columns = append(columns, "is_active", "consecutive_failures", "disabled_at", "disabled_reason")
+	}
+
+	if err := s.endpoints.Update(ctx, endpoint, columns...); err != nil {
+		return nil, webhookDatabaseError("update_webhook_endpoint", tenantID, err)
+	}
+	return endpoint, nil
+}
+
+// RotateSecret replaces the signing secret of an endpoint. Deliveries are signed
+// with the new secret from the next attempt on, including pending retries.
+func (s *WebhookService) RotateSecret(ctx context.Context, authCtx authenticator.AuthContext, id int64) (*dto.WebhookEndpointSecretResponse, error) {
+	tenantID, err := s.authorize(ctx, authCtx)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := s.findEndpoint(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeInternal).Wrap(err)
+	}
+
+	endpoint.Secret = secret
+	if err := s.endpoints.Update(ctx, endpoint, "secret"); err != nil {
+		return nil, webhookDatabaseError("rotate_webhook_secret", tenantID, err)
+	}
+	return &dto.WebhookEndpointSecretResponse{WebhookEndpoint: endpoint, Secret: secret}, nil
+}
+
+// DeleteEndpoint removes an endpoint and its delivery log. Queued retries are dropped.
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, authCtx authenticator.AuthContext, id int64) error {
+	tenantID, err := s.authorize(ctx, authCtx)
+	if err != nil {
+		return err
+	}
+	if _, err := s.findEndpoint(ctx, tenantID, id); err != nil {
+		return err
+	}
+	if err := s.endpoints.Delete(ctx, id); err != nil {
+		return webhookDatabaseError("delete_webhook_endpoint", tenantID, err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the latest delivery attempts of an endpoint, newest first.
+// limit defaults to 50 and is capped at 100.
+func (s *WebhookService) ListDeliveries(ctx context.Context, authCtx authenticator.AuthContext, endpointID int64, limit int) ([]*models.WebhookDelivery, error) {
+	tenantID, err := s.authorize(ctx, authCtx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findEndpoint(ctx, tenantID, endpointID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveryListLimit
+	}
+	if limit > maxDeliveryListLimit {
+		limit = maxDeliveryListLimit
+	}
+	deliveries, err := s.deliveries.ListByEndpoint(ctx, endpointID, limit)
+	if err != nil {
+		return nil, webhookDatabaseError("list_webhook_deliveries", tenantID, err)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns one delivery attempt of an endpoint, with its request and response snapshots.
+func (s *WebhookService) GetDelivery(ctx context.Context, authCtx authenticator.AuthContext, endpointID, deliveryID int64) (*models.WebhookDelivery, error) {
+	tenantID, err := s.authorize(ctx, authCtx)
+	if err != nil {
+		return nil, err
+	}
+	return s.findDelivery(ctx, tenantID, endpointID, deliveryID)
+}
+
+// Redeliver queues the payload of a past delivery to its endpoint again, as a new
+// delivery with a fresh retry budget. The endpoint must be active.
+func (s *WebhookService) Redeliver(ctx context.Context, authCtx authenticator.AuthContext, endpointID, deliveryID int64) error {
+	tenantID, err := s.authorize(ctx, authCtx)
+	if err != nil {
+		return err
+	}
+	if s.producer == nil {
+		return pkgErrors.NotificationService(pkgErrors.ErrCodeWebhookUnavailable).
+			Hint("Webhook deliveries are not available").
+			Errorf("queue unavailable")
+	}
+	endpoint, err := s.findEndpoint(ctx, tenantID, endpointID)
+	if err != nil {
+		return err
+	}
+	if !endpoint.IsActive {
+		return pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+			With("endpoint_id", endpointID).
+			Hint("The endpoint is disabled; enable it before redelivering").
+			Errorf("webhook endpoint disabled")
+	}
+	delivery, err := s.findDelivery(ctx, tenantID, endpointID, deliveryID)
+	if err != nil {
+		return err
+	}
+
+	job := dto.WebhookDeliveryJob{
+		EndpointID:   endpoint.ID,
+		EventID:      delivery.EventID,
+		EventType:    delivery.EventType,
+		Attempt:      1,
+		RedeliveryOf: delivery.ID,
+		Payload:      []byte(delivery.RequestBody),
+	}
+	if err := s.producer.Publish(ctx, dto.WebhookDeliveryRoutingKey, job, rabbitmq.PublishOptions{}); err != nil {
+		return pkgErrors.Queue(pkgErrors.ErrCodeQueue).
+			With("delivery_id", deliveryID).
+			Hint("Failed to queue the redelivery").
+			Wrap(err)
+	}
+	return nil
+}
+
+// authorize returns the caller's tenant once they may manage its webhooks.
+func (s *WebhookService) authorize(ctx context.Context, authCtx authenticator.AuthContext) (string, error) {
+	return authorizePermission(ctx, authCtx, s.opts.Permissions, s.opts.DefaultTenant,
+		rbacConstants.WebhooksManage, "the webhooks of this tenant")
+}
+
+func (s *WebhookService) validateEndpoint(url string, events []string) error {
+	if err := webhook.ValidateURL(url, s.opts.AllowPrivateNetworks); err != nil {
+		return pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+			With("url", url).
+			Hint("URL must be a public http(s) address without credentials").
+			Wrap(err)
+	}
+	for _, filter := range events {
+		if !eventFilterPattern.MatchString(filter) {
+			return pkgErrors.Validation(pkgErrors.ErrCodeValidation).
+				With("event", filter).
+				Hint(`Events must be event slugs ("order.shipped"), prefixes ("order.*") or "*"`).
+				Errorf("invalid event filter")
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) findEndpoint(ctx context.Context, tenantID string, id int64) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.endpoints.FindByID(ctx, id)
+	if err != nil {
+		return nil, webhookDatabaseError("get_webhook_endpoint", tenantID, err)
+	}
+	if endpoint == nil || endpoint.TenantID != tenantID {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeNotFound).
+			With("tenant_id", tenantID).
+			With("endpoint_id", id).
+			Hint("Webhook endpoint not found").
+			Errorf("webhook endpoint not found")
+	}
+	return endpoint, nil
+}
+
+func (s *WebhookService) findDelivery(ctx context.Context, tenantID string, endpointID, deliveryID int64) (*models.WebhookDelivery, error) {
+	if _, err := s.findEndpoint(ctx, tenantID, endpointID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.deliveries.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, webhookDatabaseError("get_webhook_delivery", tenantID, err)
+	}
+	if delivery == nil || delivery.EndpointID != endpointID {
+		return nil, pkgErrors.NotificationService(pkgErrors.ErrCodeNotFound).
+			With("endpoint_id", endpointID).
+			With("delivery_id", deliveryID).
+			Hint("Webhook delivery not found").
+			Errorf("webhook delivery not found")
+	}
+	return delivery, nil
+}
+
+func webhookDatabaseError(operation, tenantID string, err error) error {
+	return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+		With("operation", operation).
+		With("tenant_id", tenantID).
+		Wrap(err)
+}
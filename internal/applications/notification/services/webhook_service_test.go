@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/internal/applications/notification/dto"
+	"ichi-go/internal/applications/notification/models"
+	"ichi-go/pkg/authenticator"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/requestctx"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type mockWebhookEndpointRepo struct {
+	mock.Mock
+}
+
+func (m *mockWebhookEndpointRepo) Create(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	endpoint.ID = 1
+	return m.Called(ctx, endpoint).Error(0)
+}
+
+func (m *mockWebhookEndpointRepo) FindByID(ctx context.Context, id int64) (*models.WebhookEndpoint, error) {
+	args := m.Called(ctx, id)
+	endpoint, _ := args.Get(0).(*models.WebhookEndpoint)
+	return endpoint, args.Error(1)
+}
+
+func (m *mockWebhookEndpointRepo) ListByTenant(ctx context.Context, tenantID string) ([]*models.WebhookEndpoint, error) {
+	args := m.Called(ctx, tenantID)
+	endpoints, _ := args.Get(0).([]*models.WebhookEndpoint)
+	return endpoints, args.Error(1)
+}
+
+func (m *mockWebhookEndpointRepo) Update(ctx context.Context, endpoint *models.WebhookEndpoint, columns ...string) error {
+	return m.Called(ctx, endpoint, columns).Error(0)
+}
+
+func (m *mockWebhookEndpointRepo) Delete(ctx context.Context, id int64) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+type mockWebhookDeliveryRepo struct {
+	mock.Mock
+}
+
+func (m *mockWebhookDeliveryRepo) FindByID(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	delivery, _ := args.Get(0).(*models.WebhookDelivery)
+	return delivery, args.Error(1)
+}
+
+func (m *mockWebhookDeliveryRepo) ListByEndpoint(ctx context.Context, endpointID int64, limit int) ([]*models.WebhookDelivery, error) {
+	args := m.Called(ctx, endpointID, limit)
+	deliveries, _ := args.Get(0).([]*models.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+type mockPermissionChecker struct {
+	mock.Mock
+}
+
+func (m *mockPermissionChecker) CheckPermission(ctx context.Context, userID int64, tenantID, resource, action string) (bool, error) {
+	args := m.Called(ctx, userID, tenantID, resource, action)
+	return args.Bool(0), args.Error(1)
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+type webhookSvcMocks struct {
+	endpoints   *mockWebhookEndpointRepo
+	deliveries  *mockWebhookDeliveryRepo
+	producer    *mockProducer
+	permissions *mockPermissionChecker
+}
+
+func setupWebhookSvc(allowed bool) (*WebhookService, *webhookSvcMocks) {
+	m := &webhookSvcMocks{
+		endpoints:   new(mockWebhookEndpointRepo),
+		deliveries:  new(mockWebhookDeliveryRepo),
+		producer:    new(mockProducer),
+		permissions: new(mockPermissionChecker),
+	}
+	m.permissions.On("CheckPermission", mock.Anything, int64(42), mock.Anything, "webhooks", "manage").Return(allowed, nil)
+	svc := NewWebhookService(m.endpoints, m.deliveries, m.producer, WebhookOptions{
+		Permissions:   m.permissions,
+		DefaultTenant: "system",
+	})
+	return svc, m
+}
+
+func webhookAuth() authenticator.AuthContext {
+	return authenticator.AuthContext{UserID: authenticator.UserSubject{ID: 42}}
+}
+
+func tenantCtx(tenantID string) context.Context {
+	return requestctx.SetTenantID(context.Background(), tenantID)
+}
+
+func tenantEndpoint(tenantID string, active bool) *models.WebhookEndpoint {
+	return &models.WebhookEndpoint{ID: 7, TenantID: tenantID, URL: "https://hooks.example.com/in", Events: []string{"*"}, IsActive: active}
+}
+
+// ============================================================================
+// Authorization
+// ============================================================================
+
+func TestWebhookService_RequiresPermission(t *testing.T) {
+	svc, m := setupWebhookSvc(false)
+
+	_, err := svc.ListEndpoints(tenantCtx("acme"), webhookAuth())
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+	m.permissions.AssertCalled(t, "CheckPermission", mock.Anything, int64(42), "acme", "webhooks", "manage")
+	m.endpoints.AssertNotCalled(t, "ListByTenant", mock.Anything, mock.Anything)
+}
+
+func TestWebhookService_APIKeyScopeDenied(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	auth := webhookAuth()
+	auth.APIKey = &authenticator.APIKeyIdentity{Scopes: []string{"notifications:send"}}
+
+	_, err := svc.ListEndpoints(context.Background(), auth)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+	m.permissions.AssertNotCalled(t, "CheckPermission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhookService_NoPermissionCheckerDenies(t *testing.T) {
+	svc := NewWebhookService(new(mockWebhookEndpointRepo), new(mockWebhookDeliveryRepo), nil, WebhookOptions{DefaultTenant: "system"})
+
+	_, err := svc.ListEndpoints(context.Background(), webhookAuth())
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+}
+
+// ============================================================================
+// Endpoints
+// ============================================================================
+
+func TestWebhookService_CreateEndpoint(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	m.endpoints.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := svc.CreateEndpoint(context.Background(), webhookAuth(), dto.CreateWebhookEndpointRequest{
+		URL:    "https://hooks.example.com/in",
+		Events: []string{"order.*", "user.created"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "system", resp.TenantID, "falls back to the default tenant")
+	assert.Equal(t, int64(42), resp.CreatedBy)
+	assert.True(t, resp.IsActive)
+	assert.Regexp(t, `^whsec_[0-9a-f]{64}$`, resp.Secret)
+	assert.Equal(t, resp.Secret, resp.WebhookEndpoint.Secret)
+}
+
+func TestWebhookService_CreateEndpoint_RejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		events []string
+	}{
+		{"private address", "http://127.0.0.1/hook", []string{"*"}},
+		{"not http", "ftp://hooks.example.com/in", []string{"*"}},
+		{"bad filter", "https://hooks.example.com/in", []string{"Order Shipped"}},
+		{"wildcard in the middle", "https://hooks.example.com/in", []string{"order.*.created"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, m := setupWebhookSvc(true)
+
+			_, err := svc.CreateEndpoint(context.Background(), webhookAuth(), dto.CreateWebhookEndpointRequest{URL: tt.url, Events: tt.events})
+
+			assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+			m.endpoints.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWebhookService_GetEndpoint_OtherTenantIsNotFound(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	m.endpoints.On("FindByID", mock.Anything, int64(7)).Return(tenantEndpoint("other", true), nil)
+
+	_, err := svc.GetEndpoint(tenantCtx("acme"), webhookAuth(), 7)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+}
+
+func TestWebhookService_UpdateEndpoint_ReenableResetsFailures(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	endpoint := tenantEndpoint("acme", false)
+	endpoint.ConsecutiveFailures = 15
+	endpoint.DisabledReason = "15 failed attempts in a row"
+	m.endpoints.On("FindByID", mock.Anything, int64(7)).Return(endpoint, nil)
+	m.endpoints.On("Update", mock.Anything, endpoint, mock.Anything).Return(nil)
+
+	active := true
+	updated, err := svc.UpdateEndpoint(tenantCtx("acme"), webhookAuth(), 7, dto.UpdateWebhookEndpointRequest{IsActive: &active})
+
+	require.NoError(t, err)
+	assert.True(t, updated.IsActive)
+	assert.Zero(t, updated.ConsecutiveFailures)
+	assert.Empty(t, updated.DisabledReason)
+	m.endpoints.AssertCalled(t, "Update", mock.Anything, endpoint,
+		[]string{"url", "events", "is_active", "consecutive_failures", "disabled_at", "disabled_reason"})
+}
+
+func TestWebhookService_RotateSecret(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	endpoint := tenantEndpoint("acme", true)
+	endpoint.Secret = "whsec_old"
+	m.endpoints.On("FindByID", mock.Anything, int64(7)).Return(endpoint, nil)
+	m.endpoints.On("Update", mock.Anything, endpoint, []string{"secret"}).Return(nil)
+
+	resp, err := svc.RotateSecret(tenantCtx("acme"), webhookAuth(), 7)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, "whsec_old", resp.Secret)
+	assert.Equal(t, resp.Secret, endpoint.Secret)
+}
+
+// ============================================================================
+// Deliveries
+// ============================================================================
+
+func TestWebhookService_ListDeliveries_CapsLimit(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	m.endpoints.On("FindByID", mock.Anything, int64(7)).Return(tenantEndpoint("acme", true), nil)
+	m.deliveries.On("ListByEndpoint", mock.Anything, int64(7), 100).Return([]*models.WebhookDelivery{}, nil)
+
+	_, err := svc.ListDeliveries(tenantCtx("acme"), webhookAuth(), 7, 500)
+
+	require.NoError(t, err)
+	m.deliveries.AssertExpectations(t)
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	m.endpoints.On("FindByID", mock.Anything, int64(7)).Return(tenantEndpoint("acme", true), nil)
+	m.deliveries.On("FindByID", mock.Anything, int64(3)).Return(&models.WebhookDelivery{
+		ID: 3, EndpointID: 7, EventID: "evt-1", EventType: "order.shipped", Attempt: 6, RequestBody: `{"id":"evt-1"}`,
+	}, nil)
+	m.producer.On("Publish", mock.Anything, dto.WebhookDeliveryRoutingKey, dto.WebhookDeliveryJob{
+		EndpointID:   7,
+		EventID:      "evt-1",
+		EventType:    "order.shipped",
+		Attempt:      1,
+		RedeliveryOf: 3,
+		Payload:      json.RawMessage(`{"id":"evt-1"}`),
+	}, mock.Anything).Return(nil)
+
+	require.NoError(t, svc.Redeliver(tenantCtx("acme"), webhookAuth(), 7, 3))
+	m.producer.AssertExpectations(t)
+}
+
+func TestWebhookService_Redeliver_DisabledEndpoint(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	m.endpoints.On("FindByID", mock.Anything, int64(7)).Return(tenantEndpoint("acme", false), nil)
+
+	err := svc.Redeliver(tenantCtx("acme"), webhookAuth(), 7, 3)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeValidation)
+	m.producer.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhookService_Redeliver_DeliveryOfAnotherEndpoint(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	m.endpoints.On("FindByID", mock.Anything, int64(7)).Return(tenantEndpoint("acme", true), nil)
+	m.deliveries.On("FindByID", mock.Anything, int64(3)).Return(&models.WebhookDelivery{ID: 3, EndpointID: 8}, nil)
+
+	err := svc.Redeliver(tenantCtx("acme"), webhookAuth(), 7, 3)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+}
+
+func TestWebhookService_Redeliver_PublishError(t *testing.T) {
+	svc, m := setupWebhookSvc(true)
+	m.endpoints.On("FindByID", mock.Anything, int64(7)).Return(tenantEndpoint("acme", true), nil)
+	m.deliveries.On("FindByID", mock.Anything, int64(3)).Return(&models.WebhookDelivery{ID: 3, EndpointID: 7}, nil)
+	m.producer.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("channel closed"))
+
+	err := svc.Redeliver(tenantCtx("acme"), webhookAuth(), 7, 3)
+
+	assertErrorCode(t, err, pkgErrors.ErrCodeQueue)
+}
@@ -2,8 +2,8 @@ package consumers
 
 import (
 	"context"
-	"encoding/json"
 	"ichi-go/internal/applications/order/dto"
+	"ichi-go/internal/infra/queue/codec"
 	"ichi-go/pkg/logger"
 )
 
@@ -24,7 +24,7 @@ func (c *PaymentConsumer) Consume(ctx context.Context, body []byte) error {
 		EventType string `json:"event_type"`
 	}
 
-	if err := json.Unmarshal(body, &baseEvent); err != nil {
+	if err := codec.Unmarshal(ctx, body, &baseEvent); err != nil {
 		logger.Errorf("Invalid JSON: %v", err)
 		return nil // Don't retry bad JSON
 	}
@@ -46,7 +46,7 @@ func (c *PaymentConsumer) Consume(ctx context.Context, body []byte) error {
 
 func (c *PaymentConsumer) handleCompleted(ctx context.Context, body []byte) error {
 	var event dto.PaymentCompletedEvent
-	if err := json.Unmarshal(body, &event); err != nil {
+	if err := codec.Unmarshal(ctx, body, &event); err != nil {
 		return nil // Skip malformed
 	}
 
@@ -85,7 +85,7 @@ func (c *PaymentConsumer) handleCompleted(ctx context.Context, body []byte) erro
 
 func (c *PaymentConsumer) handleFailed(ctx context.Context, body []byte) error {
 	var event dto.PaymentFailedEvent
-	if err := json.Unmarshal(body, &event); err != nil {
+	if err := codec.Unmarshal(ctx, body, &event); err != nil {
 		return nil
 	}
 
@@ -111,7 +111,7 @@ func (c *PaymentConsumer) handleFailed(ctx context.Context, body []byte) error {
 
 func (c *PaymentConsumer) handleRefunded(ctx context.Context, body []byte) error {
 	var event dto.PaymentRefundedEvent
-	if err := json.Unmarshal(body, &event); err != nil {
+	if err := codec.Unmarshal(ctx, body, &event); err != nil {
 		return nil
 	}
 
@@ -93,6 +93,12 @@ const (
 	TenantsEdit   = "tenants:edit"
 	TenantsDelete = "tenants:delete"
 	TenantsManage = "tenants:manage"
+
+	// Notification permissions
+	NotificationsSend = "notifications:send"
+	WebhooksManage    = "webhooks:manage"
+	TemplatesManage   = "templates:manage"
+	CampaignsManage   = "campaigns:manage"
 )
 
 // Special permission flags
@@ -3,7 +3,6 @@ package controllers
 import (
 	"fmt"
 	"net/http"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -229,7 +228,7 @@ func (c *AuditController) GetAuditStats(ctx *echo.Context) error {
 // ExportAuditLogs godoc
 //
 //	@Summary		Export audit logs
-//	@Description	Export audit logs to CSV or JSON format with optional filtering
+//	@Description	Export audit logs to CSV or JSON format with optional filtering. The export is stored on the file disk and returned as a temporary download URL.
 //	@Tags			RBAC - Audit
 //	@Accept			json
 //	@Produce		json
@@ -264,40 +263,16 @@ func (c *AuditController) ExportAuditLogs(ctx *echo.Context) error {
 	// Generate filename
 	safeFileName := sanitizeExportFileName(req.FileName, req.Format)
 
-	// Create export directory
-	exportDir := "./exports/audit"
-	if err := os.MkdirAll(exportDir, 0755); err != nil {
-		return response.Error(ctx, http.StatusInternalServerError, err)
-	}
-
-	filePath := filepath.Join(exportDir, safeFileName)
-
-	// Export based on format
-	var err error
-	var recordCount int
-
-	switch req.Format {
-	case "csv":
-		err = c.auditService.ExportToCSV(ctx.Request().Context(), query, filePath)
-	case "json":
-		err = c.auditService.ExportToJSON(ctx.Request().Context(), query, filePath)
-	default:
-		return response.Error(ctx, http.StatusBadRequest, echo.NewHTTPError(http.StatusBadRequest, "Invalid format. Use 'csv' or 'json'"))
-	}
-
+	export, err := c.auditService.Export(ctx.Request().Context(), query, req.Format, safeFileName)
 	if err != nil {
 		return response.Error(ctx, http.StatusInternalServerError, err)
 	}
 
-	// Get record count (re-query with no limit)
-	logs, _, err := c.auditService.QueryAuditLogs(ctx.Request().Context(), query)
-	if err == nil {
-		recordCount = len(logs)
-	}
-
 	resp := dto.ExportAuditLogsResponse{
-		FilePath:    filePath,
-		RecordCount: recordCount,
+		Path:        export.Path,
+		DownloadURL: export.DownloadURL,
+		ExpiresAt:   export.ExpiresAt,
+		RecordCount: export.RecordCount,
 		ExportedAt:  time.Now(),
 		Format:      req.Format,
 	}
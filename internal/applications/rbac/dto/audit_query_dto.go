@@ -83,7 +83,9 @@ type ExportAuditLogsRequest struct {
 
 // ExportAuditLogsResponse represents the response for export request
 type ExportAuditLogsResponse struct {
-	FilePath    string    `json:"file_path"`
+	Path        string    `json:"path"`         // storage path of the export
+	DownloadURL string    `json:"download_url"` // temporary, expires at ExpiresAt
+	ExpiresAt   time.Time `json:"expires_at"`
 	RecordCount int       `json:"record_count"`
 	ExportedAt  time.Time `json:"exported_at"`
 	Format      string    `json:"format"`
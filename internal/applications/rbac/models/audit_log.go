@@ -60,6 +60,12 @@ const (
 	ActionRoleRevoked       = "role_revoked"
 	ActionPermissionChecked = "permission_checked"
 	ActionPermissionDenied  = "permission_denied"
+
+	// Authentication security events
+	ActionLoginLocked   = "login_locked"
+	ActionLoginUnlocked = "login_unlocked"
+	ActionMFAEnabled    = "mfa_enabled"
+	ActionMFADisabled   = "mfa_disabled"
 )
 
 // Actor types
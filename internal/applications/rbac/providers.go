@@ -9,6 +9,7 @@ import (
 	"ichi-go/internal/infra/authz/enforcer"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	"ichi-go/pkg/logger"
+	"ichi-go/pkg/storage"
 
 	"github.com/redis/go-redis/v9"
 
@@ -28,6 +29,7 @@ func RegisterProviders(injector do.Injector) {
 	do.Provide(injector, ProvideUserRoleRepository)
 	do.Provide(injector, ProvideAuditRepository)
 	do.Provide(injector, ProvidePlatformRepository)
+	do.Provide(injector, ProvideUserVerificationRepository)
 
 	// Services
 	do.Provide(injector, ProvideEnforcementService)
@@ -76,6 +78,11 @@ func ProvidePlatformRepository(i do.Injector) (*repositories.PlatformRepository,
 	return repositories.NewPlatformRepository(db), nil
 }
 
+func ProvideUserVerificationRepository(i do.Injector) (*repositories.UserVerificationRepository, error) {
+	db := do.MustInvoke[*bun.DB](i)
+	return repositories.NewUserVerificationRepository(db), nil
+}
+
 func ProvideDecisionCache(i do.Injector) (*cache.DecisionCache, error) {
 	cfg := do.MustInvoke[*config.Config](i)
 	cacheClient := do.MustInvoke[*redis.Client](i)
@@ -94,8 +101,9 @@ func ProvideEnforcementService(i do.Injector) (*services.EnforcementService, err
 	platformRepo := do.MustInvoke[*repositories.PlatformRepository](i)
 	auditRepo := do.MustInvoke[*repositories.AuditRepository](i)
 	decisionCache := do.MustInvoke[*cache.DecisionCache](i)
+	verifications := do.MustInvoke[*repositories.UserVerificationRepository](i)
 
-	return services.NewEnforcementService(enf, decisionCache, platformRepo, auditRepo, cfg.RBAC()), nil
+	return services.NewEnforcementService(enf, decisionCache, platformRepo, auditRepo, verifications, cfg.RBAC()), nil
 }
 
 func ProvidePolicyService(i do.Injector) (*services.PolicyService, error) {
@@ -150,7 +158,12 @@ func ProvideUserRoleService(i do.Injector) (*services.UserRoleService, error) {
 
 func ProvideAuditService(i do.Injector) (*services.AuditService, error) {
 	auditRepo := do.MustInvoke[*repositories.AuditRepository](i)
-	return services.NewAuditService(auditRepo), nil
+	disk, err := do.Invoke[storage.Disk](i)
+	if err != nil || disk == nil {
+		logger.Warnf("⚠️  File storage not available, audit exports disabled: %v", err)
+	}
+	cfg := do.MustInvoke[*config.Config](i)
+	return services.NewAuditService(auditRepo, disk, cfg.Storage().URLTTL), nil
 }
 
 // Controller Providers
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+)
+
+// UserVerificationRepository reads the email verification state of users
+type UserVerificationRepository struct {
+	db *bun.DB
+}
+
+// NewUserVerificationRepository creates a new user verification repository
+func NewUserVerificationRepository(db *bun.DB) *UserVerificationRepository {
+	return &UserVerificationRepository{
+		db: db,
+	}
+}
+
+// IsEmailVerified checks if a user has verified their email address
+func (r *UserVerificationRepository) IsEmailVerified(ctx context.Context, userID int64) (bool, error) {
+	exists, err := r.db.NewSelect().
+		TableExpr("users").
+		Where("id = ?", userID).
+		Where("email_verified_at IS NOT NULL").
+		Where("deleted_at IS NULL").
+		Exists(ctx)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check email verification: %w", err)
+	}
+
+	return exists, nil
+}
@@ -1,27 +1,58 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"path"
+	"regexp"
 	"time"
 
 	"ichi-go/internal/applications/rbac/models"
 	"ichi-go/internal/applications/rbac/repositories"
 	"ichi-go/pkg/logger"
+	"ichi-go/pkg/storage"
+
+	"github.com/google/uuid"
 )
 
+// auditExportDir is the storage directory audit exports are written to, one
+// <tenant>/<export id> directory per export
+const auditExportDir = "exports/audit"
+
+// allTenantsExportDir holds exports not filtered by tenant
+const allTenantsExportDir = "all"
+
+var unsafePathSegment = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
 // AuditService handles audit log queries and exports
 type AuditService struct {
 	auditRepo *repositories.AuditRepository
+	disk      storage.Disk  // nil disables exports
+	urlTTL    time.Duration // lifetime of export download links
+}
+
+// AuditExport is an audit log export stored on the file disk
+type AuditExport struct {
+	Path        string
+	DownloadURL string
+	ExpiresAt   time.Time
+	RecordCount int
 }
 
 // NewAuditService creates a new audit service
-func NewAuditService(auditRepo *repositories.AuditRepository) *AuditService {
+func NewAuditService(auditRepo *repositories.AuditRepository, disk storage.Disk, urlTTL time.Duration) *AuditService {
+	if urlTTL <= 0 {
+		urlTTL = 15 * time.Minute
+	}
 	return &AuditService{
 		auditRepo: auditRepo,
+		disk:      disk,
+		urlTTL:    urlTTL,
 	}
 }
 
@@ -74,27 +105,73 @@ func (s *AuditService) GetAuditStats(
 	return s.auditRepo.GetStatsByTenant(ctx, tenantID, startDate, endDate)
 }
 
-// ExportToCSV exports audit logs to CSV format (SOC2/GDPR compliance)
-func (s *AuditService) ExportToCSV(
+// Export writes the audit logs matching query to the file disk as format ("csv" or
+// "json") and returns a temporary download URL for it (SOC2/GDPR compliance)
+func (s *AuditService) Export(
 	ctx context.Context,
 	query repositories.AuditQuery,
-	filePath string,
-) error {
-	// Get all matching logs
-	logs, err := s.auditRepo.FindByQuery(ctx, query)
+	format, fileName string,
+) (*AuditExport, error) {
+	if s.disk == nil {
+		return nil, errors.New("file storage is not configured")
+	}
+
+	var buf bytes.Buffer
+	var count int
+	var err error
+	switch format {
+	case "csv":
+		count, err = s.ExportToCSV(ctx, query, &buf)
+	case "json":
+		count, err = s.ExportToJSON(ctx, query, &buf)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	path := auditExportPath(query.TenantID, fileName)
+	if err := s.disk.Put(ctx, path, &buf, storage.PutOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to store audit export: %w", err)
+	}
+	downloadURL, err := s.disk.TemporaryURL(ctx, path, s.urlTTL)
 	if err != nil {
-		return fmt.Errorf("failed to fetch audit logs: %w", err)
+		return nil, fmt.Errorf("failed to sign audit export url: %w", err)
+	}
+
+	logger.WithContext(ctx).Infof("Stored audit export %s (%d records)", path, count)
+	return &AuditExport{
+		Path:        path,
+		DownloadURL: downloadURL,
+		ExpiresAt:   time.Now().Add(s.urlTTL),
+		RecordCount: count,
+	}, nil
+}
+
+// auditExportPath returns a path no other export uses, so exports of different
+// tenants, or repeated exports under one file name, never overwrite each other
+func auditExportPath(tenantID *string, fileName string) string {
+	tenant := allTenantsExportDir
+	if tenantID != nil && *tenantID != "" {
+		tenant = "tenant-" + unsafePathSegment.ReplaceAllString(*tenantID, "_")
 	}
+	return path.Join(auditExportDir, tenant, uuid.NewString(), fileName)
+}
 
-	// Create CSV file
-	file, err := os.Create(filePath)
+// ExportToCSV writes audit logs to w in CSV format and returns the number of records
+func (s *AuditService) ExportToCSV(
+	ctx context.Context,
+	query repositories.AuditQuery,
+	w io.Writer,
+) (int, error) {
+	// Get all matching logs
+	logs, err := s.auditRepo.FindByQuery(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
+		return 0, fmt.Errorf("failed to fetch audit logs: %w", err)
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	writer := csv.NewWriter(w)
 
 	// Write header
 	header := []string{
@@ -103,7 +180,7 @@ func (s *AuditService) ExportToCSV(
 		"Decision", "DecisionReason", "Reason", "LatencyMs",
 	}
 	if err := writer.Write(header); err != nil {
-		return fmt.Errorf("failed to write CSV header: %w", err)
+		return 0, fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
 	// Write rows
@@ -129,41 +206,39 @@ func (s *AuditService) ExportToCSV(
 		}
 	}
 
-	logger.WithContext(ctx).Infof("Exported %d audit logs to %s", len(logs), filePath)
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	logger.WithContext(ctx).Infof("Exported %d audit logs to CSV", len(logs))
 
-	return nil
+	return len(logs), nil
 }
 
-// ExportToJSON exports audit logs to JSON format (SOC2/GDPR compliance)
+// ExportToJSON writes audit logs to w in JSON format and returns the number of records
 func (s *AuditService) ExportToJSON(
 	ctx context.Context,
 	query repositories.AuditQuery,
-	filePath string,
-) error {
+	w io.Writer,
+) (int, error) {
 	// Get all matching logs
 	logs, err := s.auditRepo.FindByQuery(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to fetch audit logs: %w", err)
-	}
-
-	// Create JSON file
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create JSON file: %w", err)
+		return 0, fmt.Errorf("failed to fetch audit logs: %w", err)
 	}
-	defer file.Close()
 
 	// Marshal to JSON
-	encoder := json.NewEncoder(file)
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 
 	if err := encoder.Encode(logs); err != nil {
-		return fmt.Errorf("failed to write JSON: %w", err)
+		return 0, fmt.Errorf("failed to write JSON: %w", err)
 	}
 
-	logger.WithContext(ctx).Infof("Exported %d audit logs to %s", len(logs), filePath)
+	logger.WithContext(ctx).Infof("Exported %d audit logs to JSON", len(logs))
 
-	return nil
+	return len(logs), nil
 }
 
 // CleanupOldLogs deletes audit logs older than retention period
@@ -20,6 +20,7 @@ type EnforcementService struct {
 	decisionCache *cache.DecisionCache
 	platformRepo  *repositories.PlatformRepository
 	auditRepo     *repositories.AuditRepository
+	verifications *repositories.UserVerificationRepository
 	config        *rbac.Config
 }
 
@@ -41,6 +42,7 @@ func NewEnforcementService(
 	decisionCache *cache.DecisionCache,
 	platformRepo *repositories.PlatformRepository,
 	auditRepo *repositories.AuditRepository,
+	verifications *repositories.UserVerificationRepository,
 	config *rbac.Config,
 ) *EnforcementService {
 	return &EnforcementService{
@@ -48,6 +50,7 @@ func NewEnforcementService(
 		decisionCache: decisionCache,
 		platformRepo:  platformRepo,
 		auditRepo:     auditRepo,
+		verifications: verifications,
 		config:        config,
 	}
 }
@@ -63,6 +66,19 @@ func (s *EnforcementService) CheckPermission(
 ) (bool, error) {
 	startTime := time.Now()
 
+	// 0. Permissions reserved for verified email addresses apply to everyone, admins included
+	if s.requiresVerifiedEmail(resource, action) {
+		verified, err := s.verifications.IsEmailVerified(ctx, userID)
+		if err != nil {
+			logger.WithContext(ctx).Errorf("Failed to check email verification: %v", err)
+			return false, fmt.Errorf("permission check failed: %w", err)
+		}
+		if !verified {
+			s.auditDecision(ctx, userID, tenantID, resource, action, false, "email_not_verified", startTime)
+			return false, nil
+		}
+	}
+
 	// 1. Check platform permissions first (Layer 1)
 	isPlatformAdmin, err := s.platformRepo.IsPlatformAdmin(ctx, userID)
 	if err != nil {
@@ -130,6 +146,14 @@ func (s *EnforcementService) CheckBatch(
 	if isPlatformAdmin {
 		for _, check := range checks {
 			key := fmt.Sprintf("%s:%s", check.Resource, check.Action)
+			if s.requiresVerifiedEmail(check.Resource, check.Action) {
+				allowed, err := s.CheckPermission(ctx, userID, tenantID, check.Resource, check.Action)
+				if err != nil {
+					return nil, err
+				}
+				results[key] = allowed
+				continue
+			}
 			results[key] = true
 		}
 		return results, nil
@@ -211,6 +235,20 @@ func (s *EnforcementService) RequirePermission(
 	return nil
 }
 
+// requiresVerifiedEmail reports whether the email verification policy reserves
+// resource:action for users with a verified email address
+func (s *EnforcementService) requiresVerifiedEmail(resource, action string) bool {
+	if s.verifications == nil {
+		return false
+	}
+	for _, permission := range s.config.EmailVerification.RequiredFor {
+		if permission == resource+":"+action || permission == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
 // auditDecision logs a permission check decision
 func (s *EnforcementService) auditDecision(
 	ctx context.Context,
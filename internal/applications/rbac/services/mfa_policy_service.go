@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"ichi-go/internal/applications/rbac/repositories"
+	"ichi-go/pkg/rbac"
+)
+
+// MFAPolicyService decides which users must sign in with multi-factor authentication
+type MFAPolicyService struct {
+	platformRepo *repositories.PlatformRepository
+	userRoleRepo *repositories.UserRoleRepository
+	requiredFor  []string
+}
+
+// NewMFAPolicyService creates a new MFA policy service from rbac.mfa.required_for
+func NewMFAPolicyService(
+	platformRepo *repositories.PlatformRepository,
+	userRoleRepo *repositories.UserRoleRepository,
+	cfg *rbac.Config,
+) *MFAPolicyService {
+	return &MFAPolicyService{
+		platformRepo: platformRepo,
+		userRoleRepo: userRoleRepo,
+		requiredFor:  cfg.MFA.RequiredFor,
+	}
+}
+
+// RequiresMFA reports whether the user holds a platform permission or an active
+// role (in any tenant) listed in the policy
+func (s *MFAPolicyService) RequiresMFA(ctx context.Context, userID int64) (bool, error) {
+	if len(s.requiredFor) == 0 {
+		return false, nil
+	}
+
+	permissions, err := s.platformRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check MFA policy: %w", err)
+	}
+	for _, permission := range permissions {
+		if slices.Contains(s.requiredFor, permission.Permission) {
+			return true, nil
+		}
+	}
+
+	userRoles, err := s.userRoleRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check MFA policy: %w", err)
+	}
+	for _, userRole := range userRoles {
+		if userRole.Role != nil && userRole.IsActive() && slices.Contains(s.requiredFor, userRole.Role.Slug) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,217 @@
+package consumers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"ichi-go/internal/applications/user/dto"
+	userRepo "ichi-go/internal/applications/user/repository"
+	"ichi-go/internal/infra/cache"
+	"ichi-go/internal/infra/queue/codec"
+	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/storage"
+)
+
+// DataExportConsumer builds the GDPR export archive of a user.
+//
+// Routing key: "user.data_export". The archive is a zip holding one JSON file per
+// table plus manifest.json, stored at exports/gdpr/<user id>/<request id>.zip.
+type DataExportConsumer struct {
+	repo userRepo.DataRequestRepository
+	disk storage.Disk
+	now  func() time.Time
+}
+
+func NewDataExportConsumer(repo userRepo.DataRequestRepository, disk storage.Disk) *DataExportConsumer {
+	return &DataExportConsumer{repo: repo, disk: disk, now: time.Now}
+}
+
+// Consume is the ConsumeFunc registered in registry.go.
+func (c *DataExportConsumer) Consume(ctx context.Context, body []byte) error {
+	req, err := startDataRequest(ctx, c.repo, body, model.DataRequestExport)
+	if req == nil {
+		return err
+	}
+	if c.disk == nil {
+		return failDataRequest(ctx, c.repo, req, "file storage is not configured", nil)
+	}
+
+	data, err := c.repo.CollectPersonalData(ctx, req.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return failDataRequest(ctx, c.repo, req, "user not found", nil)
+	}
+	if err != nil {
+		return failDataRequest(ctx, c.repo, req, "collecting personal data failed", err)
+	}
+
+	archive, err := buildExportArchive(req, data, c.now())
+	if err != nil {
+		return failDataRequest(ctx, c.repo, req, "building the archive failed", err)
+	}
+
+	path := fmt.Sprintf("exports/gdpr/%d/%d.zip", req.UserID, req.ID)
+	if err := c.disk.Put(ctx, path, bytes.NewReader(archive), storage.PutOptions{ContentType: "application/zip"}); err != nil {
+		return failDataRequest(ctx, c.repo, req, "storing the archive failed", err)
+	}
+	if err := c.repo.MarkCompleted(ctx, req.ID, path); err != nil {
+		return err
+	}
+
+	logger.Infof("[gdpr] export %d of user %d completed (%d bytes)", req.ID, req.UserID, len(archive))
+	return nil
+}
+
+// exportManifest describes an export archive
+type exportManifest struct {
+	RequestID   int64          `json:"request_id"`
+	UserID      uint64         `json:"user_id"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Records     map[string]int `json:"records"` // rows per table file
+}
+
+func buildExportArchive(req *model.UserDataRequest, data userRepo.PersonalData, now time.Time) ([]byte, error) {
+	tables := make([]string, 0, len(data))
+	for table := range data {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	manifest := exportManifest{RequestID: req.ID, UserID: req.UserID, GeneratedAt: now.UTC(), Records: map[string]int{}}
+	for _, table := range tables {
+		manifest.Records[table] = len(data[table])
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	write := func(name string, v any) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	if err := write("manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if err := write(table+".json", data[table]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DataErasureConsumer erases the personal data of a user.
+//
+// Routing key: "user.data_erasure". Database changes happen in one transaction;
+// files the erased rows referenced (avatar, earlier exports) are removed afterwards.
+type DataErasureConsumer struct {
+	repo  userRepo.DataRequestRepository
+	disk  storage.Disk // nil leaves files behind
+	cache cache.Cache  // nil skips dropping the cached user
+}
+
+func NewDataErasureConsumer(repo userRepo.DataRequestRepository, disk storage.Disk, cache cache.Cache) *DataErasureConsumer {
+	return &DataErasureConsumer{repo: repo, disk: disk, cache: cache}
+}
+
+// Consume is the ConsumeFunc registered in registry.go.
+func (c *DataErasureConsumer) Consume(ctx context.Context, body []byte) error {
+	req, err := startDataRequest(ctx, c.repo, body, model.DataRequestErasure)
+	if req == nil {
+		return err
+	}
+
+	result, err := c.repo.Erase(ctx, req.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return failDataRequest(ctx, c.repo, req, "user not found", nil)
+	}
+	if err != nil {
+		return failDataRequest(ctx, c.repo, req, "erasure failed", err)
+	}
+
+	files := result.ExportPaths
+	if result.AvatarPath != "" {
+		files = append(files, result.AvatarPath)
+	}
+	for _, path := range files {
+		if c.disk == nil {
+			logger.Warnf("[gdpr] file storage not configured, %s of erased user %d left behind", path, req.UserID)
+			continue
+		}
+		if err := c.disk.Delete(ctx, path); err != nil {
+			logger.Errorf("[gdpr] failed to delete %s of erased user %d: %v", path, req.UserID, err)
+		}
+	}
+	if c.cache != nil {
+		_, _ = c.cache.Delete(ctx, fmt.Sprintf("user:%d", req.UserID))
+	}
+
+	if err := c.repo.MarkCompleted(ctx, req.ID, ""); err != nil {
+		return err
+	}
+	logger.Infof("[gdpr] erasure %d of user %d completed", req.ID, req.UserID)
+	return nil
+}
+
+// startDataRequest decodes a job and moves its request to processing. It returns a nil
+// request when there is nothing to do, together with the error to report to the queue.
+func startDataRequest(ctx context.Context, repo userRepo.DataRequestRepository, body []byte, requestType string) (*model.UserDataRequest, error) {
+	if repo == nil {
+		logger.Errorf("[gdpr] database not available, discarding %s job", requestType)
+		return nil, nil
+	}
+
+	var message dto.DataRequestMessage
+	if err := codec.Unmarshal(ctx, body, &message); err != nil {
+		logger.Errorf("[gdpr] invalid %s job, discarding: %v", requestType, err)
+		return nil, nil
+	}
+
+	req, err := repo.FindByID(ctx, message.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil || req.Type != requestType || req.UserID != message.UserID {
+		logger.Warnf("[gdpr] unknown %s request %d, discarding", requestType, message.RequestID)
+		return nil, nil
+	}
+	if req.Status == model.DataRequestCompleted {
+		// Redelivered after completing
+		return nil, nil
+	}
+
+	if err := repo.MarkProcessing(ctx, req.ID); err != nil {
+		return nil, err
+	}
+	req.Status = model.DataRequestProcessing
+	return req, nil
+}
+
+// failDataRequest records why a request failed. A non-nil cause is returned so the
+// queue retries; the retry moves the request back to processing.
+func failDataRequest(ctx context.Context, repo userRepo.DataRequestRepository, req *model.UserDataRequest, reason string, cause error) error {
+	if cause != nil {
+		logger.Errorf("[gdpr] %s request %d of user %d: %s: %v", req.Type, req.ID, req.UserID, reason, cause)
+	} else {
+		logger.Errorf("[gdpr] %s request %d of user %d: %s", req.Type, req.ID, req.UserID, reason)
+	}
+	if err := repo.MarkFailed(ctx, req.ID, reason); err != nil {
+		logger.Errorf("[gdpr] failed to mark request %d failed: %v", req.ID, err)
+	}
+	return cause
+}
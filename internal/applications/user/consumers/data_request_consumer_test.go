@@ -0,0 +1,104 @@
+package consumers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"ichi-go/internal/applications/user/dto"
+	userRepo "ichi-go/internal/applications/user/repository"
+	userRepoMocks "ichi-go/internal/applications/user/repository/mocks"
+	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func jobBody(t *testing.T, requestID int64, userID uint64) []byte {
+	t.Helper()
+	body, err := json.Marshal(dto.DataRequestMessage{RequestID: requestID, UserID: userID})
+	require.NoError(t, err)
+	return body
+}
+
+func pendingRequest(requestType string) *model.UserDataRequest {
+	return &model.UserDataRequest{ID: 42, UserID: 7, Type: requestType, Status: model.DataRequestPending}
+}
+
+func TestDataExportConsumer_StoresArchive(t *testing.T) {
+	repo := userRepoMocks.NewMockDataRequestRepository(t)
+	disk := storage.NewMemoryDisk(nil)
+	repo.On("FindByID", mock.Anything, int64(42)).Return(pendingRequest(model.DataRequestExport), nil)
+	repo.On("MarkProcessing", mock.Anything, int64(42)).Return(nil)
+	repo.On("CollectPersonalData", mock.Anything, uint64(7)).Return(userRepo.PersonalData{
+		"users":  {{"id": 7, "email": "jane@example.com"}},
+		"orders": {},
+	}, nil)
+	repo.On("MarkCompleted", mock.Anything, int64(42), "exports/gdpr/7/42.zip").Return(nil)
+
+	require.NoError(t, NewDataExportConsumer(repo, disk).Consume(context.Background(), jobBody(t, 42, 7)))
+
+	archive, err := disk.Get(context.Background(), "exports/gdpr/7/42.zip")
+	require.NoError(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+	}
+
+	require.Contains(t, files, "manifest.json")
+	var manifest exportManifest
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	assert.Equal(t, map[string]int{"users": 1, "orders": 0}, manifest.Records)
+	assert.Contains(t, string(files["users.json"]), "jane@example.com")
+	assert.Contains(t, files, "orders.json")
+}
+
+func TestDataExportConsumer_MarksFailedAndRetriesOnError(t *testing.T) {
+	repo := userRepoMocks.NewMockDataRequestRepository(t)
+	repo.On("FindByID", mock.Anything, int64(42)).Return(pendingRequest(model.DataRequestExport), nil)
+	repo.On("MarkProcessing", mock.Anything, int64(42)).Return(nil)
+	repo.On("CollectPersonalData", mock.Anything, uint64(7)).Return(userRepo.PersonalData(nil), errors.New("connection reset"))
+	repo.On("MarkFailed", mock.Anything, int64(42), mock.AnythingOfType("string")).Return(nil)
+
+	err := NewDataExportConsumer(repo, storage.NewMemoryDisk(nil)).Consume(context.Background(), jobBody(t, 42, 7))
+	assert.Error(t, err, "the queue retries failed exports")
+}
+
+func TestDataErasureConsumer_SkipsCompletedRequest(t *testing.T) {
+	repo := userRepoMocks.NewMockDataRequestRepository(t)
+	done := pendingRequest(model.DataRequestErasure)
+	done.Status = model.DataRequestCompleted
+	repo.On("FindByID", mock.Anything, int64(42)).Return(done, nil)
+
+	assert.NoError(t, NewDataErasureConsumer(repo, nil, nil).Consume(context.Background(), jobBody(t, 42, 7)))
+}
+
+func TestDataErasureConsumer_DeletesFilesOfErasedUser(t *testing.T) {
+	repo := userRepoMocks.NewMockDataRequestRepository(t)
+	disk := storage.NewMemoryDisk(nil)
+	for _, path := range []string{"avatars/7/me.png", "exports/gdpr/7/41.zip"} {
+		require.NoError(t, disk.Put(context.Background(), path, bytes.NewReader([]byte("x")), storage.PutOptions{}))
+	}
+	repo.On("FindByID", mock.Anything, int64(42)).Return(pendingRequest(model.DataRequestErasure), nil)
+	repo.On("MarkProcessing", mock.Anything, int64(42)).Return(nil)
+	repo.On("Erase", mock.Anything, uint64(7)).Return(&userRepo.ErasureResult{
+		AvatarPath:  "avatars/7/me.png",
+		ExportPaths: []string{"exports/gdpr/7/41.zip"},
+	}, nil)
+	repo.On("MarkCompleted", mock.Anything, int64(42), "").Return(nil)
+
+	require.NoError(t, NewDataErasureConsumer(repo, disk, nil).Consume(context.Background(), jobBody(t, 42, 7)))
+	assert.Empty(t, disk.Files())
+}
@@ -2,8 +2,8 @@ package consumers
 
 import (
 	"context"
-	"encoding/json"
 	"ichi-go/internal/applications/user/dto"
+	"ichi-go/internal/infra/queue/codec"
 	"ichi-go/pkg/logger"
 )
 
@@ -25,7 +25,7 @@ func (c *WelcomeNotificationConsumer) Consume(ctx context.Context, body []byte)
 	var message dto.WelcomeNotificationMessage
 
 	// Parse message
-	if err := json.Unmarshal(body, &message); err != nil {
+	if err := codec.Unmarshal(ctx, body, &message); err != nil {
 		logger.Errorf("Invalid JSON - skipping: %v", err)
 		return nil // Don't retry bad JSON
 	}
@@ -1,14 +1,15 @@
 package user
 
 import (
+	"context"
+	"errors"
 	userDto "ichi-go/internal/applications/user/dto"
 	userService "ichi-go/internal/applications/user/service"
+	"ichi-go/pkg/authenticator"
 	pokeDto "ichi-go/pkg/clients/pokemonapi/dto"
-	"ichi-go/pkg/db/model"
 	"ichi-go/pkg/logger"
-	"ichi-go/pkg/requestctx"
-	mapper "ichi-go/pkg/utils/dto"
 	"ichi-go/pkg/utils/response"
+	appValidator "ichi-go/pkg/validator"
 	"net/http"
 	"strconv"
 
@@ -16,6 +17,10 @@ import (
 	"github.com/labstack/echo/v5"
 )
 
+// maxAvatarRequestBytes bounds the whole multipart request of an avatar upload; the
+// service applies the configured size limit to the file itself.
+const maxAvatarRequestBytes = 10 << 20
+
 type UserController struct {
 	service *userService.ServiceImpl
 }
@@ -24,72 +29,481 @@ func NewUserController(service *userService.ServiceImpl) *UserController {
 	return &UserController{service: service}
 }
 
+// ListUsers godoc
+//
+//	@Summary		List users
+//	@Description	Search, sort and page through users. Requires the users:view permission.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			email			query		string													false	"Email contains"
+//	@Param			name			query		string													false	"Name contains"
+//	@Param			created_from	query		string													false	"Created at or after (RFC 3339)"
+//	@Param			created_to		query		string													false	"Created before (RFC 3339)"
+//	@Param			status			query		string													false	"active (default), deleted or all"
+//	@Param			email_verified	query		bool													false	"Filter by email verification"
+//	@Param			sort_by			query		string													false	"id, name, email, created_at (default) or updated_at"
+//	@Param			sort_dir		query		string													false	"asc or desc (default)"
+//	@Param			page			query		int														false	"Page number"	default(1)
+//	@Param			page_size		query		int														false	"Page size"		default(20)
+//	@Success		200				{object}	response.SuccessResponse{data=userDto.UserListResponse}	"Users"
+//	@Failure		400				{object}	response.ErrorResponse									"Invalid request or validation error"
+//	@Failure		401				{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403				{object}	response.ErrorResponse									"Permission denied"
+//	@Failure		500				{object}	response.ErrorResponse									"Internal server error"
+//	@Router			/api/users [get]
+func (c *UserController) ListUsers(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req userDto.UserListRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("List users request validation failed: %v", err)
+		return err
+	}
+
+	users, err := c.service.ListUsers(eCtx.Request().Context(), *authCtx, req)
+	if err != nil {
+		logger.Errorf("Failed to list users: %v", err)
+		return err
+	}
+
+	return response.Success(eCtx, users)
+}
+
+// GetUser godoc
+//
+//	@Summary		Get a user
+//	@Description	Get a user by ID. Users can always read their own account; others require users:view.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int														true	"User ID"
+//	@Success		200	{object}	response.SuccessResponse{data=userDto.UserGetResponse}	"User"
+//	@Failure		400	{object}	response.ErrorResponse									"Invalid user ID"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse									"Permission denied"
+//	@Failure		404	{object}	response.ErrorResponse									"User not found"
+//	@Router			/api/users/{id} [get]
 func (c *UserController) GetUser(eCtx *echo.Context) error {
-	var userGetReq userDto.UserGetRequest
-	logger.Debugf("GetUser request: %+v", requestctx.FromContext(eCtx.Request().Context()))
-	err := eCtx.Bind(&userGetReq)
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseUint(eCtx.Param("id"), 10, 64)
 	if err != nil {
-		return response.Error(eCtx, http.StatusBadRequest, err)
+		return response.Error(eCtx, http.StatusBadRequest,
+			echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID"))
 	}
 
-	idInt, err := strconv.Atoi(userGetReq.ID)
+	user, err := c.service.GetUser(eCtx.Request().Context(), *authCtx, id)
 	if err != nil {
-		return response.Error(eCtx, http.StatusBadRequest, err)
+		logger.Errorf("Failed to get user %d: %v", id, err)
+		return err
 	}
-	user, err := c.service.GetById(eCtx.Request().Context(), uint32(idInt))
+
+	return response.Success(eCtx, user)
+}
+
+// CreateUser godoc
+//
+//	@Summary		Create a user
+//	@Description	Create a user account on someone's behalf. Requires the users:create permission.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		userDto.UserCreateRequest								true	"User details"
+//	@Success		201		{object}	response.SuccessResponse{data=userDto.UserGetResponse}	"User created"
+//	@Failure		400		{object}	response.ErrorResponse									"Invalid request or validation error"
+//	@Failure		401		{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse									"Permission denied"
+//	@Failure		409		{object}	response.ErrorResponse									"Email already registered"
+//	@Failure		500		{object}	response.ErrorResponse									"Internal server error"
+//	@Router			/api/users [post]
+func (c *UserController) CreateUser(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req userDto.UserCreateRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Create user request validation failed: %v", err)
+		return err
+	}
+
+	user, err := c.service.CreateUser(eCtx.Request().Context(), *authCtx, req)
 	if err != nil {
-		return response.Error(eCtx, http.StatusBadRequest, err)
+		logger.Errorf("Failed to create user: %v", err)
+		return err
 	}
-	var userGetResponseDto userDto.UserGetResponse
-	dtoMap := mapper.New()
-	err = dtoMap.SafeMap(&userGetResponseDto, user)
+
+	return response.Created(eCtx, user)
+}
+
+// UpdateUser godoc
+//
+//	@Summary		Update a user
+//	@Description	Change a user's name and email. Users can always update their own account; others require users:edit.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int														true	"User ID"
+//	@Param			request	body		userDto.UserUpdateRequest								true	"User details"
+//	@Success		200		{object}	response.SuccessResponse{data=userDto.UserGetResponse}	"User updated"
+//	@Failure		400		{object}	response.ErrorResponse									"Invalid request or validation error"
+//	@Failure		401		{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse									"Permission denied"
+//	@Failure		404		{object}	response.ErrorResponse									"User not found"
+//	@Failure		409		{object}	response.ErrorResponse									"Email already registered"
+//	@Router			/api/users/{id} [put]
+func (c *UserController) UpdateUser(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	var req userDto.UserUpdateRequest
+	if err := appValidator.BindAndValidate(eCtx, &req); err != nil {
+		logger.Errorf("Update user request validation failed: %v", err)
+		return err
+	}
+
+	user, err := c.service.UpdateUser(eCtx.Request().Context(), *authCtx, req)
 	if err != nil {
-		return response.Error(eCtx, http.StatusInternalServerError, err)
+		logger.Errorf("Failed to update user %d: %v", req.ID, err)
+		return err
 	}
 
 	return response.Success(eCtx, user)
 }
 
-func (c *UserController) CreateUser(eCtx *echo.Context) error {
-	var userCreateReq userDto.UserCreateRequest
-	err := eCtx.Bind(&userCreateReq)
+// DeleteUser godoc
+//
+//	@Summary		Delete a user
+//	@Description	Soft delete a user; the account can no longer sign in and can be restored. Requires the users:delete permission.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int													true	"User ID"
+//	@Success		200	{object}	response.SuccessResponse{data=map[string]string}	"User deleted"
+//	@Failure		400	{object}	response.ErrorResponse								"Invalid user ID"
+//	@Failure		401	{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse								"Permission denied or own account"
+//	@Failure		404	{object}	response.ErrorResponse								"User not found"
+//	@Router			/api/users/{id} [delete]
+func (c *UserController) DeleteUser(eCtx *echo.Context) error {
+	return c.userAction(eCtx, "delete", "User deleted", c.service.DeleteUser)
+}
+
+// RestoreUser godoc
+//
+//	@Summary		Restore a user
+//	@Description	Undo the soft delete of a user. Requires the users:delete permission.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int													true	"User ID"
+//	@Success		200	{object}	response.SuccessResponse{data=map[string]string}	"User restored"
+//	@Failure		400	{object}	response.ErrorResponse								"Invalid user ID"
+//	@Failure		401	{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse								"Permission denied"
+//	@Failure		404	{object}	response.ErrorResponse								"User not found"
+//	@Router			/api/users/{id}/restore [post]
+func (c *UserController) RestoreUser(eCtx *echo.Context) error {
+	return c.userAction(eCtx, "restore", "User restored", c.service.RestoreUser)
+}
+
+// PurgeUser godoc
+//
+//	@Summary		Permanently delete a user
+//	@Description	Remove a user and its sessions, identities, MFA settings and API keys. This cannot be undone. Requires the users:manage permission.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int													true	"User ID"
+//	@Success		200	{object}	response.SuccessResponse{data=map[string]string}	"User permanently deleted"
+//	@Failure		400	{object}	response.ErrorResponse								"Invalid user ID"
+//	@Failure		401	{object}	response.ErrorResponse								"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse								"Permission denied or own account"
+//	@Failure		404	{object}	response.ErrorResponse								"User not found"
+//	@Router			/api/users/{id}/purge [delete]
+func (c *UserController) PurgeUser(eCtx *echo.Context) error {
+	return c.userAction(eCtx, "purge", "User permanently deleted", c.service.PurgeUser)
+}
+
+// UploadAvatar godoc
+//
+//	@Summary		Upload a user avatar
+//	@Description	Replace the profile picture of a user with a JPEG, PNG, GIF or WebP image sent as the multipart field "avatar". The type is detected from the file content. Users can always change their own avatar; others require users:edit.
+//	@Tags			Users
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		int														true	"User ID"
+//	@Param			avatar	formData	file													true	"Image file"
+//	@Success		200		{object}	response.SuccessResponse{data=userDto.UserGetResponse}	"Avatar updated"
+//	@Failure		400		{object}	response.ErrorResponse									"Invalid user ID or missing file"
+//	@Failure		401		{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403		{object}	response.ErrorResponse									"Permission denied"
+//	@Failure		404		{object}	response.ErrorResponse									"User not found"
+//	@Failure		413		{object}	response.ErrorResponse									"File too large"
+//	@Failure		415		{object}	response.ErrorResponse									"File is not a supported image"
+//	@Router			/api/users/{id}/avatar [put]
+func (c *UserController) UploadAvatar(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseUint(eCtx.Param("id"), 10, 64)
 	if err != nil {
-		return response.Error(eCtx, http.StatusBadRequest, err)
+		return response.Error(eCtx, http.StatusBadRequest,
+			echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID"))
 	}
 
-	var userModel model.User
-	err = dtoMapper.Map(&userModel, userCreateReq)
+	req := eCtx.Request()
+	req.Body = http.MaxBytesReader(eCtx.Response(), req.Body, maxAvatarRequestBytes)
+	file, err := eCtx.FormFile("avatar")
 	if err != nil {
-		return response.Error(eCtx, http.StatusInternalServerError, err)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return response.Error(eCtx, http.StatusRequestEntityTooLarge,
+				echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Upload too large"))
+		}
+		return response.Error(eCtx, http.StatusBadRequest,
+			echo.NewHTTPError(http.StatusBadRequest, "Missing avatar file"))
 	}
-	user, err := c.service.Create(eCtx.Request().Context(), userModel)
+
+	user, err := c.service.UploadAvatar(req.Context(), *authCtx, id, file)
 	if err != nil {
-		return response.Error(eCtx, http.StatusInternalServerError, err)
+		logger.Errorf("Failed to upload avatar of user %d: %v", id, err)
+		return err
 	}
 
-	return response.Created(eCtx, map[string]interface{}{"new_user_id": user})
+	return response.Success(eCtx, user)
 }
 
-func (c *UserController) UpdateUser(eCtx *echo.Context) error {
-	var userUpdateReq userDto.UserUpdateRequest
-	err := eCtx.Bind(&userUpdateReq)
-	if err != nil {
-		return response.Error(eCtx, http.StatusBadRequest, err)
+// DeleteAvatar godoc
+//
+//	@Summary		Delete a user avatar
+//	@Description	Remove the profile picture of a user. Users can always remove their own avatar; others require users:edit.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int														true	"User ID"
+//	@Success		200	{object}	response.SuccessResponse{data=userDto.UserGetResponse}	"Avatar removed"
+//	@Failure		400	{object}	response.ErrorResponse									"Invalid user ID"
+//	@Failure		401	{object}	response.ErrorResponse									"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse									"Permission denied"
+//	@Failure		404	{object}	response.ErrorResponse									"User not found"
+//	@Router			/api/users/{id}/avatar [delete]
+func (c *UserController) DeleteAvatar(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
 	}
 
-	var userModel model.User
-	err = dtoMapper.Map(&userModel, userUpdateReq)
+	id, err := strconv.ParseUint(eCtx.Param("id"), 10, 64)
 	if err != nil {
-		return response.Error(eCtx, http.StatusInternalServerError, err)
+		return response.Error(eCtx, http.StatusBadRequest,
+			echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID"))
 	}
-	user, err := c.service.Update(eCtx.Request().Context(), userModel)
+
+	user, err := c.service.DeleteAvatar(eCtx.Request().Context(), *authCtx, id)
 	if err != nil {
-		return response.Error(eCtx, http.StatusInternalServerError, err)
+		logger.Errorf("Failed to delete avatar of user %d: %v", id, err)
+		return err
 	}
 
 	return response.Success(eCtx, user)
 }
 
+// RequestDataExport godoc
+//
+//	@Summary		Request a GDPR data export
+//	@Description	Queue an archive of everything stored about a user: account, orders, notification history, role assignments and audit events. Poll the returned request for its download URL. Users can always export their own data; others require users:manage.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int															true	"User ID"
+//	@Success		202	{object}	response.SuccessResponse{data=userDto.DataRequestResponse}	"Export queued"
+//	@Failure		400	{object}	response.ErrorResponse										"Invalid user ID"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse										"Permission denied"
+//	@Failure		404	{object}	response.ErrorResponse										"User not found"
+//	@Failure		409	{object}	response.ErrorResponse										"An export is already in progress"
+//	@Router			/api/users/{id}/data-export [post]
+func (c *UserController) RequestDataExport(eCtx *echo.Context) error {
+	return c.dataRequestAction(eCtx, "export", c.service.RequestDataExport)
+}
+
+// RequestErasure godoc
+//
+//	@Summary		Request GDPR erasure
+//	@Description	Queue the erasure of a user's personal data. The account is closed; orders and audit events are kept with the customer details pseudonymised, and sessions, credentials, push devices and role assignments are deleted. This cannot be undone. Users can always erase their own data; others require users:manage.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int															true	"User ID"
+//	@Success		202	{object}	response.SuccessResponse{data=userDto.DataRequestResponse}	"Erasure queued"
+//	@Failure		400	{object}	response.ErrorResponse										"Invalid user ID"
+//	@Failure		401	{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse										"Permission denied"
+//	@Failure		404	{object}	response.ErrorResponse										"User not found"
+//	@Failure		409	{object}	response.ErrorResponse										"An erasure is already in progress"
+//	@Router			/api/users/{id}/erasure [post]
+func (c *UserController) RequestErasure(eCtx *echo.Context) error {
+	return c.dataRequestAction(eCtx, "erasure", c.service.RequestErasure)
+}
+
+// ListDataRequests godoc
+//
+//	@Summary		List GDPR data requests
+//	@Description	List the export and erasure requests about a user, newest first. Users can always see their own; others require users:manage.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		int																true	"User ID"
+//	@Success		200	{object}	response.SuccessResponse{data=[]userDto.DataRequestResponse}	"Data requests"
+//	@Failure		400	{object}	response.ErrorResponse											"Invalid user ID"
+//	@Failure		401	{object}	response.ErrorResponse											"Unauthorized - invalid or missing token"
+//	@Failure		403	{object}	response.ErrorResponse											"Permission denied"
+//	@Router			/api/users/{id}/data-requests [get]
+func (c *UserController) ListDataRequests(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseUint(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest,
+			echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID"))
+	}
+
+	requests, err := c.service.ListDataRequests(eCtx.Request().Context(), *authCtx, id)
+	if err != nil {
+		logger.Errorf("Failed to list data requests of user %d: %v", id, err)
+		return err
+	}
+
+	return response.Success(eCtx, requests)
+}
+
+// GetDataRequest godoc
+//
+//	@Summary		Get a GDPR data request
+//	@Description	Get the status of an export or erasure request. Completed exports include a temporary download URL of the archive.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id			path		int															true	"User ID"
+//	@Param			requestId	path		int															true	"Data request ID"
+//	@Success		200			{object}	response.SuccessResponse{data=userDto.DataRequestResponse}	"Data request"
+//	@Failure		400			{object}	response.ErrorResponse										"Invalid user or request ID"
+//	@Failure		401			{object}	response.ErrorResponse										"Unauthorized - invalid or missing token"
+//	@Failure		403			{object}	response.ErrorResponse										"Permission denied"
+//	@Failure		404			{object}	response.ErrorResponse										"Data request not found"
+//	@Router			/api/users/{id}/data-requests/{requestId} [get]
+func (c *UserController) GetDataRequest(eCtx *echo.Context) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseUint(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest,
+			echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID"))
+	}
+	requestID, err := strconv.ParseInt(eCtx.Param("requestId"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest,
+			echo.NewHTTPError(http.StatusBadRequest, "Invalid request ID"))
+	}
+
+	request, err := c.service.GetDataRequest(eCtx.Request().Context(), *authCtx, id, requestID)
+	if err != nil {
+		logger.Errorf("Failed to get data request %d of user %d: %v", requestID, id, err)
+		return err
+	}
+
+	return response.Success(eCtx, request)
+}
+
+// dataRequestAction queues a data request about the user in the :id path parameter
+func (c *UserController) dataRequestAction(eCtx *echo.Context, kind string,
+	request func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*userDto.DataRequestResponse, error)) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseUint(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest,
+			echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID"))
+	}
+
+	queued, err := request(eCtx.Request().Context(), *authCtx, id)
+	if err != nil {
+		logger.Errorf("Failed to request %s of user %d: %v", kind, id, err)
+		return err
+	}
+
+	return response.Accepted(eCtx, queued)
+}
+
+// userAction runs one of the delete, restore and purge actions on the user in the path
+func (c *UserController) userAction(eCtx *echo.Context, verb, message string,
+	action func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error) error {
+	authCtx, ok := eCtx.Get("auth").(*authenticator.AuthContext)
+	if !ok {
+		return response.Error(eCtx, http.StatusUnauthorized,
+			echo.NewHTTPError(http.StatusUnauthorized, "unauthorized"))
+	}
+
+	id, err := strconv.ParseUint(eCtx.Param("id"), 10, 64)
+	if err != nil {
+		return response.Error(eCtx, http.StatusBadRequest,
+			echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID"))
+	}
+
+	if err := action(eCtx.Request().Context(), *authCtx, id); err != nil {
+		logger.Errorf("Failed to %s user %d: %v", verb, id, err)
+		return err
+	}
+
+	return response.Success(eCtx, map[string]string{
+		"message": message,
+	})
+}
+
 func (c *UserController) GetUserPage(eCtx *echo.Context) error {
 	return eCtx.HTML(http.StatusOK, "<h1>This is User Page</h1>")
 }
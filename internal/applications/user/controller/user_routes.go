@@ -12,9 +12,25 @@ var Domain = "users"
 func (c *UserController) httpRoutes(serviceName string, e *echo.Echo, auth *authenticator.Authenticator) {
 	group := e.Group("/" + serviceName + "/api/" + Domain)
 	group.Use(middlewares.RequestInjector(middlewares.RequestFields{Domain: Domain}))
-	group.GET("/:id", c.GetUser)
-	group.PUT("/:id", c.UpdateUser)
 	group.GET("/pokemon/:name", c.GetPokemon)
+
+	// Protected routes; permissions and ownership are checked by the service
+	protected := e.Group("/" + serviceName + "/api/" + Domain)
+	protected.Use(middlewares.RequestInjector(middlewares.RequestFields{Domain: Domain}))
+	protected.Use(auth.AuthenticateMiddleware())
+	protected.GET("", c.ListUsers)
+	protected.POST("", c.CreateUser)
+	protected.GET("/:id", c.GetUser)
+	protected.PUT("/:id", c.UpdateUser)
+	protected.DELETE("/:id", c.DeleteUser)
+	protected.POST("/:id/restore", c.RestoreUser)
+	protected.DELETE("/:id/purge", c.PurgeUser)
+	protected.PUT("/:id/avatar", c.UploadAvatar)
+	protected.DELETE("/:id/avatar", c.DeleteAvatar)
+	protected.POST("/:id/data-export", c.RequestDataExport)
+	protected.POST("/:id/erasure", c.RequestErasure)
+	protected.GET("/:id/data-requests", c.ListDataRequests)
+	protected.GET("/:id/data-requests/:requestId", c.GetDataRequest)
 }
 
 func (c *UserController) webRoutes(serviceName string, e *echo.Echo) {
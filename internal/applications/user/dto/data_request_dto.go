@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Routing keys of the data request jobs
+const (
+	DataExportRoutingKey  = "user.data_export"
+	DataErasureRoutingKey = "user.data_erasure"
+)
+
+// DataRequestMessage asks a queue worker to process one user_data_requests row
+type DataRequestMessage struct {
+	RequestID int64  `json:"request_id"`
+	UserID    uint64 `json:"user_id"`
+}
+
+// DataRequestResponse is the status of a GDPR export or erasure request
+type DataRequestResponse struct {
+	ID          int64        `json:"id"`
+	UserID      uint64       `json:"user_id"`
+	Type        string       `json:"type"`   // export or erasure
+	Status      string       `json:"status"` // pending, processing, completed or failed
+	Error       string       `json:"error,omitempty"`
+	DownloadURL string       `json:"download_url,omitempty"` // completed exports only; temporary, request the status again for a fresh one
+	CreatedAt   time.Time    `json:"created_at"`
+	StartedAt   bun.NullTime `json:"started_at,omitzero"`
+	CompletedAt bun.NullTime `json:"completed_at,omitzero"`
+}
@@ -1,13 +1,15 @@
 package dto
 
+import "time"
+
 type UserGetRequest struct {
 	ID string `param:"id"`
 }
 
 type UserCreateRequest struct {
-	Name     string `json:"name" validate:"required"`
+	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required,strong_password"`
 }
 type UserUpdateRequest struct {
 	ID    uint32 `param:"id" validate:"required"`
@@ -18,3 +20,17 @@ type UserUpdateRequest struct {
 type UserDeleteRequest struct {
 	ID uint32 `param:"id" validate:"required"`
 }
+
+// UserListRequest filters, sorts and pages the user list
+type UserListRequest struct {
+	Email         string    `query:"email"`
+	Name          string    `query:"name"`
+	CreatedFrom   time.Time `query:"created_from"` // RFC 3339, inclusive
+	CreatedTo     time.Time `query:"created_to"`   // RFC 3339, exclusive
+	Status        string    `query:"status" validate:"omitempty,oneof=active deleted all"`
+	EmailVerified string    `query:"email_verified" validate:"omitempty,oneof=true false"`
+	SortBy        string    `query:"sort_by" validate:"omitempty,oneof=id name email created_at updated_at"`
+	SortDir       string    `query:"sort_dir" validate:"omitempty,oneof=asc desc"`
+	Page          int       `query:"page" validate:"omitempty,min=1"`
+	PageSize      int       `query:"page_size" validate:"omitempty,min=1,max=100"`
+}
@@ -7,9 +7,21 @@ import (
 )
 
 type UserGetResponse struct {
-	ID        int64        `json:"id"`
-	Name      string       `json:"name"`
-	Email     string       `json:"email"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt bun.NullTime `json:"updated_at"`
+	ID              int64        `json:"id"`
+	Name            string       `json:"name"`
+	Email           string       `json:"email"`
+	EmailVerifiedAt bun.NullTime `json:"email_verified_at"`
+	AvatarURL       string       `json:"avatar_url,omitempty"` // temporary, request the user again for a fresh one
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       bun.NullTime `json:"updated_at"`
+	DeletedAt       bun.NullTime `json:"deleted_at,omitzero"`
+}
+
+// UserListResponse is one page of users
+type UserListResponse struct {
+	Users      []UserGetResponse `json:"users"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalPages int               `json:"total_pages"`
+	TotalCount int               `json:"total_count"`
 }
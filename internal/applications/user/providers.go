@@ -1,13 +1,18 @@
 package user
 
 import (
+	"context"
 	"fmt"
 
 	"ichi-go/config"
+	authService "ichi-go/internal/applications/auth/service"
+	rbacServices "ichi-go/internal/applications/rbac/services"
 	user "ichi-go/internal/applications/user/controller"
 	userRepo "ichi-go/internal/applications/user/repository"
 	userService "ichi-go/internal/applications/user/service"
 	"ichi-go/pkg/clients/pokemonapi"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/storage"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/samber/do/v2"
@@ -56,8 +61,8 @@ func ProvideUserService(i do.Injector) (*userService.ServiceImpl, error) {
 	if err != nil {
 		return nil, fmt.Errorf("user: failed to resolve rabbitmq connection: %w", err)
 	}
+	cfg := do.MustInvoke[*config.Config](i)
 	if conn != nil {
-		cfg := do.MustInvoke[*config.Config](i)
 		if amqpCfg, ok := cfg.Queue().DefaultAMQPConfig(); ok {
 			p, err := rabbitmq.NewProducer(conn, amqpCfg)
 			if err != nil {
@@ -67,7 +72,45 @@ func ProvideUserService(i do.Injector) (*userService.ServiceImpl, error) {
 		}
 	}
 
-	return userService.NewUserService(repo, cacheImpl, pokeClient, producer), nil
+	// Permission checks go through RBAC enforcement; without it users can only manage themselves.
+	var permissions userService.PermissionChecker
+	if enforcement, err := do.Invoke[*rbacServices.EnforcementService](i); err == nil {
+		permissions = enforcement
+	} else {
+		logger.Warnf("⚠️  RBAC enforcement not available, user administration limited to own account: %v", err)
+	}
+
+	// Avatars need file storage; without it uploads are rejected.
+	disk, err := do.Invoke[storage.Disk](i)
+	if err != nil || disk == nil {
+		logger.Warnf("⚠️  File storage not available, avatar uploads disabled: %v", err)
+	}
+
+	db := do.MustInvoke[*bun.DB](i)
+
+	return userService.NewUserService(repo, cacheImpl, pokeClient, producer, userService.Options{
+		Permissions:   permissions,
+		DefaultTenant: cfg.RBAC().DefaultTenant,
+		Storage:       disk,
+		AvatarRules:   cfg.Storage().Avatars,
+		URLTTL:        cfg.Storage().URLTTL,
+		DataRequests:  userRepo.NewDataRequestRepository(db),
+		Sessions:      sessionRevoker{injector: i},
+	}), nil
+}
+
+// sessionRevoker signs users out through the auth service. It is resolved on first
+// use because the auth domain registers after the user domain.
+type sessionRevoker struct {
+	injector do.Injector
+}
+
+func (r sessionRevoker) SignOutDeletedUser(ctx context.Context, userID uint64) error {
+	svc, err := do.Invoke[*authService.ServiceImpl](r.injector)
+	if err != nil {
+		return err
+	}
+	return svc.SignOutDeletedUser(ctx, userID)
 }
 
 func ProvideUserController(i do.Injector) (*user.UserController, error) {
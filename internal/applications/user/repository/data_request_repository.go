@@ -0,0 +1,38 @@
+package user
+
+import (
+	"context"
+	"ichi-go/pkg/db/model"
+)
+
+// PersonalData is everything stored about one user, as rows keyed by table name
+type PersonalData map[string][]map[string]any
+
+// ErasureResult lists the files that referenced an erased user and must be removed from storage
+type ErasureResult struct {
+	AvatarPath  string
+	ExportPaths []string
+}
+
+type DataRequestRepository interface {
+	Create(ctx context.Context, req *model.UserDataRequest) error
+	// FindByID returns nil, nil when no request exists.
+	FindByID(ctx context.Context, id int64) (*model.UserDataRequest, error)
+	// ListByUser returns the requests about a user, newest first.
+	ListByUser(ctx context.Context, userID uint64) ([]*model.UserDataRequest, error)
+	// FindActive returns the pending or processing request of requestType about a user, or nil, nil.
+	FindActive(ctx context.Context, userID uint64, requestType string) (*model.UserDataRequest, error)
+	MarkProcessing(ctx context.Context, id int64) error
+	MarkCompleted(ctx context.Context, id int64, filePath string) error
+	MarkFailed(ctx context.Context, id int64, reason string) error
+	// CollectPersonalData reads the rows about a user from users, orders, order_items,
+	// notification_logs, user_devices, in_app_notifications, notification_preferences,
+	// notification_opt_outs, user_attributes, rbac_user_roles and rbac_audit_log.
+	// Password hashes are left out.
+	CollectPersonalData(ctx context.Context, userID uint64) (PersonalData, error)
+	// Erase removes the personal data of a user in one transaction. Rows other records
+	// depend on (the user, orders, audit events) are kept with their PII pseudonymised;
+	// sessions, credentials, push devices, the notification inbox and settings, custom
+	// attributes and role assignments are deleted. Erasing twice is harmless.
+	Erase(ctx context.Context, userID uint64) (*ErasureResult, error)
+}
@@ -0,0 +1,328 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/db/repository"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/rbac"
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+type DataRequestRepositoryImpl struct {
+	*repository.BaseRepository[model.UserDataRequest]
+}
+
+func NewDataRequestRepository(dbConnection *upbun.DB) *DataRequestRepositoryImpl {
+	return &DataRequestRepositoryImpl{BaseRepository: repository.NewRepository[model.UserDataRequest](dbConnection, &model.UserDataRequest{})}
+}
+
+func (r *DataRequestRepositoryImpl) Create(ctx context.Context, req *model.UserDataRequest) error {
+	if _, err := r.DB().NewInsert().Model(req).Returning("id").Exec(ctx); err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "create_data_request").
+			With("user_id", req.UserID).
+			Wrap(err)
+	}
+	return nil
+}
+
+func (r *DataRequestRepositoryImpl) FindByID(ctx context.Context, id int64) (*model.UserDataRequest, error) {
+	req := new(model.UserDataRequest)
+	err := r.DB().NewSelect().Model(req).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_data_request").
+			With("request_id", id).
+			Wrap(err)
+	}
+	return req, nil
+}
+
+func (r *DataRequestRepositoryImpl) ListByUser(ctx context.Context, userID uint64) ([]*model.UserDataRequest, error) {
+	var reqs []*model.UserDataRequest
+	err := r.DB().NewSelect().Model(&reqs).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "list_data_requests").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return reqs, nil
+}
+
+func (r *DataRequestRepositoryImpl) FindActive(ctx context.Context, userID uint64, requestType string) (*model.UserDataRequest, error) {
+	req := new(model.UserDataRequest)
+	err := r.DB().NewSelect().Model(req).
+		Where("user_id = ?", userID).
+		Where("type = ?", requestType).
+		Where("status IN (?)", upbun.In([]string{model.DataRequestPending, model.DataRequestProcessing})).
+		Order("id DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_data_request").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return req, nil
+}
+
+func (r *DataRequestRepositoryImpl) MarkProcessing(ctx context.Context, id int64) error {
+	now := time.Now()
+	return r.setStatus(ctx, id, "start_data_request", func(q *upbun.UpdateQuery) {
+		q.Set("status = ?", model.DataRequestProcessing).
+			Set("error = NULL").
+			Set("started_at = ?", now)
+	})
+}
+
+func (r *DataRequestRepositoryImpl) MarkCompleted(ctx context.Context, id int64, filePath string) error {
+	var path any
+	if filePath != "" {
+		path = filePath
+	}
+	return r.setStatus(ctx, id, "complete_data_request", func(q *upbun.UpdateQuery) {
+		q.Set("status = ?", model.DataRequestCompleted).
+			Set("file_path = ?", path).
+			Set("error = NULL").
+			Set("completed_at = ?", time.Now())
+	})
+}
+
+func (r *DataRequestRepositoryImpl) MarkFailed(ctx context.Context, id int64, reason string) error {
+	return r.setStatus(ctx, id, "fail_data_request", func(q *upbun.UpdateQuery) {
+		q.Set("status = ?", model.DataRequestFailed).
+			Set("error = ?", reason)
+	})
+}
+
+func (r *DataRequestRepositoryImpl) setStatus(ctx context.Context, id int64, operation string, set func(*upbun.UpdateQuery)) error {
+	q := r.DB().NewUpdate().
+		TableExpr("user_data_requests").
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id)
+	set(q)
+	if _, err := q.Exec(ctx); err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", operation).
+			With("request_id", id).
+			Wrap(err)
+	}
+	return nil
+}
+
+// exportedUserColumns are the users columns included in an export; the password hash is not personal data of use to the subject
+var exportedUserColumns = []string{"id", "name", "email", "email_verified_at", "avatar_path", "created_at", "updated_at", "deleted_at"}
+
+func (r *DataRequestRepositoryImpl) CollectPersonalData(ctx context.Context, userID uint64) (PersonalData, error) {
+	db := r.DB()
+	data := PersonalData{}
+	subject := fmt.Sprint(userID)
+
+	users, err := scanRows(ctx, db.NewSelect().TableExpr("users").Column(exportedUserColumns...).Where("id = ?", userID))
+	if err != nil {
+		return nil, collectError("users", userID, err)
+	}
+	if len(users) == 0 {
+		return nil, collectError("users", userID, sql.ErrNoRows)
+	}
+	data["users"] = users
+	emailHash := rbac.HashEmail(fmt.Sprint(users[0]["email"]))
+
+	queries := []struct {
+		table string
+		query *upbun.SelectQuery
+	}{
+		{"orders", db.NewSelect().TableExpr("orders").
+			Where("user_id = ?", userID).
+			Order("id")},
+		{"order_items", db.NewSelect().TableExpr("order_items").
+			Where("order_id IN (?)", db.NewSelect().TableExpr("orders").Column("id").Where("user_id = ?", userID)).
+			Order("id")},
+		{"notification_logs", db.NewSelect().TableExpr("notification_logs").
+			Where("user_id = ?", userID).
+			Order("id")},
+		{"user_devices", db.NewSelect().TableExpr("user_devices").
+			Where("user_id = ?", userID).
+			Order("id")},
+		{"in_app_notifications", db.NewSelect().TableExpr("in_app_notifications").
+			Where("user_id = ?", userID).
+			Order("id")},
+		{"notification_preferences", db.NewSelect().TableExpr("notification_preferences").
+			Where("user_id = ?", userID)},
+		{"notification_opt_outs", db.NewSelect().TableExpr("notification_opt_outs").
+			Where("user_id = ?", userID).
+			Order("id")},
+		{"user_attributes", db.NewSelect().TableExpr("user_attributes").
+			Where("user_id = ?", userID).
+			Order("id")},
+		{"rbac_user_roles", db.NewSelect().TableExpr("rbac_user_roles AS rur").
+			ColumnExpr("rur.*").
+			ColumnExpr("r.slug AS role_slug, r.name AS role_name").
+			Join("LEFT JOIN rbac_roles AS r ON r.id = rur.role_id").
+			Where("rur.user_id = ?", userID).
+			Order("rur.id")},
+		{"rbac_audit_log", db.NewSelect().TableExpr("rbac_audit_log").
+			WhereOr("actor_id = ?", subject).
+			WhereOr("subject_id = ?", subject).
+			WhereOr("actor_email_hash = ?", emailHash).
+			WhereOr("subject_email_hash = ?", emailHash).
+			Order("id")},
+	}
+	for _, q := range queries {
+		rows, err := scanRows(ctx, q.query)
+		if err != nil {
+			return nil, collectError(q.table, userID, err)
+		}
+		data[q.table] = rows
+	}
+	return data, nil
+}
+
+// scanRows reads any table into maps. MySQL returns text columns as []byte, which
+// would otherwise be exported base64 encoded.
+func scanRows(ctx context.Context, q *upbun.SelectQuery) ([]map[string]any, error) {
+	rows := []map[string]any{}
+	if err := q.Scan(ctx, &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for k, v := range row {
+			if b, ok := v.([]byte); ok {
+				row[k] = string(b)
+			}
+		}
+	}
+	return rows, nil
+}
+
+func collectError(table string, userID uint64, err error) error {
+	return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+		With("operation", "collect_personal_data").
+		With("table", table).
+		With("user_id", userID).
+		Wrap(err)
+}
+
+// ErasedName is the name an erased user is renamed to; names are unique, so it contains the id
+func ErasedName(userID uint64) string {
+	return fmt.Sprintf("erased-user-%d", userID)
+}
+
+func (r *DataRequestRepositoryImpl) Erase(ctx context.Context, userID uint64) (*ErasureResult, error) {
+	result := &ErasureResult{}
+	subject := fmt.Sprint(userID)
+
+	err := r.DB().RunInTx(ctx, nil, func(ctx context.Context, tx upbun.Tx) error {
+		var found struct {
+			Name       string         `bun:"name"`
+			Email      string         `bun:"email"`
+			AvatarPath sql.NullString `bun:"avatar_path"`
+		}
+		if err := tx.NewSelect().TableExpr("users").
+			Column("name", "email", "avatar_path").
+			Where("id = ?", userID).
+			Scan(ctx, &found); err != nil {
+			return err
+		}
+		result.AvatarPath = found.AvatarPath.String
+
+		// Emails become the hash the audit log stores, so orders and audit events of
+		// the same person stay linkable without the address. A repeated erasure finds
+		// the hash already in place.
+		emailHash := found.Email
+		if found.Name != ErasedName(userID) {
+			emailHash = rbac.HashEmail(found.Email)
+		}
+		now := time.Now()
+
+		if _, err := tx.NewUpdate().TableExpr("users").
+			Set("name = ?", ErasedName(userID)).
+			Set("email = ?", emailHash).
+			Set("password = ''").
+			Set("avatar_path = NULL").
+			Set("email_verified_at = NULL").
+			Set("updated_at = ?", now).
+			Set("deleted_at = COALESCE(deleted_at, ?)", now).
+			Where("id = ?", userID).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		// Orders are financial records that must be kept; only the customer details go
+		if _, err := tx.NewUpdate().TableExpr("orders").
+			Set("user_name = 'Erased user'").
+			Set("email = ?", emailHash).
+			Set("phone = NULL").
+			Set("shipping_name = NULL").
+			Set("shipping_phone = NULL").
+			Set("shipping_address_line = NULL").
+			Set("shipping_postal_code = NULL").
+			Set("notes = NULL").
+			Where("user_id = ?", userID).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		// Delivery errors can quote the recipient address
+		if _, err := tx.NewUpdate().TableExpr("notification_logs").
+			Set("error = NULL").
+			Where("user_id = ?", userID).
+			Where("error IS NOT NULL").
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		// Audit events stay for integrity; they only ever hold email hashes, but the
+		// client details of the user's own actions are personal data
+		if _, err := tx.NewUpdate().TableExpr("rbac_audit_log").
+			Set("ip_address = NULL").
+			Set("user_agent = NULL").
+			Where("actor_id = ?", subject).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		if err := deleteUserOwnedRows(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		// Earlier exports are full copies of the erased data
+		if err := tx.NewSelect().TableExpr("user_data_requests").
+			Column("file_path").
+			Where("user_id = ?", userID).
+			Where("file_path IS NOT NULL").
+			Scan(ctx, &result.ExportPaths); err != nil {
+			return err
+		}
+		_, err := tx.NewUpdate().TableExpr("user_data_requests").
+			Set("file_path = NULL").
+			Where("user_id = ?", userID).
+			Where("file_path IS NOT NULL").
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "erase_user").
+			With("user_id", userID).
+			Wrap(err)
+	}
+	return result, nil
+}
@@ -6,6 +6,7 @@ package user
 
 import (
 	"context"
+	user "ichi-go/internal/applications/user/repository"
 	dbModel "ichi-go/pkg/db/model"
 
 	mock "github.com/stretchr/testify/mock"
@@ -240,6 +241,454 @@ func (_c *MockRepository_GetById_Call) RunAndReturn(run func(ctx context.Context
 	return _c
 }
 
+// GetByIdWithDeleted provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetByIdWithDeleted(ctx context.Context, id uint64) (*dbModel.User, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIdWithDeleted")
+	}
+
+	var r0 *dbModel.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) (*dbModel.User, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) *dbModel.User); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dbModel.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetByIdWithDeleted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByIdWithDeleted'
+type MockRepository_GetByIdWithDeleted_Call struct {
+	*mock.Call
+}
+
+// GetByIdWithDeleted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint64
+func (_e *MockRepository_Expecter) GetByIdWithDeleted(ctx interface{}, id interface{}) *MockRepository_GetByIdWithDeleted_Call {
+	return &MockRepository_GetByIdWithDeleted_Call{Call: _e.mock.On("GetByIdWithDeleted", ctx, id)}
+}
+
+func (_c *MockRepository_GetByIdWithDeleted_Call) Run(run func(ctx context.Context, id uint64)) *MockRepository_GetByIdWithDeleted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetByIdWithDeleted_Call) Return(userModel *dbModel.User, err error) *MockRepository_GetByIdWithDeleted_Call {
+	_c.Call.Return(userModel, err)
+	return _c
+}
+
+func (_c *MockRepository_GetByIdWithDeleted_Call) RunAndReturn(run func(ctx context.Context, id uint64) (*dbModel.User, error)) *MockRepository_GetByIdWithDeleted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HardDelete provides a mock function for the type MockRepository
+func (_mock *MockRepository) HardDelete(ctx context.Context, id uint64) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HardDelete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_HardDelete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HardDelete'
+type MockRepository_HardDelete_Call struct {
+	*mock.Call
+}
+
+// HardDelete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint64
+func (_e *MockRepository_Expecter) HardDelete(ctx interface{}, id interface{}) *MockRepository_HardDelete_Call {
+	return &MockRepository_HardDelete_Call{Call: _e.mock.On("HardDelete", ctx, id)}
+}
+
+func (_c *MockRepository_HardDelete_Call) Run(run func(ctx context.Context, id uint64)) *MockRepository_HardDelete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_HardDelete_Call) Return(err error) *MockRepository_HardDelete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_HardDelete_Call) RunAndReturn(run func(ctx context.Context, id uint64) error) *MockRepository_HardDelete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type MockRepository
+func (_mock *MockRepository) List(ctx context.Context, filter user.ListFilter) ([]*dbModel.User, int, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*dbModel.User
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, user.ListFilter) ([]*dbModel.User, int, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, user.ListFilter) []*dbModel.User); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dbModel.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, user.ListFilter) int); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, user.ListFilter) error); ok {
+		r2 = returnFunc(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter user.ListFilter
+func (_e *MockRepository_Expecter) List(ctx interface{}, filter interface{}) *MockRepository_List_Call {
+	return &MockRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *MockRepository_List_Call) Run(run func(ctx context.Context, filter user.ListFilter)) *MockRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 user.ListFilter
+		if args[1] != nil {
+			arg1 = args[1].(user.ListFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_List_Call) Return(users []*dbModel.User, n int, err error) *MockRepository_List_Call {
+	_c.Call.Return(users, n, err)
+	return _c
+}
+
+func (_c *MockRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter user.ListFilter) ([]*dbModel.User, int, error)) *MockRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkEmailVerified provides a mock function for the type MockRepository
+func (_mock *MockRepository) MarkEmailVerified(ctx context.Context, id uint64, email string) (bool, error) {
+	ret := _mock.Called(ctx, id, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailVerified")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string) (bool, error)); ok {
+		return returnFunc(ctx, id, email)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string) bool); ok {
+		r0 = returnFunc(ctx, id, email)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, string) error); ok {
+		r1 = returnFunc(ctx, id, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_MarkEmailVerified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkEmailVerified'
+type MockRepository_MarkEmailVerified_Call struct {
+	*mock.Call
+}
+
+// MarkEmailVerified is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint64
+//   - email string
+func (_e *MockRepository_Expecter) MarkEmailVerified(ctx interface{}, id interface{}, email interface{}) *MockRepository_MarkEmailVerified_Call {
+	return &MockRepository_MarkEmailVerified_Call{Call: _e.mock.On("MarkEmailVerified", ctx, id, email)}
+}
+
+func (_c *MockRepository_MarkEmailVerified_Call) Run(run func(ctx context.Context, id uint64, email string)) *MockRepository_MarkEmailVerified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_MarkEmailVerified_Call) Return(b bool, err error) *MockRepository_MarkEmailVerified_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockRepository_MarkEmailVerified_Call) RunAndReturn(run func(ctx context.Context, id uint64, email string) (bool, error)) *MockRepository_MarkEmailVerified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function for the type MockRepository
+func (_mock *MockRepository) Restore(ctx context.Context, id int64) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type MockRepository_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockRepository_Expecter) Restore(ctx interface{}, id interface{}) *MockRepository_Restore_Call {
+	return &MockRepository_Restore_Call{Call: _e.mock.On("Restore", ctx, id)}
+}
+
+func (_c *MockRepository_Restore_Call) Run(run func(ctx context.Context, id int64)) *MockRepository_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_Restore_Call) Return(err error) *MockRepository_Restore_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_Restore_Call) RunAndReturn(run func(ctx context.Context, id int64) error) *MockRepository_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetAvatar provides a mock function for the type MockRepository
+func (_mock *MockRepository) SetAvatar(ctx context.Context, id uint64, path string) error {
+	ret := _mock.Called(ctx, id, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAvatar")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = returnFunc(ctx, id, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_SetAvatar_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAvatar'
+type MockRepository_SetAvatar_Call struct {
+	*mock.Call
+}
+
+// SetAvatar is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint64
+//   - path string
+func (_e *MockRepository_Expecter) SetAvatar(ctx interface{}, id interface{}, path interface{}) *MockRepository_SetAvatar_Call {
+	return &MockRepository_SetAvatar_Call{Call: _e.mock.On("SetAvatar", ctx, id, path)}
+}
+
+func (_c *MockRepository_SetAvatar_Call) Run(run func(ctx context.Context, id uint64, path string)) *MockRepository_SetAvatar_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_SetAvatar_Call) Return(err error) *MockRepository_SetAvatar_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_SetAvatar_Call) RunAndReturn(run func(ctx context.Context, id uint64, path string) error) *MockRepository_SetAvatar_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SoftDelete provides a mock function for the type MockRepository
+func (_mock *MockRepository) SoftDelete(ctx context.Context, id int64) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDelete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_SoftDelete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SoftDelete'
+type MockRepository_SoftDelete_Call struct {
+	*mock.Call
+}
+
+// SoftDelete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockRepository_Expecter) SoftDelete(ctx interface{}, id interface{}) *MockRepository_SoftDelete_Call {
+	return &MockRepository_SoftDelete_Call{Call: _e.mock.On("SoftDelete", ctx, id)}
+}
+
+func (_c *MockRepository_SoftDelete_Call) Run(run func(ctx context.Context, id int64)) *MockRepository_SoftDelete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_SoftDelete_Call) Return(err error) *MockRepository_SoftDelete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_SoftDelete_Call) RunAndReturn(run func(ctx context.Context, id int64) error) *MockRepository_SoftDelete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Update provides a mock function for the type MockRepository
 func (_mock *MockRepository) Update(ctx context.Context, updateUser dbModel.User) (int64, error) {
 	ret := _mock.Called(ctx, updateUser)
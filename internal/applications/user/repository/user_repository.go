@@ -3,11 +3,46 @@ package user
 import (
 	"context"
 	"ichi-go/pkg/db/model"
+	"time"
 )
 
+// User statuses selectable in ListFilter
+const (
+	StatusActive  = "active"
+	StatusDeleted = "deleted"
+	StatusAll     = "all"
+)
+
+// ListFilter selects and orders one page of users
+type ListFilter struct {
+	Email         string // substring of the email address
+	Name          string // substring of the name
+	CreatedFrom   time.Time
+	CreatedTo     time.Time
+	Status        string // StatusActive (default), StatusDeleted or StatusAll
+	EmailVerified *bool
+	SortBy        string // id (default), name, email, created_at or updated_at
+	SortDesc      bool
+	Page          int
+	PerPage       int
+}
+
 type Repository interface {
 	GetById(ctx context.Context, id uint64) (*model.User, error)
 	Create(ctx context.Context, newUser model.User) (int64, error)
 	Update(ctx context.Context, updateUser model.User) (int64, error)
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	// MarkEmailVerified sets email_verified_at when email is still the user's unverified address.
+	// It reports false when the address changed or was already verified.
+	MarkEmailVerified(ctx context.Context, id uint64, email string) (bool, error)
+	// SetAvatar stores the storage path of the user's avatar; an empty path removes it.
+	SetAvatar(ctx context.Context, id uint64, path string) error
+	// List returns one page of users matching filter and the total number of matches.
+	List(ctx context.Context, filter ListFilter) ([]*model.User, int, error)
+	// GetByIdWithDeleted also finds soft-deleted users.
+	GetByIdWithDeleted(ctx context.Context, id uint64) (*model.User, error)
+	SoftDelete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) error
+	// HardDelete removes the user, deleted or not, together with its sessions and credentials.
+	HardDelete(ctx context.Context, id uint64) error
 }
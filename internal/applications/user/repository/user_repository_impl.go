@@ -2,10 +2,14 @@ package user
 
 import (
 	"context"
+	"fmt"
 	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/db/query"
 	"ichi-go/pkg/db/repository"
 	pkgErrors "ichi-go/pkg/errors"
 	"ichi-go/pkg/logger"
+	"strings"
+	"time"
 
 	upbun "github.com/uptrace/bun"
 )
@@ -65,6 +69,18 @@ func (r *RepositoryImpl) Create(ctx context.Context, newUser model.User) (int64,
 }
 
 func (r *RepositoryImpl) Update(ctx context.Context, updateUser model.User) (int64, error) {
+	// A changed email address has to be verified again.
+	if updateUser.Email != "" {
+		if _, err := r.DB().NewUpdate().
+			TableExpr("users").
+			Set("email_verified_at = NULL").
+			Where("id = ?", updateUser.ID).
+			Where("email <> ?", updateUser.Email).
+			Exec(ctx); err != nil {
+			logger.Errorf("Error resetting email verification of user %d, err: %+v", updateUser.ID, err)
+			return 0, err
+		}
+	}
 	data, err := r.DB().NewUpdate().
 		Model(&updateUser).
 		Where("id = ?", updateUser.ID).
@@ -86,4 +102,200 @@ func (r *RepositoryImpl) Update(ctx context.Context, updateUser model.User) (int
 	}
 	logger.Debugf("User updated with result: %+v", data)
 	return updateUser.ID, nil
-}
\ No newline at end of file
+}
+
+func (r *RepositoryImpl) MarkEmailVerified(ctx context.Context, id uint64, email string) (bool, error) {
+	now := time.Now()
+	res, err := r.DB().NewUpdate().
+		TableExpr("users").
+		Set("email_verified_at = ?", now).
+		Set("updated_at = ?", now).
+		Where("id = ?", id).
+		Where("email = ?", email).
+		Where("email_verified_at IS NULL").
+		Where("deleted_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "mark_email_verified").
+			With("user_id", id).
+			Wrap(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "mark_email_verified").
+			With("user_id", id).
+			Wrap(err)
+	}
+	return n == 1, nil
+}
+
+func (r *RepositoryImpl) SetAvatar(ctx context.Context, id uint64, path string) error {
+	var avatar any
+	if path != "" {
+		avatar = path
+	}
+	_, err := r.DB().NewUpdate().
+		TableExpr("users").
+		Set("avatar_path = ?", avatar).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("deleted_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "set_avatar").
+			With("user_id", id).
+			Wrap(err)
+	}
+	return nil
+}
+
+// sortColumns maps the sortable fields of ListFilter to their columns
+var sortColumns = map[string]string{
+	"id":         "u.id",
+	"name":       "u.name",
+	"email":      "u.email",
+	"created_at": "u.created_at",
+	"updated_at": "u.updated_at",
+}
+
+func (r *RepositoryImpl) List(ctx context.Context, filter ListFilter) ([]*model.User, int, error) {
+	scopes := []query.QueryScope{
+		func(q *upbun.SelectQuery) *upbun.SelectQuery {
+			switch filter.Status {
+			case StatusDeleted:
+				q = q.WhereDeleted()
+			case StatusAll:
+				q = q.WhereAllWithDeleted()
+			}
+			if filter.Email != "" {
+				q = q.Where("u.email LIKE ?", "%"+escapeLike(filter.Email)+"%")
+			}
+			if filter.Name != "" {
+				q = q.Where("u.name LIKE ?", "%"+escapeLike(filter.Name)+"%")
+			}
+			if !filter.CreatedFrom.IsZero() {
+				q = q.Where("u.created_at >= ?", filter.CreatedFrom)
+			}
+			if !filter.CreatedTo.IsZero() {
+				q = q.Where("u.created_at < ?", filter.CreatedTo)
+			}
+			if filter.EmailVerified != nil {
+				if *filter.EmailVerified {
+					q = q.Where("u.email_verified_at IS NOT NULL")
+				} else {
+					q = q.Where("u.email_verified_at IS NULL")
+				}
+			}
+			return q
+		},
+	}
+
+	column, ok := sortColumns[filter.SortBy]
+	if !ok {
+		column = sortColumns["id"]
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+	scopes = append(scopes, func(q *upbun.SelectQuery) *upbun.SelectQuery {
+		return q.OrderExpr("? "+direction, upbun.Safe(column))
+	})
+
+	users, total, err := r.PaginateWithCount(ctx, filter.Page, filter.PerPage, scopes...)
+	if err != nil {
+		return nil, 0, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "list_users").
+			Wrap(err)
+	}
+	return users, total, nil
+}
+
+func (r *RepositoryImpl) GetByIdWithDeleted(ctx context.Context, id uint64) (*model.User, error) {
+	user := new(model.User)
+	err := r.DB().NewSelect().
+		Model(user).
+		WhereAllWithDeleted().
+		Where("u.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "get_user_with_deleted").
+			With("user_id", id).
+			Wrap(err)
+	}
+	return user, nil
+}
+
+// userOwnedTables hold the sessions, credentials, devices, notification inbox,
+// notification settings and custom attributes of a user, removed on HardDelete and Erase
+var userOwnedTables = []struct{ table, column string }{
+	{"user_sessions", "user_id"},
+	{"password_resets", "user_id"},
+	{"user_identities", "user_id"},
+	{"user_mfa_recovery_codes", "user_id"},
+	{"user_mfa", "user_id"},
+	{"api_keys", "owner_user_id"},
+	{"user_devices", "user_id"},
+	{"in_app_notifications", "user_id"},
+	{"notification_preferences", "user_id"},
+	{"notification_opt_outs", "user_id"},
+	{"user_attributes", "user_id"},
+}
+
+// deleteUserOwnedRows deletes the rows that only exist for a user: userOwnedTables and
+// the role assignments, including the Casbin grouping rules the enforcer loads.
+// HardDelete and Erase share it so both leave nothing of the user behind.
+func deleteUserOwnedRows(ctx context.Context, tx upbun.Tx, userID uint64) error {
+	if _, err := tx.NewDelete().TableExpr("rbac_user_roles").
+		Where("user_id = ?", userID).
+		Exec(ctx); err != nil {
+		return err
+	}
+	if _, err := tx.NewDelete().TableExpr("casbin_rule").
+		Where("ptype = 'g'").
+		Where("v0 = ?", fmt.Sprintf("user:%d", userID)).
+		Exec(ctx); err != nil {
+		return err
+	}
+	for _, owned := range userOwnedTables {
+		if _, err := tx.NewDelete().
+			TableExpr(owned.table).
+			Where("? = ?", upbun.Ident(owned.column), userID).
+			Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RepositoryImpl) HardDelete(ctx context.Context, id uint64) error {
+	err := r.DB().RunInTx(ctx, nil, func(ctx context.Context, tx upbun.Tx) error {
+		if err := deleteUserOwnedRows(ctx, tx, id); err != nil {
+			return err
+		}
+		_, err := tx.NewDelete().
+			Model((*model.User)(nil)).
+			Where("id = ?", id).
+			ForceDelete().
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return pkgErrors.Database(pkgErrors.ErrCodeDatabase).
+			With("operation", "hard_delete_user").
+			With("user_id", id).
+			Wrap(err)
+	}
+	return nil
+}
+
+// escapeLike escapes the LIKE wildcards in a user supplied search term
+func escapeLike(term string) string {
+	return likeEscaper.Replace(term)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
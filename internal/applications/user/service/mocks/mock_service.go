@@ -6,7 +6,10 @@ package user
 
 import (
 	"context"
+	"ichi-go/internal/applications/user/dto"
+	"ichi-go/pkg/authenticator"
 	dbModel "ichi-go/pkg/db/model"
+	"mime/multipart"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -104,70 +107,857 @@ func (_c *MockService_Create_Call) RunAndReturn(run func(ctx context.Context, ne
 	return _c
 }
 
+// CreateUser provides a mock function for the type MockService
+func (_mock *MockService) CreateUser(ctx context.Context, authCtx authenticator.AuthContext, req dto.UserCreateRequest) (*dto.UserGetResponse, error) {
+	ret := _mock.Called(ctx, authCtx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 *dto.UserGetResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, dto.UserCreateRequest) (*dto.UserGetResponse, error)); ok {
+		return returnFunc(ctx, authCtx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, dto.UserCreateRequest) *dto.UserGetResponse); ok {
+		r0 = returnFunc(ctx, authCtx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.UserGetResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, dto.UserCreateRequest) error); ok {
+		r1 = returnFunc(ctx, authCtx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type MockService_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - req dto.UserCreateRequest
+func (_e *MockService_Expecter) CreateUser(ctx interface{}, authCtx interface{}, req interface{}) *MockService_CreateUser_Call {
+	return &MockService_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, authCtx, req)}
+}
+
+func (_c *MockService_CreateUser_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, req dto.UserCreateRequest)) *MockService_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 dto.UserCreateRequest
+		if args[2] != nil {
+			arg2 = args[2].(dto.UserCreateRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateUser_Call) Return(userGetResponse *dto.UserGetResponse, err error) *MockService_CreateUser_Call {
+	_c.Call.Return(userGetResponse, err)
+	return _c
+}
+
+func (_c *MockService_CreateUser_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, req dto.UserCreateRequest) (*dto.UserGetResponse, error)) *MockService_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAvatar provides a mock function for the type MockService
+func (_mock *MockService) DeleteAvatar(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*dto.UserGetResponse, error) {
+	ret := _mock.Called(ctx, authCtx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAvatar")
+	}
+
+	var r0 *dto.UserGetResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) (*dto.UserGetResponse, error)); ok {
+		return returnFunc(ctx, authCtx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) *dto.UserGetResponse); ok {
+		r0 = returnFunc(ctx, authCtx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.UserGetResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, uint64) error); ok {
+		r1 = returnFunc(ctx, authCtx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_DeleteAvatar_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAvatar'
+type MockService_DeleteAvatar_Call struct {
+	*mock.Call
+}
+
+// DeleteAvatar is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+func (_e *MockService_Expecter) DeleteAvatar(ctx interface{}, authCtx interface{}, id interface{}) *MockService_DeleteAvatar_Call {
+	return &MockService_DeleteAvatar_Call{Call: _e.mock.On("DeleteAvatar", ctx, authCtx, id)}
+}
+
+func (_c *MockService_DeleteAvatar_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64)) *MockService_DeleteAvatar_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteAvatar_Call) Return(userGetResponse *dto.UserGetResponse, err error) *MockService_DeleteAvatar_Call {
+	_c.Call.Return(userGetResponse, err)
+	return _c
+}
+
+func (_c *MockService_DeleteAvatar_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*dto.UserGetResponse, error)) *MockService_DeleteAvatar_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUser provides a mock function for the type MockService
+func (_mock *MockService) DeleteUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error {
+	ret := _mock.Called(ctx, authCtx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) error); ok {
+		r0 = returnFunc(ctx, authCtx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
+type MockService_DeleteUser_Call struct {
+	*mock.Call
+}
+
+// DeleteUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+func (_e *MockService_Expecter) DeleteUser(ctx interface{}, authCtx interface{}, id interface{}) *MockService_DeleteUser_Call {
+	return &MockService_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, authCtx, id)}
+}
+
+func (_c *MockService_DeleteUser_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64)) *MockService_DeleteUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteUser_Call) Return(err error) *MockService_DeleteUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteUser_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error) *MockService_DeleteUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetById provides a mock function for the type MockService
 func (_mock *MockService) GetById(ctx context.Context, id uint32) (*dbModel.User, error) {
 	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetById")
+		panic("no return value specified for GetById")
+	}
+
+	var r0 *dbModel.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint32) (*dbModel.User, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint32) *dbModel.User); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dbModel.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint32) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetById'
+type MockService_GetById_Call struct {
+	*mock.Call
+}
+
+// GetById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint32
+func (_e *MockService_Expecter) GetById(ctx interface{}, id interface{}) *MockService_GetById_Call {
+	return &MockService_GetById_Call{Call: _e.mock.On("GetById", ctx, id)}
+}
+
+func (_c *MockService_GetById_Call) Run(run func(ctx context.Context, id uint32)) *MockService_GetById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint32
+		if args[1] != nil {
+			arg1 = args[1].(uint32)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetById_Call) Return(user *dbModel.User, err error) *MockService_GetById_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockService_GetById_Call) RunAndReturn(run func(ctx context.Context, id uint32) (*dbModel.User, error)) *MockService_GetById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDataRequest provides a mock function for the type MockService
+func (_mock *MockService) GetDataRequest(ctx context.Context, authCtx authenticator.AuthContext, id uint64, requestID int64) (*dto.DataRequestResponse, error) {
+	ret := _mock.Called(ctx, authCtx, id, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDataRequest")
+	}
+
+	var r0 *dto.DataRequestResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64, int64) (*dto.DataRequestResponse, error)); ok {
+		return returnFunc(ctx, authCtx, id, requestID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64, int64) *dto.DataRequestResponse); ok {
+		r0 = returnFunc(ctx, authCtx, id, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.DataRequestResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, uint64, int64) error); ok {
+		r1 = returnFunc(ctx, authCtx, id, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetDataRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDataRequest'
+type MockService_GetDataRequest_Call struct {
+	*mock.Call
+}
+
+// GetDataRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+//   - requestID int64
+func (_e *MockService_Expecter) GetDataRequest(ctx interface{}, authCtx interface{}, id interface{}, requestID interface{}) *MockService_GetDataRequest_Call {
+	return &MockService_GetDataRequest_Call{Call: _e.mock.On("GetDataRequest", ctx, authCtx, id, requestID)}
+}
+
+func (_c *MockService_GetDataRequest_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64, requestID int64)) *MockService_GetDataRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetDataRequest_Call) Return(dataRequestResponse *dto.DataRequestResponse, err error) *MockService_GetDataRequest_Call {
+	_c.Call.Return(dataRequestResponse, err)
+	return _c
+}
+
+func (_c *MockService_GetDataRequest_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64, requestID int64) (*dto.DataRequestResponse, error)) *MockService_GetDataRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUser provides a mock function for the type MockService
+func (_mock *MockService) GetUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*dto.UserGetResponse, error) {
+	ret := _mock.Called(ctx, authCtx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 *dto.UserGetResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) (*dto.UserGetResponse, error)); ok {
+		return returnFunc(ctx, authCtx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) *dto.UserGetResponse); ok {
+		r0 = returnFunc(ctx, authCtx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.UserGetResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, uint64) error); ok {
+		r1 = returnFunc(ctx, authCtx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUser'
+type MockService_GetUser_Call struct {
+	*mock.Call
+}
+
+// GetUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+func (_e *MockService_Expecter) GetUser(ctx interface{}, authCtx interface{}, id interface{}) *MockService_GetUser_Call {
+	return &MockService_GetUser_Call{Call: _e.mock.On("GetUser", ctx, authCtx, id)}
+}
+
+func (_c *MockService_GetUser_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64)) *MockService_GetUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetUser_Call) Return(userGetResponse *dto.UserGetResponse, err error) *MockService_GetUser_Call {
+	_c.Call.Return(userGetResponse, err)
+	return _c
+}
+
+func (_c *MockService_GetUser_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*dto.UserGetResponse, error)) *MockService_GetUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDataRequests provides a mock function for the type MockService
+func (_mock *MockService) ListDataRequests(ctx context.Context, authCtx authenticator.AuthContext, id uint64) ([]dto.DataRequestResponse, error) {
+	ret := _mock.Called(ctx, authCtx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDataRequests")
+	}
+
+	var r0 []dto.DataRequestResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) ([]dto.DataRequestResponse, error)); ok {
+		return returnFunc(ctx, authCtx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) []dto.DataRequestResponse); ok {
+		r0 = returnFunc(ctx, authCtx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.DataRequestResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, uint64) error); ok {
+		r1 = returnFunc(ctx, authCtx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_ListDataRequests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDataRequests'
+type MockService_ListDataRequests_Call struct {
+	*mock.Call
+}
+
+// ListDataRequests is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+func (_e *MockService_Expecter) ListDataRequests(ctx interface{}, authCtx interface{}, id interface{}) *MockService_ListDataRequests_Call {
+	return &MockService_ListDataRequests_Call{Call: _e.mock.On("ListDataRequests", ctx, authCtx, id)}
+}
+
+func (_c *MockService_ListDataRequests_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64)) *MockService_ListDataRequests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListDataRequests_Call) Return(dataRequestResponses []dto.DataRequestResponse, err error) *MockService_ListDataRequests_Call {
+	_c.Call.Return(dataRequestResponses, err)
+	return _c
+}
+
+func (_c *MockService_ListDataRequests_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) ([]dto.DataRequestResponse, error)) *MockService_ListDataRequests_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUsers provides a mock function for the type MockService
+func (_mock *MockService) ListUsers(ctx context.Context, authCtx authenticator.AuthContext, req dto.UserListRequest) (*dto.UserListResponse, error) {
+	ret := _mock.Called(ctx, authCtx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 *dto.UserListResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, dto.UserListRequest) (*dto.UserListResponse, error)); ok {
+		return returnFunc(ctx, authCtx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, dto.UserListRequest) *dto.UserListResponse); ok {
+		r0 = returnFunc(ctx, authCtx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.UserListResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, dto.UserListRequest) error); ok {
+		r1 = returnFunc(ctx, authCtx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_ListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUsers'
+type MockService_ListUsers_Call struct {
+	*mock.Call
+}
+
+// ListUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - req dto.UserListRequest
+func (_e *MockService_Expecter) ListUsers(ctx interface{}, authCtx interface{}, req interface{}) *MockService_ListUsers_Call {
+	return &MockService_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx, authCtx, req)}
+}
+
+func (_c *MockService_ListUsers_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, req dto.UserListRequest)) *MockService_ListUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 dto.UserListRequest
+		if args[2] != nil {
+			arg2 = args[2].(dto.UserListRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListUsers_Call) Return(userListResponse *dto.UserListResponse, err error) *MockService_ListUsers_Call {
+	_c.Call.Return(userListResponse, err)
+	return _c
+}
+
+func (_c *MockService_ListUsers_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, req dto.UserListRequest) (*dto.UserListResponse, error)) *MockService_ListUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeUser provides a mock function for the type MockService
+func (_mock *MockService) PurgeUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error {
+	ret := _mock.Called(ctx, authCtx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeUser")
 	}
 
-	var r0 *dbModel.User
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) error); ok {
+		r0 = returnFunc(ctx, authCtx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_PurgeUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeUser'
+type MockService_PurgeUser_Call struct {
+	*mock.Call
+}
+
+// PurgeUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+func (_e *MockService_Expecter) PurgeUser(ctx interface{}, authCtx interface{}, id interface{}) *MockService_PurgeUser_Call {
+	return &MockService_PurgeUser_Call{Call: _e.mock.On("PurgeUser", ctx, authCtx, id)}
+}
+
+func (_c *MockService_PurgeUser_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64)) *MockService_PurgeUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_PurgeUser_Call) Return(err error) *MockService_PurgeUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_PurgeUser_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error) *MockService_PurgeUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequestDataExport provides a mock function for the type MockService
+func (_mock *MockService) RequestDataExport(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*dto.DataRequestResponse, error) {
+	ret := _mock.Called(ctx, authCtx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestDataExport")
+	}
+
+	var r0 *dto.DataRequestResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint32) (*dbModel.User, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) (*dto.DataRequestResponse, error)); ok {
+		return returnFunc(ctx, authCtx, id)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint32) *dbModel.User); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) *dto.DataRequestResponse); ok {
+		r0 = returnFunc(ctx, authCtx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*dbModel.User)
+			r0 = ret.Get(0).(*dto.DataRequestResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uint32) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, uint64) error); ok {
+		r1 = returnFunc(ctx, authCtx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockService_GetById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetById'
-type MockService_GetById_Call struct {
+// MockService_RequestDataExport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestDataExport'
+type MockService_RequestDataExport_Call struct {
 	*mock.Call
 }
 
-// GetById is a helper method to define mock.On call
+// RequestDataExport is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id uint32
-func (_e *MockService_Expecter) GetById(ctx interface{}, id interface{}) *MockService_GetById_Call {
-	return &MockService_GetById_Call{Call: _e.mock.On("GetById", ctx, id)}
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+func (_e *MockService_Expecter) RequestDataExport(ctx interface{}, authCtx interface{}, id interface{}) *MockService_RequestDataExport_Call {
+	return &MockService_RequestDataExport_Call{Call: _e.mock.On("RequestDataExport", ctx, authCtx, id)}
 }
 
-func (_c *MockService_GetById_Call) Run(run func(ctx context.Context, id uint32)) *MockService_GetById_Call {
+func (_c *MockService_RequestDataExport_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64)) *MockService_RequestDataExport_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uint32
+		var arg1 authenticator.AuthContext
 		if args[1] != nil {
-			arg1 = args[1].(uint32)
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_GetById_Call) Return(userModel *dbModel.User, err error) *MockService_GetById_Call {
-	_c.Call.Return(userModel, err)
+func (_c *MockService_RequestDataExport_Call) Return(dataRequestResponse *dto.DataRequestResponse, err error) *MockService_RequestDataExport_Call {
+	_c.Call.Return(dataRequestResponse, err)
 	return _c
 }
 
-func (_c *MockService_GetById_Call) RunAndReturn(run func(ctx context.Context, id uint32) (*dbModel.User, error)) *MockService_GetById_Call {
+func (_c *MockService_RequestDataExport_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*dto.DataRequestResponse, error)) *MockService_RequestDataExport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequestErasure provides a mock function for the type MockService
+func (_mock *MockService) RequestErasure(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*dto.DataRequestResponse, error) {
+	ret := _mock.Called(ctx, authCtx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestErasure")
+	}
+
+	var r0 *dto.DataRequestResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) (*dto.DataRequestResponse, error)); ok {
+		return returnFunc(ctx, authCtx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) *dto.DataRequestResponse); ok {
+		r0 = returnFunc(ctx, authCtx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.DataRequestResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, uint64) error); ok {
+		r1 = returnFunc(ctx, authCtx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_RequestErasure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestErasure'
+type MockService_RequestErasure_Call struct {
+	*mock.Call
+}
+
+// RequestErasure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+func (_e *MockService_Expecter) RequestErasure(ctx interface{}, authCtx interface{}, id interface{}) *MockService_RequestErasure_Call {
+	return &MockService_RequestErasure_Call{Call: _e.mock.On("RequestErasure", ctx, authCtx, id)}
+}
+
+func (_c *MockService_RequestErasure_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64)) *MockService_RequestErasure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_RequestErasure_Call) Return(dataRequestResponse *dto.DataRequestResponse, err error) *MockService_RequestErasure_Call {
+	_c.Call.Return(dataRequestResponse, err)
+	return _c
+}
+
+func (_c *MockService_RequestErasure_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*dto.DataRequestResponse, error)) *MockService_RequestErasure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreUser provides a mock function for the type MockService
+func (_mock *MockService) RestoreUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error {
+	ret := _mock.Called(ctx, authCtx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64) error); ok {
+		r0 = returnFunc(ctx, authCtx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_RestoreUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreUser'
+type MockService_RestoreUser_Call struct {
+	*mock.Call
+}
+
+// RestoreUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+func (_e *MockService_Expecter) RestoreUser(ctx interface{}, authCtx interface{}, id interface{}) *MockService_RestoreUser_Call {
+	return &MockService_RestoreUser_Call{Call: _e.mock.On("RestoreUser", ctx, authCtx, id)}
+}
+
+func (_c *MockService_RestoreUser_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64)) *MockService_RestoreUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_RestoreUser_Call) Return(err error) *MockService_RestoreUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_RestoreUser_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error) *MockService_RestoreUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -294,3 +1084,157 @@ func (_c *MockService_Update_Call) RunAndReturn(run func(ctx context.Context, up
 	_c.Call.Return(run)
 	return _c
 }
+
+// UpdateUser provides a mock function for the type MockService
+func (_mock *MockService) UpdateUser(ctx context.Context, authCtx authenticator.AuthContext, req dto.UserUpdateRequest) (*dto.UserGetResponse, error) {
+	ret := _mock.Called(ctx, authCtx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 *dto.UserGetResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, dto.UserUpdateRequest) (*dto.UserGetResponse, error)); ok {
+		return returnFunc(ctx, authCtx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, dto.UserUpdateRequest) *dto.UserGetResponse); ok {
+		r0 = returnFunc(ctx, authCtx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.UserGetResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, dto.UserUpdateRequest) error); ok {
+		r1 = returnFunc(ctx, authCtx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
+type MockService_UpdateUser_Call struct {
+	*mock.Call
+}
+
+// UpdateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - req dto.UserUpdateRequest
+func (_e *MockService_Expecter) UpdateUser(ctx interface{}, authCtx interface{}, req interface{}) *MockService_UpdateUser_Call {
+	return &MockService_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, authCtx, req)}
+}
+
+func (_c *MockService_UpdateUser_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, req dto.UserUpdateRequest)) *MockService_UpdateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 dto.UserUpdateRequest
+		if args[2] != nil {
+			arg2 = args[2].(dto.UserUpdateRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_UpdateUser_Call) Return(userGetResponse *dto.UserGetResponse, err error) *MockService_UpdateUser_Call {
+	_c.Call.Return(userGetResponse, err)
+	return _c
+}
+
+func (_c *MockService_UpdateUser_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, req dto.UserUpdateRequest) (*dto.UserGetResponse, error)) *MockService_UpdateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UploadAvatar provides a mock function for the type MockService
+func (_mock *MockService) UploadAvatar(ctx context.Context, authCtx authenticator.AuthContext, id uint64, file *multipart.FileHeader) (*dto.UserGetResponse, error) {
+	ret := _mock.Called(ctx, authCtx, id, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadAvatar")
+	}
+
+	var r0 *dto.UserGetResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64, *multipart.FileHeader) (*dto.UserGetResponse, error)); ok {
+		return returnFunc(ctx, authCtx, id, file)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, authenticator.AuthContext, uint64, *multipart.FileHeader) *dto.UserGetResponse); ok {
+		r0 = returnFunc(ctx, authCtx, id, file)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.UserGetResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, authenticator.AuthContext, uint64, *multipart.FileHeader) error); ok {
+		r1 = returnFunc(ctx, authCtx, id, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_UploadAvatar_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UploadAvatar'
+type MockService_UploadAvatar_Call struct {
+	*mock.Call
+}
+
+// UploadAvatar is a helper method to define mock.On call
+//   - ctx context.Context
+//   - authCtx authenticator.AuthContext
+//   - id uint64
+//   - file *multipart.FileHeader
+func (_e *MockService_Expecter) UploadAvatar(ctx interface{}, authCtx interface{}, id interface{}, file interface{}) *MockService_UploadAvatar_Call {
+	return &MockService_UploadAvatar_Call{Call: _e.mock.On("UploadAvatar", ctx, authCtx, id, file)}
+}
+
+func (_c *MockService_UploadAvatar_Call) Run(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64, file *multipart.FileHeader)) *MockService_UploadAvatar_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 authenticator.AuthContext
+		if args[1] != nil {
+			arg1 = args[1].(authenticator.AuthContext)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		var arg3 *multipart.FileHeader
+		if args[3] != nil {
+			arg3 = args[3].(*multipart.FileHeader)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_UploadAvatar_Call) Return(userGetResponse *dto.UserGetResponse, err error) *MockService_UploadAvatar_Call {
+	_c.Call.Return(userGetResponse, err)
+	return _c
+}
+
+func (_c *MockService_UploadAvatar_Call) RunAndReturn(run func(ctx context.Context, authCtx authenticator.AuthContext, id uint64, file *multipart.FileHeader) (*dto.UserGetResponse, error)) *MockService_UploadAvatar_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,357 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	rbacConstants "ichi-go/internal/applications/rbac/constants"
+	userDto "ichi-go/internal/applications/user/dto"
+	userRepo "ichi-go/internal/applications/user/repository"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/requestctx"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// ListUsers returns one page of users to holders of users:view
+func (s *ServiceImpl) ListUsers(ctx context.Context, authCtx authenticator.AuthContext, req userDto.UserListRequest) (*userDto.UserListResponse, error) {
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersView, 0); err != nil {
+		return nil, err
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > maxPageSize {
+		pageSize = defaultPageSize
+	}
+
+	filter := userRepo.ListFilter{
+		Email:       strings.TrimSpace(req.Email),
+		Name:        strings.TrimSpace(req.Name),
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		Status:      req.Status,
+		SortBy:      req.SortBy,
+		SortDesc:    req.SortDir == "desc",
+		Page:        page,
+		PerPage:     pageSize,
+	}
+	if req.EmailVerified != "" {
+		verified := req.EmailVerified == "true"
+		filter.EmailVerified = &verified
+	}
+
+	users, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &userDto.UserListResponse{
+		Users:      make([]userDto.UserGetResponse, 0, len(users)),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (total + pageSize - 1) / pageSize,
+		TotalCount: total,
+	}
+	for _, u := range users {
+		resp.Users = append(resp.Users, s.userResponse(ctx, u))
+	}
+	return resp, nil
+}
+
+// GetUser returns a user to themselves or to holders of users:view
+func (s *ServiceImpl) GetUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*userDto.UserGetResponse, error) {
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersView, id); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.userResponse(ctx, existing)
+	return &resp, nil
+}
+
+// CreateUser adds an account on behalf of a holder of users:create
+func (s *ServiceImpl) CreateUser(ctx context.Context, authCtx authenticator.AuthContext, req userDto.UserCreateRequest) (*userDto.UserGetResponse, error) {
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersCreate, 0); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkEmailAvailable(ctx, req.Email, 0); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, pkgErrors.UserService(pkgErrors.ErrCodeUserCreateFailed).
+			Hint("Failed to process password").
+			Wrap(err)
+	}
+
+	userID, err := s.repo.Create(ctx, model.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: string(hashedPassword),
+	})
+	if err != nil {
+		return nil, pkgErrors.UserService(pkgErrors.ErrCodeUserCreateFailed).
+			With("email", req.Email).
+			Hint("Failed to create user account").
+			Wrap(err)
+	}
+
+	created, err := s.findUser(ctx, uint64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("User %d created user %d", authCtx.UserID.ID, userID)
+	resp := s.userResponse(ctx, created)
+	return &resp, nil
+}
+
+// UpdateUser changes the name and email of a user. Users may update themselves;
+// updating anyone else needs users:edit.
+func (s *ServiceImpl) UpdateUser(ctx context.Context, authCtx authenticator.AuthContext, req userDto.UserUpdateRequest) (*userDto.UserGetResponse, error) {
+	id := uint64(req.ID)
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersEdit, id); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(existing.Email, req.Email) {
+		if err := s.checkEmailAvailable(ctx, req.Email, id); err != nil {
+			return nil, err
+		}
+	}
+
+	updateUser := model.User{Name: req.Name, Email: req.Email}
+	updateUser.ID = existing.ID
+	if _, err := s.Update(ctx, updateUser); err != nil {
+		return nil, pkgErrors.UserService(pkgErrors.ErrCodeUserUpdateFailed).
+			With("user_id", id).
+			Hint("Failed to update user").
+			Wrap(err)
+	}
+
+	updated, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.userResponse(ctx, updated)
+	return &resp, nil
+}
+
+// DeleteUser soft deletes a user; it needs users:delete. Deleted users are signed out,
+// can no longer sign in and can be restored.
+func (s *ServiceImpl) DeleteUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error {
+	if err := s.authorizeRemoval(ctx, authCtx, rbacConstants.UsersDelete, id); err != nil {
+		return err
+	}
+
+	if _, err := s.findUser(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.SoftDelete(ctx, int64(id)); err != nil {
+		return pkgErrors.UserService(pkgErrors.ErrCodeUserDeleteFailed).
+			With("user_id", id).
+			Hint("Failed to delete user").
+			Wrap(err)
+	}
+	s.forgetUser(ctx, id)
+	if err := s.signOut(ctx, id); err != nil {
+		return err
+	}
+
+	logger.Infof("User %d deleted user %d", authCtx.UserID.ID, id)
+	return nil
+}
+
+// RestoreUser undoes DeleteUser; it needs users:delete
+func (s *ServiceImpl) RestoreUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error {
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersDelete, 0); err != nil {
+		return err
+	}
+
+	existing, err := s.findUserWithDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.DeletedAt.IsZero() {
+		return nil
+	}
+	if err := s.repo.Restore(ctx, int64(id)); err != nil {
+		return pkgErrors.UserService(pkgErrors.ErrCodeUserUpdateFailed).
+			With("user_id", id).
+			Hint("Failed to restore user").
+			Wrap(err)
+	}
+	s.forgetUser(ctx, id)
+
+	logger.Infof("User %d restored user %d", authCtx.UserID.ID, id)
+	return nil
+}
+
+// PurgeUser permanently removes a user, deleted or not, with its sessions and
+// credentials; it needs users:manage
+func (s *ServiceImpl) PurgeUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error {
+	if err := s.authorizeRemoval(ctx, authCtx, rbacConstants.UsersManage, id); err != nil {
+		return err
+	}
+
+	if _, err := s.findUserWithDeleted(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.HardDelete(ctx, id); err != nil {
+		return pkgErrors.UserService(pkgErrors.ErrCodeUserDeleteFailed).
+			With("user_id", id).
+			Hint("Failed to delete user").
+			Wrap(err)
+	}
+	s.forgetUser(ctx, id)
+	// The sessions are gone with the user; outstanding access tokens are not.
+	if err := s.signOut(ctx, id); err != nil {
+		return err
+	}
+
+	logger.Warnf("User %d permanently deleted user %d", authCtx.UserID.ID, id)
+	return nil
+}
+
+// signOut ends the sessions of a removed user
+func (s *ServiceImpl) signOut(ctx context.Context, id uint64) error {
+	if s.sessions == nil {
+		return nil
+	}
+	if err := s.sessions.SignOutDeletedUser(ctx, id); err != nil {
+		return pkgErrors.UserService(pkgErrors.ErrCodeUserDeleteFailed).
+			With("user_id", id).
+			Hint("User deleted, but their sessions could not be ended").
+			Wrap(err)
+	}
+	return nil
+}
+
+// authorize lets the caller act on the account ownerID when it is their own, or when
+// they hold permission (e.g. "users:edit"); ownerID 0 always requires the permission.
+// Requests made with an API key also need the permission among the key's scopes.
+func (s *ServiceImpl) authorize(ctx context.Context, authCtx authenticator.AuthContext, permission string, ownerID uint64) error {
+	resource, action, _ := strings.Cut(permission, ":")
+	if authCtx.APIKey != nil && !authenticator.ScopeAllows(authCtx.APIKey.Scopes, resource, action) {
+		return forbiddenError(permission, "API key scope does not allow this action")
+	}
+
+	callerID := authCtx.UserID.ID
+	if ownerID != 0 && ownerID == callerID {
+		return nil
+	}
+
+	if s.permissions != nil {
+		tenantID := requestctx.GetTenantID(ctx)
+		if tenantID == "" {
+			tenantID = s.defaultTenant
+		}
+		allowed, err := s.permissions.CheckPermission(ctx, int64(callerID), tenantID, resource, action)
+		if err != nil {
+			return pkgErrors.UserService(pkgErrors.ErrCodeInternal).
+				With("user_id", callerID).
+				With("permission", permission).
+				Hint("Permission check failed").
+				Wrap(err)
+		}
+		if allowed {
+			return nil
+		}
+	}
+	return forbiddenError(permission, "You may only manage your own account")
+}
+
+// authorizeRemoval is authorize for deleting accounts, which nobody may do to themselves
+func (s *ServiceImpl) authorizeRemoval(ctx context.Context, authCtx authenticator.AuthContext, permission string, id uint64) error {
+	if id == authCtx.UserID.ID {
+		return forbiddenError(permission, "You cannot delete your own account")
+	}
+	return s.authorize(ctx, authCtx, permission, 0)
+}
+
+// checkEmailAvailable fails when email belongs to a user other than exceptID
+func (s *ServiceImpl) checkEmailAvailable(ctx context.Context, email string, exceptID uint64) error {
+	existing, err := s.repo.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if existing != nil && uint64(existing.ID) != exceptID {
+		return pkgErrors.UserService(pkgErrors.ErrCodeUserExists).
+			With("email", email).
+			Hint("Email already registered").
+			Errorf("user already exists")
+	}
+	return nil
+}
+
+func (s *ServiceImpl) findUser(ctx context.Context, id uint64) (*model.User, error) {
+	found, err := s.repo.GetById(ctx, id)
+	return found, notFoundError(id, found, err)
+}
+
+func (s *ServiceImpl) findUserWithDeleted(ctx context.Context, id uint64) (*model.User, error) {
+	found, err := s.repo.GetByIdWithDeleted(ctx, id)
+	return found, notFoundError(id, found, err)
+}
+
+// forgetUser drops the cached copy kept by GetById
+func (s *ServiceImpl) forgetUser(ctx context.Context, id uint64) {
+	if s.cache != nil {
+		_, _ = s.cache.Delete(ctx, fmt.Sprintf("user:%d", id))
+	}
+}
+
+func notFoundError(id uint64, found *model.User, err error) error {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err != nil || found == nil {
+		return pkgErrors.UserService(pkgErrors.ErrCodeUserNotFound).
+			With("user_id", id).
+			Hint("User not found").
+			Errorf("user not found")
+	}
+	return nil
+}
+
+func forbiddenError(permission, hint string) error {
+	return pkgErrors.UserService(pkgErrors.ErrCodeForbidden).
+		With("permission", permission).
+		Hint(hint).
+		Errorf("permission denied")
+}
+
+func (s *ServiceImpl) userResponse(ctx context.Context, u *model.User) userDto.UserGetResponse {
+	return userDto.UserGetResponse{
+		ID:              u.ID,
+		Name:            u.Name,
+		Email:           u.Email,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		AvatarURL:       s.avatarURL(ctx, u),
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+		DeletedAt:       u.DeletedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	rbacConstants "ichi-go/internal/applications/rbac/constants"
+	userDto "ichi-go/internal/applications/user/dto"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+	"ichi-go/pkg/storage"
+	"mime/multipart"
+
+	"github.com/google/uuid"
+)
+
+// UploadAvatar replaces the profile picture of a user. Users may change their own;
+// changing anyone else's needs users:edit.
+func (s *ServiceImpl) UploadAvatar(ctx context.Context, authCtx authenticator.AuthContext, id uint64, file *multipart.FileHeader) (*userDto.UserGetResponse, error) {
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersEdit, id); err != nil {
+		return nil, err
+	}
+	if s.disk == nil {
+		return nil, storageUnavailableError()
+	}
+
+	existing, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := storage.ReadUpload(file, s.avatarRules)
+	if err != nil {
+		return nil, uploadError(err, s.avatarRules)
+	}
+
+	// A new name per upload keeps caches from serving the previous picture.
+	path := fmt.Sprintf("avatars/%d/%s%s", id, uuid.NewString(), upload.Extension)
+	if err := s.disk.Put(ctx, path, bytes.NewReader(upload.Data), storage.PutOptions{ContentType: upload.ContentType}); err != nil {
+		return nil, pkgErrors.UserService(pkgErrors.ErrCodeStorage).
+			With("user_id", id).
+			Hint("Failed to store avatar").
+			Wrap(err)
+	}
+	if err := s.repo.SetAvatar(ctx, id, path); err != nil {
+		s.deleteFile(ctx, path)
+		return nil, err
+	}
+	if existing.AvatarPath != "" {
+		s.deleteFile(ctx, existing.AvatarPath)
+	}
+	s.forgetUser(ctx, id)
+
+	existing.AvatarPath = path
+	resp := s.userResponse(ctx, existing)
+	return &resp, nil
+}
+
+// DeleteAvatar removes the profile picture of a user, with the same permissions as
+// UploadAvatar.
+func (s *ServiceImpl) DeleteAvatar(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*userDto.UserGetResponse, error) {
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersEdit, id); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.AvatarPath != "" {
+		if err := s.repo.SetAvatar(ctx, id, ""); err != nil {
+			return nil, err
+		}
+		if s.disk != nil {
+			s.deleteFile(ctx, existing.AvatarPath)
+		}
+		s.forgetUser(ctx, id)
+		existing.AvatarPath = ""
+	}
+
+	resp := s.userResponse(ctx, existing)
+	return &resp, nil
+}
+
+// avatarURL returns a temporary URL of the user's avatar, or "" when there is none
+func (s *ServiceImpl) avatarURL(ctx context.Context, u *model.User) string {
+	if u.AvatarPath == "" || s.disk == nil {
+		return ""
+	}
+	url, err := s.disk.TemporaryURL(ctx, u.AvatarPath, s.urlTTL)
+	if err != nil {
+		logger.Warnf("Failed to sign avatar URL of user %d: %v", u.ID, err)
+		return ""
+	}
+	return url
+}
+
+// deleteFile removes a file that is no longer referenced; failures only leave an orphan behind
+func (s *ServiceImpl) deleteFile(ctx context.Context, path string) {
+	if err := s.disk.Delete(ctx, path); err != nil {
+		logger.Warnf("Failed to delete file %s: %v", path, err)
+	}
+}
+
+func uploadError(err error, rules storage.UploadRules) error {
+	switch {
+	case errors.Is(err, storage.ErrFileTooLarge):
+		return pkgErrors.UserService(pkgErrors.ErrCodeFileTooLarge).
+			With("max_size", rules.MaxSize).
+			Hint(fmt.Sprintf("Avatar must not be larger than %d KB", rules.MaxSize/1024)).
+			Wrap(err)
+	case errors.Is(err, storage.ErrUnsupportedType):
+		return pkgErrors.UserService(pkgErrors.ErrCodeUnsupportedMediaType).
+			With("allowed_types", rules.AllowedTypes).
+			Hint("Avatar must be an image").
+			Wrap(err)
+	default:
+		return pkgErrors.UserService(pkgErrors.ErrCodeValidation).
+			Hint("Could not read the uploaded file").
+			Wrap(err)
+	}
+}
+
+func storageUnavailableError() error {
+	return pkgErrors.UserService(pkgErrors.ErrCodeStorage).
+		Hint("File storage is not configured").
+		Errorf("storage unavailable")
+}
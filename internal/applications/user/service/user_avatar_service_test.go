@@ -0,0 +1,132 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	userRepoMocks "ichi-go/internal/applications/user/repository/mocks"
+	cacheMocks "ichi-go/internal/infra/cache/mocks"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+
+func newAvatarTestService(t *testing.T, granted ...string) (*ServiceImpl, *userRepoMocks.MockRepository, *cacheMocks.MockCache, *storage.MemoryDisk) {
+	t.Helper()
+	signer, err := storage.NewURLSigner("https://api.example.com/files", "0123456789abcdef0123456789abcdef")
+	require.NoError(t, err)
+	disk := storage.NewMemoryDisk(signer)
+
+	svc, repo, cache := newAdminTestService(t, granted...)
+	svc.disk = disk
+	svc.avatarRules = storage.UploadRules{MaxSize: 1024, AllowedTypes: []string{"image/png", "image/jpeg"}}
+	svc.urlTTL = time.Minute
+	return svc, repo, cache, disk
+}
+
+// fileHeader builds the *multipart.FileHeader echo hands to the controller
+func fileHeader(t *testing.T, name string, content []byte) *multipart.FileHeader {
+	t.Helper()
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile("avatar", name)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest("PUT", "/api/users/7/avatar", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	require.NoError(t, req.ParseMultipartForm(1<<20))
+	return req.MultipartForm.File["avatar"][0]
+}
+
+func TestUploadAvatar_StoresImageAndReturnsSignedURL(t *testing.T) {
+	svc, repo, cache, disk := newAvatarTestService(t)
+	repo.On("GetById", mock.Anything, uint64(7)).Return(testUser(7), nil)
+	var stored string
+	repo.On("SetAvatar", mock.Anything, uint64(7), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { stored = args.String(2) }).Return(nil)
+	cache.On("Delete", mock.Anything, "user:7").Return(true, nil)
+
+	resp, err := svc.UploadAvatar(context.Background(), caller(7), 7, fileHeader(t, "me.txt", testPNG))
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(stored, "avatars/7/"))
+	assert.True(t, strings.HasSuffix(stored, ".png"), "extension follows the detected type, not the file name")
+	data, err := disk.Get(context.Background(), stored)
+	require.NoError(t, err)
+	assert.Equal(t, testPNG, data)
+	assert.Contains(t, resp.AvatarURL, "https://api.example.com/files/"+stored+"?")
+	assert.Contains(t, resp.AvatarURL, "signature=")
+}
+
+func TestUploadAvatar_ReplacesPreviousFile(t *testing.T) {
+	svc, repo, cache, disk := newAvatarTestService(t)
+	require.NoError(t, disk.Put(context.Background(), "avatars/7/old.png", bytes.NewReader(testPNG), storage.PutOptions{}))
+	existing := testUser(7)
+	existing.AvatarPath = "avatars/7/old.png"
+	repo.On("GetById", mock.Anything, uint64(7)).Return(existing, nil)
+	repo.On("SetAvatar", mock.Anything, uint64(7), mock.AnythingOfType("string")).Return(nil)
+	cache.On("Delete", mock.Anything, "user:7").Return(true, nil)
+
+	_, err := svc.UploadAvatar(context.Background(), caller(7), 7, fileHeader(t, "me.png", testPNG))
+	require.NoError(t, err)
+
+	_, err = disk.Get(context.Background(), "avatars/7/old.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.Len(t, disk.Files(), 1)
+}
+
+func TestUploadAvatar_RejectsInvalidFiles(t *testing.T) {
+	svc, repo, _, disk := newAvatarTestService(t)
+	repo.On("GetById", mock.Anything, uint64(7)).Return(testUser(7), nil)
+
+	_, err := svc.UploadAvatar(context.Background(), caller(7), 7, fileHeader(t, "me.png", []byte("<svg onload=alert(1)>")))
+	assertErrorCode(t, err, pkgErrors.ErrCodeUnsupportedMediaType)
+
+	_, err = svc.UploadAvatar(context.Background(), caller(7), 7, fileHeader(t, "me.png", append(testPNG, make([]byte, 2048)...)))
+	assertErrorCode(t, err, pkgErrors.ErrCodeFileTooLarge)
+
+	assert.Empty(t, disk.Files())
+	repo.AssertNotCalled(t, "SetAvatar", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUploadAvatar_OtherUserNeedsPermission(t *testing.T) {
+	svc, _, _, _ := newAvatarTestService(t)
+
+	_, err := svc.UploadAvatar(context.Background(), caller(7), 8, fileHeader(t, "me.png", testPNG))
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+}
+
+func TestUploadAvatar_WithoutStorage(t *testing.T) {
+	svc, _, _ := newAdminTestService(t)
+
+	_, err := svc.UploadAvatar(context.Background(), caller(7), 7, fileHeader(t, "me.png", testPNG))
+	assertErrorCode(t, err, pkgErrors.ErrCodeStorage)
+}
+
+func TestDeleteAvatar_ClearsPathAndFile(t *testing.T) {
+	svc, repo, cache, disk := newAvatarTestService(t)
+	require.NoError(t, disk.Put(context.Background(), "avatars/7/old.png", bytes.NewReader(testPNG), storage.PutOptions{}))
+	existing := testUser(7)
+	existing.AvatarPath = "avatars/7/old.png"
+	repo.On("GetById", mock.Anything, uint64(7)).Return(existing, nil)
+	repo.On("SetAvatar", mock.Anything, uint64(7), "").Return(nil)
+	cache.On("Delete", mock.Anything, "user:7").Return(true, nil)
+
+	resp, err := svc.DeleteAvatar(context.Background(), caller(7), 7)
+	require.NoError(t, err)
+	assert.Empty(t, resp.AvatarURL)
+	assert.Empty(t, disk.Files())
+}
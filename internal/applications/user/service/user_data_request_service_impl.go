@@ -0,0 +1,150 @@
+package user
+
+import (
+	"context"
+	rbacConstants "ichi-go/internal/applications/rbac/constants"
+	userDto "ichi-go/internal/applications/user/dto"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
+)
+
+// RequestDataExport queues an archive of everything stored about a user. Users may
+// request their own; anyone else's needs users:manage.
+func (s *ServiceImpl) RequestDataExport(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*userDto.DataRequestResponse, error) {
+	if s.disk == nil {
+		return nil, storageUnavailableError()
+	}
+	return s.requestData(ctx, authCtx, id, model.DataRequestExport, userDto.DataExportRoutingKey)
+}
+
+// RequestErasure queues the erasure of a user's personal data, with the same
+// permissions as RequestDataExport. The account is closed when the job runs.
+func (s *ServiceImpl) RequestErasure(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*userDto.DataRequestResponse, error) {
+	return s.requestData(ctx, authCtx, id, model.DataRequestErasure, userDto.DataErasureRoutingKey)
+}
+
+// ListDataRequests returns the export and erasure requests about a user, newest first.
+func (s *ServiceImpl) ListDataRequests(ctx context.Context, authCtx authenticator.AuthContext, id uint64) ([]userDto.DataRequestResponse, error) {
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersManage, id); err != nil {
+		return nil, err
+	}
+	if s.dataRequests == nil {
+		return nil, dataRequestsUnavailableError()
+	}
+
+	reqs, err := s.dataRequests.ListByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]userDto.DataRequestResponse, 0, len(reqs))
+	for _, req := range reqs {
+		resp = append(resp, s.dataRequestResponse(ctx, req))
+	}
+	return resp, nil
+}
+
+// GetDataRequest returns the status of one request, with a download URL once an export completed.
+func (s *ServiceImpl) GetDataRequest(ctx context.Context, authCtx authenticator.AuthContext, id uint64, requestID int64) (*userDto.DataRequestResponse, error) {
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersManage, id); err != nil {
+		return nil, err
+	}
+	if s.dataRequests == nil {
+		return nil, dataRequestsUnavailableError()
+	}
+
+	req, err := s.dataRequests.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil || req.UserID != id {
+		return nil, pkgErrors.UserService(pkgErrors.ErrCodeNotFound).
+			With("user_id", id).
+			With("request_id", requestID).
+			Hint("Data request not found").
+			Errorf("data request not found")
+	}
+	resp := s.dataRequestResponse(ctx, req)
+	return &resp, nil
+}
+
+// requestData records a request and publishes the job processing it. Only one
+// request of each type may be open per user at a time.
+func (s *ServiceImpl) requestData(ctx context.Context, authCtx authenticator.AuthContext, id uint64, requestType, routingKey string) (*userDto.DataRequestResponse, error) {
+	if err := s.authorize(ctx, authCtx, rbacConstants.UsersManage, id); err != nil {
+		return nil, err
+	}
+	if s.dataRequests == nil || s.producer == nil {
+		return nil, dataRequestsUnavailableError()
+	}
+	if _, err := s.findUserWithDeleted(ctx, id); err != nil {
+		return nil, err
+	}
+
+	active, err := s.dataRequests.FindActive(ctx, id, requestType)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, pkgErrors.UserService(pkgErrors.ErrCodeDataRequestBusy).
+			With("user_id", id).
+			With("request_id", active.ID).
+			Hint("A "+requestType+" request for this user is already in progress").
+			Errorf("%s request %d still %s", requestType, active.ID, active.Status)
+	}
+
+	req := &model.UserDataRequest{
+		UserID:      id,
+		RequestedBy: authCtx.UserID.ID,
+		Type:        requestType,
+		Status:      model.DataRequestPending,
+	}
+	if err := s.dataRequests.Create(ctx, req); err != nil {
+		return nil, err
+	}
+
+	message := userDto.DataRequestMessage{RequestID: req.ID, UserID: id}
+	if err := s.producer.Publish(ctx, routingKey, message, rabbitmq.PublishOptions{}); err != nil {
+		if markErr := s.dataRequests.MarkFailed(ctx, req.ID, "could not be queued"); markErr != nil {
+			logger.Errorf("Failed to mark data request %d failed: %v", req.ID, markErr)
+		}
+		return nil, pkgErrors.UserService(pkgErrors.ErrCodeQueue).
+			With("user_id", id).
+			With("request_id", req.ID).
+			Hint("Failed to queue the request, please try again").
+			Wrap(err)
+	}
+	logger.Infof("Queued %s request %d for user %d (requested by %d)", requestType, req.ID, id, req.RequestedBy)
+
+	resp := s.dataRequestResponse(ctx, req)
+	return &resp, nil
+}
+
+func (s *ServiceImpl) dataRequestResponse(ctx context.Context, req *model.UserDataRequest) userDto.DataRequestResponse {
+	resp := userDto.DataRequestResponse{
+		ID:          req.ID,
+		UserID:      req.UserID,
+		Type:        req.Type,
+		Status:      req.Status,
+		Error:       req.Error,
+		CreatedAt:   req.CreatedAt,
+		StartedAt:   req.StartedAt,
+		CompletedAt: req.CompletedAt,
+	}
+	if req.FilePath != "" && s.disk != nil {
+		url, err := s.disk.TemporaryURL(ctx, req.FilePath, s.urlTTL)
+		if err != nil {
+			logger.Warnf("Failed to sign export URL of data request %d: %v", req.ID, err)
+		}
+		resp.DownloadURL = url
+	}
+	return resp
+}
+
+func dataRequestsUnavailableError() error {
+	return pkgErrors.UserService(pkgErrors.ErrCodeQueue).
+		Hint("Data requests need the job queue, which is not configured").
+		Errorf("data requests unavailable")
+}
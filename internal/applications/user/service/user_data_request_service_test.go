@@ -0,0 +1,104 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	userDto "ichi-go/internal/applications/user/dto"
+	userRepoMocks "ichi-go/internal/applications/user/repository/mocks"
+	"ichi-go/internal/infra/queue/rabbitmq"
+	producerMocks "ichi-go/internal/infra/queue/rabbitmq/mocks"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newDataRequestTestService(t *testing.T, granted ...string) (*ServiceImpl, *userRepoMocks.MockRepository, *userRepoMocks.MockDataRequestRepository, *producerMocks.MockMessageProducer) {
+	t.Helper()
+	svc, repo, _ := newAdminTestService(t, granted...)
+	dataRequests := userRepoMocks.NewMockDataRequestRepository(t)
+	producer := producerMocks.NewMockMessageProducer(t)
+	svc.dataRequests = dataRequests
+	svc.producer = producer
+	return svc, repo, dataRequests, producer
+}
+
+func TestRequestErasure_QueuesJobForOwnAccount(t *testing.T) {
+	svc, repo, dataRequests, producer := newDataRequestTestService(t)
+	repo.On("GetByIdWithDeleted", mock.Anything, uint64(7)).Return(testUser(7), nil)
+	dataRequests.On("FindActive", mock.Anything, uint64(7), model.DataRequestErasure).Return(nil, nil)
+	dataRequests.On("Create", mock.Anything, mock.AnythingOfType("*model.UserDataRequest")).
+		Run(func(args mock.Arguments) { args.Get(1).(*model.UserDataRequest).ID = 42 }).Return(nil)
+	producer.On("Publish", mock.Anything, userDto.DataErasureRoutingKey,
+		userDto.DataRequestMessage{RequestID: 42, UserID: 7}, rabbitmq.PublishOptions{}).Return(nil)
+
+	resp, err := svc.RequestErasure(context.Background(), caller(7), 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), resp.ID)
+	assert.Equal(t, model.DataRequestErasure, resp.Type)
+	assert.Equal(t, model.DataRequestPending, resp.Status)
+}
+
+func TestRequestErasure_OtherAccountNeedsPermission(t *testing.T) {
+	svc, _, _, _ := newDataRequestTestService(t)
+
+	_, err := svc.RequestErasure(context.Background(), caller(7), 8)
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+}
+
+func TestRequestErasure_RejectsWhileOneIsInProgress(t *testing.T) {
+	svc, repo, dataRequests, _ := newDataRequestTestService(t, "users:manage")
+	repo.On("GetByIdWithDeleted", mock.Anything, uint64(8)).Return(testUser(8), nil)
+	dataRequests.On("FindActive", mock.Anything, uint64(8), model.DataRequestErasure).
+		Return(&model.UserDataRequest{ID: 3, Status: model.DataRequestProcessing}, nil)
+
+	_, err := svc.RequestErasure(context.Background(), caller(7), 8)
+	assertErrorCode(t, err, pkgErrors.ErrCodeDataRequestBusy)
+}
+
+func TestRequestErasure_MarksRequestFailedWhenPublishFails(t *testing.T) {
+	svc, repo, dataRequests, producer := newDataRequestTestService(t)
+	repo.On("GetByIdWithDeleted", mock.Anything, uint64(7)).Return(testUser(7), nil)
+	dataRequests.On("FindActive", mock.Anything, uint64(7), model.DataRequestErasure).Return(nil, nil)
+	dataRequests.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { args.Get(1).(*model.UserDataRequest).ID = 42 }).Return(nil)
+	producer.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("broker down"))
+	dataRequests.On("MarkFailed", mock.Anything, int64(42), mock.AnythingOfType("string")).Return(nil)
+
+	_, err := svc.RequestErasure(context.Background(), caller(7), 7)
+	assertErrorCode(t, err, pkgErrors.ErrCodeQueue)
+}
+
+func TestRequestDataExport_NeedsStorage(t *testing.T) {
+	svc, _, _, _ := newDataRequestTestService(t)
+
+	_, err := svc.RequestDataExport(context.Background(), caller(7), 7)
+	assertErrorCode(t, err, pkgErrors.ErrCodeStorage)
+}
+
+func TestGetDataRequest_SignsDownloadURLOfCompletedExport(t *testing.T) {
+	svc, _, _, _ := newAvatarTestService(t)
+	dataRequests := userRepoMocks.NewMockDataRequestRepository(t)
+	svc.dataRequests = dataRequests
+	dataRequests.On("FindByID", mock.Anything, int64(42)).Return(&model.UserDataRequest{
+		ID: 42, UserID: 7, Type: model.DataRequestExport, Status: model.DataRequestCompleted,
+		FilePath: "exports/gdpr/7/42.zip",
+	}, nil)
+
+	resp, err := svc.GetDataRequest(context.Background(), caller(7), 7, 42)
+	require.NoError(t, err)
+	assert.Contains(t, resp.DownloadURL, "https://api.example.com/files/exports/gdpr/7/42.zip?")
+}
+
+func TestGetDataRequest_HidesRequestsOfOtherUsers(t *testing.T) {
+	svc, _, dataRequests, _ := newDataRequestTestService(t)
+	dataRequests.On("FindByID", mock.Anything, int64(42)).
+		Return(&model.UserDataRequest{ID: 42, UserID: 8, Type: model.DataRequestExport}, nil)
+
+	_, err := svc.GetDataRequest(context.Background(), caller(7), 7, 42)
+	assertErrorCode(t, err, pkgErrors.ErrCodeNotFound)
+}
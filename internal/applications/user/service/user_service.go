@@ -2,7 +2,13 @@ package user
 
 import (
 	"context"
+	userDto "ichi-go/internal/applications/user/dto"
+	user "ichi-go/internal/applications/user/repository"
+	"ichi-go/pkg/authenticator"
 	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/storage"
+	"mime/multipart"
+	"time"
 )
 
 type Service interface {
@@ -10,4 +16,42 @@ type Service interface {
 	Create(ctx context.Context, newUser model.User) (int64, error)
 	Update(ctx context.Context, updateUser model.User) (int64, error)
 	SendNotification(ctx context.Context, user model.User) error
+
+	// User administration; every method checks the caller may act on the user
+	ListUsers(ctx context.Context, authCtx authenticator.AuthContext, req userDto.UserListRequest) (*userDto.UserListResponse, error)
+	GetUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*userDto.UserGetResponse, error)
+	CreateUser(ctx context.Context, authCtx authenticator.AuthContext, req userDto.UserCreateRequest) (*userDto.UserGetResponse, error)
+	UpdateUser(ctx context.Context, authCtx authenticator.AuthContext, req userDto.UserUpdateRequest) (*userDto.UserGetResponse, error)
+	DeleteUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error
+	RestoreUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error
+	PurgeUser(ctx context.Context, authCtx authenticator.AuthContext, id uint64) error
+	UploadAvatar(ctx context.Context, authCtx authenticator.AuthContext, id uint64, file *multipart.FileHeader) (*userDto.UserGetResponse, error)
+	DeleteAvatar(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*userDto.UserGetResponse, error)
+
+	// GDPR data-subject requests, processed by queue jobs
+	RequestDataExport(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*userDto.DataRequestResponse, error)
+	RequestErasure(ctx context.Context, authCtx authenticator.AuthContext, id uint64) (*userDto.DataRequestResponse, error)
+	ListDataRequests(ctx context.Context, authCtx authenticator.AuthContext, id uint64) ([]userDto.DataRequestResponse, error)
+	GetDataRequest(ctx context.Context, authCtx authenticator.AuthContext, id uint64, requestID int64) (*userDto.DataRequestResponse, error)
+}
+
+// PermissionChecker decides whether a user holds an RBAC permission; satisfied by the RBAC EnforcementService
+type PermissionChecker interface {
+	CheckPermission(ctx context.Context, userID int64, tenantID string, resource string, action string) (bool, error)
+}
+
+// SessionRevoker signs a user out everywhere; satisfied by the auth service
+type SessionRevoker interface {
+	SignOutDeletedUser(ctx context.Context, userID uint64) error
+}
+
+// Options holds the optional dependencies of ServiceImpl
+type Options struct {
+	Permissions   PermissionChecker          // nil limits every user to their own account
+	DefaultTenant string                     // tenant of permission checks without tenant context
+	Storage       storage.Disk               // nil disables avatar uploads
+	AvatarRules   storage.UploadRules        // size and content types accepted as avatars
+	URLTTL        time.Duration              // lifetime of the avatar and export URLs in responses
+	DataRequests  user.DataRequestRepository // nil disables GDPR export and erasure requests
+	Sessions      SessionRevoker             // nil leaves deleted users signed in until their tokens expire
 }
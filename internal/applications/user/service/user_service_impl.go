@@ -9,14 +9,22 @@ import (
 	"ichi-go/pkg/clients/pokemonapi"
 	"ichi-go/pkg/clients/pokemonapi/dto"
 	"ichi-go/pkg/db/model"
+	"ichi-go/pkg/storage"
 	"time"
 )
 
 type ServiceImpl struct {
-	repo       user.Repository
-	cache      cache.Cache
-	pokeClient pokemonapi.PokemonClient
-	producer   rabbitmq.MessageProducer
+	repo          user.Repository
+	cache         cache.Cache
+	pokeClient    pokemonapi.PokemonClient
+	producer      rabbitmq.MessageProducer
+	permissions   PermissionChecker
+	defaultTenant string
+	disk          storage.Disk
+	avatarRules   storage.UploadRules
+	urlTTL        time.Duration
+	dataRequests  user.DataRequestRepository
+	sessions      SessionRevoker
 }
 
 // NewUserService creates a ServiceImpl configured with the provided repository, cache, Pokémon client, and message producer.
@@ -25,12 +33,20 @@ func NewUserService(
 	cache cache.Cache,
 	pokeClient pokemonapi.PokemonClient,
 	producer rabbitmq.MessageProducer, // Renamed from msgConnection
+	opts Options,
 ) *ServiceImpl {
 	return &ServiceImpl{
-		repo:       repo,
-		cache:      cache,
-		pokeClient: pokeClient,
-		producer:   producer,
+		repo:          repo,
+		cache:         cache,
+		pokeClient:    pokeClient,
+		producer:      producer,
+		permissions:   opts.Permissions,
+		defaultTenant: opts.DefaultTenant,
+		disk:          opts.Storage,
+		avatarRules:   opts.AvatarRules,
+		urlTTL:        opts.URLTTL,
+		sessions:      opts.Sessions,
+		dataRequests:  opts.DataRequests,
 	}
 }
 
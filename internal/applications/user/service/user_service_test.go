@@ -0,0 +1,150 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	userDto "ichi-go/internal/applications/user/dto"
+	userRepo "ichi-go/internal/applications/user/repository"
+	userRepoMocks "ichi-go/internal/applications/user/repository/mocks"
+	cacheMocks "ichi-go/internal/infra/cache/mocks"
+	"ichi-go/pkg/authenticator"
+	"ichi-go/pkg/db/model"
+	pkgErrors "ichi-go/pkg/errors"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePermissions grants the listed "resource:action" permissions to every user
+type fakePermissions struct {
+	granted map[string]bool
+	tenants []string
+}
+
+func (f *fakePermissions) CheckPermission(_ context.Context, _ int64, tenantID, resource, action string) (bool, error) {
+	f.tenants = append(f.tenants, tenantID)
+	return f.granted[resource+":"+action], nil
+}
+
+func newAdminTestService(t *testing.T, granted ...string) (*ServiceImpl, *userRepoMocks.MockRepository, *cacheMocks.MockCache) {
+	t.Helper()
+	repo := userRepoMocks.NewMockRepository(t)
+	cache := cacheMocks.NewMockCache(t)
+	perms := &fakePermissions{granted: map[string]bool{}}
+	for _, p := range granted {
+		perms.granted[p] = true
+	}
+	svc := NewUserService(repo, cache, nil, nil, Options{Permissions: perms, DefaultTenant: "default"})
+	return svc, repo, cache
+}
+
+func caller(id uint64) authenticator.AuthContext {
+	return authenticator.AuthContext{UserID: authenticator.UserSubject{ID: id}}
+}
+
+func testUser(id int64) *model.User {
+	u := &model.User{Name: "jane", Email: "jane@example.com"}
+	u.ID = id
+	return u
+}
+
+func assertErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+	require.Error(t, err)
+	oopsErr, ok := oops.AsOops(err)
+	require.True(t, ok, "expected an oops error, got %v", err)
+	assert.Equal(t, code, oopsErr.Code())
+}
+
+func TestGetUser_OwnAccountNeedsNoPermission(t *testing.T) {
+	svc, repo, _ := newAdminTestService(t)
+	repo.On("GetById", mock.Anything, uint64(7)).Return(testUser(7), nil)
+
+	resp, err := svc.GetUser(context.Background(), caller(7), 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.ID)
+}
+
+func TestGetUser_OtherAccountNeedsPermission(t *testing.T) {
+	svc, _, _ := newAdminTestService(t)
+
+	_, err := svc.GetUser(context.Background(), caller(7), 8)
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+}
+
+func TestGetUser_PermissionCheckedInDefaultTenant(t *testing.T) {
+	svc, repo, _ := newAdminTestService(t, "users:view")
+	repo.On("GetById", mock.Anything, uint64(8)).Return(testUser(8), nil)
+
+	_, err := svc.GetUser(context.Background(), caller(7), 8)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default"}, svc.permissions.(*fakePermissions).tenants)
+}
+
+func TestUpdateUser_APIKeyScopeLimitsPermission(t *testing.T) {
+	svc, _, _ := newAdminTestService(t, "users:edit")
+	authCtx := caller(7)
+	authCtx.APIKey = &authenticator.APIKeyIdentity{OwnerID: 7, Scopes: []string{"users:view"}}
+
+	_, err := svc.UpdateUser(context.Background(), authCtx, userDto.UserUpdateRequest{ID: 7, Name: "jane", Email: "jane@example.com"})
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+}
+
+func TestDeleteUser_CannotDeleteOwnAccount(t *testing.T) {
+	svc, _, _ := newAdminTestService(t, "users:delete")
+
+	err := svc.DeleteUser(context.Background(), caller(7), 7)
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+}
+
+// fakeSessions records the users signed out
+type fakeSessions struct{ signedOut []uint64 }
+
+func (f *fakeSessions) SignOutDeletedUser(_ context.Context, userID uint64) error {
+	f.signedOut = append(f.signedOut, userID)
+	return nil
+}
+
+func TestDeleteUser_SoftDeletesAndForgetsCachedUser(t *testing.T) {
+	svc, repo, cache := newAdminTestService(t, "users:delete")
+	sessions := &fakeSessions{}
+	svc.sessions = sessions
+	repo.On("GetById", mock.Anything, uint64(8)).Return(testUser(8), nil)
+	repo.On("SoftDelete", mock.Anything, int64(8)).Return(nil)
+	cache.On("Delete", mock.Anything, "user:8").Return(true, nil)
+
+	require.NoError(t, svc.DeleteUser(context.Background(), caller(7), 8))
+	assert.Equal(t, []uint64{8}, sessions.signedOut, "the deleted user is signed out")
+}
+
+func TestPurgeUser_NeedsManagePermission(t *testing.T) {
+	svc, _, _ := newAdminTestService(t, "users:delete")
+
+	err := svc.PurgeUser(context.Background(), caller(7), 8)
+	assertErrorCode(t, err, pkgErrors.ErrCodeForbidden)
+}
+
+func TestListUsers_PagesResults(t *testing.T) {
+	svc, repo, _ := newAdminTestService(t, "users:view")
+	repo.On("List", mock.Anything, mock.MatchedBy(func(f userRepo.ListFilter) bool {
+		return f.Page == 2 && f.PerPage == defaultPageSize && f.Name == "jane" && f.SortDesc &&
+			f.EmailVerified != nil && *f.EmailVerified
+	})).Return([]*model.User{testUser(21), testUser(22)}, 45, nil)
+
+	resp, err := svc.ListUsers(context.Background(), caller(7), userDto.UserListRequest{
+		Name:          " jane ",
+		EmailVerified: "true",
+		SortDir:       "desc",
+		Page:          2,
+		PageSize:      500,
+	})
+	require.NoError(t, err)
+	assert.Len(t, resp.Users, 2)
+	assert.Equal(t, 2, resp.Page)
+	assert.Equal(t, defaultPageSize, resp.PageSize)
+	assert.Equal(t, 3, resp.TotalPages)
+	assert.Equal(t, 45, resp.TotalCount)
+}
@@ -2,11 +2,11 @@ package watcher
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"ichi-go/internal/infra/authz/cache"
+	"ichi-go/internal/infra/queue/codec"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	"ichi-go/pkg/logger"
 )
@@ -106,7 +106,7 @@ func (w *RabbitMQWatcher) handleMessage(ctx context.Context, body []byte) error
 	var event RBACEvent
 
 	// Parse event
-	if err := json.Unmarshal(body, &event); err != nil {
+	if err := codec.Unmarshal(ctx, body, &event); err != nil {
 		logger.WithContext(ctx).Errorf("Failed to parse RBAC event: %v", err)
 		return nil // Don't retry bad JSON (permanent failure)
 	}
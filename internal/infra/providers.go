@@ -1,7 +1,9 @@
 package infra
 
 import (
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -20,8 +22,10 @@ import (
 	"ichi-go/internal/infra/queue"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	riverimpl "ichi-go/internal/infra/queue/river"
+	"ichi-go/pkg/authenticator"
 	"ichi-go/pkg/logger"
 	"ichi-go/pkg/rbac"
+	"ichi-go/pkg/storage"
 )
 
 func Setup(injector do.Injector, cfg *config.Config) {
@@ -30,6 +34,15 @@ func Setup(injector do.Injector, cfg *config.Config) {
 	// Core infrastructure
 	provideDatabases(injector, cfg)
 	do.Provide(injector, provideCache(cfg))
+	do.Provide(injector, provideRevocationStore)
+	do.Provide(injector, provideLoginAttemptStore)
+	do.Provide(injector, provideOIDCStateStore)
+	do.Provide(injector, provideMFAChallengeStore)
+	do.Provide(injector, provideRateLimitStore)
+
+	// File storage
+	do.Provide(injector, provideURLSigner(cfg))
+	do.Provide(injector, provideStorage(cfg))
 
 	// Queue: named + unnamed providers for all enabled connections
 	provideQueueInfra(injector, cfg)
@@ -89,6 +102,99 @@ func provideCache(cfg *config.Config) func(do.Injector) (*redis.Client, error) {
 	}
 }
 
+// provideRevocationStore backs JWT logout with Redis. Without Redis it returns nil
+// and tokens stay valid until they expire.
+func provideRevocationStore(i do.Injector) (authenticator.RevocationStore, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil || redisClient == nil {
+		logger.Warnf("Redis not available for token revocation: %v", err)
+		return nil, nil
+	}
+	logger.Debugf("initialized token revocation store")
+	return authenticator.NewRedisRevocationStore(redisClient), nil
+}
+
+// provideLoginAttemptStore backs login brute-force protection with Redis.
+// Without Redis it returns nil and login attempts are not throttled.
+func provideLoginAttemptStore(i do.Injector) (authenticator.LoginAttemptStore, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil || redisClient == nil {
+		logger.Warnf("Redis not available for login throttling: %v", err)
+		return nil, nil
+	}
+	return authenticator.NewRedisLoginAttemptStore(redisClient), nil
+}
+
+// provideOIDCStateStore keeps started OpenID Connect logins in Redis.
+// Without Redis it returns nil and the auth domain falls back to process memory.
+func provideOIDCStateStore(i do.Injector) (authenticator.OIDCStateStore, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil || redisClient == nil {
+		logger.Warnf("Redis not available for OIDC login state: %v", err)
+		return nil, nil
+	}
+	return authenticator.NewRedisOIDCStateStore(redisClient), nil
+}
+
+// provideMFAChallengeStore keeps pending MFA login steps in Redis.
+// Without Redis it returns nil and the auth domain falls back to process memory.
+func provideMFAChallengeStore(i do.Injector) (authenticator.MFAChallengeStore, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil || redisClient == nil {
+		logger.Warnf("Redis not available for MFA challenges: %v", err)
+		return nil, nil
+	}
+	return authenticator.NewRedisMFAChallengeStore(redisClient), nil
+}
+
+// provideRateLimitStore counts rate-limited auth actions in Redis.
+// Without Redis it returns nil and the auth domain falls back to process memory.
+func provideRateLimitStore(i do.Injector) (authenticator.RateLimitStore, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil || redisClient == nil {
+		logger.Warnf("Redis not available for auth rate limits: %v", err)
+		return nil, nil
+	}
+	return authenticator.NewRedisRateLimitStore(redisClient), nil
+}
+
+// provideURLSigner signs the temporary download URLs of the local and memory disks.
+// Without a configured signing key it uses a random one, so links stop working on restart.
+func provideURLSigner(cfg *config.Config) func(do.Injector) (*storage.URLSigner, error) {
+	return func(i do.Injector) (*storage.URLSigner, error) {
+		storageCfg := cfg.Storage()
+		key := storageCfg.SigningKey
+		if key == "" {
+			logger.Warnf("⚠️  storage signing_key not set, temporary download URLs will not survive a restart")
+			random := make([]byte, 32)
+			if _, err := rand.Read(random); err != nil {
+				return nil, fmt.Errorf("storage: generate signing key: %w", err)
+			}
+			key = hex.EncodeToString(random)
+		}
+		return storage.NewURLSigner(storageCfg.BaseURL, key)
+	}
+}
+
+// provideStorage returns the configured disk. An invalid storage configuration disables
+// file handling instead of failing startup; callers treat a nil disk as unavailable.
+func provideStorage(cfg *config.Config) func(do.Injector) (storage.Disk, error) {
+	return func(i do.Injector) (storage.Disk, error) {
+		signer, err := do.Invoke[*storage.URLSigner](i)
+		if err != nil {
+			logger.Errorf("❌ File storage disabled: %v", err)
+			return nil, nil
+		}
+		disk, err := storage.New(*cfg.Storage(), signer)
+		if err != nil {
+			logger.Errorf("❌ File storage disabled: %v", err)
+			return nil, nil
+		}
+		logger.Debugf("initialized file storage (driver=%s)", cfg.Storage().Driver)
+		return disk, nil
+	}
+}
+
 // provideQueueInfra registers named DI providers for every enabled queue connection,
 // then provides unnamed backward-compat aliases pointing at the default connection.
 func provideQueueInfra(injector do.Injector, cfg *config.Config) {
@@ -0,0 +1,189 @@
+package codec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Content types written to the AMQP ContentType property.
+const (
+	ContentTypeJSON    = "application/json"
+	ContentTypeMsgpack = "application/msgpack"
+	ContentTypeRaw     = "application/octet-stream"
+)
+
+// Codec serialises job payloads for the wire.
+//
+// Producers encode exactly once with the codec selected for the connection or
+// the individual job; consumers decode with the codec matching the message
+// ContentType, so producers using different codecs can share a queue.
+type Codec interface {
+	// Name is the config identifier ("json", "msgpack", "raw").
+	Name() string
+	// ContentType is published as the message ContentType property.
+	ContentType() string
+	Encode(v any) ([]byte, error)
+	Decode(data []byte, v any) error
+}
+
+var (
+	JSON    Codec = jsonCodec{}
+	Msgpack Codec = msgpackCodec{}
+	Raw     Codec = rawCodec{}
+)
+
+var (
+	byName = map[string]Codec{
+		JSON.Name():    JSON,
+		Msgpack.Name(): Msgpack,
+		Raw.Name():     Raw,
+	}
+	byContentType = map[string]Codec{
+		ContentTypeJSON:         JSON,
+		"text/json":             JSON,
+		ContentTypeMsgpack:      Msgpack,
+		"application/x-msgpack": Msgpack,
+		ContentTypeRaw:          Raw,
+	}
+)
+
+// ByName resolves a codec from its config name. Empty defaults to JSON.
+func ByName(name string) (Codec, error) {
+	if name == "" {
+		return JSON, nil
+	}
+	c, ok := byName[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown codec %q (valid: json, msgpack, raw)", name)
+	}
+	return c, nil
+}
+
+// ForContentType resolves a codec from a message ContentType.
+// Parameters such as "; charset=utf-8" are ignored. Empty or unknown content
+// types fall back to JSON, which is what every producer sent before codecs existed.
+func ForContentType(contentType string) Codec {
+	if contentType == "" {
+		return JSON
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	if c, ok := byContentType[mediaType]; ok {
+		return c
+	}
+	return JSON
+}
+
+type contentTypeKey struct{}
+
+// WithContentType stores the ContentType of the message being consumed.
+// Transports call this before invoking a ConsumeFunc.
+func WithContentType(ctx context.Context, contentType string) context.Context {
+	return context.WithValue(ctx, contentTypeKey{}, contentType)
+}
+
+// ContentTypeFromContext returns the ContentType stored by WithContentType.
+func ContentTypeFromContext(ctx context.Context) string {
+	ct, _ := ctx.Value(contentTypeKey{}).(string)
+	return ct
+}
+
+// FromContext returns the codec matching the message being consumed (JSON when unknown).
+func FromContext(ctx context.Context) Codec {
+	return ForContentType(ContentTypeFromContext(ctx))
+}
+
+// Unmarshal decodes a consumed message body into v using the codec selected
+// by the message ContentType carried in ctx.
+//
+// Consumers use this instead of json.Unmarshal:
+//
+//	var event dto.NotificationEvent
+//	if err := codec.Unmarshal(ctx, body, &event); err != nil {
+//	    return nil // permanent — don't retry undecodable payloads
+//	}
+func Unmarshal(ctx context.Context, body []byte, v any) error {
+	return FromContext(ctx).Decode(body, v)
+}
+
+// jsonCodec is the default codec.
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string        { return "json" }
+func (jsonCodec) ContentType() string { return ContentTypeJSON }
+
+// Encode passes pre-encoded JSON (json.RawMessage) through unchanged.
+func (jsonCodec) Encode(v any) ([]byte, error) {
+	if raw, ok := v.(json.RawMessage); ok {
+		return raw, nil
+	}
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Decode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// msgpackCodec honours `json` struct tags so field names match the JSON codec
+// and a struct can be decoded regardless of which codec the producer picked.
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string        { return "msgpack" }
+func (msgpackCodec) ContentType() string { return ContentTypeMsgpack }
+
+func (msgpackCodec) Encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Decode(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// rawCodec publishes bytes exactly as given. Only []byte, string and
+// json.RawMessage payloads are accepted; decoding requires *[]byte or *string.
+type rawCodec struct{}
+
+func (rawCodec) Name() string        { return "raw" }
+func (rawCodec) ContentType() string { return ContentTypeRaw }
+
+func (rawCodec) Encode(v any) ([]byte, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case json.RawMessage:
+		return b, nil
+	case string:
+		return []byte(b), nil
+	default:
+		return nil, fmt.Errorf("raw codec: unsupported payload type %T (want []byte or string)", v)
+	}
+}
+
+func (rawCodec) Decode(data []byte, v any) error {
+	switch dst := v.(type) {
+	case *[]byte:
+		*dst = append((*dst)[:0], data...)
+		return nil
+	case *string:
+		*dst = string(data)
+		return nil
+	default:
+		return fmt.Errorf("raw codec: unsupported decode target %T (want *[]byte or *string)", v)
+	}
+}
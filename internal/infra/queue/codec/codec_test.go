@@ -0,0 +1,84 @@
+package codec_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ichi-go/internal/infra/queue/codec"
+)
+
+type event struct {
+	EventID string         `json:"event_id"`
+	UserID  string         `json:"user_id,omitempty"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+func TestByName(t *testing.T) {
+	c, err := codec.ByName("")
+	require.NoError(t, err)
+	assert.Equal(t, codec.JSON, c)
+
+	c, err = codec.ByName("MsgPack")
+	require.NoError(t, err)
+	assert.Equal(t, codec.Msgpack, c)
+
+	_, err = codec.ByName("xml")
+	assert.ErrorContains(t, err, "unknown codec")
+}
+
+func TestForContentType(t *testing.T) {
+	assert.Equal(t, codec.JSON, codec.ForContentType(""))
+	assert.Equal(t, codec.JSON, codec.ForContentType("application/json; charset=utf-8"))
+	assert.Equal(t, codec.Msgpack, codec.ForContentType("application/x-msgpack"))
+	assert.Equal(t, codec.Raw, codec.ForContentType(codec.ContentTypeRaw))
+	assert.Equal(t, codec.JSON, codec.ForContentType("text/plain"), "unknown types fall back to JSON")
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := event{EventID: "evt-1", UserID: "42", Data: map[string]any{"order_id": "ord_1"}}
+
+	for _, c := range []codec.Codec{codec.JSON, codec.Msgpack} {
+		t.Run(c.Name(), func(t *testing.T) {
+			body, err := c.Encode(in)
+			require.NoError(t, err)
+
+			var out event
+			ctx := codec.WithContentType(context.Background(), c.ContentType())
+			require.NoError(t, codec.Unmarshal(ctx, body, &out))
+			assert.Equal(t, in, out)
+		})
+	}
+}
+
+func TestPreEncodedPayloadsPassThrough(t *testing.T) {
+	// Pre-encoded payloads must not be re-marshalled into a base64 string.
+	body, err := codec.JSON.Encode(json.RawMessage(`{"a":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(body))
+
+	body, err = codec.Raw.Encode([]byte(`{"a":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(body))
+}
+
+func TestRaw(t *testing.T) {
+	_, err := codec.Raw.Encode(event{})
+	assert.ErrorContains(t, err, "unsupported payload type")
+
+	var out []byte
+	require.NoError(t, codec.Raw.Decode([]byte("hello"), &out))
+	assert.Equal(t, []byte("hello"), out)
+
+	var s string
+	require.NoError(t, codec.Raw.Decode([]byte("hello"), &s))
+	assert.Equal(t, "hello", s)
+}
+
+func TestUnmarshal_DefaultsToJSON(t *testing.T) {
+	var out event
+	require.NoError(t, codec.Unmarshal(context.Background(), []byte(`{"event_id":"e"}`), &out))
+	assert.Equal(t, "e", out.EventID)
+}
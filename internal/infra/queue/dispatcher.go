@@ -3,7 +3,6 @@ package queue
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -33,7 +32,8 @@ func NewDispatcher(driver string, producer rabbitmq.MessageProducer, riverClient
 }
 
 // rabbitMQDispatcher implements Dispatcher using the existing RabbitMQ producer.
-// Serialises the job to JSON and publishes with routing_key = job.Kind().
+// Publishes with routing_key = job.Kind(); the producer serialises the job once
+// with the per-job codec (WithCodec) or the connection's publisher codec.
 type rabbitMQDispatcher struct {
 	producer rabbitmq.MessageProducer
 }
@@ -43,21 +43,20 @@ func (d *rabbitMQDispatcher) Dispatch(ctx context.Context, job JobArgs, opts ...
 
 	// RabbitMQ does not support routing by queue name, attempt limiting, or priority
 	// via the generic Dispatch interface. Fail fast so callers see the mismatch immediately.
-	if o.Queue != "" || o.MaxAttempts != 0 || o.Priority != 0 {
+	// Defaults from ApplyOptions are not caller intent, so only overrides are rejected.
+	def := ApplyOptions()
+	if o.Queue != def.Queue || o.MaxAttempts != def.MaxAttempts || o.Priority != def.Priority {
 		return fmt.Errorf(
 			"rabbitmq dispatcher: unsupported options for job %q (Queue=%q, MaxAttempts=%d, Priority=%d); "+
-				"AMQP dispatch only supports Delay",
+				"AMQP dispatch only supports Delay and WithCodec",
 			job.Kind(), o.Queue, o.MaxAttempts, o.Priority,
 		)
 	}
 
-	payload, err := json.Marshal(job)
-	if err != nil {
-		return fmt.Errorf("rabbitmq dispatcher: failed to marshal job %q: %w", job.Kind(), err)
-	}
-
-	return d.producer.Publish(ctx, job.Kind(), payload, rabbitmq.PublishOptions{
+	// Pass the job itself — marshalling here as well would double-encode it.
+	return d.producer.Publish(ctx, job.Kind(), job, rabbitmq.PublishOptions{
 		Delay: o.Delay,
+		Codec: o.Codec,
 	})
 }
 
@@ -70,6 +69,16 @@ type riverDispatcher struct {
 func (d *riverDispatcher) Dispatch(ctx context.Context, job JobArgs, opts ...DispatchOption) error {
 	o := ApplyOptions(opts...)
 
+	// River stores job args as JSON and typed workers decode them as JSON, so a
+	// per-job codec cannot be honoured here. Fail fast instead of ignoring it.
+	if o.Codec != nil {
+		return fmt.Errorf(
+			"river dispatcher: unsupported option for job %q (Codec=%q); "+
+				"database dispatch always encodes job args as JSON",
+			job.Kind(), o.Codec.Name(),
+		)
+	}
+
 	insertOpts := &riverqueue.InsertOpts{
 		Queue:       o.Queue,
 		MaxAttempts: o.MaxAttempts,
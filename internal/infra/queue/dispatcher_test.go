@@ -2,13 +2,15 @@ package queue_test
 
 import (
 	"context"
-	"encoding/json"
 	"testing"
 	"time"
 
+	riverqueue "github.com/riverqueue/river"
+	"github.com/riverqueue/river/riverdriver/riverdatabasesql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"ichi-go/internal/infra/queue"
+	"ichi-go/internal/infra/queue/codec"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	mocks "ichi-go/internal/infra/queue/rabbitmq/mocks"
 )
@@ -38,12 +40,14 @@ func TestNewDispatcher_Database_NilClient(t *testing.T) {
 func TestAMQPDispatcher_Dispatch(t *testing.T) {
 	producer := mocks.NewMockMessageProducer(t)
 
-	expectedPayload, _ := json.Marshal(emailJob{UserID: 1, Email: "a@b.com"})
+	// The job is handed to the producer as-is; the producer encodes it once.
 	producer.On("Publish",
 		mock.Anything,
 		"email.send",
-		expectedPayload,
-		mock.AnythingOfType("rabbitmq.PublishOptions"),
+		emailJob{UserID: 1, Email: "a@b.com"},
+		mock.MatchedBy(func(opts rabbitmq.PublishOptions) bool {
+			return opts.Codec == nil
+		}),
 	).Return(nil)
 
 	d, err := queue.NewDispatcher("amqp", producer, nil)
@@ -74,3 +78,45 @@ func TestAMQPDispatcher_Dispatch_WithDelay(t *testing.T) {
 	assert.NoError(t, err)
 	producer.AssertExpectations(t)
 }
+
+func TestAMQPDispatcher_Dispatch_WithCodec(t *testing.T) {
+	producer := mocks.NewMockMessageProducer(t)
+
+	producer.On("Publish",
+		mock.Anything,
+		"email.send",
+		emailJob{UserID: 1, Email: "a@b.com"},
+		mock.MatchedBy(func(opts rabbitmq.PublishOptions) bool {
+			return opts.Codec == codec.Msgpack
+		}),
+	).Return(nil)
+
+	d, err := queue.NewDispatcher("amqp", producer, nil)
+	assert.NoError(t, err)
+
+	err = d.Dispatch(context.Background(), emailJob{UserID: 1, Email: "a@b.com"},
+		queue.WithCodec(codec.Msgpack))
+	assert.NoError(t, err)
+}
+
+func TestAMQPDispatcher_Dispatch_UnsupportedOption(t *testing.T) {
+	producer := mocks.NewMockMessageProducer(t)
+
+	d, err := queue.NewDispatcher("amqp", producer, nil)
+	assert.NoError(t, err)
+
+	err = d.Dispatch(context.Background(), emailJob{UserID: 1}, queue.OnQueue("emails"))
+	assert.ErrorContains(t, err, "unsupported options")
+	producer.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRiverDispatcher_Dispatch_RejectsCodec(t *testing.T) {
+	client, err := riverqueue.NewClient(riverdatabasesql.New(nil), &riverqueue.Config{})
+	assert.NoError(t, err)
+
+	d, err := queue.NewDispatcher("database", nil, client)
+	assert.NoError(t, err)
+
+	err = d.Dispatch(context.Background(), emailJob{UserID: 1}, queue.WithCodec(codec.Msgpack))
+	assert.ErrorContains(t, err, "unsupported option")
+}
@@ -1,6 +1,10 @@
 package queue
 
-import "time"
+import (
+	"time"
+
+	"ichi-go/internal/infra/queue/codec"
+)
 
 // DispatchOptions holds resolved values after applying all DispatchOption funcs.
 type DispatchOptions struct {
@@ -8,6 +12,7 @@ type DispatchOptions struct {
 	Delay       time.Duration
 	MaxAttempts int
 	Priority    int
+	Codec       codec.Codec // nil → the connection's configured codec
 }
 
 // DispatchOption mutates DispatchOptions.
@@ -42,3 +47,8 @@ func Priority(p int) DispatchOption {
 	return func(o *DispatchOptions) { o.Priority = p }
 }
 
+// WithCodec encodes this job with c instead of the connection's codec.
+// Only the amqp driver supports it; the database driver rejects it.
+func WithCodec(c codec.Codec) DispatchOption {
+	return func(o *DispatchOptions) { o.Codec = c }
+}
@@ -62,6 +62,7 @@ type QueueConfig struct {
 
 type PublisherConfig struct {
 	ExchangeName string `yaml:"exchange_name" mapstructure:"exchange_name"`
+	Codec        string `yaml:"codec" mapstructure:"codec"` // json (default), msgpack, raw
 }
 
 func GetConsumerByName(config *Config, name string) (*ConsumerConfig, error) {
@@ -3,6 +3,7 @@ package rabbitmq
 import (
 	"context"
 	"fmt"
+	"ichi-go/internal/infra/queue/codec"
 	"ichi-go/pkg/logger"
 	"sync"
 
@@ -131,7 +132,9 @@ func (c *Consumer) Consume(ctx context.Context, handler ConsumeFunc) error {
 
 					// Process message
 					logger.Infof("⚙️  Processing message...")
-					if err := handler(ctx, delivery.Body); err != nil {
+					// Expose ContentType so handlers decode with the producer's codec
+					msgCtx := codec.WithContentType(ctx, delivery.ContentType)
+					if err := handler(msgCtx, delivery.Body); err != nil {
 						logger.Errorf("❌ Worker #%d: handler error: %v", workerID, err)
 
 						if !c.consumerConfig.AutoAck {
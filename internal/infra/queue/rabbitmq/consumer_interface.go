@@ -11,11 +11,14 @@ import "context"
 // - Return NIL for permanent failures (will skip):
 //   - Invalid JSON, unknown event, validation failure
 //
+// The message ContentType is carried in ctx; decode with codec.Unmarshal so
+// JSON, msgpack and raw producers all work against the same consumer.
+//
 // Example:
 //
 //	func (c *Consumer) Consume(ctx context.Context, body []byte) error {
 //	    var event Event
-//	    if err := json.Unmarshal(body, &event); err != nil {
+//	    if err := codec.Unmarshal(ctx, body, &event); err != nil {
 //	        return nil // Don't retry bad JSON
 //	    }
 //
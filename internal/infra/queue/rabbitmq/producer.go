@@ -2,8 +2,8 @@ package rabbitmq
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"ichi-go/internal/infra/queue/codec"
 	"ichi-go/pkg/logger"
 	"sync"
 	"time"
@@ -16,6 +16,7 @@ type Producer struct {
 	connection   *Connection
 	config       Config
 	exchangeName string
+	codec        codec.Codec
 	channel      *amqp.Channel
 	confirms     chan amqp.Confirmation
 	mu           sync.Mutex
@@ -26,14 +27,21 @@ type PublishOptions struct {
 	Headers   amqp.Table    // Custom metadata
 	Delay     time.Duration // Delivery delay
 	Mandatory bool          // Return error if no queue is bound
+	Codec     codec.Codec   // Overrides the producer's codec for this message
 }
 
 // NewProducer creates message producer.
 func NewProducer(connection *Connection, config Config) (MessageProducer, error) {
+	c, err := codec.ByName(config.Publisher.Codec)
+	if err != nil {
+		return nil, fmt.Errorf("producer: %w", err)
+	}
+
 	p := &Producer{
 		connection:   connection,
 		config:       config,
 		exchangeName: config.Publisher.ExchangeName,
+		codec:        c,
 		confirms:     make(chan amqp.Confirmation, 1),
 	}
 
@@ -84,8 +92,14 @@ func (p *Producer) Publish(ctx context.Context, routingKey string, message inter
 	logger.Infof("   Routing Key: '%s'", routingKey)
 	logger.Infof("   Message Type: %T", message)
 
-	// Serialize
-	body, err := json.Marshal(message)
+	// Serialize — exactly once, with the per-message codec or the producer default
+	c := p.codec
+	if opts.Codec != nil {
+		c = opts.Codec
+	}
+	logger.Infof("   Content Type: %s", c.ContentType())
+
+	body, err := c.Encode(message)
 	if err != nil {
 		logger.Errorf("❌ Failed to marshal message: %v", err)
 		return fmt.Errorf("failed to marshal: %w", err)
@@ -124,7 +138,7 @@ func (p *Producer) Publish(ctx context.Context, routingKey string, message inter
 		mandatory, // Set to true to get errors if message can't be routed
 		false,     // immediate
 		amqp.Publishing{
-			ContentType:  "application/json",
+			ContentType:  c.ContentType(),
 			Body:         body,
 			DeliveryMode: amqp.Persistent,
 			Timestamp:    time.Now(),
@@ -12,10 +12,20 @@ import (
 	"ichi-go/internal/applications/notification/services"
 	orderConsumers "ichi-go/internal/applications/order/consumers"
 	userConsumers "ichi-go/internal/applications/user/consumers"
+	userRepo "ichi-go/internal/applications/user/repository"
+	"ichi-go/internal/infra/cache"
 	"ichi-go/internal/infra/queue/rabbitmq"
 	"ichi-go/pkg/logger"
+	"ichi-go/pkg/notification/audience"
+	"ichi-go/pkg/notification/digest"
+	"ichi-go/pkg/notification/email"
 	"ichi-go/pkg/notification/fcm"
+	"ichi-go/pkg/notification/preference"
+	"ichi-go/pkg/notification/realtime"
+	"ichi-go/pkg/notification/sms"
 	notiftemplate "ichi-go/pkg/notification/template"
+	"ichi-go/pkg/notification/webhook"
+	"ichi-go/pkg/storage"
 )
 
 // ConsumerRegistration links consumer name to processing function.
@@ -48,31 +58,149 @@ func GetRegisteredConsumers(injector do.Injector) []ConsumerRegistration {
 	// Build renderer and log repo (nil-safe: if DB is unavailable, logs are skipped).
 	var renderer *services.TemplateRenderer
 	var logRepo *repositories.NotificationLogRepository
+	var dataRequestRepo userRepo.DataRequestRepository
+	var deviceStore notifChannels.DeviceTokenStore
+	var userLookup notifChannels.UserLookup
+	var smsRecorder notifChannels.SMSMessageRecorder
+	var webhookEndpoints *repositories.WebhookEndpointRepository
+	var webhookDeliveries *repositories.WebhookDeliveryRepository
+	var inboxStore notifChannels.InboxStore
+	var campaignStatus notifConsumers.CampaignStatusReader
+	var campaignReader notifConsumers.CampaignReader
+	var audienceResolver notifConsumers.AudienceResolver
+	var blastCheckpoints notifConsumers.BlastCheckpointStore
 	if db != nil {
+		deviceStore = repositories.NewUserDeviceRepository(db)
+		userLookup = userRepo.NewUserRepository(db)
+		dataRequestRepo = userRepo.NewDataRequestRepository(db)
 		overrideRepo := repositories.NewNotificationTemplateOverrideRepository(db)
 		logRepo = repositories.NewNotificationLogRepository(db)
+		smsRecorder = logRepo
+		webhookEndpoints = repositories.NewWebhookEndpointRepository(db)
+		webhookDeliveries = repositories.NewWebhookDeliveryRepository(db)
+		inboxStore = repositories.NewInAppNotificationRepository(db)
+		campaigns := repositories.NewNotificationCampaignRepository(db)
+		campaignStatus = campaigns
+		campaignReader = campaigns
+		audienceResolver = repositories.NewAudienceRepository(db)
+		blastCheckpoints = repositories.NewNotificationBlastCheckpointRepository(db)
 		if registry != nil {
 			renderer = services.NewTemplateRenderer(registry, overrideRepo)
 		}
 	}
 
-	// Resolve FCM-backed push channel (may be nil if FCM disabled).
+	// Resolve FCM-backed push channel (may be nil if FCM disabled); tokens come from the device registry.
 	fcmClient, _ := do.Invoke[*fcm.Client](injector)
-	pushChannel := notifChannels.NewPushChannel(fcmClient)
+	pushChannel := notifChannels.NewPushChannel(fcmClient, deviceStore)
+
+	// Resolve SMTP-backed email channel (may be nil if email disabled); recipients come from the users table.
+	emailClient, _ := do.Invoke[*email.Client](injector)
+	emailConfig, err := email.LoadConfig()
+	if err != nil {
+		logger.Warnf("[queue] %v; email layout and List-Unsubscribe disabled", err)
+	}
+	emailOpts, err := notifChannels.NewEmailOptions(emailConfig)
+	if err != nil {
+		logger.Warnf("[queue] %v; sending text-only emails", err)
+	}
+	// Unsubscribe links carry a token signed with the secret the public unsubscribe API verifies.
+	preferenceConfig, err := preference.LoadConfig()
+	if err != nil {
+		logger.Warnf("[queue] %v; notification preferences ignored and unsubscribe links disabled", err)
+	}
+	unsubscribeSigner := preference.NewSigner(preferenceConfig.UnsubscribeSecret)
+	emailOpts.UnsubscribeSigner = unsubscribeSigner
+	emailChannel := notifChannels.NewEmailChannel(emailClient, userLookup, emailOpts)
+
+	// Resolve SMS channel (may be nil if SMS disabled); provider message IDs go to notification logs.
+	smsClient, _ := do.Invoke[*sms.Client](injector)
+	smsConfig, err := sms.LoadConfig()
+	if err != nil {
+		logger.Warnf("[queue] %v; SMS phone normalisation and segment limit use defaults", err)
+	}
+	smsChannel := notifChannels.NewSMSChannel(smsClient, smsRecorder, smsConfig)
 
 	// Resolve Redis client for idempotency guard (may be nil if Redis is unavailable).
 	redisClient, _ := do.Invoke[*redis.Client](injector)
+	var userCache cache.Cache
+	if redisClient != nil {
+		userCache = cache.NewCache(redisClient)
+	}
+
+	// File storage holds GDPR export archives (may be nil if storage is misconfigured).
+	disk, _ := do.Invoke[storage.Disk](injector)
 
 	// Build blast/user producers directly from the RabbitMQ connection.
 	// This avoids importing the notification application package (which would create a cycle).
 	conn, _ := do.Invoke[*rabbitmq.Connection](injector)
 	blastProducer := buildExchangeProducer(conn, "notification.blast")
 	userProducer := buildExchangeProducer(conn, "notification.user")
+	eventsProducer := buildExchangeProducer(conn, "app.events")
+
+	// Resolve webhook client (may be nil if webhooks disabled); the channel queues one
+	// delivery job per endpoint on app.events and the webhook consumer sends it.
+	webhookClient, _ := do.Invoke[*webhook.Client](injector)
+	webhookConfig, err := webhook.LoadConfig()
+	if err != nil {
+		logger.Warnf("[queue] %v; webhook deliveries disabled", err)
+	}
+	// Typed-nil guards: a nil repository must not become a non-nil interface.
+	var webhookLister notifChannels.WebhookEndpointLister
+	var webhookStore notifConsumers.WebhookEndpointStore
+	var webhookLog notifConsumers.WebhookDeliveryLog
+	if webhookEndpoints != nil {
+		webhookLister = webhookEndpoints
+		webhookStore = webhookEndpoints
+		webhookLog = webhookDeliveries
+	}
+	webhookChannel := notifChannels.NewWebhookChannel(webhookLister, eventsProducer, webhookConfig, viper.GetString("rbac.default_tenant"))
+
+	// Resolve the realtime hub shared with the inbox API (may be nil if in-app is disabled),
+	// so notifications stored here reach the streams open on this process and, over Redis, on others.
+	inAppConfig, err := realtime.LoadConfig()
+	if err != nil {
+		logger.Warnf("[queue] %v; in-app notifications disabled", err)
+	}
+	hub, _ := do.Invoke[*realtime.Hub](injector)
+	inAppChannel := notifChannels.NewInAppChannel(inboxStore, hub, inAppConfig)
+
+	// User preferences decide which channels a targeted notification uses; quiet hours
+	// re-publish deferred channels on app.events (nil without a DB: every channel is used).
+	var preferences *services.PreferenceService
+	if db != nil && registry != nil {
+		preferences = services.NewPreferenceService(
+			repositories.NewNotificationPreferenceRepository(db),
+			registry,
+			eventsProducer,
+			unsubscribeSigner,
+			preferenceConfig,
+		)
+	}
+
+	// Digest policies buffer the events of busy users in Redis; the flush of each window
+	// is a delayed event on app.events (nil without Redis or the queue: events are sent on their own).
+	digestConfig, err := digest.LoadConfig()
+	if err != nil {
+		logger.Warnf("[queue] %v; notification digests disabled", err)
+	}
+	var digests *services.DigestService
+	if redisClient != nil && eventsProducer != nil && registry != nil {
+		digests = services.NewDigestService(registry, digest.NewRedisStore(redisClient), eventsProducer, digestConfig)
+	}
+
+	// Blasts fan out to per-user messages on notification.user in checkpointed batches.
+	audienceConfig, err := audience.LoadConfig()
+	if err != nil {
+		logger.Warnf("[queue] %v; blasts fan out in default batches", err)
+	}
 
 	// Shared channel set available to both blast and user consumers.
 	chs := []notifChannels.NotificationChannel{
-		notifChannels.NewEmailChannel(),
+		emailChannel,
 		pushChannel,
+		smsChannel,
+		webhookChannel,
+		inAppChannel,
 	}
 
 	return []ConsumerRegistration{
@@ -88,27 +216,61 @@ func GetRegisteredConsumers(injector do.Injector) []ConsumerRegistration {
 			ConsumeFunc: userConsumers.NewWelcomeNotificationConsumer().Consume,
 			Description: "Sends welcome notifications to new users",
 		},
+		// GDPR data-subject requests: export archive and erasure
+		{
+			Name:        "user_data_export",
+			ConsumeFunc: userConsumers.NewDataExportConsumer(dataRequestRepo, disk).Consume,
+			Description: "Builds the GDPR export archive of a user",
+		},
+		{
+			Name:        "user_data_erasure",
+			ConsumeFunc: userConsumers.NewDataErasureConsumer(dataRequestRepo, disk, userCache).Consume,
+			Description: "Erases the personal data of a user (GDPR right to erasure)",
+		},
 		// Dispatcher: receives delayed messages from app.events and re-routes to blast/user exchanges.
 		{
 			Name: "notification_dispatcher",
 			ConsumeFunc: notifConsumers.NewDispatcherConsumer(
 				blastProducer,
 				userProducer,
+				campaignStatus,
 			).Consume,
 			Description: "Routes delayed notification messages to the correct blast/user exchange",
 		},
-		// Blast: one publish → every user (fanout exchange)
+		// Blast: one publish → one user message per user of the campaign's segment (fanout exchange)
 		{
-			Name:        "notification_blast",
-			ConsumeFunc: notifConsumers.NewBlastConsumer(renderer, logRepo, chs...).Consume,
-			Description: "Delivers broadcast notifications to all users via email and push",
+			Name: "notification_blast",
+			ConsumeFunc: notifConsumers.NewBlastConsumer(
+				renderer,
+				logRepo,
+				audienceResolver,
+				blastCheckpoints,
+				campaignReader,
+				blastProducer,
+				userProducer,
+				audienceConfig,
+				chs...,
+			).Consume,
+			Description: "Fans broadcast notifications out to every user of their segment, in checkpointed batches",
 		},
 		// User-specific: one publish → one user (direct exchange, routing_key=user.<id>)
 		{
 			Name:        "notification_user",
-			ConsumeFunc: notifConsumers.NewUserNotificationConsumer(renderer, logRepo, redisClient, chs...).Consume,
+			ConsumeFunc: notifConsumers.NewUserNotificationConsumer(renderer, logRepo, preferences, digests, redisClient, chs...).Consume,
 			Description: "Delivers targeted notifications to a single user via email and push",
 		},
+		// Webhook deliveries: one job per (event, endpoint); retries are re-published with an x-delay.
+		{
+			Name: "notification_webhook",
+			ConsumeFunc: notifConsumers.NewWebhookDeliveryConsumer(
+				webhookStore,
+				webhookLog,
+				webhookClient,
+				eventsProducer,
+				webhookConfig,
+			).Consume,
+			Description: "Delivers signed webhook requests to tenant endpoints, with retries and auto-disable",
+		},
 	}
 }
 
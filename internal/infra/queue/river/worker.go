@@ -6,13 +6,16 @@ import (
 
 	riverqueue "github.com/riverqueue/river"
 	"ichi-go/internal/infra/queue"
+	"ichi-go/internal/infra/queue/codec"
 )
 
 // GenericJobArgs carries a raw payload for existing ConsumeFunc-based consumers.
 // ConsumerName routes the job to the correct handler in BridgeWorker.
+// ContentType names the codec Payload was encoded with (empty means JSON).
 type GenericJobArgs struct {
 	ConsumerName string `json:"consumer_name"`
 	Payload      []byte `json:"payload"`
+	ContentType  string `json:"content_type,omitempty"`
 }
 
 func (GenericJobArgs) Kind() string { return "generic_job" }
@@ -38,5 +41,5 @@ func (w *BridgeWorker) Work(ctx context.Context, job *riverqueue.Job[GenericJobA
 	if handler == nil {
 		return fmt.Errorf("bridge worker: handler for consumer %q is nil", job.Args.ConsumerName)
 	}
-	return handler(ctx, job.Args.Payload)
+	return handler(codec.WithContentType(ctx, job.Args.ContentType), job.Args.Payload)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	riverqueue "github.com/riverqueue/river"
 	"ichi-go/internal/infra/queue"
+	"ichi-go/internal/infra/queue/codec"
 	riverworker "ichi-go/internal/infra/queue/river"
 )
 
@@ -94,3 +95,24 @@ func TestBridgeWorker_Work_NilHandler(t *testing.T) {
 	assert.ErrorContains(t, err, "handler for consumer",
 		"expected a descriptive error, not a panic, when the handler is nil")
 }
+
+func TestBridgeWorker_Work_PropagatesContentType(t *testing.T) {
+	var contentType string
+	worker := riverworker.NewBridgeWorker(map[string]queue.ConsumeFunc{
+		"payment_handler": func(ctx context.Context, payload []byte) error {
+			contentType = codec.ContentTypeFromContext(ctx)
+			return nil
+		},
+	})
+
+	job := &riverqueue.Job[riverworker.GenericJobArgs]{
+		Args: riverworker.GenericJobArgs{
+			ConsumerName: "payment_handler",
+			Payload:      []byte{0x80},
+			ContentType:  codec.ContentTypeMsgpack,
+		},
+	}
+
+	require.NoError(t, worker.Work(context.Background(), job))
+	assert.Equal(t, codec.ContentTypeMsgpack, contentType)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"ichi-go/internal/applications/rbac/services"
+	"ichi-go/pkg/authenticator"
 	"ichi-go/pkg/logger"
 	"ichi-go/pkg/requestctx"
 
@@ -52,6 +53,11 @@ func RBACEnforcementMiddleware(enforcementService *services.EnforcementService,
 			// Resolve resource and action from route
 			resource, action := resolveResourceAction(c, config)
 
+			// API key requests are further limited to the key's scopes
+			if !scopeAllowed(c, resource, action) {
+				return echo.NewHTTPError(http.StatusForbidden, "API key scope does not allow this action")
+			}
+
 			// Check permission
 			allowed, err := enforcementService.CheckPermission(
 				ctx,
@@ -141,6 +147,10 @@ func RequirePermission(enforcementService *services.EnforcementService, resource
 				return echo.NewHTTPError(http.StatusBadRequest, "Tenant context required")
 			}
 
+			if !scopeAllowed(c, resource, action) {
+				return echo.NewHTTPError(http.StatusForbidden, "API key scope does not allow this action")
+			}
+
 			// Check permission
 			allowed, err := enforcementService.CheckPermission(ctx, userID, tenantID, resource, action)
 			if err != nil {
@@ -161,6 +171,24 @@ func RequirePermission(enforcementService *services.EnforcementService, resource
 	}
 }
 
+// scopeAllowed checks the scopes of the request's API key, if any. Scopes only narrow
+// access: the key owner must still hold the permission itself.
+func scopeAllowed(c *echo.Context, resource, action string) bool {
+	ctx := c.Request().Context()
+	if !requestctx.IsAPIKeyRequest(ctx) {
+		return true
+	}
+	rc := requestctx.FromContext(ctx)
+	if authenticator.ScopeAllows(rc.Scopes, resource, action) {
+		return true
+	}
+	logger.WithContext(ctx).Warnf(
+		"API key scope denied: key=%s resource=%s action=%s",
+		rc.APIKeyID, resource, action,
+	)
+	return false
+}
+
 // resolveResourceAction determines the resource and action from the request
 func resolveResourceAction(c *echo.Context, config RBACConfig) (resource, action string) {
 	// Use custom mapper if provided
@@ -4,11 +4,17 @@ import (
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
 	"ichi-go/pkg/http"
+	"strings"
 	"time"
 )
 
 func AppRequestTimeOut(configHttp *http.Config) echo.MiddlewareFunc {
 	return middleware.ContextTimeoutWithConfig(middleware.ContextTimeoutConfig{
 		Timeout: time.Duration(configHttp.Timeout) * time.Second,
+		// Event streams stay open for minutes; they end on their own max duration.
+		// Matched on the route, not on headers the client controls.
+		Skipper: func(c *echo.Context) bool {
+			return strings.HasSuffix(c.Path(), "/notifications/inbox/stream")
+		},
 	})
 }
@@ -1,15 +1,36 @@
 package authenticator
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"regexp"
+	"strings"
 
 	pkgErrors "ichi-go/pkg/errors"
 
 	"github.com/labstack/echo/v5"
 )
 
-const defaultAPIKeyHeader = "X-API-Key"
+const (
+	defaultAPIKeyHeader = "X-API-Key"
+
+	// APIKeyPrefix marks database-backed keys: "ichi_<12 hex id>_<secret>".
+	// Keys without it are checked against Validator or the static Keys list.
+	APIKeyPrefix = "ichi_"
+
+	apiKeyIDBytes     = 6
+	apiKeySecretBytes = 32
+)
+
+// ScopeAll grants every resource and action the key owner is allowed.
+const ScopeAll = "*"
+
+var scopePattern = regexp.MustCompile(`^[a-z0-9_.-]+:(\*|[a-z0-9_.-]+)$`)
 
 func NewAPIKeyAuthenticator(config *APIKeyConfig) *APIKeyAuthenticator {
 	if config.Header == "" {
@@ -28,12 +49,31 @@ type APIKeyConfig struct {
 	Keys      []string        `yaml:"keys"       mapstructure:"keys"`
 	SkipPaths []string        `yaml:"skip_paths" mapstructure:"skip_paths"`
 	Validator APIKeyValidator `yaml:"-"          mapstructure:"-"`
+
+	// Database validates "ichi_" keys against the api_keys table when no Resolver
+	// is set. Wired by the auth domain.
+	Database bool           `yaml:"database" mapstructure:"database"`
+	Resolver APIKeyResolver `yaml:"-"        mapstructure:"-"`
 }
 
 // APIKeyValidator is an optional custom validator. Return an error to reject the key.
 // When nil, Authenticate falls back to the static Keys list.
 type APIKeyValidator func(apiKey string) error
 
+// APIKeyResolver looks up a database-backed key and returns the client it belongs to.
+// Return an error for unknown, expired or revoked keys.
+type APIKeyResolver func(ctx context.Context, apiKey string) (*APIKeyIdentity, error)
+
+// APIKeyIdentity is the client behind a database-backed API key.
+type APIKeyIdentity struct {
+	ID       int64
+	Prefix   string // public part of the key, safe to log
+	Name     string
+	OwnerID  uint64 // user the key acts on behalf of
+	TenantID string // empty when the key is not bound to a tenant
+	Scopes   []string
+}
+
 func (a *APIKeyAuthenticator) Authenticate(c *echo.Context) (*AuthContext, error) {
 	key := c.Request().Header.Get(a.config.Header)
 	if key == "" {
@@ -42,13 +82,27 @@ func (a *APIKeyAuthenticator) Authenticate(c *echo.Context) (*AuthContext, error
 			Wrap(errors.New("missing API key"))
 	}
 
+	if a.config.Resolver != nil && strings.HasPrefix(key, APIKeyPrefix) {
+		identity, err := a.config.Resolver(c.Request().Context(), key)
+		if err != nil {
+			return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+				Hint("invalid API key").
+				Wrap(err)
+		}
+		return &AuthContext{
+			UserID: UserSubject{ID: identity.OwnerID},
+			Method: MethodAPIKey,
+			APIKey: identity,
+		}, nil
+	}
+
 	if a.config.Validator != nil {
 		if err := a.config.Validator(key); err != nil {
 			return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
 				Hint("API key rejected by validator").
 				Wrap(err)
 		}
-		return &AuthContext{}, nil
+		return &AuthContext{Method: MethodAPIKey}, nil
 	}
 
 	if len(a.config.Keys) == 0 {
@@ -65,10 +119,68 @@ func (a *APIKeyAuthenticator) Authenticate(c *echo.Context) (*AuthContext, error
 		matched |= subtle.ConstantTimeCompare(keyBytes, []byte(valid))
 	}
 	if matched == 1 {
-		return &AuthContext{}, nil
+		return &AuthContext{Method: MethodAPIKey}, nil
 	}
 
 	return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
 		Hint("invalid API key").
 		Wrap(errors.New("API key not recognised"))
 }
+
+// GenerateAPIKey creates a new database-backed key. Only prefix and the hash of
+// key are stored; key itself is shown to the client once.
+func GenerateAPIKey() (key, prefix string, err error) {
+	id := make([]byte, apiKeyIDBytes)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	prefix = APIKeyPrefix + hex.EncodeToString(id)
+	return prefix + "_" + base64.RawURLEncoding.EncodeToString(secret), prefix, nil
+}
+
+// ParseAPIKeyPrefix returns the lookup prefix of a database-backed key.
+func ParseAPIKeyPrefix(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, APIKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != apiKeyIDBytes*2 || secret == "" {
+		return "", false
+	}
+	if _, err := hex.DecodeString(id); err != nil {
+		return "", false
+	}
+	return APIKeyPrefix + id, true
+}
+
+// HashAPIKey returns the hex SHA-256 stored for key.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyHashMatches compares key against a stored hash in constant time.
+func APIKeyHashMatches(key, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(hash)) == 1
+}
+
+// ValidScope reports whether scope is "*" or "resource:action", where action may be "*".
+func ValidScope(scope string) bool {
+	return scope == ScopeAll || scopePattern.MatchString(scope)
+}
+
+// ScopeAllows reports whether scopes grant action on resource.
+// "*", "resource:*" and "resource:action" match; no scopes grant nothing.
+func ScopeAllows(scopes []string, resource, action string) bool {
+	for _, scope := range scopes {
+		if scope == ScopeAll || scope == resource+":*" || scope == resource+":"+action {
+			return true
+		}
+	}
+	return false
+}
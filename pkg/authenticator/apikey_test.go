@@ -0,0 +1,214 @@
+package authenticator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/requestctx"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAPIKeyTestAuthenticator accepts the static key "static-key" and one database key.
+func newAPIKeyTestAuthenticator(t *testing.T) (*Authenticator, string) {
+	t.Helper()
+	dbKey, prefix, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	return New(&Config{
+		JWT: &JWTConfig{
+			Enabled:        true,
+			SigningMethod:  jwt.SigningMethodHS256,
+			SecretKey:      []byte("test-secret-key-minimum-32-chars-long"),
+			AccessTokenTTL: 15 * time.Minute,
+			AuthScheme:     "Bearer",
+		},
+		APIKey: &APIKeyConfig{
+			Enabled: true,
+			Keys:    []string{"static-key"},
+			Resolver: func(_ context.Context, key string) (*APIKeyIdentity, error) {
+				if key != dbKey {
+					return nil, errors.New("unknown key")
+				}
+				return &APIKeyIdentity{ID: 7, Prefix: prefix, OwnerID: 42, TenantID: "acme", Scopes: []string{"orders:view"}}, nil
+			},
+		},
+	}), dbKey
+}
+
+// serveAPIKey runs a request through AuthenticateMiddleware and returns the handler's request context
+func serveAPIKey(a *Authenticator, apiKey, bearer string, opts ...AuthOption) (*httptest.ResponseRecorder, *AuthContext, *requestctx.RequestContext) {
+	e := echo.New()
+	pkgErrors.Setup(e)
+	var (
+		got *AuthContext
+		rc  *requestctx.RequestContext
+	)
+	e.GET("/orders", func(c *echo.Context) error {
+		got, _ = c.Get("auth").(*AuthContext)
+		rc = requestctx.FromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	}, a.AuthenticateMiddleware(opts...))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req = req.WithContext(requestctx.NewContext(req.Context(), requestctx.FromRequest(req)))
+	if apiKey != "" {
+		req.Header.Set(defaultAPIKeyHeader, apiKey)
+	}
+	if bearer != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+bearer)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec, got, rc
+}
+
+func TestAPIKey_DatabaseKeyAuthenticatesAsOwnerWhenRouteAllows(t *testing.T) {
+	a, dbKey := newAPIKeyTestAuthenticator(t)
+
+	rec, authCtx, rc := serveAPIKey(a, dbKey, "", WithMethods(MethodJWT, MethodAPIKey))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, authCtx)
+	assert.Equal(t, MethodAPIKey, authCtx.Method)
+	assert.Equal(t, uint64(42), authCtx.UserID.ID)
+	require.NotNil(t, authCtx.APIKey)
+	assert.Equal(t, int64(7), authCtx.APIKey.ID)
+
+	assert.Equal(t, "7", rc.APIKeyID)
+	assert.Equal(t, "42", rc.UserID)
+	assert.Equal(t, "acme", rc.TenantID)
+	assert.Equal(t, []string{"orders:view"}, rc.Scopes)
+	assert.False(t, rc.IsGuest)
+}
+
+func TestAPIKey_DatabaseKeyAloneIsRejectedOnJWTRoutes(t *testing.T) {
+	a, dbKey := newAPIKeyTestAuthenticator(t)
+
+	rec, _, _ := serveAPIKey(a, dbKey, "")
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAPIKey_DatabaseKeyWithJWTKeepsUserAndRecordsClient(t *testing.T) {
+	a, dbKey := newAPIKeyTestAuthenticator(t)
+	token, err := a.jwtAuth.GenerateTokens(context.Background(), 5)
+	require.NoError(t, err)
+
+	rec, authCtx, rc := serveAPIKey(a, dbKey, token.AccessToken)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, authCtx)
+	assert.Equal(t, MethodJWT, authCtx.Method)
+	assert.Equal(t, uint64(5), authCtx.UserID.ID)
+	require.NotNil(t, authCtx.APIKey)
+	assert.Equal(t, "7", rc.APIKeyID)
+	assert.Equal(t, "acme", rc.TenantID)
+}
+
+func TestAPIKey_StaticKeyCannotStandInForUser(t *testing.T) {
+	a, _ := newAPIKeyTestAuthenticator(t)
+
+	rec, _, _ := serveAPIKey(a, "static-key", "", WithMethods(MethodJWT, MethodAPIKey))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	token, err := a.jwtAuth.GenerateTokens(context.Background(), 5)
+	require.NoError(t, err)
+	rec, authCtx, rc := serveAPIKey(a, "static-key", token.AccessToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, authCtx.APIKey)
+	assert.Empty(t, rc.APIKeyID)
+}
+
+func TestAPIKey_UnknownDatabaseKeyRejected(t *testing.T) {
+	a, dbKey := newAPIKeyTestAuthenticator(t)
+
+	rec, _, _ := serveAPIKey(a, dbKey+"x", "", WithMethods(MethodJWT, MethodAPIKey))
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAPIKey_PartnerRouteEnforcesScopeAndRevocation(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	require.NoError(t, err)
+	revoked := false
+	a := New(&Config{
+		JWT: &JWTConfig{
+			Enabled:        true,
+			SigningMethod:  jwt.SigningMethodHS256,
+			SecretKey:      []byte("test-secret-key-minimum-32-chars-long"),
+			AccessTokenTTL: 15 * time.Minute,
+			AuthScheme:     "Bearer",
+		},
+		APIKey: &APIKeyConfig{
+			Enabled: true,
+			Resolver: func(_ context.Context, got string) (*APIKeyIdentity, error) {
+				if got != key || revoked {
+					return nil, errors.New("unknown or revoked key")
+				}
+				return &APIKeyIdentity{ID: 9, Prefix: prefix, OwnerID: 42, TenantID: "acme", Scopes: []string{"orders:*"}}, nil
+			},
+		},
+	})
+	partner := WithMethods(MethodJWT, MethodAPIKey)
+
+	rec, authCtx, _ := serveAPIKey(a, key, "", partner, WithScope("orders:view"))
+	assert.Equal(t, http.StatusOK, rec.Code, "the key alone authenticates the partner")
+	require.NotNil(t, authCtx)
+	assert.Equal(t, uint64(42), authCtx.UserID.ID)
+
+	rec, _, _ = serveAPIKey(a, key, "", partner, WithScope("notifications:send"))
+	assert.Equal(t, http.StatusForbidden, rec.Code, "the key's scopes are enforced")
+
+	revoked = true
+	rec, _, _ = serveAPIKey(a, key, "", partner, WithScope("orders:view"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "a revoked key is rejected")
+}
+
+func TestParseAPIKeyPrefix(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	got, ok := ParseAPIKeyPrefix(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, got)
+	assert.True(t, APIKeyHashMatches(key, HashAPIKey(key)))
+	assert.False(t, APIKeyHashMatches(key+"x", HashAPIKey(key)))
+
+	for _, bad := range []string{"", "static-key", "ichi_", "ichi_zzzzzzzzzzzz_secret", "ichi_0123456789ab", "ichi_0123456789ab_"} {
+		_, ok := ParseAPIKeyPrefix(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestScopeAllows(t *testing.T) {
+	tests := []struct {
+		scopes   []string
+		resource string
+		action   string
+		want     bool
+	}{
+		{[]string{"orders:view"}, "orders", "view", true},
+		{[]string{"orders:view"}, "orders", "create", false},
+		{[]string{"orders:*"}, "orders", "delete", true},
+		{[]string{"orders:*"}, "products", "view", false},
+		{[]string{"*"}, "products", "view", true},
+		{nil, "orders", "view", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ScopeAllows(tt.scopes, tt.resource, tt.action), "%v %s:%s", tt.scopes, tt.resource, tt.action)
+	}
+
+	assert.True(t, ValidScope("orders:view"))
+	assert.True(t, ValidScope("*"))
+	assert.False(t, ValidScope("orders"))
+	assert.False(t, ValidScope("*:view"))
+}
@@ -38,9 +38,20 @@ func New(config *Config) *Authenticator {
 	return a
 }
 
+// Authentication methods selectable per route with WithMethods.
+const (
+	MethodJWT   = "jwt"
+	MethodBasic = "basic"
+	// MethodAPIKey accepts a database-backed API key on its own, acting as the key owner.
+	MethodAPIKey = "api_key"
+)
+
 type AuthContext struct {
-	UserID UserSubject
-	Claims jwt.MapClaims // populated during Authenticate; nil for non-JWT auth
+	UserID   UserSubject
+	Claims   jwt.MapClaims   // populated during Authenticate; nil for non-JWT auth
+	Method   string          // authentication method that accepted the request
+	Username string          // basic auth username; empty for other methods
+	APIKey   *APIKeyIdentity // calling client when it sent a database-backed API key
 }
 
 // // RequirePermission middleware checks ACL
@@ -62,6 +73,8 @@ type AuthContext struct {
 // Options pattern
 type authOptions struct {
 	guestAllowed bool
+	methods      []string // nil means JWT only
+	scope        string   // "resource:action" API keys must allow; empty means any
 }
 
 type AuthOption func(*authOptions)
@@ -72,6 +85,26 @@ func WithGuestAllowed() AuthOption {
 	}
 }
 
+// WithMethods selects which user authentication methods the route accepts, tried
+// in order until one succeeds, e.g. WithMethods(MethodBasic, MethodJWT) for internal
+// tooling or WithMethods(MethodJWT, MethodAPIKey) for partner integrations. Methods
+// that are not enabled in config are ignored. Default: JWT only.
+func WithMethods(methods ...string) AuthOption {
+	return func(o *authOptions) {
+		o.methods = methods
+	}
+}
+
+// WithScope makes the route refuse (403) requests whose database API key does not
+// allow scope ("resource:action"), e.g. WithScope("notifications:send") for a route
+// partners reach with WithMethods(MethodJWT, MethodAPIKey). Requests without a key
+// are not affected.
+func WithScope(scope string) AuthOption {
+	return func(o *authOptions) {
+		o.scope = scope
+	}
+}
+
 // RegisterPublicEndpoint marks a specific method+path combination as public.
 // Must be called during server setup before the server starts accepting requests.
 func (a *Authenticator) RegisterPublicEndpoint(method, path string) {
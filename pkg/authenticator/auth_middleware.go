@@ -1,6 +1,12 @@
 package authenticator
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ichi-go/pkg/requestctx"
+
 	"github.com/labstack/echo/v5"
 )
 
@@ -10,11 +16,15 @@ func (a *Authenticator) AuthenticateMiddleware(options ...AuthOption) echo.Middl
 	for _, opt := range options {
 		opt(opts)
 	}
+	methods := opts.methods
+	if methods == nil {
+		methods = []string{MethodJWT}
+	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
 			// Check skip paths and per-route public endpoints first.
-			if a.jwtAuth != nil && a.shouldSkip(c.Path(), a.jwtAuth.config.SkipPaths) {
+			if a.skippedByAll(c.Path(), methods) {
 				return next(c)
 			}
 			if a.publicEndpoints[c.Request().Method+":"+c.Path()] {
@@ -22,37 +32,154 @@ func (a *Authenticator) AuthenticateMiddleware(options ...AuthOption) echo.Middl
 			}
 
 			// Layer 1: API key — validates the calling client application.
-			// Must pass before JWT is evaluated.
+			// Must pass before the user is authenticated.
+			var client *AuthContext
 			if a.apiKeyAuth != nil && !a.shouldSkip(c.Path(), a.apiKeyAuth.config.SkipPaths) {
-				if _, err := a.apiKeyAuth.Authenticate(c); err != nil {
+				keyCtx, err := a.apiKeyAuth.Authenticate(c)
+				if err != nil {
 					if opts.guestAllowed {
 						return next(c)
 					}
 					return err
 				}
+				client = keyCtx
 			}
 
-			// Layer 2: JWT — validates the user identity.
-			var authCtx *AuthContext
-			var authErr error
-			if a.jwtAuth != nil {
-				authCtx, authErr = a.jwtAuth.Authenticate(c)
-			}
+			// Layer 2: user identity — the route's methods in order, first success wins.
+			authCtx, authErr := a.authenticateUser(c, methods, client)
 
 			if authCtx == nil {
 				if opts.guestAllowed {
 					return next(c)
 				}
+				if a.basicAuth != nil && containsMethod(methods, MethodBasic) {
+					a.basicAuth.Challenge(c)
+				}
 				if authErr != nil {
 					return authErr
 				}
 				return handleAuthError(c, nil)
 			}
 
+			if client != nil && client.APIKey != nil {
+				authCtx.APIKey = client.APIKey
+			}
+			if opts.scope != "" && authCtx.APIKey != nil {
+				resource, action, _ := strings.Cut(opts.scope, ":")
+				if !ScopeAllows(authCtx.APIKey.Scopes, resource, action) {
+					return echo.NewHTTPError(http.StatusForbidden, "API key scope does not allow this action")
+				}
+			}
+
 			// Store auth context for downstream handlers.
 			c.Set("auth", authCtx)
+			bindRequestContext(c, authCtx)
 
 			return next(c)
 		}
 	}
 }
+
+// authenticateUser tries each enabled method. When all fail, the error of the
+// method whose credentials the client actually sent is returned. client is the
+// result of the API key layer; MethodAPIKey accepts it when it is database-backed.
+func (a *Authenticator) authenticateUser(c *echo.Context, methods []string, client *AuthContext) (*AuthContext, error) {
+	sentBasic := strings.HasPrefix(strings.ToLower(c.Request().Header.Get(echo.HeaderAuthorization)), "basic ")
+
+	var authErr error
+	for _, method := range methods {
+		var (
+			authCtx *AuthContext
+			err     error
+		)
+		switch method {
+		case MethodJWT:
+			if a.jwtAuth == nil {
+				continue
+			}
+			authCtx, err = a.jwtAuth.Authenticate(c)
+		case MethodBasic:
+			if a.basicAuth == nil {
+				continue
+			}
+			authCtx, err = a.basicAuth.Authenticate(c)
+		case MethodAPIKey:
+			// Static keys identify no one, so they cannot stand in for a user.
+			if client == nil || client.APIKey == nil {
+				continue
+			}
+			authCtx = client
+		default:
+			continue
+		}
+		if authCtx != nil {
+			return authCtx, nil
+		}
+		if authErr == nil || (method == MethodBasic) == sentBasic {
+			authErr = err
+		}
+	}
+	return nil, authErr
+}
+
+// skippedByAll reports whether every enabled method of the route lists path in its
+// SkipPaths. A route accepting Basic or JWT stays protected on a JWT skip path.
+func (a *Authenticator) skippedByAll(path string, methods []string) bool {
+	enabled := 0
+	for _, method := range methods {
+		var skipPaths []string
+		switch method {
+		case MethodJWT:
+			if a.jwtAuth == nil {
+				continue
+			}
+			skipPaths = a.jwtAuth.config.SkipPaths
+		case MethodBasic:
+			if a.basicAuth == nil {
+				continue
+			}
+			skipPaths = a.basicAuth.config.SkipPaths
+		case MethodAPIKey:
+			if a.apiKeyAuth == nil {
+				continue
+			}
+			skipPaths = a.apiKeyAuth.config.SkipPaths
+		default:
+			continue
+		}
+		enabled++
+		if !a.shouldSkip(path, skipPaths) {
+			return false
+		}
+	}
+	return enabled > 0
+}
+
+// bindRequestContext exposes the API key client to code reading requestctx, e.g.
+// RBAC enforcement. A tenant-bound key pins the request to its tenant, and a
+// request authenticated by the key alone acts as the key owner.
+func bindRequestContext(c *echo.Context, authCtx *AuthContext) {
+	if authCtx.APIKey == nil {
+		return
+	}
+	rc := requestctx.FromContext(c.Request().Context())
+	rc.APIKeyID = strconv.FormatInt(authCtx.APIKey.ID, 10)
+	rc.Scopes = authCtx.APIKey.Scopes
+	if authCtx.APIKey.TenantID != "" {
+		rc.TenantID = authCtx.APIKey.TenantID
+	}
+	if authCtx.Method == MethodAPIKey {
+		rc.UserID = strconv.FormatUint(authCtx.APIKey.OwnerID, 10)
+		rc.IsGuest = false
+	}
+	c.SetRequest(c.Request().WithContext(requestctx.NewContext(c.Request().Context(), rc)))
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,31 @@
 package authenticator
 
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	pkgErrors "ichi-go/pkg/errors"
+
+	"github.com/labstack/echo/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const defaultBasicAuthRealm = "Restricted"
+
+// dummyBasicAuthHash is compared against when the username is unknown so that
+// unknown and known users take the same time to reject.
+var dummyBasicAuthHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("basic-auth-timing-equalizer"), bcrypt.DefaultCost)
+	return hash
+})
+
 func NewBasicAuthenticator(config *BasicAuthConfig) *BasicAuthenticator {
+	if config.Realm == "" {
+		config.Realm = defaultBasicAuthRealm
+	}
 	return &BasicAuthenticator{config: config}
 }
 
@@ -9,17 +34,81 @@ type BasicAuthenticator struct {
 }
 
 type BasicAuthConfig struct {
-	Enabled bool
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
 
 	// Credential validation
-	Validator BasicAuthValidator // Custom username/password validator
-	Realm     string             // WWW-Authenticate realm (default: "Restricted")
+	Validator BasicAuthValidator `yaml:"-" mapstructure:"-"`         // Custom username/password validator
+	Realm     string             `yaml:"realm" mapstructure:"realm"` // WWW-Authenticate realm (default: "Restricted")
+
+	// UsersTable validates credentials against the users table (email + password)
+	// when no Validator is set. Wired by the auth domain.
+	UsersTable bool `yaml:"users_table" mapstructure:"users_table"`
 
-	// For simple static credentials (dev/testing only)
-	Users map[string]string // username -> password (hashed!)
+	// For simple static credentials (internal tooling, dev/testing)
+	Users map[string]string `yaml:"users" mapstructure:"users"` // username -> bcrypt hash
 
 	// Advanced
-	SkipPaths []string
+	SkipPaths []string `yaml:"skip_paths" mapstructure:"skip_paths"`
+}
+
+// BasicAuthValidator checks a username/password pair. userID is the numeric user ID
+// when the credentials belong to an application user, empty for service accounts.
+type BasicAuthValidator func(ctx context.Context, username, password string) (userID string, err error)
+
+// Authenticate validates the credentials of an "Authorization: Basic" header.
+// Failures do not set the WWW-Authenticate header; see Challenge.
+func (b *BasicAuthenticator) Authenticate(c *echo.Context) (*AuthContext, error) {
+	username, password, ok := c.Request().BasicAuth()
+	if !ok {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeUnauthorized).
+			Hint("missing basic auth credentials").
+			Wrap(errors.New("missing basic auth credentials"))
+	}
+
+	userID, err := b.validate(c.Request().Context(), username, password)
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidCredentials).
+			With("username", username).
+			Hint("invalid username or password").
+			Wrap(err)
+	}
+
+	authCtx := &AuthContext{Method: MethodBasic, Username: username}
+	if userID != "" {
+		id, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			return nil, pkgErrors.AuthService(pkgErrors.ErrCodeUnauthorized).
+				With("username", username).
+				Hint("basic auth validator returned an invalid user ID").
+				Wrap(err)
+		}
+		authCtx.UserID = UserSubject{ID: id}
+	}
+	return authCtx, nil
+}
+
+// Challenge asks the client for Basic credentials.
+func (b *BasicAuthenticator) Challenge(c *echo.Context) {
+	realm := strings.ReplaceAll(b.config.Realm, `"`, `\"`)
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="`+realm+`", charset="UTF-8"`)
 }
 
-type BasicAuthValidator func(username, password string) (userID string, err error)
+func (b *BasicAuthenticator) validate(ctx context.Context, username, password string) (string, error) {
+	if b.config.Validator != nil {
+		return b.config.Validator(ctx, username, password)
+	}
+
+	if len(b.config.Users) == 0 {
+		return "", errors.New("basic auth misconfigured: no users and no Validator set")
+	}
+
+	hash, ok := b.config.Users[username]
+	if !ok {
+		_ = bcrypt.CompareHashAndPassword(dummyBasicAuthHash(), []byte(password))
+		return "", errors.New("unknown basic auth user")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return "", errors.New("basic auth password mismatch")
+	}
+	return "", nil
+}
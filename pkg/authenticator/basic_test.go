@@ -0,0 +1,142 @@
+package authenticator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pkgErrors "ichi-go/pkg/errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newBasicTestAuthenticator(t *testing.T, basic *BasicAuthConfig) *Authenticator {
+	t.Helper()
+	if basic.Users == nil && basic.Validator == nil {
+		hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+		require.NoError(t, err)
+		basic.Users = map[string]string{"ops": string(hash)}
+	}
+	basic.Enabled = true
+	return New(&Config{
+		JWT: &JWTConfig{
+			Enabled:        true,
+			SigningMethod:  jwt.SigningMethodHS256,
+			SecretKey:      []byte("test-secret-key-minimum-32-chars-long"),
+			AccessTokenTTL: 15 * time.Minute,
+			AuthScheme:     "Bearer",
+			SkipPaths:      []string{"/docs/*"},
+		},
+		BasicAuth: basic,
+	})
+}
+
+// serve runs a request through AuthenticateMiddleware and returns the recorder and handler auth context
+func serve(a *Authenticator, path string, setup func(*http.Request), opts ...AuthOption) (*httptest.ResponseRecorder, *AuthContext) {
+	e := echo.New()
+	pkgErrors.Setup(e)
+	var got *AuthContext
+	e.GET(path, func(c *echo.Context) error {
+		got, _ = c.Get("auth").(*AuthContext)
+		return c.NoContent(http.StatusOK)
+	}, a.AuthenticateMiddleware(opts...))
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if setup != nil {
+		setup(req)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec, got
+}
+
+func TestBasicAuth_StaticUsers(t *testing.T) {
+	a := newBasicTestAuthenticator(t, &BasicAuthConfig{Realm: "Internal"})
+	basicOnly := WithMethods(MethodBasic)
+
+	rec, authCtx := serve(a, "/internal", func(r *http.Request) { r.SetBasicAuth("ops", "s3cret") }, basicOnly)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, authCtx)
+	assert.Equal(t, MethodBasic, authCtx.Method)
+	assert.Equal(t, "ops", authCtx.Username)
+
+	rec, _ = serve(a, "/internal", func(r *http.Request) { r.SetBasicAuth("ops", "wrong") }, basicOnly)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Basic realm="Internal", charset="UTF-8"`, rec.Header().Get(echo.HeaderWWWAuthenticate))
+
+	rec, _ = serve(a, "/internal", func(r *http.Request) { r.SetBasicAuth("nobody", "s3cret") }, basicOnly)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec, _ = serve(a, "/internal", nil, basicOnly)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderWWWAuthenticate))
+}
+
+func TestBasicAuth_Validator(t *testing.T) {
+	a := newBasicTestAuthenticator(t, &BasicAuthConfig{
+		Validator: func(_ context.Context, username, password string) (string, error) {
+			if username == "jane@example.com" && password == "pw" {
+				return "42", nil
+			}
+			return "", errors.New("bad credentials")
+		},
+	})
+
+	rec, authCtx := serve(a, "/internal", func(r *http.Request) { r.SetBasicAuth("jane@example.com", "pw") }, WithMethods(MethodBasic))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, authCtx)
+	assert.Equal(t, uint64(42), authCtx.UserID.ID)
+
+	rec, _ = serve(a, "/internal", func(r *http.Request) { r.SetBasicAuth("jane@example.com", "nope") }, WithMethods(MethodBasic))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Basic realm="Restricted", charset="UTF-8"`, rec.Header().Get(echo.HeaderWWWAuthenticate))
+}
+
+func TestAuthenticateMiddleware_WithMethods(t *testing.T) {
+	a := newBasicTestAuthenticator(t, &BasicAuthConfig{})
+	pair, err := a.jwtAuth.GenerateTokens(context.Background(), 7)
+	require.NoError(t, err)
+	bearer := func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+pair.AccessToken) }
+	basic := func(r *http.Request) { r.SetBasicAuth("ops", "s3cret") }
+	both := WithMethods(MethodBasic, MethodJWT)
+
+	// Either method is accepted
+	rec, authCtx := serve(a, "/docs/index.html", basic, both)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, MethodBasic, authCtx.Method)
+
+	rec, authCtx = serve(a, "/docs/index.html", bearer, both)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, MethodJWT, authCtx.Method)
+
+	// A JWT skip path does not open a route that also accepts Basic
+	rec, _ = serve(a, "/docs/index.html", nil, both)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderWWWAuthenticate))
+
+	// Default routes stay JWT-only: Basic credentials are not accepted and no challenge is sent
+	rec, _ = serve(a, "/api/me", basic)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderWWWAuthenticate))
+
+	// ...and JWT skip paths still apply to them
+	rec, _ = serve(a, "/docs/index.html", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestBasicAuth_SkipPaths(t *testing.T) {
+	a := newBasicTestAuthenticator(t, &BasicAuthConfig{SkipPaths: []string{"/internal/health"}})
+
+	rec, _ := serve(a, "/internal/health", nil, WithMethods(MethodBasic))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, _ = serve(a, "/internal/other", nil, WithMethods(MethodBasic))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -1,6 +1,7 @@
 package authenticator
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -8,9 +9,45 @@ import (
 )
 
 type Config struct {
-	JWT       *JWTConfig
-	BasicAuth *BasicAuthConfig
-	APIKey    *APIKeyConfig
+	JWT           *JWTConfig
+	BasicAuth     *BasicAuthConfig     `mapstructure:"basic_auth"`
+	APIKey        *APIKeyConfig        `mapstructure:"api_key"`
+	PasswordReset *PasswordResetConfig `mapstructure:"password_reset"`
+	LoginThrottle *LoginThrottleConfig `mapstructure:"login_throttle"`
+	OIDC          *OIDCConfig          `mapstructure:"oidc"`
+	MFA           *MFAConfig           `mapstructure:"mfa"`
+
+	EmailVerification *EmailVerificationConfig `mapstructure:"email_verification"`
+}
+
+// PasswordResetConfig controls the forgot-password flow
+type PasswordResetConfig struct {
+	// TokenTTL is how long an emailed reset token stays valid (default: 30m)
+	TokenTTL time.Duration `yaml:"token_ttl" json:"token_ttl" mapstructure:"token_ttl"`
+	// ResetURL is the frontend page receiving the token, e.g. "https://app.example.com/reset-password".
+	// The token is appended as the "token" query parameter.
+	ResetURL string `yaml:"reset_url" json:"reset_url" mapstructure:"reset_url"`
+	// RequestCooldown is the minimum time between two reset emails to one address (default: 1m)
+	RequestCooldown time.Duration `yaml:"request_cooldown" json:"request_cooldown" mapstructure:"request_cooldown"`
+	// RequestLimit is the number of reset requests per address or client IP within
+	// RequestWindow (default: 5 per 1h)
+	RequestLimit  int           `yaml:"request_limit" json:"request_limit" mapstructure:"request_limit"`
+	RequestWindow time.Duration `yaml:"request_window" json:"request_window" mapstructure:"request_window"`
+}
+
+// NewRequestLimiter limits forgot-password requests with the configured cooldown and window.
+func (c PasswordResetConfig) NewRequestLimiter(store RateLimitStore) *ResendLimiter {
+	cooldown, limit, window := c.RequestCooldown, c.RequestLimit, c.RequestWindow
+	if cooldown <= 0 {
+		cooldown = time.Minute
+	}
+	if limit <= 0 {
+		limit = 5
+	}
+	if window <= 0 {
+		window = time.Hour
+	}
+	return NewResendLimiter(store, "password_reset", cooldown, limit, window)
 }
 
 func SetDefault() Config {
@@ -29,6 +66,39 @@ func SetDefault() Config {
 			Enabled: false,
 			Realm:   "Restricted",
 		},
+		PasswordReset: &PasswordResetConfig{
+			TokenTTL:        30 * time.Minute,
+			RequestCooldown: time.Minute,
+			RequestLimit:    5,
+			RequestWindow:   time.Hour,
+		},
+		LoginThrottle: &LoginThrottleConfig{
+			Enabled:          false,
+			MaxAttempts:      5,
+			MaxAttemptsPerIP: 20,
+			Window:           15 * time.Minute,
+			LockoutDuration:  15 * time.Minute,
+			BaseDelay:        250 * time.Millisecond,
+			MaxDelay:         5 * time.Second,
+		},
+		OIDC: &OIDCConfig{
+			Enabled:  false,
+			StateTTL: 10 * time.Minute,
+		},
+		MFA: &MFAConfig{
+			Enabled:       false,
+			Issuer:        "ichi-go",
+			ChallengeTTL:  5 * time.Minute,
+			MaxAttempts:   5,
+			RecoveryCodes: 10,
+		},
+		EmailVerification: &EmailVerificationConfig{
+			Enabled:        false,
+			TokenTTL:       24 * time.Hour,
+			ResendCooldown: time.Minute,
+			ResendLimit:    5,
+			ResendWindow:   time.Hour,
+		},
 	}
 }
 
@@ -98,6 +168,26 @@ func (j *JWTConfig) InitializeKeys() error {
 		return fmt.Errorf("SigningMethod is nil - call SetSigningMethod() first or use Config.InitializeJWTKeys()")
 	}
 
+	j.initializeRemoteJWKS()
+
+	if j.KeysDir != "" {
+		if j.KeySet != nil {
+			return nil
+		}
+		keySet, err := LoadJWTKeySet(j.KeysDir, j.SigningMethod)
+		if err != nil {
+			return fmt.Errorf("failed to load JWT keyset: %w", err)
+		}
+		j.KeySet = keySet
+		interval := j.KeysReloadInterval
+		if interval <= 0 {
+			interval = defaultKeysReloadInterval
+		}
+		// Picks up rotated keys for the lifetime of the process.
+		go keySet.ReloadEvery(context.Background(), interval)
+		return nil
+	}
+
 	// Skip if keys are already loaded (not string paths)
 	if j.PublicKey != nil {
 		if _, ok := j.PublicKey.(string); !ok {
@@ -127,6 +217,23 @@ func (j *JWTConfig) InitializeKeys() error {
 	return nil
 }
 
+// initializeRemoteJWKS creates the key caches and subject mappings of the configured external issuers
+func (j *JWTConfig) initializeRemoteJWKS() {
+	if len(j.RemoteJWKS) == 0 || j.remoteIssuers != nil {
+		return
+	}
+	j.remoteIssuers = make(map[string]*RemoteJWKS, len(j.RemoteJWKS))
+	j.remoteSubjects = make(map[string]map[string]uint64, len(j.RemoteJWKS))
+	for _, remote := range j.RemoteJWKS {
+		j.remoteIssuers[remote.Issuer] = NewRemoteJWKS(remote.URL, remote.CacheTTL, nil)
+		subjects := make(map[string]uint64, len(remote.Subjects))
+		for _, s := range remote.Subjects {
+			subjects[s.Subject] = s.UserID
+		}
+		j.remoteSubjects[remote.Issuer] = subjects
+	}
+}
+
 func (j *JWTConfig) Validate() error {
 	if !j.Enabled {
 		return nil
@@ -143,7 +250,7 @@ func (j *JWTConfig) Validate() error {
 		}
 
 	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
-		if j.PublicKey == nil {
+		if j.PublicKey == nil && j.KeySet == nil {
 			return fmt.Errorf("public key is required for %s signing method", j.SigningMethod.Alg())
 		}
 	}
@@ -0,0 +1,134 @@
+package authenticator
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidVerificationToken is returned for a malformed or tampered verification token,
+	// or one issued for another email address.
+	ErrInvalidVerificationToken = errors.New("invalid email verification token")
+	// ErrVerificationTokenExpired is returned for a well-formed token past its expiry.
+	ErrVerificationTokenExpired = errors.New("email verification token expired")
+)
+
+// EmailVerificationConfig controls the verification of email addresses on registration.
+type EmailVerificationConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	// SigningKey signs verification tokens (HMAC-SHA256); at least 32 characters.
+	SigningKey string `yaml:"signing_key" json:"-" mapstructure:"signing_key"`
+	// TokenTTL is how long an emailed verification link stays valid (default: 24h)
+	TokenTTL time.Duration `yaml:"token_ttl" json:"token_ttl" mapstructure:"token_ttl"`
+	// VerifyURL is the frontend page receiving the token, e.g. "https://app.example.com/verify-email".
+	// The token is appended as the "token" query parameter.
+	VerifyURL string `yaml:"verify_url" json:"verify_url" mapstructure:"verify_url"`
+	// RequireForLogin rejects logins of users who have not verified their email yet.
+	RequireForLogin bool `yaml:"require_for_login" json:"require_for_login" mapstructure:"require_for_login"`
+	// ResendCooldown is the minimum time between two resends to one address (default: 1m)
+	ResendCooldown time.Duration `yaml:"resend_cooldown" json:"resend_cooldown" mapstructure:"resend_cooldown"`
+	// ResendLimit is the number of resends per address within ResendWindow (default: 5 per 1h)
+	ResendLimit  int           `yaml:"resend_limit" json:"resend_limit" mapstructure:"resend_limit"`
+	ResendWindow time.Duration `yaml:"resend_window" json:"resend_window" mapstructure:"resend_window"`
+}
+
+func (c *EmailVerificationConfig) applyDefaults() {
+	if c.TokenTTL <= 0 {
+		c.TokenTTL = 24 * time.Hour
+	}
+	if c.ResendCooldown <= 0 {
+		c.ResendCooldown = time.Minute
+	}
+	if c.ResendLimit <= 0 {
+		c.ResendLimit = 5
+	}
+	if c.ResendWindow <= 0 {
+		c.ResendWindow = time.Hour
+	}
+}
+
+// EmailVerifier issues and checks signed email verification tokens. A token is bound
+// to the user and the address it was sent to, so it stops working when the email changes;
+// the caller makes it single-use by accepting it only while the email is unverified.
+type EmailVerifier struct {
+	config EmailVerificationConfig
+	key    []byte
+	limits *ResendLimiter
+	now    func() time.Time
+}
+
+// NewEmailVerifier fails when cfg has no usable signing key.
+func NewEmailVerifier(cfg EmailVerificationConfig, limits RateLimitStore) (*EmailVerifier, error) {
+	cfg.applyDefaults()
+	if len(cfg.SigningKey) < 32 {
+		return nil, errors.New("email_verification signing_key must be at least 32 characters")
+	}
+	var limiter *ResendLimiter
+	if limits != nil {
+		limiter = NewResendLimiter(limits, "verify_email", cfg.ResendCooldown, cfg.ResendLimit, cfg.ResendWindow)
+	}
+	return &EmailVerifier{config: cfg, key: []byte(cfg.SigningKey), limits: limiter, now: time.Now}, nil
+}
+
+func (v *EmailVerifier) RequireForLogin() bool   { return v.config.RequireForLogin }
+func (v *EmailVerifier) TokenTTL() time.Duration { return v.config.TokenTTL }
+func (v *EmailVerifier) VerifyURL() string       { return v.config.VerifyURL }
+
+// IssueToken returns a token, formatted as "<user id>.<expiry>.<signature>", that
+// verifies email for userID.
+func (v *EmailVerifier) IssueToken(userID uint64, email string) string {
+	expires := v.now().Add(v.config.TokenTTL).Unix()
+	payload := strconv.FormatUint(userID, 10) + "." + strconv.FormatInt(expires, 10)
+	return payload + "." + v.sign(payload, email)
+}
+
+// TokenUserID returns the user a token was issued for, without checking it.
+func (v *EmailVerifier) TokenUserID(token string) (uint64, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, ErrInvalidVerificationToken
+	}
+	userID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidVerificationToken
+	}
+	return userID, nil
+}
+
+// Verify checks that token was issued by us for email and has not expired.
+func (v *EmailVerifier) Verify(token, email string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrInvalidVerificationToken
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(v.sign(payload, email))) {
+		return ErrInvalidVerificationToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	if !v.now().Before(time.Unix(expires, 0)) {
+		return ErrVerificationTokenExpired
+	}
+	return nil
+}
+
+// AllowResend counts a verification email sent under key (an address or client IP).
+// It returns how long to wait when the cooldown or the window limit is exceeded.
+func (v *EmailVerifier) AllowResend(ctx context.Context, key string) (time.Duration, error) {
+	return v.limits.Allow(ctx, key)
+}
+
+func (v *EmailVerifier) sign(payload, email string) string {
+	mac := hmac.New(sha256.New, v.key)
+	mac.Write([]byte("email_verification\x00" + payload + "\x00" + strings.ToLower(strings.TrimSpace(email))))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
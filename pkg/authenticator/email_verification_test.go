@@ -0,0 +1,104 @@
+package authenticator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testVerificationKey = "0123456789abcdef0123456789abcdef"
+
+func newTestEmailVerifier(t *testing.T, limits RateLimitStore) *EmailVerifier {
+	t.Helper()
+	verifier, err := NewEmailVerifier(EmailVerificationConfig{
+		SigningKey:     testVerificationKey,
+		ResendCooldown: time.Minute,
+		ResendLimit:    2,
+		ResendWindow:   time.Hour,
+	}, limits)
+	require.NoError(t, err)
+	return verifier
+}
+
+func TestNewEmailVerifier_RequiresSigningKey(t *testing.T) {
+	_, err := NewEmailVerifier(EmailVerificationConfig{SigningKey: "short"}, nil)
+	assert.Error(t, err)
+}
+
+func TestEmailVerifier_TokenRoundTrip(t *testing.T) {
+	verifier := newTestEmailVerifier(t, nil)
+	token := verifier.IssueToken(42, "Jane@Example.com")
+
+	userID, err := verifier.TokenUserID(token)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), userID)
+	assert.NoError(t, verifier.Verify(token, "jane@example.com"), "email comparison is case-insensitive")
+}
+
+func TestEmailVerifier_RejectsInvalidTokens(t *testing.T) {
+	verifier := newTestEmailVerifier(t, nil)
+	token := verifier.IssueToken(42, "jane@example.com")
+	parts := strings.Split(token, ".")
+
+	other, err := NewEmailVerifier(EmailVerificationConfig{SigningKey: strings.Repeat("x", 32)}, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+		email string
+	}{
+		{"other email", token, "john@example.com"},
+		{"other user", "43." + parts[1] + "." + parts[2], "jane@example.com"},
+		{"extended expiry", parts[0] + ".9999999999." + parts[2], "jane@example.com"},
+		{"other key", other.IssueToken(42, "jane@example.com"), "jane@example.com"},
+		{"malformed", "not-a-token", "jane@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, verifier.Verify(tt.token, tt.email), ErrInvalidVerificationToken)
+		})
+	}
+}
+
+func TestEmailVerifier_RejectsExpiredTokens(t *testing.T) {
+	verifier := newTestEmailVerifier(t, nil)
+	token := verifier.IssueToken(42, "jane@example.com")
+
+	verifier.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	assert.ErrorIs(t, verifier.Verify(token, "jane@example.com"), ErrVerificationTokenExpired)
+}
+
+func TestEmailVerifier_AllowResend(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	verifier := newTestEmailVerifier(t, store)
+
+	retryAfter, err := verifier.AllowResend(ctx, "jane")
+	require.NoError(t, err)
+	assert.Zero(t, retryAfter)
+
+	retryAfter, err = verifier.AllowResend(ctx, "jane")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, retryAfter, "second resend within the cooldown")
+
+	retryAfter, err = verifier.AllowResend(ctx, "john")
+	require.NoError(t, err)
+	assert.Zero(t, retryAfter, "limits are counted per key")
+
+	now = now.Add(2 * time.Minute)
+	retryAfter, err = verifier.AllowResend(ctx, "jane")
+	require.NoError(t, err)
+	assert.Zero(t, retryAfter)
+
+	now = now.Add(2 * time.Minute)
+	retryAfter, err = verifier.AllowResend(ctx, "jane")
+	require.NoError(t, err)
+	assert.Equal(t, 56*time.Minute, retryAfter, "window limit of 2 resends exceeded")
+}
@@ -1,7 +1,9 @@
 package authenticator
 
 import (
+	"context"
 	pkgErrors "ichi-go/pkg/errors"
+	"ichi-go/pkg/logger"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -29,6 +31,16 @@ type JWTConfig struct {
 	PublicKey     interface{}       `yaml:"public_key" json:"public_key" mapstructure:"public_key"`
 	PrivateKey    interface{}       `yaml:"private_key" json:"private_key" mapstructure:"private_key"`
 
+	// Rotating keyset (see JWTKeySet); when set, PublicKey/PrivateKey are ignored.
+	KeysDir            string        `yaml:"keys_dir" json:"keys_dir" mapstructure:"keys_dir"`
+	KeysReloadInterval time.Duration `yaml:"keys_reload_interval" json:"keys_reload_interval" mapstructure:"keys_reload_interval"` // default: 1m
+	KeySet             *JWTKeySet    `yaml:"-" json:"-"`
+
+	// RemoteJWKS lists external issuers whose tokens are accepted as well.
+	RemoteJWKS     []RemoteJWKSConfig `yaml:"remote_jwks" json:"remote_jwks" mapstructure:"remote_jwks"`
+	remoteIssuers  map[string]*RemoteJWKS
+	remoteSubjects map[string]map[string]uint64 // issuer -> subject -> local user ID
+
 	TokenLookup string `yaml:"token_lookup" json:"token_lookup" mapstructure:"token_lookup"`
 	AuthScheme  string `yaml:"auth_scheme" json:"auth_scheme" mapstructure:"auth_scheme"`
 
@@ -43,6 +55,9 @@ type JWTConfig struct {
 	KeyFunc         jwt.Keyfunc        `yaml:"-" json:"-"`
 	SkipPaths       []string           `yaml:"skip_paths" json:"skip_paths" mapstructure:"skip_paths"`
 
+	// RevocationStore enables logout. When nil, tokens stay valid until they expire.
+	RevocationStore RevocationStore `yaml:"-" json:"-"`
+
 	LeewayDuration time.Duration `yaml:"leeway_duration" json:"leeway_duration" mapstructure:"leeway_duration"`
 }
 
@@ -56,7 +71,7 @@ func (a *JWTAuthenticator) Authenticate(c *echo.Context) (*AuthContext, error) {
 			Wrap(err)
 	}
 
-	token, err := ParseToken(tokenString, *a.config)
+	token, err := ParseAccessToken(c.Request().Context(), tokenString, *a.config)
 	if err != nil {
 		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
 			Hint("Invalid token format").
@@ -70,14 +85,25 @@ func (a *JWTAuthenticator) Authenticate(c *echo.Context) (*AuthContext, error) {
 			Wrap(err)
 	}
 
-	user, err := GetUserIdFromMapClaims(claims)
+	// Refresh tokens live for days: they only buy new tokens, never access.
+	if typ, _ := GetClaimString(claims, TokenTypeClaim); typ == TokenTypeRefresh {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			Hint("Refresh tokens cannot be used for access").
+			Errorf("refresh token presented as access token")
+	}
+
+	user, err := a.config.userSubject(claims)
 	if err != nil {
 		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
 			Hint("Invalid user claims").
 			Wrap(err)
 	}
 
-	return &AuthContext{UserID: user}, nil
+	if err := a.checkRevoked(c.Request().Context(), claims, user.ID, false); err != nil {
+		return nil, err
+	}
+
+	return &AuthContext{UserID: user, Claims: claims, Method: MethodJWT}, nil
 }
 
 // GenerateToken creates a new JWT token for the given user ID
@@ -87,34 +113,236 @@ func (a *JWTAuthenticator) GenerateToken(userID uint64) (string, error) {
 }
 
 // GenerateTokens creates both access and refresh tokens for the given user ID
-// Returns a TokenPair with both tokens
-func (a *JWTAuthenticator) GenerateTokens(userID uint64) (*TokenPair, error) {
-	return GenerateTokenPair(userID, *a.config)
+// Returns a TokenPair with both tokens, stamped with the user's current token version
+func (a *JWTAuthenticator) GenerateTokens(ctx context.Context, userID uint64) (*TokenPair, error) {
+	return a.GenerateSessionTokens(ctx, userID, "")
+}
+
+// GenerateSessionTokens is GenerateTokens with both tokens bound to sessionID
+// through the "sid" claim. An empty sessionID omits the claim.
+func (a *JWTAuthenticator) GenerateSessionTokens(ctx context.Context, userID uint64, sessionID string) (*TokenPair, error) {
+	customClaims := map[string]interface{}{}
+	if sessionID != "" {
+		customClaims[SessionIDClaim] = sessionID
+	}
+
+	if a.config.RevocationStore != nil {
+		version, err := a.config.RevocationStore.TokenVersion(ctx, userID)
+		if err != nil {
+			return nil, pkgErrors.Cache(pkgErrors.ErrCodeCache).
+				With("user_id", userID).
+				Hint("Failed to read token version").
+				Wrap(err)
+		}
+		customClaims[TokenVersionClaim] = version
+	}
+
+	return GenerateTokenPairWithClaims(userID, *a.config, customClaims)
 }
 
 // ValidateRefreshToken validates a refresh token and returns the user ID
 // This is useful for token refresh endpoints
-func (a *JWTAuthenticator) ValidateRefreshToken(tokenString string) (uint64, error) {
-	token, err := ParseToken(tokenString, *a.config)
+func (a *JWTAuthenticator) ValidateRefreshToken(ctx context.Context, tokenString string) (uint64, error) {
+	claims, err := a.ParseRefreshToken(ctx, tokenString)
+	if err != nil {
+		return 0, err
+	}
+
+	user, err := GetUserIdFromMapClaims(claims)
 	if err != nil {
 		return 0, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			Hint("Invalid user claims in refresh token").
+			Wrap(err)
+	}
+
+	return user.ID, nil
+}
+
+// ParseRefreshToken validates a refresh token, including its token type and revocation,
+// and returns its claims
+func (a *JWTAuthenticator) ParseRefreshToken(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	token, err := ParseToken(tokenString, *a.config)
+	if err != nil {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
 			Hint("Invalid refresh token format").
 			Wrap(err)
 	}
 
 	claims, err := ValidateToken(token, *a.config)
 	if err != nil {
-		return 0, pkgErrors.AuthService(pkgErrors.ErrCodeTokenExpired).
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeTokenExpired).
 			Hint("Refresh token expired or invalid").
 			Wrap(err)
 	}
 
+	if typ, _ := GetClaimString(claims, TokenTypeClaim); typ != TokenTypeRefresh {
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			Hint("Not a refresh token").
+			Errorf("token type %q is not %q", typ, TokenTypeRefresh)
+	}
+
 	user, err := GetUserIdFromMapClaims(claims)
 	if err != nil {
-		return 0, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+		return nil, pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
 			Hint("Invalid user claims in refresh token").
 			Wrap(err)
 	}
 
-	return user.ID, nil
+	if err := a.checkRevoked(ctx, claims, user.ID, true); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// RevocationEnabled reports whether tokens can be revoked before they expire.
+func (a *JWTAuthenticator) RevocationEnabled() bool {
+	return a.config.RevocationStore != nil
+}
+
+// RevokeToken denylists the token described by claims for the rest of its lifetime.
+func (a *JWTAuthenticator) RevokeToken(ctx context.Context, claims jwt.MapClaims) error {
+	if a.config.RevocationStore == nil {
+		return nil
+	}
+
+	jti, err := GetClaimString(claims, "jti")
+	if err != nil {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			Hint("Token has no jti and cannot be revoked").
+			Wrap(err)
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			Hint("Token has no expiry").
+			Errorf("missing exp claim")
+	}
+
+	ttl := time.Until(exp.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := a.config.RevocationStore.Revoke(ctx, jti, ttl); err != nil {
+		return pkgErrors.Cache(pkgErrors.ErrCodeCache).
+			With("jti", jti).
+			Hint("Failed to revoke token").
+			Wrap(err)
+	}
+	return nil
+}
+
+// RevokeAccessTokenID denylists an access token by jti alone, for when only the
+// id is known (e.g. killing another session). The access TTL bounds its lifetime.
+func (a *JWTAuthenticator) RevokeAccessTokenID(ctx context.Context, jti string) error {
+	if a.config.RevocationStore == nil || jti == "" {
+		return nil
+	}
+
+	if err := a.config.RevocationStore.Revoke(ctx, jti, a.config.AccessTokenTTL); err != nil {
+		return pkgErrors.Cache(pkgErrors.ErrCodeCache).
+			With("jti", jti).
+			Hint("Failed to revoke token").
+			Wrap(err)
+	}
+	return nil
+}
+
+// RevokeTokenString validates tokenString, checks it belongs to userID and revokes it.
+// Used to revoke the refresh token handed in on logout.
+func (a *JWTAuthenticator) RevokeTokenString(ctx context.Context, tokenString string, userID uint64) error {
+	token, err := ParseToken(tokenString, *a.config)
+	if err != nil {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			Hint("Invalid token format").
+			Wrap(err)
+	}
+
+	claims, err := ValidateToken(token, *a.config)
+	if err != nil {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeInvalidToken).
+			Hint("Token expired or invalid").
+			Wrap(err)
+	}
+
+	owner, err := GetUserIdFromMapClaims(claims)
+	if err != nil || owner.ID != userID {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeUnauthorized).
+			With("user_id", userID).
+			Hint("Token does not belong to the current user").
+			Errorf("token subject mismatch")
+	}
+
+	return a.RevokeToken(ctx, claims)
+}
+
+// RevokeAllForUser invalidates every token issued to userID so far by bumping
+// the user's token version. Tokens issued afterwards carry the new version.
+func (a *JWTAuthenticator) RevokeAllForUser(ctx context.Context, userID uint64) error {
+	if a.config.RevocationStore == nil {
+		return nil
+	}
+
+	if _, err := a.config.RevocationStore.IncrementTokenVersion(ctx, userID); err != nil {
+		return pkgErrors.Cache(pkgErrors.ErrCodeCache).
+			With("user_id", userID).
+			Hint("Failed to revoke user tokens").
+			Wrap(err)
+	}
+	return nil
+}
+
+// checkRevoked rejects denylisted tokens and tokens older than the user's token version.
+// When the store fails, a short-lived access token is accepted (failClosed false), so
+// a Redis outage does not lock every user out; a refresh token, which would mint new
+// tokens for its whole lifetime, is rejected (failClosed true).
+func (a *JWTAuthenticator) checkRevoked(ctx context.Context, claims jwt.MapClaims, userID uint64, failClosed bool) error {
+	store := a.config.RevocationStore
+	if store == nil {
+		return nil
+	}
+
+	storeFailed := func(check string, err error) error {
+		if !failClosed {
+			logger.Warnf("%s failed for user %d, accepting token: %v", check, userID, err)
+			return nil
+		}
+		logger.Errorf("%s failed for user %d, rejecting token: %v", check, userID, err)
+		return pkgErrors.Cache(pkgErrors.ErrCodeCache).
+			With("user_id", userID).
+			Hint("Token revocation status unavailable").
+			Wrap(err)
+	}
+
+	if jti, err := GetClaimString(claims, "jti"); err == nil {
+		revoked, err := store.IsRevoked(ctx, jti)
+		if err != nil {
+			if err := storeFailed("token revocation check", err); err != nil {
+				return err
+			}
+		} else if revoked {
+			return pkgErrors.AuthService(pkgErrors.ErrCodeTokenRevoked).
+				Hint("Token has been revoked").
+				Errorf("token %s revoked", jti)
+		}
+	}
+
+	current, err := store.TokenVersion(ctx, userID)
+	if err != nil {
+		return storeFailed("token version check", err)
+	}
+	if current == 0 {
+		return nil
+	}
+
+	issued, _ := GetClaimInt(claims, TokenVersionClaim)
+	if issued < current {
+		return pkgErrors.AuthService(pkgErrors.ErrCodeTokenRevoked).
+			With("user_id", userID).
+			Hint("Token has been revoked").
+			Errorf("token version %d older than current %d", issued, current)
+	}
+	return nil
 }
@@ -6,6 +6,23 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenVersionClaim carries the user's token version at issue time.
+// Tokens with a version older than the one in the RevocationStore are rejected.
+const TokenVersionClaim = "tv"
+
+// SessionIDClaim ties access and refresh tokens to a login session (refresh-token family).
+const SessionIDClaim = "sid"
+
+// TokenTypeClaim tells access tokens from refresh tokens, so one cannot be used as the other.
+const TokenTypeClaim = "typ"
+
+// Values of TokenTypeClaim.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
 )
 
 // StandardClaimsInput contains the input parameters for creating standard JWT claims
@@ -110,6 +127,16 @@ func GenerateToken(claims jwt.Claims, config JWTConfig) (string, error) {
 	// Create token with claims
 	token := jwt.NewWithClaims(config.SigningMethod, claims)
 
+	if config.KeySet != nil {
+		kid, key := config.KeySet.SigningKey()
+		token.Header["kid"] = kid
+		signedToken, err := token.SignedString(key)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign token: %w", err)
+		}
+		return signedToken, nil
+	}
+
 	// Sign token based on signing method
 	var signedToken string
 	var err error
@@ -147,28 +174,28 @@ func GenerateToken(claims jwt.Claims, config JWTConfig) (string, error) {
 // GenerateAccessToken is a convenience function to generate an access token
 // Uses the AccessTokenTTL from config
 func GenerateAccessToken(userID uint64, config JWTConfig) (string, error) {
-	input := StandardClaimsInput{
-		UserID:    userID,
-		Issuer:    config.Issuer,
-		Audience:  config.Audience,
-		ExpiresIn: config.AccessTokenTTL,
-	}
-
-	claims := CreateCustomClaims(input)
-	return GenerateToken(claims, config)
+	return generateTokenWithTTL(userID, config, config.AccessTokenTTL, uuid.NewString(), TokenTypeAccess, nil)
 }
 
 // GenerateRefreshToken is a convenience function to generate a refresh token
 // Uses the RefreshTokenTTL from config
 func GenerateRefreshToken(userID uint64, config JWTConfig) (string, error) {
+	return generateTokenWithTTL(userID, config, config.RefreshTokenTTL, uuid.NewString(), TokenTypeRefresh, nil)
+}
+
+// generateTokenWithTTL signs a token of tokenType carrying jti so it can be revoked individually
+func generateTokenWithTTL(userID uint64, config JWTConfig, ttl time.Duration, jti, tokenType string, customClaims map[string]interface{}) (string, error) {
 	input := StandardClaimsInput{
-		UserID:    userID,
-		Issuer:    config.Issuer,
-		Audience:  config.Audience,
-		ExpiresIn: config.RefreshTokenTTL,
+		UserID:       userID,
+		Issuer:       config.Issuer,
+		Audience:     config.Audience,
+		ExpiresIn:    ttl,
+		JWTID:        jti,
+		CustomClaims: customClaims,
 	}
 
 	claims := CreateCustomClaims(input)
+	claims[TokenTypeClaim] = tokenType
 	return GenerateToken(claims, config)
 }
 
@@ -178,27 +205,45 @@ type TokenPair struct {
 	RefreshToken string `json:"refresh_token"`
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int64  `json:"expires_in"` // seconds until access token expires
+
+	// Server-side bookkeeping for session tracking; never serialised.
+	AccessTokenID    string    `json:"-"`
+	RefreshTokenID   string    `json:"-"`
+	RefreshExpiresAt time.Time `json:"-"`
 }
 
 // GenerateTokenPair generates both access and refresh tokens
 // This is the recommended way to generate tokens for authentication flows
 func GenerateTokenPair(userID uint64, config JWTConfig) (*TokenPair, error) {
+	return GenerateTokenPairWithClaims(userID, config, nil)
+}
+
+// GenerateTokenPairWithClaims generates both tokens with extra custom claims.
+// Each token gets its own jti.
+func GenerateTokenPairWithClaims(userID uint64, config JWTConfig, customClaims map[string]interface{}) (*TokenPair, error) {
+	now := time.Now()
+	accessID := uuid.NewString()
+	refreshID := uuid.NewString()
+
 	// Generate access token
-	accessToken, err := GenerateAccessToken(userID, config)
+	accessToken, err := generateTokenWithTTL(userID, config, config.AccessTokenTTL, accessID, TokenTypeAccess, customClaims)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Generate refresh token
-	refreshToken, err := GenerateRefreshToken(userID, config)
+	refreshToken, err := generateTokenWithTTL(userID, config, config.RefreshTokenTTL, refreshID, TokenTypeRefresh, customClaims)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
 	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		TokenType:    config.AuthScheme,
-		ExpiresIn:    int64(config.AccessTokenTTL.Seconds()),
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        config.AuthScheme,
+		ExpiresIn:        int64(config.AccessTokenTTL.Seconds()),
+		AccessTokenID:    accessID,
+		RefreshTokenID:   refreshID,
+		RefreshExpiresAt: now.Add(config.RefreshTokenTTL),
 	}, nil
 }
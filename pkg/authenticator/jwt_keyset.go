@@ -0,0 +1,367 @@
+package authenticator
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"ichi-go/pkg/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	// Keyset directory layout: "<kid>.pem" private key, "<kid>.pub.pem" public key and
+	// "active" holding the kid that signs new tokens.
+	keySetPrivateSuffix = ".pem"
+	keySetPublicSuffix  = ".pub.pem"
+	keySetActiveFile    = "active"
+
+	defaultKeysReloadInterval = time.Minute
+)
+
+// JWTKeySet is a rotating set of asymmetric signing keys read from a directory.
+// The active key signs new tokens; every key still in the directory verifies them.
+//
+// Rotation without restart:
+//  1. add "<new>.pem" and wait for JWKS consumers to refresh (the new key is published)
+//  2. write "<new>" into "active"; new tokens carry kid "<new>"
+//  3. once the old tokens have expired, delete the old key's private key, then its
+//     public key to retire it
+type JWTKeySet struct {
+	dir    string
+	method jwt.SigningMethod
+
+	mu     sync.RWMutex
+	active string
+	keys   map[string]*jwtKey
+}
+
+type jwtKey struct {
+	private crypto.Signer // nil for verify-only keys
+	public  crypto.PublicKey
+}
+
+// LoadJWTKeySet reads the keyset in dir. method must be an RSA, RSA-PSS or ECDSA method.
+func LoadJWTKeySet(dir string, method jwt.SigningMethod) (*JWTKeySet, error) {
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+	default:
+		return nil, fmt.Errorf("key sets require an asymmetric signing method, got %s", method.Alg())
+	}
+	s := &JWTKeySet{dir: dir, method: method}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads the directory. On error the current keys stay in use.
+func (s *JWTKeySet) Reload() error {
+	keys, active, err := readKeySetDir(s.dir, s.method)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	changed := active != s.active || !slices.Equal(sortedKeys(keys), sortedKeys(s.keys))
+	s.active, s.keys = active, keys
+	s.mu.Unlock()
+
+	if changed {
+		logger.Infof("JWT keyset loaded from %s: active=%s keys=%v", s.dir, active, sortedKeys(keys))
+	}
+	return nil
+}
+
+// ReloadEvery reloads the keyset every interval until ctx is done.
+func (s *JWTKeySet) ReloadEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(); err != nil {
+				logger.Warnf("JWT keyset reload failed, keeping current keys: %v", err)
+			}
+		}
+	}
+}
+
+// SigningKey returns the kid and private key that sign new tokens.
+func (s *JWTKeySet) SigningKey() (string, crypto.Signer) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active, s.keys[s.active].private
+}
+
+// VerificationKey returns the public key of kid. Tokens without a kid are verified
+// with the active key, so tokens issued before the keyset was introduced keep working.
+func (s *JWTKeySet) VerificationKey(kid string) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if kid == "" {
+		kid = s.active
+	}
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown or retired signing key %q", kid)
+	}
+	return key.public, nil
+}
+
+// JWKS returns the public keys of the set, active key first.
+func (s *JWTKeySet) JWKS() (JWKS, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	set := JWKS{Keys: []JWK{}}
+	kids := sortedKeys(s.keys)
+	slices.SortStableFunc(kids, func(a, b string) int {
+		if a == s.active {
+			return -1
+		}
+		if b == s.active {
+			return 1
+		}
+		return 0
+	})
+	for _, kid := range kids {
+		jwk, err := NewJWK(kid, s.method.Alg(), s.keys[kid].public)
+		if err != nil {
+			return JWKS{}, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+func readKeySetDir(dir string, method jwt.SigningMethod) (map[string]*jwtKey, string, error) {
+	activeBytes, err := os.ReadFile(filepath.Join(dir, keySetActiveFile))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read active key id: %w", err)
+	}
+	active := strings.TrimSpace(string(activeBytes))
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read keyset directory: %w", err)
+	}
+
+	keys := make(map[string]*jwtKey)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(name, keySetPublicSuffix):
+			kid := strings.TrimSuffix(name, keySetPublicSuffix)
+			public, err := LoadPublicKey(filepath.Join(dir, name), method)
+			if err != nil {
+				return nil, "", fmt.Errorf("key %s: %w", kid, err)
+			}
+			if keys[kid] == nil {
+				keys[kid] = &jwtKey{}
+			}
+			keys[kid].public = public
+		case strings.HasSuffix(name, keySetPrivateSuffix):
+			kid := strings.TrimSuffix(name, keySetPrivateSuffix)
+			private, err := LoadPrivateKey(filepath.Join(dir, name), method)
+			if err != nil {
+				return nil, "", fmt.Errorf("key %s: %w", kid, err)
+			}
+			signer, ok := private.(crypto.Signer)
+			if !ok {
+				return nil, "", fmt.Errorf("key %s: private key cannot sign", kid)
+			}
+			if keys[kid] == nil {
+				keys[kid] = &jwtKey{}
+			}
+			keys[kid].private = signer
+		}
+	}
+
+	for _, key := range keys {
+		if key.public == nil {
+			key.public = key.private.Public()
+		}
+	}
+	if keys[active] == nil || keys[active].private == nil {
+		return nil, "", fmt.Errorf("active key %q has no private key in %s", active, dir)
+	}
+	return keys, active, nil
+}
+
+func sortedKeys(keys map[string]*jwtKey) []string {
+	kids := make([]string, 0, len(keys))
+	for kid := range keys {
+		kids = append(kids, kid)
+	}
+	slices.Sort(kids)
+	return kids
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a public RSA or EC JSON Web Key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// NewJWK encodes an RSA or ECDSA public key for publication.
+func NewJWK(kid, alg string, publicKey crypto.PublicKey) (JWK, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA", Kid: kid, Use: "sig", Alg: alg,
+			N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		point, err := key.Bytes()
+		if err != nil {
+			return JWK{}, err
+		}
+		size := (len(point) - 1) / 2
+		return JWK{
+			Kty: "EC", Kid: kid, Use: "sig", Alg: alg,
+			Crv: key.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+			Y:   base64.RawURLEncoding.EncodeToString(point[1+size:]),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
+
+// PublicKey decodes the JWK into an *rsa.PublicKey or *ecdsa.PublicKey.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return nil, errors.New("invalid EC coordinates")
+		}
+		return ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, x...), y...))
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// JWKThumbprint returns the RFC 7638 SHA-256 thumbprint of a public key, used as
+// the kid of a single configured key.
+func JWKThumbprint(publicKey crypto.PublicKey) (string, error) {
+	jwk, err := NewJWK("", "", publicKey)
+	if err != nil {
+		return "", err
+	}
+	// Required members only, in lexicographic order.
+	var canonical string
+	if jwk.Kty == "RSA" {
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	} else {
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, jwk.Crv, jwk.X, jwk.Y)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// PublicJWKS returns the keys other services need to verify our tokens: the keyset,
+// or the single configured public key. HMAC secrets are never published.
+func (j *JWTConfig) PublicJWKS() (JWKS, error) {
+	if j.KeySet != nil {
+		return j.KeySet.JWKS()
+	}
+	switch j.SigningMethod.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+	default:
+		return JWKS{Keys: []JWK{}}, nil
+	}
+	if j.PublicKey == nil {
+		return JWKS{Keys: []JWK{}}, nil
+	}
+	if _, isPath := j.PublicKey.(string); isPath {
+		return JWKS{}, errors.New("JWT public key not loaded")
+	}
+	kid, err := JWKThumbprint(j.PublicKey)
+	if err != nil {
+		return JWKS{}, err
+	}
+	jwk, err := NewJWK(kid, j.SigningMethod.Alg(), j.PublicKey)
+	if err != nil {
+		return JWKS{}, err
+	}
+	return JWKS{Keys: []JWK{jwk}}, nil
+}
+
+// JWKSHandler serves PublicJWKS, e.g. at /.well-known/jwks.json.
+func JWKSHandler(config *JWTConfig) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		set, err := config.PublicJWKS()
+		if err != nil {
+			logger.Errorf("Failed to build JWKS: %v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "JWKS unavailable")
+		}
+		c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+		body, err := json.Marshal(set)
+		if err != nil {
+			return err
+		}
+		return c.Blob(http.StatusOK, "application/jwk-set+json", body)
+	}
+}
@@ -0,0 +1,318 @@
+package authenticator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func writeKeyFile(t *testing.T, dir, kid string, key *ecdsa.PrivateKey) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600))
+}
+
+func writePublicKeyFile(t *testing.T, dir, kid string, key *ecdsa.PrivateKey) {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pub.pem"), data, 0o644))
+}
+
+func setActiveKey(t *testing.T, dir, kid string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "active"), []byte(kid+"\n"), 0o644))
+}
+
+func newKeySetConfig(t *testing.T, dir string) JWTConfig {
+	t.Helper()
+	keySet, err := LoadJWTKeySet(dir, jwt.SigningMethodES256)
+	require.NoError(t, err)
+	return JWTConfig{
+		Enabled:        true,
+		SigningMethod:  jwt.SigningMethodES256,
+		Issuer:         "ichi",
+		AccessTokenTTL: 15 * time.Minute,
+		KeySet:         keySet,
+	}
+}
+
+func tokenKid(t *testing.T, tokenString string) string {
+	t.Helper()
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	require.NoError(t, err)
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
+
+func TestJWTKeySet_RotationKeepsOutstandingTokensValid(t *testing.T) {
+	dir := t.TempDir()
+	oldKey := newECKey(t)
+	writeKeyFile(t, dir, "2026-04", oldKey)
+	setActiveKey(t, dir, "2026-04")
+	cfg := newKeySetConfig(t, dir)
+
+	oldToken, err := GenerateAccessToken(1, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-04", tokenKid(t, oldToken))
+
+	// Rotate: add the new key, make it active, keep the old one for verification.
+	writeKeyFile(t, dir, "2026-10", newECKey(t))
+	setActiveKey(t, dir, "2026-10")
+	require.NoError(t, cfg.KeySet.Reload())
+
+	newToken, err := GenerateAccessToken(1, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", tokenKid(t, newToken))
+
+	for _, tok := range []string{oldToken, newToken} {
+		_, err := ParseToken(tok, cfg)
+		assert.NoError(t, err)
+	}
+
+	// Retire the old key: first drop its private key, then its public key.
+	require.NoError(t, os.Remove(filepath.Join(dir, "2026-04.pem")))
+	writePublicKeyFile(t, dir, "2026-04", oldKey)
+	require.NoError(t, cfg.KeySet.Reload())
+	_, err = ParseToken(oldToken, cfg)
+	assert.NoError(t, err, "verify-only key still verifies")
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "2026-04.pub.pem")))
+	require.NoError(t, cfg.KeySet.Reload())
+	_, err = ParseToken(oldToken, cfg)
+	assert.Error(t, err, "retired key no longer verifies")
+	_, err = ParseToken(newToken, cfg)
+	assert.NoError(t, err)
+}
+
+func TestJWTKeySet_FailedReloadKeepsCurrentKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeKeyFile(t, dir, "k1", newECKey(t))
+	setActiveKey(t, dir, "k1")
+	cfg := newKeySetConfig(t, dir)
+
+	setActiveKey(t, dir, "missing")
+	assert.Error(t, cfg.KeySet.Reload())
+
+	kid, signer := cfg.KeySet.SigningKey()
+	assert.Equal(t, "k1", kid)
+	assert.NotNil(t, signer)
+}
+
+func TestJWKSHandler_PublishesKeySet(t *testing.T) {
+	dir := t.TempDir()
+	active := newECKey(t)
+	writeKeyFile(t, dir, "b-active", active)
+	writePublicKeyFile(t, dir, "a-old", newECKey(t))
+	setActiveKey(t, dir, "b-active")
+	cfg := newKeySetConfig(t, dir)
+
+	e := echo.New()
+	e.GET("/.well-known/jwks.json", JWKSHandler(&cfg))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var set JWKS
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &set))
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, "b-active", set.Keys[0].Kid, "active key is listed first")
+	assert.Equal(t, "ES256", set.Keys[0].Alg)
+
+	public, err := set.Keys[0].PublicKey()
+	require.NoError(t, err)
+	assert.True(t, active.PublicKey.Equal(public))
+}
+
+func TestJWKSHandler_HMACPublishesNothing(t *testing.T) {
+	cfg := &JWTConfig{SigningMethod: jwt.SigningMethodHS256, SecretKey: []byte("secret")}
+	set, err := cfg.PublicJWKS()
+	require.NoError(t, err)
+	assert.Empty(t, set.Keys)
+}
+
+// newRemoteIssuer serves a JWKS for key and counts fetches.
+func newRemoteIssuer(t *testing.T, kid string, key *ecdsa.PrivateKey) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	jwk, err := NewJWK(kid, "ES256", &key.PublicKey)
+	require.NoError(t, err)
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		_ = json.NewEncoder(w).Encode(JWKS{Keys: []JWK{jwk}})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func signExternal(t *testing.T, issuer, kid string, key *ecdsa.PrivateKey) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"sub": "42",
+		"iss": issuer,
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func newRemoteJWKSConfig(t *testing.T, url string) JWTConfig {
+	t.Helper()
+	cfg := JWTConfig{
+		Enabled:        true,
+		SigningMethod:  jwt.SigningMethodHS256,
+		SecretKey:      []byte("test-secret-key-minimum-32-chars-long"),
+		Issuer:         "ichi",
+		AccessTokenTTL: 15 * time.Minute,
+		RemoteJWKS:     []RemoteJWKSConfig{{Issuer: "https://id.example.com", URL: url}},
+	}
+	require.NoError(t, cfg.InitializeKeys())
+	return cfg
+}
+
+func TestRemoteJWKS_VerifiesExternalIssuerWithCachedKeys(t *testing.T) {
+	externalKey := newECKey(t)
+	srv, hits := newRemoteIssuer(t, "ext-1", externalKey)
+	cfg := newRemoteJWKSConfig(t, srv.URL)
+
+	external := signExternal(t, "https://id.example.com", "ext-1", externalKey)
+	for i := 0; i < 3; i++ {
+		token, err := ParseAccessToken(context.Background(), external, cfg)
+		require.NoError(t, err)
+		claims, err := ValidateToken(token, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "42", claims["sub"])
+	}
+	assert.Equal(t, int32(1), hits.Load(), "keys are cached")
+
+	// Our own tokens keep working alongside.
+	local, err := GenerateAccessToken(1, cfg)
+	require.NoError(t, err)
+	_, err = ParseAccessToken(context.Background(), local, cfg)
+	assert.NoError(t, err)
+	_, err = ParseToken(local, cfg)
+	assert.NoError(t, err)
+}
+
+func TestRemoteJWKS_OnlyAccessChecksTrustExternalIssuers(t *testing.T) {
+	externalKey := newECKey(t)
+	srv, _ := newRemoteIssuer(t, "ext-1", externalKey)
+	cfg := newRemoteJWKSConfig(t, srv.URL)
+	external := signExternal(t, "https://id.example.com", "ext-1", externalKey)
+
+	_, err := ParseToken(external, cfg)
+	assert.Error(t, err)
+
+	a := NewJWTAuthenticator(&cfg)
+	_, err = a.ParseRefreshToken(context.Background(), external)
+	assert.Error(t, err, "an external token does not refresh a session")
+	assert.Error(t, a.RevokeTokenString(context.Background(), external, 42))
+}
+
+func TestRemoteJWKS_SubjectsOnlyActAsMappedUsers(t *testing.T) {
+	externalKey := newECKey(t)
+	srv, _ := newRemoteIssuer(t, "ext-1", externalKey)
+	external := signExternal(t, "https://id.example.com", "ext-1", externalKey)
+
+	authenticate := func(cfg JWTConfig) (*AuthContext, error) {
+		cfg.AuthScheme = "Bearer"
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+external)
+		return NewJWTAuthenticator(&cfg).Authenticate(echo.New().NewContext(req, httptest.NewRecorder()))
+	}
+
+	// The external "42" is not local user 42.
+	_, err := authenticate(newRemoteJWKSConfig(t, srv.URL))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not mapped", "an unmapped subject is rejected")
+
+	cfg := JWTConfig{
+		Enabled:        true,
+		SigningMethod:  jwt.SigningMethodHS256,
+		SecretKey:      []byte("test-secret-key-minimum-32-chars-long"),
+		Issuer:         "ichi",
+		AccessTokenTTL: 15 * time.Minute,
+		RemoteJWKS: []RemoteJWKSConfig{{
+			Issuer:   "https://id.example.com",
+			URL:      srv.URL,
+			Subjects: []RemoteSubjectConfig{{Subject: "42", UserID: 7}},
+		}},
+	}
+	require.NoError(t, cfg.InitializeKeys())
+	authCtx, err := authenticate(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), authCtx.UserID.ID)
+}
+
+func TestRemoteJWKS_ConcurrentRequestsShareOneFetch(t *testing.T) {
+	externalKey := newECKey(t)
+	srv, hits := newRemoteIssuer(t, "ext-1", externalKey)
+	jwks := NewRemoteJWKS(srv.URL, 0, nil)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			_, err := jwks.Key(context.Background(), "ext-1", jwt.SigningMethodES256)
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), hits.Load())
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := jwks.Key(cancelled, "unknown", jwt.SigningMethodES256)
+	assert.Error(t, err)
+}
+
+func TestRemoteJWKS_RejectsUntrustedIssuersAndForeignKeys(t *testing.T) {
+	externalKey := newECKey(t)
+	srv, _ := newRemoteIssuer(t, "ext-1", externalKey)
+	cfg := newRemoteJWKSConfig(t, srv.URL)
+
+	ctx := context.Background()
+	_, err := ParseAccessToken(ctx, signExternal(t, "https://evil.example.com", "ext-1", externalKey), cfg)
+	assert.Error(t, err, "issuer not configured")
+
+	_, err = ParseAccessToken(ctx, signExternal(t, "https://id.example.com", "ext-1", newECKey(t)), cfg)
+	assert.Error(t, err, "signed with a key the issuer did not publish")
+
+	// HS256 token keyed with the issuer's public key must not be accepted.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "42", "iss": "https://id.example.com", "exp": time.Now().Add(time.Minute).Unix(),
+	})
+	forged.Header["kid"] = "ext-1"
+	forgedString, err := forged.SignedString([]byte("anything"))
+	require.NoError(t, err)
+	_, err = ParseAccessToken(ctx, forgedString, cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,221 @@
+package authenticator
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"ichi-go/pkg/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultRemoteJWKSCacheTTL = 10 * time.Minute
+	// remoteJWKSMinRefresh limits refetches triggered by unknown kids.
+	remoteJWKSMinRefresh = 30 * time.Second
+	remoteJWKSMaxBytes   = 1 << 20
+)
+
+// RemoteJWKSConfig trusts tokens of an external issuer, verified with the keys
+// it publishes. The issuer's subjects are its own, not user IDs of this service:
+// a token is only accepted when Subjects maps its subject to a local user.
+type RemoteJWKSConfig struct {
+	Issuer   string                `yaml:"issuer" json:"issuer" mapstructure:"issuer"`          // expected "iss" claim
+	URL      string                `yaml:"url" json:"url" mapstructure:"url"`                   // e.g. https://id.example.com/.well-known/jwks.json
+	CacheTTL time.Duration         `yaml:"cache_ttl" json:"cache_ttl" mapstructure:"cache_ttl"` // default: 10m
+	Subjects []RemoteSubjectConfig `yaml:"subjects" json:"subjects" mapstructure:"subjects"`
+}
+
+// RemoteSubjectConfig lets the external subject act as the local user UserID.
+// Subjects are case-sensitive, which Viper map keys are not, so they are a list.
+type RemoteSubjectConfig struct {
+	Subject string `yaml:"subject" json:"subject" mapstructure:"subject"`
+	UserID  uint64 `yaml:"user_id" json:"user_id" mapstructure:"user_id"`
+}
+
+// RemoteJWKS fetches and caches the key set of an external issuer. Keys are
+// refreshed after CacheTTL, or earlier when a token names an unknown kid.
+type RemoteJWKS struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	// fetches lets concurrent requests share one fetch, made without holding mu.
+	fetches singleflight.Group
+
+	mu          sync.Mutex
+	keys        map[string]remoteKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	fetching    bool
+}
+
+type remoteKey struct {
+	alg    string
+	public crypto.PublicKey
+}
+
+// NewRemoteJWKS creates a cache for url. A nil client uses a client with a 10s timeout.
+func NewRemoteJWKS(url string, ttl time.Duration, client *http.Client) *RemoteJWKS {
+	if ttl <= 0 {
+		ttl = defaultRemoteJWKSCacheTTL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &RemoteJWKS{url: url, ttl: ttl, client: client}
+}
+
+// Key returns the public key of kid for a token signed with method. An empty kid
+// is accepted when the set holds a single key. A request needing a refresh waits
+// for it until ctx is done.
+func (r *RemoteJWKS) Key(ctx context.Context, kid string, method jwt.SigningMethod) (crypto.PublicKey, error) {
+	r.mu.Lock()
+	now := time.Now()
+	stale := now.Sub(r.fetchedAt) >= r.ttl
+	_, known := r.lookup(kid)
+	refresh := (stale || !known) && (r.fetching || now.Sub(r.lastAttempt) >= remoteJWKSMinRefresh)
+	if refresh && !r.fetching {
+		r.lastAttempt = now
+		r.fetching = true
+	}
+	r.mu.Unlock()
+
+	if refresh {
+		// The fetch outlives a cancelled request: other requests may be waiting on it.
+		done := r.fetches.DoChan("keys", func() (interface{}, error) {
+			return nil, r.refresh(context.WithoutCancel(ctx), now)
+		})
+		select {
+		case res := <-done:
+			if res.Err != nil {
+				// Keep verifying with the cached keys while the issuer is unreachable.
+				logger.Warnf("Failed to refresh JWKS from %s: %v", r.url, res.Err)
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	r.mu.Lock()
+	key, ok := r.lookup(kid)
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no key %q in JWKS %s", kid, r.url)
+	}
+	if key.alg != "" && key.alg != method.Alg() {
+		return nil, fmt.Errorf("key %q is for %s, token uses %s", kid, key.alg, method.Alg())
+	}
+	if !publicKeyMatchesMethod(key.public, method) {
+		return nil, fmt.Errorf("key %q does not match signing method %s", kid, method.Alg())
+	}
+	return key.public, nil
+}
+
+// lookup must be called with mu held.
+func (r *RemoteJWKS) lookup(kid string) (remoteKey, bool) {
+	if kid == "" {
+		if len(r.keys) != 1 {
+			return remoteKey{}, false
+		}
+		for _, key := range r.keys {
+			return key, true
+		}
+	}
+	key, ok := r.keys[kid]
+	return key, ok
+}
+
+// refresh fetches the key set unless it was fetched after since, by a fetch that
+// finished between the caller's check and this one.
+func (r *RemoteJWKS) refresh(ctx context.Context, since time.Time) error {
+	defer func() {
+		r.mu.Lock()
+		r.fetching = false
+		r.mu.Unlock()
+	}()
+	r.mu.Lock()
+	fresh := r.fetchedAt.After(since)
+	r.mu.Unlock()
+	if fresh {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(io.LimitReader(resp.Body, remoteJWKSMaxBytes)).Decode(&set); err != nil {
+		return fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]remoteKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		public, err := jwk.PublicKey()
+		if err != nil {
+			logger.Warnf("Skipping key %q of JWKS %s: %v", jwk.Kid, r.url, err)
+			continue
+		}
+		keys[jwk.Kid] = remoteKey{alg: jwk.Alg, public: public}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = keys
+	r.fetchedAt = time.Now()
+	return nil
+}
+
+// publicKeyMatchesMethod prevents a token from choosing an algorithm that does not
+// belong to the key, e.g. HS256 with an RSA public key as the secret.
+func publicKeyMatchesMethod(key crypto.PublicKey, method jwt.SigningMethod) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		switch method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return true
+		}
+	case *ecdsa.PublicKey:
+		_, ok := method.(*jwt.SigningMethodECDSA)
+		return ok
+	}
+	return false
+}
+
+// userSubject returns the local user a validated token acts as. Tokens of this
+// service carry the user ID as subject; tokens of external issuers only act as
+// the users their subject is mapped to.
+func (j *JWTConfig) userSubject(claims jwt.MapClaims) (UserSubject, error) {
+	issuer, _ := claims.GetIssuer()
+	if _, remote := j.remoteIssuers[issuer]; !remote || issuer == j.Issuer {
+		return GetUserIdFromMapClaims(claims)
+	}
+	subject, _ := claims.GetSubject()
+	userID, ok := j.remoteSubjects[issuer][subject]
+	if !ok {
+		return UserSubject{}, fmt.Errorf("subject %q of issuer %q is not mapped to a user", subject, issuer)
+	}
+	return UserSubject{ID: userID}, nil
+}
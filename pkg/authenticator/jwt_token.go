@@ -1,6 +1,7 @@
 package authenticator
 
 import (
+	"context"
 	"fmt"
 	"ichi-go/pkg/requestctx"
 	"strings"
@@ -68,9 +69,22 @@ func ExtractTokenFromContext(reqCtx requestctx.RequestContext, config JWTConfig)
 	return parts[1], nil
 }
 
-// ParseToken parses a JWT token string and validates its signature
+// ParseToken parses a JWT token string issued by this service and validates its signature
 // Supports multiple signing methods: HMAC (HS*), RSA (RS*), ECDSA (ES*)
 func ParseToken(tokenString string, config JWTConfig) (*jwt.Token, error) {
+	return parseToken(context.Background(), tokenString, config, false)
+}
+
+// ParseAccessToken is ParseToken also accepting tokens of the trusted external
+// issuers (RemoteJWKS). Only access checks use it: external tokens never refresh
+// or revoke sessions of this service.
+func ParseAccessToken(ctx context.Context, tokenString string, config JWTConfig) (*jwt.Token, error) {
+	return parseToken(ctx, tokenString, config, true)
+}
+
+func parseToken(ctx context.Context, tokenString string, config JWTConfig, acceptRemote bool) (*jwt.Token, error) {
+	acceptRemote = acceptRemote && len(config.remoteIssuers) > 0
+
 	// Set up parser options
 	var parserOptions []jwt.ParserOption
 
@@ -79,8 +93,9 @@ func ParseToken(tokenString string, config JWTConfig) (*jwt.Token, error) {
 		parserOptions = append(parserOptions, jwt.WithLeeway(config.LeewayDuration))
 	}
 
-	// Add issuer validation if configured
-	if config.Issuer != "" {
+	// Add issuer validation if configured. With external issuers the key function
+	// checks the issuer, since it decides which keys apply.
+	if config.Issuer != "" && !acceptRemote {
 		parserOptions = append(parserOptions, jwt.WithIssuer(config.Issuer))
 	}
 
@@ -96,11 +111,29 @@ func ParseToken(tokenString string, config JWTConfig) (*jwt.Token, error) {
 			return config.KeyFunc(token)
 		}
 
+		kid, _ := token.Header["kid"].(string)
+
+		// Tokens of trusted external issuers are verified with their published keys
+		if acceptRemote {
+			issuer, _ := token.Claims.GetIssuer()
+			if issuer != config.Issuer {
+				remote, ok := config.remoteIssuers[issuer]
+				if !ok {
+					return nil, fmt.Errorf("untrusted issuer %q", issuer)
+				}
+				return remote.Key(ctx, kid, token.Method)
+			}
+		}
+
 		// Verify signing method matches configuration
 		if !isSigningMethodValid(token.Method, config.SigningMethod) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
 		}
 
+		if config.KeySet != nil {
+			return config.KeySet.VerificationKey(kid)
+		}
+
 		// Return appropriate key based on signing method
 		switch config.SigningMethod.(type) {
 		case *jwt.SigningMethodHMAC:
@@ -0,0 +1,313 @@
+package authenticator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	loginFailuresKeyPrefix = "auth:login:failures:"
+	loginLockKeyPrefix     = "auth:login:lock:"
+)
+
+// LoginThrottleConfig controls brute-force protection on password login.
+// Failed attempts are counted per email and per client IP within Window;
+// reaching the limit locks that email or IP for LockoutDuration.
+type LoginThrottleConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+
+	MaxAttempts      int           `yaml:"max_attempts" json:"max_attempts" mapstructure:"max_attempts"`                      // per email (default: 5)
+	MaxAttemptsPerIP int           `yaml:"max_attempts_per_ip" json:"max_attempts_per_ip" mapstructure:"max_attempts_per_ip"` // per client IP (default: 20)
+	Window           time.Duration `yaml:"window" json:"window" mapstructure:"window"`                                        // failure counting window (default: 15m)
+	LockoutDuration  time.Duration `yaml:"lockout_duration" json:"lockout_duration" mapstructure:"lockout_duration"`          // default: 15m
+
+	// Progressive delay before verifying a password: BaseDelay doubled for every
+	// recent failure of the email, capped at MaxDelay. Zero BaseDelay disables it.
+	BaseDelay time.Duration `yaml:"base_delay" json:"base_delay" mapstructure:"base_delay"`
+	MaxDelay  time.Duration `yaml:"max_delay" json:"max_delay" mapstructure:"max_delay"`
+}
+
+func (c *LoginThrottleConfig) applyDefaults() {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.MaxAttemptsPerIP <= 0 {
+		c.MaxAttemptsPerIP = 20
+	}
+	if c.Window <= 0 {
+		c.Window = 15 * time.Minute
+	}
+	if c.LockoutDuration <= 0 {
+		c.LockoutDuration = 15 * time.Minute
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = 5 * time.Second
+	}
+}
+
+// LoginAttemptStore keeps failed-login counters and lockouts.
+type LoginAttemptStore interface {
+	// IncrementFailures adds a failure to key. The counter expires window after the first failure.
+	IncrementFailures(ctx context.Context, key string, window time.Duration) (int64, error)
+	// Failures returns the current failure count of key (0 when none).
+	Failures(ctx context.Context, key string) (int64, error)
+	// Lock locks key for ttl.
+	Lock(ctx context.Context, key string, ttl time.Duration) error
+	// LockRemaining returns how long key stays locked (0 when not locked).
+	LockRemaining(ctx context.Context, key string) (time.Duration, error)
+	// Clear removes the failure counter and lock of key.
+	Clear(ctx context.Context, key string) error
+}
+
+// LoginThrottle applies LoginThrottleConfig on top of a LoginAttemptStore.
+type LoginThrottle struct {
+	store  LoginAttemptStore
+	config LoginThrottleConfig
+}
+
+// LoginFailure is the outcome of recording a failed login.
+type LoginFailure struct {
+	Failures    int64 // recent failures of the email
+	EmailLocked bool  // this failure locked the email
+	IPLocked    bool  // this failure locked the client IP
+}
+
+func NewLoginThrottle(store LoginAttemptStore, config LoginThrottleConfig) *LoginThrottle {
+	config.applyDefaults()
+	return &LoginThrottle{store: store, config: config}
+}
+
+// LockoutDuration is how long an email or IP stays locked after too many failures.
+func (t *LoginThrottle) LockoutDuration() time.Duration {
+	return t.config.LockoutDuration
+}
+
+// Locked returns the remaining lockout of the email or client IP, whichever is longer.
+func (t *LoginThrottle) Locked(ctx context.Context, email, ip string) (time.Duration, error) {
+	remaining, err := t.store.LockRemaining(ctx, emailThrottleKey(email))
+	if err != nil {
+		return 0, err
+	}
+	if ip == "" {
+		return remaining, nil
+	}
+	ipRemaining, err := t.store.LockRemaining(ctx, ipThrottleKey(ip))
+	if err != nil {
+		return 0, err
+	}
+	return max(remaining, ipRemaining), nil
+}
+
+// Delay returns how long to pause before checking the password of email.
+func (t *LoginThrottle) Delay(ctx context.Context, email string) (time.Duration, error) {
+	if t.config.BaseDelay <= 0 {
+		return 0, nil
+	}
+	failures, err := t.store.Failures(ctx, emailThrottleKey(email))
+	if err != nil || failures <= 0 {
+		return 0, err
+	}
+	delay := t.config.BaseDelay
+	for i := int64(1); i < failures && delay < t.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, t.config.MaxDelay), nil
+}
+
+// RecordFailure counts a failed login for the email and client IP and locks
+// whichever reached its limit.
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email, ip string) (LoginFailure, error) {
+	var result LoginFailure
+
+	emailKey := emailThrottleKey(email)
+	failures, err := t.store.IncrementFailures(ctx, emailKey, t.config.Window)
+	if err != nil {
+		return result, err
+	}
+	result.Failures = failures
+	if failures >= int64(t.config.MaxAttempts) {
+		if err := t.lock(ctx, emailKey); err != nil {
+			return result, err
+		}
+		result.EmailLocked = true
+	}
+
+	if ip == "" {
+		return result, nil
+	}
+	ipKey := ipThrottleKey(ip)
+	ipFailures, err := t.store.IncrementFailures(ctx, ipKey, t.config.Window)
+	if err != nil {
+		return result, err
+	}
+	if ipFailures >= int64(t.config.MaxAttemptsPerIP) {
+		if err := t.lock(ctx, ipKey); err != nil {
+			return result, err
+		}
+		result.IPLocked = true
+	}
+	return result, nil
+}
+
+// RecordSuccess forgets the failures of email. The IP counter is kept so a
+// sprayer cannot reset it by logging into their own account.
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, email string) error {
+	return t.store.Clear(ctx, emailThrottleKey(email))
+}
+
+// UnlockEmail lifts the lockout of email and resets its failures.
+func (t *LoginThrottle) UnlockEmail(ctx context.Context, email string) error {
+	return t.store.Clear(ctx, emailThrottleKey(email))
+}
+
+// UnlockIP lifts the lockout of a client IP and resets its failures.
+func (t *LoginThrottle) UnlockIP(ctx context.Context, ip string) error {
+	return t.store.Clear(ctx, ipThrottleKey(ip))
+}
+
+// lock starts a lockout and resets the counter so the next window starts fresh.
+func (t *LoginThrottle) lock(ctx context.Context, key string) error {
+	if err := t.store.Clear(ctx, key); err != nil {
+		return err
+	}
+	return t.store.Lock(ctx, key, t.config.LockoutDuration)
+}
+
+func emailThrottleKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// RedisLoginAttemptStore is the production LoginAttemptStore, shared by all pods.
+type RedisLoginAttemptStore struct {
+	client *redis.Client
+}
+
+func NewRedisLoginAttemptStore(client *redis.Client) *RedisLoginAttemptStore {
+	return &RedisLoginAttemptStore{client: client}
+}
+
+func (s *RedisLoginAttemptStore) IncrementFailures(ctx context.Context, key string, window time.Duration) (int64, error) {
+	failuresKey := loginFailuresKeyPrefix + key
+	n, err := s.client.Incr(ctx, failuresKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("increment login failures: %w", err)
+	}
+	if n == 1 {
+		if err := s.client.Expire(ctx, failuresKey, window).Err(); err != nil {
+			return 0, fmt.Errorf("expire login failures: %w", err)
+		}
+	}
+	return n, nil
+}
+
+func (s *RedisLoginAttemptStore) Failures(ctx context.Context, key string) (int64, error) {
+	n, err := s.client.Get(ctx, loginFailuresKeyPrefix+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return n, err
+}
+
+func (s *RedisLoginAttemptStore) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	return s.client.Set(ctx, loginLockKeyPrefix+key, 1, ttl).Err()
+}
+
+func (s *RedisLoginAttemptStore) LockRemaining(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, loginLockKeyPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// PTTL returns a negative duration when the key does not exist or has no expiry.
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (s *RedisLoginAttemptStore) Clear(ctx context.Context, key string) error {
+	return s.client.Del(ctx, loginFailuresKeyPrefix+key, loginLockKeyPrefix+key).Err()
+}
+
+// MemoryLoginAttemptStore is a process-local LoginAttemptStore for tests and
+// single-instance development setups.
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	failures map[string]memoryCounter
+	locks    map[string]time.Time // key -> locked until
+	now      func() time.Time
+}
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{
+		failures: make(map[string]memoryCounter),
+		locks:    make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryLoginAttemptStore) IncrementFailures(_ context.Context, key string, window time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counter, ok := s.failures[key]
+	if !ok || !s.now().Before(counter.expiresAt) {
+		counter = memoryCounter{expiresAt: s.now().Add(window)}
+	}
+	counter.count++
+	s.failures[key] = counter
+	return counter.count, nil
+}
+
+func (s *MemoryLoginAttemptStore) Failures(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counter, ok := s.failures[key]
+	if !ok || !s.now().Before(counter.expiresAt) {
+		return 0, nil
+	}
+	return counter.count, nil
+}
+
+func (s *MemoryLoginAttemptStore) Lock(_ context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locks[key] = s.now().Add(ttl)
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) LockRemaining(_ context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	until, ok := s.locks[key]
+	if !ok {
+		return 0, nil
+	}
+	remaining := until.Sub(s.now())
+	if remaining <= 0 {
+		delete(s.locks, key)
+		return 0, nil
+	}
+	return remaining, nil
+}
+
+func (s *MemoryLoginAttemptStore) Clear(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, key)
+	delete(s.locks, key)
+	return nil
+}
@@ -0,0 +1,117 @@
+package authenticator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLoginThrottle() (*LoginThrottle, *MemoryLoginAttemptStore) {
+	store := NewMemoryLoginAttemptStore()
+	return NewLoginThrottle(store, LoginThrottleConfig{
+		Enabled:          true,
+		MaxAttempts:      3,
+		MaxAttemptsPerIP: 5,
+		Window:           time.Minute,
+		LockoutDuration:  10 * time.Minute,
+		BaseDelay:        100 * time.Millisecond,
+		MaxDelay:         time.Second,
+	}), store
+}
+
+func TestLoginThrottle_LocksEmailAfterMaxAttempts(t *testing.T) {
+	throttle, _ := newTestLoginThrottle()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := throttle.RecordFailure(ctx, "User@Example.com", "203.0.113.7")
+		require.NoError(t, err)
+		assert.False(t, result.EmailLocked)
+	}
+
+	result, err := throttle.RecordFailure(ctx, "user@example.com", "203.0.113.8")
+	require.NoError(t, err)
+	assert.True(t, result.EmailLocked, "emails are compared case-insensitively")
+
+	remaining, err := throttle.Locked(ctx, "user@example.com", "198.51.100.1")
+	require.NoError(t, err)
+	assert.InDelta(t, (10 * time.Minute).Seconds(), remaining.Seconds(), 1)
+
+	// Other accounts are unaffected
+	remaining, err = throttle.Locked(ctx, "other@example.com", "198.51.100.1")
+	require.NoError(t, err)
+	assert.Zero(t, remaining)
+
+	require.NoError(t, throttle.UnlockEmail(ctx, "user@example.com"))
+	remaining, err = throttle.Locked(ctx, "user@example.com", "")
+	require.NoError(t, err)
+	assert.Zero(t, remaining)
+}
+
+func TestLoginThrottle_LocksIPAcrossEmails(t *testing.T) {
+	throttle, _ := newTestLoginThrottle()
+	ctx := context.Background()
+
+	var result LoginFailure
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"} {
+		var err error
+		result, err = throttle.RecordFailure(ctx, email, "203.0.113.7")
+		require.NoError(t, err)
+	}
+	assert.True(t, result.IPLocked)
+
+	remaining, err := throttle.Locked(ctx, "fresh@example.com", "203.0.113.7")
+	require.NoError(t, err)
+	assert.Positive(t, remaining)
+
+	// A successful login does not reset the IP counter
+	require.NoError(t, throttle.RecordSuccess(ctx, "fresh@example.com"))
+	remaining, err = throttle.Locked(ctx, "fresh@example.com", "203.0.113.7")
+	require.NoError(t, err)
+	assert.Positive(t, remaining)
+}
+
+func TestLoginThrottle_ProgressiveDelay(t *testing.T) {
+	throttle, _ := newTestLoginThrottle()
+	ctx := context.Background()
+
+	delay, err := throttle.Delay(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	var delays []time.Duration
+	for i := 0; i < 2; i++ {
+		_, err := throttle.RecordFailure(ctx, "user@example.com", "")
+		require.NoError(t, err)
+		delay, err := throttle.Delay(ctx, "user@example.com")
+		require.NoError(t, err)
+		delays = append(delays, delay)
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, delays)
+
+	require.NoError(t, throttle.RecordSuccess(ctx, "user@example.com"))
+	delay, err = throttle.Delay(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+}
+
+func TestMemoryLoginAttemptStore_WindowExpiry(t *testing.T) {
+	store := NewMemoryLoginAttemptStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := store.IncrementFailures(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	n, err := store.IncrementFailures(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	now = now.Add(2 * time.Minute)
+	n, err = store.Failures(ctx, "k")
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
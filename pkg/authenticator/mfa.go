@@ -0,0 +1,303 @@
+package authenticator
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const mfaChallengeKeyPrefix = "auth:mfa:challenge:"
+
+// ErrInvalidMFAChallenge is returned for a challenge token that is unknown, expired,
+// already used or has run out of attempts.
+var ErrInvalidMFAChallenge = errors.New("invalid or expired MFA challenge")
+
+// MFAConfig controls TOTP multi-factor authentication.
+type MFAConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	// Issuer is the account label shown in authenticator apps (default: ichi-go)
+	Issuer string `yaml:"issuer" json:"issuer" mapstructure:"issuer"`
+	// EncryptionKey encrypts stored TOTP secrets: base64 of 32 random bytes (AES-256-GCM).
+	EncryptionKey string `yaml:"encryption_key" json:"-" mapstructure:"encryption_key"`
+	// ChallengeTTL is how long the second login step may take (default: 5m)
+	ChallengeTTL time.Duration `yaml:"challenge_ttl" json:"challenge_ttl" mapstructure:"challenge_ttl"`
+	// MaxAttempts is the number of wrong codes a challenge accepts (default: 5)
+	MaxAttempts int `yaml:"max_attempts" json:"max_attempts" mapstructure:"max_attempts"`
+	// RecoveryCodes is the number of single-use recovery codes issued (default: 10)
+	RecoveryCodes int `yaml:"recovery_codes" json:"recovery_codes" mapstructure:"recovery_codes"`
+}
+
+func (c *MFAConfig) applyDefaults() {
+	if c.Issuer == "" {
+		c.Issuer = "ichi-go"
+	}
+	if c.ChallengeTTL <= 0 {
+		c.ChallengeTTL = 5 * time.Minute
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.RecoveryCodes <= 0 {
+		c.RecoveryCodes = 10
+	}
+}
+
+// MFAChallenge is a login that passed the first factor and waits for the second.
+type MFAChallenge struct {
+	UserID uint64 `json:"user_id"`
+	// Enroll marks a user who must set up MFA before the login completes.
+	Enroll bool `json:"enroll"`
+}
+
+// MFAChallengeStore keeps pending second login steps keyed by a token hash.
+type MFAChallengeStore interface {
+	// Save stores challenge under key for ttl.
+	Save(ctx context.Context, key string, challenge MFAChallenge, ttl time.Duration) error
+	// Attempt counts one verification attempt and returns the challenge with the
+	// number of attempts made so far. It returns nil when key is unknown or expired.
+	Attempt(ctx context.Context, key string) (*MFAChallenge, int64, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// MFA issues login challenges and encrypts TOTP secrets at rest.
+type MFA struct {
+	config     MFAConfig
+	challenges MFAChallengeStore
+	aead       cipher.AEAD
+}
+
+// NewMFA fails when cfg has no valid encryption key.
+func NewMFA(cfg MFAConfig, challenges MFAChallengeStore) (*MFA, error) {
+	cfg.applyDefaults()
+	key, err := base64.StdEncoding.DecodeString(cfg.EncryptionKey)
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("mfa encryption_key must be base64 of 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &MFA{config: cfg, challenges: challenges, aead: aead}, nil
+}
+
+func (m *MFA) Issuer() string              { return m.config.Issuer }
+func (m *MFA) ChallengeTTL() time.Duration { return m.config.ChallengeTTL }
+func (m *MFA) RecoveryCodeCount() int      { return m.config.RecoveryCodes }
+
+// NewChallenge starts the second login step of userID and returns its token.
+func (m *MFA) NewChallenge(ctx context.Context, userID uint64, enroll bool) (string, error) {
+	token, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+	challenge := MFAChallenge{UserID: userID, Enroll: enroll}
+	if err := m.challenges.Save(ctx, mfaChallengeKey(token), challenge, m.config.ChallengeTTL); err != nil {
+		return "", fmt.Errorf("save MFA challenge: %w", err)
+	}
+	return token, nil
+}
+
+// AttemptChallenge returns the challenge of token and counts the attempt. The
+// challenge is dropped once MaxAttempts is exceeded.
+func (m *MFA) AttemptChallenge(ctx context.Context, token string) (*MFAChallenge, error) {
+	if token == "" {
+		return nil, ErrInvalidMFAChallenge
+	}
+	key := mfaChallengeKey(token)
+	challenge, attempts, err := m.challenges.Attempt(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("load MFA challenge: %w", err)
+	}
+	if challenge == nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+	if attempts > int64(m.config.MaxAttempts) {
+		_ = m.challenges.Delete(ctx, key)
+		return nil, ErrInvalidMFAChallenge
+	}
+	return challenge, nil
+}
+
+// EndChallenge invalidates token once the login completed.
+func (m *MFA) EndChallenge(ctx context.Context, token string) error {
+	return m.challenges.Delete(ctx, mfaChallengeKey(token))
+}
+
+// EncryptSecret seals a TOTP secret for storage.
+func (m *MFA) EncryptSecret(secret string) (string, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := m.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret opens a secret sealed by EncryptSecret.
+func (m *MFA) DecryptSecret(encrypted string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("decode TOTP secret: %w", err)
+	}
+	nonceSize := m.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("encrypted TOTP secret too short")
+	}
+	secret, err := m.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt TOTP secret: %w", err)
+	}
+	return string(secret), nil
+}
+
+// mfaChallengeKey stores challenges under a hash so a leaked store does not leak tokens.
+func mfaChallengeKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RedisMFAChallengeStore keeps each challenge as a hash of the challenge and its
+// attempt count, expiring with the challenge; attempts are counted atomically, so
+// parallel guesses on several pods share one MaxAttempts budget.
+type RedisMFAChallengeStore struct {
+	client *redis.Client
+}
+
+func NewRedisMFAChallengeStore(client *redis.Client) *RedisMFAChallengeStore {
+	return &RedisMFAChallengeStore{client: client}
+}
+
+func (s *RedisMFAChallengeStore) Save(ctx context.Context, key string, challenge MFAChallenge, ttl time.Duration) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	redisKey := mfaChallengeKeyPrefix + key
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, redisKey, "challenge", data, "attempts", 0)
+	pipe.Expire(ctx, redisKey, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// attemptScript counts an attempt on an existing challenge only, so an attempt racing
+// the challenge's deletion cannot recreate it. KEYS: challenge. Returns {challenge, attempts}.
+var attemptScript = redis.NewScript(`
+local challenge = redis.call('HGET', KEYS[1], 'challenge')
+if not challenge then
+  return false
+end
+return {challenge, redis.call('HINCRBY', KEYS[1], 'attempts', 1)}
+`)
+
+func (s *RedisMFAChallengeStore) Attempt(ctx context.Context, key string) (*MFAChallenge, int64, error) {
+	res, err := attemptScript.Run(ctx, s.client, []string{mfaChallengeKeyPrefix + key}).Slice()
+	if errors.Is(err, redis.Nil) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(res) != 2 {
+		return nil, 0, fmt.Errorf("attempt MFA challenge: unexpected reply %v", res)
+	}
+	data, _ := res[0].(string)
+	attempts, _ := res[1].(int64)
+	var challenge MFAChallenge
+	if err := json.Unmarshal([]byte(data), &challenge); err != nil {
+		return nil, 0, err
+	}
+	return &challenge, attempts, nil
+}
+
+func (s *RedisMFAChallengeStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, mfaChallengeKeyPrefix+key).Err()
+}
+
+// MemoryMFAChallengeStore is a process-local MFAChallengeStore for tests and
+// single-instance development setups.
+type MemoryMFAChallengeStore struct {
+	mu         sync.Mutex
+	challenges map[string]*memoryMFAChallenge
+	now        func() time.Time
+}
+
+type memoryMFAChallenge struct {
+	challenge MFAChallenge
+	attempts  int64
+	expiresAt time.Time
+}
+
+func NewMemoryMFAChallengeStore() *MemoryMFAChallengeStore {
+	return &MemoryMFAChallengeStore{
+		challenges: make(map[string]*memoryMFAChallenge),
+		now:        time.Now,
+	}
+}
+
+func (s *MemoryMFAChallengeStore) Save(_ context.Context, key string, challenge MFAChallenge, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenges[key] = &memoryMFAChallenge{challenge: challenge, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryMFAChallengeStore) Attempt(_ context.Context, key string) (*MFAChallenge, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.challenges[key]
+	if !ok {
+		return nil, 0, nil
+	}
+	if !s.now().Before(entry.expiresAt) {
+		delete(s.challenges, key)
+		return nil, 0, nil
+	}
+	entry.attempts++
+	challenge := entry.challenge
+	return &challenge, entry.attempts, nil
+}
+
+func (s *MemoryMFAChallengeStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.challenges, key)
+	return nil
+}
+
+// GenerateRecoveryCodes returns n random single-use codes formatted as xxxxx-xxxxx.
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		raw := strings.ToLower(totpEncoding.EncodeToString(b))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode normalises a recovery code as typed by a user and hashes it for storage.
+func HashRecoveryCode(code string) string {
+	normalised := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalised))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,158 @@
+package authenticator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA-1 test key of RFC 6238 appendix B, base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTestMFA(t *testing.T, store MFAChallengeStore) *MFA {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	mfa, err := NewMFA(MFAConfig{EncryptionKey: base64.StdEncoding.EncodeToString(key), MaxAttempts: 2}, store)
+	require.NoError(t, err)
+	return mfa
+}
+
+func TestTOTPCode_MatchesRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		code, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.code, code, "t=%d", tt.unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current := TOTPStep(now)
+
+	step, ok := ValidateTOTP(rfc6238Secret, "081804", now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, current, step)
+
+	_, ok = ValidateTOTP(rfc6238Secret, "081804", now, current)
+	assert.False(t, ok, "a used code is rejected")
+
+	previous, err := TOTPCode(rfc6238Secret, current-1)
+	require.NoError(t, err)
+	step, ok = ValidateTOTP(rfc6238Secret, previous, now, 0)
+	assert.True(t, ok, "one period of clock drift is tolerated")
+	assert.Equal(t, current-1, step)
+
+	tooOld, err := TOTPCode(rfc6238Secret, current-2)
+	require.NoError(t, err)
+	_, ok = ValidateTOTP(rfc6238Secret, tooOld, now, 0)
+	assert.False(t, ok)
+
+	_, ok = ValidateTOTP(rfc6238Secret, "12345", now, 0)
+	assert.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	u, err := url.Parse(TOTPURI("ichi-go", "jane@example.com", secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/ichi-go:jane@example.com", u.Path)
+	assert.Equal(t, secret, u.Query().Get("secret"))
+	assert.Equal(t, "ichi-go", u.Query().Get("issuer"))
+}
+
+func TestMFA_EncryptsSecrets(t *testing.T) {
+	mfa := newTestMFA(t, NewMemoryMFAChallengeStore())
+
+	encrypted, err := mfa.EncryptSecret(rfc6238Secret)
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, rfc6238Secret)
+
+	decrypted, err := mfa.DecryptSecret(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, rfc6238Secret, decrypted)
+
+	other := newTestMFA(t, NewMemoryMFAChallengeStore())
+	_, err = other.DecryptSecret(encrypted)
+	assert.Error(t, err, "another key cannot decrypt")
+
+	_, err = NewMFA(MFAConfig{EncryptionKey: "too-short"}, NewMemoryMFAChallengeStore())
+	assert.Error(t, err)
+}
+
+func TestMFA_ChallengeAttemptsAreLimited(t *testing.T) {
+	mfa := newTestMFA(t, NewMemoryMFAChallengeStore())
+	ctx := context.Background()
+
+	token, err := mfa.NewChallenge(ctx, 42, false)
+	require.NoError(t, err)
+
+	for range 2 {
+		challenge, err := mfa.AttemptChallenge(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), challenge.UserID)
+	}
+	_, err = mfa.AttemptChallenge(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+	_, err = mfa.AttemptChallenge(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidMFAChallenge, "challenge is dropped once exhausted")
+
+	token, err = mfa.NewChallenge(ctx, 42, false)
+	require.NoError(t, err)
+	require.NoError(t, mfa.EndChallenge(ctx, token))
+	_, err = mfa.AttemptChallenge(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+}
+
+func TestMemoryMFAChallengeStore_Expires(t *testing.T) {
+	store := NewMemoryMFAChallengeStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "k1", MFAChallenge{UserID: 1}, time.Minute))
+	now = now.Add(2 * time.Minute)
+
+	challenge, _, err := store.Attempt(ctx, "k1")
+	require.NoError(t, err)
+	assert.Nil(t, challenge)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+		assert.False(t, seen[code])
+		seen[code] = true
+	}
+
+	typed := " " + strings.ToUpper(strings.ReplaceAll(codes[0], "-", " ")) + " "
+	assert.Equal(t, HashRecoveryCode(codes[0]), HashRecoveryCode(typed))
+	assert.NotEqual(t, HashRecoveryCode(codes[0]), HashRecoveryCode(codes[1]))
+}
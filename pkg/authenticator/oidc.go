@@ -0,0 +1,231 @@
+package authenticator
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const oidcStateKeyPrefix = "auth:oidc:state:"
+
+// ErrUnknownOIDCProvider is returned for a provider name that is not configured.
+var ErrUnknownOIDCProvider = errors.New("unknown OIDC provider")
+
+// ErrInvalidOIDCState is returned when the callback state is unknown, expired,
+// already used or was issued for another provider.
+var ErrInvalidOIDCState = errors.New("invalid or expired OIDC state")
+
+// OIDCConfig enables login through external OpenID Connect providers
+// (authorization code flow with PKCE).
+type OIDCConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled" mapstructure:"enabled"`
+	// StateTTL is how long a started login may take to come back (default: 10m)
+	StateTTL  time.Duration                 `yaml:"state_ttl" json:"state_ttl" mapstructure:"state_ttl"`
+	Providers map[string]OIDCProviderConfig `yaml:"providers" json:"providers" mapstructure:"providers"`
+}
+
+// OIDCFlow is what a started login remembers until its callback.
+type OIDCFlow struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// OIDCStateStore keeps started logins keyed by their state parameter.
+type OIDCStateStore interface {
+	// Save stores flow under state for ttl.
+	Save(ctx context.Context, state string, flow OIDCFlow, ttl time.Duration) error
+	// Consume returns and deletes the flow of state, so every state is single-use.
+	// It returns nil when state is unknown or expired.
+	Consume(ctx context.Context, state string) (*OIDCFlow, error)
+}
+
+// OIDC runs logins against the configured providers.
+type OIDC struct {
+	providers map[string]*OIDCProvider
+	states    OIDCStateStore
+	stateTTL  time.Duration
+}
+
+// NewOIDC creates the providers of cfg. A nil client uses a client with a 10s timeout.
+func NewOIDC(cfg *OIDCConfig, states OIDCStateStore, client *http.Client) *OIDC {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	o := &OIDC{
+		providers: make(map[string]*OIDCProvider, len(cfg.Providers)),
+		states:    states,
+		stateTTL:  cfg.StateTTL,
+	}
+	if o.stateTTL <= 0 {
+		o.stateTTL = 10 * time.Minute
+	}
+	for name, providerCfg := range cfg.Providers {
+		o.providers[name] = NewOIDCProvider(name, providerCfg, client)
+	}
+	return o
+}
+
+// Providers returns the configured provider names, sorted.
+func (o *OIDC) Providers() []string {
+	names := make([]string, 0, len(o.providers))
+	for name := range o.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Begin starts a login with provider and returns the URL to redirect the user to.
+func (o *OIDC) Begin(ctx context.Context, provider string) (string, error) {
+	p, ok := o.providers[provider]
+	if !ok {
+		return "", ErrUnknownOIDCProvider
+	}
+
+	state, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, pkceChallenge(verifier))
+	if err != nil {
+		return "", err
+	}
+	flow := OIDCFlow{Provider: provider, Nonce: nonce, CodeVerifier: verifier}
+	if err := o.states.Save(ctx, state, flow, o.stateTTL); err != nil {
+		return "", fmt.Errorf("save OIDC state: %w", err)
+	}
+	return authURL, nil
+}
+
+// Complete finishes a login from the provider's callback: it checks state,
+// redeems code and verifies the returned ID token.
+func (o *OIDC) Complete(ctx context.Context, provider, state, code string) (*OIDCIdentity, error) {
+	p, ok := o.providers[provider]
+	if !ok {
+		return nil, ErrUnknownOIDCProvider
+	}
+	if state == "" || code == "" {
+		return nil, ErrInvalidOIDCState
+	}
+
+	flow, err := o.states.Consume(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("load OIDC state: %w", err)
+	}
+	if flow == nil || flow.Provider != provider {
+		return nil, ErrInvalidOIDCState
+	}
+
+	rawIDToken, err := p.Exchange(ctx, code, flow.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	return p.VerifyIDToken(ctx, rawIDToken, flow.Nonce)
+}
+
+func randomURLString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// pkceChallenge is the S256 code challenge of verifier (RFC 7636 4.2).
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RedisOIDCStateStore is the production OIDCStateStore, shared by all pods.
+type RedisOIDCStateStore struct {
+	client *redis.Client
+}
+
+func NewRedisOIDCStateStore(client *redis.Client) *RedisOIDCStateStore {
+	return &RedisOIDCStateStore{client: client}
+}
+
+func (s *RedisOIDCStateStore) Save(ctx context.Context, state string, flow OIDCFlow, ttl time.Duration) error {
+	data, err := json.Marshal(flow)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, oidcStateKeyPrefix+state, data, ttl).Err()
+}
+
+func (s *RedisOIDCStateStore) Consume(ctx context.Context, state string) (*OIDCFlow, error) {
+	data, err := s.client.GetDel(ctx, oidcStateKeyPrefix+state).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var flow OIDCFlow
+	if err := json.Unmarshal(data, &flow); err != nil {
+		return nil, err
+	}
+	return &flow, nil
+}
+
+// MemoryOIDCStateStore is a process-local OIDCStateStore for tests and
+// single-instance development setups.
+type MemoryOIDCStateStore struct {
+	mu     sync.Mutex
+	states map[string]memoryOIDCFlow
+	now    func() time.Time
+}
+
+type memoryOIDCFlow struct {
+	flow      OIDCFlow
+	expiresAt time.Time
+}
+
+func NewMemoryOIDCStateStore() *MemoryOIDCStateStore {
+	return &MemoryOIDCStateStore{
+		states: make(map[string]memoryOIDCFlow),
+		now:    time.Now,
+	}
+}
+
+func (s *MemoryOIDCStateStore) Save(_ context.Context, state string, flow OIDCFlow, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state] = memoryOIDCFlow{flow: flow, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryOIDCStateStore) Consume(_ context.Context, state string) (*OIDCFlow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.states[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.states, state)
+	if !s.now().Before(entry.expiresAt) {
+		return nil, nil
+	}
+	return &entry.flow, nil
+}
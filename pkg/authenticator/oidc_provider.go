@@ -0,0 +1,219 @@
+package authenticator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const oidcMaxResponseBytes = 1 << 20
+
+// OIDCProviderConfig is one OpenID Connect identity provider, e.g. Google.
+type OIDCProviderConfig struct {
+	Issuer       string   `yaml:"issuer" json:"issuer" mapstructure:"issuer"` // discovery at <issuer>/.well-known/openid-configuration
+	ClientID     string   `yaml:"client_id" json:"client_id" mapstructure:"client_id"`
+	ClientSecret string   `yaml:"client_secret" json:"-" mapstructure:"client_secret"`          // empty for public clients (PKCE only)
+	RedirectURL  string   `yaml:"redirect_url" json:"redirect_url" mapstructure:"redirect_url"` // our /auth/oauth/<provider>/callback
+	Scopes       []string `yaml:"scopes" json:"scopes" mapstructure:"scopes"`                   // default: openid email profile
+}
+
+// OIDCIdentity is the verified identity from an ID token.
+type OIDCIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCProvider talks to one identity provider. Its discovery document is fetched
+// on first use and cached.
+type OIDCProvider struct {
+	name   string
+	config OIDCProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	jwks      *RemoteJWKS
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewOIDCProvider(name string, config OIDCProviderConfig, client *http.Client) *OIDCProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &OIDCProvider{name: name, config: config, client: client}
+}
+
+// AuthCodeURL is the provider's login page for an authorization-code flow with PKCE (S256).
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	authURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+// Exchange redeems an authorization code and returns the raw ID token.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseBytes)).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid token response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return "", fmt.Errorf("token request rejected (status %d): %s %s", resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("token response has no id_token")
+	}
+	return body.IDToken, nil
+}
+
+// VerifyIDToken checks the ID token's signature, issuer, audience, expiry and nonce.
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*OIDCIdentity, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.jwks.Key(ctx, kid, token.Method)
+	},
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+	// With several audiences the token must have been issued to us (OIDC Core 3.1.3.7).
+	if aud, _ := claims.GetAudience(); len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.config.ClientID {
+			return nil, errors.New("id_token authorized party mismatch")
+		}
+	}
+
+	identity := &OIDCIdentity{Issuer: discovery.Issuer}
+	identity.Subject, _ = claims.GetSubject()
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = v
+	case string: // some providers send "true"
+		identity.EmailVerified = v == "true"
+	}
+	if identity.Subject == "" {
+		return nil, errors.New("id_token has no subject")
+	}
+	return identity, nil
+}
+
+// discover fetches the provider's discovery document once. A failed fetch is
+// retried on the next call.
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC discovery for %s failed: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OIDC discovery for %s: unexpected status %d", p.name, resp.StatusCode)
+	}
+
+	var discovery oidcDiscovery
+	if err := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseBytes)).Decode(&discovery); err != nil {
+		return nil, fmt.Errorf("OIDC discovery for %s: %w", p.name, err)
+	}
+	// The document must describe the configured issuer (OIDC Discovery 4.3).
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.config.Issuer, "/") {
+		return nil, fmt.Errorf("OIDC discovery for %s: issuer %q does not match %q", p.name, discovery.Issuer, p.config.Issuer)
+	}
+	if slices.Contains([]string{discovery.AuthorizationEndpoint, discovery.TokenEndpoint, discovery.JWKSURI}, "") {
+		return nil, fmt.Errorf("OIDC discovery for %s: incomplete document", p.name)
+	}
+
+	p.discovery = &discovery
+	p.jwks = NewRemoteJWKS(discovery.JWKSURI, 0, p.client)
+	return p.discovery, nil
+}
@@ -0,0 +1,206 @@
+package authenticator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCProvider is a minimal OpenID Connect provider: discovery, JWKS and a
+// token endpoint that checks PKCE before issuing an ES256 ID token.
+type fakeOIDCProvider struct {
+	t      *testing.T
+	srv    *httptest.Server
+	key    *ecdsa.PrivateKey
+	mu     sync.Mutex
+	grants map[string]fakeGrant // code -> grant
+}
+
+type fakeGrant struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	t.Helper()
+	p := &fakeOIDCProvider{t: t, key: newECKey(t), grants: make(map[string]fakeGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.srv.URL,
+			"authorization_endpoint": p.srv.URL + "/authorize",
+			"token_endpoint":         p.srv.URL + "/token",
+			"jwks_uri":               p.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		jwk, err := NewJWK("fake-1", "ES256", &p.key.PublicKey)
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(JWKS{Keys: []JWK{jwk}})
+	})
+	mux.HandleFunc("/token", p.token)
+	p.srv = httptest.NewServer(mux)
+	t.Cleanup(p.srv.Close)
+	return p
+}
+
+// approve simulates the user signing in at the provider after being redirected
+// to authURL; it returns the callback's state and code.
+func (p *fakeOIDCProvider) approve(authURL string, claims jwt.MapClaims) (state, code string) {
+	p.t.Helper()
+	u, err := url.Parse(authURL)
+	require.NoError(p.t, err)
+	query := u.Query()
+	require.Equal(p.t, "S256", query.Get("code_challenge_method"))
+
+	if claims == nil {
+		claims = jwt.MapClaims{}
+	}
+	if _, ok := claims["iss"]; !ok {
+		claims["iss"] = p.srv.URL
+	}
+	if _, ok := claims["aud"]; !ok {
+		claims["aud"] = query.Get("client_id")
+	}
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = time.Now().Add(time.Minute).Unix()
+	}
+	if _, ok := claims["nonce"]; !ok {
+		claims["nonce"] = query.Get("nonce")
+	}
+
+	code = "code-" + query.Get("state")[:8]
+	p.mu.Lock()
+	p.grants[code] = fakeGrant{challenge: query.Get("code_challenge"), claims: claims}
+	p.mu.Unlock()
+	return query.Get("state"), code
+}
+
+func (p *fakeOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	require.NoError(p.t, r.ParseForm())
+	p.mu.Lock()
+	grant, ok := p.grants[r.PostForm.Get("code")]
+	delete(p.grants, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	if !ok || pkceChallenge(r.PostForm.Get("code_verifier")) != grant.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, grant.claims)
+	token.Header["kid"] = "fake-1"
+	signed, err := token.SignedString(p.key)
+	require.NoError(p.t, err)
+	_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+func newTestOIDC(p *fakeOIDCProvider) *OIDC {
+	return NewOIDC(&OIDCConfig{Providers: map[string]OIDCProviderConfig{
+		"fake": {Issuer: p.srv.URL, ClientID: "ichi-client", RedirectURL: "http://localhost/callback"},
+	}}, NewMemoryOIDCStateStore(), p.srv.Client())
+}
+
+func TestOIDC_CompletesAuthorizationCodeFlow(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	oidc := newTestOIDC(provider)
+	ctx := context.Background()
+
+	authURL, err := oidc.Begin(ctx, "fake")
+	require.NoError(t, err)
+	state, code := provider.approve(authURL, jwt.MapClaims{
+		"sub": "user-1", "email": "jane@example.com", "email_verified": true, "name": "Jane",
+	})
+
+	identity, err := oidc.Complete(ctx, "fake", state, code)
+	require.NoError(t, err)
+	assert.Equal(t, provider.srv.URL, identity.Issuer)
+	assert.Equal(t, "user-1", identity.Subject)
+	assert.Equal(t, "jane@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Jane", identity.Name)
+
+	_, err = oidc.Complete(ctx, "fake", state, code)
+	assert.ErrorIs(t, err, ErrInvalidOIDCState, "state is single-use")
+}
+
+func TestOIDC_RejectsInvalidCallbacks(t *testing.T) {
+	provider := newFakeOIDCProvider(t)
+	oidc := newTestOIDC(provider)
+	ctx := context.Background()
+
+	_, err := oidc.Begin(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrUnknownOIDCProvider)
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{"nonce mismatch", jwt.MapClaims{"sub": "user-1", "nonce": "replayed"}},
+		{"wrong audience", jwt.MapClaims{"sub": "user-1", "aud": "another-client"}},
+		{"wrong issuer", jwt.MapClaims{"sub": "user-1", "iss": "https://evil.example.com"}},
+		{"expired", jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(-time.Minute).Unix()}},
+		{"foreign azp", jwt.MapClaims{"sub": "user-1", "aud": []string{"ichi-client", "other"}, "azp": "other"}},
+		{"no subject", jwt.MapClaims{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL, err := oidc.Begin(ctx, "fake")
+			require.NoError(t, err)
+			state, code := provider.approve(authURL, tt.claims)
+
+			_, err = oidc.Complete(ctx, "fake", state, code)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("unknown state", func(t *testing.T) {
+		authURL, err := oidc.Begin(ctx, "fake")
+		require.NoError(t, err)
+		_, code := provider.approve(authURL, jwt.MapClaims{"sub": "user-1"})
+
+		_, err = oidc.Complete(ctx, "fake", "forged-state", code)
+		assert.ErrorIs(t, err, ErrInvalidOIDCState)
+	})
+
+	t.Run("wrong code verifier", func(t *testing.T) {
+		authURL, err := oidc.Begin(ctx, "fake")
+		require.NoError(t, err)
+		state, code := provider.approve(authURL, jwt.MapClaims{"sub": "user-1"})
+
+		// Swap in another login's verifier, as an attacker replaying a stolen code would.
+		flow, err := oidc.states.Consume(ctx, state)
+		require.NoError(t, err)
+		flow.CodeVerifier = "attacker-verifier"
+		require.NoError(t, oidc.states.Save(ctx, state, *flow, time.Minute))
+
+		_, err = oidc.Complete(ctx, "fake", state, code)
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryOIDCStateStore_Expires(t *testing.T) {
+	store := NewMemoryOIDCStateStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "s1", OIDCFlow{Provider: "fake"}, time.Minute))
+	now = now.Add(2 * time.Minute)
+
+	flow, err := store.Consume(ctx, "s1")
+	require.NoError(t, err)
+	assert.Nil(t, flow)
+}
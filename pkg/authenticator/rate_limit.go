@@ -0,0 +1,118 @@
+package authenticator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const rateLimitKeyPrefix = "auth:ratelimit:"
+
+// RateLimitStore counts events in fixed windows.
+type RateLimitStore interface {
+	// Hit counts one event under key. The counter expires window after the first event;
+	// it returns the count so far and the time until the counter resets.
+	Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+}
+
+// RedisRateLimitStore is the production RateLimitStore, shared by all pods.
+type RedisRateLimitStore struct {
+	client *redis.Client
+}
+
+func NewRedisRateLimitStore(client *redis.Client) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client}
+}
+
+func (s *RedisRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	redisKey := rateLimitKeyPrefix + key
+	n, err := s.client.Incr(ctx, redisKey).Result()
+	if err != nil {
+		return 0, 0, fmt.Errorf("increment rate limit: %w", err)
+	}
+	if n == 1 {
+		if err := s.client.PExpire(ctx, redisKey, window).Err(); err != nil {
+			return 0, 0, fmt.Errorf("expire rate limit: %w", err)
+		}
+		return n, window, nil
+	}
+	ttl, err := s.client.PTTL(ctx, redisKey).Result()
+	if err != nil {
+		return 0, 0, fmt.Errorf("read rate limit ttl: %w", err)
+	}
+	return n, max(ttl, 0), nil
+}
+
+// MemoryRateLimitStore is a process-local RateLimitStore for tests and
+// single-instance development setups.
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	counters map[string]*memoryRateLimitCounter
+	now      func() time.Time
+}
+
+type memoryRateLimitCounter struct {
+	count   int64
+	resetAt time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		counters: make(map[string]*memoryRateLimitCounter),
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryRateLimitStore) Hit(_ context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.resetAt) {
+		counter = &memoryRateLimitCounter{resetAt: now.Add(window)}
+		s.counters[key] = counter
+	}
+	counter.count++
+	return counter.count, counter.resetAt.Sub(now), nil
+}
+
+// ResendLimiter limits emails sent on request (verification links, password resets):
+// at most one per cooldown and limit per window under each key.
+type ResendLimiter struct {
+	store    RateLimitStore
+	prefix   string
+	cooldown time.Duration
+	limit    int
+	window   time.Duration
+}
+
+// NewResendLimiter counts under keys starting with prefix, e.g. "verify_email".
+func NewResendLimiter(store RateLimitStore, prefix string, cooldown time.Duration, limit int, window time.Duration) *ResendLimiter {
+	return &ResendLimiter{store: store, prefix: prefix, cooldown: cooldown, limit: limit, window: window}
+}
+
+// Allow counts an email sent under key (an address or client IP). It returns how
+// long to wait when the cooldown or the window limit is exceeded.
+func (l *ResendLimiter) Allow(ctx context.Context, key string) (time.Duration, error) {
+	if l == nil || l.store == nil {
+		return 0, nil
+	}
+	count, resetIn, err := l.store.Hit(ctx, l.prefix+":cooldown:"+key, l.cooldown)
+	if err != nil {
+		return 0, fmt.Errorf("check resend cooldown: %w", err)
+	}
+	if count > 1 {
+		return resetIn, nil
+	}
+	count, resetIn, err = l.store.Hit(ctx, l.prefix+":window:"+key, l.window)
+	if err != nil {
+		return 0, fmt.Errorf("check resend limit: %w", err)
+	}
+	if count > int64(l.limit) {
+		return resetIn, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,135 @@
+package authenticator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	revokedJTIKeyPrefix   = "auth:revoked:"
+	tokenVersionKeyPrefix = "auth:token_version:"
+)
+
+// RevocationStore tracks revoked tokens and per-user token versions.
+//
+// Single-session logout revokes a token's jti until it would have expired anyway.
+// Logout-all increments the user's token version; tokens carrying an older
+// "tv" claim are rejected without having to enumerate them.
+type RevocationStore interface {
+	// Revoke denylists jti for ttl (the token's remaining lifetime).
+	Revoke(ctx context.Context, jti string, ttl time.Duration) error
+	// IsRevoked reports whether jti has been denylisted.
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	// TokenVersion returns the user's current token version (0 when never bumped).
+	TokenVersion(ctx context.Context, userID uint64) (int64, error)
+	// IncrementTokenVersion bumps the user's token version. The version never
+	// expires: letting it reset would re-validate tokens issued under an old version.
+	IncrementTokenVersion(ctx context.Context, userID uint64) (int64, error)
+}
+
+// RedisRevocationStore is the production RevocationStore, shared by all pods.
+type RedisRevocationStore struct {
+	client *redis.Client
+}
+
+func NewRedisRevocationStore(client *redis.Client) *RedisRevocationStore {
+	return &RedisRevocationStore{client: client}
+}
+
+func (s *RedisRevocationStore) Revoke(ctx context.Context, jti string, ttl time.Duration) error {
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, revokedJTIKeyPrefix+jti, 1, ttl).Err()
+}
+
+func (s *RedisRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+	n, err := s.client.Exists(ctx, revokedJTIKeyPrefix+jti).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (s *RedisRevocationStore) TokenVersion(ctx context.Context, userID uint64) (int64, error) {
+	v, err := s.client.Get(ctx, tokenVersionKey(userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return v, err
+}
+
+func (s *RedisRevocationStore) IncrementTokenVersion(ctx context.Context, userID uint64) (int64, error) {
+	v, err := s.client.Incr(ctx, tokenVersionKey(userID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("increment token version: %w", err)
+	}
+	return v, nil
+}
+
+func tokenVersionKey(userID uint64) string {
+	return tokenVersionKeyPrefix + strconv.FormatUint(userID, 10)
+}
+
+// MemoryRevocationStore is a process-local RevocationStore for tests and
+// single-instance development setups. Revocations are not shared across pods.
+type MemoryRevocationStore struct {
+	mu       sync.Mutex
+	revoked  map[string]time.Time // jti -> expires at
+	versions map[uint64]int64
+	now      func() time.Time
+}
+
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		revoked:  make(map[string]time.Time),
+		versions: make(map[uint64]int64),
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryRevocationStore) Revoke(_ context.Context, jti string, ttl time.Duration) error {
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[jti] = s.now().Add(ttl)
+	return nil
+}
+
+func (s *MemoryRevocationStore) IsRevoked(_ context.Context, jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.revoked[jti]
+	if !ok {
+		return false, nil
+	}
+	if s.now().After(expiresAt) {
+		delete(s.revoked, jti)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s *MemoryRevocationStore) TokenVersion(_ context.Context, userID uint64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.versions[userID], nil
+}
+
+func (s *MemoryRevocationStore) IncrementTokenVersion(_ context.Context, userID uint64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions[userID]++
+	return s.versions[userID], nil
+}
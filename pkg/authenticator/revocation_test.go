@@ -0,0 +1,208 @@
+package authenticator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRevocationTestAuthenticator() (*JWTAuthenticator, *MemoryRevocationStore) {
+	store := NewMemoryRevocationStore()
+	return NewJWTAuthenticator(&JWTConfig{
+		SigningMethod:   jwt.SigningMethodHS256,
+		SecretKey:       []byte("test-secret-key-minimum-32-chars-long"),
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 7 * 24 * time.Hour,
+		AuthScheme:      "Bearer",
+		RevocationStore: store,
+	}), store
+}
+
+func authenticateWith(a *JWTAuthenticator, token string) (*AuthContext, error) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	c := e.NewContext(req, httptest.NewRecorder())
+	return a.Authenticate(c)
+}
+
+func TestGenerateTokenPair_SetsUniqueJTI(t *testing.T) {
+	a, _ := newRevocationTestAuthenticator()
+
+	pair, err := GenerateTokenPair(1, *a.config)
+	require.NoError(t, err)
+
+	access, err := ParseToken(pair.AccessToken, *a.config)
+	require.NoError(t, err)
+	refresh, err := ParseToken(pair.RefreshToken, *a.config)
+	require.NoError(t, err)
+
+	accessJTI, err := GetClaimString(access.Claims.(jwt.MapClaims), "jti")
+	require.NoError(t, err)
+	refreshJTI, err := GetClaimString(refresh.Claims.(jwt.MapClaims), "jti")
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, accessJTI)
+	assert.NotEqual(t, accessJTI, refreshJTI)
+}
+
+func TestAuthenticate_RevokedToken(t *testing.T) {
+	a, _ := newRevocationTestAuthenticator()
+	ctx := context.Background()
+
+	pair, err := a.GenerateTokens(ctx, 42)
+	require.NoError(t, err)
+
+	authCtx, err := authenticateWith(a, pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), authCtx.UserID.ID)
+	require.NotNil(t, authCtx.Claims)
+
+	require.NoError(t, a.RevokeToken(ctx, authCtx.Claims))
+
+	_, err = authenticateWith(a, pair.AccessToken)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "revoked")
+}
+
+func TestRevokeTokenString_RefreshToken(t *testing.T) {
+	a, _ := newRevocationTestAuthenticator()
+	ctx := context.Background()
+
+	pair, err := a.GenerateTokens(ctx, 42)
+	require.NoError(t, err)
+
+	// Someone else's refresh token cannot be revoked
+	assert.Error(t, a.RevokeTokenString(ctx, pair.RefreshToken, 7))
+
+	require.NoError(t, a.RevokeTokenString(ctx, pair.RefreshToken, 42))
+
+	_, err = a.ValidateRefreshToken(ctx, pair.RefreshToken)
+	assert.Error(t, err)
+
+	// Access token from the same pair is unaffected
+	_, err = authenticateWith(a, pair.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestRevokeAllForUser(t *testing.T) {
+	a, _ := newRevocationTestAuthenticator()
+	ctx := context.Background()
+
+	first, err := a.GenerateTokens(ctx, 42)
+	require.NoError(t, err)
+	other, err := a.GenerateTokens(ctx, 7)
+	require.NoError(t, err)
+
+	require.NoError(t, a.RevokeAllForUser(ctx, 42))
+
+	_, err = authenticateWith(a, first.AccessToken)
+	assert.Error(t, err)
+	_, err = a.ValidateRefreshToken(ctx, first.RefreshToken)
+	assert.Error(t, err)
+
+	// Other users keep their sessions
+	_, err = authenticateWith(a, other.AccessToken)
+	assert.NoError(t, err)
+
+	// Tokens issued after logout-all carry the new version
+	second, err := a.GenerateTokens(ctx, 42)
+	require.NoError(t, err)
+	_, err = authenticateWith(a, second.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestMemoryRevocationStore_Expiry(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, store.Revoke(ctx, "jti-1", time.Minute))
+	revoked, err := store.IsRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	now = now.Add(2 * time.Minute)
+	revoked, err = store.IsRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestAuthenticate_WithoutRevocationStore(t *testing.T) {
+	a := NewJWTAuthenticator(&JWTConfig{
+		SigningMethod:  jwt.SigningMethodHS256,
+		SecretKey:      []byte("test-secret-key-minimum-32-chars-long"),
+		AccessTokenTTL: 15 * time.Minute,
+		AuthScheme:     "Bearer",
+	})
+	ctx := context.Background()
+
+	pair, err := a.GenerateTokens(ctx, 42)
+	require.NoError(t, err)
+
+	assert.False(t, a.RevocationEnabled())
+	authCtx, err := authenticateWith(a, pair.AccessToken)
+	require.NoError(t, err)
+	assert.NoError(t, a.RevokeToken(ctx, authCtx.Claims))
+
+	_, err = authenticateWith(a, pair.AccessToken)
+	assert.NoError(t, err)
+}
+
+// failingRevocationStore simulates a Redis outage.
+type failingRevocationStore struct{ MemoryRevocationStore }
+
+func (*failingRevocationStore) IsRevoked(context.Context, string) (bool, error) {
+	return false, errors.New("redis down")
+}
+
+func (*failingRevocationStore) TokenVersion(context.Context, uint64) (int64, error) {
+	return 0, errors.New("redis down")
+}
+
+func TestCheckRevoked_StoreOutageFailsClosedForRefresh(t *testing.T) {
+	a, _ := newRevocationTestAuthenticator()
+	ctx := context.Background()
+	pair, err := GenerateTokenPair(42, *a.config)
+	require.NoError(t, err)
+
+	a.config.RevocationStore = &failingRevocationStore{}
+
+	_, err = authenticateWith(a, pair.AccessToken)
+	assert.NoError(t, err, "access tokens are accepted during an outage")
+
+	_, err = a.ValidateRefreshToken(ctx, pair.RefreshToken)
+	assert.Error(t, err, "refresh tokens are rejected during an outage")
+}
+
+func TestTokenType_AccessAndRefreshAreNotInterchangeable(t *testing.T) {
+	a, _ := newRevocationTestAuthenticator()
+	ctx := context.Background()
+	pair, err := GenerateTokenPair(42, *a.config)
+	require.NoError(t, err)
+
+	_, err = authenticateWith(a, pair.RefreshToken)
+	assert.Error(t, err, "a refresh token grants no access")
+
+	_, err = a.ValidateRefreshToken(ctx, pair.AccessToken)
+	assert.Error(t, err, "an access token cannot be refreshed")
+
+	// Tokens without a type predate the claim and are not refresh tokens.
+	untyped, err := GenerateToken(CreateCustomClaims(StandardClaimsInput{UserID: 42, ExpiresIn: time.Hour, JWTID: "j-1"}), *a.config)
+	require.NoError(t, err)
+	_, err = a.ValidateRefreshToken(ctx, untyped)
+	assert.Error(t, err)
+
+	userID, err := a.ValidateRefreshToken(ctx, pair.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), userID)
+}
@@ -0,0 +1,95 @@
+package authenticator
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP parameters (RFC 6238 defaults, understood by every authenticator app).
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30 * time.Second
+	// totpSkew accepts codes of the previous and next period to tolerate clock drift.
+	totpSkew        = 1
+	totpSecretBytes = 20
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a new random base32 secret.
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate TOTP secret: %w", err)
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPURI is the otpauth:// URI authenticator apps import, usually as a QR code.
+func TOTPURI(issuer, account, secret string) string {
+	query := url.Values{
+		"secret":    {secret},
+		"issuer":    {issuer},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprintf("%d", TOTPDigits)},
+		"period":    {fmt.Sprintf("%d", int(TOTPPeriod.Seconds()))},
+	}
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// TOTPStep is the time step of t.
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / int64(TOTPPeriod.Seconds())
+}
+
+// TOTPCode returns the code of secret for time step step.
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", TOTPDigits, value%1_000_000), nil
+}
+
+// ValidateTOTP checks code against secret at now and returns the matched time step.
+// Steps up to lastStep are rejected, so every code can be used only once.
+func ValidateTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
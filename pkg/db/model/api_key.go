@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+// APIKey is a revocable credential issued to one client integration.
+// Only the SHA-256 hash of the key is stored; Prefix is its public lookup part.
+type APIKey struct {
+	upbun.BaseModel `bun:"table:api_keys,alias:ak" dto:"ignore"`
+
+	ID          int64          `bun:"id,pk,autoincrement"`
+	CreatedAt   time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt   upbun.NullTime `bun:"updated_at,nullzero,default:current_timestamp"`
+	Name        string         `bun:"name,notnull"`
+	Prefix      string         `bun:"prefix,notnull,unique"`
+	SecretHash  string         `bun:"secret_hash,notnull"`
+	OwnerUserID uint64         `bun:"owner_user_id,notnull"`
+	TenantID    string         `bun:"tenant_id,nullzero"`
+	Scopes      []string       `bun:"scopes,type:json,notnull"`
+	ExpiresAt   upbun.NullTime `bun:"expires_at,nullzero"`
+	LastUsedAt  upbun.NullTime `bun:"last_used_at,nullzero"`
+	RevokedAt   upbun.NullTime `bun:"revoked_at,nullzero"`
+}
+
+// IsActive reports whether the key is accepted at now.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt.IsZero() && (k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt.Time))
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+// PasswordReset is a single-use password reset token.
+// Only the SHA-256 hash of the token is stored; the raw token is only ever sent to the user.
+type PasswordReset struct {
+	upbun.BaseModel `bun:"table:password_resets,alias:pr" dto:"ignore"`
+
+	ID          int64          `bun:"id,pk,autoincrement"`
+	CreatedAt   time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UserID      uint64         `bun:"user_id,notnull"`
+	TokenHash   string         `bun:"token_hash,notnull,unique"`
+	ExpiresAt   time.Time      `bun:"expires_at,notnull"`
+	UsedAt      upbun.NullTime `bun:"used_at,nullzero"`
+	RequestedIP string         `bun:"requested_ip"`
+}
@@ -10,4 +10,13 @@ type User struct {
 	Name            string `bun:"name,unique,notnull"`
 	Email           string `bun:"email,unique,notnull"`
 	Password        string `bun:"password,notnull" json:"-"`
+	// EmailVerifiedAt is set once the user confirmed owning Email
+	EmailVerifiedAt upbun.NullTime `bun:"email_verified_at,nullzero"`
+	// AvatarPath is the storage path of the profile picture; empty when none was uploaded
+	AvatarPath string `bun:"avatar_path,nullzero"`
+}
+
+// IsEmailVerified reports whether the user confirmed their email address.
+func (u *User) IsEmailVerified() bool {
+	return !u.EmailVerifiedAt.IsZero()
 }
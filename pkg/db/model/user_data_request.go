@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+// Data request types
+const (
+	DataRequestExport  = "export"
+	DataRequestErasure = "erasure"
+)
+
+// Data request statuses
+const (
+	DataRequestPending    = "pending"
+	DataRequestProcessing = "processing"
+	DataRequestCompleted  = "completed"
+	DataRequestFailed     = "failed"
+)
+
+// UserDataRequest is a GDPR data-subject request: an export of everything stored
+// about a user, or the erasure of their personal data. A queue job does the work
+// and moves it from pending through processing to completed or failed.
+type UserDataRequest struct {
+	upbun.BaseModel `bun:"table:user_data_requests,alias:udr" dto:"ignore"`
+
+	ID          int64          `bun:"id,pk,autoincrement"`
+	CreatedAt   time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt   upbun.NullTime `bun:"updated_at,nullzero,default:current_timestamp"`
+	UserID      uint64         `bun:"user_id,notnull"`
+	RequestedBy uint64         `bun:"requested_by,notnull"`
+	Type        string         `bun:"type,notnull"`
+	Status      string         `bun:"status,notnull"`
+	// FilePath is the storage path of the export archive once it is completed
+	FilePath    string         `bun:"file_path,nullzero"`
+	Error       string         `bun:"error,nullzero"`
+	StartedAt   upbun.NullTime `bun:"started_at,nullzero"`
+	CompletedAt upbun.NullTime `bun:"completed_at,nullzero"`
+}
+
+// IsActive reports whether the request is still waiting for or running its job.
+func (r *UserDataRequest) IsActive() bool {
+	return r.Status == DataRequestPending || r.Status == DataRequestProcessing
+}
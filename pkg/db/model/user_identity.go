@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+// UserIdentity links a user to an account at an external OpenID Connect provider.
+// Provider and Subject together identify the external account.
+type UserIdentity struct {
+	upbun.BaseModel `bun:"table:user_identities,alias:ui" dto:"ignore"`
+
+	ID          int64          `bun:"id,pk,autoincrement"`
+	CreatedAt   time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt   upbun.NullTime `bun:"updated_at,nullzero,default:current_timestamp"`
+	UserID      uint64         `bun:"user_id,notnull"`
+	Provider    string         `bun:"provider,notnull"`
+	Subject     string         `bun:"subject,notnull"`
+	Email       string         `bun:"email,nullzero"`
+	LastLoginAt upbun.NullTime `bun:"last_login_at,nullzero"`
+}
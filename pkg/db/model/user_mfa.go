@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+// UserMFA is a user's TOTP authenticator. It is pending until ConfirmedAt is set
+// by the first valid code. SecretEncrypted is AES-GCM sealed.
+type UserMFA struct {
+	upbun.BaseModel `bun:"table:user_mfa,alias:um" dto:"ignore"`
+
+	ID              int64          `bun:"id,pk,autoincrement"`
+	CreatedAt       time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt       upbun.NullTime `bun:"updated_at,nullzero,default:current_timestamp"`
+	UserID          uint64         `bun:"user_id,notnull,unique"`
+	SecretEncrypted string         `bun:"secret_encrypted,notnull"`
+	ConfirmedAt     upbun.NullTime `bun:"confirmed_at,nullzero"`
+	// LastUsedStep is the TOTP time step of the last accepted code; older codes are rejected.
+	LastUsedStep int64 `bun:"last_used_step,notnull,default:0"`
+}
+
+// IsEnabled reports whether enrollment was confirmed.
+func (m *UserMFA) IsEnabled() bool {
+	return !m.ConfirmedAt.IsZero()
+}
+
+// MFARecoveryCode is a single-use code replacing a TOTP code. Only its hash is stored.
+type MFARecoveryCode struct {
+	upbun.BaseModel `bun:"table:user_mfa_recovery_codes,alias:umrc" dto:"ignore"`
+
+	ID        int64          `bun:"id,pk,autoincrement"`
+	CreatedAt time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UserID    uint64         `bun:"user_id,notnull"`
+	CodeHash  string         `bun:"code_hash,notnull"`
+	UsedAt    upbun.NullTime `bun:"used_at,nullzero"`
+}
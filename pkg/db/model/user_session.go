@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	upbun "github.com/uptrace/bun"
+)
+
+// UserSession is one login session, i.e. one refresh-token family.
+//
+// Every refresh rotates RefreshJTI; presenting a refresh token whose jti is no
+// longer current means it was replayed, and the whole family is revoked.
+type UserSession struct {
+	upbun.BaseModel `bun:"table:user_sessions,alias:us" dto:"ignore"`
+
+	ID            int64          `bun:"id,pk,autoincrement"`
+	CreatedAt     time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt     upbun.NullTime `bun:"updated_at,nullzero,default:current_timestamp"`
+	UserID        uint64         `bun:"user_id,notnull"`
+	FamilyID      string         `bun:"family_id,notnull,unique"`
+	RefreshJTI    string         `bun:"refresh_jti,notnull"`
+	AccessJTI     string         `bun:"access_jti,notnull"`
+	Device        string         `bun:"device"`
+	IPAddress     string         `bun:"ip_address"`
+	UserAgent     string         `bun:"user_agent"`
+	LastUsedAt    time.Time      `bun:"last_used_at,notnull"`
+	ExpiresAt     time.Time      `bun:"expires_at,notnull"`
+	RevokedAt     upbun.NullTime `bun:"revoked_at,nullzero"`
+	RevokedReason string         `bun:"revoked_reason"`
+}
+
+// IsActive reports whether the session can still be refreshed at now.
+func (s *UserSession) IsActive(now time.Time) bool {
+	return s.RevokedAt.IsZero() && now.Before(s.ExpiresAt)
+}
@@ -154,11 +154,13 @@ func (r *BaseRepository[T]) SoftDelete(ctx context.Context, id int64) error {
 
 // Restore - restore soft deleted record
 func (r *BaseRepository[T]) Restore(ctx context.Context, id int64) error {
+	// Soft delete models hide deleted rows from updates unless asked for them
 	_, err := r.db.NewUpdate().
 		Model(r.model).
 		Set("deleted_at = NULL").
 		//Set("updated_by", 0).
 		Where("id = ?", id).
+		WhereDeleted().
 		Exec(ctx)
 	return err
 }
@@ -11,6 +11,15 @@ const (
 	ErrCodePasswordHashFailed = "AUTH_PASSWORD_HASH_FAILED"    // NEW
 	ErrCodeTokenGenFailed     = "AUTH_TOKEN_GENERATION_FAILED" // NEW
 	ErrCodeUnauthorized       = "UNAUTHORIZED"
+	ErrCodeTokenRevoked       = "AUTH_TOKEN_REVOKED"
+	ErrCodeResetTokenInvalid  = "AUTH_RESET_TOKEN_INVALID"
+	ErrCodeAccountLocked      = "AUTH_ACCOUNT_LOCKED"
+	ErrCodeForbidden          = "FORBIDDEN"
+	ErrCodeOAuthFailed        = "AUTH_OAUTH_FAILED"
+	ErrCodeMFAInvalid         = "AUTH_MFA_INVALID"
+	ErrCodeEmailNotVerified   = "AUTH_EMAIL_NOT_VERIFIED"
+	ErrCodeVerifyTokenInvalid = "AUTH_VERIFICATION_TOKEN_INVALID"
+	ErrCodeRateLimited        = "RATE_LIMITED"
 )
 
 // User domain error codes
@@ -18,6 +27,21 @@ const (
 	ErrCodeUserCreateFailed = "USER_CREATE_FAILED"
 	ErrCodeUserUpdateFailed = "USER_UPDATE_FAILED"
 	ErrCodeUserDeleteFailed = "USER_DELETE_FAILED"
+	ErrCodeDataRequestBusy  = "USER_DATA_REQUEST_IN_PROGRESS"
+)
+
+// Notification domain error codes
+const (
+	ErrCodePushUnavailable       = "NOTIFICATION_PUSH_UNAVAILABLE"
+	ErrCodeTopicSubscribeFailed  = "NOTIFICATION_TOPIC_SUBSCRIBE_FAILED"
+	ErrCodeSMSUnavailable        = "NOTIFICATION_SMS_UNAVAILABLE"
+	ErrCodeWebhookUnavailable    = "NOTIFICATION_WEBHOOK_UNAVAILABLE"
+	ErrCodeInboxUnavailable      = "NOTIFICATION_INBOX_UNAVAILABLE"
+	ErrCodeUnsubscribeInvalid    = "NOTIFICATION_UNSUBSCRIBE_TOKEN_INVALID"
+	ErrCodeTemplateExists        = "NOTIFICATION_TEMPLATE_OVERRIDE_EXISTS"
+	ErrCodeTemplateModified      = "NOTIFICATION_TEMPLATE_OVERRIDE_MODIFIED"
+	ErrCodeCampaignNotCancelable = "NOTIFICATION_CAMPAIGN_NOT_CANCELABLE"
+	ErrCodeCampaignNotRetryable  = "NOTIFICATION_CAMPAIGN_NOT_RETRYABLE"
 )
 
 // Infrastructure error codes
@@ -29,4 +53,11 @@ const (
 	ErrCodeValidation         = "VALIDATION_ERROR"
 	ErrCodeNotFound           = "NOT_FOUND"
 	ErrCodeNotificationFailed = "NOTIFICATION_ENQUEUE_FAILED" // NEW
+	ErrCodeStorage            = "STORAGE_ERROR"
+)
+
+// File upload error codes
+const (
+	ErrCodeFileTooLarge         = "FILE_TOO_LARGE"
+	ErrCodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
 )
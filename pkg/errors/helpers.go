@@ -56,6 +56,13 @@ func PaymentService(code string) oops.OopsErrorBuilder {
 		Tags("payments")
 }
 
+// NotificationService - Notification delivery and device registration
+func NotificationService(code string) oops.OopsErrorBuilder {
+	return oops.Code(code).
+		In("notification-service").
+		Tags("notifications")
+}
+
 // ============================================================================
 // Infrastructure Layer Errors
 // ============================================================================
@@ -81,6 +88,13 @@ func Queue(code string) oops.OopsErrorBuilder {
 		Tags("infrastructure", "messaging")
 }
 
+// Storage - File storage operations
+func Storage(code string) oops.OopsErrorBuilder {
+	return oops.Code(code).
+		In("storage").
+		Tags("infrastructure", "files")
+}
+
 // ExternalAPI - External API calls
 func ExternalAPI(code string) oops.OopsErrorBuilder {
 	return oops.Code(code).
@@ -61,26 +61,62 @@ func mapErrorCodeToHTTP(code interface{}) int {
 	case ErrCodeInvalidCredentials,
 		ErrCodeInvalidToken,
 		ErrCodeTokenExpired,
+		ErrCodeTokenRevoked,
+		ErrCodeOAuthFailed,
+		ErrCodeMFAInvalid,
 		ErrCodeUnauthorized:
 		return http.StatusUnauthorized
 
 	// Auth - 400 Bad Request
-	case ErrCodePasswordWeak:
+	case ErrCodePasswordWeak,
+		ErrCodeResetTokenInvalid,
+		ErrCodeVerifyTokenInvalid:
 		return http.StatusBadRequest
 
+	// Auth - 403 Forbidden
+	case ErrCodeForbidden,
+		ErrCodeEmailNotVerified:
+		return http.StatusForbidden
+
+	// Auth - 429 Too Many Requests
+	case ErrCodeAccountLocked,
+		ErrCodeRateLimited:
+		return http.StatusTooManyRequests
+
 	// Auth - 404 Not Found
 	case ErrCodeUserNotFound,
 		ErrCodeNotFound:
 		return http.StatusNotFound
 
 	// Auth - 409 Conflict
-	case ErrCodeUserExists:
+	case ErrCodeUserExists,
+		ErrCodeDataRequestBusy,
+		ErrCodeTemplateExists,
+		ErrCodeTemplateModified,
+		ErrCodeCampaignNotCancelable,
+		ErrCodeCampaignNotRetryable:
 		return http.StatusConflict
 
 	// Validation - 400 Bad Request
-	case ErrCodeValidation:
+	case ErrCodeValidation,
+		ErrCodeUnsubscribeInvalid:
 		return http.StatusBadRequest
 
+	// File uploads - 413 / 415
+	case ErrCodeFileTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrCodeUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+
+	// Notification - 502 / 503
+	case ErrCodeTopicSubscribeFailed:
+		return http.StatusBadGateway
+	case ErrCodePushUnavailable,
+		ErrCodeSMSUnavailable,
+		ErrCodeWebhookUnavailable,
+		ErrCodeInboxUnavailable:
+		return http.StatusServiceUnavailable
+
 	// Infrastructure - 500 Internal Server Error
 	case ErrCodeDatabase,
 		ErrCodeCache,
@@ -88,7 +124,8 @@ func mapErrorCodeToHTTP(code interface{}) int {
 		ErrCodeInternal,
 		ErrCodePasswordHashFailed,
 		ErrCodeTokenGenFailed,
-		ErrCodeNotificationFailed:
+		ErrCodeNotificationFailed,
+		ErrCodeStorage:
 		return http.StatusInternalServerError
 
 	default:
@@ -0,0 +1,51 @@
+package audience
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds blast fan-out configuration (the `notification.audience:` YAML block).
+type Config struct {
+	// BatchSize is the number of users read from the users table per query and
+	// published before the blast's checkpoint is saved (default: 500).
+	BatchSize int `mapstructure:"batch_size"`
+
+	// BatchesPerMessage is the number of batches one blast message fans out before
+	// it re-queues itself, so a large blast does not hold a consumer for its whole
+	// audience and other blasts get their turn (default: 20).
+	BatchesPerMessage int `mapstructure:"batches_per_message"`
+}
+
+// SetDefault registers Viper defaults for the audience config block.
+// Called from config.setDefault() during application startup.
+func SetDefault() {
+	viper.SetDefault("notification.audience.batch_size", 500)
+	viper.SetDefault("notification.audience.batches_per_message", 20)
+}
+
+// LoadConfig reads the `notification.audience:` block from Viper.
+func LoadConfig() (Config, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("notification.audience", &cfg); err != nil {
+		return Config{}, fmt.Errorf("audience: invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Batch returns BatchSize, or 500 when it is not positive.
+func (c Config) Batch() int {
+	if c.BatchSize <= 0 {
+		return 500
+	}
+	return c.BatchSize
+}
+
+// MaxBatches returns BatchesPerMessage, or 20 when it is not positive.
+func (c Config) MaxBatches() int {
+	if c.BatchesPerMessage <= 0 {
+		return 20
+	}
+	return c.BatchesPerMessage
+}
@@ -0,0 +1,78 @@
+package digest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds notification digest configuration (the `notification.digest:` YAML block).
+type Config struct {
+	// Enabled controls whether the events of templates with a digest policy are
+	// buffered into digests. When false every event is sent on its own.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Windows override the digest window of events, by event slug. A window of 0
+	// sends the event on its own.
+	Windows []WindowConfig `mapstructure:"windows"`
+
+	// MaxEvents is the number of events whose data the digest template receives;
+	// the rest are only counted (default: 20).
+	MaxEvents int `mapstructure:"max_events"`
+
+	// BufferTTL bounds how long buffered events are kept when their flush never
+	// runs, e.g. after the broker lost it (default: 24h).
+	BufferTTL time.Duration `mapstructure:"buffer_ttl"`
+}
+
+// WindowConfig is the digest window of one event. Event slugs contain dots, which
+// Viper reads as nested keys, so windows are a list rather than a map.
+type WindowConfig struct {
+	Event  string        `mapstructure:"event"`
+	Window time.Duration `mapstructure:"window"`
+}
+
+// SetDefault registers Viper defaults for the digest config block.
+// Called from config.setDefault() during application startup.
+func SetDefault() {
+	viper.SetDefault("notification.digest.enabled", true)
+	viper.SetDefault("notification.digest.max_events", 20)
+	viper.SetDefault("notification.digest.buffer_ttl", 24*time.Hour)
+}
+
+// LoadConfig reads the `notification.digest:` block from Viper.
+func LoadConfig() (Config, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("notification.digest", &cfg); err != nil {
+		return Config{}, fmt.Errorf("digest: invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Window returns the digest window of event: the configured one, or policy, the
+// window of the template's digest policy.
+func (c Config) Window(event string, policy time.Duration) time.Duration {
+	for _, w := range c.Windows {
+		if w.Event == event {
+			return w.Window
+		}
+	}
+	return policy
+}
+
+// Max returns MaxEvents, or 20 when it is not positive.
+func (c Config) Max() int {
+	if c.MaxEvents <= 0 {
+		return 20
+	}
+	return c.MaxEvents
+}
+
+// TTL returns BufferTTL, or 24h when it is not positive.
+func (c Config) TTL() time.Duration {
+	if c.BufferTTL <= 0 {
+		return 24 * time.Hour
+	}
+	return c.BufferTTL
+}
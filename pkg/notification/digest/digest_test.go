@@ -0,0 +1,105 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Window(t *testing.T) {
+	cfg := Config{Windows: []WindowConfig{
+		{Event: "order.shipped", Window: 5 * time.Minute},
+		{Event: "order.delivered", Window: 0},
+	}}
+	assert.Equal(t, 5*time.Minute, cfg.Window("order.shipped", time.Minute))
+	assert.Equal(t, time.Duration(0), cfg.Window("order.delivered", time.Minute), "0 turns the digest off")
+	assert.Equal(t, time.Minute, cfg.Window("order.paid", time.Minute))
+
+	assert.Equal(t, 20, cfg.Max())
+	assert.Equal(t, 24*time.Hour, cfg.TTL())
+}
+
+func TestMemoryStore_AppendsToOpenWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	first, err := s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-1", At: now}, 2*time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, Appended{WindowID: "evt-1", Opened: true, FlushIn: 2 * time.Minute}, first)
+
+	now = now.Add(30 * time.Second)
+	second, err := s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-2", At: now}, 2*time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, Appended{WindowID: "evt-1", FlushIn: 90 * time.Second}, second)
+
+	dup, err := s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-2", At: now}, 2*time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, dup.Duplicate)
+
+	other, err := s.Append(ctx, "7", "order.shipped", Entry{EventID: "evt-3", At: now}, 2*time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, other.Opened, "windows are per user")
+
+	entries, err := s.Load(ctx, "42", "order.shipped", "evt-1")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "evt-1", entries[0].EventID)
+	assert.Equal(t, "evt-2", entries[1].EventID)
+}
+
+func TestMemoryStore_CloseAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	_, err := s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-1"}, time.Minute, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, s.Close(ctx, "42", "order.shipped", "evt-1"))
+
+	next, err := s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-2"}, time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "evt-2", next.WindowID, "a closed window takes no more events")
+
+	// A window whose flush never closed it stops collecting after the grace period.
+	now = now.Add(time.Minute + grace)
+	late, err := s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-3"}, time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "evt-3", late.WindowID)
+	assert.True(t, late.Opened)
+}
+
+func TestMemoryStore_ReleaseKeepsJoinedEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	_, err := s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-1", At: now}, time.Minute, time.Hour)
+	require.NoError(t, err)
+	_, err = s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-2", At: now.Add(time.Second)}, time.Minute, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, s.Release(ctx, "42", "order.shipped", "evt-1"))
+
+	entries, err := s.Load(ctx, "42", "order.shipped", "evt-1")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "evt-2", entries[0].EventID, "only the opening entry is taken back")
+
+	// The retry of the opening event opens the same window again.
+	retried, err := s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-1", At: now}, time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, Appended{WindowID: "evt-1", Opened: true, FlushIn: time.Minute}, retried)
+	entries, err = s.Load(ctx, "42", "order.shipped", "evt-1")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	dup, err := s.Append(ctx, "42", "order.shipped", Entry{EventID: "evt-2"}, time.Minute, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, dup.Duplicate, "joined entries are still seen")
+}
@@ -0,0 +1,314 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Entry is one event buffered into a digest.
+type Entry struct {
+	EventID  string         `json:"event_id"`
+	At       time.Time      `json:"at"`
+	Channels []string       `json:"channels"`
+	Data     map[string]any `json:"data,omitempty"`
+}
+
+// Appended is the result of Store.Append.
+type Appended struct {
+	// WindowID identifies the window the entry was added to: the event ID of the
+	// entry that opened it. Empty when the entry was a duplicate.
+	WindowID string
+
+	// Opened is true when the entry opened the window: its caller schedules the flush.
+	Opened bool
+
+	// Duplicate is true when an entry with the same event ID was already appended,
+	// e.g. by an earlier delivery of a retried message. Nothing was added.
+	Duplicate bool
+
+	// FlushIn is the time left until the window's flush.
+	FlushIn time.Duration
+}
+
+// A window stays open for grace after its flush time, so a late flush still closes
+// it before a new window opens; past it, a lost flush stops collecting events.
+const grace = time.Minute
+
+// maxAppendAttempts bounds how often Append retries when the open window changes
+// between reading it and appending to it.
+const maxAppendAttempts = 5
+
+// The keys of a user and event share the {userID:slug} hash tag, so the scripts
+// touching them run on one Redis Cluster slot.
+const keyPrefix = "notif-digest:"
+
+func keyTag(userID, slug string) string { return keyPrefix + "{" + userID + ":" + slug + "}:" }
+
+func openKey(userID, slug string) string { return keyTag(userID, slug) + "open" }
+
+func bufferKey(userID, slug, windowID string) string {
+	return keyTag(userID, slug) + "buf:" + windowID
+}
+
+func seenKey(userID, slug, eventID string) string {
+	return keyTag(userID, slug) + "seen:" + eventID
+}
+
+// appendScript adds an entry to the window expected to be open for a user and event,
+// opening one when none is. The "seen" key of the event makes a retried append a no-op.
+// When another window is open than the caller read, nothing is changed and the caller
+// retries with it.
+//
+// KEYS: open, seen, buffer of the expected window. ARGV: event ID, entry, open ms
+// (window + grace), TTL ms, grace ms, expected window ID ("" for none).
+// Returns {window ID, opened, ms to flush}; the window ID is "" for a duplicate and
+// false for a changed window.
+var appendScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 1 then
+  return {'', 0, 0}
+end
+local window = redis.call('GET', KEYS[1])
+if (window or '') ~= ARGV[6] then
+  return {false, 0, 0}
+end
+redis.call('SET', KEYS[2], '1', 'PX', ARGV[4])
+local opened = 0
+if not window then
+  window = ARGV[1]
+  redis.call('SET', KEYS[1], window, 'PX', ARGV[3])
+  opened = 1
+end
+redis.call('HSETNX', KEYS[3], ARGV[1], ARGV[2])
+redis.call('PEXPIRE', KEYS[3], ARGV[4])
+return {window, opened, redis.call('PTTL', KEYS[1]) - tonumber(ARGV[5])}
+`)
+
+// closeScript deletes the open key when it still holds the window ID. KEYS: open. ARGV: window ID.
+var closeScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// releaseScript closes a window and takes back the entry of the event that opened it.
+// Entries that joined the window meanwhile stay buffered.
+//
+// KEYS: open, seen key of the opening event, buffer. ARGV: window ID.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  redis.call('DEL', KEYS[1])
+end
+redis.call('DEL', KEYS[2])
+redis.call('HDEL', KEYS[3], ARGV[1])
+return 1
+`)
+
+// RedisStore keeps each window as an expiring "open" key naming it and a hash of its
+// entries, and the event IDs it has seen as expiring keys. Appends run as Lua scripts,
+// so consumers on every pod agree on the window of an event and buffer it once.
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Append adds entry to the open window of (userID, slug), opening a window of
+// length window when none is open. Buffered entries expire after ttl.
+func (s *RedisStore) Append(ctx context.Context, userID, slug string, entry Entry, window, ttl time.Duration) (Appended, error) {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return Appended{}, fmt.Errorf("digest: encode entry: %w", err)
+	}
+	open := openKey(userID, slug)
+	for range maxAppendAttempts {
+		// The buffer key has to be passed in KEYS, so the script is run for the window
+		// read here and refuses when another one was opened meanwhile.
+		expected, err := s.client.Get(ctx, open).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return Appended{}, fmt.Errorf("digest: append: %w", err)
+		}
+		windowID := expected
+		if windowID == "" {
+			windowID = entry.EventID
+		}
+		res, err := appendScript.Run(ctx, s.client,
+			[]string{open, seenKey(userID, slug, entry.EventID), bufferKey(userID, slug, windowID)},
+			entry.EventID, raw, (window + grace).Milliseconds(), ttl.Milliseconds(),
+			grace.Milliseconds(), expected,
+		).Slice()
+		if err != nil {
+			return Appended{}, fmt.Errorf("digest: append: %w", err)
+		}
+		if len(res) != 3 {
+			return Appended{}, fmt.Errorf("digest: append: unexpected reply %v", res)
+		}
+		if res[0] == nil {
+			continue
+		}
+		windowID, _ = res[0].(string)
+		if windowID == "" {
+			return Appended{Duplicate: true}, nil
+		}
+		opened, _ := res[1].(int64)
+		flushIn, _ := res[2].(int64)
+		return Appended{WindowID: windowID, Opened: opened == 1, FlushIn: time.Duration(flushIn) * time.Millisecond}, nil
+	}
+	return Appended{}, fmt.Errorf("digest: append: open window kept changing")
+}
+
+// Release undoes the opening of a window whose flush could not be scheduled: the
+// window is closed and the opening event may be appended again. Events that joined
+// the window stay buffered; the window ID is the opening event's ID, so its retry
+// opens the same window again and schedules the flush of all of them.
+func (s *RedisStore) Release(ctx context.Context, userID, slug, windowID string) error {
+	keys := []string{openKey(userID, slug), seenKey(userID, slug, windowID), bufferKey(userID, slug, windowID)}
+	if err := releaseScript.Run(ctx, s.client, keys, windowID).Err(); err != nil {
+		return fmt.Errorf("digest: release window: %w", err)
+	}
+	return nil
+}
+
+// Close stops the window from collecting events; the next event opens a new one.
+func (s *RedisStore) Close(ctx context.Context, userID, slug, windowID string) error {
+	if err := closeScript.Run(ctx, s.client, []string{openKey(userID, slug)}, windowID).Err(); err != nil {
+		return fmt.Errorf("digest: close window: %w", err)
+	}
+	return nil
+}
+
+// Load returns the entries of a window, oldest first. A window already deleted has none.
+func (s *RedisStore) Load(ctx context.Context, userID, slug, windowID string) ([]Entry, error) {
+	raw, err := s.client.HGetAll(ctx, bufferKey(userID, slug, windowID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("digest: load window: %w", err)
+	}
+	entries := make([]Entry, 0, len(raw))
+	for eventID, value := range raw {
+		var e Entry
+		if err := json.Unmarshal([]byte(value), &e); err != nil {
+			return nil, fmt.Errorf("digest: decode entry %s: %w", eventID, err)
+		}
+		entries = append(entries, e)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+// Delete removes the entries of a flushed window.
+func (s *RedisStore) Delete(ctx context.Context, userID, slug, windowID string) error {
+	return s.client.Del(ctx, bufferKey(userID, slug, windowID)).Err()
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].At.Equal(entries[j].At) {
+			return entries[i].At.Before(entries[j].At)
+		}
+		return entries[i].EventID < entries[j].EventID
+	})
+}
+
+// MemoryStore is a process-local digest buffer for tests and single-instance
+// development setups.
+type MemoryStore struct {
+	mu      sync.Mutex
+	open    map[string]memoryWindow     // open key -> window
+	buffers map[string]map[string]Entry // buffer key -> event ID -> entry
+	seen    map[string]time.Time        // seen key -> expires at
+	now     func() time.Time
+}
+
+type memoryWindow struct {
+	id      string
+	flushAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		open:    make(map[string]memoryWindow),
+		buffers: make(map[string]map[string]Entry),
+		seen:    make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Append(_ context.Context, userID, slug string, entry Entry, window, ttl time.Duration) (Appended, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	seen := seenKey(userID, slug, entry.EventID)
+	if expiresAt, ok := s.seen[seen]; ok && now.Before(expiresAt) {
+		return Appended{Duplicate: true}, nil
+	}
+	s.seen[seen] = now.Add(ttl)
+
+	key := openKey(userID, slug)
+	w, ok := s.open[key]
+	opened := !ok || !now.Before(w.flushAt.Add(grace))
+	if opened {
+		w = memoryWindow{id: entry.EventID, flushAt: now.Add(window)}
+		s.open[key] = w
+	}
+	buffer := bufferKey(userID, slug, w.id)
+	if s.buffers[buffer] == nil {
+		s.buffers[buffer] = make(map[string]Entry)
+	}
+	if _, ok := s.buffers[buffer][entry.EventID]; !ok {
+		s.buffers[buffer][entry.EventID] = entry
+	}
+	return Appended{WindowID: w.id, Opened: opened, FlushIn: w.flushAt.Sub(now)}, nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, userID, slug, windowID string) error {
+	_ = s.Close(ctx, userID, slug, windowID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.seen, seenKey(userID, slug, windowID))
+	buffer := bufferKey(userID, slug, windowID)
+	delete(s.buffers[buffer], windowID)
+	if len(s.buffers[buffer]) == 0 {
+		delete(s.buffers, buffer)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Close(_ context.Context, userID, slug, windowID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key := openKey(userID, slug); s.open[key].id == windowID {
+		delete(s.open, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Load(_ context.Context, userID, slug, windowID string) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buffer := s.buffers[bufferKey(userID, slug, windowID)]
+	entries := make([]Entry, 0, len(buffer))
+	for _, e := range buffer {
+		entries = append(entries, e)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, userID, slug, windowID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.buffers, bufferKey(userID, slug, windowID))
+	return nil
+}
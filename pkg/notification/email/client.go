@@ -0,0 +1,252 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Client sends email over SMTP, keeping up to PoolSize idle connections open
+// so consecutive messages skip the dial, TLS handshake and AUTH round trips.
+//
+// Pitfalls to be aware of:
+//   - Servers drop idle clients (usually after 60s). Pooled connections older
+//     than IdleTimeout are closed instead of reused, and a reused connection
+//     is probed with RSET first.
+//   - A rejected message (4xx/5xx reply) keeps the connection in the pool; an
+//     I/O error, timeout or cancellation closes it.
+//   - Use IsPermanent / IsTransient on Send errors to decide between dropping
+//     and retrying the message. Connection setup failures (greeting, STARTTLS,
+//     AUTH) are always transient: a misconfigured relay must not drop mail.
+type Client struct {
+	cfg  Config
+	from mail.Address
+
+	mu     sync.Mutex
+	idle   []*pooledConn
+	closed bool
+}
+
+type pooledConn struct {
+	client   *smtp.Client
+	netConn  net.Conn // underlying TCP connection, for deadlines
+	lastUsed time.Time
+}
+
+// NewClient validates cfg and returns a Client. No connection is opened until
+// the first Send.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("email: host is required")
+	}
+	if cfg.FromAddress == "" {
+		return nil, fmt.Errorf("email: from_address is required")
+	}
+	switch cfg.Encryption {
+	case "":
+		cfg.Encryption = EncryptionStartTLS
+	case EncryptionStartTLS, EncryptionTLS, EncryptionNone:
+	default:
+		return nil, fmt.Errorf("email: unknown encryption %q", cfg.Encryption)
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = 2
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = 30 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	return &Client{
+		cfg:  cfg,
+		from: mail.Address{Name: cfg.FromName, Address: cfg.FromAddress},
+	}, nil
+}
+
+// From returns the configured sender address.
+func (c *Client) From() mail.Address {
+	return c.from
+}
+
+// Send delivers msg. An empty msg.From is filled with the configured sender.
+func (c *Client) Send(ctx context.Context, msg *Message) error {
+	if msg.From.Address == "" {
+		msg.From = c.from
+	}
+	raw, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	conn, err := c.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.transmit(ctx, conn, msg.From.Address, msg.Recipients(), raw); err != nil {
+		// A reply code means the server is still in sync: reset the
+		// transaction and keep the connection. Anything else (I/O error,
+		// timeout, cancellation) leaves it unusable.
+		if isReply(err) && conn.client.Reset() == nil {
+			c.release(conn)
+		} else {
+			_ = conn.client.Close()
+		}
+		return err
+	}
+
+	c.release(conn)
+	return nil
+}
+
+// Close closes all idle connections. Send must not be called afterwards.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	idle := c.idle
+	c.idle = nil
+	c.closed = true
+	c.mu.Unlock()
+
+	for _, conn := range idle {
+		_ = conn.client.Quit()
+	}
+	return nil
+}
+
+func (c *Client) transmit(ctx context.Context, conn *pooledConn, from string, rcpts []string, raw []byte) error {
+	deadline := time.Now().Add(c.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.netConn.SetDeadline(deadline)
+	defer func() { _ = conn.netConn.SetDeadline(time.Time{}) }()
+
+	// Abort a blocked exchange as soon as ctx is cancelled.
+	stop := context.AfterFunc(ctx, func() { _ = conn.netConn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if err := conn.client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range rcpts {
+		if err := conn.client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := conn.client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// acquire returns a pooled connection that still answers, or dials a new one.
+func (c *Client) acquire(ctx context.Context) (*pooledConn, error) {
+	for {
+		c.mu.Lock()
+		if len(c.idle) == 0 {
+			c.mu.Unlock()
+			break
+		}
+		conn := c.idle[len(c.idle)-1]
+		c.idle = c.idle[:len(c.idle)-1]
+		c.mu.Unlock()
+
+		if time.Since(conn.lastUsed) > c.cfg.IdleTimeout {
+			_ = conn.client.Close()
+			continue
+		}
+		_ = conn.netConn.SetDeadline(time.Now().Add(c.cfg.Timeout))
+		err := conn.client.Reset()
+		_ = conn.netConn.SetDeadline(time.Time{})
+		if err != nil {
+			_ = conn.client.Close()
+			continue
+		}
+		return conn, nil
+	}
+	return c.dial(ctx)
+}
+
+// release returns conn to the pool, or closes it when the pool is full.
+func (c *Client) release(conn *pooledConn) {
+	conn.lastUsed = time.Now()
+
+	c.mu.Lock()
+	if !c.closed && len(c.idle) < c.cfg.PoolSize {
+		c.idle = append(c.idle, conn)
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+	_ = conn.client.Quit()
+}
+
+// dial opens and authenticates a new connection. Reply errors are flattened
+// with %v so IsPermanent never classifies a setup failure as permanent.
+func (c *Client) dial(ctx context.Context) (*pooledConn, error) {
+	addr := net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Port))
+	tlsConfig := &tls.Config{
+		ServerName:         c.cfg.Host,
+		InsecureSkipVerify: c.cfg.InsecureSkipVerify, //nolint:gosec // opt-in for development relays
+	}
+
+	dialer := &net.Dialer{Timeout: c.cfg.Timeout}
+	var (
+		netConn net.Conn
+		err     error
+	)
+	if c.cfg.Encryption == EncryptionTLS {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("email: dial %s: %w", addr, err)
+	}
+
+	_ = netConn.SetDeadline(time.Now().Add(c.cfg.Timeout))
+	defer func() { _ = netConn.SetDeadline(time.Time{}) }()
+
+	client, err := smtp.NewClient(netConn, c.cfg.Host)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, fmt.Errorf("email: greeting from %s: %v", addr, err)
+	}
+
+	if c.cfg.Encryption == EncryptionStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			_ = client.Close()
+			return nil, fmt.Errorf("email: %s does not support STARTTLS", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("email: starttls: %v", err)
+		}
+	}
+
+	if c.cfg.Username != "" {
+		auth := smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("email: auth: %v", err)
+		}
+	}
+
+	return &pooledConn{client: client, netConn: netConn, lastUsed: time.Now()}, nil
+}
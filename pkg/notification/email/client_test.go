@@ -0,0 +1,143 @@
+package email_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ichi-go/pkg/notification/email"
+	"ichi-go/pkg/notification/email/smtptest"
+)
+
+func newTestClient(t *testing.T, srv *smtptest.Server, mutate ...func(*email.Config)) *email.Client {
+	t.Helper()
+	cfg := email.Config{
+		Host:        srv.Host(),
+		Port:        srv.Port(),
+		Encryption:  email.EncryptionNone,
+		FromAddress: "no-reply@example.com",
+		FromName:    "Ichi",
+	}
+	for _, m := range mutate {
+		m(&cfg)
+	}
+	client, err := email.NewClient(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func testMessage(to string) *email.Message {
+	return &email.Message{
+		To:      []mail.Address{{Name: "Budi", Address: to}},
+		Subject: "Pesanan dikirim ✓",
+		Text:    "Your order is on its way.",
+		HTML:    "<p>Your order is on its way.</p>",
+	}
+}
+
+func TestSend_MultipartAlternativeWithAttachment(t *testing.T) {
+	srv := smtptest.NewServer(t)
+	client := newTestClient(t, srv)
+
+	msg := testMessage("budi@example.com")
+	msg.Headers = map[string]string{"List-Unsubscribe": "<https://example.com/u>"}
+	msg.Attachments = []email.Attachment{{Filename: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.4")}}
+	require.NoError(t, client.Send(context.Background(), msg))
+
+	msgs := srv.Messages()
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "no-reply@example.com", msgs[0].From)
+	assert.Equal(t, []string{"budi@example.com"}, msgs[0].To)
+
+	parsed, err := msgs[0].Parse()
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Pesanan dikirim ✓", subject)
+	assert.Equal(t, "<https://example.com/u>", parsed.Header.Get("List-Unsubscribe"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	body, err := mr.NextPart()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(body.Header.Get("Content-Type"), "multipart/alternative"))
+
+	attachment, err := mr.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "invoice.pdf", attachment.FileName())
+	content, err := io.ReadAll(attachment)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "JVBERi0xLjQ=") // base64 of %PDF-1.4
+}
+
+func TestSend_ReusesPooledConnection(t *testing.T) {
+	srv := smtptest.NewServer(t)
+	client := newTestClient(t, srv)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, client.Send(context.Background(), testMessage("budi@example.com")))
+	}
+
+	assert.Len(t, srv.Messages(), 3)
+	assert.Equal(t, 1, srv.Connections())
+}
+
+func TestSend_ClassifiesReplies(t *testing.T) {
+	srv := smtptest.NewServer(t)
+	client := newTestClient(t, srv)
+
+	srv.RejectRecipient("gone@example.com", 550, "5.1.1 No such user")
+	err := client.Send(context.Background(), testMessage("gone@example.com"))
+	assert.True(t, email.IsPermanent(err), "550 must be permanent: %v", err)
+
+	srv.FailNextData(451, "4.7.1 Try again later")
+	err = client.Send(context.Background(), testMessage("budi@example.com"))
+	assert.True(t, email.IsTransient(err), "451 must be transient: %v", err)
+	assert.False(t, email.IsPermanent(err))
+
+	// Rejections keep the connection usable.
+	require.NoError(t, client.Send(context.Background(), testMessage("budi@example.com")))
+	assert.Equal(t, 1, srv.Connections())
+}
+
+func TestSend_Auth(t *testing.T) {
+	srv := smtptest.NewServer(t)
+	srv.RequireAuth("mailer", "s3cret")
+
+	good := newTestClient(t, srv, func(c *email.Config) { c.Username, c.Password = "mailer", "s3cret" })
+	require.NoError(t, good.Send(context.Background(), testMessage("budi@example.com")))
+
+	// A wrong password is a configuration problem: retry, never drop the message.
+	bad := newTestClient(t, srv, func(c *email.Config) { c.Username, c.Password = "mailer", "wrong" })
+	err := bad.Send(context.Background(), testMessage("budi@example.com"))
+	require.Error(t, err)
+	assert.True(t, email.IsTransient(err))
+}
+
+func TestLayout_EscapesBodyAndSplitsParagraphs(t *testing.T) {
+	layout, err := email.NewLayout("")
+	require.NoError(t, err)
+
+	html, err := layout.Render(email.LayoutData{
+		Title:          "Hello",
+		Body:           "First <b>line</b>\n\nSecond",
+		AppName:        "Ichi",
+		UnsubscribeURL: "https://example.com/u?a=1&b=2",
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, html, "First &lt;b&gt;line&lt;/b&gt;</p>")
+	assert.Contains(t, html, ">Second</p>")
+	assert.Contains(t, html, `href="https://example.com/u?a=1&amp;b=2"`)
+}
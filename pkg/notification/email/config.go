@@ -0,0 +1,80 @@
+package email
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Encryption modes of the SMTP connection.
+const (
+	EncryptionStartTLS = "starttls" // plain connection upgraded with STARTTLS (port 587)
+	EncryptionTLS      = "tls"      // implicit TLS from the first byte (port 465)
+	EncryptionNone     = "none"     // no encryption; local sinks such as MailHog only
+)
+
+// Config holds SMTP email configuration (the `notification.email:` YAML block).
+type Config struct {
+	// Enabled controls whether the SMTP client is initialized.
+	// When false the email channel logs and skips every message.
+	Enabled bool `mapstructure:"enabled"`
+
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port"`
+
+	// Username and Password enable SMTP AUTH PLAIN. Leave empty for relays without auth.
+	// AUTH is only attempted over TLS or to localhost.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+
+	// Encryption is "starttls" (default), "tls" or "none".
+	Encryption string `mapstructure:"encryption"`
+	// InsecureSkipVerify disables certificate verification. Development only.
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+
+	// FromAddress and FromName form the From header of every email.
+	FromAddress string `mapstructure:"from_address"`
+	FromName    string `mapstructure:"from_name"`
+
+	// PoolSize is the number of idle connections kept open for reuse (default: 2).
+	PoolSize int `mapstructure:"pool_size"`
+	// IdleTimeout closes pooled connections unused for longer (default: 30s).
+	// Most servers drop idle clients after a minute.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+	// Timeout bounds dialing and each message exchange (default: 30s).
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// LayoutFile is an html/template file wrapping every HTML body; the built-in
+	// layout is used when empty. See Layout for the fields it receives.
+	LayoutFile string `mapstructure:"layout_file"`
+	// AppName is shown in the header and footer of the HTML layout.
+	AppName string `mapstructure:"app_name"`
+
+	// UnsubscribeURL and UnsubscribeMailto fill the List-Unsubscribe header.
+	// "{user_id}" and "{event_type}" are replaced with the values of the event, and
+	// "{token}" with a signed one-click unsubscribe token (see notification.preferences).
+	// Leave both empty to omit the header.
+	UnsubscribeURL    string `mapstructure:"unsubscribe_url"`
+	UnsubscribeMailto string `mapstructure:"unsubscribe_mailto"`
+}
+
+// SetDefault registers Viper defaults for the email config block.
+// Called from config.setDefault() during application startup.
+func SetDefault() {
+	viper.SetDefault("notification.email.enabled", false)
+	viper.SetDefault("notification.email.port", 587)
+	viper.SetDefault("notification.email.encryption", EncryptionStartTLS)
+	viper.SetDefault("notification.email.pool_size", 2)
+	viper.SetDefault("notification.email.idle_timeout", "30s")
+	viper.SetDefault("notification.email.timeout", "30s")
+}
+
+// LoadConfig reads the `notification.email:` block from Viper.
+func LoadConfig() (Config, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("notification.email", &cfg); err != nil {
+		return Config{}, fmt.Errorf("email: invalid config: %w", err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,27 @@
+package email
+
+import (
+	"errors"
+	"net/textproto"
+)
+
+// IsPermanent reports whether err is a permanent SMTP failure (5xx reply):
+// an unknown mailbox, a rejected sender, a message refused as spam. Retrying
+// will not help, so the message should be dropped.
+func IsPermanent(err error) bool {
+	var tpErr *textproto.Error
+	return errors.As(err, &tpErr) && tpErr.Code >= 500 && tpErr.Code < 600
+}
+
+// IsTransient reports whether err may succeed on retry: a 4xx reply
+// (mailbox busy, greylisting, rate limit) or any network failure.
+func IsTransient(err error) bool {
+	return err != nil && !IsPermanent(err)
+}
+
+// isReply reports whether err carries an SMTP reply code, meaning the
+// connection is still in a known protocol state.
+func isReply(err error) bool {
+	var tpErr *textproto.Error
+	return errors.As(err, &tpErr)
+}
@@ -0,0 +1,87 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+//go:embed layouts/default.html
+var layoutFS embed.FS
+
+// LayoutData is what an HTML layout template receives.
+//
+//	{{.Title}}          rendered ChannelContent title (the subject)
+//	{{.Body}}           rendered ChannelContent body, as plain text
+//	{{.Paragraphs}}     Body split on blank lines
+//	{{.AppName}}        Config.AppName
+//	{{.UnsubscribeURL}} resolved unsubscribe link, empty when not configured
+//	{{.Locale}}         locale of the event, e.g. "en"
+//	{{.Year}}           current year, for the footer
+//
+// All values are escaped by html/template; the body copy is plain text.
+type LayoutData struct {
+	Title          string
+	Body           string
+	Paragraphs     []string
+	AppName        string
+	UnsubscribeURL string
+	Locale         string
+	Year           int
+}
+
+// Layout wraps plain-text notification content into an HTML document.
+type Layout struct {
+	tmpl *template.Template
+}
+
+// NewLayout parses the layout at path, or the built-in layout when path is empty.
+func NewLayout(path string) (*Layout, error) {
+	var (
+		tmpl *template.Template
+		err  error
+	)
+	if path == "" {
+		tmpl, err = template.ParseFS(layoutFS, "layouts/default.html")
+	} else {
+		tmpl, err = template.ParseFiles(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("email: parse layout: %w", err)
+	}
+	return &Layout{tmpl: tmpl.Option("missingkey=zero")}, nil
+}
+
+// Render executes the layout. Paragraphs, Year and Locale are filled in when unset.
+func (l *Layout) Render(data LayoutData) (string, error) {
+	if data.Paragraphs == nil {
+		data.Paragraphs = Paragraphs(data.Body)
+	}
+	if data.Year == 0 {
+		data.Year = time.Now().Year()
+	}
+	if data.Locale == "" {
+		data.Locale = "en"
+	}
+
+	var buf bytes.Buffer
+	if err := l.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("email: render layout: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Paragraphs splits plain text on blank lines, trimming each paragraph.
+func Paragraphs(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var paragraphs []string
+	for _, p := range strings.Split(text, "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
@@ -0,0 +1,219 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message is one email. Text is required; HTML adds a multipart/alternative
+// part and Attachments turn the message into multipart/mixed.
+type Message struct {
+	From        mail.Address
+	To          []mail.Address
+	Subject     string
+	Text        string
+	HTML        string
+	Headers     map[string]string // extra headers, e.g. List-Unsubscribe
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a Message.
+type Attachment struct {
+	Filename    string
+	ContentType string // defaults to application/octet-stream
+	Data        []byte
+}
+
+// Recipients returns the bare addresses of the message, for the SMTP envelope.
+func (m *Message) Recipients() []string {
+	rcpts := make([]string, len(m.To))
+	for i, to := range m.To {
+		rcpts[i] = to.Address
+	}
+	return rcpts
+}
+
+// Bytes renders the message in RFC 5322 / MIME format with CRLF line endings.
+func (m *Message) Bytes() ([]byte, error) {
+	if len(m.To) == 0 {
+		return nil, fmt.Errorf("email: message has no recipients")
+	}
+
+	buf := &bytes.Buffer{}
+	to := make([]string, len(m.To))
+	for i := range m.To {
+		to[i] = m.To[i].String()
+	}
+
+	headers := map[string]string{
+		"From":         m.From.String(),
+		"To":           strings.Join(to, ", "),
+		"Subject":      mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"Message-ID":   messageID(m.From.Address),
+		"MIME-Version": "1.0",
+	}
+	for k, v := range m.Headers {
+		headers[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(buf, "%s: %s\r\n", k, headers[k])
+	}
+
+	if len(m.Attachments) == 0 {
+		if err := m.writeBody(buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(buf)
+	fmt.Fprintf(buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary())
+
+	bodyHeader, body, err := m.body()
+	if err != nil {
+		return nil, err
+	}
+	part, err := mixed.CreatePart(bodyHeader)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(body); err != nil {
+		return nil, err
+	}
+
+	for _, a := range m.Attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": a.Filename}))
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+		h.Set("Content-Transfer-Encoding", "base64")
+		part, err := mixed.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, a.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBody writes the text or text+HTML body, part headers included, to w.
+func (m *Message) writeBody(w *bytes.Buffer) error {
+	h, body, err := m.body()
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s: %s\r\n", k, h.Get(k))
+	}
+	w.WriteString("\r\n")
+	w.Write(body)
+	return nil
+}
+
+// body returns the headers and content of the text part, or of a
+// multipart/alternative holding the text and HTML parts.
+func (m *Message) body() (textproto.MIMEHeader, []byte, error) {
+	if m.HTML == "" {
+		content, err := quotedPrintable(m.Text)
+		return textHeader("text/plain"), content, err
+	}
+
+	buf := &bytes.Buffer{}
+	alt := multipart.NewWriter(buf)
+	for _, p := range []struct{ contentType, content string }{
+		{"text/plain", m.Text},
+		{"text/html", m.HTML},
+	} {
+		part, err := alt.CreatePart(textHeader(p.contentType))
+		if err != nil {
+			return nil, nil, err
+		}
+		content, err := quotedPrintable(p.content)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := part.Write(content); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := alt.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", "multipart/alternative; boundary="+alt.Boundary())
+	return h, buf.Bytes(), nil
+}
+
+func textHeader(contentType string) textproto.MIMEHeader {
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", contentType+"; charset=utf-8")
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	return h
+}
+
+func quotedPrintable(s string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := quotedprintable.NewWriter(buf)
+	if _, err := io.WriteString(w, s); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64 writes data base64 encoded in lines of 76 characters (RFC 2045).
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(w, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := io.WriteString(w, encoded+"\r\n")
+	return err
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
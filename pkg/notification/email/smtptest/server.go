@@ -0,0 +1,302 @@
+// Package smtptest provides a tiny in-process SMTP server for tests.
+//
+// It speaks enough of RFC 5321 for net/smtp: EHLO/HELO, AUTH PLAIN, MAIL,
+// RCPT, DATA, RSET, NOOP and QUIT. STARTTLS is not offered, so clients must
+// connect with encryption "none". Accepted messages are recorded and can be
+// inspected with Messages; replies can be forced with RejectRecipient and
+// FailNextData to exercise 4xx/5xx handling.
+//
+//	srv := smtptest.NewServer(t)
+//	client, _ := email.NewClient(email.Config{Host: srv.Host(), Port: srv.Port(), Encryption: "none", ...})
+//	...
+//	msgs := srv.Messages()
+package smtptest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Message is one message accepted by the server.
+type Message struct {
+	From string
+	To   []string
+	Raw  []byte
+}
+
+// Parse parses the raw message with net/mail.
+func (m Message) Parse() (*mail.Message, error) {
+	return mail.ReadMessage(bytes.NewReader(m.Raw))
+}
+
+// Reply is a forced SMTP reply.
+type Reply struct {
+	Code int
+	Text string
+}
+
+// Server is an in-process SMTP sink listening on 127.0.0.1.
+type Server struct {
+	listener net.Listener
+	quit     chan struct{}
+	once     sync.Once
+
+	mu          sync.Mutex
+	messages    []Message
+	rejected    map[string]Reply // by recipient address
+	dataFails   []Reply          // consumed one per DATA
+	username    string
+	password    string
+	connections int
+
+	wg sync.WaitGroup
+}
+
+// NewServer starts a server and stops it when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("smtptest: listen: %v", err)
+	}
+	s := &Server{listener: l, quit: make(chan struct{}), rejected: map[string]Reply{}}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Host returns the listening host.
+func (s *Server) Host() string { return "127.0.0.1" }
+
+// Port returns the listening port.
+func (s *Server) Port() int { return s.listener.Addr().(*net.TCPAddr).Port }
+
+// Close stops the server and waits for open sessions to end.
+func (s *Server) Close() {
+	s.once.Do(func() {
+		close(s.quit)
+		_ = s.listener.Close()
+	})
+	s.wg.Wait()
+}
+
+// RequireAuth makes the server advertise AUTH PLAIN and require these credentials.
+func (s *Server) RequireAuth(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username, s.password = username, password
+}
+
+// RejectRecipient answers RCPT TO for addr with the given reply, e.g. 550.
+func (s *Server) RejectRecipient(addr string, code int, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejected[strings.ToLower(addr)] = Reply{Code: code, Text: text}
+}
+
+// FailNextData answers the end of the next DATA with the given reply, e.g. 451.
+// Calls queue up: each DATA consumes one forced reply.
+func (s *Server) FailNextData(code int, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dataFails = append(s.dataFails, Reply{Code: code, Text: text})
+}
+
+// Messages returns a copy of the accepted messages, oldest first.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// Connections returns the number of connections accepted so far.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.connections++
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.session(conn)
+		}()
+	}
+}
+
+// session runs one SMTP conversation until QUIT or disconnect.
+func (s *Server) session(conn net.Conn) {
+	defer conn.Close()
+	// Unblock the session when the server closes.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-done:
+		case <-s.quit:
+			_ = conn.Close()
+		}
+	}()
+
+	r := bufio.NewReader(conn)
+	reply := func(code int, text string) {
+		fmt.Fprintf(conn, "%d %s\r\n", code, text)
+	}
+
+	var (
+		from          string
+		rcpts         []string
+		authenticated bool
+	)
+	reset := func() { from, rcpts = "", nil }
+
+	reply(220, "smtptest ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+
+		s.mu.Lock()
+		needAuth := s.username != ""
+		s.mu.Unlock()
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			fmt.Fprintf(conn, "250-smtptest\r\n")
+			if needAuth {
+				fmt.Fprintf(conn, "250-AUTH PLAIN\r\n")
+			}
+			reply(250, "8BITMIME")
+		case "HELO":
+			reply(250, "smtptest")
+		case "AUTH":
+			if s.checkAuth(arg) {
+				authenticated = true
+				reply(235, "2.7.0 Authentication successful")
+			} else {
+				reply(535, "5.7.8 Authentication credentials invalid")
+			}
+		case "MAIL":
+			if needAuth && !authenticated {
+				reply(530, "5.7.0 Authentication required")
+				continue
+			}
+			reset()
+			from = addrArg(arg)
+			reply(250, "2.1.0 OK")
+		case "RCPT":
+			if from == "" {
+				reply(503, "5.5.1 MAIL first")
+				continue
+			}
+			rcpt := addrArg(arg)
+			s.mu.Lock()
+			rej, bad := s.rejected[strings.ToLower(rcpt)]
+			s.mu.Unlock()
+			if bad {
+				reply(rej.Code, rej.Text)
+				continue
+			}
+			rcpts = append(rcpts, rcpt)
+			reply(250, "2.1.5 OK")
+		case "DATA":
+			if len(rcpts) == 0 {
+				reply(503, "5.5.1 RCPT first")
+				continue
+			}
+			reply(354, "End data with <CR><LF>.<CR><LF>")
+			raw, err := readData(r)
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			if len(s.dataFails) > 0 {
+				fail := s.dataFails[0]
+				s.dataFails = s.dataFails[1:]
+				s.mu.Unlock()
+				reply(fail.Code, fail.Text)
+				reset()
+				continue
+			}
+			s.messages = append(s.messages, Message{From: from, To: rcpts, Raw: raw})
+			s.mu.Unlock()
+			reply(250, "2.0.0 OK queued")
+			reset()
+		case "RSET":
+			reset()
+			reply(250, "2.0.0 OK")
+		case "NOOP":
+			reply(250, "2.0.0 OK")
+		case "QUIT":
+			reply(221, "2.0.0 Bye")
+			return
+		default:
+			reply(502, "5.5.2 Command not recognized")
+		}
+	}
+}
+
+func (s *Server) checkAuth(arg string) bool {
+	mech, initial, _ := strings.Cut(arg, " ")
+	if !strings.EqualFold(mech, "PLAIN") {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(initial)
+	if err != nil {
+		return false
+	}
+	parts := strings.Split(string(decoded), "\x00")
+	if len(parts) != 3 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return parts[1] == s.username && parts[2] == s.password
+}
+
+// addrArg extracts the address from "FROM:<a@b>" or "TO:<a@b> SIZE=1".
+func addrArg(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
+
+// readData reads a dot-terminated DATA block, undoing dot-stuffing.
+func readData(r *bufio.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return buf.Bytes(), nil
+		}
+		if strings.HasPrefix(line, ".") {
+			line = line[1:]
+		}
+		buf.WriteString(line)
+	}
+}
@@ -72,21 +72,30 @@ func (c *Client) SendToToken(ctx context.Context, token, title, body string, dat
 	return err
 }
 
+// BatchResult reports the per-token outcome of a multicast send.
+type BatchResult struct {
+	// Failed lists every token that was not delivered, including the unregistered ones.
+	Failed []string
+	// Unregistered lists the tokens FCM reported as permanently invalid.
+	Unregistered []string
+}
+
 // SendToTokens sends a notification to multiple device tokens in a single batch call.
 // FCM's SendEach processes each token independently — partial success is possible.
 //
 // Returns:
-//   - failedTokens: tokens that failed delivery (may include stale/unregistered tokens)
+//   - result: tokens that failed delivery, with the unregistered ones listed separately
 //   - err: non-nil only when the entire batch request failed (network error, auth error)
 //
 // NOTE: FCM limits SendEach to 500 messages per call. Use SendToTokensBatch for
 // slices larger than 500.
-func (c *Client) SendToTokens(ctx context.Context, tokens []string, title, body string, data map[string]string) (failedTokens []string, err error) {
+func (c *Client) SendToTokens(ctx context.Context, tokens []string, title, body string, data map[string]string) (BatchResult, error) {
+	var result BatchResult
 	if len(tokens) == 0 {
-		return nil, nil
+		return result, nil
 	}
 	if len(tokens) > 500 {
-		return nil, fmt.Errorf("fcm: SendToTokens accepts max 500 tokens per call, got %d; use SendToTokensBatch", len(tokens))
+		return result, fmt.Errorf("fcm: SendToTokens accepts max 500 tokens per call, got %d; use SendToTokensBatch", len(tokens))
 	}
 
 	messages := make([]*messaging.Message, len(tokens))
@@ -107,34 +116,39 @@ func (c *Client) SendToTokens(ctx context.Context, tokens []string, title, body
 	// SendEach replaces the deprecated SendAll — each message is sent independently.
 	br, err := c.messaging.SendEach(ctx, messages)
 	if err != nil {
-		return nil, fmt.Errorf("fcm: batch send failed: %w", err)
+		return result, fmt.Errorf("fcm: batch send failed: %w", err)
 	}
 
 	for i, resp := range br.Responses {
 		if !resp.Success {
-			failedTokens = append(failedTokens, tokens[i])
+			result.Failed = append(result.Failed, tokens[i])
+			if IsUnregisteredToken(resp.Error) {
+				result.Unregistered = append(result.Unregistered, tokens[i])
+			}
 		}
 	}
-	return failedTokens, nil
+	return result, nil
 }
 
 // SendToTokensBatch handles slices of any size by chunking into 500-token batches.
 // Use this for user notification lists that may exceed 500 device tokens.
-func (c *Client) SendToTokensBatch(ctx context.Context, tokens []string, title, body string, data map[string]string) (failedTokens []string, err error) {
+func (c *Client) SendToTokensBatch(ctx context.Context, tokens []string, title, body string, data map[string]string) (BatchResult, error) {
 	const batchSize = 500
+	var result BatchResult
 	for i := 0; i < len(tokens); i += batchSize {
 		end := i + batchSize
 		if end > len(tokens) {
 			end = len(tokens)
 		}
 		batch := tokens[i:end]
-		failed, batchErr := c.SendToTokens(ctx, batch, title, body, data)
+		sent, batchErr := c.SendToTokens(ctx, batch, title, body, data)
 		if batchErr != nil {
-			return failedTokens, batchErr
+			return result, batchErr
 		}
-		failedTokens = append(failedTokens, failed...)
+		result.Failed = append(result.Failed, sent.Failed...)
+		result.Unregistered = append(result.Unregistered, sent.Unregistered...)
 	}
-	return failedTokens, nil
+	return result, nil
 }
 
 // SendToTopic sends a notification to all devices subscribed to an FCM topic.
@@ -153,6 +167,36 @@ func (c *Client) SendToTopic(ctx context.Context, topic, title, body string, dat
 	return err
 }
 
+// SubscribeToTopic subscribes device tokens to an FCM topic, so SendToTopic reaches them.
+//
+// Returns the tokens FCM refused, e.g. because they are no longer registered.
+func (c *Client) SubscribeToTopic(ctx context.Context, tokens []string, topic string) (failedTokens []string, err error) {
+	resp, err := c.messaging.SubscribeToTopic(ctx, tokens, topic)
+	if err != nil {
+		return nil, fmt.Errorf("fcm: subscribe to topic %q failed: %w", topic, err)
+	}
+	return topicFailures(tokens, resp), nil
+}
+
+// UnsubscribeFromTopic removes device tokens from an FCM topic.
+func (c *Client) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) (failedTokens []string, err error) {
+	resp, err := c.messaging.UnsubscribeFromTopic(ctx, tokens, topic)
+	if err != nil {
+		return nil, fmt.Errorf("fcm: unsubscribe from topic %q failed: %w", topic, err)
+	}
+	return topicFailures(tokens, resp), nil
+}
+
+func topicFailures(tokens []string, resp *messaging.TopicManagementResponse) []string {
+	var failed []string
+	for _, e := range resp.Errors {
+		if e.Index >= 0 && e.Index < len(tokens) {
+			failed = append(failed, tokens[e.Index])
+		}
+	}
+	return failed
+}
+
 // IsUnregisteredToken reports whether an FCM error indicates a permanently invalid token.
 // Callers should mark such tokens as stale in their token store.
 func IsUnregisteredToken(err error) bool {
@@ -0,0 +1,55 @@
+package preference
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds notification preference configuration (the `notification.preferences:` YAML block).
+type Config struct {
+	// Enabled controls whether dispatch applies user preferences.
+	// When false every notification is sent to every channel it lists; users can
+	// still edit their preferences. Their preferred locale applies either way.
+	Enabled bool `mapstructure:"enabled"`
+
+	// UnsubscribeSecret signs one-click unsubscribe tokens. Keep it stable: changing
+	// it invalidates the links in every email already sent. Empty disables the links.
+	UnsubscribeSecret string `mapstructure:"unsubscribe_secret"`
+
+	// QuietChannels are the channels held back during quiet hours; the others, which
+	// do not interrupt the user, are sent at once (default: push, sms).
+	QuietChannels []string `mapstructure:"quiet_channels"`
+
+	// DefaultTimezone is the IANA time zone of quiet hours for users who did not
+	// choose one (default: UTC).
+	DefaultTimezone string `mapstructure:"default_timezone"`
+}
+
+// SetDefault registers Viper defaults for the preferences config block.
+// Called from config.setDefault() during application startup.
+func SetDefault() {
+	viper.SetDefault("notification.preferences.enabled", true)
+	viper.SetDefault("notification.preferences.unsubscribe_secret", "")
+	viper.SetDefault("notification.preferences.quiet_channels", []string{"push", "sms"})
+	viper.SetDefault("notification.preferences.default_timezone", "UTC")
+}
+
+// LoadConfig reads the `notification.preferences:` block from Viper.
+func LoadConfig() (Config, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("notification.preferences", &cfg); err != nil {
+		return Config{}, fmt.Errorf("preferences: invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// IsQuietChannel reports whether channel is held back during quiet hours.
+func (c Config) IsQuietChannel(channel string) bool {
+	for _, ch := range c.QuietChannels {
+		if ch == channel {
+			return true
+		}
+	}
+	return false
+}
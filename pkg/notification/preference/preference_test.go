@@ -0,0 +1,119 @@
+package preference
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clock(t *testing.T, s string) Clock {
+	t.Helper()
+	c, err := ParseClock(s)
+	require.NoError(t, err)
+	return c
+}
+
+func TestParseClock(t *testing.T) {
+	c, err := ParseClock("07:30")
+	require.NoError(t, err)
+	assert.Equal(t, Clock(450), c)
+	assert.Equal(t, "07:30", c.String())
+
+	for _, invalid := range []string{"", "7", "24:00", "12:60", "noon"} {
+		_, err := ParseClock(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestQuietHours_WrapsMidnight(t *testing.T) {
+	jakarta, err := LoadLocation("Asia/Jakarta") // UTC+7, no DST
+	require.NoError(t, err)
+	q := QuietHours{Start: clock(t, "22:00"), End: clock(t, "07:00")}
+
+	// 23:30 in Jakarta: quiet until 07:00 the next morning.
+	end, quiet := q.Until(time.Date(2026, 3, 1, 16, 30, 0, 0, time.UTC), jakarta)
+	require.True(t, quiet)
+	assert.Equal(t, time.Date(2026, 3, 2, 7, 0, 0, 0, jakarta), end)
+
+	// 05:00 in Jakarta: quiet until 07:00 the same morning.
+	end, quiet = q.Until(time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC), jakarta)
+	require.True(t, quiet)
+	assert.Equal(t, time.Date(2026, 3, 2, 7, 0, 0, 0, jakarta), end)
+
+	// 12:00 in Jakarta: not quiet.
+	_, quiet = q.Until(time.Date(2026, 3, 1, 5, 0, 0, 0, time.UTC), jakarta)
+	assert.False(t, quiet)
+
+	// The end of the window is not inside it.
+	_, quiet = q.Until(time.Date(2026, 3, 1, 7, 0, 0, 0, jakarta), jakarta)
+	assert.False(t, quiet)
+}
+
+func TestQuietHours_SameDayWindow(t *testing.T) {
+	q := QuietHours{Start: clock(t, "13:00"), End: clock(t, "14:00")}
+
+	end, quiet := q.Until(time.Date(2026, 3, 1, 13, 15, 0, 0, time.UTC), time.UTC)
+	require.True(t, quiet)
+	assert.Equal(t, time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC), end)
+
+	_, quiet = q.Until(time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC), time.UTC)
+	assert.False(t, quiet)
+}
+
+func TestQuietHours_EqualBoundsDisable(t *testing.T) {
+	q := QuietHours{Start: clock(t, "08:00"), End: clock(t, "08:00")}
+
+	_, quiet := q.Until(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), time.UTC)
+	assert.False(t, quiet)
+}
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	_, err = LoadLocation("Mars/Olympus_Mons")
+	assert.Error(t, err)
+}
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner("secret")
+	want := UnsubscribeToken{UserID: 42, EventType: "order.shipped", Channel: "email"}
+
+	got, err := signer.Verify(signer.Sign(want))
+
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestSigner_RejectsTamperedTokens(t *testing.T) {
+	signer := NewSigner("secret")
+	token := signer.Sign(UnsubscribeToken{UserID: 42})
+	forged := NewSigner("secret").Sign(UnsubscribeToken{UserID: 43})
+	_, forgedSig, _ := strings.Cut(forged, ".")
+	payload, _, _ := strings.Cut(token, ".")
+
+	for _, invalid := range []string{
+		"",
+		"not-a-token",
+		payload + "." + forgedSig, // signature of another payload
+		NewSigner("other").Sign(UnsubscribeToken{UserID: 42}),
+	} {
+		_, err := signer.Verify(invalid)
+		assert.ErrorIs(t, err, ErrInvalidToken, invalid)
+	}
+}
+
+func TestNewSigner_EmptySecretDisablesLinks(t *testing.T) {
+	assert.Nil(t, NewSigner(""))
+}
+
+func TestConfig_IsQuietChannel(t *testing.T) {
+	cfg := Config{QuietChannels: []string{"push", "sms"}}
+
+	assert.True(t, cfg.IsQuietChannel("sms"))
+	assert.False(t, cfg.IsQuietChannel("email"))
+}
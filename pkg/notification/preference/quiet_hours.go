@@ -0,0 +1,68 @@
+package preference
+
+import (
+	"fmt"
+	"time"
+	// Embed the time zone database: quiet hours must work on images without /usr/share/zoneinfo.
+	_ "time/tzdata"
+)
+
+// Clock is a time of day, in minutes after midnight.
+type Clock int
+
+// ParseClock parses a time of day written as "HH:MM" (24-hour).
+func ParseClock(s string) (Clock, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("preferences: invalid time of day %q, expected HH:MM", s)
+	}
+	return Clock(t.Hour()*60 + t.Minute()), nil
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// QuietHours is a daily window during which non-urgent notifications wait.
+// A Start after End wraps midnight (22:00–07:00); equal Start and End disable it.
+type QuietHours struct {
+	Start Clock
+	End   Clock
+}
+
+// Until reports whether t falls inside the window, read on the wall clock of loc,
+// and if so when the window ends.
+func (q QuietHours) Until(t time.Time, loc *time.Location) (time.Time, bool) {
+	if q.Start == q.End {
+		return time.Time{}, false
+	}
+	local := t.In(loc)
+	now := Clock(local.Hour()*60 + local.Minute())
+
+	inside := now >= q.Start && now < q.End
+	if q.Start > q.End {
+		inside = now >= q.Start || now < q.End
+	}
+	if !inside {
+		return time.Time{}, false
+	}
+
+	year, month, day := local.Date()
+	end := time.Date(year, month, day, int(q.End)/60, int(q.End)%60, 0, 0, loc)
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end, true
+}
+
+// LoadLocation returns the IANA time zone name, or UTC for an empty name.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("preferences: unknown time zone %q", name)
+	}
+	return loc, nil
+}
@@ -0,0 +1,75 @@
+package preference
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidToken is returned by Signer.Verify for tokens that were not signed
+// with the current secret or are malformed.
+var ErrInvalidToken = errors.New("preferences: invalid unsubscribe token")
+
+// UnsubscribeToken is what a one-click unsubscribe link opts the user out of.
+type UnsubscribeToken struct {
+	UserID int64 `json:"u"`
+	// EventType is the event the link was sent with; its category is opted out.
+	// Empty unsubscribes the user from all marketing notifications.
+	EventType string `json:"e,omitempty"`
+	// Channel is the channel opted out of; empty opts out of every channel of the category.
+	Channel string `json:"c,omitempty"`
+}
+
+// Signer signs and verifies unsubscribe tokens, so links work without a login
+// but cannot be forged for another user.
+//
+// Tokens do not expire: an unsubscribe link must keep working in old emails.
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner returns a Signer, or nil when secret is empty.
+func NewSigner(secret string) *Signer {
+	if secret == "" {
+		return nil
+	}
+	return &Signer{secret: []byte(secret)}
+}
+
+// Sign returns the URL-safe token of t: "<payload>.<signature>", both base64url.
+func (s *Signer) Sign(t UnsubscribeToken) string {
+	payload, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(s.mac(payload))
+}
+
+// Verify checks the signature of token and returns what it unsubscribes from.
+func (s *Signer) Verify(token string) (UnsubscribeToken, error) {
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return UnsubscribeToken{}, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return UnsubscribeToken{}, ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil || !hmac.Equal(sig, s.mac(payload)) {
+		return UnsubscribeToken{}, ErrInvalidToken
+	}
+
+	var t UnsubscribeToken
+	if err := json.Unmarshal(payload, &t); err != nil || t.UserID <= 0 {
+		return UnsubscribeToken{}, ErrInvalidToken
+	}
+	return t, nil
+}
+
+func (s *Signer) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
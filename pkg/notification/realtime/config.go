@@ -0,0 +1,56 @@
+package realtime
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds in-app notification configuration (the `notification.in_app:` YAML block).
+type Config struct {
+	// Enabled controls whether the in_app channel stores notifications.
+	// When false the channel logs and skips every event and streams are unavailable;
+	// the rest of the inbox API keeps working.
+	Enabled bool `mapstructure:"enabled"`
+
+	// RedisChannel is the Redis pub/sub channel the hubs of all pods share
+	// (default: notification:in_app). Without Redis, streams only receive
+	// notifications delivered by the same process.
+	RedisChannel string `mapstructure:"redis_channel"`
+
+	// Heartbeat is the interval of the keep-alive comments sent on idle streams,
+	// so proxies and load balancers do not close them (default: 25s).
+	Heartbeat time.Duration `mapstructure:"heartbeat"`
+
+	// MaxStreamDuration closes a stream after this long; the client reconnects and
+	// its token is checked again (default: 1h).
+	MaxStreamDuration time.Duration `mapstructure:"max_stream_duration"`
+
+	// MaxStreamsPerUser limits the open streams of one user on one pod (default: 5).
+	MaxStreamsPerUser int `mapstructure:"max_streams_per_user"`
+
+	// Buffer is the number of messages queued per stream; a stream that falls
+	// further behind misses messages (default: 32).
+	Buffer int `mapstructure:"buffer"`
+}
+
+// SetDefault registers Viper defaults for the in-app config block.
+// Called from config.setDefault() during application startup.
+func SetDefault() {
+	viper.SetDefault("notification.in_app.enabled", true)
+	viper.SetDefault("notification.in_app.redis_channel", "notification:in_app")
+	viper.SetDefault("notification.in_app.heartbeat", "25s")
+	viper.SetDefault("notification.in_app.max_stream_duration", "1h")
+	viper.SetDefault("notification.in_app.max_streams_per_user", 5)
+	viper.SetDefault("notification.in_app.buffer", 32)
+}
+
+// LoadConfig reads the `notification.in_app:` block from Viper.
+func LoadConfig() (Config, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("notification.in_app", &cfg); err != nil {
+		return Config{}, fmt.Errorf("in_app: invalid config: %w", err)
+	}
+	return cfg, nil
+}
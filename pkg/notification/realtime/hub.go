@@ -0,0 +1,220 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"ichi-go/pkg/logger"
+)
+
+// resubscribeDelay is the wait before the hub subscribes to the broker again
+// after the subscription failed or was lost.
+const resubscribeDelay = 5 * time.Second
+
+var (
+	// ErrHubClosed is returned by Subscribe once the hub has been shut down.
+	ErrHubClosed = errors.New("realtime: hub closed")
+	// ErrTooManyStreams is returned by Subscribe when the user already has
+	// Config.MaxStreamsPerUser streams open on this pod.
+	ErrTooManyStreams = errors.New("realtime: too many streams")
+)
+
+// Message is one event for the open streams of a user.
+type Message struct {
+	UserID int64           `json:"user_id"`
+	Event  string          `json:"event"`        // SSE event name, e.g. "notification"
+	ID     string          `json:"id,omitempty"` // SSE event ID; clients send the last one back as Last-Event-ID
+	Data   json.RawMessage `json:"data"`
+}
+
+// Broker carries messages between the hubs of all pods. *RedisBroker implements it.
+type Broker interface {
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe returns the payloads published by any pod, including this one.
+	// The channel is closed when ctx is done or the subscription is lost.
+	Subscribe(ctx context.Context) (<-chan []byte, error)
+}
+
+// Hub delivers messages to the streams of connected users.
+//
+// With a Broker, Publish goes through the broker and every pod's hub delivers
+// the message to its own streams, so a user connected to pod A receives messages
+// published on pod B. Without one, Publish delivers to the streams of this
+// process only.
+//
+// Delivery never blocks the publisher: a stream whose buffer is full misses the
+// message. Clients catch up by listing the inbox. Safe for concurrent use.
+type Hub struct {
+	broker     Broker // nil: in-process delivery only
+	buffer     int
+	maxPerUser int
+
+	mu      sync.RWMutex
+	streams map[int64]map[*Subscription]struct{}
+	closed  bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewHub creates a Hub. broker may be nil when Redis is not available. With a
+// broker, the hub subscribes to it right away and keeps resubscribing until
+// Shutdown is called.
+func NewHub(cfg Config, broker Broker) *Hub {
+	h := &Hub{
+		broker:     broker,
+		buffer:     cfg.Buffer,
+		maxPerUser: cfg.MaxStreamsPerUser,
+		streams:    make(map[int64]map[*Subscription]struct{}),
+		done:       make(chan struct{}),
+	}
+	if h.buffer <= 0 {
+		h.buffer = 32
+	}
+	if broker == nil {
+		close(h.done)
+		return h
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	payloads, err := broker.Subscribe(ctx)
+	if err != nil {
+		logger.Errorf("[realtime] broker subscription failed, retrying in %s: %v", resubscribeDelay, err)
+	}
+	go h.run(ctx, payloads)
+	return h
+}
+
+// Subscribe opens a stream for userID. Close the subscription when the client disconnects.
+func (h *Hub) Subscribe(userID int64) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+	if h.maxPerUser > 0 && len(h.streams[userID]) >= h.maxPerUser {
+		return nil, ErrTooManyStreams
+	}
+
+	ch := make(chan Message, h.buffer)
+	sub := &Subscription{UserID: userID, C: ch, ch: ch, hub: h}
+	if h.streams[userID] == nil {
+		h.streams[userID] = make(map[*Subscription]struct{})
+	}
+	h.streams[userID][sub] = struct{}{}
+	return sub, nil
+}
+
+// Publish sends msg to every open stream of msg.UserID, on all pods when the hub has a broker.
+func (h *Hub) Publish(ctx context.Context, msg Message) error {
+	if h.broker == nil {
+		h.deliver(msg)
+		return nil
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("realtime: encode message: %w", err)
+	}
+	if err := h.broker.Publish(ctx, payload); err != nil {
+		return fmt.Errorf("realtime: publish: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops the broker subscription and closes every open stream.
+// The DI container calls it on application shutdown.
+func (h *Hub) Shutdown() error {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	<-h.done
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for userID, subs := range h.streams {
+		for sub := range subs {
+			close(sub.ch)
+		}
+		delete(h.streams, userID)
+	}
+	return nil
+}
+
+// run delivers the broker's payloads to local streams, resubscribing whenever the
+// subscription fails or is lost, until ctx is cancelled.
+func (h *Hub) run(ctx context.Context, payloads <-chan []byte) {
+	defer close(h.done)
+	for {
+		if payloads != nil {
+			for payload := range payloads {
+				var msg Message
+				if err := json.Unmarshal(payload, &msg); err != nil {
+					logger.Warnf("[realtime] invalid broker message, dropping: %v", err)
+					continue
+				}
+				h.deliver(msg)
+			}
+			if ctx.Err() == nil {
+				logger.Warnf("[realtime] broker subscription lost, resubscribing in %s", resubscribeDelay)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+		var err error
+		if payloads, err = h.broker.Subscribe(ctx); err != nil {
+			logger.Errorf("[realtime] broker subscription failed, retrying in %s: %v", resubscribeDelay, err)
+			payloads = nil
+		}
+	}
+}
+
+func (h *Hub) deliver(msg Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.streams[msg.UserID] {
+		select {
+		case sub.ch <- msg:
+		default:
+			logger.Warnf("[realtime] stream of user %d is full, dropping %s event", msg.UserID, msg.Event)
+		}
+	}
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := h.streams[sub.UserID]
+	if _, ok := subs[sub]; !ok {
+		return // already closed, or the hub shut down
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.streams, sub.UserID)
+	}
+	close(sub.ch)
+}
+
+// Subscription is an open stream of one user.
+type Subscription struct {
+	UserID int64
+	// C receives the messages of the user. It is closed by Close and when the hub shuts down.
+	C <-chan Message
+
+	ch  chan Message
+	hub *Hub
+}
+
+// Close removes the stream from the hub. Safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+}
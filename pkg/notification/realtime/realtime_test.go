@@ -0,0 +1,170 @@
+package realtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memBroker stands in for Redis: every payload goes to every subscriber.
+type memBroker struct {
+	mu   sync.Mutex
+	subs map[chan []byte]struct{}
+}
+
+func newMemBroker() *memBroker {
+	return &memBroker{subs: make(map[chan []byte]struct{})}
+}
+
+func (b *memBroker) Publish(_ context.Context, payload []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		ch <- payload
+	}
+	return nil
+}
+
+func (b *memBroker) Subscribe(ctx context.Context) (<-chan []byte, error) {
+	ch := make(chan []byte, 16)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+	return ch, nil
+}
+
+func msg(userID int64, event string) Message {
+	return Message{UserID: userID, Event: event, Data: json.RawMessage(`{"n":1}`)}
+}
+
+func receive(t *testing.T, sub *Subscription) Message {
+	t.Helper()
+	select {
+	case m, ok := <-sub.C:
+		require.True(t, ok, "stream closed")
+		return m
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return Message{}
+	}
+}
+
+func assertNothing(t *testing.T, sub *Subscription) {
+	t.Helper()
+	select {
+	case m := <-sub.C:
+		t.Fatalf("unexpected message %+v", m)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// ============================================================================
+// Hub
+// ============================================================================
+
+func TestHub_LocalDeliveryToEveryStreamOfTheUser(t *testing.T) {
+	hub := NewHub(Config{}, nil)
+	a, err := hub.Subscribe(1)
+	require.NoError(t, err)
+	b, err := hub.Subscribe(1)
+	require.NoError(t, err)
+	other, err := hub.Subscribe(2)
+	require.NoError(t, err)
+
+	require.NoError(t, hub.Publish(context.Background(), msg(1, "notification")))
+
+	assert.Equal(t, "notification", receive(t, a).Event)
+	assert.Equal(t, "notification", receive(t, b).Event)
+	assertNothing(t, other)
+}
+
+func TestHub_FanOutAcrossPods(t *testing.T) {
+	broker := newMemBroker()
+	podA := NewHub(Config{}, broker)
+	podB := NewHub(Config{}, broker)
+	defer podA.Shutdown()
+	defer podB.Shutdown()
+
+	sub, err := podA.Subscribe(1)
+	require.NoError(t, err)
+
+	require.NoError(t, podB.Publish(context.Background(), msg(1, "unread_count")))
+
+	got := receive(t, sub)
+	assert.Equal(t, int64(1), got.UserID)
+	assert.Equal(t, "unread_count", got.Event)
+	assert.JSONEq(t, `{"n":1}`, string(got.Data))
+}
+
+func TestHub_MaxStreamsPerUser(t *testing.T) {
+	hub := NewHub(Config{MaxStreamsPerUser: 1}, nil)
+	sub, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	_, err = hub.Subscribe(1)
+	assert.ErrorIs(t, err, ErrTooManyStreams)
+
+	sub.Close()
+	_, err = hub.Subscribe(1)
+	assert.NoError(t, err, "closing a stream frees its slot")
+}
+
+func TestHub_FullStreamDoesNotBlockPublisher(t *testing.T) {
+	hub := NewHub(Config{Buffer: 1}, nil)
+	sub, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	require.NoError(t, hub.Publish(context.Background(), msg(1, "first")))
+	require.NoError(t, hub.Publish(context.Background(), msg(1, "second"))) // dropped
+
+	assert.Equal(t, "first", receive(t, sub).Event)
+	assertNothing(t, sub)
+}
+
+func TestHub_CloseAndShutdown(t *testing.T) {
+	hub := NewHub(Config{}, newMemBroker())
+	closed, err := hub.Subscribe(1)
+	require.NoError(t, err)
+	open, err := hub.Subscribe(1)
+	require.NoError(t, err)
+
+	closed.Close()
+	closed.Close() // idempotent
+	_, ok := <-closed.C
+	assert.False(t, ok)
+
+	require.NoError(t, hub.Shutdown())
+	_, ok = <-open.C
+	assert.False(t, ok, "shutdown closes open streams")
+	open.Close() // after shutdown: no-op
+
+	_, err = hub.Subscribe(1)
+	assert.ErrorIs(t, err, ErrHubClosed)
+}
+
+// ============================================================================
+// SSE encoding
+// ============================================================================
+
+func TestWriteEvent(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteEvent(&buf, Message{ID: "42", Event: "notification", Data: json.RawMessage("{\"a\":1,\n\"b\":2}")}))
+	assert.Equal(t, "id: 42\nevent: notification\ndata: {\"a\":1,\ndata: \"b\":2}\n\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteComment(&buf, "ping"))
+	assert.Equal(t, ": ping\n\n", buf.String())
+}
@@ -0,0 +1,54 @@
+package realtime
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBroker shares hub messages between pods over one Redis pub/sub channel.
+// Messages published while a pod is disconnected from Redis are lost for that pod.
+type RedisBroker struct {
+	client  *redis.Client
+	channel string
+}
+
+func NewRedisBroker(client *redis.Client, channel string) *RedisBroker {
+	return &RedisBroker{client: client, channel: channel}
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, payload []byte) error {
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+// Subscribe subscribes to the channel and waits for Redis to confirm it.
+func (b *RedisBroker) Subscribe(ctx context.Context) (<-chan []byte, error) {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	out := make(chan []byte, 64)
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+		in := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
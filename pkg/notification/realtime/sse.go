@@ -0,0 +1,33 @@
+package realtime
+
+import (
+	"io"
+	"strings"
+)
+
+// ContentType is the media type of Server-Sent Events streams.
+const ContentType = "text/event-stream"
+
+// WriteEvent writes msg to w as one Server-Sent Event. Multi-line data is split
+// into several data fields, as the format requires.
+func WriteEvent(w io.Writer, msg Message) error {
+	var b strings.Builder
+	if msg.ID != "" {
+		b.WriteString("id: " + msg.ID + "\n")
+	}
+	if msg.Event != "" {
+		b.WriteString("event: " + msg.Event + "\n")
+	}
+	for _, line := range strings.Split(string(msg.Data), "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteComment writes a comment line, which clients ignore. Used as a keep-alive.
+func WriteComment(w io.Writer, text string) error {
+	_, err := io.WriteString(w, ": "+text+"\n\n")
+	return err
+}
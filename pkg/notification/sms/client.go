@@ -0,0 +1,108 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Client routes SMS to the configured providers, rate limiting each one.
+//
+// Pitfalls to be aware of:
+//   - Rate limits are per process. With N workers the provider sees up to
+//     N × rate_per_second; size the setting accordingly.
+//   - A send waits for a rate-limit slot until ctx is done; the wait is not
+//     an error.
+//   - Use IsPermanent on Send errors to decide between dropping and retrying
+//     the message.
+type Client struct {
+	providers       map[string]*limitedProvider
+	defaultProvider string
+}
+
+type limitedProvider struct {
+	Provider
+	limiter *rate.Limiter
+}
+
+// NewClient builds every provider of cfg.
+func NewClient(cfg Config) (*Client, error) {
+	if len(cfg.Providers) == 0 {
+		return nil, fmt.Errorf("sms: no providers configured")
+	}
+
+	c := &Client{providers: make(map[string]*limitedProvider, len(cfg.Providers)), defaultProvider: cfg.DefaultProvider}
+	for name, pc := range cfg.Providers {
+		if pc.Timeout <= 0 {
+			pc.Timeout = 10 * time.Second
+		}
+		if pc.RatePerSecond <= 0 {
+			pc.RatePerSecond = 10
+		}
+		if pc.Burst <= 0 {
+			pc.Burst = 1
+		}
+		p, err := NewProvider(name, pc)
+		if err != nil {
+			return nil, err
+		}
+		c.providers[name] = &limitedProvider{Provider: p, limiter: rate.NewLimiter(rate.Limit(pc.RatePerSecond), pc.Burst)}
+	}
+
+	if c.defaultProvider == "" {
+		if len(c.providers) > 1 {
+			return nil, fmt.Errorf("sms: default_provider is required with several providers")
+		}
+		for name := range c.providers {
+			c.defaultProvider = name
+		}
+	}
+	if _, ok := c.providers[c.defaultProvider]; !ok {
+		return nil, fmt.Errorf("sms: default_provider %q is not configured", c.defaultProvider)
+	}
+	return c, nil
+}
+
+// DefaultProvider returns the name of the provider used when none is requested.
+func (c *Client) DefaultProvider() string {
+	return c.defaultProvider
+}
+
+// Providers returns the configured provider names, sorted.
+func (c *Client) Providers() []string {
+	names := make([]string, 0, len(c.providers))
+	for name := range c.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Send submits msg through provider, or the default provider when empty.
+func (c *Client) Send(ctx context.Context, provider string, msg Message) (Result, error) {
+	if provider == "" {
+		provider = c.defaultProvider
+	}
+	p, ok := c.providers[provider]
+	if !ok {
+		return Result{}, &ProviderError{Provider: provider, Message: "provider is not configured", Permanent: true}
+	}
+
+	if err := p.limiter.Wait(ctx); err != nil {
+		return Result{}, fmt.Errorf("sms: %s: rate limit wait: %w", provider, err)
+	}
+	return p.Send(ctx, msg)
+}
+
+// ParseReceipt verifies and decodes a delivery-receipt callback for provider.
+func (c *Client) ParseReceipt(provider string, r *http.Request, body []byte) ([]Receipt, error) {
+	p, ok := c.providers[provider]
+	if !ok {
+		return nil, fmt.Errorf("sms: provider %q is not configured", provider)
+	}
+	return p.ParseReceipt(r, body)
+}
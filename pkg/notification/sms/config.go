@@ -0,0 +1,89 @@
+package sms
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Provider types accepted in ProviderConfig.Type.
+const (
+	ProviderTypeHTTP   = "http"   // generic HTTP/JSON gateway
+	ProviderTypeTwilio = "twilio" // Twilio or any Twilio-compatible Messages API
+)
+
+// Config holds SMS configuration (the `notification.sms:` YAML block).
+type Config struct {
+	// Enabled controls whether the SMS client is initialized.
+	// When false the SMS channel logs and skips every message.
+	Enabled bool `mapstructure:"enabled"`
+
+	// DefaultProvider is the key in Providers used when an event does not name one.
+	DefaultProvider string `mapstructure:"default_provider"`
+
+	// DefaultCountryCode is the calling code (without "+") assumed for national
+	// numbers such as "0812 3456 7890", e.g. "62" for Indonesia.
+	DefaultCountryCode string `mapstructure:"default_country_code"`
+
+	// MaxSegments rejects messages longer than this many segments (default: 6).
+	// Every segment is billed separately.
+	MaxSegments int `mapstructure:"max_segments"`
+
+	Providers map[string]ProviderConfig `mapstructure:"providers"`
+}
+
+// ProviderConfig configures one SMS provider.
+type ProviderConfig struct {
+	// Type is "http" or "twilio".
+	Type string `mapstructure:"type"`
+
+	// From is the sender ID or number. Twilio also accepts a Messaging Service SID (MG...).
+	From string `mapstructure:"from"`
+
+	// Endpoint is the send URL of an http provider, or the API base URL of a
+	// twilio provider (default: https://api.twilio.com).
+	Endpoint string `mapstructure:"endpoint"`
+
+	// APIKey is sent as "Authorization: Bearer <key>" by http providers.
+	APIKey string `mapstructure:"api_key"`
+	// Headers are extra request headers of http providers.
+	Headers map[string]string `mapstructure:"headers"`
+
+	// AccountSID and AuthToken authenticate twilio providers. The auth token also
+	// verifies the X-Twilio-Signature of delivery receipts.
+	AccountSID string `mapstructure:"account_sid"`
+	AuthToken  string `mapstructure:"auth_token"`
+
+	// CallbackURL is the public URL of the delivery-receipt endpoint for this provider,
+	// e.g. https://api.example.com/svc/api/notifications/sms/receipts/twilio.
+	// Twilio signs receipts over this exact URL.
+	CallbackURL string `mapstructure:"callback_url"`
+	// CallbackSecret verifies the X-Signature (hex HMAC-SHA256 of the body) of
+	// http provider receipts. Receipts are refused while it is empty.
+	CallbackSecret string `mapstructure:"callback_secret"`
+
+	// RatePerSecond caps outbound requests to this provider (default: 10).
+	// Sends wait for a slot instead of failing.
+	RatePerSecond float64 `mapstructure:"rate_per_second"`
+	// Burst is the number of requests allowed at once (default: 1).
+	Burst int `mapstructure:"burst"`
+	// Timeout bounds each provider request (default: 10s).
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// SetDefault registers Viper defaults for the SMS config block.
+// Called from config.setDefault() during application startup.
+func SetDefault() {
+	viper.SetDefault("notification.sms.enabled", false)
+	viper.SetDefault("notification.sms.max_segments", 6)
+}
+
+// LoadConfig reads the `notification.sms:` block from Viper.
+func LoadConfig() (Config, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("notification.sms", &cfg); err != nil {
+		return Config{}, fmt.Errorf("sms: invalid config: %w", err)
+	}
+	return cfg, nil
+}
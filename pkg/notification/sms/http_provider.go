@@ -0,0 +1,157 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// httpProvider talks to a generic HTTP/JSON SMS gateway.
+//
+// Send request:   POST {endpoint}  {"to": "+62...", "from": "...", "text": "..."}
+// Send response:  2xx {"message_id": "...", "status": "queued"}  ("id" is accepted too)
+// Error response: non-2xx {"code": "...", "message": "..."}
+//
+// Receipt callback body, signed with X-Signature = hex(HMAC-SHA256(body, callback_secret));
+// receipts are refused while callback_secret is empty:
+//
+//	{"message_id": "...", "status": "delivered|failed|...", "error": "..."}
+//
+// or a JSON array of such objects.
+type httpProvider struct {
+	name   string
+	cfg    ProviderConfig
+	client *http.Client
+}
+
+func newHTTPProvider(name string, cfg ProviderConfig) (*httpProvider, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("sms: provider %q: endpoint is required", name)
+	}
+	return &httpProvider{name: name, cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+type httpSendRequest struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Text string `json:"text"`
+}
+
+type httpSendResponse struct {
+	MessageID string `json:"message_id"`
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+func (p *httpProvider) Send(ctx context.Context, msg Message) (Result, error) {
+	payload, err := json.Marshal(httpSendRequest{To: msg.To, From: p.cfg.From, Text: msg.Body})
+	if err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.cfg.APIKey)
+	}
+	for k, v := range p.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("sms: %s: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var decoded httpSendResponse
+	_ = json.Unmarshal(body, &decoded)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message := decoded.Message
+		if message == "" {
+			message = strings.TrimSpace(string(body))
+		}
+		return Result{}, &ProviderError{
+			Provider:   p.name,
+			StatusCode: resp.StatusCode,
+			Code:       decoded.Code,
+			Message:    message,
+			Permanent:  permanentStatus(resp.StatusCode),
+		}
+	}
+
+	id := decoded.MessageID
+	if id == "" {
+		id = decoded.ID
+	}
+	return Result{MessageID: id, Status: decoded.Status}, nil
+}
+
+type httpReceipt struct {
+	MessageID string `json:"message_id"`
+	Status    string `json:"status"`
+	Error     string `json:"error"`
+}
+
+func (p *httpProvider) ParseReceipt(r *http.Request, body []byte) ([]Receipt, error) {
+	// Without a secret nothing can be verified, so every receipt is refused.
+	if p.cfg.CallbackSecret == "" {
+		return nil, ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(p.cfg.CallbackSecret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(r.Header.Get("X-Signature")))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var raw []httpReceipt
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, fmt.Errorf("sms: %s: invalid receipt: %w", p.name, err)
+		}
+	} else {
+		var single httpReceipt
+		if err := json.Unmarshal(trimmed, &single); err != nil {
+			return nil, fmt.Errorf("sms: %s: invalid receipt: %w", p.name, err)
+		}
+		raw = []httpReceipt{single}
+	}
+
+	receipts := make([]Receipt, 0, len(raw))
+	for _, rr := range raw {
+		if rr.MessageID == "" {
+			continue
+		}
+		receipts = append(receipts, Receipt{MessageID: rr.MessageID, Status: normalizeStatus(rr.Status), Error: rr.Error})
+	}
+	return receipts, nil
+}
+
+// normalizeStatus maps the usual gateway vocabulary onto ReceiptStatus.
+func normalizeStatus(status string) ReceiptStatus {
+	switch strings.ToLower(status) {
+	case "delivered", "delivrd":
+		return ReceiptDelivered
+	case "failed", "undelivered", "undeliv", "rejected", "rejectd", "expired", "canceled":
+		return ReceiptFailed
+	default:
+		return ReceiptPending
+	}
+}
@@ -0,0 +1,54 @@
+package sms
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPhone is returned by NormalizeE164 for input that cannot be a phone number.
+var ErrInvalidPhone = errors.New("sms: invalid phone number")
+
+// NormalizeE164 converts a phone number to E.164 ("+6281234567890").
+//
+// Accepted forms:
+//   - international: "+62 812-3456-7890", "0062 812 3456 7890"
+//   - national with trunk prefix: "0812 3456 7890", using defaultCountryCode ("62")
+//   - digits already carrying the country code: "6281234567890"
+//
+// Spaces, dashes, dots and parentheses are ignored. E.164 allows at most 15
+// digits; fewer than 8 is rejected as too short to be a subscriber number.
+func NormalizeE164(raw, defaultCountryCode string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+	number := b.String()
+
+	var digits string
+	switch {
+	case strings.HasPrefix(number, "+"):
+		digits = number[1:]
+	case strings.HasPrefix(number, "00"):
+		digits = number[2:]
+	case strings.HasPrefix(number, "0"):
+		if defaultCountryCode == "" {
+			return "", ErrInvalidPhone
+		}
+		digits = strings.TrimPrefix(defaultCountryCode, "+") + number[1:]
+	default:
+		digits = number
+	}
+
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	return "+" + digits, nil
+}
@@ -0,0 +1,109 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Message is one outbound SMS.
+type Message struct {
+	To   string // E.164
+	Body string
+}
+
+// Result is the provider's acknowledgement of an accepted message.
+type Result struct {
+	MessageID string // provider message ID, matched against delivery receipts
+	Status    string // provider status at acceptance, e.g. "queued"
+}
+
+// ReceiptStatus is the normalized state of a delivery receipt.
+type ReceiptStatus string
+
+const (
+	ReceiptPending   ReceiptStatus = "pending"   // accepted or in flight; not final
+	ReceiptDelivered ReceiptStatus = "delivered" // handset confirmed delivery
+	ReceiptFailed    ReceiptStatus = "failed"    // rejected or undeliverable
+)
+
+// Receipt is a delivery report pushed by a provider to the callback endpoint.
+type Receipt struct {
+	MessageID string
+	Status    ReceiptStatus
+	Error     string // provider error code and description when failed
+}
+
+// Provider sends SMS through one gateway and parses its delivery receipts.
+//
+// Adding a new provider:
+//  1. Implement this interface in a new file of this package
+//  2. Add its type constant and a case in NewProvider
+type Provider interface {
+	// Send submits msg. Errors should be a *ProviderError when the gateway answered.
+	Send(ctx context.Context, msg Message) (Result, error)
+
+	// ParseReceipt verifies and decodes a delivery-receipt callback.
+	// body is the raw request body, already read from r.
+	ParseReceipt(r *http.Request, body []byte) ([]Receipt, error)
+}
+
+// ProviderError is a send the gateway answered with a failure, or that could
+// not be routed to a provider at all.
+type ProviderError struct {
+	Provider   string
+	StatusCode int    // HTTP status; 0 when the request was never sent
+	Code       string // provider error code, when given
+	Message    string
+	Permanent  bool
+}
+
+func (e *ProviderError) Error() string {
+	msg := "sms: " + e.Provider + ": "
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf("HTTP %d: ", e.StatusCode)
+	}
+	msg += e.Message
+	if e.Code != "" {
+		msg += " (code " + e.Code + ")"
+	}
+	return msg
+}
+
+// IsPermanent reports whether err will fail again on retry: an invalid or
+// unsubscribed number, rejected content, an unknown provider. Network errors,
+// rate limiting (429), bad credentials (401/403) and 5xx responses are transient.
+func IsPermanent(err error) bool {
+	var pErr *ProviderError
+	return errors.As(err, &pErr) && pErr.Permanent
+}
+
+// permanentStatus is the default classification of an HTTP status.
+func permanentStatus(status int) bool {
+	switch {
+	case status == http.StatusRequestTimeout, status == http.StatusTooManyRequests:
+		return false
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		// Credentials are configuration, not message content: retry so a fix
+		// in config does not lose the backlog.
+		return false
+	default:
+		return status >= 400 && status < 500
+	}
+}
+
+// ErrInvalidSignature is returned by ParseReceipt for unauthenticated callbacks.
+var ErrInvalidSignature = errors.New("sms: invalid receipt signature")
+
+// NewProvider builds the provider described by cfg.
+func NewProvider(name string, cfg ProviderConfig) (Provider, error) {
+	switch cfg.Type {
+	case ProviderTypeHTTP:
+		return newHTTPProvider(name, cfg)
+	case ProviderTypeTwilio:
+		return newTwilioProvider(name, cfg)
+	default:
+		return nil, fmt.Errorf("sms: provider %q: unknown type %q", name, cfg.Type)
+	}
+}
@@ -0,0 +1,77 @@
+package sms
+
+import "unicode/utf16"
+
+// Encodings of an SMS body.
+const (
+	EncodingGSM7 = "GSM-7"
+	EncodingUCS2 = "UCS-2"
+)
+
+// gsm7Basic is the GSM 03.38 default alphabet; each character costs one septet.
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extension characters are sent as ESC + char and cost two septets.
+const gsm7Extension = "\f^{}\\[~]|€"
+
+var (
+	gsm7BasicSet     = runeSet(gsm7Basic)
+	gsm7ExtensionSet = runeSet(gsm7Extension)
+)
+
+// SegmentInfo describes how a body is split into SMS segments.
+type SegmentInfo struct {
+	Encoding string // EncodingGSM7 or EncodingUCS2
+	Units    int    // septets for GSM-7, UTF-16 code units for UCS-2
+	Segments int
+}
+
+// CountSegments reports the encoding and segment count of text.
+//
+// A single GSM-7 segment holds 160 septets and a UCS-2 one 70 code units.
+// Concatenated messages spend 7 septets / 3 code units of each segment on
+// the UDH, leaving 153 and 67. Any character outside the GSM-7 alphabet
+// (emoji, most non-Latin scripts) switches the whole message to UCS-2.
+func CountSegments(text string) SegmentInfo {
+	if text == "" {
+		return SegmentInfo{Encoding: EncodingGSM7}
+	}
+
+	septets := 0
+	gsm7 := true
+	for _, r := range text {
+		switch {
+		case gsm7BasicSet[r]:
+			septets++
+		case gsm7ExtensionSet[r]:
+			septets += 2
+		default:
+			gsm7 = false
+		}
+		if !gsm7 {
+			break
+		}
+	}
+	if gsm7 {
+		return SegmentInfo{Encoding: EncodingGSM7, Units: septets, Segments: segments(septets, 160, 153)}
+	}
+
+	units := len(utf16.Encode([]rune(text)))
+	return SegmentInfo{Encoding: EncodingUCS2, Units: units, Segments: segments(units, 70, 67)}
+}
+
+func segments(units, single, multi int) int {
+	if units <= single {
+		return 1
+	}
+	return (units + multi - 1) / multi
+}
+
+func runeSet(s string) map[rune]bool {
+	set := make(map[rune]bool, len(s))
+	for _, r := range s {
+		set[r] = true
+	}
+	return set
+}
@@ -0,0 +1,256 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ============================================================================
+// Phone numbers and segments
+// ============================================================================
+
+func TestNormalizeE164(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"+62 812-3456-7890", "+6281234567890"},
+		{"0062 812 3456 7890", "+6281234567890"},
+		{"0812 3456 7890", "+6281234567890"},
+		{"6281234567890", "+6281234567890"},
+		{"+1 (415) 555.2671", "+14155552671"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := NormalizeE164(tt.raw, "62")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, raw := range []string{"", "12345", "+62 812 abc", "+1234567890123456", "0812345678"} {
+		t.Run("invalid "+raw, func(t *testing.T) {
+			countryCode := "62"
+			if raw == "0812345678" {
+				countryCode = "" // national number without a default country
+			}
+			_, err := NormalizeE164(raw, countryCode)
+			assert.ErrorIs(t, err, ErrInvalidPhone)
+		})
+	}
+}
+
+func TestCountSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		encoding string
+		units    int
+		segments int
+	}{
+		{"empty", "", EncodingGSM7, 0, 0},
+		{"single GSM-7", strings.Repeat("a", 160), EncodingGSM7, 160, 1},
+		{"concatenated GSM-7", strings.Repeat("a", 161), EncodingGSM7, 161, 2},
+		{"extension chars cost two", strings.Repeat("€", 80), EncodingGSM7, 160, 1},
+		{"emoji forces UCS-2", "Order shipped 📦", EncodingUCS2, 16, 1},
+		{"concatenated UCS-2", strings.Repeat("ä", 70) + "한", EncodingUCS2, 71, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := CountSegments(tt.text)
+			assert.Equal(t, tt.encoding, info.Encoding)
+			assert.Equal(t, tt.units, info.Units)
+			assert.Equal(t, tt.segments, info.Segments)
+		})
+	}
+}
+
+// ============================================================================
+// HTTP/JSON provider
+// ============================================================================
+
+func TestHTTPProvider_Send(t *testing.T) {
+	var got httpSendRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer key-1", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"message_id":"m-1","status":"queued"}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider("gateway", ProviderConfig{Type: ProviderTypeHTTP, Endpoint: srv.URL, APIKey: "key-1", From: "ICHI"})
+	require.NoError(t, err)
+
+	result, err := p.Send(context.Background(), Message{To: "+6281234567890", Body: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "m-1", result.MessageID)
+	assert.Equal(t, httpSendRequest{To: "+6281234567890", From: "ICHI", Text: "hi"}, got)
+}
+
+func TestHTTPProvider_ClassifiesErrors(t *testing.T) {
+	status := http.StatusBadRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"code":"invalid_number","message":"bad number"}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider("gateway", ProviderConfig{Type: ProviderTypeHTTP, Endpoint: srv.URL})
+	require.NoError(t, err)
+
+	_, err = p.Send(context.Background(), Message{To: "+6281234567890", Body: "hi"})
+	assert.True(t, IsPermanent(err), "400 must be permanent: %v", err)
+
+	for _, status = range []int{http.StatusTooManyRequests, http.StatusUnauthorized, http.StatusBadGateway} {
+		_, err = p.Send(context.Background(), Message{To: "+6281234567890", Body: "hi"})
+		require.Error(t, err)
+		assert.False(t, IsPermanent(err), "%d must be transient", status)
+	}
+}
+
+func TestHTTPProvider_ParseReceipt(t *testing.T) {
+	p, err := NewProvider("gateway", ProviderConfig{Type: ProviderTypeHTTP, Endpoint: "http://unused", CallbackSecret: "s3cret"})
+	require.NoError(t, err)
+
+	body := []byte(`[{"message_id":"m-1","status":"DELIVRD"},{"message_id":"m-2","status":"undelivered","error":"absent subscriber"}]`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+
+	r := httptest.NewRequest(http.MethodPost, "/receipts/gateway", nil)
+	r.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	receipts, err := p.ParseReceipt(r, body)
+	require.NoError(t, err)
+	assert.Equal(t, []Receipt{
+		{MessageID: "m-1", Status: ReceiptDelivered},
+		{MessageID: "m-2", Status: ReceiptFailed, Error: "absent subscriber"},
+	}, receipts)
+
+	r.Header.Set("X-Signature", "deadbeef")
+	_, err = p.ParseReceipt(r, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestHTTPProvider_ParseReceipt_RequiresSecret(t *testing.T) {
+	p, err := NewProvider("gateway", ProviderConfig{Type: ProviderTypeHTTP, Endpoint: "http://unused"})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/receipts/gateway", nil)
+	_, err = p.ParseReceipt(r, []byte(`{"message_id":"m-1","status":"delivered"}`))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+// ============================================================================
+// Twilio-compatible provider
+// ============================================================================
+
+func TestTwilioProvider_Send(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "AC123", user)
+		assert.Equal(t, "token", pass)
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "+6281234567890", r.PostForm.Get("To"))
+		assert.Equal(t, "MG999", r.PostForm.Get("MessagingServiceSid"))
+		assert.Equal(t, "https://api.example.com/receipts/twilio", r.PostForm.Get("StatusCallback"))
+
+		if r.PostForm.Get("Body") == "unsubscribed" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":21610,"message":"Attempt to send to unsubscribed recipient","status":400}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sid":"SM1","status":"queued"}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider("twilio", ProviderConfig{
+		Type:        ProviderTypeTwilio,
+		Endpoint:    srv.URL,
+		AccountSID:  "AC123",
+		AuthToken:   "token",
+		From:        "MG999",
+		CallbackURL: "https://api.example.com/receipts/twilio",
+	})
+	require.NoError(t, err)
+
+	result, err := p.Send(context.Background(), Message{To: "+6281234567890", Body: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, Result{MessageID: "SM1", Status: "queued"}, result)
+
+	_, err = p.Send(context.Background(), Message{To: "+6281234567890", Body: "unsubscribed"})
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+	assert.Contains(t, err.Error(), "21610")
+}
+
+func TestTwilioProvider_ParseReceipt(t *testing.T) {
+	callbackURL := "https://api.example.com/receipts/twilio"
+	p, err := NewProvider("twilio", ProviderConfig{
+		Type: ProviderTypeTwilio, AccountSID: "AC123", AuthToken: "token", From: "+15005550006", CallbackURL: callbackURL,
+	})
+	require.NoError(t, err)
+
+	form := url.Values{"MessageSid": {"SM1"}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}
+	body := []byte(form.Encode())
+
+	r := httptest.NewRequest(http.MethodPost, "/receipts/twilio", nil)
+	r.Header.Set("X-Twilio-Signature", twilioSignature("token", callbackURL, form))
+	receipts, err := p.ParseReceipt(r, body)
+	require.NoError(t, err)
+	assert.Equal(t, []Receipt{{MessageID: "SM1", Status: ReceiptFailed, Error: "twilio error 30003"}}, receipts)
+
+	r.Header.Set("X-Twilio-Signature", twilioSignature("other", callbackURL, form))
+	_, err = p.ParseReceipt(r, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+// ============================================================================
+// Client
+// ============================================================================
+
+func TestClient_RateLimitsEachProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"m"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(Config{Providers: map[string]ProviderConfig{
+		"gateway": {Type: ProviderTypeHTTP, Endpoint: srv.URL, RatePerSecond: 20, Burst: 1},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", client.DefaultProvider())
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.Send(context.Background(), "", Message{To: "+6281234567890", Body: "hi"})
+		require.NoError(t, err)
+	}
+	// Burst 1 at 20/s: the 2nd and 3rd sends wait 50ms each.
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	_, err = client.Send(context.Background(), "missing", Message{To: "+6281234567890", Body: "hi"})
+	assert.True(t, IsPermanent(err))
+}
+
+func TestNewClient_RequiresDefaultWithSeveralProviders(t *testing.T) {
+	_, err := NewClient(Config{Providers: map[string]ProviderConfig{
+		"a": {Type: ProviderTypeHTTP, Endpoint: "http://a"},
+		"b": {Type: ProviderTypeHTTP, Endpoint: "http://b"},
+	}})
+	assert.Error(t, err)
+}
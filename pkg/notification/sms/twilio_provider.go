@@ -0,0 +1,171 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // Twilio signs callbacks with HMAC-SHA1
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const twilioDefaultEndpoint = "https://api.twilio.com"
+
+// twilioProvider sends through the Twilio Messages API, or any gateway
+// compatible with it (same paths, form fields, basic auth and signatures).
+//
+// Send request:  POST {endpoint}/2010-04-01/Accounts/{sid}/Messages.json
+// form fields:   To, Body, From (or MessagingServiceSid for MG... senders), StatusCallback
+//
+// Receipts are form posts with MessageSid, MessageStatus and ErrorCode, signed
+// with X-Twilio-Signature = base64(HMAC-SHA1(callback_url + sorted key/value pairs, auth_token)).
+type twilioProvider struct {
+	name   string
+	cfg    ProviderConfig
+	client *http.Client
+}
+
+func newTwilioProvider(name string, cfg ProviderConfig) (*twilioProvider, error) {
+	if cfg.AccountSID == "" || cfg.AuthToken == "" {
+		return nil, fmt.Errorf("sms: provider %q: account_sid and auth_token are required", name)
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("sms: provider %q: from is required", name)
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = twilioDefaultEndpoint
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	return &twilioProvider{name: name, cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+type twilioResponse struct {
+	SID     string `json:"sid"`
+	Status  string `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// twilioTransientCodes are error codes of 4xx answers that are nevertheless worth retrying.
+var twilioTransientCodes = map[int]bool{
+	20429: true, // too many requests
+	30001: true, // queue overflow
+}
+
+func (p *twilioProvider) Send(ctx context.Context, msg Message) (Result, error) {
+	form := url.Values{}
+	form.Set("To", msg.To)
+	form.Set("Body", msg.Body)
+	if strings.HasPrefix(p.cfg.From, "MG") {
+		form.Set("MessagingServiceSid", p.cfg.From)
+	} else {
+		form.Set("From", p.cfg.From)
+	}
+	if p.cfg.CallbackURL != "" {
+		form.Set("StatusCallback", p.cfg.CallbackURL)
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", p.cfg.Endpoint, url.PathEscape(p.cfg.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(p.cfg.AccountSID, p.cfg.AuthToken)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("sms: %s: %w", p.name, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var decoded twilioResponse
+	_ = json.Unmarshal(body, &decoded)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		pErr := &ProviderError{
+			Provider:   p.name,
+			StatusCode: resp.StatusCode,
+			Message:    decoded.Message,
+			Permanent:  permanentStatus(resp.StatusCode) && !twilioTransientCodes[decoded.Code],
+		}
+		if decoded.Code != 0 {
+			pErr.Code = strconv.Itoa(decoded.Code)
+		}
+		if pErr.Message == "" {
+			pErr.Message = strings.TrimSpace(string(body))
+		}
+		return Result{}, pErr
+	}
+
+	return Result{MessageID: decoded.SID, Status: decoded.Status}, nil
+}
+
+func (p *twilioProvider) ParseReceipt(r *http.Request, body []byte) ([]Receipt, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("sms: %s: invalid receipt: %w", p.name, err)
+	}
+
+	callbackURL := p.cfg.CallbackURL
+	if callbackURL == "" {
+		// Without a configured URL, trust the request line. Breaks behind
+		// proxies that rewrite the host or scheme; set callback_url there.
+		scheme := "https"
+		if r.TLS == nil {
+			scheme = "http"
+		}
+		callbackURL = scheme + "://" + r.Host + r.URL.RequestURI()
+	}
+	expected := twilioSignature(p.cfg.AuthToken, callbackURL, form)
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Twilio-Signature"))) {
+		return nil, ErrInvalidSignature
+	}
+
+	sid := form.Get("MessageSid")
+	if sid == "" {
+		sid = form.Get("SmsSid")
+	}
+	if sid == "" {
+		return nil, nil
+	}
+
+	receipt := Receipt{MessageID: sid, Status: normalizeStatus(form.Get("MessageStatus"))}
+	if code := form.Get("ErrorCode"); code != "" && receipt.Status == ReceiptFailed {
+		receipt.Error = "twilio error " + code
+		if msg := form.Get("ErrorMessage"); msg != "" {
+			receipt.Error += ": " + msg
+		}
+	}
+	return []Receipt{receipt}, nil
+}
+
+// twilioSignature computes the X-Twilio-Signature of a form callback.
+func twilioSignature(authToken, callbackURL string, form url.Values) string {
+	keys := make([]string, 0, len(form))
+	for k := range form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(callbackURL)
+	for _, k := range keys {
+		for _, v := range form[k] {
+			b.WriteString(k)
+			b.WriteString(v)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
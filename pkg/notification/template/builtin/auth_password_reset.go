@@ -0,0 +1,49 @@
+package builtin
+
+import (
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+// PasswordResetTemplate is the built-in template for the "auth.password_reset" event.
+// Sent when a user asks to reset a forgotten password.
+//
+// Required data variables:
+//   - name        string — user display name
+//   - reset_url   string — link carrying the single-use reset token
+//   - expires_in  string — human readable validity, e.g. "30 minutes"
+type PasswordResetTemplate struct{}
+
+func (t PasswordResetTemplate) Slug() string { return "auth.password_reset" }
+
+func (t PasswordResetTemplate) SupportedChannels() []string {
+	return []string{"email"}
+}
+
+func (t PasswordResetTemplate) Classification() notiftemplate.Classification {
+	return notiftemplate.Classification{
+		Category: "account",
+		Kind:     notiftemplate.KindTransactional,
+		Urgent:   true,
+	}
+}
+
+func (t PasswordResetTemplate) SampleData() map[string]any {
+	return map[string]any{
+		"name":       "Jane Doe",
+		"reset_url":  "https://example.com/reset-password?token=sample",
+		"expires_in": "30 minutes",
+	}
+}
+
+// DefaultContent returns the copy of locales/*.yaml, along the locale's fallback chain.
+func (t PasswordResetTemplate) DefaultContent(channel, locale string) notiftemplate.ChannelContent {
+	return notiftemplate.GlobalBundle.Content(t.Slug(), channel, locale)
+}
+
+func (t PasswordResetTemplate) HasContent(channel, locale string) bool {
+	return notiftemplate.GlobalBundle.Has(t.Slug(), channel, locale)
+}
+
+func init() {
+	notiftemplate.GlobalRegistry.Register(PasswordResetTemplate{})
+}
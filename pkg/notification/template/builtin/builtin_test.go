@@ -0,0 +1,46 @@
+package builtin
+
+import (
+	"strings"
+	"testing"
+	gotemplate "text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+func TestBuiltinTemplatesHaveFallbackCopy(t *testing.T) {
+	require.NoError(t, notiftemplate.GlobalRegistry.Validate())
+}
+
+func TestBuiltinTemplatesAreTranslated(t *testing.T) {
+	shipped, err := notiftemplate.GlobalRegistry.MustGet(OrderShippedTemplate{}.Slug())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Pesanan #{{.order_id}} sudah dikirim!", shipped.DefaultContent("push", "id-ID").Title)
+	assert.Equal(t, "Your order #{{.order_id}} is on its way!", shipped.DefaultContent("push", "fr-FR").Title)
+	assert.True(t, notiftemplate.HasContent(shipped, "email", "id"))
+	assert.False(t, notiftemplate.HasContent(shipped, "email", "id-ID"))
+}
+
+func TestOrderShippedDigestCopy(t *testing.T) {
+	policy, ok := notiftemplate.DigestPolicyOf(OrderShippedTemplate{})
+	require.True(t, ok)
+	digest, err := notiftemplate.GlobalRegistry.MustGet(policy.Template)
+	require.NoError(t, err)
+
+	render := func(text, locale string) string {
+		tmpl := gotemplate.Must(gotemplate.New("copy").Funcs(notiftemplate.FuncMap(locale)).Parse(text))
+		var sb strings.Builder
+		require.NoError(t, tmpl.Execute(&sb, digest.(notiftemplate.SampleDataProvider).SampleData()))
+		return sb.String()
+	}
+
+	push := digest.DefaultContent("push", "en")
+	assert.Equal(t, "3 orders on the way!", render(push.Title, "en"))
+	assert.Equal(t, "#ORD-10042, #ORD-10043 and 1 more shipped.", render(push.Body, "en"))
+	assert.Equal(t, "#ORD-10042, #ORD-10043 dan 1 lainnya sudah dikirim.",
+		render(digest.DefaultContent("push", "id-ID").Body, "id-ID"))
+}
@@ -0,0 +1,21 @@
+package builtin
+
+import (
+	"embed"
+	"io/fs"
+
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+// locales holds the default copy of the builtin templates, one file per locale.
+//
+//go:embed locales/*.yaml
+var locales embed.FS
+
+func init() {
+	sub, err := fs.Sub(locales, "locales")
+	if err != nil {
+		panic(err)
+	}
+	notiftemplate.GlobalBundle.MustLoad(sub)
+}
@@ -1,6 +1,8 @@
 package builtin
 
 import (
+	"time"
+
 	notiftemplate "ichi-go/pkg/notification/template"
 )
 
@@ -12,63 +14,57 @@ import (
 //   - name       string  — customer display name
 //   - eta        string  — estimated arrival date/time string
 //   - tracking_url string — carrier tracking link
+//
+// Optional data variables:
+//   - action_url string — opened when the in-app notification is clicked
+//
+// The webhook channel sends the data variables as they are; it has no copy in locales/*.yaml.
+//
+// Push and SMS are digested: the shipments of a user within two minutes are sent as
+// one "order.shipped.digest" notification (see OrderShippedDigestTemplate).
 type OrderShippedTemplate struct{}
 
 func (t OrderShippedTemplate) Slug() string { return "order.shipped" }
 
 func (t OrderShippedTemplate) SupportedChannels() []string {
-	return []string{"email", "push", "sms"}
+	return []string{"email", "push", "sms", "in_app", "webhook"}
 }
 
-func (t OrderShippedTemplate) DefaultContent(channel, locale string) notiftemplate.ChannelContent {
-	switch locale {
-	case "id": // Bahasa Indonesia
-		return t.indonesian(channel)
-	default: // "en" and all other locales fall back to English
-		return t.english(channel)
+func (t OrderShippedTemplate) Classification() notiftemplate.Classification {
+	return notiftemplate.Classification{
+		Category: "orders",
+		Kind:     notiftemplate.KindTransactional,
+		Urgent:   false,
 	}
 }
 
-func (t OrderShippedTemplate) english(channel string) notiftemplate.ChannelContent {
-	switch channel {
-	case "push":
-		return notiftemplate.ChannelContent{
-			Title: "Your order #{{.order_id}} is on its way!",
-			Body:  "Estimated arrival: {{.eta}}",
-		}
-	case "sms":
-		return notiftemplate.ChannelContent{
-			Title: "",
-			Body:  "Hi {{.name}}, your order #{{.order_id}} has shipped! Track it: {{.tracking_url}}",
-		}
-	default: // email
-		return notiftemplate.ChannelContent{
-			Title: "Order Shipped — #{{.order_id}}",
-			Body:  "Hi {{.name}}, your order has shipped. Track it here: {{.tracking_url}}. Estimated arrival: {{.eta}}.",
-		}
+func (t OrderShippedTemplate) SampleData() map[string]any {
+	return map[string]any{
+		"order_id":     "ORD-10042",
+		"name":         "Jane Doe",
+		"eta":          "Friday, 14 March",
+		"tracking_url": "https://example.com/track/ORD-10042",
+		"action_url":   "https://example.com/orders/ORD-10042",
 	}
 }
 
-func (t OrderShippedTemplate) indonesian(channel string) notiftemplate.ChannelContent {
-	switch channel {
-	case "push":
-		return notiftemplate.ChannelContent{
-			Title: "Pesanan #{{.order_id}} sudah dikirim!",
-			Body:  "Estimasi tiba: {{.eta}}",
-		}
-	case "sms":
-		return notiftemplate.ChannelContent{
-			Title: "",
-			Body:  "Hai {{.name}}, pesanan #{{.order_id}} sudah dikirim! Lacak: {{.tracking_url}}",
-		}
-	default: // email
-		return notiftemplate.ChannelContent{
-			Title: "Pesanan Dikirim — #{{.order_id}}",
-			Body:  "Hai {{.name}}, pesanan Anda sudah dikirim. Lacak di sini: {{.tracking_url}}. Estimasi tiba: {{.eta}}.",
-		}
+func (t OrderShippedTemplate) Digest() notiftemplate.DigestPolicy {
+	return notiftemplate.DigestPolicy{
+		Window:   2 * time.Minute,
+		Template: OrderShippedDigestTemplate{}.Slug(),
+		Channels: []string{"push", "sms"},
 	}
 }
 
+// DefaultContent returns the copy of locales/*.yaml, along the locale's fallback chain.
+func (t OrderShippedTemplate) DefaultContent(channel, locale string) notiftemplate.ChannelContent {
+	return notiftemplate.GlobalBundle.Content(t.Slug(), channel, locale)
+}
+
+func (t OrderShippedTemplate) HasContent(channel, locale string) bool {
+	return notiftemplate.GlobalBundle.Has(t.Slug(), channel, locale)
+}
+
 func init() {
 	notiftemplate.GlobalRegistry.Register(OrderShippedTemplate{})
 }
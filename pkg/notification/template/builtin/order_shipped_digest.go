@@ -0,0 +1,51 @@
+package builtin
+
+import (
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+// OrderShippedDigestTemplate is the built-in template of the "order.shipped.digest"
+// notification, which replaces the push and SMS notifications of several orders of a
+// user shipped in a short time (see OrderShippedTemplate.Digest).
+//
+// Data variables (see notiftemplate.DigestPolicy):
+//   - count   int              — number of orders shipped
+//   - events  []map[string]any — the data of the first "order.shipped" events, oldest first
+//   - more    int              — orders not listed in events
+type OrderShippedDigestTemplate struct{}
+
+func (t OrderShippedDigestTemplate) Slug() string { return "order.shipped.digest" }
+
+func (t OrderShippedDigestTemplate) SupportedChannels() []string {
+	return []string{"push", "sms"}
+}
+
+// Classification is the one of "order.shipped": the digest reaches the users who
+// would have received the orders one by one.
+func (t OrderShippedDigestTemplate) Classification() notiftemplate.Classification {
+	return OrderShippedTemplate{}.Classification()
+}
+
+func (t OrderShippedDigestTemplate) SampleData() map[string]any {
+	return map[string]any{
+		"count": 3,
+		"events": []map[string]any{
+			{"order_id": "ORD-10042", "name": "Jane Doe", "eta": "Friday, 14 March"},
+			{"order_id": "ORD-10043", "name": "Jane Doe", "eta": "Friday, 14 March"},
+		},
+		"more": 1,
+	}
+}
+
+// DefaultContent returns the copy of locales/*.yaml, along the locale's fallback chain.
+func (t OrderShippedDigestTemplate) DefaultContent(channel, locale string) notiftemplate.ChannelContent {
+	return notiftemplate.GlobalBundle.Content(t.Slug(), channel, locale)
+}
+
+func (t OrderShippedDigestTemplate) HasContent(channel, locale string) bool {
+	return notiftemplate.GlobalBundle.Has(t.Slug(), channel, locale)
+}
+
+func init() {
+	notiftemplate.GlobalRegistry.Register(OrderShippedDigestTemplate{})
+}
@@ -0,0 +1,49 @@
+package builtin
+
+import (
+	notiftemplate "ichi-go/pkg/notification/template"
+)
+
+// VerifyEmailTemplate is the built-in template for the "user.verify_email" event.
+// Sent after registration, and on request, to confirm the user owns the address.
+//
+// Required data variables:
+//   - name        string — user display name
+//   - verify_url  string — link carrying the signed verification token
+//   - expires_in  string — human readable validity, e.g. "24 hours"
+type VerifyEmailTemplate struct{}
+
+func (t VerifyEmailTemplate) Slug() string { return "user.verify_email" }
+
+func (t VerifyEmailTemplate) SupportedChannels() []string {
+	return []string{"email"}
+}
+
+func (t VerifyEmailTemplate) Classification() notiftemplate.Classification {
+	return notiftemplate.Classification{
+		Category: "account",
+		Kind:     notiftemplate.KindTransactional,
+		Urgent:   true,
+	}
+}
+
+func (t VerifyEmailTemplate) SampleData() map[string]any {
+	return map[string]any{
+		"name":       "Jane Doe",
+		"verify_url": "https://example.com/verify-email?token=sample",
+		"expires_in": "24 hours",
+	}
+}
+
+// DefaultContent returns the copy of locales/*.yaml, along the locale's fallback chain.
+func (t VerifyEmailTemplate) DefaultContent(channel, locale string) notiftemplate.ChannelContent {
+	return notiftemplate.GlobalBundle.Content(t.Slug(), channel, locale)
+}
+
+func (t VerifyEmailTemplate) HasContent(channel, locale string) bool {
+	return notiftemplate.GlobalBundle.Has(t.Slug(), channel, locale)
+}
+
+func init() {
+	notiftemplate.GlobalRegistry.Register(VerifyEmailTemplate{})
+}
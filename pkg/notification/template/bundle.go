@@ -0,0 +1,266 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	gotemplate "text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GlobalBundle holds the translations of the builtin templates, loaded from the
+// files embedded in the builtin package and, optionally, from
+// notification.templates.bundle_dir on disk (see Config).
+var GlobalBundle = NewBundle()
+
+// Bundle holds the default content of events per locale, and the formats the
+// template helper functions use in each locale (see FuncMap).
+//
+// A bundle is loaded from YAML files named after their locale, e.g. en.yaml, id.yaml:
+//
+//	formats:
+//	  date: "2 January 2006"
+//	  months: [Januari, Februari, ...]
+//	events:
+//	  order.shipped:
+//	    push:
+//	      title: "Pesanan #{{.order_id}} sudah dikirim!"
+//	      body: "Estimasi tiba: {{date .eta}}"
+//
+// Loading more files merges them in: an event channel or format set again replaces
+// the earlier one, so files on disk can retranslate or add locales to the
+// embedded ones without a rebuild.
+type Bundle struct {
+	mu      sync.RWMutex
+	locales map[string]*translation
+}
+
+// translation is the content of one bundle file.
+type translation struct {
+	Formats Formats                              `yaml:"formats"`
+	Events  map[string]map[string]ChannelContent `yaml:"events"` // slug → channel → content
+}
+
+// Formats are the conventions of a locale used by the date helper functions.
+// Numbers and currencies follow CLDR data and need no configuration.
+type Formats struct {
+	// Date is the Go time layout of {{date}} (default: "2 January 2006").
+	Date string `yaml:"date"`
+
+	// DateTime is the Go time layout of {{datetime}} (default: "2 January 2006 15:04").
+	DateTime string `yaml:"datetime"`
+
+	// Months are the 12 month names, January first, written for "January" in a
+	// layout; "Jan" is written as their first 3 letters. Empty keeps English.
+	Months []string `yaml:"months"`
+
+	// Weekdays are the 7 day names, Sunday first, written for "Monday" in a
+	// layout; "Mon" is written as their first 3 letters. Empty keeps English.
+	Weekdays []string `yaml:"weekdays"`
+}
+
+// NewBundle creates an empty Bundle.
+func NewBundle() *Bundle {
+	return &Bundle{locales: make(map[string]*translation)}
+}
+
+// LoadBundle creates a Bundle from the *.yaml and *.yml files at the root of fsys.
+func LoadBundle(fsys fs.FS) (*Bundle, error) {
+	b := NewBundle()
+	if err := b.Load(fsys); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Load merges the *.yaml and *.yml files at the root of fsys into b. Each file is
+// named after its locale. Nothing is merged when any file is invalid: unknown
+// keys, a locale that is not a BCP-47 tag, malformed formats, or content that
+// does not parse as a Go text/template.
+func (b *Bundle) Load(fsys fs.FS) error {
+	var names []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return fmt.Errorf("notification template: listing bundle files: %w", err)
+		}
+		names = append(names, matches...)
+	}
+	sort.Strings(names)
+
+	loaded := make(map[string][]*translation, len(names))
+	for _, name := range names {
+		locale, err := ParseLocale(strings.TrimSuffix(name, path.Ext(name)))
+		if err != nil {
+			return fmt.Errorf("notification template: bundle file %s: %w", name, err)
+		}
+		t, err := readTranslation(fsys, name)
+		if err != nil {
+			return fmt.Errorf("notification template: bundle file %s: %w", name, err)
+		}
+		loaded[locale] = append(loaded[locale], t)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for locale, ts := range loaded {
+		for _, t := range ts {
+			b.merge(locale, t)
+		}
+	}
+	return nil
+}
+
+// MustLoad is Load for files embedded in the binary: an invalid file is a
+// programming error, caught at startup.
+func (b *Bundle) MustLoad(fsys fs.FS) {
+	if err := b.Load(fsys); err != nil {
+		panic(err)
+	}
+}
+
+func readTranslation(fsys fs.FS, name string) (*translation, error) {
+	raw, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	t := new(translation)
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(t); err != nil {
+		return nil, err
+	}
+
+	if n := len(t.Formats.Months); n != 0 && n != 12 {
+		return nil, fmt.Errorf("formats.months has %d names, want 12", n)
+	}
+	if n := len(t.Formats.Weekdays); n != 0 && n != 7 {
+		return nil, fmt.Errorf("formats.weekdays has %d names, want 7", n)
+	}
+	funcs := FuncMap("")
+	for slug, channels := range t.Events {
+		for channel, content := range channels {
+			for field, text := range map[string]string{"title": content.Title, "body": content.Body} {
+				if _, err := gotemplate.New(field).Funcs(funcs).Parse(text); err != nil {
+					return nil, fmt.Errorf("events.%s.%s.%s: %w", slug, channel, field, err)
+				}
+			}
+		}
+	}
+	return t, nil
+}
+
+// merge adds t to the translation of locale. b.mu must be held.
+func (b *Bundle) merge(locale string, t *translation) {
+	own := b.locales[locale]
+	if own == nil {
+		own = &translation{Events: make(map[string]map[string]ChannelContent)}
+		b.locales[locale] = own
+	}
+
+	if t.Formats.Date != "" {
+		own.Formats.Date = t.Formats.Date
+	}
+	if t.Formats.DateTime != "" {
+		own.Formats.DateTime = t.Formats.DateTime
+	}
+	if len(t.Formats.Months) > 0 {
+		own.Formats.Months = t.Formats.Months
+	}
+	if len(t.Formats.Weekdays) > 0 {
+		own.Formats.Weekdays = t.Formats.Weekdays
+	}
+
+	for slug, channels := range t.Events {
+		if own.Events[slug] == nil {
+			own.Events[slug] = make(map[string]ChannelContent, len(channels))
+		}
+		for channel, content := range channels {
+			own.Events[slug][channel] = content
+		}
+	}
+}
+
+// Has reports whether the bundle holds content for (slug, channel) in exactly locale.
+func (b *Bundle) Has(slug, channel, locale string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	_, ok := b.lookup(slug, channel, locale)
+	return ok
+}
+
+// Content returns the content of (slug, channel) in the first locale of
+// LocaleChain(locale) that has it, or empty content when none does.
+func (b *Bundle) Content(slug, channel, locale string) ChannelContent {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, l := range LocaleChain(locale) {
+		if content, ok := b.lookup(slug, channel, l); ok {
+			return content
+		}
+	}
+	return ChannelContent{}
+}
+
+func (b *Bundle) lookup(slug, channel, locale string) (ChannelContent, bool) {
+	t := b.locales[locale]
+	if t == nil {
+		return ChannelContent{}, false
+	}
+	content, ok := t.Events[slug][channel]
+	return content, ok
+}
+
+// Formats returns the formats of locale, each taken from the first locale of
+// LocaleChain(locale) that sets it, with the defaults for layouts none sets.
+func (b *Bundle) Formats(locale string) Formats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var f Formats
+	for _, l := range LocaleChain(locale) {
+		t := b.locales[l]
+		if t == nil {
+			continue
+		}
+		if f.Date == "" {
+			f.Date = t.Formats.Date
+		}
+		if f.DateTime == "" {
+			f.DateTime = t.Formats.DateTime
+		}
+		if f.Months == nil {
+			f.Months = t.Formats.Months
+		}
+		if f.Weekdays == nil {
+			f.Weekdays = t.Formats.Weekdays
+		}
+	}
+	if f.Date == "" {
+		f.Date = "2 January 2006"
+	}
+	if f.DateTime == "" {
+		f.DateTime = "2 January 2006 15:04"
+	}
+	return f
+}
+
+// Locales returns the locales of the bundle, sorted.
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	locales := make([]string, 0, len(b.locales))
+	for l := range b.locales {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
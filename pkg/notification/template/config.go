@@ -0,0 +1,31 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds notification template configuration (the `notification.templates:` YAML block).
+type Config struct {
+	// BundleDir is a directory of translation bundle files (en.yaml, id.yaml, ...)
+	// merged over the bundles embedded in the builtin package at startup, to
+	// retranslate events or add locales without a rebuild. Empty uses the embedded
+	// bundles only.
+	BundleDir string `mapstructure:"bundle_dir"`
+}
+
+// SetDefault registers Viper defaults for the templates config block.
+// Called from config.setDefault() during application startup.
+func SetDefault() {
+	viper.SetDefault("notification.templates.bundle_dir", "")
+}
+
+// LoadConfig reads the `notification.templates:` block from Viper.
+func LoadConfig() (Config, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("notification.templates", &cfg); err != nil {
+		return Config{}, fmt.Errorf("templates: invalid config: %w", err)
+	}
+	return cfg, nil
+}
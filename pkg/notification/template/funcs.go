@@ -0,0 +1,188 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	gotemplate "text/template"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// FuncMap returns the helper functions of templates rendered in locale, with the
+// formats of GlobalBundle. See Bundle.FuncMap.
+func FuncMap(locale string) gotemplate.FuncMap {
+	return GlobalBundle.FuncMap(locale)
+}
+
+// FuncMap returns the helper functions of templates rendered in locale:
+//
+//	{{number .total}}              1,234.5 (en) / 1.234,5 (id)
+//	{{currency .amount "IDR"}}     IDR 150,000 (en) / Rp 150.000 (id)
+//	{{date .shipped_at}}           Formats.Date, with the locale's month and day names
+//	{{datetime .shipped_at}}       Formats.DateTime
+//	{{plural .count "item" "items"}}  the CLDR "one" form, or the other one
+//
+// Event data arrives as JSON: numbers may be numbers or numeric strings, times
+// time.Time values, RFC 3339 strings or "2006-01-02" dates. A value that is none
+// of these is written as it is, so bad data never fails a send.
+func (b *Bundle) FuncMap(locale string) gotemplate.FuncMap {
+	tag := language.Make(locale)
+	if locale == "" {
+		tag = language.Make(FallbackLocale)
+	}
+	printer := message.NewPrinter(tag)
+	formats := b.Formats(locale)
+
+	return gotemplate.FuncMap{
+		"number": func(v any) string {
+			n, ok := toFloat(v)
+			if !ok {
+				return fmt.Sprint(v)
+			}
+			return printer.Sprint(number.Decimal(n))
+		},
+		"currency": func(v any, code string) string {
+			n, ok := toFloat(v)
+			if !ok {
+				return fmt.Sprint(v)
+			}
+			unit, err := currency.ParseISO(code)
+			if err != nil {
+				return code + " " + printer.Sprint(number.Decimal(n))
+			}
+			return printer.Sprint(currency.Symbol(unit.Amount(n)))
+		},
+		"date": func(v any) string {
+			t, ok := toTime(v)
+			if !ok {
+				return fmt.Sprint(v)
+			}
+			return formats.format(t, formats.Date)
+		},
+		"datetime": func(v any) string {
+			t, ok := toTime(v)
+			if !ok {
+				return fmt.Sprint(v)
+			}
+			return formats.format(t, formats.DateTime)
+		},
+		"plural": func(v any, one, other string) string {
+			n, ok := toFloat(v)
+			if !ok || n != math.Trunc(n) || math.Abs(n) > math.MaxInt32 {
+				return other
+			}
+			i := int(math.Abs(n))
+			if plural.Cardinal.MatchPlural(tag, i, 0, 0, 0, 0) == plural.One {
+				return one
+			}
+			return other
+		},
+	}
+}
+
+// format formats t with layout, writing the month and day names of f.
+func (f Formats) format(t time.Time, layout string) string {
+	var sb strings.Builder
+	for layout != "" {
+		i, token := nextNameToken(layout)
+		if i < 0 {
+			sb.WriteString(t.Format(layout))
+			break
+		}
+		sb.WriteString(t.Format(layout[:i]))
+		sb.WriteString(f.name(t, token))
+		layout = layout[i+len(token):]
+	}
+	return sb.String()
+}
+
+// nameTokens are the layout elements of month and day names, longest first.
+var nameTokens = []string{"January", "Monday", "Jan", "Mon"}
+
+// nextNameToken returns the index and value of the first month or day name element in layout.
+func nextNameToken(layout string) (int, string) {
+	index, token := -1, ""
+	for _, tok := range nameTokens {
+		if i := strings.Index(layout, tok); i >= 0 && (index < 0 || i < index) {
+			index, token = i, tok
+		}
+	}
+	return index, token
+}
+
+func (f Formats) name(t time.Time, token string) string {
+	var name string
+	switch token {
+	case "January", "Jan":
+		if len(f.Months) == 12 {
+			name = f.Months[t.Month()-1]
+		}
+	case "Monday", "Mon":
+		if len(f.Weekdays) == 7 {
+			name = f.Weekdays[t.Weekday()]
+		}
+	}
+	if name == "" {
+		return t.Format(token)
+	}
+	if len(token) == 3 {
+		if runes := []rune(name); len(runes) > 3 {
+			name = string(runes[:3])
+		}
+	}
+	return name
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,58 @@
+package template
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// FallbackLocale is the last locale of every fallback chain. Every channel an event
+// supports must have default content in it (see Registry.Validate).
+const FallbackLocale = "en"
+
+// ParseLocale returns the canonical form of a BCP-47 language tag: "id_ID" and
+// "ID-id" become "id-ID", deprecated codes such as "in" become "id".
+// Unknown languages are rejected.
+func ParseLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", fmt.Errorf("notification template: invalid locale %q: %w", locale, err)
+	}
+	return tag.String(), nil
+}
+
+// LocaleChain returns the locales content is looked up in for locale, most specific
+// first and ending with FallbackLocale: "id-ID" → [id-ID id en],
+// "zh-Hant-TW" → [zh-Hant-TW zh-Hant zh en]. Variants and extensions are dropped.
+// An empty or invalid locale returns [en].
+func LocaleChain(locale string) []string {
+	chain := make([]string, 0, 4)
+	add := func(l string) {
+		for _, c := range chain {
+			if c == l {
+				return
+			}
+		}
+		chain = append(chain, l)
+	}
+
+	if tag, err := language.Parse(locale); err == nil && locale != "" {
+		base, script, region := tag.Raw()
+		hasScript := script != (language.Script{})
+		hasRegion := region != (language.Region{})
+		switch {
+		case hasScript && hasRegion:
+			add(base.String() + "-" + script.String() + "-" + region.String())
+			add(base.String() + "-" + script.String())
+		case hasScript:
+			add(base.String() + "-" + script.String())
+		case hasRegion:
+			add(base.String() + "-" + region.String())
+		}
+		if base != (language.Base{}) {
+			add(base.String())
+		}
+	}
+	add(FallbackLocale)
+	return chain
+}
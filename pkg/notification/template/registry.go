@@ -1,10 +1,15 @@
 package template
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 )
 
+// dataOnlyChannels send the event data as it is; they need no copy.
+var dataOnlyChannels = map[string]bool{"webhook": true}
+
 // Registry holds all registered Go-defined notification event templates.
 // Templates are registered at startup via init() functions in each template file.
 type Registry struct {
@@ -74,3 +79,72 @@ func (r *Registry) Slugs() []string {
 	}
 	return slugs
 }
+
+// Validate checks that every channel of every registered template, except the
+// channels that send the data as it is (webhook), has default copy in
+// FallbackLocale, so that rendering never runs out of locales, and that digest
+// policies name a registered digest template supporting their channels. It
+// reports every problem at once. Called at startup, after the bundles are loaded.
+func (r *Registry) Validate() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	slugs := make([]string, 0, len(r.templates))
+	for slug := range r.templates {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	var errs []error
+	for _, slug := range slugs {
+		t := r.templates[slug]
+		for _, channel := range t.SupportedChannels() {
+			if dataOnlyChannels[channel] {
+				continue
+			}
+			content := t.DefaultContent(channel, FallbackLocale)
+			if !HasContent(t, channel, FallbackLocale) || (content.Title == "" && content.Body == "") {
+				errs = append(errs, fmt.Errorf("notification template: event %q has no %s copy in fallback locale %q", slug, channel, FallbackLocale))
+			}
+		}
+		if policy, ok := DigestPolicyOf(t); ok {
+			errs = append(errs, r.validateDigest(slug, t, policy)...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateDigest checks the digest policy of the template t. r.mu must be held.
+func (r *Registry) validateDigest(slug string, t EventTemplate, policy DigestPolicy) []error {
+	var errs []error
+	if policy.Window <= 0 {
+		errs = append(errs, fmt.Errorf("notification template: event %q has a digest window of %s", slug, policy.Window))
+	}
+	if len(policy.Channels) == 0 {
+		errs = append(errs, fmt.Errorf("notification template: event %q digests no channel", slug))
+	}
+	digest, ok := r.templates[policy.Template]
+	if !ok {
+		return append(errs, fmt.Errorf("notification template: event %q is digested with unregistered template %q", slug, policy.Template))
+	}
+	for _, channel := range policy.Channels {
+		switch {
+		case dataOnlyChannels[channel]:
+			errs = append(errs, fmt.Errorf("notification template: event %q cannot digest %s: it sends the data as it is", slug, channel))
+		case !supportsChannel(t, channel):
+			errs = append(errs, fmt.Errorf("notification template: event %q digests %s, which it does not support", slug, channel))
+		case !supportsChannel(digest, channel):
+			errs = append(errs, fmt.Errorf("notification template: digest template %q of event %q does not support %s", policy.Template, slug, channel))
+		}
+	}
+	return errs
+}
+
+func supportsChannel(t EventTemplate, channel string) bool {
+	for _, c := range t.SupportedChannels() {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
@@ -1,18 +1,27 @@
 package template
 
+import "time"
+
 // EventTemplate is the Go-code contract every notification event must implement.
 //
 // Adding a new notification event:
 //  1. Create a new file in pkg/notification/template/builtin/, e.g. order_shipped.go
-//  2. Implement this interface
-//  3. Register via GlobalRegistry.Register() in an init() function
+//  2. Implement this interface, reading the copy from GlobalBundle (TranslatedTemplate)
+//  3. Add the copy of every supported channel to builtin/locales/en.yaml, and its
+//     translations to the other locale files
+//  4. Register via GlobalRegistry.Register() in an init() function
+//  5. Optionally implement SampleDataProvider for previews in the template override API
+//  6. Optionally implement Digester to batch the events of busy users into digests,
+//     registering the digest template like any other
 //
 // The template defines:
 //   - Which channels this event supports
 //   - Default title + body copy per channel and locale
+//   - How user preferences apply to it (Classification)
 //
 // The DB table notification_template_overrides can override the copy at runtime
 // without a redeploy. If no DB override exists, DefaultContent() is used.
+// Both are looked up along LocaleChain: "id-ID", then "id", then "en".
 type EventTemplate interface {
 	// Slug returns the unique event identifier used in API calls and DB lookups.
 	// Use dot-separated notation: "order.shipped", "user.welcome", "system.maintenance"
@@ -28,10 +37,123 @@ type EventTemplate interface {
 	//
 	// Templates use Go text/template syntax: "Hello {{.name}}, your order {{.order_id}} shipped!"
 	// Use option("missingkey=zero") — missing keys render as empty string, not panic.
+	// The helper functions of FuncMap are available: {{currency .total "IDR"}}.
 	//
 	// channel: matches dto.Channel constants
 	// locale:  BCP-47 tag, e.g. "en", "id"
 	DefaultContent(channel, locale string) ChannelContent
+
+	// Classification returns the preference category of the event and whether it is
+	// transactional or marketing, which decides how opt-outs and quiet hours apply.
+	Classification() Classification
+}
+
+// TranslatedTemplate is implemented by templates that tell which locales have their
+// own copy, typically by reading it from a Bundle. The renderer then prefers a DB
+// override of a more specific locale to the default copy of a less specific one:
+// for "id-ID" an "id" override wins over the "en" default copy.
+//
+// A template that does not implement it is assumed to translate every locale
+// itself in DefaultContent.
+type TranslatedTemplate interface {
+	EventTemplate
+
+	// HasContent reports whether DefaultContent has copy for channel in exactly
+	// locale, rather than falling back to a less specific locale.
+	HasContent(channel, locale string) bool
+}
+
+// HasContent reports whether t has default copy for channel in exactly locale.
+// Templates that do not implement TranslatedTemplate have copy in every locale.
+func HasContent(t EventTemplate, channel, locale string) bool {
+	if tt, ok := t.(TranslatedTemplate); ok {
+		return tt.HasContent(channel, locale)
+	}
+	return true
+}
+
+// Digester is implemented by templates whose events are delivered in digests:
+// the events of one user within DigestPolicy.Window of the first are buffered and
+// sent as one notification, rendered with the DigestPolicy.Template event.
+type Digester interface {
+	Digest() DigestPolicy
+}
+
+// DigestPolicy tells dispatch how to batch the events of a template.
+type DigestPolicy struct {
+	// Window is how long the events following the first one of a user are collected
+	// before the digest is sent. Config can change it per event.
+	Window time.Duration
+
+	// Template is the slug of the registered template the digest is rendered with.
+	// It receives the data variables:
+	//   - count   int              — number of events in the digest
+	//   - events  []map[string]any — the data of the first events, oldest first
+	//   - more    int              — events not in events (count - len(events))
+	// A window holding a single event is sent with the event's own template instead.
+	Template string
+
+	// Channels are the channels digested, e.g. push and sms; the event's other
+	// channels are sent per event. They must be supported by both templates.
+	Channels []string
+}
+
+// DigestPolicyOf returns the digest policy of t, and false when t is not digested.
+func DigestPolicyOf(t EventTemplate) (DigestPolicy, bool) {
+	d, ok := t.(Digester)
+	if !ok {
+		return DigestPolicy{}, false
+	}
+	return d.Digest(), true
+}
+
+// SampleDataProvider is implemented by templates that provide example data
+// variables. The template override API renders previews with them and executes
+// new copy against them to reject templates that would fail at send time.
+type SampleDataProvider interface {
+	SampleData() map[string]any
+}
+
+// SampleData returns a copy of the example data variables of t, or an empty map
+// when t does not implement SampleDataProvider.
+func SampleData(t EventTemplate) map[string]any {
+	data := make(map[string]any)
+	if p, ok := t.(SampleDataProvider); ok {
+		for k, v := range p.SampleData() {
+			data[k] = v
+		}
+	}
+	return data
+}
+
+// Kind separates the notifications a user must get from the ones they agreed to receive.
+type Kind string
+
+const (
+	// KindTransactional is a notification about the user's own account or activity:
+	// order updates, security alerts, one-time codes. A global unsubscribe does not stop it.
+	KindTransactional Kind = "transactional"
+
+	// KindMarketing is a promotional notification: newsletters, offers, product news.
+	// A global unsubscribe stops it.
+	KindMarketing Kind = "marketing"
+)
+
+// Classification tells dispatch how a user's notification preferences apply to an event.
+//
+//   - Opt-outs are per (Category, channel) and apply to both kinds.
+//   - A global unsubscribe skips KindMarketing events only.
+//   - Quiet hours defer events that are not Urgent.
+//   - Urgent transactional events (one-time codes, password resets) are what the
+//     user is waiting for: they ignore opt-outs and quiet hours.
+type Classification struct {
+	// Category groups events in the user's preferences, e.g. "orders", "account", "promotions".
+	Category string
+
+	Kind Kind
+
+	// Urgent events are sent during quiet hours instead of waiting for them to end.
+	Urgent bool
 }
 
 // ChannelContent holds the rendered title and body template strings for a
@@ -41,11 +163,11 @@ type ChannelContent struct {
 	// For email: the email subject line.
 	// For push: the push notification title.
 	// For SMS: not used (body only).
-	Title string
+	Title string `yaml:"title"`
 
 	// Body is the notification body Go text/template string.
 	// For email: plain text body (HTML templates belong in a separate email template file).
 	// For push: the notification body text.
 	// For SMS: the full message text.
-	Body string
+	Body string `yaml:"body"`
 }
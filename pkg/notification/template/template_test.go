@@ -0,0 +1,221 @@
+package template
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+	gotemplate "text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocaleChain(t *testing.T) {
+	tests := map[string][]string{
+		"id-ID":          {"id-ID", "id", "en"},
+		"id_ID":          {"id-ID", "id", "en"},
+		"in":             {"id", "en"},
+		"zh-Hant-TW":     {"zh-Hant-TW", "zh-Hant", "zh", "en"},
+		"en-GB-oxendict": {"en-GB", "en"},
+		"EN-us":          {"en-US", "en"},
+		"en":             {"en"},
+		"":               {"en"},
+		"not a locale":   {"en"},
+	}
+	for locale, want := range tests {
+		assert.Equal(t, want, LocaleChain(locale), locale)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	locale, err := ParseLocale("pt_br")
+	require.NoError(t, err)
+	assert.Equal(t, "pt-BR", locale)
+
+	for _, invalid := range []string{"", "English", "xx-YY"} {
+		_, err := ParseLocale(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func testBundle(t *testing.T) *Bundle {
+	t.Helper()
+	b, err := LoadBundle(fstest.MapFS{
+		"en.yaml": {Data: []byte(`
+events:
+  order.shipped:
+    push: {title: "Shipped #{{.order_id}}", body: "Arrives {{date .eta}}"}
+    sms: {body: "Shipped"}
+`)},
+		"id.yml": {Data: []byte(`
+formats:
+  months: [Januari, Februari, Maret, April, Mei, Juni, Juli, Agustus, September, Oktober, November, Desember]
+  weekdays: [Minggu, Senin, Selasa, Rabu, Kamis, Jumat, Sabtu]
+events:
+  order.shipped:
+    push: {title: "Dikirim #{{.order_id}}", body: "Tiba {{date .eta}}"}
+`)},
+		"README.md": {Data: []byte("not a bundle")},
+	})
+	require.NoError(t, err)
+	return b
+}
+
+func TestBundle_ContentFallsBackAlongChain(t *testing.T) {
+	b := testBundle(t)
+
+	assert.Equal(t, []string{"en", "id"}, b.Locales())
+	assert.Equal(t, "Dikirim #{{.order_id}}", b.Content("order.shipped", "push", "id-ID").Title)
+	assert.Equal(t, "Shipped", b.Content("order.shipped", "sms", "id-ID").Body, "sms has no id copy")
+	assert.Equal(t, "Shipped #{{.order_id}}", b.Content("order.shipped", "push", "fr").Title)
+	assert.Empty(t, b.Content("order.lost", "push", "en"))
+
+	assert.True(t, b.Has("order.shipped", "push", "id"))
+	assert.False(t, b.Has("order.shipped", "push", "id-ID"), "Has does not fall back")
+	assert.False(t, b.Has("order.shipped", "sms", "id"))
+}
+
+func TestBundle_LoadMergesOverEarlierFiles(t *testing.T) {
+	b := testBundle(t)
+
+	require.NoError(t, b.Load(fstest.MapFS{
+		"id.yaml": {Data: []byte(`
+events:
+  order.shipped:
+    sms: {body: "Dikirim"}
+`)},
+	}))
+
+	assert.Equal(t, "Dikirim", b.Content("order.shipped", "sms", "id").Body)
+	assert.Equal(t, "Dikirim #{{.order_id}}", b.Content("order.shipped", "push", "id").Title, "other channels are kept")
+	assert.Len(t, b.Formats("id").Months, 12, "formats are kept")
+}
+
+func TestBundle_LoadRejectsInvalidFiles(t *testing.T) {
+	tests := map[string]string{
+		"english.yaml": "events: {}",
+		"en.yaml":      "event: {}",
+		"id.yaml":      "formats: {months: [Januari]}",
+		"de.yaml":      `events: {order.shipped: {push: {title: "{{.order_id"}}}`,
+	}
+	for name, data := range tests {
+		b := NewBundle()
+		err := b.Load(fstest.MapFS{name: {Data: []byte(data)}})
+		assert.Error(t, err, name)
+		assert.Empty(t, b.Locales(), "%s: nothing is merged", name)
+	}
+}
+
+func render(t *testing.T, b *Bundle, locale, text string, data map[string]any) string {
+	t.Helper()
+	tmpl, err := gotemplate.New("t").Funcs(b.FuncMap(locale)).Parse(text)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, data))
+	return buf.String()
+}
+
+func TestFuncMap_FormatsPerLocale(t *testing.T) {
+	b := testBundle(t)
+	data := map[string]any{
+		"total":   1234567.5,
+		"amount":  "150000",
+		"eta":     "2026-03-02T09:05:00+07:00",
+		"one":     1,
+		"several": 3,
+		"raw":     "soon",
+	}
+	text := `{{number .total}}|{{currency .amount "IDR"}}|{{date .eta}}|{{datetime .eta}}|` +
+		`{{plural .one "item" "items"}}|{{plural .several "item" "items"}}|{{date .raw}}`
+
+	assert.Equal(t, "1,234,567.5|IDR 150,000|2 March 2026|2 March 2026 09:05|item|items|soon",
+		render(t, b, "en", text, data))
+	// Indonesian has no plural "one" form.
+	assert.Equal(t, "1.234.567,5|Rp 150.000|2 Maret 2026|2 Maret 2026 09:05|items|items|soon",
+		render(t, b, "id-ID", text, data))
+}
+
+func TestFormats_WritesLocalNames(t *testing.T) {
+	f := testBundle(t).Formats("id")
+	day := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "Senin, 5 Januari 2026 (Sen, Jan)", f.format(day, "Monday, 2 January 2006 (Mon, Jan)"))
+	assert.Equal(t, "2 January 2006", f.Date, "layouts default when no locale of the chain sets them")
+}
+
+type bundledTemplate struct {
+	bundle   *Bundle
+	channels []string
+}
+
+func (t bundledTemplate) Slug() string                   { return "order.shipped" }
+func (t bundledTemplate) SupportedChannels() []string    { return t.channels }
+func (t bundledTemplate) Classification() Classification { return Classification{} }
+func (t bundledTemplate) DefaultContent(channel, locale string) ChannelContent {
+	return t.bundle.Content(t.Slug(), channel, locale)
+}
+func (t bundledTemplate) HasContent(channel, locale string) bool {
+	return t.bundle.Has(t.Slug(), channel, locale)
+}
+
+func TestRegistry_ValidateRequiresFallbackLocale(t *testing.T) {
+	b := testBundle(t)
+
+	r := NewRegistry()
+	r.Register(bundledTemplate{bundle: b, channels: []string{"push", "sms", "webhook"}})
+	assert.NoError(t, r.Validate(), "webhook needs no copy")
+
+	r = NewRegistry()
+	r.Register(bundledTemplate{bundle: b, channels: []string{"push", "email", "in_app"}})
+	err := r.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `event "order.shipped" has no email copy in fallback locale "en"`)
+	assert.Equal(t, 2, strings.Count(err.Error(), "no "), "every missing channel is reported")
+}
+
+type plainTemplate struct {
+	slug     string
+	channels []string
+}
+
+func (t plainTemplate) Slug() string                   { return t.slug }
+func (t plainTemplate) SupportedChannels() []string    { return t.channels }
+func (t plainTemplate) Classification() Classification { return Classification{} }
+func (t plainTemplate) DefaultContent(string, string) ChannelContent {
+	return ChannelContent{Title: "title", Body: "body"}
+}
+
+type digestedTemplate struct {
+	plainTemplate
+	policy *DigestPolicy
+}
+
+func (t digestedTemplate) Digest() DigestPolicy { return *t.policy }
+
+func TestRegistry_ValidateChecksDigestPolicies(t *testing.T) {
+	digest := plainTemplate{slug: "order.shipped.digest", channels: []string{"push"}}
+	policy := &DigestPolicy{Window: time.Minute, Template: digest.slug, Channels: []string{"push"}}
+
+	r := NewRegistry()
+	r.Register(digest)
+	r.Register(digestedTemplate{plainTemplate{slug: "order.shipped", channels: []string{"push", "sms", "webhook"}}, policy})
+	require.NoError(t, r.Validate())
+
+	policy.Window = 0
+	policy.Channels = []string{"push", "sms", "webhook", "email"}
+	err := r.Validate()
+	require.Error(t, err)
+	for _, want := range []string{
+		`event "order.shipped" has a digest window of 0s`,
+		`digest template "order.shipped.digest" of event "order.shipped" does not support sms`,
+		`event "order.shipped" cannot digest webhook`,
+		`event "order.shipped" digests email, which it does not support`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+
+	policy.Window, policy.Template = time.Minute, "order.missing"
+	assert.ErrorContains(t, r.Validate(), `digested with unregistered template "order.missing"`)
+}
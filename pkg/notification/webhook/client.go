@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned for endpoints on loopback, private or link-local
+// addresses while Config.AllowPrivateNetworks is off.
+var ErrPrivateAddress = errors.New("webhook: endpoint resolves to a private address")
+
+// Request is one delivery attempt to an endpoint.
+type Request struct {
+	URL       string
+	Secret    string
+	EventID   string
+	EventType string
+	Attempt   int
+	Body      []byte // JSON payload, sent as is and signed
+}
+
+// Result is the snapshot of a delivery attempt kept in the delivery log.
+type Result struct {
+	RequestHeaders  map[string]string
+	StatusCode      int // 0 when no response was received
+	ResponseHeaders map[string]string
+	ResponseBody    string // truncated to Config.MaxResponseBody
+	Duration        time.Duration
+}
+
+// Succeeded reports whether the endpoint acknowledged the delivery with a 2xx status.
+func (r *Result) Succeeded() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Client signs and sends webhook deliveries. Redirects are not followed and the
+// environment's HTTP proxy is ignored, so a delivery only ever reaches the
+// registered URL. Safe for concurrent use.
+type Client struct {
+	http *http.Client
+	cfg  Config
+	now  func() time.Time
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.MaxResponseBody <= 0 {
+		cfg.MaxResponseBody = 4096
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = "ichi-go-webhooks/1.0"
+	}
+
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateNetworks {
+		// Checked on the resolved address at connect time, so a public hostname
+		// pointing at an internal address is refused too.
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   cfg.Timeout,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConnsPerHost:   4,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return &Client{
+		http: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Deliver POSTs req.Body to req.URL. The returned Result is never nil; the error
+// is set when no response was received (DNS, connection, timeout), in which case
+// Result.StatusCode is 0. A non-2xx response is not an error: check Result.Succeeded.
+func (c *Client) Deliver(ctx context.Context, req Request) (*Result, error) {
+	now := c.now()
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		"User-Agent":    c.cfg.UserAgent,
+		HeaderID:        req.EventID,
+		HeaderEvent:     req.EventType,
+		HeaderAttempt:   strconv.Itoa(req.Attempt),
+		HeaderTimestamp: strconv.FormatInt(now.Unix(), 10),
+		HeaderSignature: Sign(req.Secret, now, req.Body),
+	}
+	result := &Result{RequestHeaders: headers}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return result, err
+	}
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		result.Duration = time.Since(start)
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, int64(c.cfg.MaxResponseBody)))
+	// Drain a little more so the connection can be reused; give up on huge bodies.
+	_, _ = io.CopyN(io.Discard, resp.Body, 64<<10)
+
+	result.Duration = time.Since(start)
+	result.StatusCode = resp.StatusCode
+	result.ResponseHeaders = flattenHeaders(resp.Header)
+	result.ResponseBody = string(body)
+	return result, nil
+}
+
+// ValidateURL checks that raw can be registered as an endpoint: an absolute http
+// or https URL without credentials. Unless allowPrivate is set, hosts that are
+// obviously internal (localhost, private IP literals) are refused up front;
+// hostnames resolving to internal addresses are refused when delivering.
+func ValidateURL(raw string, allowPrivate bool) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("URL must use http or https")
+	}
+	if u.Hostname() == "" {
+		return errors.New("URL must have a host")
+	}
+	if u.User != nil {
+		return errors.New("URL must not contain credentials")
+	}
+	if allowPrivate {
+		return nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+// flattenHeaders joins multi-valued headers with ", " for the delivery log.
+func flattenHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		out[k] = strings.Join(v, ", ")
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds outbound webhook configuration (the `notification.webhook:` YAML block).
+type Config struct {
+	// Enabled controls whether webhook deliveries are made.
+	// When false the webhook channel logs and skips every event.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Timeout bounds each delivery request, including reading the response (default: 10s).
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// MaxAttempts is the number of times a delivery is tried before it is given up (default: 6).
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// BackoffBase is the wait before the first retry; each later retry waits twice
+	// as long as the one before (default: 30s).
+	BackoffBase time.Duration `mapstructure:"backoff_base"`
+	// BackoffMax caps the wait between two attempts (default: 1h).
+	BackoffMax time.Duration `mapstructure:"backoff_max"`
+
+	// DisableAfter disables an endpoint after this many failed attempts in a row,
+	// across all of its deliveries (default: 15). A success resets the count.
+	DisableAfter int `mapstructure:"disable_after"`
+
+	// MaxResponseBody is the number of response body bytes kept in the delivery log (default: 4096).
+	MaxResponseBody int `mapstructure:"max_response_body"`
+
+	// AllowPrivateNetworks permits endpoints on loopback, private and link-local
+	// addresses. Keep it off in production: tenants choose the URLs.
+	AllowPrivateNetworks bool `mapstructure:"allow_private_networks"`
+
+	// UserAgent is sent with every delivery (default: ichi-go-webhooks/1.0).
+	UserAgent string `mapstructure:"user_agent"`
+}
+
+// SetDefault registers Viper defaults for the webhook config block.
+// Called from config.setDefault() during application startup.
+func SetDefault() {
+	viper.SetDefault("notification.webhook.enabled", false)
+	viper.SetDefault("notification.webhook.timeout", "10s")
+	viper.SetDefault("notification.webhook.max_attempts", 6)
+	viper.SetDefault("notification.webhook.backoff_base", "30s")
+	viper.SetDefault("notification.webhook.backoff_max", "1h")
+	viper.SetDefault("notification.webhook.disable_after", 15)
+	viper.SetDefault("notification.webhook.max_response_body", 4096)
+	viper.SetDefault("notification.webhook.user_agent", "ichi-go-webhooks/1.0")
+}
+
+// LoadConfig reads the `notification.webhook:` block from Viper.
+func LoadConfig() (Config, error) {
+	var cfg Config
+	if err := viper.UnmarshalKey("notification.webhook", &cfg); err != nil {
+		return Config{}, fmt.Errorf("webhook: invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Backoff returns the wait before the attempt following attempt (1-based):
+// BackoffBase, then doubling up to BackoffMax.
+func (c Config) Backoff(attempt int) time.Duration {
+	base := c.BackoffBase
+	if base <= 0 {
+		base = 30 * time.Second
+	}
+	limit := c.BackoffMax
+	if limit <= 0 {
+		limit = time.Hour
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		delay = limit
+	}
+	return delay
+}
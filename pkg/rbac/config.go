@@ -27,6 +27,12 @@ type Config struct {
 
 	// Features toggles for optional functionality
 	Features FeaturesConfig `mapstructure:"features"`
+
+	// MFA policy forcing multi-factor authentication for privileged users
+	MFA MFAPolicyConfig `mapstructure:"mfa"`
+
+	// Email verification policy reserving permissions for verified users
+	EmailVerification EmailVerificationPolicyConfig `mapstructure:"email_verification"`
 }
 
 // PerformanceConfig configures RBAC performance optimizations
@@ -102,6 +108,20 @@ type FeaturesConfig struct {
 	ResourceLevelABAC bool `mapstructure:"resource_level_abac"`
 }
 
+// MFAPolicyConfig selects who must use multi-factor authentication
+type MFAPolicyConfig struct {
+	// RequiredFor lists platform permissions (e.g. "platform.admin") and role slugs
+	// (e.g. "tenant-admin"); users holding any of them must sign in with MFA
+	RequiredFor []string `mapstructure:"required_for"`
+}
+
+// EmailVerificationPolicyConfig selects permissions that need a verified email address
+type EmailVerificationPolicyConfig struct {
+	// RequiredFor lists permissions as "resource:action" (e.g. "users:create") or
+	// "resource:*"; users who have not verified their email are denied them
+	RequiredFor []string `mapstructure:"required_for"`
+}
+
 // SetDefault sets default RBAC configuration values
 func SetDefault() {
 	// Mode defaults
@@ -135,6 +155,12 @@ func SetDefault() {
 	viper.SetDefault("rbac.features.impersonation", false)
 	viper.SetDefault("rbac.features.approval_workflows", false)
 	viper.SetDefault("rbac.features.resource_level_abac", false)
+
+	// MFA policy defaults
+	viper.SetDefault("rbac.mfa.required_for", []string{"platform.admin"})
+
+	// Email verification policy defaults
+	viper.SetDefault("rbac.email_verification.required_for", []string{})
 }
 
 // Validate validates the RBAC configuration
@@ -0,0 +1,16 @@
+package rbac
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// HashEmail returns the pseudonym stored in place of an email address when
+// AuditConfig.AnonymizePII is on: the hex SHA-256 of the trimmed, lower-cased address.
+// The same address always maps to the same hash, so records stay linkable without
+// revealing it.
+func HashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
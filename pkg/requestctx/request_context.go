@@ -36,6 +36,10 @@ type RequestContext struct {
 	// RBAC - Multi-tenant context
 	TenantID string `json:"tenant_id,omitempty"`
 
+	// API key client, set when the request carried a database-backed key
+	APIKeyID string   `json:"api_key_id,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+
 	// Meta
 	RequestID     string            `json:"request_id,omitempty"`
 	CorrelationID string            `json:"correlation_id,omitempty"`
@@ -154,6 +158,12 @@ func GetTenantID(ctx context.Context) string {
 	return rc.TenantID
 }
 
+// IsAPIKeyRequest reports whether the request was made with a database-backed API key,
+// whose Scopes then limit what it may do
+func IsAPIKeyRequest(ctx context.Context) bool {
+	return FromContext(ctx).APIKeyID != ""
+}
+
 // SetTenantID sets the tenant ID in the request context
 func SetTenantID(ctx context.Context, tenantID string) context.Context {
 	rc := FromContext(ctx)
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config mirrors the `storage:` YAML block.
+type Config struct {
+	// Driver selects the disk: "local", "s3" or "memory" (tests only, files are lost on restart)
+	Driver string `mapstructure:"driver"`
+	// SigningKey signs temporary download URLs of the local and memory disks (HMAC-SHA256);
+	// at least 32 characters. Leave empty to generate one per process, which breaks links
+	// across restarts and between replicas.
+	SigningKey string `mapstructure:"signing_key"`
+	// BaseURL is the public URL of the /files download route, e.g. "https://api.example.com/files"
+	BaseURL string `mapstructure:"base_url"`
+	// URLTTL is how long temporary download URLs handed to clients stay valid (default: 15m)
+	URLTTL time.Duration `mapstructure:"url_ttl"`
+
+	Local LocalConfig `mapstructure:"local"`
+	S3    S3Config    `mapstructure:"s3"`
+
+	// Avatars limits user avatar uploads
+	Avatars UploadRules `mapstructure:"avatars"`
+}
+
+// LocalConfig configures the local-disk driver.
+type LocalConfig struct {
+	// Root is the directory files are stored under
+	Root string `mapstructure:"root"`
+}
+
+// S3Config configures the S3-compatible driver (AWS S3, MinIO, R2, ...).
+type S3Config struct {
+	// Endpoint is the service URL, e.g. "https://s3.amazonaws.com" or "http://minio:9000"
+	Endpoint        string `mapstructure:"endpoint"`
+	Region          string `mapstructure:"region"`
+	Bucket          string `mapstructure:"bucket"`
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	// UsePathStyle addresses the bucket as "<endpoint>/<bucket>" instead of "<bucket>.<endpoint host>";
+	// most self-hosted services need it.
+	UsePathStyle bool `mapstructure:"use_path_style"`
+	// PublicURL is the base of permanent URLs, e.g. a CDN in front of the bucket.
+	// Defaults to the bucket URL.
+	PublicURL string `mapstructure:"public_url"`
+	// Timeout bounds each request to the service (default: 30s)
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// UploadRules limits the files accepted by an upload endpoint.
+type UploadRules struct {
+	// MaxSize is the largest accepted file in bytes
+	MaxSize int64 `mapstructure:"max_size"`
+	// AllowedTypes lists the accepted content types, detected from the file content
+	AllowedTypes []string `mapstructure:"allowed_types"`
+}
+
+// SetDefault registers Viper defaults for the storage config block.
+// Called from config.setDefault() during application startup.
+func SetDefault() {
+	viper.SetDefault("storage.driver", "local")
+	viper.SetDefault("storage.base_url", "http://localhost:8080/files")
+	viper.SetDefault("storage.url_ttl", "15m")
+	viper.SetDefault("storage.local.root", "./storage")
+	viper.SetDefault("storage.s3.region", "us-east-1")
+	viper.SetDefault("storage.s3.timeout", "30s")
+	viper.SetDefault("storage.avatars.max_size", 2<<20) // 2 MiB
+	viper.SetDefault("storage.avatars.allowed_types", []string{"image/jpeg", "image/png", "image/gif", "image/webp"})
+}
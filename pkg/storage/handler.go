@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+)
+
+// DownloadHandler serves the files behind temporary URLs issued by signer. Mount it on
+// the wildcard route matching the configured base URL, e.g. GET "/files/*".
+func DownloadHandler(disk Disk, signer *URLSigner) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		p, err := cleanPath(c.Param("*"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "file not found")
+		}
+
+		if err := signer.Verify(p, c.QueryParam("expires"), c.QueryParam("signature")); err != nil {
+			if errors.Is(err, ErrURLExpired) {
+				return echo.NewHTTPError(http.StatusForbidden, "download link expired")
+			}
+			return echo.NewHTTPError(http.StatusForbidden, "invalid download link")
+		}
+
+		body, obj, err := disk.Stream(c.Request().Context(), p)
+		if errors.Is(err, ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "file not found")
+		}
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentType, obj.ContentType)
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Cache-Control", "private, no-store")
+		if obj.Size > 0 {
+			header.Set(echo.HeaderContentLength, strconv.FormatInt(obj.Size, 10))
+		}
+		c.Response().WriteHeader(http.StatusOK)
+		_, err = io.Copy(c.Response(), body)
+		return err
+	}
+}
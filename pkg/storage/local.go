@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalDisk stores files in a directory of the local filesystem.
+type LocalDisk struct {
+	root   string
+	signer *URLSigner
+}
+
+// NewLocalDisk creates the root directory when it does not exist yet.
+func NewLocalDisk(cfg LocalConfig, signer *URLSigner) (*LocalDisk, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("storage: local root not configured")
+	}
+	if err := os.MkdirAll(cfg.Root, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: create local root: %w", err)
+	}
+	return &LocalDisk{root: cfg.Root, signer: signer}, nil
+}
+
+func (d *LocalDisk) Put(_ context.Context, path string, r io.Reader, _ PutOptions) error {
+	full, err := d.fullPath(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return fmt.Errorf("storage: create directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial file.
+	tmp, err := os.CreateTemp(filepath.Dir(full), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage: create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("storage: write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("storage: write file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("storage: write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), full); err != nil {
+		return fmt.Errorf("storage: write file: %w", err)
+	}
+	return nil
+}
+
+func (d *LocalDisk) Get(ctx context.Context, path string) ([]byte, error) {
+	body, _, err := d.Stream(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func (d *LocalDisk) Stream(_ context.Context, path string) (io.ReadCloser, Object, error) {
+	full, err := d.fullPath(path)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	f, err := os.Open(full)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, Object{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, Object{}, fmt.Errorf("storage: open file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, Object{}, fmt.Errorf("storage: stat file: %w", err)
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, Object{}, ErrNotFound
+	}
+
+	key, _ := cleanPath(path)
+	return f, Object{
+		Path:         key,
+		Size:         info.Size(),
+		ContentType:  contentTypeOf(key, PutOptions{}),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (d *LocalDisk) Delete(_ context.Context, path string) error {
+	full, err := d.fullPath(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(full); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("storage: delete file: %w", err)
+	}
+	return nil
+}
+
+func (d *LocalDisk) URL(path string) string {
+	key, _ := cleanPath(path)
+	return d.signer.URL(key)
+}
+
+func (d *LocalDisk) TemporaryURL(_ context.Context, path string, ttl time.Duration) (string, error) {
+	key, err := cleanPath(path)
+	if err != nil {
+		return "", err
+	}
+	return d.signer.Sign(key, ttl), nil
+}
+
+func (d *LocalDisk) fullPath(path string) (string, error) {
+	key, err := cleanPath(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d.root, filepath.FromSlash(key)), nil
+}
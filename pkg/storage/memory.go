@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// MemoryDisk keeps files in process memory. It is meant for tests.
+type MemoryDisk struct {
+	mu     sync.RWMutex
+	files  map[string]memoryFile
+	signer *URLSigner
+}
+
+type memoryFile struct {
+	data        []byte
+	contentType string
+	modified    time.Time
+}
+
+func NewMemoryDisk(signer *URLSigner) *MemoryDisk {
+	return &MemoryDisk{files: make(map[string]memoryFile), signer: signer}
+}
+
+func (d *MemoryDisk) Put(_ context.Context, path string, r io.Reader, opts PutOptions) error {
+	key, err := cleanPath(path)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.files[key] = memoryFile{data: data, contentType: contentTypeOf(key, opts), modified: time.Now()}
+	return nil
+}
+
+func (d *MemoryDisk) Get(_ context.Context, path string) ([]byte, error) {
+	file, _, err := d.file(path)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(file.data), nil
+}
+
+func (d *MemoryDisk) Stream(_ context.Context, path string) (io.ReadCloser, Object, error) {
+	file, key, err := d.file(path)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	return io.NopCloser(bytes.NewReader(file.data)), Object{
+		Path:         key,
+		Size:         int64(len(file.data)),
+		ContentType:  file.contentType,
+		LastModified: file.modified,
+	}, nil
+}
+
+func (d *MemoryDisk) Delete(_ context.Context, path string) error {
+	key, err := cleanPath(path)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.files, key)
+	return nil
+}
+
+func (d *MemoryDisk) URL(path string) string {
+	key, _ := cleanPath(path)
+	if d.signer == nil {
+		return "memory://" + key
+	}
+	return d.signer.URL(key)
+}
+
+func (d *MemoryDisk) TemporaryURL(_ context.Context, path string, ttl time.Duration) (string, error) {
+	key, err := cleanPath(path)
+	if err != nil {
+		return "", err
+	}
+	if d.signer == nil {
+		return "memory://" + key, nil
+	}
+	return d.signer.Sign(key, ttl), nil
+}
+
+// Files lists the stored paths.
+func (d *MemoryDisk) Files() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	paths := make([]string, 0, len(d.files))
+	for p := range d.files {
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+func (d *MemoryDisk) file(path string) (memoryFile, string, error) {
+	key, err := cleanPath(path)
+	if err != nil {
+		return memoryFile{}, "", err
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	file, ok := d.files[key]
+	if !ok {
+		return memoryFile{}, "", ErrNotFound
+	}
+	return file, key, nil
+}
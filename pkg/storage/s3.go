@@ -0,0 +1,303 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+)
+
+// S3Disk stores files in a bucket of an S3-compatible object store. Requests are
+// signed with AWS Signature Version 4.
+type S3Disk struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3Disk(cfg S3Config) (*S3Disk, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("storage: s3 endpoint and bucket must be configured")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("storage: s3 access_key_id and secret_access_key must be configured")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &S3Disk{
+		config:   cfg,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		now:      time.Now,
+	}, nil
+}
+
+func (d *S3Disk) Put(ctx context.Context, path string, r io.Reader, opts PutOptions) error {
+	key, err := cleanPath(path)
+	if err != nil {
+		return err
+	}
+	// The payload hash is part of the signature, so the body is read up front.
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("storage: read upload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, d.objectURL(key).String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", contentTypeOf(key, opts))
+
+	resp, err := d.do(req, body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (d *S3Disk) Get(ctx context.Context, path string) ([]byte, error) {
+	body, _, err := d.Stream(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func (d *S3Disk) Stream(ctx context.Context, path string) (io.ReadCloser, Object, error) {
+	key, err := cleanPath(path)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	resp, err := d.do(req, nil)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	obj := Object{
+		Path:        key,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		obj.LastModified = modified
+	}
+	return resp.Body, obj, nil
+}
+
+func (d *S3Disk) Delete(ctx context.Context, path string) error {
+	key, err := cleanPath(path)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, d.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.do(req, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (d *S3Disk) URL(path string) string {
+	key, _ := cleanPath(path)
+	if d.config.PublicURL != "" {
+		return strings.TrimSuffix(d.config.PublicURL, "/") + "/" + escapePath(key)
+	}
+	return d.objectURL(key).String()
+}
+
+// TemporaryURL returns a presigned GET URL.
+func (d *S3Disk) TemporaryURL(_ context.Context, path string, ttl time.Duration) (string, error) {
+	key, err := cleanPath(path)
+	if err != nil {
+		return "", err
+	}
+	u := d.objectURL(key)
+	now := d.now().UTC()
+
+	query := url.Values{
+		"X-Amz-Algorithm":     {s3Algorithm},
+		"X-Amz-Credential":    {d.config.AccessKeyID + "/" + d.scope(now)},
+		"X-Amz-Date":          {now.Format(s3TimeFormat)},
+		"X-Amz-Expires":       {strconv.Itoa(int(clampTTL(ttl).Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	headers := map[string]string{"host": u.Host}
+	signature := d.signature(http.MethodGet, u.EscapedPath(), canonicalQuery(query), headers, s3UnsignedPayload, now)
+	u.RawQuery = canonicalQuery(query) + "&X-Amz-Signature=" + signature
+	return u.String(), nil
+}
+
+// objectURL returns the URL of key in the bucket, path-style or virtual-hosted.
+func (d *S3Disk) objectURL(key string) *url.URL {
+	u := *d.endpoint
+	if d.config.UsePathStyle {
+		u.RawPath = strings.TrimSuffix(u.EscapedPath(), "/") + "/" + uriEncode(d.config.Bucket) + "/" + escapePath(key)
+		u.Path = strings.TrimSuffix(d.endpoint.Path, "/") + "/" + d.config.Bucket + "/" + key
+	} else {
+		u.Host = d.config.Bucket + "." + u.Host
+		u.RawPath = strings.TrimSuffix(u.EscapedPath(), "/") + "/" + escapePath(key)
+		u.Path = strings.TrimSuffix(d.endpoint.Path, "/") + "/" + key
+	}
+	return &u
+}
+
+// do signs and sends req, mapping error responses to errors. body is the request
+// payload, nil for requests without one.
+func (d *S3Disk) do(req *http.Request, body []byte) (*http.Response, error) {
+	d.sign(req, body)
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("storage: s3 %s: %w", req.Method, err)
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&s3Err)
+	if resp.StatusCode == http.StatusNotFound || s3Err.Code == "NoSuchKey" {
+		return nil, ErrNotFound
+	}
+	return nil, fmt.Errorf("storage: s3 %s %s: %s %s: %s",
+		req.Method, req.URL.Path, resp.Status, s3Err.Code, s3Err.Message)
+}
+
+// sign adds the Signature Version 4 Authorization header to req.
+func (d *S3Disk) sign(req *http.Request, body []byte) {
+	now := d.now().UTC()
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           now.Format(s3TimeFormat),
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		headers["content-type"] = ct
+	}
+
+	signature := d.signature(req.Method, req.URL.EscapedPath(), canonicalQuery(req.URL.Query()), headers, payloadHash, now)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, d.config.AccessKeyID, d.scope(now), signedHeaders(headers), signature))
+}
+
+// signature computes the Signature Version 4 signature of a request.
+func (d *S3Disk) signature(method, escapedPath, query string, headers map[string]string, payloadHash string, now time.Time) string {
+	names := sortedKeys(headers)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		escapedPath,
+		query,
+		canonicalHeaders.String(),
+		signedHeaders(headers),
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		d.scope(now),
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+d.config.SecretAccessKey), now.Format(s3DateFormat))
+	key = hmacSHA256(key, d.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (d *S3Disk) scope(now time.Time) string {
+	return now.Format(s3DateFormat) + "/" + d.config.Region + "/s3/aws4_request"
+}
+
+// canonicalQuery encodes query sorted by key, as Signature Version 4 requires.
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for _, k := range sortedKeys(query) {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func signedHeaders(headers map[string]string) string {
+	return strings.Join(sortedKeys(headers), ";")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// URLSigner issues and checks the temporary download URLs of disks that cannot sign
+// URLs themselves. A URL is "<base url>/<path>?expires=<unix>&signature=<hmac>" and is
+// served by DownloadHandler.
+type URLSigner struct {
+	baseURL string
+	key     []byte
+	now     func() time.Time
+}
+
+// NewURLSigner fails when key is shorter than 32 characters.
+func NewURLSigner(baseURL, key string) (*URLSigner, error) {
+	if len(key) < 32 {
+		return nil, errors.New("storage signing_key must be at least 32 characters")
+	}
+	return &URLSigner{baseURL: strings.TrimSuffix(baseURL, "/"), key: []byte(key), now: time.Now}, nil
+}
+
+// URL returns the unsigned URL of path.
+func (s *URLSigner) URL(path string) string {
+	return s.baseURL + "/" + escapePath(path)
+}
+
+// Sign returns the URL of path, valid for ttl.
+func (s *URLSigner) Sign(path string, ttl time.Duration) string {
+	expires := strconv.FormatInt(s.now().Add(clampTTL(ttl)).Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"signature": {s.signature(path, expires)},
+	}
+	return s.URL(path) + "?" + query.Encode()
+}
+
+// Verify checks the expires and signature query parameters of a URL for path.
+func (s *URLSigner) Verify(path, expires, signature string) error {
+	expected := s.signature(path, expires)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrInvalidSignature
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if s.now().Unix() > expiresAt {
+		return ErrURLExpired
+	}
+	return nil
+}
+
+func (s *URLSigner) signature(path, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(path + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,139 @@
+// Package storage stores files on a configurable disk: the local filesystem, an
+// S3-compatible object store, or process memory for tests.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when no file exists at the path.
+	ErrNotFound = errors.New("storage: file not found")
+	// ErrInvalidPath is returned for empty paths and paths escaping the disk root.
+	ErrInvalidPath = errors.New("storage: invalid path")
+	// ErrInvalidSignature is returned for a tampered or malformed temporary URL.
+	ErrInvalidSignature = errors.New("storage: invalid url signature")
+	// ErrURLExpired is returned for a well-formed temporary URL past its expiry.
+	ErrURLExpired = errors.New("storage: url expired")
+)
+
+// maxTemporaryURLTTL is the longest lifetime S3 accepts for presigned URLs; the other
+// disks apply it too so all drivers behave alike.
+const maxTemporaryURLTTL = 7 * 24 * time.Hour
+
+// Disk stores files under slash-separated paths such as "avatars/42/a1b2.png".
+type Disk interface {
+	// Put stores the content of r at path, replacing any existing file.
+	Put(ctx context.Context, path string, r io.Reader, opts PutOptions) error
+	// Get reads the whole file at path.
+	Get(ctx context.Context, path string) ([]byte, error)
+	// Stream opens the file at path for reading; the caller closes the reader.
+	Stream(ctx context.Context, path string) (io.ReadCloser, Object, error)
+	// Delete removes the file at path. Deleting a missing file is not an error.
+	Delete(ctx context.Context, path string) error
+	// URL returns the permanent URL of the file at path. It is only reachable when the
+	// disk is served publicly, e.g. a public bucket or a CDN.
+	URL(path string) string
+	// TemporaryURL returns a signed URL granting read access to the file at path for ttl.
+	TemporaryURL(ctx context.Context, path string, ttl time.Duration) (string, error)
+}
+
+// PutOptions describes a stored file.
+type PutOptions struct {
+	// ContentType is served with the file; detected from the path extension when empty
+	ContentType string
+}
+
+// Object describes a stored file.
+type Object struct {
+	Path         string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// New returns the disk selected by cfg.Driver. signer signs the temporary URLs of the
+// local and memory disks; it is required for local and ignored by s3.
+func New(cfg Config, signer *URLSigner) (Disk, error) {
+	switch cfg.Driver {
+	case "local", "":
+		if signer == nil {
+			return nil, errors.New("storage: local disk needs a url signer")
+		}
+		return NewLocalDisk(cfg.Local, signer)
+	case "memory":
+		return NewMemoryDisk(signer), nil
+	case "s3":
+		return NewS3Disk(cfg.S3)
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}
+
+// cleanPath normalizes p to a relative slash-separated path, rejecting paths that are
+// empty or leave the disk root.
+func cleanPath(p string) (string, error) {
+	p = strings.TrimPrefix(strings.ReplaceAll(p, "\\", "/"), "/")
+	if p == "" {
+		return "", ErrInvalidPath
+	}
+	cleaned := path.Clean(p)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidPath
+	}
+	return cleaned, nil
+}
+
+// contentTypeOf returns opts.ContentType, or the type registered for the extension of p.
+func contentTypeOf(p string, opts PutOptions) string {
+	if opts.ContentType != "" {
+		return opts.ContentType
+	}
+	if ct := mime.TypeByExtension(path.Ext(p)); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// clampTTL bounds the lifetime of a temporary URL to (0, maxTemporaryURLTTL].
+func clampTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > maxTemporaryURLTTL {
+		return maxTemporaryURLTTL
+	}
+	return ttl
+}
+
+// escapePath percent-encodes each segment of p for use in a URL path.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = uriEncode(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// uriEncode percent-encodes everything but the RFC 3986 unreserved characters, the
+// encoding AWS Signature Version 4 requires.
+func uriEncode(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSigningKey = "0123456789abcdef0123456789abcdef"
+
+func newTestSigner(t *testing.T) *URLSigner {
+	t.Helper()
+	signer, err := NewURLSigner("https://api.example.com/files/", testSigningKey)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestDisks_RoundTrip(t *testing.T) {
+	signer := newTestSigner(t)
+	local, err := NewLocalDisk(LocalConfig{Root: t.TempDir()}, signer)
+	require.NoError(t, err)
+
+	disks := map[string]Disk{
+		"local":  local,
+		"memory": NewMemoryDisk(signer),
+	}
+	for name, disk := range disks {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			require.NoError(t, disk.Put(ctx, "/exports/audit/log.csv", strings.NewReader("a,b"), PutOptions{}))
+
+			data, err := disk.Get(ctx, "exports/audit/log.csv")
+			require.NoError(t, err)
+			assert.Equal(t, "a,b", string(data))
+
+			body, obj, err := disk.Stream(ctx, "exports/audit/log.csv")
+			require.NoError(t, err)
+			body.Close()
+			assert.Equal(t, int64(3), obj.Size)
+			assert.Contains(t, obj.ContentType, "text/csv")
+
+			require.NoError(t, disk.Put(ctx, "exports/audit/log.csv", strings.NewReader("c"), PutOptions{}))
+			data, err = disk.Get(ctx, "exports/audit/log.csv")
+			require.NoError(t, err)
+			assert.Equal(t, "c", string(data), "put replaces the file")
+
+			require.NoError(t, disk.Delete(ctx, "exports/audit/log.csv"))
+			_, err = disk.Get(ctx, "exports/audit/log.csv")
+			assert.ErrorIs(t, err, ErrNotFound)
+			assert.NoError(t, disk.Delete(ctx, "exports/audit/log.csv"), "deleting a missing file is not an error")
+
+			assert.Equal(t, "https://api.example.com/files/exports/audit/log.csv", disk.URL("exports/audit/log.csv"))
+		})
+	}
+}
+
+func TestLocalDisk_RejectsPathsOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	disk, err := NewLocalDisk(LocalConfig{Root: filepath.Join(root, "files")}, newTestSigner(t))
+	require.NoError(t, err)
+
+	for _, p := range []string{"", "../secret.txt", "a/../../secret.txt", ".."} {
+		err := disk.Put(context.Background(), p, strings.NewReader("x"), PutOptions{})
+		assert.ErrorIs(t, err, ErrInvalidPath, "path %q", p)
+	}
+	_, err = os.Stat(filepath.Join(root, "secret.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestURLSigner_Verify(t *testing.T) {
+	signer := newTestSigner(t)
+	signed := signer.Sign("avatars/1/a.png", time.Minute)
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+	assert.NoError(t, signer.Verify("avatars/1/a.png", expires, signature))
+
+	assert.ErrorIs(t, signer.Verify("avatars/2/a.png", expires, signature), ErrInvalidSignature)
+	assert.ErrorIs(t, signer.Verify("avatars/1/a.png", "9999999999", signature), ErrInvalidSignature)
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	assert.ErrorIs(t, signer.Verify("avatars/1/a.png", expires, signature), ErrURLExpired)
+}
+
+func TestDownloadHandler(t *testing.T) {
+	ctx := context.Background()
+	signer := newTestSigner(t)
+	disk := NewMemoryDisk(signer)
+	require.NoError(t, disk.Put(ctx, "avatars/1/a.png", strings.NewReader("png"), PutOptions{ContentType: "image/png"}))
+
+	e := echo.New()
+	e.GET("/files/*", DownloadHandler(disk, signer))
+
+	get := func(target string) *httptest.ResponseRecorder {
+		u, err := url.Parse(target)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+		return rec
+	}
+
+	signed, err := disk.TemporaryURL(ctx, "avatars/1/a.png", time.Minute)
+	require.NoError(t, err)
+	rec := get(signed)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "png", rec.Body.String())
+
+	assert.Equal(t, http.StatusForbidden, get(disk.URL("avatars/1/a.png")).Code, "unsigned")
+	assert.Equal(t, http.StatusForbidden, get(strings.Replace(signed, "a.png", "b.png", 1)).Code, "signed for another file")
+
+	missing, err := disk.TemporaryURL(ctx, "avatars/1/missing.png", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, get(missing).Code)
+}
+
+func TestValidateUpload(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+	rules := UploadRules{MaxSize: 64, AllowedTypes: []string{"image/png", "image/jpeg"}}
+
+	upload, err := ValidateUpload(bytes.NewReader(png), rules)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", upload.ContentType)
+	assert.Equal(t, ".png", upload.Extension)
+	assert.Equal(t, int64(len(png)), upload.Size)
+
+	_, err = ValidateUpload(strings.NewReader("<html><script>alert(1)</script></html>"), rules)
+	assert.ErrorIs(t, err, ErrUnsupportedType, "content type is detected, not trusted")
+
+	_, err = ValidateUpload(bytes.NewReader(append(png, bytes.Repeat([]byte{0}, 64)...)), rules)
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+
+	_, err = ValidateUpload(strings.NewReader(""), rules)
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestNew_SelectsDriver(t *testing.T) {
+	signer := newTestSigner(t)
+
+	disk, err := New(Config{Driver: "memory"}, signer)
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryDisk{}, disk)
+
+	disk, err = New(Config{Driver: "local", Local: LocalConfig{Root: t.TempDir()}}, signer)
+	require.NoError(t, err)
+	assert.IsType(t, &LocalDisk{}, disk)
+
+	_, err = New(Config{Driver: "ftp"}, signer)
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrFileTooLarge is returned for uploads above UploadRules.MaxSize.
+	ErrFileTooLarge = errors.New("storage: file too large")
+	// ErrUnsupportedType is returned for uploads whose content is not an allowed type.
+	ErrUnsupportedType = errors.New("storage: unsupported file type")
+)
+
+// extensions names the files stored for the content types accepted by upload endpoints.
+var extensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// Upload is a validated uploaded file.
+type Upload struct {
+	// ContentType is detected from the file content; the type claimed by the client is ignored
+	ContentType string
+	// Extension matches ContentType, e.g. ".png"
+	Extension string
+	Size      int64
+	Data      []byte
+}
+
+// ReadUpload reads an uploaded multipart file and checks it against rules.
+func ReadUpload(fh *multipart.FileHeader, rules UploadRules) (*Upload, error) {
+	if rules.MaxSize > 0 && fh.Size > rules.MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", ErrFileTooLarge, fh.Size, rules.MaxSize)
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, fmt.Errorf("storage: open upload: %w", err)
+	}
+	defer f.Close()
+	return ValidateUpload(f, rules)
+}
+
+// ValidateUpload reads r, never more than rules.MaxSize bytes, and checks its size and
+// detected content type against rules.
+func ValidateUpload(r io.Reader, rules UploadRules) (*Upload, error) {
+	if rules.MaxSize > 0 {
+		r = io.LimitReader(r, rules.MaxSize+1)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, fmt.Errorf("storage: read upload: %w", err)
+	}
+	data := buf.Bytes()
+	if rules.MaxSize > 0 && int64(len(data)) > rules.MaxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrFileTooLarge, rules.MaxSize)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty file", ErrUnsupportedType)
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	if len(rules.AllowedTypes) > 0 && !slices.Contains(rules.AllowedTypes, contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+	return &Upload{
+		ContentType: contentType,
+		Extension:   extensions[contentType],
+		Size:        int64(len(data)),
+		Data:        data,
+	}, nil
+}
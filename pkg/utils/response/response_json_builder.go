@@ -52,6 +52,15 @@ func Created(ctx *echo.Context, data interface{}) error {
 	return Base(ctx, http.StatusCreated, http.StatusText(http.StatusCreated), data, http.StatusCreated, nil)
 }
 
+// Accepted answers a request whose work continues in the background
+func Accepted(ctx *echo.Context, data interface{}) error {
+	if data == nil {
+		panic(errors.New("accepted response : data on body is mandatory"))
+	}
+
+	return Base(ctx, http.StatusAccepted, http.StatusText(http.StatusAccepted), data, http.StatusAccepted, nil)
+}
+
 func Success(ctx *echo.Context, data interface{}) error {
 	if data == nil {
 		panic(errors.New("success response : data on body is mandatory"))